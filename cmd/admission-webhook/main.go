@@ -23,18 +23,27 @@ import (
 
 	"github.com/openshift/generic-admission-server/pkg/cmd"
 	"github.com/pingcap/tidb-operator/pkg/features"
+	"github.com/pingcap/tidb-operator/pkg/util/crypto"
 	"github.com/pingcap/tidb-operator/pkg/version"
 	"github.com/pingcap/tidb-operator/pkg/webhook/pod"
 	"github.com/pingcap/tidb-operator/pkg/webhook/statefulset"
 	"github.com/pingcap/tidb-operator/pkg/webhook/strategy"
+	"github.com/pingcap/tidb-operator/pkg/webhook/util"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/component-base/logs"
 	"k8s.io/klog"
+	aggregatorclientset "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
 )
 
 var (
 	printVersion         bool
 	extraServiceAccounts string
 	minResyncDuration    time.Duration
+	servingCertSecret    string
+	apiServiceName       string
+	evictionConfig       pod.EvictionConfig
 )
 
 func init() {
@@ -43,6 +52,10 @@ func init() {
 	flag.BoolVar(&printVersion, "version", false, "Show version and quit")
 	flag.StringVar(&extraServiceAccounts, "extraServiceAccounts", "", "comma-separated, extra Service Accounts the Webhook should control. The full pattern for each common service account is system:serviceaccount:<namespace>:<serviceaccount-name>")
 	flag.DurationVar(&minResyncDuration, "min-resync-duration", 12*time.Hour, "The resync period in reflectors will be random between MinResyncPeriod and 2*MinResyncPeriod.")
+	flag.StringVar(&servingCertSecret, "serving-cert-secret", "", "The name of the TLS Secret in the same namespace which provides the serving certificate, the Secret is watched and the caBundle of the APIService is updated when the `ca.crt` in it changes. The Secret should be mounted as --tls-cert-file and --tls-private-key-file, which are reloaded without restarting")
+	flag.StringVar(&apiServiceName, "apiservice-name", "v1alpha1.admission.tidb.pingcap.com", "The name of the APIService of the admission webhooks, used with --serving-cert-secret")
+	flag.BoolVar(&evictionConfig.FailOpen, "eviction-fail-open", true, "Admit the evictions of the PD and TiKV pods when PD is unreachable")
+	flag.IntVar(&evictionConfig.TiKVLeaderCountThreshold, "eviction-tikv-leader-count-threshold", 1, "The eviction of a TiKV pod is refused until its region leader count drops below the threshold or the evict leader timeout of the TidbCluster passes")
 	features.DefaultFeatureGate.AddFlag(flag.CommandLine)
 }
 
@@ -72,9 +85,40 @@ func main() {
 	}
 	pod.AstsControllerServiceAccounts = fmt.Sprintf("system:serviceaccount:%s:advanced-statefulset-controller", ns)
 
-	podAdmissionHook := pod.NewPodAdmissionControl(strings.Split(extraServiceAccounts, ","), resyncDuration)
+	if len(servingCertSecret) > 0 {
+		watchServingCertSecret(ns, resyncDuration)
+	}
+
+	podAdmissionHook := pod.NewPodAdmissionControl(strings.Split(extraServiceAccounts, ","), resyncDuration, evictionConfig)
 	statefulSetAdmissionHook := statefulset.NewStatefulSetAdmissionControl()
 	strategyAdmissionHook := strategy.NewStrategyAdmissionHook(&strategy.Registry)
 
 	cmd.RunAdmissionServer(podAdmissionHook, statefulSetAdmissionHook, strategyAdmissionHook)
 }
+
+// watchServingCertSecret keeps the caBundle of the APIService in sync with the
+// CA in the serving certificate Secret
+func watchServingCertSecret(ns string, resyncDuration time.Duration) {
+	cfg, err := rest.InClusterConfig()
+	if err != nil {
+		klog.Fatalf("failed to get config: %v", err)
+	}
+	kubeCli, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("failed to get kubernetes Clientset: %v", err)
+	}
+	aggregatorCli, err := aggregatorclientset.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("failed to get aggregator Clientset: %v", err)
+	}
+
+	certWatcher := crypto.NewSecretCertWatcher(kubeCli, ns, servingCertSecret, resyncDuration)
+	certWatcher.AddCAChangeHandler(func(ca []byte) {
+		if err := util.UpdateAPIServiceCABundle(aggregatorCli, apiServiceName, ca); err != nil {
+			klog.Errorf("failed to update caBundle of APIService %s: %v", apiServiceName, err)
+		}
+	})
+	if err := certWatcher.Run(wait.NeverStop); err != nil {
+		klog.Fatalf("failed to watch serving certificate secret %s/%s: %v", ns, servingCertSecret, err)
+	}
+}
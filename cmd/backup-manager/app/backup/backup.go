@@ -22,27 +22,31 @@ import (
 	"os/exec"
 	"path"
 	"strings"
+	"time"
 
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/constants"
 	backupUtil "github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/util"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog"
 )
 
+// maxBROutputLineSize is the max size of a line of the BR output, a longer line stops the parsing of the output
+const maxBROutputLineSize = 1024 * 1024
+
 // Options contains the input arguments to the backup command
 type Options struct {
 	backupUtil.GenericOptions
 }
 
-// backupData generates br args and runs br binary to do the real backup work
-func (bo *Options) backupData(ctx context.Context, backup *v1alpha1.Backup) error {
-	clusterNamespace := backup.Spec.BR.ClusterNamespace
-	if backup.Spec.BR.ClusterNamespace == "" {
-		clusterNamespace = backup.Namespace
-	}
+// backupData generates br args and runs br binary to do the real backup work,
+// the progress of br is updated to the status of the backup by statusUpdater
+func (bo *Options) backupData(ctx context.Context, backup *v1alpha1.Backup, statusUpdater controller.BackupConditionUpdaterInterface) error {
 	args := make([]string, 0)
-	args = append(args, fmt.Sprintf("--pd=%s-pd.%s:2379", backup.Spec.BR.Cluster, clusterNamespace))
+	args = append(args, fmt.Sprintf("--pd=%s", backupUtil.GetPDAddress(bo.PDAddress, backup.Spec.BR, backup.Namespace)))
 	if bo.TLSCluster {
 		args = append(args, fmt.Sprintf("--ca=%s", path.Join(util.ClusterClientTLSPath, corev1.ServiceAccountRootCAKey)))
 		args = append(args, fmt.Sprintf("--cert=%s", path.Join(util.ClusterClientTLSPath, corev1.TLSCertKey)))
@@ -83,17 +87,28 @@ func (bo *Options) backupData(ctx context.Context, backup *v1alpha1.Backup) erro
 		return fmt.Errorf("cluster %s, execute br command failed, args: %s, err: %v", bo, fullArgs, err)
 	}
 	var errMsg string
-	reader := bufio.NewReader(stdOut)
-	for {
-		line, err := reader.ReadString('\n')
+	limiter := backupUtil.NewRateLimiter(constants.ProgressUpdateInterval)
+	scanner := bufio.NewScanner(stdOut)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxBROutputLineSize)
+	scanner.Split(backupUtil.ScanBROutputLines)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
 		if strings.Contains(line, "[ERROR]") {
-			errMsg += line
+			errMsg += line + "\n"
 		}
-
-		klog.Info(strings.Replace(line, "\n", "", -1))
-		if err != nil || io.EOF == err {
-			break
+		if step, progress, ok := backupUtil.ParseBRProgress(line); ok {
+			bo.updateProgress(backup, statusUpdater, limiter, step, progress)
 		}
+
+		klog.Info(line)
+	}
+	if err := scanner.Err(); err != nil {
+		klog.Errorf("cluster %s, read br output failed, err: %v", bo, err)
+		// keep draining the output so that br is not blocked on a full pipe
+		io.Copy(ioutil.Discard, stdOut)
 	}
 	tmpErr, _ := ioutil.ReadAll(stdErr)
 	if len(tmpErr) > 0 {
@@ -109,6 +124,27 @@ func (bo *Options) backupData(ctx context.Context, backup *v1alpha1.Backup) erro
 	return nil
 }
 
+// updateProgress updates the progress of the step to the status of the backup, the status is updated
+// at most once per ProgressUpdateInterval except for the completion of the step
+func (bo *Options) updateProgress(backup *v1alpha1.Backup, statusUpdater controller.BackupConditionUpdaterInterface, limiter *backupUtil.RateLimiter, step string, progress float64) {
+	if progress < 100 && !limiter.Allow(time.Now()) {
+		return
+	}
+	updateTime := metav1.Now()
+	err := statusUpdater.Update(backup, &v1alpha1.BackupCondition{
+		Type:   v1alpha1.BackupRunning,
+		Status: corev1.ConditionTrue,
+	}, &controller.BackupUpdateStatus{
+		ProgressStep:       &step,
+		Progress:           &progress,
+		ProgressUpdateTime: &updateTime,
+	})
+	if err != nil {
+		// the progress is informative only, so the backup goes on
+		klog.Warningf("cluster %s, update progress %.2f%% of step %s failed, err: %v", bo, progress, step, err)
+	}
+}
+
 // constructOptions constructs options for BR
 func constructOptions(backup *v1alpha1.Backup) ([]string, error) {
 	args, err := backupUtil.ConstructBRGlobalOptionsForBackup(backup)
@@ -19,12 +19,16 @@ import (
 	"io"
 	"os/exec"
 	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog"
 
 	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/constants"
 	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
 )
 
 // Options contains the input arguments to the backup command
@@ -38,7 +42,10 @@ func (bo *Options) String() string {
 }
 
 // cleanBRRemoteBackupData clean the backup data from remote
-func (bo *Options) cleanBRRemoteBackupData(ctx context.Context, backup *v1alpha1.Backup) error {
+//
+// The deleted objects are not listed again, so a re-run clean job resumes from the remaining objects,
+// and the count of the deleted objects continues from the progress recorded in the status.
+func (bo *Options) cleanBRRemoteBackupData(ctx context.Context, backup *v1alpha1.Backup, statusUpdater controller.BackupConditionUpdaterInterface) error {
 	s, err := util.NewStorageBackend(backup.Spec.StorageProvider)
 	if err != nil {
 		return err
@@ -49,6 +56,8 @@ func (bo *Options) cleanBRRemoteBackupData(ctx context.Context, backup *v1alpha1
 
 	klog.Infof("cleanning cluster %s backup data with opt: %+v", bo, opt)
 
+	deleted := cleanDeletedObjects(backup)
+	limiter := util.NewRateLimiter(constants.ProgressUpdateInterval)
 	iter := s.ListPage(nil)
 	for {
 		// list one page of object
@@ -65,6 +74,8 @@ func (bo *Options) cleanBRRemoteBackupData(ctx context.Context, backup *v1alpha1
 
 		if len(result.Deleted) != 0 {
 			klog.Infof("delete %d objects for cluster %s successfully: %s", len(result.Deleted), bo, strings.Join(result.Deleted, ","))
+			deleted += int64(len(result.Deleted))
+			bo.updateCleanProgress(backup, statusUpdater, limiter, 0, deleted)
 		}
 		if len(result.Errors) != 0 {
 			for _, oerr := range result.Errors {
@@ -74,9 +85,43 @@ func (bo *Options) cleanBRRemoteBackupData(ctx context.Context, backup *v1alpha1
 		}
 	}
 
+	bo.updateCleanProgress(backup, statusUpdater, limiter, 100, deleted)
 	return nil
 }
 
+// cleanDeletedObjects returns the count of the objects deleted by the previous clean jobs
+func cleanDeletedObjects(backup *v1alpha1.Backup) int64 {
+	for _, p := range backup.Status.Progresses {
+		if p.Step == v1alpha1.BackupCleanProgressStep {
+			return p.DeletedObjects
+		}
+	}
+	return 0
+}
+
+// updateCleanProgress updates the count of the deleted objects to the status of the backup, the status
+// is updated at most once per ProgressUpdateInterval except for the completion of the clean
+func (bo *Options) updateCleanProgress(backup *v1alpha1.Backup, statusUpdater controller.BackupConditionUpdaterInterface, limiter *util.RateLimiter, progress float64, deleted int64) {
+	if progress < 100 && !limiter.Allow(time.Now()) {
+		return
+	}
+	step := v1alpha1.BackupCleanProgressStep
+	updateTime := metav1.Now()
+	err := statusUpdater.Update(backup, &v1alpha1.BackupCondition{
+		Type:   v1alpha1.BackupClean,
+		Status: corev1.ConditionFalse,
+	}, &controller.BackupUpdateStatus{
+		ProgressStep:       &step,
+		Progress:           &progress,
+		DeletedObjects:     &deleted,
+		ProgressUpdateTime: &updateTime,
+	})
+	if err != nil {
+		// the progress is informative only, so the clean goes on
+		klog.Warningf("cluster %s, update clean progress with %d deleted objects failed, err: %v", bo, deleted, err)
+	}
+}
+
 func (bo *Options) cleanRemoteBackupData(ctx context.Context, bucket string, opts []string) error {
 	destBucket := util.NormalizeBucketURI(bucket)
 	args := util.ConstructRcloneArgs(constants.RcloneConfigArg, opts, "delete", destBucket, "", true)
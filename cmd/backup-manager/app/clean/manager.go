@@ -72,7 +72,7 @@ func (bm *Manager) performCleanBackup(ctx context.Context, backup *v1alpha1.Back
 	var errs []error
 	var err error
 	if backup.Spec.BR != nil {
-		err = bm.cleanBRRemoteBackupData(ctx, backup)
+		err = bm.cleanBRRemoteBackupData(ctx, backup, bm.StatusUpdater)
 	} else {
 		opts := util.GetOptions(backup.Spec.StorageProvider)
 		err = bm.cleanRemoteBackupData(ctx, backup.Status.BackupPath, opts)
@@ -32,6 +32,7 @@ func NewBackupMgrCommand() *cobra.Command {
 	cmds.AddCommand(NewBackupCommand())
 	cmds.AddCommand(NewExportCommand())
 	cmds.AddCommand(NewRestoreCommand())
+	cmds.AddCommand(NewVerifyCommand())
 	cmds.AddCommand(NewImportCommand())
 	cmds.AddCommand(NewCleanCommand())
 	return cmds
@@ -25,6 +25,9 @@ const (
 	// CheckTimeout is the maximum time to wait for the tidb cluster ready
 	CheckTimeout = 30 * time.Minute
 
+	// ProgressUpdateInterval is the minimum interval to update the progress of BR to the status
+	ProgressUpdateInterval = 30 * time.Second
+
 	// BackupRootPath is the root path to backup data
 	BackupRootPath = "/backup"
 
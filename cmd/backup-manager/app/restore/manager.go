@@ -98,9 +98,20 @@ func (rm *Manager) ProcessRestore() error {
 
 	rm.setOptions(restore)
 
+	db, err := rm.connectDB(ctx, restore)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return rm.performRestore(ctx, restore.DeepCopy(), db)
+}
+
+// connectDB connects to the tidb cluster of the restore, and sets the restore Failed
+// if it can't connect to the tidb cluster before timeout.
+func (rm *Manager) connectDB(ctx context.Context, restore *v1alpha1.Restore) (*sql.DB, error) {
 	var db *sql.DB
 	var dsn string
-	err = wait.PollImmediate(constants.PollInterval, constants.CheckTimeout, func() (done bool, err error) {
+	err := wait.PollImmediate(constants.PollInterval, constants.CheckTimeout, func() (done bool, err error) {
 		dsn, err = rm.GetDSN(rm.TLSClient)
 		if err != nil {
 			klog.Errorf("can't get dsn of tidb cluster %s, err: %s", rm, err)
@@ -119,6 +130,7 @@ func (rm *Manager) ProcessRestore() error {
 	})
 
 	if err != nil {
+		var errs []error
 		errs = append(errs, err)
 		klog.Errorf("cluster %s connect failed, err: %s", rm, err)
 		uerr := rm.StatusUpdater.Update(restore, &v1alpha1.RestoreCondition{
@@ -128,11 +140,9 @@ func (rm *Manager) ProcessRestore() error {
 			Message: err.Error(),
 		}, nil)
 		errs = append(errs, uerr)
-		return errorutils.NewAggregate(errs)
+		return nil, errorutils.NewAggregate(errs)
 	}
-
-	defer db.Close()
-	return rm.performRestore(ctx, restore.DeepCopy(), db)
+	return db, nil
 }
 
 func (rm *Manager) performRestore(ctx context.Context, restore *v1alpha1.Restore, db *sql.DB) error {
@@ -288,8 +298,19 @@ func (rm *Manager) performRestore(ctx context.Context, restore *v1alpha1.Restore
 	}
 	klog.Infof("restore cluster %s from %s succeed", rm, restore.Spec.Type)
 
-	finish := time.Now()
 	ts := strconv.FormatUint(commitTs, 10)
+	if restore.Spec.Verify != "" {
+		// the restore is complete after the restored data is verified by the verify job
+		return rm.StatusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:   v1alpha1.RestoreVerifying,
+			Status: corev1.ConditionTrue,
+		}, &controller.RestoreUpdateStatus{
+			TimeStarted: &metav1.Time{Time: started},
+			CommitTs:    &ts,
+		})
+	}
+
+	finish := time.Now()
 	updateStatus := &controller.RestoreUpdateStatus{
 		TimeStarted:   &metav1.Time{Time: started},
 		TimeCompleted: &metav1.Time{Time: finish},
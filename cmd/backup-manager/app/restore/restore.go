@@ -35,12 +35,8 @@ type Options struct {
 }
 
 func (ro *Options) restoreData(ctx context.Context, restore *v1alpha1.Restore) error {
-	clusterNamespace := restore.Spec.BR.ClusterNamespace
-	if restore.Spec.BR.ClusterNamespace == "" {
-		clusterNamespace = restore.Namespace
-	}
 	args := make([]string, 0)
-	args = append(args, fmt.Sprintf("--pd=%s-pd.%s:2379", restore.Spec.BR.Cluster, clusterNamespace))
+	args = append(args, fmt.Sprintf("--pd=%s", backupUtil.GetPDAddress(ro.PDAddress, restore.Spec.BR, restore.Namespace)))
 	if ro.TLSCluster {
 		args = append(args, fmt.Sprintf("--ca=%s", path.Join(util.ClusterClientTLSPath, corev1.ServiceAccountRootCAKey)))
 		args = append(args, fmt.Sprintf("--cert=%s", path.Join(util.ClusterClientTLSPath, corev1.TLSCertKey)))
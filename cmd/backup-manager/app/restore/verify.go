@@ -0,0 +1,312 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	kvbackup "github.com/pingcap/kvproto/pkg/backup"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog"
+)
+
+// systemDBs are the databases which are not restored by BR
+var systemDBs = map[string]struct{}{
+	"mysql":              {},
+	"information_schema": {},
+	"performance_schema": {},
+	"metrics_schema":     {},
+}
+
+// tableChecksum is the checksum of a table
+type tableChecksum struct {
+	Crc64Xor   uint64
+	TotalKvs   uint64
+	TotalBytes uint64
+}
+
+// verifyTable is a restored table to verify and its checksum recorded in the backup
+type verifyTable struct {
+	DB    string
+	Table string
+	tableChecksum
+}
+
+func (t *verifyTable) String() string {
+	return fmt.Sprintf("%s.%s", t.DB, t.Table)
+}
+
+// schemaName is the name of a db or table in the backup meta
+type schemaName struct {
+	O string `json:"O"`
+}
+
+// ProcessVerify verifies the restored data of a restore and marks the restore Complete
+// if the verification passes, or Failed if not
+func (rm *Manager) ProcessVerify() error {
+	ctx, cancel := util.GetContextForTerminationSignals(rm.ResourceName)
+	defer cancel()
+
+	var errs []error
+	restore, err := rm.restoreLister.Restores(rm.Namespace).Get(rm.ResourceName)
+	if err != nil {
+		klog.Errorf("can't find cluster %s restore %s CRD object, err: %v", rm, rm.ResourceName, err)
+		return err
+	}
+	if restore.Spec.BR == nil || restore.Spec.To == nil {
+		return fmt.Errorf("no br or tidb config in %s", rm)
+	}
+	restore = restore.DeepCopy()
+
+	var maxTableSize int64
+	if restore.Spec.VerifyMaxTableSize != "" {
+		q, err := resource.ParseQuantity(restore.Spec.VerifyMaxTableSize)
+		if err != nil {
+			return fmt.Errorf("parse verifyMaxTableSize %s of %s failed, err: %v", restore.Spec.VerifyMaxTableSize, rm, err)
+		}
+		maxTableSize = q.Value()
+	}
+
+	backupMeta, err := util.GetBRMetaData(ctx, restore.Spec.StorageProvider)
+	if err != nil {
+		errs = append(errs, err)
+		klog.Errorf("get backup meta of cluster %s failed, err: %s", rm, err)
+		uerr := rm.StatusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "GetBackupMetaFailed",
+			Message: err.Error(),
+		}, nil)
+		errs = append(errs, uerr)
+		return errorutils.NewAggregate(errs)
+	}
+
+	tables, skipped, err := planVerifyTables(restore, backupMeta, maxTableSize)
+	if err != nil {
+		errs = append(errs, err)
+		klog.Errorf("parse backup meta of cluster %s failed, err: %s", rm, err)
+		uerr := rm.StatusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "ParseBackupMetaFailed",
+			Message: err.Error(),
+		}, nil)
+		errs = append(errs, uerr)
+		return errorutils.NewAggregate(errs)
+	}
+
+	rm.setOptions(restore)
+	db, err := rm.connectDB(ctx, restore)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	results := skipped
+	for i := range tables {
+		result, err := rm.verifyTable(ctx, db, restore, &tables[i])
+		if err != nil {
+			errs = append(errs, err)
+			klog.Errorf("verify table %s of cluster %s failed, err: %s", tables[i].String(), rm, err)
+			uerr := rm.StatusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+				Type:    v1alpha1.RestoreFailed,
+				Status:  corev1.ConditionTrue,
+				Reason:  "ChecksumTableFailed",
+				Message: err.Error(),
+			}, nil)
+			errs = append(errs, uerr)
+			return errorutils.NewAggregate(errs)
+		}
+		results = append(results, result)
+	}
+
+	verification := buildVerificationStatus(results)
+	klog.Infof("verify cluster %s finished, %d tables passed, %d tables failed, %d tables skipped",
+		rm, verification.PassedTables, verification.FailedTables, verification.SkippedTables)
+	if verification.FailedTables > 0 {
+		return rm.StatusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "VerificationFailed",
+			Message: fmt.Sprintf("%d tables failed the verification", verification.FailedTables),
+		}, &controller.RestoreUpdateStatus{
+			Verification: verification,
+		})
+	}
+
+	return rm.StatusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+		Type:   v1alpha1.RestoreComplete,
+		Status: corev1.ConditionTrue,
+	}, &controller.RestoreUpdateStatus{
+		TimeCompleted: &metav1.Time{Time: time.Now()},
+		Verification:  verification,
+	})
+}
+
+// planVerifyTables returns the restored tables to verify according to the backup meta, and
+// the results of the tables skipped because they are larger than maxTableSize.
+// maxTableSize <= 0 means no limit.
+func planVerifyTables(restore *v1alpha1.Restore, meta *kvbackup.BackupMeta, maxTableSize int64) ([]verifyTable, []v1alpha1.RestoreTableVerification, error) {
+	var tables []verifyTable
+	var skipped []v1alpha1.RestoreTableVerification
+	for _, schema := range meta.Schemas {
+		// the schema of an empty database has no table
+		if len(schema.Table) == 0 {
+			continue
+		}
+		db := &struct {
+			Name schemaName `json:"db_name"`
+		}{}
+		if err := json.Unmarshal(schema.Db, db); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal db info failed, err: %v", err)
+		}
+		table := &struct {
+			Name schemaName `json:"name"`
+		}{}
+		if err := json.Unmarshal(schema.Table, table); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal table info of db %s failed, err: %v", db.Name.O, err)
+		}
+
+		if _, ok := systemDBs[strings.ToLower(db.Name.O)]; ok {
+			continue
+		}
+		if !isTableRestored(restore, db.Name.O, table.Name.O) {
+			continue
+		}
+
+		t := verifyTable{
+			DB:    db.Name.O,
+			Table: table.Name.O,
+			tableChecksum: tableChecksum{
+				Crc64Xor:   schema.Crc64Xor,
+				TotalKvs:   schema.TotalKvs,
+				TotalBytes: schema.TotalBytes,
+			},
+		}
+		if maxTableSize > 0 && t.TotalBytes > uint64(maxTableSize) {
+			skipped = append(skipped, v1alpha1.RestoreTableVerification{
+				Table:   t.String(),
+				Result:  v1alpha1.RestoreTableVerificationSkipped,
+				Message: fmt.Sprintf("table size %d bytes exceeds verifyMaxTableSize %s", t.TotalBytes, restore.Spec.VerifyMaxTableSize),
+			})
+			continue
+		}
+		tables = append(tables, t)
+	}
+	return tables, skipped, nil
+}
+
+// isTableRestored returns whether the table is restored by the restore type of BR
+func isTableRestored(restore *v1alpha1.Restore, db, table string) bool {
+	switch restore.Spec.Type {
+	case v1alpha1.BackupTypeDB:
+		return strings.EqualFold(db, restore.Spec.BR.DB)
+	case v1alpha1.BackupTypeTable:
+		return strings.EqualFold(db, restore.Spec.BR.DB) && strings.EqualFold(table, restore.Spec.BR.Table)
+	}
+	return true
+}
+
+// verifyTable compares the checksum of the restored table with the checksum recorded in the backup
+func (rm *Manager) verifyTable(ctx context.Context, db *sql.DB, restore *v1alpha1.Restore, t *verifyTable) (v1alpha1.RestoreTableVerification, error) {
+	result := v1alpha1.RestoreTableVerification{Table: t.String()}
+
+	var count int
+	row := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = ? AND table_name = ?", t.DB, t.Table)
+	if err := row.Scan(&count); err != nil {
+		return result, fmt.Errorf("check the existence of table %s failed, err: %v", t.String(), err)
+	}
+	if count == 0 {
+		// the table in the backup may be filtered out by the table filter
+		if len(restore.Spec.TableFilter) > 0 {
+			result.Result = v1alpha1.RestoreTableVerificationSkipped
+			result.Message = "table not found, it may be filtered out by tableFilter"
+		} else {
+			result.Result = v1alpha1.RestoreTableVerificationFailed
+			result.Message = "table not found"
+		}
+		return result, nil
+	}
+
+	var dbName, tableName string
+	actual := tableChecksum{}
+	row = db.QueryRowContext(ctx, fmt.Sprintf("ADMIN CHECKSUM TABLE `%s`.`%s`", escapeName(t.DB), escapeName(t.Table))) // nolint: gosec
+	if err := row.Scan(&dbName, &tableName, &actual.Crc64Xor, &actual.TotalKvs, &actual.TotalBytes); err != nil {
+		return result, fmt.Errorf("checksum table %s failed, err: %v", t.String(), err)
+	}
+	result.Result, result.Message = compareChecksum(t.tableChecksum, actual)
+	return result, nil
+}
+
+// compareChecksum compares the checksum of the restored table with the expected checksum recorded in the backup
+func compareChecksum(expected, actual tableChecksum) (v1alpha1.RestoreTableVerificationResult, string) {
+	if expected == (tableChecksum{}) && actual != (tableChecksum{}) {
+		// the checksum is not recorded if the backup is taken with checksum disabled
+		return v1alpha1.RestoreTableVerificationSkipped, "no checksum in the backup"
+	}
+	if expected != actual {
+		return v1alpha1.RestoreTableVerificationFailed, fmt.Sprintf("checksum mismatch, expected crc64xor %d, kvs %d, bytes %d, got crc64xor %d, kvs %d, bytes %d",
+			expected.Crc64Xor, expected.TotalKvs, expected.TotalBytes, actual.Crc64Xor, actual.TotalKvs, actual.TotalBytes)
+	}
+	return v1alpha1.RestoreTableVerificationPassed, ""
+}
+
+// buildVerificationStatus builds the verification status from the results of the tables,
+// the failed tables come first and at most MaxRestoreVerificationTables tables are recorded.
+func buildVerificationStatus(results []v1alpha1.RestoreTableVerification) *v1alpha1.RestoreVerificationStatus {
+	status := &v1alpha1.RestoreVerificationStatus{Type: v1alpha1.RestoreVerifyChecksum}
+	order := map[v1alpha1.RestoreTableVerificationResult]int{
+		v1alpha1.RestoreTableVerificationFailed:  0,
+		v1alpha1.RestoreTableVerificationSkipped: 1,
+		v1alpha1.RestoreTableVerificationPassed:  2,
+	}
+	for _, r := range results {
+		switch r.Result {
+		case v1alpha1.RestoreTableVerificationPassed:
+			status.PassedTables++
+		case v1alpha1.RestoreTableVerificationFailed:
+			status.FailedTables++
+		case v1alpha1.RestoreTableVerificationSkipped:
+			status.SkippedTables++
+		}
+	}
+
+	tables := make([]v1alpha1.RestoreTableVerification, len(results))
+	copy(tables, results)
+	sort.SliceStable(tables, func(i, j int) bool {
+		return order[tables[i].Result] < order[tables[j].Result]
+	})
+	if len(tables) > v1alpha1.MaxRestoreVerificationTables {
+		tables = tables[:v1alpha1.MaxRestoreVerificationTables]
+	}
+	status.Tables = tables
+	return status
+}
+
+// escapeName escapes the backquotes in the name of a db or table
+func escapeName(name string) string {
+	return strings.ReplaceAll(name, "`", "``")
+}
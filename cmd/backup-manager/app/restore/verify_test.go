@@ -0,0 +1,159 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	kvbackup "github.com/pingcap/kvproto/pkg/backup"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+func newSchema(db, table string, totalBytes uint64) *kvbackup.Schema {
+	schema := &kvbackup.Schema{
+		Db:         []byte(fmt.Sprintf(`{"id":1,"db_name":{"O":"%s","L":"%s"}}`, db, db)),
+		Crc64Xor:   42,
+		TotalKvs:   10,
+		TotalBytes: totalBytes,
+	}
+	if table != "" {
+		schema.Table = []byte(fmt.Sprintf(`{"id":2,"name":{"O":"%s","L":"%s"}}`, table, table))
+	}
+	return schema
+}
+
+func TestPlanVerifyTables(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	meta := &kvbackup.BackupMeta{
+		Schemas: []*kvbackup.Schema{
+			newSchema("test", "t1", 1024),
+			newSchema("test", "t2", 200*1024*1024),
+			newSchema("test2", "t1", 1024),
+			newSchema("empty", "", 0),
+			newSchema("mysql", "user", 1024),
+		},
+	}
+
+	type testcase struct {
+		name          string
+		restoreType   v1alpha1.BackupType
+		maxTableSize  int64
+		expectTables  []string
+		expectSkipped []string
+	}
+	tests := []testcase{
+		{
+			name:         "full restore without size limit",
+			restoreType:  v1alpha1.BackupTypeFull,
+			expectTables: []string{"test.t1", "test.t2", "test2.t1"},
+		},
+		{
+			name:          "full restore skips the large tables",
+			restoreType:   v1alpha1.BackupTypeFull,
+			maxTableSize:  100 * 1024 * 1024,
+			expectTables:  []string{"test.t1", "test2.t1"},
+			expectSkipped: []string{"test.t2"},
+		},
+		{
+			name:         "db restore",
+			restoreType:  v1alpha1.BackupTypeDB,
+			expectTables: []string{"test.t1", "test.t2"},
+		},
+		{
+			name:         "table restore",
+			restoreType:  v1alpha1.BackupTypeTable,
+			maxTableSize: 100 * 1024 * 1024,
+			expectTables: []string{"test.t1"},
+		},
+	}
+	for _, test := range tests {
+		t.Log(test.name)
+		restore := &v1alpha1.Restore{
+			Spec: v1alpha1.RestoreSpec{
+				Type: test.restoreType,
+				BR: &v1alpha1.BRConfig{
+					DB:    "test",
+					Table: "t1",
+				},
+				VerifyMaxTableSize: "100Mi",
+			},
+		}
+		tables, skipped, err := planVerifyTables(restore, meta, test.maxTableSize)
+		g.Expect(err).To(Succeed())
+
+		var names []string
+		for i := range tables {
+			names = append(names, tables[i].String())
+			g.Expect(tables[i].tableChecksum).To(Equal(tableChecksum{Crc64Xor: 42, TotalKvs: 10, TotalBytes: tables[i].TotalBytes}))
+		}
+		g.Expect(names).To(Equal(test.expectTables))
+
+		var skippedNames []string
+		for _, s := range skipped {
+			skippedNames = append(skippedNames, s.Table)
+			g.Expect(s.Result).To(Equal(v1alpha1.RestoreTableVerificationSkipped))
+		}
+		g.Expect(skippedNames).To(Equal(test.expectSkipped))
+	}
+}
+
+func TestCompareChecksum(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	checksum := tableChecksum{Crc64Xor: 42, TotalKvs: 10, TotalBytes: 1024}
+	result, _ := compareChecksum(checksum, checksum)
+	g.Expect(result).To(Equal(v1alpha1.RestoreTableVerificationPassed))
+
+	result, msg := compareChecksum(checksum, tableChecksum{Crc64Xor: 43, TotalKvs: 10, TotalBytes: 1024})
+	g.Expect(result).To(Equal(v1alpha1.RestoreTableVerificationFailed))
+	g.Expect(msg).To(ContainSubstring("checksum mismatch"))
+
+	// the backup is taken with checksum disabled
+	result, _ = compareChecksum(tableChecksum{}, checksum)
+	g.Expect(result).To(Equal(v1alpha1.RestoreTableVerificationSkipped))
+
+	// empty table
+	result, _ = compareChecksum(tableChecksum{}, tableChecksum{})
+	g.Expect(result).To(Equal(v1alpha1.RestoreTableVerificationPassed))
+}
+
+func TestBuildVerificationStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var results []v1alpha1.RestoreTableVerification
+	for i := 0; i < v1alpha1.MaxRestoreVerificationTables; i++ {
+		results = append(results, v1alpha1.RestoreTableVerification{
+			Table:  fmt.Sprintf("test.t%d", i),
+			Result: v1alpha1.RestoreTableVerificationPassed,
+		})
+	}
+	results = append(results,
+		v1alpha1.RestoreTableVerification{Table: "test.skipped", Result: v1alpha1.RestoreTableVerificationSkipped},
+		v1alpha1.RestoreTableVerification{Table: "test.failed", Result: v1alpha1.RestoreTableVerificationFailed},
+	)
+
+	status := buildVerificationStatus(results)
+	g.Expect(status.Type).To(Equal(v1alpha1.RestoreVerifyChecksum))
+	g.Expect(status.PassedTables).To(Equal(int32(v1alpha1.MaxRestoreVerificationTables)))
+	g.Expect(status.FailedTables).To(Equal(int32(1)))
+	g.Expect(status.SkippedTables).To(Equal(int32(1)))
+	// the list is bounded and the failed tables come first
+	g.Expect(status.Tables).To(HaveLen(v1alpha1.MaxRestoreVerificationTables))
+	g.Expect(status.Tables[0].Table).To(Equal("test.failed"))
+	g.Expect(status.Tables[1].Table).To(Equal("test.skipped"))
+	g.Expect(status.Tables[2].Table).To(Equal("test.t0"))
+}
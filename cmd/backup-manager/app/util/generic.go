@@ -41,6 +41,7 @@ type GenericOptions struct {
 	Password     string
 	User         string
 	TiKVVersion  string
+	PDAddress    string
 }
 
 func (bo *GenericOptions) String() string {
@@ -0,0 +1,97 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// brLogProgressRegexp matches the progress logged by BR when the output is not a terminal, e.g.
+	// [2021/03/02 10:00:00.000 +08:00] [INFO] [progress.go:80] ["Full backup"] [progress=45.30%] [count="10 / 22"]
+	brLogProgressRegexp = regexp.MustCompile(`\[(?:"([^"]+)"|([^\]\s"]+))\] \[progress=([0-9]+(?:\.[0-9]+)?)%\]`)
+	// brBarProgressRegexp matches the progress bar printed by BR, e.g.
+	// Full backup <-------------...............................> 45.30%
+	brBarProgressRegexp = regexp.MustCompile(`^(.+?) <[-/\\|.>~]*> ([0-9]+(?:\.[0-9]+)?)%`)
+)
+
+// ParseBRProgress parses the step name and the progress percentage from a line of the BR output,
+// ok is false if the line does not contain the progress of BR.
+func ParseBRProgress(line string) (step string, progress float64, ok bool) {
+	line = strings.TrimSpace(line)
+	var value string
+	if m := brLogProgressRegexp.FindStringSubmatch(line); m != nil {
+		step, value = m[1], m[3]
+		if step == "" {
+			step = m[2]
+		}
+	} else if m := brBarProgressRegexp.FindStringSubmatch(line); m != nil {
+		step, value = strings.TrimSpace(m[1]), m[2]
+	} else {
+		return "", 0, false
+	}
+
+	progress, err := strconv.ParseFloat(value, 64)
+	if err != nil || step == "" {
+		return "", 0, false
+	}
+	if progress > 100 {
+		progress = 100
+	}
+	return step, progress, true
+}
+
+// ScanBROutputLines is a bufio.SplitFunc which splits the BR output into lines ended by '\n' or '\r',
+// BR redraws its progress bar in place with '\r', so every frame of the bar is returned as a line.
+// The line ending is not included in the returned line, and "\r\n" yields an extra empty line.
+func ScanBROutputLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// RateLimiter allows an action at most once per interval.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     time.Time
+}
+
+// NewRateLimiter returns a RateLimiter which allows an action at most once per interval
+func NewRateLimiter(interval time.Duration) *RateLimiter {
+	return &RateLimiter{interval: interval}
+}
+
+// Allow returns whether the action is allowed at now, and records now as the time of the last action if allowed.
+func (r *RateLimiter) Allow(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.last.IsZero() && now.Sub(r.last) < r.interval {
+		return false
+	}
+	r.last = now
+	return true
+}
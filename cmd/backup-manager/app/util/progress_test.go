@@ -0,0 +1,131 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseBRProgress(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name           string
+		line           string
+		expectOK       bool
+		expectStep     string
+		expectProgress float64
+	}{
+		{
+			name:           "progress log with quoted step",
+			line:           `[2021/03/02 10:00:00.000 +08:00] [INFO] [progress.go:80] ["Full backup"] [progress=45.30%] [count="10 / 22"] [speed="1 p/s"] [elapsed=10s] [remaining=12s]`,
+			expectOK:       true,
+			expectStep:     "Full backup",
+			expectProgress: 45.3,
+		},
+		{
+			name:           "progress log with unquoted step",
+			line:           `[2021/03/02 10:00:00.000 +08:00] [INFO] [progress.go:80] [Checksum] [progress=100%] [count="22 / 22"]` + "\n",
+			expectOK:       true,
+			expectStep:     "Checksum",
+			expectProgress: 100,
+		},
+		{
+			name:           "progress bar",
+			line:           "Full backup <-----------------/.................................> 33.61%\n",
+			expectOK:       true,
+			expectStep:     "Full backup",
+			expectProgress: 33.61,
+		},
+		{
+			name:           "completed progress bar",
+			line:           "Full backup <--------------------------------------------------------> 100.00%",
+			expectOK:       true,
+			expectStep:     "Full backup",
+			expectProgress: 100,
+		},
+		{
+			name: "normal log",
+			line: `[2021/03/02 10:00:00.000 +08:00] [INFO] [backup.go:100] ["backup started"] [ranges=1]`,
+		},
+		{
+			name: "empty line",
+			line: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test: %s", test.name)
+		step, progress, ok := ParseBRProgress(test.line)
+		g.Expect(ok).To(Equal(test.expectOK))
+		g.Expect(step).To(Equal(test.expectStep))
+		g.Expect(progress).To(BeNumerically("~", test.expectProgress, 0.001))
+	}
+}
+
+func TestScanBROutputLines(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// BR redraws the progress bar in place with '\r' and only ends the line when the step is done
+	output := "[2021/03/02 10:00:00.000 +08:00] [INFO] [backup.go:100] [\"backup started\"] [ranges=1]\n" +
+		"Full backup <-----.......> 10.00%\r" +
+		"Full backup <------......> 45.30%\r" +
+		"Full backup <------------> 100.00%\r\n" +
+		"[2021/03/02 10:00:10.000 +08:00] [ERROR] [backup.go:200] [\"backup failed\"]"
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Split(ScanBROutputLines)
+	var lines []string
+	var progresses []float64
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+		if step, progress, ok := ParseBRProgress(line); ok {
+			g.Expect(step).To(Equal("Full backup"))
+			progresses = append(progresses, progress)
+		}
+	}
+	g.Expect(scanner.Err()).NotTo(HaveOccurred())
+	g.Expect(lines).To(Equal([]string{
+		`[2021/03/02 10:00:00.000 +08:00] [INFO] [backup.go:100] ["backup started"] [ranges=1]`,
+		"Full backup <-----.......> 10.00%",
+		"Full backup <------......> 45.30%",
+		"Full backup <------------> 100.00%",
+		`[2021/03/02 10:00:10.000 +08:00] [ERROR] [backup.go:200] ["backup failed"]`,
+	}))
+	g.Expect(progresses).To(Equal([]float64{10, 45.3, 100}))
+}
+
+func TestRateLimiter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	start := time.Unix(1000, 0)
+	limiter := NewRateLimiter(30 * time.Second)
+
+	g.Expect(limiter.Allow(start)).To(BeTrue())
+	g.Expect(limiter.Allow(start.Add(time.Second))).To(BeFalse())
+	g.Expect(limiter.Allow(start.Add(29 * time.Second))).To(BeFalse())
+	g.Expect(limiter.Allow(start.Add(30 * time.Second))).To(BeTrue())
+	// the interval starts from the last allowed action
+	g.Expect(limiter.Allow(start.Add(45 * time.Second))).To(BeFalse())
+	g.Expect(limiter.Allow(start.Add(60 * time.Second))).To(BeTrue())
+}
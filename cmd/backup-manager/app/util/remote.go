@@ -20,9 +20,12 @@ import (
 	"path"
 	"strings"
 	"sync"
+	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
@@ -31,6 +34,8 @@ import (
 	"gocloud.dev/blob/gcsblob"
 	"gocloud.dev/blob/s3blob"
 	"gocloud.dev/gcp"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -203,7 +208,17 @@ func (b *StorageBackend) BatchDeleteObjects(ctx context.Context, objs []*blob.Li
 	return result
 }
 
+// batchDeleteBackoff is the backoff of retrying a batch deletion throttled by the storage
+var batchDeleteBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    6,
+}
+
 // BatchDeleteObjectsOfS3 delete objects by batch delete api
+//
+// The throttled requests and objects are retried with an exponential backoff.
 func BatchDeleteObjectsOfS3(ctx context.Context, s3cli s3iface.S3API, objs []*blob.ListObject, bucket string, prefix string, concurrency int) *BatchDeleteObjectsResult {
 	mu := &sync.Mutex{}
 	result := &BatchDeleteObjectsResult{}
@@ -223,40 +238,63 @@ func BatchDeleteObjectsOfS3(ctx context.Context, s3cli s3iface.S3API, objs []*bl
 			return
 		}
 
-		// delete objects
-		delete := &s3.Delete{}
-		for _, obj := range objs[start:end] {
-			delete.Objects = append(delete.Objects, &s3.ObjectIdentifier{
-				Key: aws.String(prefix + obj.Key), // key must be absolute path
-			})
-		}
-		input := &s3.DeleteObjectsInput{
-			Bucket: aws.String(bucket),
-			Delete: delete,
-		}
-		resp, err := s3cli.DeleteObjectsWithContext(ctx, input)
+		var deleted []string
+		var errs []ObjectError
+		pending := objs[start:end]
+		err := retry.OnError(batchDeleteBackoff, request.IsErrorThrottle, func() error {
+			// delete objects
+			delete := &s3.Delete{}
+			keyToObj := make(map[string]*blob.ListObject, len(pending))
+			for _, obj := range pending {
+				key := prefix + obj.Key // key must be absolute path
+				keyToObj[key] = obj
+				delete.Objects = append(delete.Objects, &s3.ObjectIdentifier{
+					Key: aws.String(key),
+				})
+			}
+			input := &s3.DeleteObjectsInput{
+				Bucket: aws.String(bucket),
+				Delete: delete,
+			}
+			resp, err := s3cli.DeleteObjectsWithContext(ctx, input)
+			if err != nil {
+				return err
+			}
+
+			for _, d := range resp.Deleted {
+				deleted = append(deleted, *d.Key)
+			}
+			var throttled []*blob.ListObject
+			var throttleErr error
+			for _, rerr := range resp.Errors {
+				oerr := awserr.New(*rerr.Code, *rerr.Message, nil)
+				if obj, ok := keyToObj[*rerr.Key]; ok && request.IsErrorThrottle(oerr) {
+					throttled = append(throttled, obj)
+					throttleErr = oerr
+					continue
+				}
+				errs = append(errs, ObjectError{
+					Key: *rerr.Key,
+					Err: fmt.Errorf("code:%s msg:%s", *rerr.Code, *rerr.Message),
+				})
+			}
+			pending = throttled
+			return throttleErr
+		})
 
 		// record result
 		mu.Lock()
 		defer mu.Unlock()
+		result.Deleted = append(result.Deleted, deleted...)
+		result.Errors = append(result.Errors, errs...)
 		if err != nil {
-			// send request failed, consider that all deletion is failed
-			for _, obj := range objs[start:end] {
+			// send request failed, consider that all pending deletion is failed
+			for _, obj := range pending {
 				result.Errors = append(result.Errors, ObjectError{
 					Key: obj.Key,
 					Err: err,
 				})
 			}
-		} else {
-			for _, deleted := range resp.Deleted {
-				result.Deleted = append(result.Deleted, *deleted.Key)
-			}
-			for _, rerr := range resp.Errors {
-				result.Errors = append(result.Errors, ObjectError{
-					Key: *rerr.Key,
-					Err: fmt.Errorf("code:%s msg:%s", *rerr.Code, *rerr.Message),
-				})
-			}
 		}
 	})
 
@@ -469,8 +507,9 @@ func makeGcsConfig(gcs *v1alpha1.GcsStorageProvider, fakeRegion bool) *gcsConfig
 }
 
 func makeLocalConfig(local *v1alpha1.LocalStorageProvider) *localConfig {
+	_, volumeMount := local.GetVolumeAndMount()
 	return &localConfig{
-		mountPath: local.VolumeMount.MountPath,
+		mountPath: volumeMount.MountPath,
 		prefix:    local.Prefix,
 	}
 }
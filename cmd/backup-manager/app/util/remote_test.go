@@ -22,10 +22,12 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/storage"
 	gomonkey "github.com/agiledragon/gomonkey/v2"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
@@ -578,6 +580,105 @@ func TestBatchDeleteObjectsOfS3(t *testing.T) {
 	}
 }
 
+func TestBatchDeleteObjectsOfS3Throttled(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	backoff := batchDeleteBackoff
+	batchDeleteBackoff.Duration = time.Millisecond
+	defer func() { batchDeleteBackoff = backoff }()
+
+	type testcase struct {
+		name string
+		// respFn returns the request error of the call, or the error code of the key in the call,
+		// the call and the attempt of the key start from 0
+		respFn        func(call int, key string, attempt int) (reqErr error, code string)
+		expectCalls   int
+		expectDeleted int
+		expectErrors  int
+	}
+	cases := []testcase{
+		{
+			name: "request throttled",
+			respFn: func(call int, key string, attempt int) (error, string) {
+				if call == 0 {
+					return awserr.New("SlowDown", "reduce your request rate", nil), ""
+				}
+				return nil, ""
+			},
+			// 1 throttled call and 3 batches
+			expectCalls:   4,
+			expectDeleted: 2500,
+		},
+		{
+			name: "objects throttled",
+			respFn: func(call int, key string, attempt int) (error, string) {
+				if i, _ := strconv.Atoi(key); i%10 == 0 && attempt == 0 {
+					return nil, "SlowDown"
+				}
+				return nil, ""
+			},
+			// 3 batches and a retry of each batch
+			expectCalls:   6,
+			expectDeleted: 2500,
+		},
+		{
+			name: "objects failed with other errors are not retried",
+			respFn: func(call int, key string, attempt int) (error, string) {
+				if i, _ := strconv.Atoi(key); i%10 == 0 {
+					return nil, "AccessDenied"
+				}
+				return nil, ""
+			},
+			expectCalls:   3,
+			expectDeleted: 2250,
+			expectErrors:  250,
+		},
+		{
+			name: "retries exhausted",
+			respFn: func(call int, key string, attempt int) (error, string) {
+				return awserr.New("Throttling", "rate exceeded", nil), ""
+			},
+			expectCalls:  3 * batchDeleteBackoff.Steps,
+			expectErrors: 2500,
+		},
+	}
+
+	for _, tcase := range cases {
+		t.Log(tcase.name)
+		calls := 0
+		attempts := map[string]int{}
+		cli := &mockS3Client{}
+		cli.deleteObjects = func(input *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+			defer func() { calls++ }()
+			output := &s3.DeleteObjectsOutput{}
+			for _, delObj := range input.Delete.Objects {
+				key := *delObj.Key
+				reqErr, code := tcase.respFn(calls, key, attempts[key])
+				if reqErr != nil {
+					return nil, reqErr
+				}
+				attempts[key]++
+				if code != "" {
+					output.Errors = append(output.Errors, &s3.Error{
+						Key:     delObj.Key,
+						Code:    aws.String(code),
+						Message: aws.String(code),
+					})
+					continue
+				}
+				output.Deleted = append(output.Deleted, &s3.DeletedObject{Key: delObj.Key})
+			}
+			return output, nil
+		}
+
+		// the batches are deleted one by one
+		result := BatchDeleteObjectsOfS3(context.Background(), cli, objects(2500), "test", "", 1)
+		g.Expect(calls).To(gomega.Equal(tcase.expectCalls))
+		g.Expect(result.Deleted).To(gomega.HaveLen(tcase.expectDeleted))
+		g.Expect(result.Errors).To(gomega.HaveLen(tcase.expectErrors))
+	}
+}
+
 func TestBatchDeleteObjectsConcurrently(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
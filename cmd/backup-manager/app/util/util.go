@@ -105,7 +105,8 @@ func GetStoragePath(backup *v1alpha1.Backup) (string, error) {
 		return url, nil
 	case v1alpha1.BackupStorageTypeLocal:
 		prefix = backup.Spec.StorageProvider.Local.Prefix
-		mountPath := backup.Spec.StorageProvider.Local.VolumeMount.MountPath
+		_, volumeMount := backup.Spec.StorageProvider.Local.GetVolumeAndMount()
+		mountPath := volumeMount.MountPath
 		url = fmt.Sprintf("local://%s", path.Join(mountPath, prefix))
 		return url, nil
 	default:
@@ -262,6 +263,19 @@ func ConstructBRGlobalOptionsForRestore(restore *v1alpha1.Restore) ([]string, er
 	return args, nil
 }
 
+// GetPDAddress returns the PD address passed by --pdAddress. The Jobs created by the operators without the flag
+// don't pass it, so the address of the PD of the cluster in the BR config is returned if it's empty.
+func GetPDAddress(pdAddress string, config *v1alpha1.BRConfig, ns string) string {
+	if pdAddress != "" {
+		return pdAddress
+	}
+	clusterNamespace := config.ClusterNamespace
+	if clusterNamespace == "" {
+		clusterNamespace = ns
+	}
+	return fmt.Sprintf("%s-pd.%s:2379", config.Cluster, clusterNamespace)
+}
+
 // constructBRGlobalOptions constructs BR basic global options.
 func constructBRGlobalOptions(config *v1alpha1.BRConfig) []string {
 	var args []string
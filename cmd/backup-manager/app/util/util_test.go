@@ -244,6 +244,37 @@ func TestGetRemotePath(t *testing.T) {
 			},
 			expect: "gcs://test1-demo1/",
 		},
+		{
+			name: "local with volume",
+			backup: &v1alpha1.Backup{
+				Spec: v1alpha1.BackupSpec{
+					StorageProvider: v1alpha1.StorageProvider{
+						Local: &v1alpha1.LocalStorageProvider{
+							VolumeMount: corev1.VolumeMount{
+								Name:      "nfs",
+								MountPath: "/nfs",
+							},
+							Prefix: "prefix",
+						},
+					},
+				},
+			},
+			expect: "local:///nfs/prefix",
+		},
+		{
+			name: "local with pvc",
+			backup: &v1alpha1.Backup{
+				Spec: v1alpha1.BackupSpec{
+					StorageProvider: v1alpha1.StorageProvider{
+						Local: &v1alpha1.LocalStorageProvider{
+							PersistentVolumeClaimName: "backup-pvc",
+							Prefix:                    "prefix",
+						},
+					},
+				},
+			},
+			expect: "local:///backup/prefix",
+		},
 		{
 			name: "unknow storage type",
 			backup: &v1alpha1.Backup{
@@ -308,6 +339,42 @@ func TestSuffix(t *testing.T) {
 	}
 }
 
+func TestGetPDAddress(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name      string
+		pdAddress string
+		config    *v1alpha1.BRConfig
+		expect    string
+	}
+
+	tests := []*testcase{
+		{
+			name:      "passed by the operator",
+			pdAddress: "primary-pd.ns-primary:2379",
+			config:    &v1alpha1.BRConfig{Cluster: "tiflash-only"},
+			expect:    "primary-pd.ns-primary:2379",
+		},
+		{
+			name:   "not passed, in the namespace of the backup",
+			config: &v1alpha1.BRConfig{Cluster: "demo"},
+			expect: "demo-pd.ns:2379",
+		},
+		{
+			name:   "not passed, in the cluster namespace",
+			config: &v1alpha1.BRConfig{Cluster: "demo", ClusterNamespace: "tidb"},
+			expect: "demo-pd.tidb:2379",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g.Expect(GetPDAddress(tt.pdAddress, tt.config, "ns")).To(Equal(tt.expect))
+		})
+	}
+}
+
 func TestConstructBRGlobalOptionsForRestore(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -122,11 +122,13 @@ func main() {
 	}
 
 	// note that kubeCli here must not be the hijacked one
-	var operatorUpgrader upgrader.Interface
+	var operatorUpgraders []upgrader.Interface
 	if cliCfg.ClusterScoped {
-		operatorUpgrader = upgrader.NewUpgrader(kubeCli, cli, asCli, metav1.NamespaceAll)
+		operatorUpgraders = append(operatorUpgraders, upgrader.NewUpgrader(kubeCli, cli, asCli, metav1.NamespaceAll))
 	} else {
-		operatorUpgrader = upgrader.NewUpgrader(kubeCli, cli, asCli, ns)
+		for _, watchNs := range cliCfg.GetWatchNamespaces(ns) {
+			operatorUpgraders = append(operatorUpgraders, upgrader.NewUpgrader(kubeCli, cli, asCli, watchNs))
+		}
 	}
 
 	if features.DefaultFeatureGate.Enabled(features.AdvancedStatefulSet) {
@@ -140,8 +142,10 @@ func main() {
 	onStarted := func(ctx context.Context) {
 		// Upgrade before running any controller logic. If it fails, we wait
 		// for process supervisor to restart it again.
-		if err := operatorUpgrader.Upgrade(); err != nil {
-			klog.Fatalf("failed to upgrade: %v", err)
+		for _, operatorUpgrader := range operatorUpgraders {
+			if err := operatorUpgrader.Upgrade(); err != nil {
+				klog.Fatalf("failed to upgrade: %v", err)
+			}
 		}
 
 		// Define some nested types to simplify the codebase
@@ -170,11 +174,16 @@ func main() {
 			controllers = append(controllers, autoscaler.NewController(deps))
 		}
 
-		// Start informer factories after all controllers are initialized.
-		informerFactories := []InformerFactory{
-			deps.InformerFactory,
-			deps.KubeInformerFactory,
-			deps.LabelFilterKubeInformerFactory,
+		// Start informer factories of all the watched namespaces after all controllers are initialized.
+		var informerFactories []InformerFactory
+		for _, f := range deps.InformerFactories {
+			informerFactories = append(informerFactories, f)
+		}
+		for _, f := range deps.KubeInformerFactories {
+			informerFactories = append(informerFactories, f)
+		}
+		for _, f := range deps.LabelFilterKubeInformerFactories {
+			informerFactories = append(informerFactories, f)
 		}
 		for _, f := range informerFactories {
 			f.Start(ctx.Done())
@@ -15,6 +15,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"flag"
 	"fmt"
 	"net/http"
@@ -29,6 +30,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/discovery/server"
 	"github.com/pingcap/tidb-operator/pkg/dmapi"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util/crypto"
 	"github.com/pingcap/tidb-operator/pkg/version"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
@@ -38,9 +40,10 @@ import (
 )
 
 var (
-	printVersion bool
-	port         int
-	proxyPort    int
+	printVersion  bool
+	port          int
+	proxyPort     int
+	tlsSecretName string
 )
 
 func init() {
@@ -48,6 +51,7 @@ func init() {
 	flag.BoolVar(&printVersion, "version", false, "Show version and quit")
 	flag.IntVar(&port, "port", 10261, "The port that the tidb discovery's http service runs on (default 10261)")
 	flag.IntVar(&proxyPort, "proxy-port", 10262, "The port that the tidb discovery's proxy service runs on (default 10262)")
+	flag.StringVar(&tlsSecretName, "tls-secret", "", "The name of the TLS Secret in the same namespace to serve HTTPS with, the Secret is watched and the certificate is rotated without restarting")
 	flag.Parse()
 }
 
@@ -88,17 +92,38 @@ func main() {
 		tcTls = true
 	}
 
+	var servingTLSConfig *tls.Config
+	if len(tlsSecretName) > 0 {
+		ns := os.Getenv("MY_POD_NAMESPACE")
+		if len(ns) < 1 {
+			klog.Fatal("ENV MY_POD_NAMESPACE is not set")
+		}
+		certWatcher := crypto.NewSecretCertWatcher(kubeCli, ns, tlsSecretName, 30*time.Second)
+		if err := certWatcher.Run(wait.NeverStop); err != nil {
+			klog.Fatalf("failed to load serving certificate: %v", err)
+		}
+		servingTLSConfig = certWatcher.TLSConfig()
+	}
+
 	go wait.Forever(func() {
 		addr := fmt.Sprintf("0.0.0.0:%d", port)
 		klog.Infof("starting TiDB Discovery server, listening on %s", addr)
 		discoveryServer := server.NewServer(pdapi.NewDefaultPDControl(kubeCli), dmapi.NewDefaultMasterControl(kubeCli), cli, kubeCli)
-		discoveryServer.ListenAndServe(addr)
+		if servingTLSConfig != nil {
+			discoveryServer.ListenAndServeTLS(addr, servingTLSConfig)
+		} else {
+			discoveryServer.ListenAndServe(addr)
+		}
 	}, 5*time.Second)
 	go wait.Forever(func() {
 		addr := fmt.Sprintf("0.0.0.0:%d", proxyPort)
 		klog.Infof("starting TiDB Proxy server, listening on %s", addr)
 		proxyServer := server.NewProxyServer(tcName, tcTls)
-		proxyServer.ListenAndServe(addr)
+		if servingTLSConfig != nil {
+			proxyServer.ListenAndServeTLS(addr, servingTLSConfig)
+		} else {
+			proxyServer.ListenAndServe(addr)
+		}
 	}, 5*time.Second)
 
 	srv := http.Server{Addr: ":6060"}
@@ -22,6 +22,7 @@ cd $ROOT
 
 hack/update-codegen.sh
 hack/update-openapi-spec.sh
+hack/update-config-schema.sh
 hack/update-crd-groups.sh
 hack/update-EOF.sh
 hack/update-goimports.sh
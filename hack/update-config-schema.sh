@@ -0,0 +1,47 @@
+#!/usr/bin/env bash
+
+# Copyright 2021 PingCAP, Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# This script embeds the config schemas in pkg/configschema/assets into
+# pkg/configschema/assets_generated.go.
+#
+# To add the schema of a new version, put the schema file as
+# pkg/configschema/assets/<component>/v<major>.<minor>.json and run this script.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+ROOT=$(unset CDPATH && cd $(dirname "${BASH_SOURCE[0]}")/.. && pwd)
+cd $ROOT
+
+ASSETS_DIR=pkg/configschema/assets
+target=pkg/configschema/assets_generated.go
+
+{
+    cat hack/boilerplate/boilerplate.generatego.txt
+    echo
+    echo "// Code generated by hack/update-config-schema.sh. DO NOT EDIT."
+    echo
+    echo "package configschema"
+    echo
+    echo "// assets are the config schemas in ${ASSETS_DIR}, keyed by <component>/<version>.json"
+    echo "var assets = map[string]string{"
+    for f in $(find ${ASSETS_DIR} -name '*.json' | LC_ALL=C sort); do
+        echo "	\"${f#${ASSETS_DIR}/}\": \`$(cat $f)\`,"
+    done
+    echo "}"
+} > $target
+
+gofmt -w $target
@@ -34,6 +34,9 @@ const (
 	// InstanceLabelKey is Kubernetes recommended label key, it represents a unique name identifying the instance of an application
 	// It's set by helm when installing a release
 	InstanceLabelKey string = "app.kubernetes.io/instance"
+	// PartOfLabelKey is Kubernetes recommended label key, it represents the name of a higher level application this one is part of
+	// It's one of the standard labels, see StandardLabelKeys
+	PartOfLabelKey string = "app.kubernetes.io/part-of"
 
 	// NamespaceLabelKey is label key used in PV for easy querying
 	NamespaceLabelKey string = "app.kubernetes.io/namespace"
@@ -46,6 +49,8 @@ const (
 	StoreIDLabelKey string = "tidb.pingcap.com/store-id"
 	// MemberIDLabelKey is member id label key
 	MemberIDLabelKey string = "tidb.pingcap.com/member-id"
+	// NodeLabelKey is the label key of the node which a local PV is pinned to
+	NodeLabelKey string = "tidb.pingcap.com/node"
 
 	// InitLabelKey is the key for TiDB initializer
 	InitLabelKey string = "tidb.pingcap.com/initializer"
@@ -62,6 +67,14 @@ const (
 	// BackupProtectionFinalizer is the name of finalizer on backups
 	BackupProtectionFinalizer string = "tidb.pingcap.com/backup-protection"
 
+	// TidbClusterCleanupFinalizer is the name of finalizer on tidb clusters, it's removed once the
+	// cluster is cleaned up, e.g. the reclaim policy of its PVs is restored
+	TidbClusterCleanupFinalizer string = "tidb.pingcap.com/tidbcluster-cleanup"
+
+	// TidbMonitorCleanupFinalizer is the name of finalizer on tidb monitors granted in the namespaces of the
+	// target clusters, it's removed once the Roles and RoleBindings in the other namespaces are deleted
+	TidbMonitorCleanupFinalizer string = "tidb.pingcap.com/tidbmonitor-cleanup"
+
 	// AutoScalingGroupLabelKey describes the autoscaling group of the TiDB
 	AutoScalingGroupLabelKey = "tidb.pingcap.com/autoscaling-group"
 	// AutoInstanceLabelKey is label key used in autoscaling, it represents the autoscaler name
@@ -70,6 +83,9 @@ const (
 	AutoComponentLabelKey string = "tidb.pingcap.com/auto-component"
 	// BaseTCLabelKey is label key used for heterogeneous clusters to refer to its base TidbCluster
 	BaseTCLabelKey string = "tidb.pingcap.com/base-tc"
+	// PreservedFailurePodLabelKey is label key of the failure pod preserved by the failover for inspection,
+	// its value is the component of the pod, the component label is removed from the pod
+	PreservedFailurePodLabelKey string = "tidb.pingcap.com/preserved-failure-pod"
 
 	// AnnHATopologyKey defines the High availability topology key
 	AnnHATopologyKey = "pingcap.com/ha-topology-key"
@@ -97,11 +113,54 @@ const (
 	AnnEvictLeaderBeginTime = "tidb.pingcap.com/evictLeaderBeginTime"
 	// AnnStsLastSyncTimestamp is sts annotation key to indicate the last timestamp the operator sync the sts
 	AnnStsLastSyncTimestamp = "tidb.pingcap.com/sync-timestamp"
+	// AnnTLSServerCertHashKey is pod annotation key of the hash of the server-side certificate secret of TiDB,
+	// it's set only if TiDB should be restarted on the renewal of the certificate
+	AnnTLSServerCertHashKey = "tidb.pingcap.com/tls-server-cert-hash"
+	// AnnForceScaleInPDKey is tc annotation key to indicate whether the quorum check of PD scale-in should be skipped,
+	// it is used for disaster recovery
+	AnnForceScaleInPDKey = "tidb.pingcap.com/force-scale-in-pd"
+	// AnnPendingPodLabelsKey is sts annotation key to record the standard labels which are not added to the pod template yet,
+	// they are added with the next rolling update of the sts to avoid restarting pods only for labels
+	AnnPendingPodLabelsKey = "tidb.pingcap.com/pending-pod-labels"
+	// AnnBackupEligibleKey is PV annotation key to indicate whether the PV holds the data of a component
+	// which should be snapshotted by the volume backup tooling
+	AnnBackupEligibleKey = "tidb.pingcap.com/backup-eligible"
+	// AnnPDBootstrappedKey is tc annotation key to record the name of the PD pod told by the discovery service
+	// to bootstrap the PD cluster, it makes sure only one PD pod ever bootstraps the PD cluster
+	AnnPDBootstrappedKey = "tidb.pingcap.com/pd-bootstrapped"
+	// AnnBypassStsAdmissionKey is sts annotation key to indicate whether the checks of the statefulset admission webhook
+	// should be skipped, it is used for emergencies
+	AnnBypassStsAdmissionKey = "tidb.pingcap.com/bypass-sts-admission"
+	// AnnAllowEvenPDReplicasKey is tc annotation key to indicate whether an even number of PD replicas is allowed
+	AnnAllowEvenPDReplicasKey = "tidb.pingcap.com/allow-even-pd-replicas"
+	// AnnPDTriggerFailoverKey is tc annotation key to name the PD pod whose member should be failed over immediately
+	// regardless of its health, it is cleared once the failover is triggered or refused
+	AnnPDTriggerFailoverKey = "tidb.pingcap.com/pd-trigger-failover"
+	// AnnFailoverOriginKey is pv annotation key to record the name of the pod of the failure member the PV
+	// belongs to in the PodOnly failover mode, the PV is rebound to the PVC of the pod if the PVC is lost when
+	// the failure member is recovered
+	AnnFailoverOriginKey = "tidb.pingcap.com/failover-origin"
+	// AnnForceRemoveFinalizerKey is tc annotation key to indicate whether the finalizer of the tc being deleted
+	// should be removed without the cleanup, it is used when the cleanup is stuck
+	AnnForceRemoveFinalizerKey = "tidb.pingcap.com/force-remove-finalizer"
+	// AnnOriginalReclaimPolicyKey is pv annotation key to record the reclaim policy of the PV before it's
+	// patched by the operator, the reclaim policy is restored with it if the change is rolled back
+	AnnOriginalReclaimPolicyKey = "tidb.pingcap.com/original-reclaim-policy"
+	// AnnInitSqlHashesKey is job annotation key to record the hashes of the SQL files of the TidbInitializer
+	// the job executes, they are recorded in the status of the TidbInitializer after they are executed
+	AnnInitSqlHashesKey = "tidb.pingcap.com/init-sql-hashes"
 
 	// AnnForceUpgradeVal is tc annotation value to indicate whether force upgrade should be done
 	AnnForceUpgradeVal = "true"
 	// AnnSysctlInitVal is pod annotation value to indicate whether configuring sysctls with init container
 	AnnSysctlInitVal = "true"
+	// AnnForceScaleInPDVal is tc annotation value to indicate whether the quorum check of PD scale-in should be skipped
+	AnnForceScaleInPDVal = "true"
+	// AnnBypassStsAdmissionVal is sts annotation value to indicate whether the checks of the statefulset admission webhook
+	// should be skipped
+	AnnBypassStsAdmissionVal = "true"
+	// AnnAllowEvenPDReplicasVal is tc annotation value to indicate whether an even number of PD replicas is allowed
+	AnnAllowEvenPDReplicasVal = "true"
 
 	// AnnPDDeleteSlots is annotation key of pd delete slots.
 	AnnPDDeleteSlots = "pd.tidb.pingcap.com/delete-slots"
@@ -146,6 +205,8 @@ const (
 	BackupScheduleJobLabelVal string = "backup-schedule"
 	// InitJobLabelVal is TiDB initializer job label value
 	InitJobLabelVal string = "initializer"
+	// PreScaleInJobLabelVal is TiKV pre scale-in job label value
+	PreScaleInJobLabelVal string = "pre-scale-in"
 	// TiDBOperator is ManagedByLabelKey label value
 	TiDBOperator string = "tidb-operator"
 
@@ -164,6 +225,10 @@ const (
 	ApplicationLabelKey string = "app.kubernetes.io/app"
 )
 
+// StandardLabelKeys are the keys of the standard labels, they are added to the generated objects in addition
+// to the legacy labels and are never used in selectors, so that they can be added to the existing objects
+var StandardLabelKeys = []string{PartOfLabelKey}
+
 // Label is the label field in metadata
 type Label map[string]string
 
@@ -442,6 +507,15 @@ func (l Label) Labels() map[string]string {
 	return l
 }
 
+// Standard returns the standard labels derived from the legacy labels
+func (l Label) Standard() Label {
+	std := Label{}
+	if name, ok := l[NameLabelKey]; ok {
+		std[PartOfLabelKey] = name
+	}
+	return std
+}
+
 // Copy copy the value of label to avoid pointer copy
 func (l Label) Copy() Label {
 	copyLabel := make(Label)
@@ -100,6 +100,14 @@ func TestLabelDMWorker(t *testing.T) {
 	g.Expect(l.IsDMWorker()).To(BeTrue())
 }
 
+func TestLabelStandard(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(New().Instance("demo").PD().Standard()).To(Equal(Label{PartOfLabelKey: "tidb-cluster"}))
+	g.Expect(NewDM().Instance("demo").DMMaster().Standard()).To(Equal(Label{PartOfLabelKey: "dm-cluster"}))
+	g.Expect(NewOperatorManaged().Standard()).To(BeEmpty())
+}
+
 func TestLabelSelector(t *testing.T) {
 	g := NewGomegaWithT(t)
 
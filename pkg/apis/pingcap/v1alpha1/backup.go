@@ -15,6 +15,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	corev1 "k8s.io/api/core/v1"
@@ -34,6 +35,64 @@ var (
 	}
 )
 
+const (
+	// LocalStorageVolumeName is the name of the volume of LocalStorageProvider.PersistentVolumeClaimName
+	LocalStorageVolumeName = "local-storage"
+	// LocalStorageMountPath is the mount path of LocalStorageProvider.PersistentVolumeClaimName
+	LocalStorageMountPath = "/backup"
+
+	// DefaultBackoffRetryMaxRetryTimes is the default max retry times of BackoffRetryPolicy
+	DefaultBackoffRetryMaxRetryTimes int32 = 2
+	// DefaultBackoffRetryMinRetryDuration is the default min retry duration of BackoffRetryPolicy
+	DefaultBackoffRetryMinRetryDuration = "300s"
+	// DefaultBackoffRetryTimeout is the default retry timeout of BackoffRetryPolicy
+	DefaultBackoffRetryTimeout = "30m"
+)
+
+// GetMaxRetryTimes returns the max retry times of the policy
+func (p *BackoffRetryPolicy) GetMaxRetryTimes() int32 {
+	if p.MaxRetryTimes == nil {
+		return DefaultBackoffRetryMaxRetryTimes
+	}
+	return *p.MaxRetryTimes
+}
+
+// GetMinRetryDuration returns the min retry duration of the policy
+func (p *BackoffRetryPolicy) GetMinRetryDuration() (time.Duration, error) {
+	if p.MinRetryDuration == "" {
+		return time.ParseDuration(DefaultBackoffRetryMinRetryDuration)
+	}
+	return time.ParseDuration(p.MinRetryDuration)
+}
+
+// GetRetryTimeout returns the retry timeout of the policy
+func (p *BackoffRetryPolicy) GetRetryTimeout() (time.Duration, error) {
+	if p.RetryTimeout == "" {
+		return time.ParseDuration(DefaultBackoffRetryTimeout)
+	}
+	return time.ParseDuration(p.RetryTimeout)
+}
+
+// GetVolumeAndMount returns the volume and the volume mount of the local storage
+func (local *LocalStorageProvider) GetVolumeAndMount() (corev1.Volume, corev1.VolumeMount) {
+	if local.PersistentVolumeClaimName == "" {
+		return local.Volume, local.VolumeMount
+	}
+	volume := corev1.Volume{
+		Name: LocalStorageVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: local.PersistentVolumeClaimName,
+			},
+		},
+	}
+	volumeMount := corev1.VolumeMount{
+		Name:      LocalStorageVolumeName,
+		MountPath: LocalStorageMountPath,
+	}
+	return volume, volumeMount
+}
+
 // GetCleanJobName return the clean job name
 func (bk *Backup) GetCleanJobName() string {
 	return fmt.Sprintf("clean-%s", bk.GetName())
@@ -145,6 +204,12 @@ func IsBackupFailed(backup *Backup) bool {
 	return condition != nil && condition.Status == corev1.ConditionTrue
 }
 
+// IsBackupRetrying returns true if a failed Backup is being retried according to the backoff retry policy
+func IsBackupRetrying(backup *Backup) bool {
+	_, condition := GetBackupCondition(&backup.Status, BackupRetryTheFailed)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
 // IsBackupScheduled returns true if a Backup has successfully scheduled
 func IsBackupScheduled(backup *Backup) bool {
 	_, condition := GetBackupCondition(&backup.Status, BackupScheduled)
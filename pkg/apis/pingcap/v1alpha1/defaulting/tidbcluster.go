@@ -14,8 +14,12 @@
 package defaulting
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
 	"k8s.io/utils/pointer"
 )
 
@@ -147,3 +151,61 @@ func setTiCDCSpecDefault(tc *v1alpha1.TidbCluster) {
 		}
 	}
 }
+
+// ImageDigestResolver resolves the digest of an image from its registry
+type ImageDigestResolver interface {
+	ResolveDigest(image string) (string, error)
+}
+
+// SetTidbClusterImages sets the resolved images of the components from their image, baseImage and version,
+// so the images to run are shown in the spec. If the resolver is not nil, the images are pinned to their digests
+// unless the imagePullPolicy of the component is Always, the image is kept as is if its digest can't be resolved.
+func SetTidbClusterImages(tc *v1alpha1.TidbCluster, resolver ImageDigestResolver) {
+	type component struct {
+		spec  *v1alpha1.ComponentSpec
+		image func() string
+	}
+	var components []component
+	if tc.Spec.PD != nil {
+		components = append(components, component{&tc.Spec.PD.ComponentSpec, tc.PDImage})
+	}
+	if tc.Spec.TiKV != nil {
+		components = append(components, component{&tc.Spec.TiKV.ComponentSpec, tc.TiKVImage})
+	}
+	if tc.Spec.TiDB != nil {
+		components = append(components, component{&tc.Spec.TiDB.ComponentSpec, tc.TiDBImage})
+	}
+	if tc.Spec.Pump != nil {
+		components = append(components, component{&tc.Spec.Pump.ComponentSpec, func() string { return *tc.PumpImage() }})
+	}
+	if tc.Spec.TiFlash != nil {
+		components = append(components, component{&tc.Spec.TiFlash.ComponentSpec, tc.TiFlashImage})
+	}
+	if tc.Spec.TiCDC != nil {
+		components = append(components, component{&tc.Spec.TiCDC.ComponentSpec, tc.TiCDCImage})
+	}
+
+	for _, c := range components {
+		// the resolved image is recomputed from the image, baseImage and version
+		c.spec.ResolvedImage = ""
+		image := c.image()
+		c.spec.ResolvedImage = image
+		if image == "" || resolver == nil || strings.Contains(image, "@") {
+			continue
+		}
+		pullPolicy := tc.Spec.ImagePullPolicy
+		if c.spec.ImagePullPolicy != nil {
+			pullPolicy = *c.spec.ImagePullPolicy
+		}
+		// the image is pulled for every pod with Always, it's expected to follow the tag
+		if pullPolicy == corev1.PullAlways {
+			continue
+		}
+		digest, err := resolver.ResolveDigest(image)
+		if err != nil {
+			klog.Warningf("failed to resolve the digest of image %s for tc %s/%s, use the tag: %v", image, tc.Namespace, tc.Name, err)
+			continue
+		}
+		c.spec.ResolvedImage = fmt.Sprintf("%s@%s", image, digest)
+	}
+}
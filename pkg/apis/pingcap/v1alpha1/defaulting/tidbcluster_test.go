@@ -14,10 +14,13 @@
 package defaulting
 
 import (
+	"fmt"
 	"testing"
 
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
 )
 
 func TestSetTidbSpecDefault(t *testing.T) {
@@ -79,6 +82,72 @@ func TestSetTidbSpecDefault(t *testing.T) {
 
 }
 
+type fakeImageDigestResolver map[string]string
+
+func (r fakeImageDigestResolver) ResolveDigest(image string) (string, error) {
+	digest, ok := r[image]
+	if !ok {
+		return "", fmt.Errorf("image %s not found", image)
+	}
+	return digest, nil
+}
+
+func TestSetTidbClusterImages(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	newTC := func() *v1alpha1.TidbCluster {
+		tc := newTidbCluster()
+		tc.Spec.Version = "v5.0.0"
+		tc.Spec.PD.BaseImage = "pingcap/pd"
+		tc.Spec.TiKV.BaseImage = "pingcap/tikv"
+		// the component version overrides the cluster version
+		tc.Spec.TiKV.Version = pointer.StringPtr("v5.0.1")
+		// the deprecated image is used without base image
+		tc.Spec.TiDB.Image = "pingcap/tidb:v4.0.0"
+		return tc
+	}
+
+	// without resolver
+	tc := newTC()
+	SetTidbClusterImages(tc, nil)
+	g.Expect(tc.Spec.PD.ResolvedImage).To(Equal("pingcap/pd:v5.0.0"))
+	g.Expect(tc.Spec.TiKV.ResolvedImage).To(Equal("pingcap/tikv:v5.0.1"))
+	g.Expect(tc.Spec.TiDB.ResolvedImage).To(Equal("pingcap/tidb:v4.0.0"))
+
+	// base image takes higher priority
+	tc = newTC()
+	tc.Spec.PD.Image = "pingcap/pd:v4.0.0"
+	SetTidbClusterImages(tc, nil)
+	g.Expect(tc.Spec.PD.ResolvedImage).To(Equal("pingcap/pd:v5.0.0"))
+
+	// pinned to digests
+	resolver := fakeImageDigestResolver{
+		"pingcap/pd:v5.0.0":   "sha256:pd",
+		"pingcap/tidb:v4.0.0": "sha256:tidb",
+	}
+	tc = newTC()
+	tc.Spec.ImagePullPolicy = corev1.PullIfNotPresent
+	tc.Spec.TiDB.ImagePullPolicy = func() *corev1.PullPolicy { p := corev1.PullAlways; return &p }()
+	SetTidbClusterImages(tc, resolver)
+	g.Expect(tc.Spec.PD.ResolvedImage).To(Equal("pingcap/pd:v5.0.0@sha256:pd"))
+	g.Expect(tc.PDImage()).To(Equal("pingcap/pd:v5.0.0@sha256:pd"))
+	// the digest can't be resolved, fall back to the tag
+	g.Expect(tc.Spec.TiKV.ResolvedImage).To(Equal("pingcap/tikv:v5.0.1"))
+	g.Expect(tc.TiKVImage()).To(Equal("pingcap/tikv:v5.0.1"))
+	// the image follows the tag with Always
+	g.Expect(tc.Spec.TiDB.ResolvedImage).To(Equal("pingcap/tidb:v4.0.0"))
+
+	// resolved again after the version is changed
+	tc.Spec.Version = "v5.0.1"
+	resolver["pingcap/pd:v5.0.1"] = "sha256:pd-new"
+	SetTidbClusterImages(tc, resolver)
+	g.Expect(tc.PDImage()).To(Equal("pingcap/pd:v5.0.1@sha256:pd-new"))
+
+	// the stale resolved image is ignored
+	tc.Spec.Version = "v5.0.2"
+	g.Expect(tc.PDImage()).To(Equal("pingcap/pd:v5.0.2"))
+}
+
 func newTidbCluster() *v1alpha1.TidbCluster {
 	return &v1alpha1.TidbCluster{
 		Spec: v1alpha1.TidbClusterSpec{
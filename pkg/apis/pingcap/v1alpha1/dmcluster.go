@@ -181,6 +181,14 @@ func (dc *DMCluster) MasterScaling() bool {
 	return dc.Status.Master.Phase == ScalePhase
 }
 
+func (dc *DMCluster) WorkerUpgrading() bool {
+	return dc.Status.Worker.Phase == UpgradePhase
+}
+
+func (dc *DMCluster) WorkerScaling() bool {
+	return dc.Status.Worker.Phase == ScalePhase
+}
+
 func (dc *DMCluster) getDeleteSlots(component string) (deleteSlots sets.Int32) {
 	deleteSlots = sets.NewInt32()
 	annotations := dc.GetAnnotations()
@@ -29,9 +29,12 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 	return map[string]common.OpenAPIDefinition{
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.AutoResource":                  schema_pkg_apis_pingcap_v1alpha1_AutoResource(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.AutoRule":                      schema_pkg_apis_pingcap_v1alpha1_AutoRule(ref),
+		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.AutoScalingRecommendation":     schema_pkg_apis_pingcap_v1alpha1_AutoScalingRecommendation(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BRConfig":                      schema_pkg_apis_pingcap_v1alpha1_BRConfig(ref),
+		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BackoffRetryPolicy":            schema_pkg_apis_pingcap_v1alpha1_BackoffRetryPolicy(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.Backup":                        schema_pkg_apis_pingcap_v1alpha1_Backup(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BackupList":                    schema_pkg_apis_pingcap_v1alpha1_BackupList(ref),
+		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BackupPodTemplate":             schema_pkg_apis_pingcap_v1alpha1_BackupPodTemplate(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BackupSchedule":                schema_pkg_apis_pingcap_v1alpha1_BackupSchedule(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BackupScheduleList":            schema_pkg_apis_pingcap_v1alpha1_BackupScheduleList(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BackupScheduleSpec":            schema_pkg_apis_pingcap_v1alpha1_BackupScheduleSpec(ref),
@@ -54,6 +57,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.DiscoverySpec":                 schema_pkg_apis_pingcap_v1alpha1_DiscoverySpec(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.DumplingConfig":                schema_pkg_apis_pingcap_v1alpha1_DumplingConfig(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.Experimental":                  schema_pkg_apis_pingcap_v1alpha1_Experimental(ref),
+		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.ExternalClusterSpec":           schema_pkg_apis_pingcap_v1alpha1_ExternalClusterSpec(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.ExternalConfig":                schema_pkg_apis_pingcap_v1alpha1_ExternalConfig(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.ExternalEndpoint":              schema_pkg_apis_pingcap_v1alpha1_ExternalEndpoint(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.FileLogConfig":                 schema_pkg_apis_pingcap_v1alpha1_FileLogConfig(ref),
@@ -66,6 +70,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.GcsStorageProvider":            schema_pkg_apis_pingcap_v1alpha1_GcsStorageProvider(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.HelperSpec":                    schema_pkg_apis_pingcap_v1alpha1_HelperSpec(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.IngressSpec":                   schema_pkg_apis_pingcap_v1alpha1_IngressSpec(ref),
+		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.InitSqlFailure":                schema_pkg_apis_pingcap_v1alpha1_InitSqlFailure(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.IsolationRead":                 schema_pkg_apis_pingcap_v1alpha1_IsolationRead(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.Log":                           schema_pkg_apis_pingcap_v1alpha1_Log(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.LogTailerSpec":                 schema_pkg_apis_pingcap_v1alpha1_LogTailerSpec(ref),
@@ -110,6 +115,8 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.ServiceSpec":                   schema_pkg_apis_pingcap_v1alpha1_ServiceSpec(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.Status":                        schema_pkg_apis_pingcap_v1alpha1_Status(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.StmtSummary":                   schema_pkg_apis_pingcap_v1alpha1_StmtSummary(ref),
+		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.StorageAutoScalerSpec":         schema_pkg_apis_pingcap_v1alpha1_StorageAutoScalerSpec(ref),
+		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.StorageAutoScalerStatus":       schema_pkg_apis_pingcap_v1alpha1_StorageAutoScalerStatus(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.StorageClaim":                  schema_pkg_apis_pingcap_v1alpha1_StorageClaim(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.StorageProvider":               schema_pkg_apis_pingcap_v1alpha1_StorageProvider(ref),
 		"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TLSConfig":                     schema_pkg_apis_pingcap_v1alpha1_TLSConfig(ref),
@@ -512,6 +519,70 @@ func schema_pkg_apis_pingcap_v1alpha1_AutoRule(ref common.ReferenceCallback) com
 	}
 }
 
+func schema_pkg_apis_pingcap_v1alpha1_AutoScalingRecommendation(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AutoScalingRecommendation describes an auto-scaling evaluation",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"component": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Component is the component(tidb/tikv) evaluated",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"group": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Group is the auto-scaling group evaluated",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"currentReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CurrentReplicas is the replicas of the group when evaluated",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"recommendedReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RecommendedReplicas is the replicas recommended by the evaluation",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"decision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Decision is the decision of the evaluation",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message describes the metric values and the thresholds of the evaluation",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"timestamp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timestamp is the time of the evaluation",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"component", "group", "currentReplicas", "recommendedReplicas", "decision", "timestamp"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_pingcap_v1alpha1_BRConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -624,6 +695,40 @@ func schema_pkg_apis_pingcap_v1alpha1_BRConfig(ref common.ReferenceCallback) com
 	}
 }
 
+func schema_pkg_apis_pingcap_v1alpha1_BackoffRetryPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "BackoffRetryPolicy is the backoff retry policy of a backup whose job fails with a retriable error. The n-th retry is done at least MinRetryDuration * 2^(n-1) after the failure is detected.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maxRetryTimes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxRetryTimes is the max times of retry, the backup is not retried if it is 0. Optional: Defaults to 2",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"minRetryDuration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MinRetryDuration is the min duration of the backoff, in the format of Go Duration, e.g. 300s Optional: Defaults to 300s",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"retryTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RetryTimeout is the timeout of retry since the first failure is detected, in the format of Go Duration, e.g. 30m. The backup is not retried any more after the timeout. Optional: Defaults to 30m",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_pingcap_v1alpha1_Backup(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -701,6 +806,118 @@ func schema_pkg_apis_pingcap_v1alpha1_BackupList(ref common.ReferenceCallback) c
 	}
 }
 
+func schema_pkg_apis_pingcap_v1alpha1_BackupPodTemplate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "BackupPodTemplate is the customization of the Pods of the backup and restore Jobs. The fields required by the operator, e.g. the service account, the args and env of the main container, take precedence over it on conflict.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"labels": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Labels of the Pods, the labels set by the operator are not overridden",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"annotations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Annotations of the Pods, the annotations set by the operator are not overridden",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"affinity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Affinity of the Pods, it overrides `spec.affinity`",
+							Ref:         ref("k8s.io/api/core/v1.Affinity"),
+						},
+					},
+					"tolerations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tolerations of the Pods, they are appended to `spec.tolerations`",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.Toleration"),
+									},
+								},
+							},
+						},
+					},
+					"nodeSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NodeSelector of the Pods",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"priorityClassName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PriorityClassName of the Pods, it overrides `spec.priorityClassName`",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"additionalContainers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AdditionalContainers are appended to the containers of the Pods, e.g. sidecars, the containers with the same names as the ones of the operator are ignored",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.Container"),
+									},
+								},
+							},
+						},
+					},
+					"additionalVolumes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AdditionalVolumes are appended to the volumes of the Pods, the volumes with the same names as the ones of the operator are ignored",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.Volume"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.Affinity", "k8s.io/api/core/v1.Container", "k8s.io/api/core/v1.Toleration", "k8s.io/api/core/v1.Volume"},
+	}
+}
+
 func schema_pkg_apis_pingcap_v1alpha1_BackupSchedule(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -806,7 +1023,7 @@ func schema_pkg_apis_pingcap_v1alpha1_BackupScheduleSpec(ref common.ReferenceCal
 					},
 					"maxBackups": {
 						SchemaProps: spec.SchemaProps{
-							Description: "MaxBackups is to specify how many backups we want to keep 0 is magic number to indicate un-limited backups. if MaxBackups and MaxReservedTime are set at the same time, MaxReservedTime is preferred and MaxBackups is ignored.",
+							Description: "MaxBackups is to specify how many backups we want to keep 0 is magic number to indicate un-limited backups. if several of MaxBackups, MaxReservedTime and MaxReservedSpace are set at the same time, a backup is deleted if any of them requires, i.e. the most aggressive one wins. The most recent successful backup is never deleted.",
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
@@ -818,6 +1035,12 @@ func schema_pkg_apis_pingcap_v1alpha1_BackupScheduleSpec(ref common.ReferenceCal
 							Format:      "",
 						},
 					},
+					"maxReservedSpace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxReservedSpace is to specify the max total size of the completed backups we want to keep, the oldest backups exceeding it are deleted.",
+							Ref:         ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+						},
+					},
 					"backupTemplate": {
 						SchemaProps: spec.SchemaProps{
 							Description: "BackupTemplate is the specification of the backup structure to get scheduled.",
@@ -851,12 +1074,19 @@ func schema_pkg_apis_pingcap_v1alpha1_BackupScheduleSpec(ref common.ReferenceCal
 							},
 						},
 					},
+					"concurrencyPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConcurrencyPolicy specifies how to treat a scheduled backup when the last backup is still running, valid values are Allow, Forbid and Replace. If it's not set, the scheduled backup is delayed until the last backup finishes.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"schedule", "backupTemplate"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BackupSpec", "k8s.io/api/core/v1.LocalObjectReference"},
+			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BackupSpec", "k8s.io/api/core/v1.LocalObjectReference", "k8s.io/apimachinery/pkg/api/resource.Quantity"},
 	}
 }
 
@@ -1039,11 +1269,23 @@ func schema_pkg_apis_pingcap_v1alpha1_BackupSpec(ref common.ReferenceCallback) c
 							Format:      "",
 						},
 					},
+					"backoffRetryPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BackoffRetryPolicy the backoff retry policy, currently only valid for snapshot backup with BR",
+							Ref:         ref("github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BackoffRetryPolicy"),
+						},
+					},
+					"podTemplate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodTemplate customizes the Pods of the backup Jobs",
+							Ref:         ref("github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BackupPodTemplate"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BRConfig", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.CleanOption", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.DumplingConfig", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.GcsStorageProvider", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.LocalStorageProvider", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.S3StorageProvider", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TiDBAccessConfig", "k8s.io/api/core/v1.Affinity", "k8s.io/api/core/v1.EnvVar", "k8s.io/api/core/v1.LocalObjectReference", "k8s.io/api/core/v1.PodSecurityContext", "k8s.io/api/core/v1.ResourceRequirements", "k8s.io/api/core/v1.Toleration"},
+			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BRConfig", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BackoffRetryPolicy", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BackupPodTemplate", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.CleanOption", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.DumplingConfig", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.GcsStorageProvider", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.LocalStorageProvider", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.S3StorageProvider", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TiDBAccessConfig", "k8s.io/api/core/v1.Affinity", "k8s.io/api/core/v1.EnvVar", "k8s.io/api/core/v1.LocalObjectReference", "k8s.io/api/core/v1.PodSecurityContext", "k8s.io/api/core/v1.ResourceRequirements", "k8s.io/api/core/v1.Toleration"},
 	}
 }
 
@@ -1150,6 +1392,18 @@ func schema_pkg_apis_pingcap_v1alpha1_BasicAutoScalerStatus(ref common.Reference
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
+					"lastScaleOutTimestamp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastScaleOutTimestamp describes the last auto-scaling-out timestamp for the component(tidb/tikv)",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastScaleInTimestamp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastScaleInTimestamp describes the last auto-scaling-in timestamp for the component(tidb/tikv)",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
 				},
 			},
 		},
@@ -1383,6 +1637,13 @@ func schema_pkg_apis_pingcap_v1alpha1_ComponentSpec(ref common.ReferenceCallback
 							Format:      "",
 						},
 					},
+					"resolvedImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResolvedImage is the image of the component resolved by the admission webhook from the image, baseImage and version, it may be pinned to a digest. It's set by the webhook and ignored if it's not resolved from the current image, baseImage and version.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"imagePullPolicy": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ImagePullPolicy of the component. Override the cluster-level imagePullPolicy if present Optional: Defaults to cluster-level setting",
@@ -1580,6 +1841,13 @@ func schema_pkg_apis_pingcap_v1alpha1_ComponentSpec(ref common.ReferenceCallback
 							Format:      "",
 						},
 					},
+					"podManagementPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodManagementPolicy of the StatefulSet of the component. The field is immutable and only takes effect when the StatefulSet is created, a change of an existing StatefulSet is reported by the condition PodManagementPolicyNotApplied. It can not be set for PD. Optional: Defaults to Parallel, and OrderedReady for Pump",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"topologySpreadConstraints": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -2068,6 +2336,13 @@ func schema_pkg_apis_pingcap_v1alpha1_DiscoverySpec(ref common.ReferenceCallback
 							},
 						},
 					},
+					"tlsSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TLSSecret is the name of the TLS Secret in the namespace of the cluster to serve the discovery service with HTTPS. The Secret must contain `tls.crt`, `tls.key` and `ca.crt`, the certificate must be valid for `<cluster>-discovery.<namespace>` and `<cluster>-discovery.<namespace>.svc`. The `ca.crt` is mounted to the components to verify the discovery service. Changing this will cause a rolling-update of the components requesting the discovery service.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -2144,6 +2419,49 @@ func schema_pkg_apis_pingcap_v1alpha1_Experimental(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_pingcap_v1alpha1_ExternalClusterSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ExternalClusterSpec describes the heterogeneous TidbCluster whose tidb is auto-scaled",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace is the namespace of the TidbCluster, default to the same namespace with TidbClusterAutoScaler",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the TidbCluster",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"template": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Template is the tidb spec of the TidbCluster when it's created by the auto-scaler, the tidb spec of the target TidbCluster is used if not set",
+							Ref:         ref("github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TiDBSpec"),
+						},
+					},
+					"garbageCollect": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GarbageCollect makes the TidbCluster created by the auto-scaler deleted with the TidbClusterAutoScaler, the TidbCluster should be in the same namespace with TidbClusterAutoScaler",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TiDBSpec"},
+	}
+}
+
 func schema_pkg_apis_pingcap_v1alpha1_ExternalConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2846,6 +3164,41 @@ func schema_pkg_apis_pingcap_v1alpha1_IngressSpec(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_pingcap_v1alpha1_InitSqlFailure(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "InitSqlFailure is a statement failing in a SQL file of TidbInitializer",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"file": {
+						SchemaProps: spec.SchemaProps{
+							Description: "File is the file of the statement, <configmap name>/<key>",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"statementIndex": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StatementIndex is the index of the statement in the file, starting from 0",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is the error returned by TiDB",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"file", "statementIndex"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_pingcap_v1alpha1_IsolationRead(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3156,6 +3509,13 @@ func schema_pkg_apis_pingcap_v1alpha1_MasterSpec(ref common.ReferenceCallback) c
 							Format:      "",
 						},
 					},
+					"resolvedImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResolvedImage is the image of the component resolved by the admission webhook from the image, baseImage and version, it may be pinned to a digest. It's set by the webhook and ignored if it's not resolved from the current image, baseImage and version.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"imagePullPolicy": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ImagePullPolicy of the component. Override the cluster-level imagePullPolicy if present Optional: Defaults to cluster-level setting",
@@ -3353,6 +3713,13 @@ func schema_pkg_apis_pingcap_v1alpha1_MasterSpec(ref common.ReferenceCallback) c
 							Format:      "",
 						},
 					},
+					"podManagementPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodManagementPolicy of the StatefulSet of the component. The field is immutable and only takes effect when the StatefulSet is created, a change of an existing StatefulSet is reported by the condition PodManagementPolicyNotApplied. It can not be set for PD. Optional: Defaults to Parallel, and OrderedReady for Pump",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"topologySpreadConstraints": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -4355,6 +4722,13 @@ func schema_pkg_apis_pingcap_v1alpha1_PDSpec(ref common.ReferenceCallback) commo
 							Format:      "",
 						},
 					},
+					"resolvedImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResolvedImage is the image of the component resolved by the admission webhook from the image, baseImage and version, it may be pinned to a digest. It's set by the webhook and ignored if it's not resolved from the current image, baseImage and version.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"imagePullPolicy": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ImagePullPolicy of the component. Override the cluster-level imagePullPolicy if present Optional: Defaults to cluster-level setting",
@@ -4552,6 +4926,13 @@ func schema_pkg_apis_pingcap_v1alpha1_PDSpec(ref common.ReferenceCallback) commo
 							Format:      "",
 						},
 					},
+					"podManagementPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodManagementPolicy of the StatefulSet of the component. The field is immutable and only takes effect when the StatefulSet is created, a change of an existing StatefulSet is reported by the condition PodManagementPolicyNotApplied. It can not be set for PD. Optional: Defaults to Parallel, and OrderedReady for Pump",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"topologySpreadConstraints": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -4635,6 +5016,55 @@ func schema_pkg_apis_pingcap_v1alpha1_PDSpec(ref common.ReferenceCallback) commo
 							Format:      "int32",
 						},
 					},
+					"failoverEventInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailoverEventInterval is the interval in which a failover event of a member of PD is emitted once, e.g. the member staying unhealthy is reported once in every interval, in the format of Go Duration. Optional: Defaults to the --event-dedup-window of the controller manager, which is also used if the interval is 0, throttling can't be turned off for the component.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"failoverMode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailoverMode is the mode of the failover of PD. `Full` deletes the failure member from the PD cluster and deletes its Pod and PVCs, `PodOnly` only deletes the Pod of the failure member. Optional: Defaults to Full",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"waitForMemberDeleted": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WaitForMemberDeleted makes the failover of PD re-check via the PD API that the failure member has left the PD cluster before deleting its PVCs, and requeue until it has left. Optional: Defaults to false",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"preservePodOnFailover": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PreservePodOnFailover makes the failover of PD preserve the Pod and PVCs of the failure member for inspection instead of deleting them. The Pod is released from the StatefulSet and its ordinal is added to the PD delete slots, so the replacement is created with a new ordinal. The preserved Pod and the delete slot should be removed manually after the inspection. It requires the AdvancedStatefulSet feature, the Pod is deleted otherwise. Optional: Defaults to false",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"gracePeriodSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GracePeriodSeconds is the grace period of deleting the Pod of the failure member in the failover of PD. 0 deletes the Pod immediately, which speeds up the recovery of a truly dead Pod. Optional: Defaults to the terminationGracePeriodSeconds of the Pod",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"protectedMemberIDs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ProtectedMemberIDs are the IDs of the PD members which are never failed over, e.g. a witness PD. A protected member is still reported unhealthy, but it's never marked as failure and deleted.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
 					"storageClassName": {
 						SchemaProps: spec.SchemaProps{
 							Description: "The storageClassName of the persistent volume for PD data storage. Defaults to Kubernetes default storage class.",
@@ -5141,7 +5571,14 @@ func schema_pkg_apis_pingcap_v1alpha1_PumpSpec(ref common.ReferenceCallback) com
 				Properties: map[string]spec.Schema{
 					"version": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Version of the component. Override the cluster-level version if non-empty Optional: Defaults to cluster-level setting",
+							Description: "Version of the component. Override the cluster-level version if non-empty Optional: Defaults to cluster-level setting",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resolvedImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResolvedImage is the image of the component resolved by the admission webhook from the image, baseImage and version, it may be pinned to a digest. It's set by the webhook and ignored if it's not resolved from the current image, baseImage and version.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -5343,6 +5780,13 @@ func schema_pkg_apis_pingcap_v1alpha1_PumpSpec(ref common.ReferenceCallback) com
 							Format:      "",
 						},
 					},
+					"podManagementPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodManagementPolicy of the StatefulSet of the component. The field is immutable and only takes effect when the StatefulSet is created, a change of an existing StatefulSet is reported by the condition PodManagementPolicyNotApplied. It can not be set for PD. Optional: Defaults to Parallel, and OrderedReady for Pump",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"topologySpreadConstraints": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -5618,6 +6062,12 @@ func schema_pkg_apis_pingcap_v1alpha1_RemoteWriteSpec(ref common.ReferenceCallba
 							Format:      "",
 						},
 					},
+					"bearerTokenSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The secret in the namespace of the TidbMonitor that contains the bearer token for remote write, it takes precedence over BearerTokenFile.",
+							Ref:         ref("k8s.io/api/core/v1.SecretKeySelector"),
+						},
+					},
 					"tlsConfig": {
 						SchemaProps: spec.SchemaProps{
 							Description: "TLS Config to use for remote write.",
@@ -5641,7 +6091,7 @@ func schema_pkg_apis_pingcap_v1alpha1_RemoteWriteSpec(ref common.ReferenceCallba
 			},
 		},
 		Dependencies: []string{
-			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BasicAuth", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.QueueConfig", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.RelabelConfig", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TLSConfig"},
+			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BasicAuth", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.QueueConfig", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.RelabelConfig", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TLSConfig", "k8s.io/api/core/v1.SecretKeySelector"},
 	}
 }
 
@@ -5882,11 +6332,31 @@ func schema_pkg_apis_pingcap_v1alpha1_RestoreSpec(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"verify": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Verify is the way to verify the restored data after the restore succeeds, only `checksum` is supported now. The restore is complete after the verification passes. It's only supported by BR restore.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"verifyMaxTableSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VerifyMaxTableSize is the max size of the tables to verify, e.g. 100Gi, the larger tables are skipped. Defaults to no limit.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"podTemplate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodTemplate customizes the Pods of the restore Jobs",
+							Ref:         ref("github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BackupPodTemplate"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BRConfig", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.GcsStorageProvider", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.LocalStorageProvider", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.S3StorageProvider", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TiDBAccessConfig", "k8s.io/api/core/v1.Affinity", "k8s.io/api/core/v1.EnvVar", "k8s.io/api/core/v1.LocalObjectReference", "k8s.io/api/core/v1.PodSecurityContext", "k8s.io/api/core/v1.ResourceRequirements", "k8s.io/api/core/v1.Toleration"},
+			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BRConfig", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.BackupPodTemplate", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.GcsStorageProvider", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.LocalStorageProvider", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.S3StorageProvider", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TiDBAccessConfig", "k8s.io/api/core/v1.Affinity", "k8s.io/api/core/v1.EnvVar", "k8s.io/api/core/v1.LocalObjectReference", "k8s.io/api/core/v1.PodSecurityContext", "k8s.io/api/core/v1.ResourceRequirements", "k8s.io/api/core/v1.Toleration"},
 	}
 }
 
@@ -6296,6 +6766,104 @@ func schema_pkg_apis_pingcap_v1alpha1_StmtSummary(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_pingcap_v1alpha1_StorageAutoScalerSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StorageAutoScalerSpec describes the spec for tikv auto-scaling on the storage pressure. The tikv is never scaled in on the storage usage.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"usedThresholdPercent": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UsedThresholdPercent is the used percentage of the storage of a tikv store, the tikv scales out when the max used percentage of the stores exceeds it",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"stabilizationWindowSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StabilizationWindowSeconds represents the duration seconds that the storage usage should keep exceeding the threshold before scaling out If not set, the default StabilizationWindowSeconds will be set to 300",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"maxReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxReplicas is the upper limit for the number of replicas to which the autoscaler can scale out",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"usedThresholdPercent", "maxReplicas"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_pingcap_v1alpha1_StorageAutoScalerStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StorageAutoScalerStatus describe the inputs of the evaluation of the storage pressure of tikv",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"storeCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StoreCount is the count of the tikv stores that are evaluated",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"maxUsedStoreID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxUsedStoreID is the id of the store with the max used percentage of the storage",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"maxUsedPercent": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxUsedPercent is the max used percentage of the storage of the stores",
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+					"capacityBytes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CapacityBytes is the storage capacity of the store with the max used percentage",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"availableBytes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AvailableBytes is the available storage of the store with the max used percentage",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"thresholdExceededTimestamp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ThresholdExceededTimestamp is the time since when the max used percentage keeps exceeding the threshold",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastEvaluationTimestamp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastEvaluationTimestamp is the time of the last evaluation",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"storeCount", "maxUsedPercent"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_pingcap_v1alpha1_StorageClaim(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -6477,6 +7045,13 @@ func schema_pkg_apis_pingcap_v1alpha1_TiCDCSpec(ref common.ReferenceCallback) co
 							Format:      "",
 						},
 					},
+					"resolvedImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResolvedImage is the image of the component resolved by the admission webhook from the image, baseImage and version, it may be pinned to a digest. It's set by the webhook and ignored if it's not resolved from the current image, baseImage and version.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"imagePullPolicy": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ImagePullPolicy of the component. Override the cluster-level imagePullPolicy if present Optional: Defaults to cluster-level setting",
@@ -6674,6 +7249,13 @@ func schema_pkg_apis_pingcap_v1alpha1_TiCDCSpec(ref common.ReferenceCallback) co
 							Format:      "",
 						},
 					},
+					"podManagementPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodManagementPolicy of the StatefulSet of the component. The field is immutable and only takes effect when the StatefulSet is created, a change of an existing StatefulSet is reported by the condition PodManagementPolicyNotApplied. It can not be set for PD. Optional: Defaults to Parallel, and OrderedReady for Pump",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"topologySpreadConstraints": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -7286,6 +7868,13 @@ func schema_pkg_apis_pingcap_v1alpha1_TiDBSpec(ref common.ReferenceCallback) com
 							Format:      "",
 						},
 					},
+					"resolvedImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResolvedImage is the image of the component resolved by the admission webhook from the image, baseImage and version, it may be pinned to a digest. It's set by the webhook and ignored if it's not resolved from the current image, baseImage and version.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"imagePullPolicy": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ImagePullPolicy of the component. Override the cluster-level imagePullPolicy if present Optional: Defaults to cluster-level setting",
@@ -7483,6 +8072,13 @@ func schema_pkg_apis_pingcap_v1alpha1_TiDBSpec(ref common.ReferenceCallback) com
 							Format:      "",
 						},
 					},
+					"podManagementPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodManagementPolicy of the StatefulSet of the component. The field is immutable and only takes effect when the StatefulSet is created, a change of an existing StatefulSet is reported by the condition PodManagementPolicyNotApplied. It can not be set for PD. Optional: Defaults to Parallel, and OrderedReady for Pump",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"topologySpreadConstraints": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -7573,6 +8169,13 @@ func schema_pkg_apis_pingcap_v1alpha1_TiDBSpec(ref common.ReferenceCallback) com
 							Format:      "int32",
 						},
 					},
+					"failoverEventInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailoverEventInterval is the interval in which a failover event of a member of TiDB is emitted once, e.g. the member staying unhealthy is reported once in every interval, in the format of Go Duration. Optional: Defaults to the --event-dedup-window of the controller manager, which is also used if the interval is 0, throttling can't be turned off for the component.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"separateSlowLog": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Whether output the slow log in an separate sidecar container Optional: Defaults to true",
@@ -7701,6 +8304,13 @@ func schema_pkg_apis_pingcap_v1alpha1_TiFlashSpec(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"resolvedImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResolvedImage is the image of the component resolved by the admission webhook from the image, baseImage and version, it may be pinned to a digest. It's set by the webhook and ignored if it's not resolved from the current image, baseImage and version.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"imagePullPolicy": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ImagePullPolicy of the component. Override the cluster-level imagePullPolicy if present Optional: Defaults to cluster-level setting",
@@ -7898,6 +8508,13 @@ func schema_pkg_apis_pingcap_v1alpha1_TiFlashSpec(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"podManagementPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodManagementPolicy of the StatefulSet of the component. The field is immutable and only takes effect when the StatefulSet is created, a change of an existing StatefulSet is reported by the condition PodManagementPolicyNotApplied. It can not be set for PD. Optional: Defaults to Parallel, and OrderedReady for Pump",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"topologySpreadConstraints": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -7982,6 +8599,13 @@ func schema_pkg_apis_pingcap_v1alpha1_TiFlashSpec(ref common.ReferenceCallback)
 							Format:      "int32",
 						},
 					},
+					"failoverEventInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailoverEventInterval is the interval in which a failover event of a member of TiFlash is emitted once, e.g. the member staying unhealthy is reported once in every interval, in the format of Go Duration. Optional: Defaults to the --event-dedup-window of the controller manager, which is also used if the interval is 0, throttling can't be turned off for the component.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"storageClaims": {
 						SchemaProps: spec.SchemaProps{
 							Description: "The persistent volume claims of the TiFlash data storages. TiFlash supports multiple disks.",
@@ -9993,6 +10617,13 @@ func schema_pkg_apis_pingcap_v1alpha1_TiKVSpec(ref common.ReferenceCallback) com
 							Format:      "",
 						},
 					},
+					"resolvedImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResolvedImage is the image of the component resolved by the admission webhook from the image, baseImage and version, it may be pinned to a digest. It's set by the webhook and ignored if it's not resolved from the current image, baseImage and version.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"imagePullPolicy": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ImagePullPolicy of the component. Override the cluster-level imagePullPolicy if present Optional: Defaults to cluster-level setting",
@@ -10190,6 +10821,13 @@ func schema_pkg_apis_pingcap_v1alpha1_TiKVSpec(ref common.ReferenceCallback) com
 							Format:      "",
 						},
 					},
+					"podManagementPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodManagementPolicy of the StatefulSet of the component. The field is immutable and only takes effect when the StatefulSet is created, a change of an existing StatefulSet is reported by the condition PodManagementPolicyNotApplied. It can not be set for PD. Optional: Defaults to Parallel, and OrderedReady for Pump",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"topologySpreadConstraints": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -10274,6 +10912,13 @@ func schema_pkg_apis_pingcap_v1alpha1_TiKVSpec(ref common.ReferenceCallback) com
 							Format:      "int32",
 						},
 					},
+					"failoverEventInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailoverEventInterval is the interval in which a failover event of a member of TiKV is emitted once, e.g. the member staying unhealthy is reported once in every interval, in the format of Go Duration. Optional: Defaults to the --event-dedup-window of the controller manager, which is also used if the interval is 0, throttling can't be turned off for the component.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"separateRocksDBLog": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Whether output the RocksDB log in a separate sidecar container Optional: Defaults to false",
@@ -10321,6 +10966,13 @@ func schema_pkg_apis_pingcap_v1alpha1_TiKVSpec(ref common.ReferenceCallback) com
 							Format:      "",
 						},
 					},
+					"paused": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Indicates that the tikv cluster is paused and will not be processed by the controller, the other components are still processed.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"mountClusterClientSecret": {
 						SchemaProps: spec.SchemaProps{
 							Description: "MountClusterClientSecret indicates whether to mount `cluster-client-secret` to the Pod",
@@ -10369,12 +11021,18 @@ func schema_pkg_apis_pingcap_v1alpha1_TiKVSpec(ref common.ReferenceCallback) com
 							Format:      "",
 						},
 					},
+					"scalePolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ScalePolicy is the scale configuration for TiKV",
+							Ref:         ref("github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TiKVScalePolicy"),
+						},
+					},
 				},
 				Required: []string{"replicas"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.LogTailerSpec", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.StorageVolume", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TiKVConfigWraper", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TopologySpreadConstraint", "k8s.io/api/core/v1.Affinity", "k8s.io/api/core/v1.Container", "k8s.io/api/core/v1.EnvVar", "k8s.io/api/core/v1.LocalObjectReference", "k8s.io/api/core/v1.PodSecurityContext", "k8s.io/api/core/v1.Toleration", "k8s.io/api/core/v1.Volume", "k8s.io/api/core/v1.VolumeMount", "k8s.io/apimachinery/pkg/api/resource.Quantity"},
+			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.LogTailerSpec", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.StorageVolume", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TiKVConfigWraper", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TiKVScalePolicy", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TopologySpreadConstraint", "k8s.io/api/core/v1.Affinity", "k8s.io/api/core/v1.Container", "k8s.io/api/core/v1.EnvVar", "k8s.io/api/core/v1.LocalObjectReference", "k8s.io/api/core/v1.PodSecurityContext", "k8s.io/api/core/v1.Toleration", "k8s.io/api/core/v1.Volume", "k8s.io/api/core/v1.VolumeMount", "k8s.io/apimachinery/pkg/api/resource.Quantity"},
 	}
 }
 
@@ -10724,11 +11382,17 @@ func schema_pkg_apis_pingcap_v1alpha1_TidbAutoScalerSpec(ref common.ReferenceCal
 							},
 						},
 					},
+					"externalCluster": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExternalCluster is the heterogeneous TidbCluster joining the target TidbCluster, the replicas recommended by the external service are applied to its tidb instead of the auto-created TidbCluster",
+							Ref:         ref("github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.ExternalClusterSpec"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.AutoResource", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.AutoRule", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.ExternalConfig"},
+			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.AutoResource", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.AutoRule", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.ExternalClusterSpec", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.ExternalConfig"},
 	}
 }
 
@@ -10745,6 +11409,18 @@ func schema_pkg_apis_pingcap_v1alpha1_TidbAutoScalerStatus(ref common.ReferenceC
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
+					"lastScaleOutTimestamp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastScaleOutTimestamp describes the last auto-scaling-out timestamp for the component(tidb/tikv)",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastScaleInTimestamp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastScaleInTimestamp describes the last auto-scaling-in timestamp for the component(tidb/tikv)",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
 				},
 			},
 		},
@@ -10968,11 +11644,24 @@ func schema_pkg_apis_pingcap_v1alpha1_TidbClusterAutoScalerStatus(ref common.Ref
 							},
 						},
 					},
+					"recommendations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Recommendations describes the last auto-scaling evaluations that recommend changing the replicas, the latest evaluation is the last one",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.AutoScalingRecommendation"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TidbAutoScalerStatus", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TikvAutoScalerStatus"},
+			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.AutoScalingRecommendation", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TidbAutoScalerStatus", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.TikvAutoScalerStatus"},
 	}
 }
 
@@ -11177,6 +11866,13 @@ func schema_pkg_apis_pingcap_v1alpha1_TidbClusterSpec(ref common.ReferenceCallba
 							Format:      "",
 						},
 					},
+					"terminationPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TerminationPolicy is what happens to the data when the TidbCluster is deleted. `Retain` keeps the reclaim policy of the PVs of the cluster, `Delete` restores the reclaim policy of the PVs of the cluster to Delete before the deletion completes. Optional: Defaults to Retain",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"tlsCluster": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Whether enable the TLS connection between TiDB server components Optional: Defaults to nil",
@@ -11485,6 +12181,27 @@ func schema_pkg_apis_pingcap_v1alpha1_TidbInitializerSpec(ref common.ReferenceCa
 							Format:      "",
 						},
 					},
+					"initSqlConfigMapNames": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InitSqlConfigMapNames reference the configmaps of the SQL files executed after initSql, every key of a configmap is a file of the statements ending with a semicolon at the end of a line. The files are executed in the lexical order of the configmap names and then the keys, and a file is executed once for the same content as the hash of the content executed is recorded in the status.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"rerunOnChange": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RerunOnChange is whether to execute the files of initSqlConfigMapNames again after their content is changed, only the changed files are executed. Optional: Defaults to false",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"passwordSecret": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
@@ -11584,11 +12301,32 @@ func schema_pkg_apis_pingcap_v1alpha1_TidbInitializerStatus(ref common.Reference
 							Format:      "",
 						},
 					},
+					"appliedSqlHashes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AppliedSqlHashes are the sha256 hashes of the content of the files of initSqlConfigMapNames executed, keyed by <configmap name>/<key>",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"sqlFailure": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SqlFailure is the statement of the files of initSqlConfigMapNames failing the last job",
+							Ref:         ref("github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.InitSqlFailure"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/batch/v1.JobCondition", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.InitSqlFailure", "k8s.io/api/batch/v1.JobCondition", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
@@ -11923,11 +12661,18 @@ func schema_pkg_apis_pingcap_v1alpha1_TidbMonitorSpec(ref common.ReferenceCallba
 					},
 					"shards": {
 						SchemaProps: spec.SchemaProps{
-							Description: "EXPERIMENTAL: Number of shards to distribute targets onto. Number of replicas multiplied by shards is the total number of Pods created. Note that scaling down shards will not reshard data onto remaining instances, it must be manually moved. Increasing shards will not reshard data either but it will continue to be available from the same instances. To query globally use Thanos sidecar and Thanos querier or remote write data to a central location. Sharding is done on the content of the `__address__` target meta-label.",
+							Description: "EXPERIMENTAL: Number of shards to distribute targets onto. Number of replicas multiplied by shards is the total number of Pods created. Note that scaling down shards will not reshard data onto remaining instances, it must be manually moved. Increasing shards will not reshard data either but it will continue to be available from the same instances. To query globally use Thanos sidecar and Thanos querier or remote write data to a central location. Sharding is done on the stable identity of the targets, see ShardingStrategy.",
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
 					},
+					"shardingStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ShardingStrategy is the strategy to distribute targets onto the shards. `target` distributes the targets by the hash of their namespace, cluster, component and Pod name, so a target stays on its shard when its Pod IP changes. `cluster` assigns all the targets of a cluster to the same shard, so the dashboards of a cluster query a single Prometheus. Defaults to `target`.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"additionalVolumes": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Additional volumes of component pod.",
@@ -12024,11 +12769,17 @@ func schema_pkg_apis_pingcap_v1alpha1_TikvAutoScalerSpec(ref common.ReferenceCal
 							},
 						},
 					},
+					"storage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Storage makes the auto-scaler controller able to scale out the tikv of the target TidbCluster when the storage usage of the tikv stores queried from PD exceeds the threshold",
+							Ref:         ref("github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.StorageAutoScalerSpec"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.AutoResource", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.AutoRule", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.ExternalConfig"},
+			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.AutoResource", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.AutoRule", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.ExternalConfig", "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.StorageAutoScalerSpec"},
 	}
 }
 
@@ -12045,11 +12796,29 @@ func schema_pkg_apis_pingcap_v1alpha1_TikvAutoScalerStatus(ref common.ReferenceC
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
+					"lastScaleOutTimestamp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastScaleOutTimestamp describes the last auto-scaling-out timestamp for the component(tidb/tikv)",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastScaleInTimestamp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastScaleInTimestamp describes the last auto-scaling-in timestamp for the component(tidb/tikv)",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"storage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Storage describes the inputs of the last evaluation of the storage pressure",
+							Ref:         ref("github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.StorageAutoScalerStatus"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1.StorageAutoScalerStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
@@ -12168,6 +12937,13 @@ func schema_pkg_apis_pingcap_v1alpha1_WorkerSpec(ref common.ReferenceCallback) c
 							Format:      "",
 						},
 					},
+					"resolvedImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResolvedImage is the image of the component resolved by the admission webhook from the image, baseImage and version, it may be pinned to a digest. It's set by the webhook and ignored if it's not resolved from the current image, baseImage and version.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"imagePullPolicy": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ImagePullPolicy of the component. Override the cluster-level imagePullPolicy if present Optional: Defaults to cluster-level setting",
@@ -12365,6 +13141,13 @@ func schema_pkg_apis_pingcap_v1alpha1_WorkerSpec(ref common.ReferenceCallback) c
 							Format:      "",
 						},
 					},
+					"podManagementPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodManagementPolicy of the StatefulSet of the component. The field is immutable and only takes effect when the StatefulSet is created, a change of an existing StatefulSet is reported by the condition PodManagementPolicyNotApplied. It can not be set for PD. Optional: Defaults to Parallel, and OrderedReady for Pump",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"topologySpreadConstraints": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -26,6 +26,11 @@ func (rs *Restore) GetRestoreJobName() string {
 	return fmt.Sprintf("restore-%s", rs.GetName())
 }
 
+// GetVerifyJobName return the job name verifying the restored data
+func (rs *Restore) GetVerifyJobName() string {
+	return fmt.Sprintf("restore-verify-%s", rs.GetName())
+}
+
 // GetInstanceName return the restore instance name
 func (rs *Restore) GetInstanceName() string {
 	if rs.Labels != nil {
@@ -118,3 +123,9 @@ func IsRestoreFailed(restore *Restore) bool {
 	_, condition := GetRestoreCondition(&restore.Status, RestoreFailed)
 	return condition != nil && condition.Status == corev1.ConditionTrue
 }
+
+// IsRestoreVerifying returns true if the restored data of a Restore is being verified
+func IsRestoreVerifying(restore *Restore) bool {
+	_, condition := GetRestoreCondition(&restore.Status, RestoreVerifying)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
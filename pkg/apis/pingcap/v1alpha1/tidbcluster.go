@@ -16,6 +16,7 @@ package v1alpha1
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -48,21 +49,15 @@ var (
 	defaultHelperSpec = HelperSpec{}
 )
 
-// PDImage return the image used by PD.
-//
-// If PD isn't specified, return empty string.
-func (tc *TidbCluster) PDImage() string {
-	if tc.Spec.PD == nil {
-		return ""
-	}
-
-	image := tc.Spec.PD.Image
-	baseImage := tc.Spec.PD.BaseImage
+// componentImage returns the image of the component from the image, baseImage and version,
+// the resolved image is used if it's the same image pinned to a digest.
+func componentImage(spec *ComponentSpec, baseImage, clusterVersion string) string {
+	image := spec.Image
 	// base image takes higher priority
 	if baseImage != "" {
-		version := tc.Spec.PD.Version
+		version := spec.Version
 		if version == nil {
-			version = &tc.Spec.Version
+			version = &clusterVersion
 		}
 		if *version == "" {
 			image = baseImage
@@ -70,9 +65,24 @@ func (tc *TidbCluster) PDImage() string {
 			image = fmt.Sprintf("%s:%s", baseImage, *version)
 		}
 	}
+	// the resolved image is ignored if it's stale, e.g. the version is changed without the admission webhook
+	if image != "" && strings.HasPrefix(spec.ResolvedImage, image+"@") {
+		return spec.ResolvedImage
+	}
 	return image
 }
 
+// PDImage return the image used by PD.
+//
+// If PD isn't specified, return empty string.
+func (tc *TidbCluster) PDImage() string {
+	if tc.Spec.PD == nil {
+		return ""
+	}
+
+	return componentImage(&tc.Spec.PD.ComponentSpec, tc.Spec.PD.BaseImage, tc.Spec.Version)
+}
+
 // PDVersion return the image version used by PD.
 //
 // If PD isn't specified, return empty string.
@@ -98,21 +108,7 @@ func (tc *TidbCluster) TiKVImage() string {
 		return ""
 	}
 
-	image := tc.Spec.TiKV.Image
-	baseImage := tc.Spec.TiKV.BaseImage
-	// base image takes higher priority
-	if baseImage != "" {
-		version := tc.Spec.TiKV.Version
-		if version == nil {
-			version = &tc.Spec.Version
-		}
-		if *version == "" {
-			image = baseImage
-		} else {
-			image = fmt.Sprintf("%s:%s", baseImage, *version)
-		}
-	}
-	return image
+	return componentImage(&tc.Spec.TiKV.ComponentSpec, tc.Spec.TiKV.BaseImage, tc.Spec.Version)
 }
 
 // TiKVVersion return the image version used by TiKV.
@@ -150,6 +146,18 @@ func (tc *TidbCluster) TiKVEvictLeaderTimeout() time.Duration {
 	return defaultEvictLeaderTimeout
 }
 
+// TiKVPreScaleInJobTimeout returns the timeout to wait for the pre scale-in Job of TiKV,
+// 0 means waiting without a timeout.
+func (tc *TidbCluster) TiKVPreScaleInJobTimeout() time.Duration {
+	if tc.Spec.TiKV != nil && tc.Spec.TiKV.ScalePolicy != nil && tc.Spec.TiKV.ScalePolicy.PreScaleInJobTimeout != nil {
+		d, err := time.ParseDuration(*tc.Spec.TiKV.ScalePolicy.PreScaleInJobTimeout)
+		if err == nil {
+			return d
+		}
+	}
+	return 0
+}
+
 // TiFlashImage return the image used by TiFlash.
 //
 // If TiFlash isn't specified, return empty string.
@@ -158,21 +166,7 @@ func (tc *TidbCluster) TiFlashImage() string {
 		return ""
 	}
 
-	image := tc.Spec.TiFlash.Image
-	baseImage := tc.Spec.TiFlash.BaseImage
-	// base image takes higher priority
-	if baseImage != "" {
-		version := tc.Spec.TiFlash.Version
-		if version == nil {
-			version = &tc.Spec.Version
-		}
-		if *version == "" {
-			image = baseImage
-		} else {
-			image = fmt.Sprintf("%s:%s", baseImage, *version)
-		}
-	}
-	return image
+	return componentImage(&tc.Spec.TiFlash.ComponentSpec, tc.Spec.TiFlash.BaseImage, tc.Spec.Version)
 }
 
 // TiFlashVersion returns the image version used by TiFlash.
@@ -200,21 +194,7 @@ func (tc *TidbCluster) TiCDCImage() string {
 		return ""
 	}
 
-	image := tc.Spec.TiCDC.Image
-	baseImage := tc.Spec.TiCDC.BaseImage
-	// base image takes higher priority
-	if baseImage != "" {
-		version := tc.Spec.TiCDC.Version
-		if version == nil {
-			version = &tc.Spec.Version
-		}
-		if *version == "" {
-			image = baseImage
-		} else {
-			image = fmt.Sprintf("%s:%s", baseImage, *version)
-		}
-	}
-	return image
+	return componentImage(&tc.Spec.TiCDC.ComponentSpec, tc.Spec.TiCDC.BaseImage, tc.Spec.Version)
 }
 
 func (tc *TidbCluster) TiFlashContainerPrivilege() *bool {
@@ -233,21 +213,24 @@ func (tc *TidbCluster) TiDBImage() string {
 		return ""
 	}
 
-	image := tc.Spec.TiDB.Image
-	baseImage := tc.Spec.TiDB.BaseImage
-	// base image takes higher priority
-	if baseImage != "" {
-		version := tc.Spec.TiDB.Version
-		if version == nil {
-			version = &tc.Spec.Version
-		}
-		if *version == "" {
-			image = baseImage
-		} else {
-			image = fmt.Sprintf("%s:%s", baseImage, *version)
-		}
+	return componentImage(&tc.Spec.TiDB.ComponentSpec, tc.Spec.TiDB.BaseImage, tc.Spec.Version)
+}
+
+// TiDBVersion return the image version used by TiDB.
+//
+// If TiDB isn't specified, return empty string.
+func (tc *TidbCluster) TiDBVersion() string {
+	if tc.Spec.TiDB == nil {
+		return ""
 	}
-	return image
+
+	image := tc.TiDBImage()
+	colonIdx := strings.LastIndexByte(image, ':')
+	if colonIdx >= 0 {
+		return image[colonIdx+1:]
+	}
+
+	return "latest"
 }
 
 // PumpImage return the image used by Pump.
@@ -258,20 +241,7 @@ func (tc *TidbCluster) PumpImage() *string {
 		return nil
 	}
 
-	image := tc.Spec.Pump.Image
-	baseImage := tc.Spec.Pump.BaseImage
-	// base image takes higher priority
-	if baseImage != "" {
-		version := tc.Spec.Pump.Version
-		if version == nil {
-			version = &tc.Spec.Version
-		}
-		if *version == "" {
-			image = baseImage
-		} else {
-			image = fmt.Sprintf("%s:%s", baseImage, *version)
-		}
-	}
+	image := componentImage(&tc.Spec.Pump.ComponentSpec, tc.Spec.Pump.BaseImage, tc.Spec.Version)
 	return &image
 }
 
@@ -420,6 +390,28 @@ func (tc *TidbCluster) PDAutoFailovering() bool {
 	return false
 }
 
+// PDFailoverTriggered returns the name of the PD pod whose member is requested to be failed over on demand
+// by the tidb.pingcap.com/pd-trigger-failover annotation, and whether the annotation is set
+func (tc *TidbCluster) PDFailoverTriggered() (string, bool) {
+	podName, ok := tc.Annotations[label.AnnPDTriggerFailoverKey]
+	return podName, ok
+}
+
+// ShouldPauseReconcile returns whether the reconciliation of the component is paused, either by spec.paused
+// for the whole cluster or by the paused field of the component, e.g. spec.tikv.paused. The status of a
+// paused component is still synced from the observations, only the mutations are skipped.
+func (tc *TidbCluster) ShouldPauseReconcile(component MemberType) bool {
+	if tc.Spec.Paused {
+		return true
+	}
+	switch component {
+	case TiKVMemberType:
+		return tc.Spec.TiKV != nil && tc.Spec.TiKV.Paused
+	default:
+		return false
+	}
+}
+
 func (tc *TidbCluster) GetPDDeletedFailureReplicas() int32 {
 	var deteledReplicas int32 = 0
 	for _, failureMember := range tc.Status.PD.FailureMembers {
@@ -430,6 +422,94 @@ func (tc *TidbCluster) GetPDDeletedFailureReplicas() int32 {
 	return deteledReplicas
 }
 
+// AllFailureMembers returns the failure members of PD, TiKV, TiDB and TiFlash,
+// ordered by the component in that order and then by the pod name.
+func (tc *TidbCluster) AllFailureMembers() []FailureMember {
+	var pd, tikv, tidb, tiflash []FailureMember
+	for _, m := range tc.Status.PD.FailureMembers {
+		pd = append(pd, FailureMember{Component: PDMemberType, PodName: m.PodName, ID: m.MemberID, Deleted: m.MemberDeleted})
+	}
+	for _, s := range tc.Status.TiKV.FailureStores {
+		tikv = append(tikv, FailureMember{Component: TiKVMemberType, PodName: s.PodName, ID: s.StoreID})
+	}
+	for _, m := range tc.Status.TiDB.FailureMembers {
+		tidb = append(tidb, FailureMember{Component: TiDBMemberType, PodName: m.PodName})
+	}
+	for _, s := range tc.Status.TiFlash.FailureStores {
+		tiflash = append(tiflash, FailureMember{Component: TiFlashMemberType, PodName: s.PodName, ID: s.StoreID})
+	}
+
+	var members []FailureMember
+	for _, ms := range [][]FailureMember{pd, tikv, tidb, tiflash} {
+		sort.Slice(ms, func(i, j int) bool { return ms[i].PodName < ms[j].PodName })
+		members = append(members, ms...)
+	}
+	return members
+}
+
+// PDFailoverMode returns the failover mode of PD, defaults to Full
+func (tc *TidbCluster) PDFailoverMode() FailoverMode {
+	if tc.Spec.PD == nil || tc.Spec.PD.FailoverMode == "" {
+		return FailoverModeFull
+	}
+	return tc.Spec.PD.FailoverMode
+}
+
+// PDFailoverWaitForMemberDeleted returns whether the failover of PD waits for the failure member
+// to leave the PD cluster before deleting its PVCs
+func (tc *TidbCluster) PDFailoverWaitForMemberDeleted() bool {
+	if tc.Spec.PD == nil || tc.Spec.PD.WaitForMemberDeleted == nil {
+		return false
+	}
+	return *tc.Spec.PD.WaitForMemberDeleted
+}
+
+// PDMemberProtected returns whether the PD member of the id is protected from the failover
+func (tc *TidbCluster) PDMemberProtected(id string) bool {
+	if tc.Spec.PD == nil {
+		return false
+	}
+	for _, protected := range tc.Spec.PD.ProtectedMemberIDs {
+		if protected == id {
+			return true
+		}
+	}
+	return false
+}
+
+// FailoverEventInterval returns the interval in which a failover event of a member of the component is emitted once,
+// 0 means the --event-dedup-window of the controller manager, which is also returned for an interval of 0,
+// a negative one or one failing to parse, i.e. the failover events of a component are always throttled.
+func (tc *TidbCluster) FailoverEventInterval(memberType MemberType) time.Duration {
+	var interval *string
+	switch memberType {
+	case PDMemberType:
+		if tc.Spec.PD != nil {
+			interval = tc.Spec.PD.FailoverEventInterval
+		}
+	case TiKVMemberType:
+		if tc.Spec.TiKV != nil {
+			interval = tc.Spec.TiKV.FailoverEventInterval
+		}
+	case TiFlashMemberType:
+		if tc.Spec.TiFlash != nil {
+			interval = tc.Spec.TiFlash.FailoverEventInterval
+		}
+	case TiDBMemberType:
+		if tc.Spec.TiDB != nil {
+			interval = tc.Spec.TiDB.FailoverEventInterval
+		}
+	}
+	if interval == nil {
+		return 0
+	}
+	d, err := time.ParseDuration(*interval)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
 func (tc *TidbCluster) PDStsDesiredReplicas() int32 {
 	if tc.Spec.PD == nil {
 		return 0
@@ -456,6 +536,11 @@ func (tc *TidbCluster) PDStsDesiredOrdinals(excludeFailover bool) sets.Int32 {
 	return GetPodOrdinalsFromReplicasAndDeleteSlots(replicas, tc.getDeleteSlots(label.PDLabelVal))
 }
 
+// PDDeleteSlots returns the ordinals of PD skipped by the advanced statefulset
+func (tc *TidbCluster) PDDeleteSlots() sets.Int32 {
+	return tc.getDeleteSlots(label.PDLabelVal)
+}
+
 // TiKVAllPodsStarted return whether all pods of TiKV are started.
 //
 // If TiKV isn't specified, return false.
@@ -724,6 +809,11 @@ func (tc *TidbCluster) IsTLSClusterEnabled() bool {
 	return tc.Spec.TLSCluster != nil && tc.Spec.TLSCluster.Enabled
 }
 
+// IsDiscoveryTLSEnabled returns whether the discovery service serves HTTPS
+func (tc *TidbCluster) IsDiscoveryTLSEnabled() bool {
+	return tc.Spec.Discovery.TLSSecret != ""
+}
+
 func (tc *TidbCluster) Scheme() string {
 	if tc.IsTLSClusterEnabled() {
 		return "https"
@@ -747,6 +837,14 @@ func (tc *TidbCluster) IsPVReclaimEnabled() bool {
 	return *enabled
 }
 
+// GetTerminationPolicy returns what happens to the data when the TidbCluster is deleted
+func (tc *TidbCluster) GetTerminationPolicy() TerminationPolicy {
+	if tc.Spec.TerminationPolicy == "" {
+		return TerminationPolicyRetain
+	}
+	return tc.Spec.TerminationPolicy
+}
+
 func (tc *TidbCluster) IsTiDBBinlogEnabled() bool {
 	var binlogEnabled *bool
 	if tc.Spec.TiDB != nil {
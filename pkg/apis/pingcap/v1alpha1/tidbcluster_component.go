@@ -48,6 +48,7 @@ type ComponentAccessor interface {
 	AdditionalVolumeMounts() []corev1.VolumeMount
 	TerminationGracePeriodSeconds() *int64
 	StatefulSetUpdateStrategy() apps.StatefulSetUpdateStrategyType
+	PodManagementPolicy() apps.PodManagementPolicyType
 	TopologySpreadConstraints() []corev1.TopologySpreadConstraint
 }
 
@@ -101,6 +102,16 @@ func (a *componentAccessorImpl) StatefulSetUpdateStrategy() apps.StatefulSetUpda
 	return a.ComponentSpec.StatefulSetUpdateStrategy
 }
 
+func (a *componentAccessorImpl) PodManagementPolicy() apps.PodManagementPolicyType {
+	if a.ComponentSpec == nil || len(a.ComponentSpec.PodManagementPolicy) == 0 {
+		if a.component == ComponentPump {
+			return apps.OrderedReadyPodManagement
+		}
+		return apps.ParallelPodManagement
+	}
+	return a.ComponentSpec.PodManagementPolicy
+}
+
 func (a *componentAccessorImpl) PodSecurityContext() *corev1.PodSecurityContext {
 	if a.ComponentSpec == nil || a.ComponentSpec.PodSecurityContext == nil {
 		return a.podSecurityContext
@@ -250,6 +250,72 @@ func TestTiKVIsAvailable(t *testing.T) {
 }
 
 // TODO: refector test of buildTidbClusterComponentAccessor
+func TestAllFailureMembers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name   string
+		update func(*TidbCluster)
+		expect []FailureMember
+	}
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		tc := newTidbCluster()
+		test.update(tc)
+		g.Expect(tc.AllFailureMembers()).To(Equal(test.expect))
+	}
+	tests := []testcase{
+		{
+			name:   "no failure members",
+			update: func(tc *TidbCluster) {},
+			expect: nil,
+		},
+		{
+			name: "failure members in multiple components",
+			update: func(tc *TidbCluster) {
+				tc.Status.PD.FailureMembers = map[string]PDFailureMember{
+					"test-pd-2": {PodName: "test-pd-2", MemberID: "222", MemberDeleted: true},
+					"test-pd-1": {PodName: "test-pd-1", MemberID: "111"},
+				}
+				tc.Status.TiKV.FailureStores = map[string]TiKVFailureStore{
+					"4": {PodName: "test-tikv-0", StoreID: "4"},
+				}
+				tc.Status.TiDB.FailureMembers = map[string]TiDBFailureMember{
+					"test-tidb-1": {PodName: "test-tidb-1"},
+				}
+				tc.Status.TiFlash.FailureStores = map[string]TiKVFailureStore{
+					"9": {PodName: "test-tiflash-1", StoreID: "9"},
+					"8": {PodName: "test-tiflash-0", StoreID: "8"},
+				}
+			},
+			expect: []FailureMember{
+				{Component: PDMemberType, PodName: "test-pd-1", ID: "111"},
+				{Component: PDMemberType, PodName: "test-pd-2", ID: "222", Deleted: true},
+				{Component: TiKVMemberType, PodName: "test-tikv-0", ID: "4"},
+				{Component: TiDBMemberType, PodName: "test-tidb-1"},
+				{Component: TiFlashMemberType, PodName: "test-tiflash-0", ID: "8"},
+				{Component: TiFlashMemberType, PodName: "test-tiflash-1", ID: "9"},
+			},
+		},
+		{
+			name: "failure members only in tidb",
+			update: func(tc *TidbCluster) {
+				tc.Status.TiDB.FailureMembers = map[string]TiDBFailureMember{
+					"test-tidb-0": {PodName: "test-tidb-0"},
+				}
+			},
+			expect: []FailureMember{
+				{Component: TiDBMemberType, PodName: "test-tidb-0"},
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
 func TestComponentAccessor(t *testing.T) {
 	g := NewGomegaWithT(t)
 
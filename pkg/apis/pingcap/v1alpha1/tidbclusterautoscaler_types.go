@@ -91,12 +91,62 @@ type AutoRule struct {
 // TikvAutoScalerSpec describes the spec for tikv auto-scaling
 type TikvAutoScalerSpec struct {
 	BasicAutoScalerSpec `json:",inline"`
+
+	// Storage makes the auto-scaler controller able to scale out the tikv of the target TidbCluster
+	// when the storage usage of the tikv stores queried from PD exceeds the threshold
+	// +optional
+	Storage *StorageAutoScalerSpec `json:"storage,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// StorageAutoScalerSpec describes the spec for tikv auto-scaling on the storage pressure.
+// The tikv is never scaled in on the storage usage.
+type StorageAutoScalerSpec struct {
+	// UsedThresholdPercent is the used percentage of the storage of a tikv store, the tikv scales out
+	// when the max used percentage of the stores exceeds it
+	UsedThresholdPercent int32 `json:"usedThresholdPercent"`
+
+	// StabilizationWindowSeconds represents the duration seconds that the storage usage should keep
+	// exceeding the threshold before scaling out
+	// If not set, the default StabilizationWindowSeconds will be set to 300
+	// +optional
+	StabilizationWindowSeconds *int32 `json:"stabilizationWindowSeconds,omitempty"`
+
+	// MaxReplicas is the upper limit for the number of replicas to which the autoscaler can scale out
+	MaxReplicas int32 `json:"maxReplicas"`
 }
 
 // +k8s:openapi-gen=true
 // TidbAutoScalerSpec describes the spec for tidb auto-scaling
 type TidbAutoScalerSpec struct {
 	BasicAutoScalerSpec `json:",inline"`
+
+	// ExternalCluster is the heterogeneous TidbCluster joining the target TidbCluster, the replicas
+	// recommended by the external service are applied to its tidb instead of the auto-created TidbCluster
+	// +optional
+	ExternalCluster *ExternalClusterSpec `json:"externalCluster,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// ExternalClusterSpec describes the heterogeneous TidbCluster whose tidb is auto-scaled
+type ExternalClusterSpec struct {
+	// Namespace is the namespace of the TidbCluster,
+	// default to the same namespace with TidbClusterAutoScaler
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the TidbCluster
+	Name string `json:"name"`
+
+	// Template is the tidb spec of the TidbCluster when it's created by the auto-scaler,
+	// the tidb spec of the target TidbCluster is used if not set
+	// +optional
+	Template *TiDBSpec `json:"template,omitempty"`
+
+	// GarbageCollect makes the TidbCluster created by the auto-scaler deleted with the TidbClusterAutoScaler,
+	// the TidbCluster should be in the same namespace with TidbClusterAutoScaler
+	// +optional
+	GarbageCollect bool `json:"garbageCollect,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -162,6 +212,42 @@ type TidbClusterAutoScalerStatus struct {
 	// Tidb describes the status of each group for the tidb in the last auto-scaling reconciliation
 	// +optional
 	TiDB map[string]TidbAutoScalerStatus `json:"tidb,omitempty"`
+	// Recommendations describes the last auto-scaling evaluations that recommend changing the replicas,
+	// the latest evaluation is the last one
+	// +optional
+	Recommendations []AutoScalingRecommendation `json:"recommendations,omitempty"`
+}
+
+// AutoScalingDecision is the decision of an auto-scaling evaluation
+type AutoScalingDecision string
+
+const (
+	// AutoScalingDecisionScaleOut means the replicas are scaled out
+	AutoScalingDecisionScaleOut AutoScalingDecision = "ScaleOut"
+	// AutoScalingDecisionScaleIn means the replicas are scaled in
+	AutoScalingDecisionScaleIn AutoScalingDecision = "ScaleIn"
+	// AutoScalingDecisionDeferred means the auto-scaling is deferred by the scale-in or scale-out interval
+	AutoScalingDecisionDeferred AutoScalingDecision = "Deferred"
+)
+
+// +k8s:openapi-gen=true
+// AutoScalingRecommendation describes an auto-scaling evaluation
+type AutoScalingRecommendation struct {
+	// Component is the component(tidb/tikv) evaluated
+	Component MemberType `json:"component"`
+	// Group is the auto-scaling group evaluated
+	Group string `json:"group"`
+	// CurrentReplicas is the replicas of the group when evaluated
+	CurrentReplicas int32 `json:"currentReplicas"`
+	// RecommendedReplicas is the replicas recommended by the evaluation
+	RecommendedReplicas int32 `json:"recommendedReplicas"`
+	// Decision is the decision of the evaluation
+	Decision AutoScalingDecision `json:"decision"`
+	// Message describes the metric values and the thresholds of the evaluation
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Timestamp is the time of the evaluation
+	Timestamp metav1.Time `json:"timestamp"`
 }
 
 // +k8s:openapi-gen=true
@@ -174,6 +260,34 @@ type TidbAutoScalerStatus struct {
 // TikvAutoScalerStatus describe the auto-scaling status of tikv
 type TikvAutoScalerStatus struct {
 	BasicAutoScalerStatus `json:",inline"`
+
+	// Storage describes the inputs of the last evaluation of the storage pressure
+	// +optional
+	Storage *StorageAutoScalerStatus `json:"storage,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// StorageAutoScalerStatus describe the inputs of the evaluation of the storage pressure of tikv
+type StorageAutoScalerStatus struct {
+	// StoreCount is the count of the tikv stores that are evaluated
+	StoreCount int32 `json:"storeCount"`
+	// MaxUsedStoreID is the id of the store with the max used percentage of the storage
+	// +optional
+	MaxUsedStoreID string `json:"maxUsedStoreID,omitempty"`
+	// MaxUsedPercent is the max used percentage of the storage of the stores
+	MaxUsedPercent float64 `json:"maxUsedPercent"`
+	// CapacityBytes is the storage capacity of the store with the max used percentage
+	// +optional
+	CapacityBytes int64 `json:"capacityBytes,omitempty"`
+	// AvailableBytes is the available storage of the store with the max used percentage
+	// +optional
+	AvailableBytes int64 `json:"availableBytes,omitempty"`
+	// ThresholdExceededTimestamp is the time since when the max used percentage keeps exceeding the threshold
+	// +optional
+	ThresholdExceededTimestamp *metav1.Time `json:"thresholdExceededTimestamp,omitempty"`
+	// LastEvaluationTimestamp is the time of the last evaluation
+	// +optional
+	LastEvaluationTimestamp *metav1.Time `json:"lastEvaluationTimestamp,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -182,6 +296,12 @@ type BasicAutoScalerStatus struct {
 	// LastAutoScalingTimestamp describes the last auto-scaling timestamp for the component(tidb/tikv)
 	// +optional
 	LastAutoScalingTimestamp *metav1.Time `json:"lastAutoScalingTimestamp,omitempty"`
+	// LastScaleOutTimestamp describes the last auto-scaling-out timestamp for the component(tidb/tikv)
+	// +optional
+	LastScaleOutTimestamp *metav1.Time `json:"lastScaleOutTimestamp,omitempty"`
+	// LastScaleInTimestamp describes the last auto-scaling-in timestamp for the component(tidb/tikv)
+	// +optional
+	LastScaleInTimestamp *metav1.Time `json:"lastScaleInTimestamp,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -81,6 +81,19 @@ type TidbInitializerSpec struct {
 	// +optional
 	InitSqlConfigMap *string `json:"initSqlConfigMap,omitempty"`
 
+	// InitSqlConfigMapNames reference the configmaps of the SQL files executed after initSql, every key of
+	// a configmap is a file of the statements ending with a semicolon at the end of a line. The files are
+	// executed in the lexical order of the configmap names and then the keys, and a file is executed once
+	// for the same content as the hash of the content executed is recorded in the status.
+	// +optional
+	InitSqlConfigMapNames []string `json:"initSqlConfigMapNames,omitempty"`
+
+	// RerunOnChange is whether to execute the files of initSqlConfigMapNames again after their content
+	// is changed, only the changed files are executed.
+	// Optional: Defaults to false
+	// +optional
+	RerunOnChange bool `json:"rerunOnChange,omitempty"`
+
 	// +optional
 	PasswordSecret *string `json:"passwordSecret,omitempty"`
 
@@ -103,6 +116,27 @@ type TidbInitializerStatus struct {
 
 	// Phase is a user readable state inferred from the underlying Job status and TidbCluster status
 	Phase InitializePhase `json:"phase,omitempty"`
+
+	// AppliedSqlHashes are the sha256 hashes of the content of the files of initSqlConfigMapNames executed,
+	// keyed by <configmap name>/<key>
+	// +optional
+	AppliedSqlHashes map[string]string `json:"appliedSqlHashes,omitempty"`
+
+	// SqlFailure is the statement of the files of initSqlConfigMapNames failing the last job
+	// +optional
+	SqlFailure *InitSqlFailure `json:"sqlFailure,omitempty"`
+}
+
+// InitSqlFailure is a statement failing in a SQL file of TidbInitializer
+// +k8s:openapi-gen=true
+type InitSqlFailure struct {
+	// File is the file of the statement, <configmap name>/<key>
+	File string `json:"file"`
+	// StatementIndex is the index of the statement in the file, starting from 0
+	StatementIndex int32 `json:"statementIndex"`
+	// Message is the error returned by TiDB
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -120,10 +120,19 @@ type TidbMonitorSpec struct {
 	// it must be manually moved. Increasing shards will not reshard data
 	// either but it will continue to be available from the same instances. To
 	// query globally use Thanos sidecar and Thanos querier or remote write
-	// data to a central location. Sharding is done on the content of the
-	// `__address__` target meta-label.
+	// data to a central location. Sharding is done on the stable identity of
+	// the targets, see ShardingStrategy.
 	Shards *int32 `json:"shards,omitempty"`
 
+	// ShardingStrategy is the strategy to distribute targets onto the shards.
+	// `target` distributes the targets by the hash of their namespace, cluster,
+	// component and Pod name, so a target stays on its shard when its Pod IP changes.
+	// `cluster` assigns all the targets of a cluster to the same shard, so the
+	// dashboards of a cluster query a single Prometheus.
+	// Defaults to `target`.
+	// +optional
+	ShardingStrategy TidbMonitorShardingStrategy `json:"shardingStrategy,omitempty"`
+
 	// Additional volumes of component pod.
 	// +optional
 	AdditionalVolumes []corev1.Volume `json:"additionalVolumes,omitempty"`
@@ -173,6 +182,11 @@ type PrometheusSpec struct {
 	RemoteWrite []*RemoteWriteSpec `json:"remoteWrite,omitempty"`
 	// Additional volume mounts of prometheus pod.
 	AdditionalVolumeMounts []corev1.VolumeMount `json:"additionalVolumeMounts,omitempty"`
+	// Names of the ConfigMaps in the namespace of the TidbMonitor containing extra alert rules,
+	// they are mounted to /prometheus-rules/extra/<name> and the keys must have the suffix `.rules.yml`.
+	// A missing ConfigMap is skipped with a warning event.
+	// +optional
+	ExtraRulesConfigMaps []string `json:"extraRulesConfigMaps,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -212,6 +226,7 @@ type GrafanaSpec struct {
 
 	// +optional
 	// if `passwordSecret` is not set, `password` will be used.
+	// The grafana is rolled out when the credentials in the secrets are changed.
 	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty"`
 
 	// +optional
@@ -231,6 +246,11 @@ type GrafanaSpec struct {
 	Ingress *IngressSpec `json:"ingress,omitempty"`
 	// Additional volume mounts of grafana pod.
 	AdditionalVolumeMounts []corev1.VolumeMount `json:"additionalVolumeMounts,omitempty"`
+	// Names of the ConfigMaps in the namespace of the TidbMonitor containing extra dashboards,
+	// they are mounted to /grafana-dashboard-definitions/extra/<name> and provisioned by grafana.
+	// A missing ConfigMap is skipped with a warning event.
+	// +optional
+	ExtraDashboardsConfigMaps []string `json:"extraDashboardsConfigMaps,omitempty"`
 }
 
 // ReloaderSpec is the desired state of reloader
@@ -316,6 +336,11 @@ type TidbMonitorStatus struct {
 	DeploymentStorageStatus *DeploymentStorageStatus `json:"deploymentStorageStatus,omitempty"`
 
 	StatefulSet *apps.StatefulSetStatus `json:"statefulSet,omitempty"`
+
+	// RBACNamespaces are the namespaces of the target clusters, other than the namespace
+	// of the TidbMonitor, in which the Role and RoleBinding are created for the monitor.
+	// +optional
+	RBACNamespaces []string `json:"rbacNamespaces,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -391,6 +416,10 @@ type RemoteWriteSpec struct {
 	// File to read bearer token for remote write.
 	// +optional
 	BearerTokenFile string `json:"bearerTokenFile,omitempty"`
+	// The secret in the namespace of the TidbMonitor that contains the bearer token
+	// for remote write, it takes precedence over BearerTokenFile.
+	// +optional
+	BearerTokenSecret *corev1.SecretKeySelector `json:"bearerTokenSecret,omitempty"`
 	// TLS Config to use for remote write.
 	// +optional
 	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
@@ -460,6 +489,24 @@ type QueueConfig struct {
 	MaxBackoff time.Duration `json:"maxBackoff,omitempty"`
 }
 
+// TidbMonitorShardingStrategy is the strategy to distribute targets onto the shards of TidbMonitor
+type TidbMonitorShardingStrategy string
+
+const (
+	// ShardingStrategyTarget distributes the targets by their identity
+	ShardingStrategyTarget TidbMonitorShardingStrategy = "target"
+	// ShardingStrategyCluster assigns all the targets of a cluster to the same shard
+	ShardingStrategyCluster TidbMonitorShardingStrategy = "cluster"
+)
+
+// GetShardingStrategy returns the sharding strategy, defaults to ShardingStrategyTarget
+func (tm *TidbMonitor) GetShardingStrategy() TidbMonitorShardingStrategy {
+	if tm.Spec.ShardingStrategy == "" {
+		return ShardingStrategyTarget
+	}
+	return tm.Spec.ShardingStrategy
+}
+
 func (tm *TidbMonitor) GetShards() int32 {
 	shards := int32(1)
 	if tm.Spec.Shards != nil && *tm.Spec.Shards > 1 {
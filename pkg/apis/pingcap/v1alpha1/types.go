@@ -15,8 +15,10 @@ package v1alpha1
 
 import (
 	apps "k8s.io/api/apps/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -28,6 +30,9 @@ const (
 	TiKVStateUp string = "Up"
 	// TiKVStateDown represents status of Down of TiKV
 	TiKVStateDown string = "Down"
+	// TiKVStateDisconnected represents status of Disconnected of TiKV, the store misses
+	// heartbeats and is unreachable, it becomes Down after max-store-down-time
+	TiKVStateDisconnected string = "Disconnected"
 	// TiKVStateOffline represents status of Offline of TiKV
 	TiKVStateOffline string = "Offline"
 	// TiKVStateTombstone represents status of Tombstone of TiKV
@@ -96,6 +101,29 @@ const (
 	ConfigUpdateStrategyRollingUpdate ConfigUpdateStrategy = "RollingUpdate"
 )
 
+// FailoverMode represents what the failover does to a failure member
+type FailoverMode string
+
+const (
+	// FailoverModeFull deletes the failure member from the cluster, and deletes its Pod and PVCs,
+	// a new member is created with a new Pod and new PVCs.
+	FailoverModeFull FailoverMode = "Full"
+	// FailoverModePodOnly only deletes the Pod of the failure member, the member and its PVCs are kept.
+	FailoverModePodOnly FailoverMode = "PodOnly"
+)
+
+// TerminationPolicy represents what happens to the data of a TidbCluster when it's deleted
+type TerminationPolicy string
+
+const (
+	// TerminationPolicyRetain keeps the reclaim policy of the PVs of the cluster, the data is retained
+	// as long as the reclaim policy is Retain.
+	TerminationPolicyRetain TerminationPolicy = "Retain"
+	// TerminationPolicyDelete restores the reclaim policy of the PVs of the cluster to Delete, the data is
+	// deleted along with the PVCs.
+	TerminationPolicyDelete TerminationPolicy = "Delete"
+)
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -203,6 +231,14 @@ type TidbClusterSpec struct {
 	// +optional
 	EnablePVReclaim *bool `json:"enablePVReclaim,omitempty"`
 
+	// TerminationPolicy is what happens to the data when the TidbCluster is deleted.
+	// `Retain` keeps the reclaim policy of the PVs of the cluster,
+	// `Delete` restores the reclaim policy of the PVs of the cluster to Delete before the deletion completes.
+	// Optional: Defaults to Retain
+	// +kubebuilder:validation:Enum=Retain,Delete
+	// +optional
+	TerminationPolicy TerminationPolicy `json:"terminationPolicy,omitempty"`
+
 	// Whether enable the TLS connection between TiDB server components
 	// Optional: Defaults to nil
 	// +optional
@@ -300,6 +336,20 @@ type TidbClusterStatus struct {
 	// Represents the latest available observations of a tidb cluster's state.
 	// +optional
 	Conditions []TidbClusterCondition `json:"conditions,omitempty"`
+	// FailoverSummary tracks the failovers summarized periodically by an event
+	// +optional
+	FailoverSummary *FailoverSummaryStatus `json:"failoverSummary,omitempty"`
+}
+
+// FailoverSummaryStatus is the status of the periodic failover summary of a tidb cluster
+type FailoverSummaryStatus struct {
+	// LastSummaryTime is the last time the failover summary event is emitted
+	LastSummaryTime metav1.Time `json:"lastSummaryTime,omitempty"`
+	// LastFailoverTime is the creation time of the latest failover counted
+	LastFailoverTime metav1.Time `json:"lastFailoverTime,omitempty"`
+	// Counts is the number of failovers of each component since the last summary
+	// +optional
+	Counts map[MemberType]int32 `json:"counts,omitempty"`
 }
 
 // TidbClusterCondition describes the state of a tidb cluster at a certain point.
@@ -333,6 +383,33 @@ const (
 	// - All TiKV stores are up.
 	// - All TiFlash stores are up.
 	TidbClusterReady TidbClusterConditionType = "Ready"
+	// TiKVPreScaleInJobFailed indicates that the pre scale-in Job of a TiKV store
+	// failed and the scale-in of TiKV is blocked.
+	TiKVPreScaleInJobFailed TidbClusterConditionType = "TiKVPreScaleInJobFailed"
+	// PodManagementPolicyNotApplied indicates that the podManagementPolicy of a component
+	// differs from the one of its existing StatefulSet, and it can not be applied
+	// because the field of the StatefulSet is immutable.
+	PodManagementPolicyNotApplied TidbClusterConditionType = "PodManagementPolicyNotApplied"
+	// PDScaleInBlocked indicates that the scale-in of PD is blocked because
+	// removing the member would break the quorum of the PD cluster.
+	PDScaleInBlocked TidbClusterConditionType = "PDScaleInBlocked"
+	// UpgradeBlocked indicates that the rolling upgrade of a component is blocked,
+	// the reason of the condition tells why it's blocked. The condition type is
+	// prefixed by the component, e.g. PDUpgradeBlocked.
+	UpgradeBlocked TidbClusterConditionType = "UpgradeBlocked"
+	// FailoverBlocked indicates that the failover of a component is blocked,
+	// the reason of the condition tells why it's blocked. The condition type is
+	// prefixed by the component, e.g. TiKVFailoverBlocked.
+	FailoverBlocked TidbClusterConditionType = "FailoverBlocked"
+	// ConfigUnknownKeys indicates that the config of a component has keys unknown to
+	// the config schema of its version, which are probably typos and ignored by the component.
+	ConfigUnknownKeys TidbClusterConditionType = "ConfigUnknownKeys"
+	// ExternalPDUnreachable indicates that none of the external PD endpoints in spec.pdAddresses
+	// of a TidbCluster without local PD is reachable.
+	ExternalPDUnreachable TidbClusterConditionType = "ExternalPDUnreachable"
+	// TidbClusterPaused indicates that the reconciliation of the tidb cluster or any of its
+	// components is paused by spec.paused or the paused field of the component.
+	TidbClusterPaused TidbClusterConditionType = "Paused"
 )
 
 // +k8s:openapi-gen=true
@@ -340,6 +417,14 @@ const (
 type DiscoverySpec struct {
 	*ComponentSpec              `json:",inline"`
 	corev1.ResourceRequirements `json:",inline"`
+
+	// TLSSecret is the name of the TLS Secret in the namespace of the cluster to serve the discovery service
+	// with HTTPS. The Secret must contain `tls.crt`, `tls.key` and `ca.crt`, the certificate must be valid
+	// for `<cluster>-discovery.<namespace>` and `<cluster>-discovery.<namespace>.svc`. The `ca.crt` is
+	// mounted to the components to verify the discovery service.
+	// Changing this will cause a rolling-update of the components requesting the discovery service.
+	// +optional
+	TLSSecret string `json:"tlsSecret,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -371,6 +456,48 @@ type PDSpec struct {
 	// +optional
 	MaxFailoverCount *int32 `json:"maxFailoverCount,omitempty"`
 
+	// FailoverEventInterval is the interval in which a failover event of a member of PD is emitted once,
+	// e.g. the member staying unhealthy is reported once in every interval, in the format of Go Duration.
+	// Optional: Defaults to the --event-dedup-window of the controller manager, which is also used if
+	// the interval is 0, throttling can't be turned off for the component.
+	// +optional
+	FailoverEventInterval *string `json:"failoverEventInterval,omitempty"`
+
+	// FailoverMode is the mode of the failover of PD.
+	// `Full` deletes the failure member from the PD cluster and deletes its Pod and PVCs,
+	// `PodOnly` only deletes the Pod of the failure member.
+	// Optional: Defaults to Full
+	// +kubebuilder:validation:Enum=Full,PodOnly
+	// +optional
+	FailoverMode FailoverMode `json:"failoverMode,omitempty"`
+
+	// WaitForMemberDeleted makes the failover of PD re-check via the PD API that the failure member
+	// has left the PD cluster before deleting its PVCs, and requeue until it has left.
+	// Optional: Defaults to false
+	// +optional
+	WaitForMemberDeleted *bool `json:"waitForMemberDeleted,omitempty"`
+
+	// PreservePodOnFailover makes the failover of PD preserve the Pod and PVCs of the failure member
+	// for inspection instead of deleting them. The Pod is released from the StatefulSet and its ordinal
+	// is added to the PD delete slots, so the replacement is created with a new ordinal.
+	// The preserved Pod and the delete slot should be removed manually after the inspection.
+	// It requires the AdvancedStatefulSet feature, the Pod is deleted otherwise.
+	// Optional: Defaults to false
+	// +optional
+	PreservePodOnFailover bool `json:"preservePodOnFailover,omitempty"`
+
+	// GracePeriodSeconds is the grace period of deleting the Pod of the failure member in the failover of PD.
+	// 0 deletes the Pod immediately, which speeds up the recovery of a truly dead Pod.
+	// Optional: Defaults to the terminationGracePeriodSeconds of the Pod
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+
+	// ProtectedMemberIDs are the IDs of the PD members which are never failed over, e.g. a witness PD.
+	// A protected member is still reported unhealthy, but it's never marked as failure and deleted.
+	// +optional
+	ProtectedMemberIDs []string `json:"protectedMemberIDs,omitempty"`
+
 	// The storageClassName of the persistent volume for PD data storage.
 	// Defaults to Kubernetes default storage class.
 	// +optional
@@ -440,6 +567,13 @@ type TiKVSpec struct {
 	// +optional
 	MaxFailoverCount *int32 `json:"maxFailoverCount,omitempty"`
 
+	// FailoverEventInterval is the interval in which a failover event of a member of TiKV is emitted once,
+	// e.g. the member staying unhealthy is reported once in every interval, in the format of Go Duration.
+	// Optional: Defaults to the --event-dedup-window of the controller manager, which is also used if
+	// the interval is 0, throttling can't be turned off for the component.
+	// +optional
+	FailoverEventInterval *string `json:"failoverEventInterval,omitempty"`
+
 	// Whether output the RocksDB log in a separate sidecar container
 	// Optional: Defaults to false
 	// +optional
@@ -478,6 +612,11 @@ type TiKVSpec struct {
 	// +optional
 	RecoverFailover bool `json:"recoverFailover,omitempty"`
 
+	// Indicates that the tikv cluster is paused and will not be processed by
+	// the controller, the other components are still processed.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
 	// MountClusterClientSecret indicates whether to mount `cluster-client-secret` to the Pod
 	// +optional
 	MountClusterClientSecret *bool `json:"mountClusterClientSecret,omitempty"`
@@ -498,6 +637,36 @@ type TiKVSpec struct {
 	// EnableNamedStatusPort enables status port(20180) in the Pod spec.
 	// If you set it to `true` for an existing cluster, the TiKV cluster will be rolling updated.
 	EnableNamedStatusPort bool `json:"enableNamedStatusPort,omitempty"`
+
+	// ScalePolicy is the scale configuration for TiKV
+	// +optional
+	ScalePolicy *TiKVScalePolicy `json:"scalePolicy,omitempty"`
+}
+
+// TiKVScalePolicy is the scale configuration for TiKV
+type TiKVScalePolicy struct {
+	// PreScaleInJobTemplate is the template of the Job which is run before the store of
+	// a TiKV Pod is deleted during scale-in. The env POD_NAME, STORE_ID and PD_ADDRESS
+	// are injected into all the containers of the Job.
+	// The scale-in is blocked if the Job fails.
+	// +optional
+	PreScaleInJobTemplate *batchv1beta1.JobTemplateSpec `json:"preScaleInJobTemplate,omitempty"`
+
+	// PreScaleInJobTimeout is the timeout to wait for the pre scale-in Job to complete,
+	// in the format of Go Duration. The scale-in proceeds if the Job is still running
+	// after the timeout.
+	// Defaults to wait for the Job without a timeout
+	// +optional
+	PreScaleInJobTimeout *string `json:"preScaleInJobTimeout,omitempty"`
+
+	// NewPVCDataSource is the data source of the PVC of a new TiKV Pod during scale-out,
+	// e.g. a VolumeSnapshot of an existing store, to warm up the data and reduce the
+	// region transferring. The PVC is pre-created with the data source before the replicas
+	// of the StatefulSet is increased, and is adopted by the StatefulSet controller.
+	// The PVC is created without the data source if the restore size of the VolumeSnapshot
+	// is larger than the requested storage.
+	// +optional
+	NewPVCDataSource *corev1.TypedLocalObjectReference `json:"newPVCDataSource,omitempty"`
 }
 
 // TiFlashSpec contains details of TiFlash members
@@ -530,6 +699,13 @@ type TiFlashSpec struct {
 	// +optional
 	MaxFailoverCount *int32 `json:"maxFailoverCount,omitempty"`
 
+	// FailoverEventInterval is the interval in which a failover event of a member of TiFlash is emitted once,
+	// e.g. the member staying unhealthy is reported once in every interval, in the format of Go Duration.
+	// Optional: Defaults to the --event-dedup-window of the controller manager, which is also used if
+	// the interval is 0, throttling can't be turned off for the component.
+	// +optional
+	FailoverEventInterval *string `json:"failoverEventInterval,omitempty"`
+
 	// The persistent volume claims of the TiFlash data storages.
 	// TiFlash supports multiple disks.
 	StorageClaims []StorageClaim `json:"storageClaims"`
@@ -662,6 +838,13 @@ type TiDBSpec struct {
 	// +optional
 	MaxFailoverCount *int32 `json:"maxFailoverCount,omitempty"`
 
+	// FailoverEventInterval is the interval in which a failover event of a member of TiDB is emitted once,
+	// e.g. the member staying unhealthy is reported once in every interval, in the format of Go Duration.
+	// Optional: Defaults to the --event-dedup-window of the controller manager, which is also used if
+	// the interval is 0, throttling can't be turned off for the component.
+	// +optional
+	FailoverEventInterval *string `json:"failoverEventInterval,omitempty"`
+
 	// Whether output the slow log in an separate sidecar container
 	// Optional: Defaults to true
 	// +optional
@@ -806,6 +989,12 @@ type ComponentSpec struct {
 	// +optional
 	Version *string `json:"version,omitempty"`
 
+	// ResolvedImage is the image of the component resolved by the admission webhook from the image,
+	// baseImage and version, it may be pinned to a digest. It's set by the webhook and ignored if it's
+	// not resolved from the current image, baseImage and version.
+	// +optional
+	ResolvedImage string `json:"resolvedImage,omitempty"`
+
 	// ImagePullPolicy of the component. Override the cluster-level imagePullPolicy if present
 	// Optional: Defaults to cluster-level setting
 	// +optional
@@ -911,6 +1100,15 @@ type ComponentSpec struct {
 	// +optional
 	StatefulSetUpdateStrategy apps.StatefulSetUpdateStrategyType `json:"statefulSetUpdateStrategy,omitempty"`
 
+	// PodManagementPolicy of the StatefulSet of the component.
+	// The field is immutable and only takes effect when the StatefulSet is created,
+	// a change of an existing StatefulSet is reported by the condition PodManagementPolicyNotApplied.
+	// It can not be set for PD.
+	// Optional: Defaults to Parallel, and OrderedReady for Pump
+	// +kubebuilder:validation:Enum=OrderedReady,Parallel
+	// +optional
+	PodManagementPolicy apps.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
+
 	// TopologySpreadConstraints describes how a group of pods ought to spread across topology
 	// domains. Scheduler will schedule pods in a way which abides by the constraints.
 	// This field is is only honored by clusters that enables the EvenPodsSpread feature.
@@ -1010,6 +1208,13 @@ type PDStatus struct {
 	FailureMembers  map[string]PDFailureMember `json:"failureMembers,omitempty"`
 	UnjoinedMembers map[string]UnjoinedMember  `json:"unjoinedMembers,omitempty"`
 	Image           string                     `json:"image,omitempty"`
+	// UnsyncedSince is the time since when the status has failed to be synced from the PD cluster,
+	// it's cleared once the status is synced
+	// +nullable
+	UnsyncedSince *metav1.Time `json:"unsyncedSince,omitempty"`
+	// RecoveredMembers are the failure members cleared by the failover recovery and the time of the recovery,
+	// the failover of them is suppressed during the cooldown after the recovery
+	RecoveredMembers map[string]metav1.Time `json:"recoveredMembers,omitempty"`
 }
 
 // PDMember is PD member
@@ -1032,6 +1237,9 @@ type PDFailureMember struct {
 	PVCUIDSet     map[types.UID]struct{} `json:"pvcUIDSet,omitempty"`
 	MemberDeleted bool                   `json:"memberDeleted,omitempty"`
 	CreatedAt     metav1.Time            `json:"createdAt,omitempty"`
+	// Triggered indicates the member is marked as failure on demand by the tidb.pingcap.com/pd-trigger-failover
+	// annotation, it's not recovered before it's deleted even if it's healthy
+	Triggered bool `json:"triggered,omitempty"`
 }
 
 // UnjoinedMember is the pd unjoin cluster member information
@@ -1119,6 +1327,10 @@ type TiKVStore struct {
 	State       string `json:"state"`
 	// Last time the health transitioned from one to another.
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// DownSince is the time the store first reported Down, it's kept until the store is Up again
+	// even if the store transitions to other states in between.
+	// +optional
+	DownSince *metav1.Time `json:"downSince,omitempty"`
 }
 
 // TiKVFailureStore is the tikv failure store information
@@ -1128,6 +1340,19 @@ type TiKVFailureStore struct {
 	CreatedAt metav1.Time `json:"createdAt,omitempty"`
 }
 
+// FailureMember is a failure member of a component of TidbCluster,
+// it's aggregated from the failure members and the failure stores of all the components.
+type FailureMember struct {
+	// Component is the component of the failure member
+	Component MemberType `json:"component"`
+	// PodName is the name of the Pod of the failure member
+	PodName string `json:"podName"`
+	// ID is the member ID of PD, or the store ID of TiKV and TiFlash, it's empty for TiDB
+	ID string `json:"id,omitempty"`
+	// Deleted is whether the failure member has been deleted, only the failure members of PD are deleted
+	Deleted bool `json:"deleted,omitempty"`
+}
+
 // PumpNodeStatus represents the status saved in etcd.
 type PumpNodeStatus struct {
 	NodeID string `json:"nodeId"`
@@ -1166,6 +1391,12 @@ type TiDBTLSClient struct {
 	//   4. Set Enabled to `true`.
 	// +optional
 	Enabled bool `json:"enabled,omitempty"`
+
+	// RestartOnRenew makes TiDB rolling restart when the server-side certificate in the
+	// <clusterName>-tidb-server-secret is renewed, so the renewed certificate is served.
+	// By default the renewal doesn't restart TiDB.
+	// +optional
+	RestartOnRenew bool `json:"restartOnRenew,omitempty"`
 }
 
 // TLSCluster can enable mutual TLS connection between TiDB cluster components
@@ -1254,9 +1485,17 @@ type StorageProvider struct {
 
 // LocalStorageProvider defines local storage options, which can be any k8s supported mounted volume
 type LocalStorageProvider struct {
-	Volume      corev1.Volume      `json:"volume"`
+	// Volume is the volume to store the backup data, it's ignored if PersistentVolumeClaimName is set
+	// +optional
+	Volume corev1.Volume `json:"volume"`
+	// VolumeMount is the mount of the Volume, it's ignored if PersistentVolumeClaimName is set
+	// +optional
 	VolumeMount corev1.VolumeMount `json:"volumeMount"`
-	Prefix      string             `json:"prefix,omitempty"`
+	// PersistentVolumeClaimName is the name of the PVC to store the backup data, e.g. a PVC backed by NFS.
+	// The PVC is mounted at /backup in the backup, restore and clean Job pods.
+	// +optional
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName,omitempty"`
+	Prefix                    string `json:"prefix,omitempty"`
 }
 
 // S3StorageProvider represents a S3 compliant storage for storing backups.
@@ -1460,6 +1699,66 @@ type BackupSpec struct {
 
 	// PriorityClassName of Backup Job Pods
 	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// BackoffRetryPolicy the backoff retry policy, currently only valid for snapshot backup with BR
+	// +optional
+	BackoffRetryPolicy *BackoffRetryPolicy `json:"backoffRetryPolicy,omitempty"`
+
+	// PodTemplate customizes the Pods of the backup Jobs
+	// +optional
+	PodTemplate *BackupPodTemplate `json:"podTemplate,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// BackupPodTemplate is the customization of the Pods of the backup and restore Jobs.
+// The fields required by the operator, e.g. the service account, the args and env of
+// the main container, take precedence over it on conflict.
+type BackupPodTemplate struct {
+	// Labels of the Pods, the labels set by the operator are not overridden
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations of the Pods, the annotations set by the operator are not overridden
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Affinity of the Pods, it overrides `spec.affinity`
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Tolerations of the Pods, they are appended to `spec.tolerations`
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// NodeSelector of the Pods
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// PriorityClassName of the Pods, it overrides `spec.priorityClassName`
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// AdditionalContainers are appended to the containers of the Pods, e.g. sidecars,
+	// the containers with the same names as the ones of the operator are ignored
+	// +optional
+	AdditionalContainers []corev1.Container `json:"additionalContainers,omitempty"`
+	// AdditionalVolumes are appended to the volumes of the Pods,
+	// the volumes with the same names as the ones of the operator are ignored
+	// +optional
+	AdditionalVolumes []corev1.Volume `json:"additionalVolumes,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// BackoffRetryPolicy is the backoff retry policy of a backup whose job fails with a retriable error.
+// The n-th retry is done at least MinRetryDuration * 2^(n-1) after the failure is detected.
+type BackoffRetryPolicy struct {
+	// MaxRetryTimes is the max times of retry, the backup is not retried if it is 0.
+	// Optional: Defaults to 2
+	// +optional
+	MaxRetryTimes *int32 `json:"maxRetryTimes,omitempty"`
+	// MinRetryDuration is the min duration of the backoff, in the format of Go Duration, e.g. 300s
+	// Optional: Defaults to 300s
+	// +optional
+	MinRetryDuration string `json:"minRetryDuration,omitempty"`
+	// RetryTimeout is the timeout of retry since the first failure is detected, in the format of Go Duration, e.g. 30m.
+	// The backup is not retried any more after the timeout.
+	// Optional: Defaults to 30m
+	// +optional
+	RetryTimeout string `json:"retryTimeout,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -1523,6 +1822,8 @@ const (
 	BackupInvalid BackupConditionType = "Invalid"
 	// BackupPrepare means the backup prepare backup process
 	BackupPrepare BackupConditionType = "Prepare"
+	// BackupRetryTheFailed means the failed backup is being retried according to the backoff retry policy
+	BackupRetryTheFailed BackupConditionType = "RetryTheFailed"
 )
 
 // BackupCondition describes the observed state of a Backup at a certain point.
@@ -1552,6 +1853,44 @@ type BackupStatus struct {
 	// Phase is a user readable state inferred from the underlying Backup conditions
 	Phase      BackupConditionType `json:"phase"`
 	Conditions []BackupCondition   `json:"conditions"`
+	// Progresses is the progress of the steps of the backup, the latest updated step is the last one
+	// +optional
+	Progresses []Progress `json:"progresses,omitempty"`
+	// BackoffRetryStatus is the records of the retries of the failed backup, the latest one is the last
+	// +optional
+	BackoffRetryStatus []BackoffRetryRecord `json:"backoffRetryStatus,omitempty"`
+}
+
+// BackoffRetryRecord is the record of a retry of the failed backup.
+type BackoffRetryRecord struct {
+	// RetryNum is the number of the retry, starting from 1
+	RetryNum int `json:"retryNum"`
+	// DetectFailedAt is the time at which the failure is detected
+	DetectFailedAt *metav1.Time `json:"detectFailedAt,omitempty"`
+	// ExpectedRetryAt is the earliest time at which the backup job is recreated
+	ExpectedRetryAt *metav1.Time `json:"expectedRetryAt,omitempty"`
+	// RealRetryAt is the time at which the backup job is recreated
+	RealRetryAt *metav1.Time `json:"realRetryAt,omitempty"`
+	// RetryReason is the reason why the failure is considered retriable
+	RetryReason string `json:"retryReason,omitempty"`
+	// OriginalReason is the reason of the failure
+	OriginalReason string `json:"originalReason,omitempty"`
+}
+
+// BackupCleanProgressStep is the step name of the progress of cleaning the backup data
+const BackupCleanProgressStep = "Clean"
+
+// Progress is the progress of a step of the backup reported by BR or the clean job.
+type Progress struct {
+	// Step is the name of the step, e.g. "Full backup"
+	Step string `json:"step"`
+	// Progress is the percentage of the step, from 0 to 100
+	Progress float64 `json:"progress"`
+	// DeletedObjects is the number of the objects deleted by the step, only reported by the Clean step
+	// +optional
+	DeletedObjects int64 `json:"deletedObjects,omitempty"`
+	// LastTransitionTime is the time at which the progress was updated
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
 }
 
 // +genclient
@@ -1589,11 +1928,16 @@ type BackupScheduleSpec struct {
 	Pause bool `json:"pause,omitempty"`
 	// MaxBackups is to specify how many backups we want to keep
 	// 0 is magic number to indicate un-limited backups.
-	// if MaxBackups and MaxReservedTime are set at the same time, MaxReservedTime is preferred
-	// and MaxBackups is ignored.
+	// if several of MaxBackups, MaxReservedTime and MaxReservedSpace are set at the same time,
+	// a backup is deleted if any of them requires, i.e. the most aggressive one wins.
+	// The most recent successful backup is never deleted.
 	MaxBackups *int32 `json:"maxBackups,omitempty"`
 	// MaxReservedTime is to specify how long backups we want to keep.
 	MaxReservedTime *string `json:"maxReservedTime,omitempty"`
+	// MaxReservedSpace is to specify the max total size of the completed backups we want to keep,
+	// the oldest backups exceeding it are deleted.
+	// +optional
+	MaxReservedSpace *resource.Quantity `json:"maxReservedSpace,omitempty"`
 	// BackupTemplate is the specification of the backup structure to get scheduled.
 	BackupTemplate BackupSpec `json:"backupTemplate"`
 	// The storageClassName of the persistent volume for Backup data storage if not storage class name set in BackupSpec.
@@ -1605,8 +1949,25 @@ type BackupScheduleSpec struct {
 	// ImagePullSecrets is an optional list of references to secrets in the same namespace to use for pulling any of the images.
 	// +optional
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// ConcurrencyPolicy specifies how to treat a scheduled backup when the last backup is still running,
+	// valid values are Allow, Forbid and Replace. If it's not set, the scheduled backup is delayed
+	// until the last backup finishes.
+	// +optional
+	ConcurrencyPolicy BackupScheduleConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
 }
 
+// BackupScheduleConcurrencyPolicy describes how a scheduled backup is treated when the last backup is still running.
+type BackupScheduleConcurrencyPolicy string
+
+const (
+	// BackupScheduleConcurrencyAllow allows the scheduled backup to run concurrently with the last backup.
+	BackupScheduleConcurrencyAllow BackupScheduleConcurrencyPolicy = "Allow"
+	// BackupScheduleConcurrencyForbid skips the scheduled backup if the last backup is still running.
+	BackupScheduleConcurrencyForbid BackupScheduleConcurrencyPolicy = "Forbid"
+	// BackupScheduleConcurrencyReplace deletes the running last backup and starts the scheduled backup.
+	BackupScheduleConcurrencyReplace BackupScheduleConcurrencyPolicy = "Replace"
+)
+
 // BackupScheduleStatus represents the current state of a BackupSchedule.
 type BackupScheduleStatus struct {
 	// LastBackup represents the last backup.
@@ -1615,6 +1976,15 @@ type BackupScheduleStatus struct {
 	LastBackupTime *metav1.Time `json:"lastBackupTime"`
 	// AllBackupCleanTime represents the time when all backup entries are cleaned up
 	AllBackupCleanTime *metav1.Time `json:"allBackupCleanTime"`
+	// LastSkippedTime represents the last scheduled time skipped because the last backup was still running.
+	// +optional
+	LastSkippedTime *metav1.Time `json:"lastSkippedTime,omitempty"`
+	// LastGCDeletedBackups is the number of the backups deleted by the last backup gc.
+	// +optional
+	LastGCDeletedBackups int32 `json:"lastGCDeletedBackups,omitempty"`
+	// LastGCDeletedBytes is the total size of the backups deleted by the last backup gc.
+	// +optional
+	LastGCDeletedBytes int64 `json:"lastGCDeletedBytes,omitempty"`
 }
 
 // +genclient
@@ -1661,6 +2031,9 @@ const (
 	RestoreRetryFailed RestoreConditionType = "RetryFailed"
 	// RestoreInvalid means invalid restore CR.
 	RestoreInvalid RestoreConditionType = "Invalid"
+	// RestoreVerifying means the backup data has been loaded into tidb cluster
+	// and the restored data is being verified.
+	RestoreVerifying RestoreConditionType = "Verifying"
 )
 
 // RestoreCondition describes the observed state of a Restore at a certain point.
@@ -1740,6 +2113,66 @@ type RestoreSpec struct {
 
 	// PriorityClassName of Restore Job Pods
 	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Verify is the way to verify the restored data after the restore succeeds,
+	// only `checksum` is supported now. The restore is complete after the
+	// verification passes. It's only supported by BR restore.
+	// +optional
+	Verify RestoreVerifyType `json:"verify,omitempty"`
+	// VerifyMaxTableSize is the max size of the tables to verify, e.g. 100Gi,
+	// the larger tables are skipped. Defaults to no limit.
+	// +optional
+	VerifyMaxTableSize string `json:"verifyMaxTableSize,omitempty"`
+
+	// PodTemplate customizes the Pods of the restore Jobs
+	// +optional
+	PodTemplate *BackupPodTemplate `json:"podTemplate,omitempty"`
+}
+
+// RestoreVerifyType represents the way to verify the restored data.
+type RestoreVerifyType string
+
+const (
+	// RestoreVerifyChecksum verifies the checksums of the restored tables
+	// against the checksums recorded in the backup.
+	RestoreVerifyChecksum RestoreVerifyType = "checksum"
+)
+
+// MaxRestoreVerificationTables is the max number of tables recorded in the
+// verification status of a Restore.
+const MaxRestoreVerificationTables = 100
+
+// RestoreTableVerificationResult is the verification result of a table.
+type RestoreTableVerificationResult string
+
+const (
+	// RestoreTableVerificationPassed means the table passes the verification.
+	RestoreTableVerificationPassed RestoreTableVerificationResult = "Passed"
+	// RestoreTableVerificationFailed means the table fails the verification.
+	RestoreTableVerificationFailed RestoreTableVerificationResult = "Failed"
+	// RestoreTableVerificationSkipped means the table is not verified.
+	RestoreTableVerificationSkipped RestoreTableVerificationResult = "Skipped"
+)
+
+// RestoreTableVerification is the verification result of a restored table.
+type RestoreTableVerification struct {
+	// Table is the name of the table in the format of `db.table`.
+	Table  string                         `json:"table"`
+	Result RestoreTableVerificationResult `json:"result"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RestoreVerificationStatus represents the status of the verification of the restored data.
+type RestoreVerificationStatus struct {
+	Type          RestoreVerifyType `json:"type"`
+	PassedTables  int32             `json:"passedTables"`
+	FailedTables  int32             `json:"failedTables"`
+	SkippedTables int32             `json:"skippedTables"`
+	// Tables are the verification results of the tables, the failed tables come first.
+	// At most MaxRestoreVerificationTables tables are recorded.
+	// +optional
+	Tables []RestoreTableVerification `json:"tables,omitempty"`
 }
 
 // RestoreStatus represents the current status of a tidb cluster restore.
@@ -1753,6 +2186,9 @@ type RestoreStatus struct {
 	// Phase is a user readable state inferred from the underlying Restore conditions
 	Phase      RestoreConditionType `json:"phase"`
 	Conditions []RestoreCondition   `json:"conditions"`
+	// Verification is the status of the verification of the restored data.
+	// +optional
+	Verification *RestoreVerificationStatus `json:"verification,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -2075,6 +2511,10 @@ const (
 	// - All Master members are healthy.
 	// - All Worker pods are up.
 	DMClusterReady DMClusterConditionType = "Ready"
+	// DMClusterPodManagementPolicyNotApplied indicates that the podManagementPolicy of a component
+	// differs from the one of its existing StatefulSet, and it can not be applied
+	// because the field of the StatefulSet is immutable.
+	DMClusterPodManagementPolicyNotApplied DMClusterConditionType = "PodManagementPolicyNotApplied"
 )
 
 // MasterStatus is dm-master status
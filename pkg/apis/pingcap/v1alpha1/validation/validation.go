@@ -21,6 +21,7 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/prometheus/common/model"
+	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
@@ -121,6 +123,11 @@ func validateTiDBClusterSpec(spec *v1alpha1.TidbClusterSpec, fldPath *field.Path
 	if spec.PDAddresses != nil {
 		allErrs = append(allErrs, validatePDAddresses(spec.PDAddresses, fldPath.Child("pdAddresses"))...)
 	}
+	switch spec.TerminationPolicy {
+	case "", v1alpha1.TerminationPolicyRetain, v1alpha1.TerminationPolicyDelete:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("terminationPolicy"), spec.TerminationPolicy, []string{string(v1alpha1.TerminationPolicyRetain), string(v1alpha1.TerminationPolicyDelete)}))
+	}
 	return allErrs
 }
 
@@ -135,10 +142,25 @@ func validateDiscoverySpec(spec v1alpha1.DiscoverySpec, fldPath *field.Path) fie
 func validatePDSpec(spec *v1alpha1.PDSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	allErrs = append(allErrs, validateTimeDurationStr(spec.FailoverEventInterval, fldPath.Child("failoverEventInterval"))...)
 	allErrs = append(allErrs, validateRequestsStorage(spec.ResourceRequirements.Requests, fldPath)...)
 	if len(spec.StorageVolumes) > 0 {
 		allErrs = append(allErrs, validateStorageVolumes(spec.StorageVolumes, fldPath.Child("storageVolumes"))...)
 	}
+	// the pod management policy of PD is managed by the operator
+	if len(spec.PodManagementPolicy) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("podManagementPolicy"), "podManagementPolicy can not be set for PD"))
+	}
+	switch spec.FailoverMode {
+	case "", v1alpha1.FailoverModeFull, v1alpha1.FailoverModePodOnly:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("failoverMode"), spec.FailoverMode, []string{string(v1alpha1.FailoverModeFull), string(v1alpha1.FailoverModePodOnly)}))
+	}
+	for i, id := range spec.ProtectedMemberIDs {
+		if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("protectedMemberIDs").Index(i), id, "must be a numeric PD member id"))
+		}
+	}
 	return allErrs
 }
 
@@ -168,6 +190,19 @@ func validateTiKVSpec(spec *v1alpha1.TiKVSpec, fldPath *field.Path) field.ErrorL
 		allErrs = append(allErrs, validateStorageVolumes(spec.StorageVolumes, fldPath.Child("storageVolumes"))...)
 	}
 	allErrs = append(allErrs, validateTimeDurationStr(spec.EvictLeaderTimeout, fldPath.Child("evictLeaderTimeout"))...)
+	allErrs = append(allErrs, validateTimeDurationStr(spec.FailoverEventInterval, fldPath.Child("failoverEventInterval"))...)
+	if spec.ScalePolicy != nil {
+		allErrs = append(allErrs, validateTimeDurationStr(spec.ScalePolicy.PreScaleInJobTimeout, fldPath.Child("scalePolicy", "preScaleInJobTimeout"))...)
+		if ds := spec.ScalePolicy.NewPVCDataSource; ds != nil {
+			dsPath := fldPath.Child("scalePolicy", "newPVCDataSource")
+			if len(ds.Kind) == 0 {
+				allErrs = append(allErrs, field.Required(dsPath.Child("kind"), "kind of the data source must be set"))
+			}
+			if len(ds.Name) == 0 {
+				allErrs = append(allErrs, field.Required(dsPath.Child("name"), "name of the data source must be set"))
+			}
+		}
+	}
 	return allErrs
 }
 
@@ -175,6 +210,7 @@ func validateTiFlashSpec(spec *v1alpha1.TiFlashSpec, fldPath *field.Path) field.
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
 	allErrs = append(allErrs, validateTiFlashConfig(spec.Config, fldPath)...)
+	allErrs = append(allErrs, validateTimeDurationStr(spec.FailoverEventInterval, fldPath.Child("failoverEventInterval"))...)
 	if len(spec.StorageClaims) < 1 {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("spec.StorageClaims"),
 			spec.StorageClaims, "storageClaims should be configured at least one item."))
@@ -245,6 +281,7 @@ func validateTiFlashConfig(config *v1alpha1.TiFlashConfigWraper, path *field.Pat
 func validateTiDBSpec(spec *v1alpha1.TiDBSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	allErrs = append(allErrs, validateTimeDurationStr(spec.FailoverEventInterval, fldPath.Child("failoverEventInterval"))...)
 	if spec.Service != nil {
 		allErrs = append(allErrs, validateService(&spec.Service.ServiceSpec, fldPath)...)
 	}
@@ -308,6 +345,17 @@ func validateComponentSpec(spec *v1alpha1.ComponentSpec, fldPath *field.Path) fi
 	// TODO validate other fields
 	allErrs = append(allErrs, validateEnv(spec.Env, fldPath.Child("env"))...)
 	allErrs = append(allErrs, validateAdditionalContainers(spec.AdditionalContainers, fldPath.Child("additionalContainers"))...)
+	allErrs = append(allErrs, validatePodManagementPolicy(spec.PodManagementPolicy, fldPath.Child("podManagementPolicy"))...)
+	return allErrs
+}
+
+func validatePodManagementPolicy(policy apps.PodManagementPolicyType, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch policy {
+	case "", apps.OrderedReadyPodManagement, apps.ParallelPodManagement:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath, policy, []string{string(apps.OrderedReadyPodManagement), string(apps.ParallelPodManagement)}))
+	}
 	return allErrs
 }
 
@@ -457,6 +505,7 @@ func ValidateCreateTidbCluster(tc *v1alpha1.TidbCluster) field.ErrorList {
 	// basic validation
 	allErrs = append(allErrs, ValidateTidbCluster(tc)...)
 	allErrs = append(allErrs, validateNewTidbClusterSpec(&tc.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validatePDReplicas(tc, field.NewPath("spec", "pd", "replicas"))...)
 	return allErrs
 }
 
@@ -472,7 +521,130 @@ func ValidateUpdateTidbCluster(old, tc *v1alpha1.TidbCluster) field.ErrorList {
 	}
 	allErrs = append(allErrs, validateUpdatePDConfig(old.Spec.PD.Config, tc.Spec.PD.Config, field.NewPath("spec.pd.config"))...)
 	allErrs = append(allErrs, disallowUsingLegacyAPIInNewCluster(old, tc)...)
+	allErrs = append(allErrs, validateUpdateStorage(old, tc)...)
+	// the existing clusters with an even number of PD replicas are not affected until the replicas are changed
+	if old.Spec.PD == nil || tc.Spec.PD == nil || old.Spec.PD.Replicas != tc.Spec.PD.Replicas {
+		allErrs = append(allErrs, validatePDReplicas(tc, field.NewPath("spec", "pd", "replicas"))...)
+	}
+	allErrs = append(allErrs, validateUpdateTiKVReplicas(old, tc)...)
+
+	return allErrs
+}
+
+// validatePDReplicas rejects an even number of PD replicas, which tolerates no more failures than one replica less,
+// unless it's allowed by the annotation
+func validatePDReplicas(tc *v1alpha1.TidbCluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if tc.Spec.PD == nil || tc.Annotations[label.AnnAllowEvenPDReplicasKey] == label.AnnAllowEvenPDReplicasVal {
+		return allErrs
+	}
+	replicas := tc.Spec.PD.Replicas
+	if replicas >= 2 && replicas%2 == 0 {
+		msg := fmt.Sprintf("an even number of PD replicas tolerates no more failures than %d replicas, set annotation %q to %q to allow it",
+			replicas-1, label.AnnAllowEvenPDReplicasKey, label.AnnAllowEvenPDReplicasVal)
+		allErrs = append(allErrs, field.Invalid(fldPath, replicas, msg))
+	}
+	return allErrs
+}
+
+// validateUpdateTiKVReplicas rejects removing all TiKV while TiFlash replicas remain, TiFlash can't work without TiKV
+func validateUpdateTiKVReplicas(old, tc *v1alpha1.TidbCluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if old.Spec.TiKV == nil || old.Spec.TiKV.Replicas == 0 {
+		return allErrs
+	}
+	if tc.Spec.TiFlash == nil || tc.Spec.TiFlash.Replicas == 0 {
+		return allErrs
+	}
+	path := field.NewPath("spec", "tikv")
+	if tc.Spec.TiKV == nil {
+		allErrs = append(allErrs, field.Forbidden(path, "TiKV can not be removed while spec.tiflash.replicas is not 0"))
+	} else if tc.Spec.TiKV.Replicas == 0 {
+		allErrs = append(allErrs, field.Forbidden(path.Child("replicas"), "all TiKV can not be removed while spec.tiflash.replicas is not 0"))
+	}
+	return allErrs
+}
+
+// validateUpdateStorage rejects changing the storage class and shrinking the storage requests, the storage class of
+// PVCs is immutable and PVCs can't be shrunk, the operator gets stuck otherwise
+func validateUpdateStorage(old, tc *v1alpha1.TidbCluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+	path := field.NewPath("spec")
+	if old.Spec.PD != nil && tc.Spec.PD != nil {
+		pdPath := path.Child("pd")
+		allErrs = append(allErrs, validateUpdateStorageClassName(old.Spec.PD.StorageClassName, tc.Spec.PD.StorageClassName, pdPath.Child("storageClassName"))...)
+		allErrs = append(allErrs, validateUpdateStorageRequest(old.Spec.PD.Requests, tc.Spec.PD.Requests, pdPath.Child("requests", "storage"))...)
+		allErrs = append(allErrs, validateUpdateStorageVolumes(old.Spec.PD.StorageVolumes, tc.Spec.PD.StorageVolumes, pdPath.Child("storageVolumes"))...)
+	}
+	if old.Spec.TiKV != nil && tc.Spec.TiKV != nil {
+		tikvPath := path.Child("tikv")
+		allErrs = append(allErrs, validateUpdateStorageClassName(old.Spec.TiKV.StorageClassName, tc.Spec.TiKV.StorageClassName, tikvPath.Child("storageClassName"))...)
+		allErrs = append(allErrs, validateUpdateStorageRequest(old.Spec.TiKV.Requests, tc.Spec.TiKV.Requests, tikvPath.Child("requests", "storage"))...)
+		allErrs = append(allErrs, validateUpdateStorageVolumes(old.Spec.TiKV.StorageVolumes, tc.Spec.TiKV.StorageVolumes, tikvPath.Child("storageVolumes"))...)
+	}
+	if old.Spec.TiDB != nil && tc.Spec.TiDB != nil {
+		allErrs = append(allErrs, validateUpdateStorageVolumes(old.Spec.TiDB.StorageVolumes, tc.Spec.TiDB.StorageVolumes, path.Child("tidb", "storageVolumes"))...)
+	}
+	if old.Spec.TiFlash != nil && tc.Spec.TiFlash != nil {
+		claimsPath := path.Child("tiflash", "storageClaims")
+		for i, claim := range tc.Spec.TiFlash.StorageClaims {
+			if i >= len(old.Spec.TiFlash.StorageClaims) {
+				break
+			}
+			oldClaim := old.Spec.TiFlash.StorageClaims[i]
+			allErrs = append(allErrs, validateUpdateStorageClassName(oldClaim.StorageClassName, claim.StorageClassName, claimsPath.Index(i).Child("storageClassName"))...)
+			allErrs = append(allErrs, validateUpdateStorageRequest(oldClaim.Resources.Requests, claim.Resources.Requests, claimsPath.Index(i).Child("resources", "requests", "storage"))...)
+		}
+	}
+	return allErrs
+}
+
+func validateUpdateStorageClassName(old, storageClassName *string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !reflect.DeepEqual(old, storageClassName) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "storageClassName is immutable, the storage class of the existing volumes can not be changed"))
+	}
+	return allErrs
+}
+
+func validateUpdateStorageRequest(old, requests corev1.ResourceList, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	oldSize, ok := old[corev1.ResourceStorage]
+	if !ok {
+		return allErrs
+	}
+	size, ok := requests[corev1.ResourceStorage]
+	if ok && size.Cmp(oldSize) < 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("storage request can not be shrunk from %s to %s, the existing volumes can not be shrunk", oldSize.String(), size.String())))
+	}
+	return allErrs
+}
 
+func validateUpdateStorageVolumes(old, storageVolumes []v1alpha1.StorageVolume, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	oldVolumes := map[string]v1alpha1.StorageVolume{}
+	for _, volume := range old {
+		oldVolumes[volume.Name] = volume
+	}
+	for i, volume := range storageVolumes {
+		oldVolume, ok := oldVolumes[volume.Name]
+		if !ok {
+			continue
+		}
+		idxPath := fldPath.Index(i)
+		allErrs = append(allErrs, validateUpdateStorageClassName(oldVolume.StorageClassName, volume.StorageClassName, idxPath.Child("storageClassName"))...)
+		oldSize, err := resource.ParseQuantity(oldVolume.StorageSize)
+		if err != nil {
+			continue
+		}
+		size, err := resource.ParseQuantity(volume.StorageSize)
+		if err != nil {
+			continue
+		}
+		if size.Cmp(oldSize) < 0 {
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("storageSize"), fmt.Sprintf("storageSize can not be shrunk from %s to %s, the existing volumes can not be shrunk", oldVolume.StorageSize, volume.StorageSize)))
+		}
+	}
 	return allErrs
 }
 
@@ -20,6 +20,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -491,6 +492,195 @@ func TestValidateDMCluster(t *testing.T) {
 	}
 }
 
+func TestValidateUpdateTidbClusterStorageAndReplicas(t *testing.T) {
+	newTC := func() *v1alpha1.TidbCluster {
+		tc := newTidbCluster()
+		tc.Spec.PD.Replicas = 3
+		tc.Spec.PD.ResourceRequirements.Requests = corev1.ResourceList{
+			corev1.ResourceStorage: resource.MustParse("1Gi"),
+		}
+		tc.Spec.PD.StorageVolumes = []v1alpha1.StorageVolume{{Name: "log", StorageSize: "1Gi"}}
+		tc.Spec.TiKV.Replicas = 3
+		tc.Spec.TiKV.StorageClassName = pointer.StringPtr("local-storage")
+		tc.Spec.TiKV.ResourceRequirements.Requests = corev1.ResourceList{
+			corev1.ResourceStorage: resource.MustParse("10Gi"),
+		}
+		tc.Spec.TiFlash = &v1alpha1.TiFlashSpec{
+			Replicas: 1,
+			StorageClaims: []v1alpha1.StorageClaim{{
+				StorageClassName: pointer.StringPtr("local-storage"),
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("10Gi"),
+					},
+				},
+			}},
+		}
+		return tc
+	}
+
+	tests := []struct {
+		name      string
+		update    func(tc *v1alpha1.TidbCluster)
+		old       func(tc *v1alpha1.TidbCluster)
+		errFields []string
+	}{
+		{
+			name:   "no change",
+			update: func(tc *v1alpha1.TidbCluster) {},
+		},
+		{
+			name: "change storageClassName of TiKV",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV.StorageClassName = pointer.StringPtr("ebs")
+			},
+			errFields: []string{"spec.tikv.storageClassName"},
+		},
+		{
+			name: "set storageClassName of PD",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.PD.StorageClassName = pointer.StringPtr("ebs")
+			},
+			errFields: []string{"spec.pd.storageClassName"},
+		},
+		{
+			name: "change storageClassName of TiFlash storage claim",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiFlash.StorageClaims[0].StorageClassName = pointer.StringPtr("ebs")
+			},
+			errFields: []string{"spec.tiflash.storageClaims[0].storageClassName"},
+		},
+		{
+			name: "shrink storage request of TiKV",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV.Requests[corev1.ResourceStorage] = resource.MustParse("5Gi")
+			},
+			errFields: []string{"spec.tikv.requests.storage"},
+		},
+		{
+			name: "expand storage request of TiKV",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV.Requests[corev1.ResourceStorage] = resource.MustParse("20Gi")
+			},
+		},
+		{
+			name: "shrink storage volume of PD",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.PD.StorageVolumes[0].StorageSize = "512Mi"
+			},
+			errFields: []string{"spec.pd.storageVolumes[0].storageSize"},
+		},
+		{
+			name: "shrink storage request of TiFlash storage claim",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiFlash.StorageClaims[0].Resources.Requests[corev1.ResourceStorage] = resource.MustParse("5Gi")
+			},
+			errFields: []string{"spec.tiflash.storageClaims[0].resources.requests.storage"},
+		},
+		{
+			name: "even PD replicas",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.PD.Replicas = 4
+			},
+			errFields: []string{"spec.pd.replicas"},
+		},
+		{
+			name: "even PD replicas with the override annotation",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Annotations = map[string]string{label.AnnAllowEvenPDReplicasKey: label.AnnAllowEvenPDReplicasVal}
+				tc.Spec.PD.Replicas = 4
+			},
+		},
+		{
+			name: "existing even PD replicas",
+			old: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.PD.Replicas = 2
+			},
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.PD.Replicas = 2
+			},
+		},
+		{
+			name: "remove TiKV while TiFlash remains",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV = nil
+			},
+			errFields: []string{"spec.tikv"},
+		},
+		{
+			name: "scale in all TiKV while TiFlash remains",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV.Replicas = 0
+			},
+			errFields: []string{"spec.tikv.replicas"},
+		},
+		{
+			name: "scale in all TiKV and TiFlash",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV.Replicas = 0
+				tc.Spec.TiFlash.Replicas = 0
+			},
+		},
+	}
+
+	// the rules checked in this test
+	ruleFields := []string{
+		"spec.pd.storageClassName",
+		"spec.pd.requests.storage",
+		"spec.pd.storageVolumes[0].storageSize",
+		"spec.pd.replicas",
+		"spec.tikv",
+		"spec.tikv.storageClassName",
+		"spec.tikv.requests.storage",
+		"spec.tikv.replicas",
+		"spec.tiflash.storageClaims[0].storageClassName",
+		"spec.tiflash.storageClaims[0].resources.requests.storage",
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			old := newTC()
+			if tt.old != nil {
+				tt.old(old)
+			}
+			tc := old.DeepCopy()
+			tt.update(tc)
+
+			var errFields []string
+			for _, err := range ValidateUpdateTidbCluster(old, tc) {
+				for _, f := range ruleFields {
+					if err.Field == f {
+						errFields = append(errFields, err.Field)
+					}
+				}
+			}
+			if len(tt.errFields) == 0 {
+				g.Expect(errFields).To(BeEmpty())
+			} else {
+				g.Expect(errFields).To(Equal(tt.errFields))
+			}
+		})
+	}
+}
+
+func TestValidateCreateTidbClusterPDReplicas(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbCluster()
+	tc.Spec.PD.Replicas = 2
+	errs := validatePDReplicas(tc, field.NewPath("spec", "pd", "replicas"))
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Field).To(Equal("spec.pd.replicas"))
+	g.Expect(errs[0].Detail).To(ContainSubstring(label.AnnAllowEvenPDReplicasKey))
+
+	tc.Spec.PD.Replicas = 1
+	g.Expect(validatePDReplicas(tc, field.NewPath("spec", "pd", "replicas"))).To(BeEmpty())
+
+	tc.Spec.PD.Replicas = 2
+	tc.Annotations = map[string]string{label.AnnAllowEvenPDReplicasKey: label.AnnAllowEvenPDReplicasVal}
+	g.Expect(validatePDReplicas(tc, field.NewPath("spec", "pd", "replicas"))).To(BeEmpty())
+}
+
 func newTidbCluster() *v1alpha1.TidbCluster {
 	tc := &v1alpha1.TidbCluster{
 		Spec: v1alpha1.TidbClusterSpec{
@@ -587,6 +777,73 @@ func TestValidateEvictLeaderTimeout(t *testing.T) {
 	}
 }
 
+func TestValidatePodManagementPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, policy := range []apps.PodManagementPolicyType{"", apps.OrderedReadyPodManagement, apps.ParallelPodManagement} {
+		errs := validatePodManagementPolicy(policy, field.NewPath("podManagementPolicy"))
+		g.Expect(errs).To(BeEmpty())
+	}
+	errs := validatePodManagementPolicy("Random", field.NewPath("podManagementPolicy"))
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+
+	tc := newTidbCluster()
+	tc.Spec.PD.ResourceRequirements.Requests = corev1.ResourceList{
+		corev1.ResourceStorage: resource.MustParse("1Gi"),
+	}
+	tc.Spec.TiKV.ResourceRequirements.Requests = corev1.ResourceList{
+		corev1.ResourceStorage: resource.MustParse("1Gi"),
+	}
+	tc.Spec.PD.PodManagementPolicy = apps.ParallelPodManagement
+	tc.Spec.TiKV.PodManagementPolicy = apps.ParallelPodManagement
+	errs = validatePDSpec(tc.Spec.PD, field.NewPath("spec", "pd"))
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+	g.Expect(errs[0].Field).To(Equal("spec.pd.podManagementPolicy"))
+	errs = validateTiKVSpec(tc.Spec.TiKV, field.NewPath("spec", "tikv"))
+	g.Expect(errs).To(BeEmpty())
+}
+
+func TestValidatePDProtectedMemberIDs(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbCluster()
+	tc.Spec.PD.ResourceRequirements.Requests = corev1.ResourceList{
+		corev1.ResourceStorage: resource.MustParse("1Gi"),
+	}
+	tc.Spec.PD.ProtectedMemberIDs = []string{"12891273174085095651", "1"}
+	errs := validatePDSpec(tc.Spec.PD, field.NewPath("spec", "pd"))
+	g.Expect(errs).To(BeEmpty())
+
+	tc.Spec.PD.ProtectedMemberIDs = []string{"12891273174085095651", "pd-0", "-1"}
+	errs = validatePDSpec(tc.Spec.PD, field.NewPath("spec", "pd"))
+	g.Expect(errs).To(HaveLen(2))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+	g.Expect(errs[0].Field).To(Equal("spec.pd.protectedMemberIDs[1]"))
+	g.Expect(errs[1].Field).To(Equal("spec.pd.protectedMemberIDs[2]"))
+}
+
+func TestValidateTerminationPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	terminationPolicyErrs := func(errs field.ErrorList) field.ErrorList {
+		return errs.Filter(func(err error) bool {
+			return err.(*field.Error).Field != "spec.terminationPolicy"
+		})
+	}
+	tc := newTidbCluster()
+	for _, policy := range []v1alpha1.TerminationPolicy{"", v1alpha1.TerminationPolicyRetain, v1alpha1.TerminationPolicyDelete} {
+		tc.Spec.TerminationPolicy = policy
+		g.Expect(terminationPolicyErrs(validateTiDBClusterSpec(&tc.Spec, field.NewPath("spec")))).To(BeEmpty())
+	}
+
+	tc.Spec.TerminationPolicy = "Recycle"
+	errs := terminationPolicyErrs(validateTiDBClusterSpec(&tc.Spec, field.NewPath("spec")))
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+}
+
 func TestValidatePromDurationStr(t *testing.T) {
 	successCases := []*string{
 		nil,
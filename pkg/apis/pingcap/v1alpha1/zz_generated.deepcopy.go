@@ -22,9 +22,11 @@ import (
 
 	model "github.com/prometheus/common/model"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	v1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	v1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	types "k8s.io/apimachinery/pkg/types"
 )
@@ -79,6 +81,23 @@ func (in *AutoRule) DeepCopy() *AutoRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoScalingRecommendation) DeepCopyInto(out *AutoScalingRecommendation) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoScalingRecommendation.
+func (in *AutoScalingRecommendation) DeepCopy() *AutoScalingRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoScalingRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BRConfig) DeepCopyInto(out *BRConfig) {
 	*out = *in
@@ -125,6 +144,55 @@ func (in *BRConfig) DeepCopy() *BRConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackoffRetryPolicy) DeepCopyInto(out *BackoffRetryPolicy) {
+	*out = *in
+	if in.MaxRetryTimes != nil {
+		in, out := &in.MaxRetryTimes, &out.MaxRetryTimes
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackoffRetryPolicy.
+func (in *BackoffRetryPolicy) DeepCopy() *BackoffRetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(BackoffRetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackoffRetryRecord) DeepCopyInto(out *BackoffRetryRecord) {
+	*out = *in
+	if in.DetectFailedAt != nil {
+		in, out := &in.DetectFailedAt, &out.DetectFailedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpectedRetryAt != nil {
+		in, out := &in.ExpectedRetryAt, &out.ExpectedRetryAt
+		*out = (*in).DeepCopy()
+	}
+	if in.RealRetryAt != nil {
+		in, out := &in.RealRetryAt, &out.RealRetryAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackoffRetryRecord.
+func (in *BackoffRetryRecord) DeepCopy() *BackoffRetryRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(BackoffRetryRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Backup) DeepCopyInto(out *Backup) {
 	*out = *in
@@ -203,6 +271,69 @@ func (in *BackupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupPodTemplate) DeepCopyInto(out *BackupPodTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AdditionalContainers != nil {
+		in, out := &in.AdditionalContainers, &out.AdditionalContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalVolumes != nil {
+		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupPodTemplate.
+func (in *BackupPodTemplate) DeepCopy() *BackupPodTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupPodTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupSchedule) DeepCopyInto(out *BackupSchedule) {
 	*out = *in
@@ -277,6 +408,11 @@ func (in *BackupScheduleSpec) DeepCopyInto(out *BackupScheduleSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.MaxReservedSpace != nil {
+		in, out := &in.MaxReservedSpace, &out.MaxReservedSpace
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 	in.BackupTemplate.DeepCopyInto(&out.BackupTemplate)
 	if in.StorageClassName != nil {
 		in, out := &in.StorageClassName, &out.StorageClassName
@@ -312,6 +448,10 @@ func (in *BackupScheduleStatus) DeepCopyInto(out *BackupScheduleStatus) {
 		in, out := &in.AllBackupCleanTime, &out.AllBackupCleanTime
 		*out = (*in).DeepCopy()
 	}
+	if in.LastSkippedTime != nil {
+		in, out := &in.LastSkippedTime, &out.LastSkippedTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -394,6 +534,16 @@ func (in *BackupSpec) DeepCopyInto(out *BackupSpec) {
 		*out = new(v1.PodSecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BackoffRetryPolicy != nil {
+		in, out := &in.BackoffRetryPolicy, &out.BackoffRetryPolicy
+		*out = new(BackoffRetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodTemplate != nil {
+		in, out := &in.PodTemplate, &out.PodTemplate
+		*out = new(BackupPodTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -419,6 +569,20 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Progresses != nil {
+		in, out := &in.Progresses, &out.Progresses
+		*out = make([]Progress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BackoffRetryStatus != nil {
+		in, out := &in.BackoffRetryStatus, &out.BackoffRetryStatus
+		*out = make([]BackoffRetryRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -502,6 +666,14 @@ func (in *BasicAutoScalerStatus) DeepCopyInto(out *BasicAutoScalerStatus) {
 		in, out := &in.LastAutoScalingTimestamp, &out.LastAutoScalingTimestamp
 		*out = (*in).DeepCopy()
 	}
+	if in.LastScaleOutTimestamp != nil {
+		in, out := &in.LastScaleOutTimestamp, &out.LastScaleOutTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScaleInTimestamp != nil {
+		in, out := &in.LastScaleInTimestamp, &out.LastScaleInTimestamp
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -1487,6 +1659,27 @@ func (in *Experimental) DeepCopy() *Experimental {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalClusterSpec) DeepCopyInto(out *ExternalClusterSpec) {
+	*out = *in
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(TiDBSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalClusterSpec.
+func (in *ExternalClusterSpec) DeepCopy() *ExternalClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalConfig) DeepCopyInto(out *ExternalConfig) {
 	*out = *in
@@ -1525,6 +1718,47 @@ func (in *ExternalEndpoint) DeepCopy() *ExternalEndpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverSummaryStatus) DeepCopyInto(out *FailoverSummaryStatus) {
+	*out = *in
+	in.LastSummaryTime.DeepCopyInto(&out.LastSummaryTime)
+	in.LastFailoverTime.DeepCopyInto(&out.LastFailoverTime)
+	if in.Counts != nil {
+		in, out := &in.Counts, &out.Counts
+		*out = make(map[MemberType]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverSummaryStatus.
+func (in *FailoverSummaryStatus) DeepCopy() *FailoverSummaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverSummaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailureMember) DeepCopyInto(out *FailureMember) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureMember.
+func (in *FailureMember) DeepCopy() *FailureMember {
+	if in == nil {
+		return nil
+	}
+	out := new(FailureMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FileLogConfig) DeepCopyInto(out *FileLogConfig) {
 	*out = *in
@@ -1999,6 +2233,11 @@ func (in *GrafanaSpec) DeepCopyInto(out *GrafanaSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ExtraDashboardsConfigMaps != nil {
+		in, out := &in.ExtraDashboardsConfigMaps, &out.ExtraDashboardsConfigMaps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -2073,6 +2312,22 @@ func (in *IngressSpec) DeepCopy() *IngressSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InitSqlFailure) DeepCopyInto(out *InitSqlFailure) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitSqlFailure.
+func (in *InitSqlFailure) DeepCopy() *InitSqlFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(InitSqlFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InitializerSpec) DeepCopyInto(out *InitializerSpec) {
 	*out = *in
@@ -3384,6 +3639,26 @@ func (in *PDSpec) DeepCopyInto(out *PDSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.FailoverEventInterval != nil {
+		in, out := &in.FailoverEventInterval, &out.FailoverEventInterval
+		*out = new(string)
+		**out = **in
+	}
+	if in.WaitForMemberDeleted != nil {
+		in, out := &in.WaitForMemberDeleted, &out.WaitForMemberDeleted
+		*out = new(bool)
+		**out = **in
+	}
+	if in.GracePeriodSeconds != nil {
+		in, out := &in.GracePeriodSeconds, &out.GracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ProtectedMemberIDs != nil {
+		in, out := &in.ProtectedMemberIDs, &out.ProtectedMemberIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.StorageClassName != nil {
 		in, out := &in.StorageClassName, &out.StorageClassName
 		*out = new(string)
@@ -3466,6 +3741,17 @@ func (in *PDStatus) DeepCopyInto(out *PDStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.UnsyncedSince != nil {
+		in, out := &in.UnsyncedSince, &out.UnsyncedSince
+		*out = (*in).DeepCopy()
+	}
+	if in.RecoveredMembers != nil {
+		in, out := &in.RecoveredMembers, &out.RecoveredMembers
+		*out = make(map[string]metav1.Time, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -3778,6 +4064,23 @@ func (in *Profile) DeepCopy() *Profile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Progress) DeepCopyInto(out *Progress) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Progress.
+func (in *Progress) DeepCopy() *Progress {
+	if in == nil {
+		return nil
+	}
+	out := new(Progress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrometheusConfiguration) DeepCopyInto(out *PrometheusConfiguration) {
 	*out = *in
@@ -3864,6 +4167,11 @@ func (in *PrometheusSpec) DeepCopyInto(out *PrometheusSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ExtraRulesConfigMaps != nil {
+		in, out := &in.ExtraRulesConfigMaps, &out.ExtraRulesConfigMaps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -4165,6 +4473,11 @@ func (in *RemoteWriteSpec) DeepCopyInto(out *RemoteWriteSpec) {
 		*out = new(BasicAuth)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BearerTokenSecret != nil {
+		in, out := &in.BearerTokenSecret, &out.BearerTokenSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TLSConfig != nil {
 		in, out := &in.TLSConfig, &out.TLSConfig
 		*out = new(TLSConfig)
@@ -4330,6 +4643,11 @@ func (in *RestoreSpec) DeepCopyInto(out *RestoreSpec) {
 		*out = new(v1.PodSecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PodTemplate != nil {
+		in, out := &in.PodTemplate, &out.PodTemplate
+		*out = new(BackupPodTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -4355,6 +4673,11 @@ func (in *RestoreStatus) DeepCopyInto(out *RestoreStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(RestoreVerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -4368,6 +4691,43 @@ func (in *RestoreStatus) DeepCopy() *RestoreStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreTableVerification) DeepCopyInto(out *RestoreTableVerification) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreTableVerification.
+func (in *RestoreTableVerification) DeepCopy() *RestoreTableVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreTableVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreVerificationStatus) DeepCopyInto(out *RestoreVerificationStatus) {
+	*out = *in
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]RestoreTableVerification, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreVerificationStatus.
+func (in *RestoreVerificationStatus) DeepCopy() *RestoreVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *S3StorageProvider) DeepCopyInto(out *S3StorageProvider) {
 	*out = *in
@@ -4658,6 +5018,51 @@ func (in *StmtSummary) DeepCopy() *StmtSummary {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageAutoScalerSpec) DeepCopyInto(out *StorageAutoScalerSpec) {
+	*out = *in
+	if in.StabilizationWindowSeconds != nil {
+		in, out := &in.StabilizationWindowSeconds, &out.StabilizationWindowSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageAutoScalerSpec.
+func (in *StorageAutoScalerSpec) DeepCopy() *StorageAutoScalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageAutoScalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageAutoScalerStatus) DeepCopyInto(out *StorageAutoScalerStatus) {
+	*out = *in
+	if in.ThresholdExceededTimestamp != nil {
+		in, out := &in.ThresholdExceededTimestamp, &out.ThresholdExceededTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.LastEvaluationTimestamp != nil {
+		in, out := &in.LastEvaluationTimestamp, &out.LastEvaluationTimestamp
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageAutoScalerStatus.
+func (in *StorageAutoScalerStatus) DeepCopy() *StorageAutoScalerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageAutoScalerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageClaim) DeepCopyInto(out *StorageClaim) {
 	*out = *in
@@ -5364,6 +5769,11 @@ func (in *TiDBSpec) DeepCopyInto(out *TiDBSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.FailoverEventInterval != nil {
+		in, out := &in.FailoverEventInterval, &out.FailoverEventInterval
+		*out = new(string)
+		**out = **in
+	}
 	if in.SeparateSlowLog != nil {
 		in, out := &in.SeparateSlowLog, &out.SeparateSlowLog
 		*out = new(bool)
@@ -5582,6 +5992,11 @@ func (in *TiFlashSpec) DeepCopyInto(out *TiFlashSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.FailoverEventInterval != nil {
+		in, out := &in.FailoverEventInterval, &out.FailoverEventInterval
+		*out = new(string)
+		**out = **in
+	}
 	if in.StorageClaims != nil {
 		in, out := &in.StorageClaims, &out.StorageClaims
 		*out = make([]StorageClaim, len(*in))
@@ -7102,6 +7517,37 @@ func (in *TiKVReadPoolConfig) DeepCopy() *TiKVReadPoolConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVScalePolicy) DeepCopyInto(out *TiKVScalePolicy) {
+	*out = *in
+	if in.PreScaleInJobTemplate != nil {
+		in, out := &in.PreScaleInJobTemplate, &out.PreScaleInJobTemplate
+		*out = new(batchv1beta1.JobTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreScaleInJobTimeout != nil {
+		in, out := &in.PreScaleInJobTimeout, &out.PreScaleInJobTimeout
+		*out = new(string)
+		**out = **in
+	}
+	if in.NewPVCDataSource != nil {
+		in, out := &in.NewPVCDataSource, &out.NewPVCDataSource
+		*out = new(v1.TypedLocalObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVScalePolicy.
+func (in *TiKVScalePolicy) DeepCopy() *TiKVScalePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVScalePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiKVSecurityConfig) DeepCopyInto(out *TiKVSecurityConfig) {
 	*out = *in
@@ -7403,6 +7849,11 @@ func (in *TiKVSpec) DeepCopyInto(out *TiKVSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.FailoverEventInterval != nil {
+		in, out := &in.FailoverEventInterval, &out.FailoverEventInterval
+		*out = new(string)
+		**out = **in
+	}
 	if in.SeparateRocksDBLog != nil {
 		in, out := &in.SeparateRocksDBLog, &out.SeparateRocksDBLog
 		*out = new(bool)
@@ -7450,6 +7901,11 @@ func (in *TiKVSpec) DeepCopyInto(out *TiKVSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ScalePolicy != nil {
+		in, out := &in.ScalePolicy, &out.ScalePolicy
+		*out = new(TiKVScalePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -7623,6 +8079,10 @@ func (in *TiKVStorageReadPoolConfig) DeepCopy() *TiKVStorageReadPoolConfig {
 func (in *TiKVStore) DeepCopyInto(out *TiKVStore) {
 	*out = *in
 	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.DownSince != nil {
+		in, out := &in.DownSince, &out.DownSince
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -7788,6 +8248,11 @@ func (in *TiKVUnifiedReadPoolConfig) DeepCopy() *TiKVUnifiedReadPoolConfig {
 func (in *TidbAutoScalerSpec) DeepCopyInto(out *TidbAutoScalerSpec) {
 	*out = *in
 	in.BasicAutoScalerSpec.DeepCopyInto(&out.BasicAutoScalerSpec)
+	if in.ExternalCluster != nil {
+		in, out := &in.ExternalCluster, &out.ExternalCluster
+		*out = new(ExternalClusterSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -7967,6 +8432,13 @@ func (in *TidbClusterAutoScalerStatus) DeepCopyInto(out *TidbClusterAutoScalerSt
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Recommendations != nil {
+		in, out := &in.Recommendations, &out.Recommendations
+		*out = make([]AutoScalingRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -8213,6 +8685,11 @@ func (in *TidbClusterStatus) DeepCopyInto(out *TidbClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.FailoverSummary != nil {
+		in, out := &in.FailoverSummary, &out.FailoverSummary
+		*out = new(FailoverSummaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -8321,6 +8798,11 @@ func (in *TidbInitializerSpec) DeepCopyInto(out *TidbInitializerSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.InitSqlConfigMapNames != nil {
+		in, out := &in.InitSqlConfigMapNames, &out.InitSqlConfigMapNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.PasswordSecret != nil {
 		in, out := &in.PasswordSecret, &out.PasswordSecret
 		*out = new(string)
@@ -8353,6 +8835,18 @@ func (in *TidbInitializerSpec) DeepCopy() *TidbInitializerSpec {
 func (in *TidbInitializerStatus) DeepCopyInto(out *TidbInitializerStatus) {
 	*out = *in
 	in.JobStatus.DeepCopyInto(&out.JobStatus)
+	if in.AppliedSqlHashes != nil {
+		in, out := &in.AppliedSqlHashes, &out.AppliedSqlHashes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SqlFailure != nil {
+		in, out := &in.SqlFailure, &out.SqlFailure
+		*out = new(InitSqlFailure)
+		**out = **in
+	}
 	return
 }
 
@@ -8599,6 +9093,11 @@ func (in *TidbMonitorStatus) DeepCopyInto(out *TidbMonitorStatus) {
 		*out = new(appsv1.StatefulSetStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RBACNamespaces != nil {
+		in, out := &in.RBACNamespaces, &out.RBACNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -8616,6 +9115,11 @@ func (in *TidbMonitorStatus) DeepCopy() *TidbMonitorStatus {
 func (in *TikvAutoScalerSpec) DeepCopyInto(out *TikvAutoScalerSpec) {
 	*out = *in
 	in.BasicAutoScalerSpec.DeepCopyInto(&out.BasicAutoScalerSpec)
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageAutoScalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -8633,6 +9137,11 @@ func (in *TikvAutoScalerSpec) DeepCopy() *TikvAutoScalerSpec {
 func (in *TikvAutoScalerStatus) DeepCopyInto(out *TikvAutoScalerStatus) {
 	*out = *in
 	in.BasicAutoScalerStatus.DeepCopyInto(&out.BasicAutoScalerStatus)
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageAutoScalerStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
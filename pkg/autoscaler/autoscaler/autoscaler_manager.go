@@ -16,7 +16,6 @@ package autoscaler
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/autoscaler/autoscaler/query"
@@ -132,11 +131,17 @@ func (am *autoScalerManager) syncAutoScaling(tc *v1alpha1.TidbCluster, tac *v1al
 			if err := am.syncExternal(tc, tac, v1alpha1.TiKVMemberType); err != nil {
 				errs = append(errs, err)
 			}
-		} else {
+		} else if len(tac.Spec.TiKV.Rules) > 0 {
 			if err := am.syncPD(tc, tac, v1alpha1.TiKVMemberType); err != nil {
 				errs = append(errs, err)
 			}
 		}
+
+		if tac.Spec.TiKV.Storage != nil {
+			if err := am.syncStorage(tc, tac); err != nil {
+				errs = append(errs, err)
+			}
+		}
 	}
 
 	klog.Infof("tc[%s/%s]'s tac[%s/%s] synced", tc.Namespace, tc.Name, tac.Namespace, tac.Name)
@@ -196,21 +201,74 @@ func (am *autoScalerManager) updateTidbClusterAutoScaler(tac *v1alpha1.TidbClust
 	return err
 }
 
-func updateLastAutoScalingTimestamp(tac *v1alpha1.TidbClusterAutoScaler, memberType string, group string) {
+// maxAutoScalingRecommendations is the max number of the recommendations kept in the status
+const maxAutoScalingRecommendations = 10
+
+// recordAutoScaling records the auto-scaling of the group in the status of the tac
+func recordAutoScaling(tac *v1alpha1.TidbClusterAutoScaler, memberType v1alpha1.MemberType, group string, beforeReplicas, afterReplicas int32, message string) {
+	now := metav1.Now()
+	updateStatus := func(status *v1alpha1.BasicAutoScalerStatus) {
+		status.LastAutoScalingTimestamp = &now
+		if afterReplicas > beforeReplicas {
+			status.LastScaleOutTimestamp = &now
+		} else if afterReplicas < beforeReplicas {
+			status.LastScaleInTimestamp = &now
+		}
+	}
+
 	switch memberType {
-	case v1alpha1.TiKVMemberType.String():
+	case v1alpha1.TiKVMemberType:
 		if tac.Status.TiKV == nil {
 			tac.Status.TiKV = map[string]v1alpha1.TikvAutoScalerStatus{}
 		}
 		status := tac.Status.TiKV[group]
-		status.LastAutoScalingTimestamp = &metav1.Time{Time: time.Now()}
+		updateStatus(&status.BasicAutoScalerStatus)
 		tac.Status.TiKV[group] = status
-	case v1alpha1.TiDBMemberType.String():
+	case v1alpha1.TiDBMemberType:
 		if tac.Status.TiDB == nil {
 			tac.Status.TiDB = map[string]v1alpha1.TidbAutoScalerStatus{}
 		}
 		status := tac.Status.TiDB[group]
-		status.LastAutoScalingTimestamp = &metav1.Time{Time: time.Now()}
+		updateStatus(&status.BasicAutoScalerStatus)
 		tac.Status.TiDB[group] = status
 	}
+
+	decision := v1alpha1.AutoScalingDecisionScaleOut
+	if afterReplicas < beforeReplicas {
+		decision = v1alpha1.AutoScalingDecisionScaleIn
+	}
+	addAutoScalingRecommendation(tac, memberType, group, beforeReplicas, afterReplicas, decision, message, now)
+}
+
+// deferAutoScaling records the auto-scaling of the group deferred by the scale-in or scale-out interval
+func deferAutoScaling(tac *v1alpha1.TidbClusterAutoScaler, memberType v1alpha1.MemberType, group string, beforeReplicas, afterReplicas int32, message string) {
+	klog.Infof("tac[%s/%s] defers auto-scaling %s group %s from %d to %d within the interval", tac.Namespace, tac.Name, memberType, group, beforeReplicas, afterReplicas)
+	addAutoScalingRecommendation(tac, memberType, group, beforeReplicas, afterReplicas, v1alpha1.AutoScalingDecisionDeferred, message, metav1.Now())
+}
+
+func addAutoScalingRecommendation(tac *v1alpha1.TidbClusterAutoScaler, memberType v1alpha1.MemberType, group string, beforeReplicas, afterReplicas int32, decision v1alpha1.AutoScalingDecision, message string, timestamp metav1.Time) {
+	recommendation := v1alpha1.AutoScalingRecommendation{
+		Component:           memberType,
+		Group:               group,
+		CurrentReplicas:     beforeReplicas,
+		RecommendedReplicas: afterReplicas,
+		Decision:            decision,
+		Message:             message,
+		Timestamp:           timestamp,
+	}
+	recommendations := tac.Status.Recommendations
+	if n := len(recommendations); n > 0 && decision == v1alpha1.AutoScalingDecisionDeferred {
+		// the same deferred auto-scaling in the following syncs only refreshes the last recommendation
+		last := recommendations[n-1]
+		if last.Decision == decision && last.Component == memberType && last.Group == group &&
+			last.CurrentReplicas == beforeReplicas && last.RecommendedReplicas == afterReplicas {
+			recommendations[n-1] = recommendation
+			return
+		}
+	}
+	recommendations = append(recommendations, recommendation)
+	if len(recommendations) > maxAutoScalingRecommendations {
+		recommendations = recommendations[len(recommendations)-maxAutoScalingRecommendations:]
+	}
+	tac.Status.Recommendations = recommendations
 }
@@ -32,6 +32,10 @@ const (
 )
 
 func (am *autoScalerManager) syncExternalResult(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType, targetReplicas int32) error {
+	if component == v1alpha1.TiDBMemberType && tac.Spec.TiDB.ExternalCluster != nil {
+		return am.syncExternalClusterResult(tc, tac, tac.Spec.TiDB.ExternalCluster, targetReplicas)
+	}
+
 	externalTcName := fmt.Sprintf(externalTcNamePattern, tc.ClusterName, component.String())
 	externalTc, err := am.deps.TiDBClusterLister.TidbClusters(tc.Namespace).Get(externalTcName)
 	if err != nil {
@@ -66,6 +70,75 @@ func (am *autoScalerManager) syncExternalResult(tc *v1alpha1.TidbCluster, tac *v
 	return am.updateExternalAutoCluster(externalTc, tac, component, targetReplicas)
 }
 
+// syncExternalClusterResult applies the target replicas to the tidb of the heterogeneous TidbCluster referenced by
+// the tac, the TidbCluster is created if absent, and it's scaled to 0 rather than deleted as it may be shared.
+// The target TidbCluster is never touched.
+func (am *autoScalerManager) syncExternalClusterResult(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, ec *v1alpha1.ExternalClusterSpec, targetReplicas int32) error {
+	if targetReplicas < 0 {
+		targetReplicas = 0
+	}
+
+	externalTc, err := am.deps.TiDBClusterLister.TidbClusters(ec.Namespace).Get(ec.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			if targetReplicas == 0 {
+				return nil
+			}
+			return am.createExternalCluster(tc, tac, ec, targetReplicas)
+		}
+
+		klog.Errorf("tac[%s/%s] failed to get external cluster tc[%s/%s], err: %v", tac.Namespace, tac.Name, ec.Namespace, ec.Name, err)
+		return err
+	}
+
+	// the existing TidbCluster of the name may be an unrelated one, it's only managed if it joins the target cluster
+	if !joinsCluster(externalTc, tc) {
+		return fmt.Errorf("external cluster tc[%s/%s] of tac[%s/%s] doesn't join tc[%s/%s], refuse to manage it", ec.Namespace, ec.Name, tac.Namespace, tac.Name, tc.Namespace, tc.Name)
+	}
+	if externalTc.Spec.TiDB == nil {
+		return fmt.Errorf("external cluster tc[%s/%s] of tac[%s/%s] has no tidb", ec.Namespace, ec.Name, tac.Namespace, tac.Name)
+	}
+	return am.updateExternalAutoCluster(externalTc, tac, v1alpha1.TiDBMemberType, targetReplicas)
+}
+
+// joinsCluster returns whether the TidbCluster joins tc by spec.cluster, the namespace of spec.cluster
+// defaults to the namespace of the TidbCluster
+func joinsCluster(externalTc, tc *v1alpha1.TidbCluster) bool {
+	ref := externalTc.Spec.Cluster
+	if ref == nil || ref.Name != tc.Name {
+		return false
+	}
+	ns := ref.Namespace
+	if ns == "" {
+		ns = externalTc.Namespace
+	}
+	return ns == tc.Namespace
+}
+
+func (am *autoScalerManager) createExternalCluster(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, ec *v1alpha1.ExternalClusterSpec, targetReplicas int32) error {
+	autoTc := newAutoScalingCluster(tc, tac, ec.Name, v1alpha1.TiDBMemberType.String())
+	autoTc.Namespace = ec.Namespace
+	if !ec.GarbageCollect {
+		autoTc.OwnerReferences = nil
+	}
+	if ec.Template != nil {
+		autoTc.Spec.TiDB = ec.Template.DeepCopy()
+		if autoTc.Spec.TiDB.Config == nil {
+			autoTc.Spec.TiDB.Config = v1alpha1.NewTiDBConfig()
+		}
+	}
+	autoTc.Spec.TiDB.Replicas = targetReplicas
+
+	_, err := am.deps.Clientset.PingcapV1alpha1().TidbClusters(ec.Namespace).Create(context.TODO(), autoTc, metav1.CreateOptions{})
+	if err != nil {
+		klog.Errorf("tac[%s/%s] failed to create external cluster tc[%s/%s], err: %v", tac.Namespace, tac.Name, ec.Namespace, ec.Name, err)
+		return err
+	}
+
+	recordAutoScaling(tac, v1alpha1.TiDBMemberType, externalStatusKey, 0, targetReplicas, externalMessage(tac, v1alpha1.TiDBMemberType, targetReplicas))
+	return nil
+}
+
 func (am *autoScalerManager) createExternalAutoCluster(tc *v1alpha1.TidbCluster, externalTcName string, tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType, targetReplicas int32) error {
 	autoTc := newAutoScalingCluster(tc, tac, externalTcName, component.String())
 
@@ -83,32 +156,32 @@ func (am *autoScalerManager) createExternalAutoCluster(tc *v1alpha1.TidbCluster,
 		return err
 	}
 
-	updateLastAutoScalingTimestamp(tac, component.String(), externalStatusKey)
+	recordAutoScaling(tac, component, externalStatusKey, 0, targetReplicas, externalMessage(tac, component, targetReplicas))
 	return nil
 }
 
 func (am *autoScalerManager) updateExternalAutoCluster(externalTc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType, targetReplicas int32) error {
 	updated := externalTc.DeepCopy()
+	var replicas *int32
 	switch component {
 	case v1alpha1.TiDBMemberType:
-		if updated.Spec.TiDB.Replicas == targetReplicas {
-			return nil
-		}
-
-		if !checkAutoScaling(tac, component, externalStatusKey, updated.Spec.TiDB.Replicas, targetReplicas) {
-			return nil
-		}
-		updated.Spec.TiDB.Replicas = targetReplicas
+		replicas = &updated.Spec.TiDB.Replicas
 	case v1alpha1.TiKVMemberType:
-		if updated.Spec.TiKV.Replicas == targetReplicas {
-			return nil
-		}
+		replicas = &updated.Spec.TiKV.Replicas
+	default:
+		return nil
+	}
 
-		if !checkAutoScaling(tac, component, externalStatusKey, updated.Spec.TiKV.Replicas, targetReplicas) {
-			return nil
-		}
-		updated.Spec.TiKV.Replicas = targetReplicas
+	currentReplicas := *replicas
+	if currentReplicas == targetReplicas {
+		return nil
+	}
+	message := externalMessage(tac, component, targetReplicas)
+	if !checkAutoScaling(tac, component, externalStatusKey, currentReplicas, targetReplicas) {
+		deferAutoScaling(tac, component, externalStatusKey, currentReplicas, targetReplicas, message)
+		return nil
 	}
+	*replicas = targetReplicas
 
 	_, err := am.deps.TiDBClusterControl.UpdateTidbCluster(updated, &updated.Status, &externalTc.Status)
 	if err != nil {
@@ -116,6 +189,12 @@ func (am *autoScalerManager) updateExternalAutoCluster(externalTc *v1alpha1.Tidb
 		return err
 	}
 
-	updateLastAutoScalingTimestamp(tac, component.String(), externalStatusKey)
+	recordAutoScaling(tac, component, externalStatusKey, currentReplicas, targetReplicas, message)
 	return nil
 }
+
+// externalMessage describes the replicas recommended by the external service for the recommendations
+func externalMessage(tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType, targetReplicas int32) string {
+	cfg := getBasicAutoScalerSpec(tac, component).External
+	return fmt.Sprintf("external service %s:%d%s recommends %d replicas with max replicas %d", cfg.Endpoint.Host, cfg.Endpoint.Port, cfg.Endpoint.Path, targetReplicas, cfg.MaxReplicas)
+}
@@ -0,0 +1,293 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestUpdateExternalAutoClusterInterval(t *testing.T) {
+	now := time.Now()
+	ago := func(d time.Duration) *metav1.Time {
+		return &metav1.Time{Time: now.Add(-d)}
+	}
+
+	tests := []struct {
+		name           string
+		status         v1alpha1.BasicAutoScalerStatus
+		targetReplicas int32
+		expectReplicas int32
+		expectDecision v1alpha1.AutoScalingDecision
+	}{
+		{
+			name: "scale out within the scale-out interval",
+			status: v1alpha1.BasicAutoScalerStatus{
+				LastAutoScalingTimestamp: ago(time.Minute),
+				LastScaleOutTimestamp:    ago(time.Minute),
+			},
+			targetReplicas: 4,
+			expectReplicas: 3,
+			expectDecision: v1alpha1.AutoScalingDecisionDeferred,
+		},
+		{
+			name: "scale out after the scale-out interval",
+			status: v1alpha1.BasicAutoScalerStatus{
+				LastAutoScalingTimestamp: ago(time.Hour),
+				LastScaleOutTimestamp:    ago(time.Hour),
+			},
+			targetReplicas: 4,
+			expectReplicas: 4,
+			expectDecision: v1alpha1.AutoScalingDecisionScaleOut,
+		},
+		{
+			name: "scale out right after scaling in",
+			status: v1alpha1.BasicAutoScalerStatus{
+				LastAutoScalingTimestamp: ago(time.Minute),
+				LastScaleOutTimestamp:    ago(time.Hour),
+				LastScaleInTimestamp:     ago(time.Minute),
+			},
+			targetReplicas: 4,
+			expectReplicas: 4,
+			expectDecision: v1alpha1.AutoScalingDecisionScaleOut,
+		},
+		{
+			name: "scale out within the interval of the last auto-scaling without the directions recorded",
+			status: v1alpha1.BasicAutoScalerStatus{
+				LastAutoScalingTimestamp: ago(time.Minute),
+			},
+			targetReplicas: 4,
+			expectReplicas: 3,
+			expectDecision: v1alpha1.AutoScalingDecisionDeferred,
+		},
+		{
+			name: "scale in right after scaling out",
+			status: v1alpha1.BasicAutoScalerStatus{
+				LastAutoScalingTimestamp: ago(time.Minute),
+				LastScaleOutTimestamp:    ago(time.Minute),
+			},
+			targetReplicas: 2,
+			expectReplicas: 3,
+			expectDecision: v1alpha1.AutoScalingDecisionDeferred,
+		},
+		{
+			name: "scale in after the scale-in interval",
+			status: v1alpha1.BasicAutoScalerStatus{
+				LastAutoScalingTimestamp: ago(time.Hour),
+				LastScaleOutTimestamp:    ago(time.Hour),
+			},
+			targetReplicas: 2,
+			expectReplicas: 2,
+			expectDecision: v1alpha1.AutoScalingDecisionScaleIn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			externalTc := newTidbCluster()
+			externalTc.Name = "tc-tikv-external"
+			externalTc.Spec.TiKV.Replicas = 3
+			tac := newTidbClusterAutoScaler()
+			tac.Spec.TiKV.External = &v1alpha1.ExternalConfig{
+				Endpoint:    v1alpha1.ExternalEndpoint{Host: "external", Port: 8080, Path: "/recommend"},
+				MaxReplicas: 10,
+			}
+			tac.Spec.TiKV.ScaleOutIntervalSeconds = pointer.Int32Ptr(300)
+			tac.Spec.TiKV.ScaleInIntervalSeconds = pointer.Int32Ptr(500)
+			tac.Status.TiKV = map[string]v1alpha1.TikvAutoScalerStatus{
+				externalStatusKey: {BasicAutoScalerStatus: tt.status},
+			}
+
+			fakeDeps := controller.NewFakeDependencies()
+			tcIndexer := fakeDeps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer()
+			g.Expect(tcIndexer.Add(externalTc)).To(Succeed())
+			am := &autoScalerManager{deps: fakeDeps}
+
+			// the deferred auto-scaling is recorded once across the syncs
+			for i := 0; i < 2; i++ {
+				g.Expect(am.updateExternalAutoCluster(externalTc, tac, v1alpha1.TiKVMemberType, tt.targetReplicas)).To(Succeed())
+				if tt.expectDecision != v1alpha1.AutoScalingDecisionDeferred {
+					break
+				}
+			}
+
+			updated, err := fakeDeps.TiDBClusterLister.TidbClusters(externalTc.Namespace).Get(externalTc.Name)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(updated.Spec.TiKV.Replicas).To(Equal(tt.expectReplicas))
+
+			g.Expect(tac.Status.Recommendations).To(HaveLen(1))
+			recommendation := tac.Status.Recommendations[0]
+			g.Expect(recommendation.Component).To(Equal(v1alpha1.TiKVMemberType))
+			g.Expect(recommendation.Group).To(Equal(externalStatusKey))
+			g.Expect(recommendation.CurrentReplicas).To(Equal(int32(3)))
+			g.Expect(recommendation.RecommendedReplicas).To(Equal(tt.targetReplicas))
+			g.Expect(recommendation.Decision).To(Equal(tt.expectDecision))
+			g.Expect(recommendation.Message).To(ContainSubstring("external:8080/recommend"))
+
+			status := tac.Status.TiKV[externalStatusKey]
+			switch tt.expectDecision {
+			case v1alpha1.AutoScalingDecisionDeferred:
+				g.Expect(status.BasicAutoScalerStatus).To(Equal(tt.status))
+			case v1alpha1.AutoScalingDecisionScaleOut:
+				g.Expect(status.LastScaleOutTimestamp.Time).To(BeTemporally(">", now.Add(-time.Second)))
+				g.Expect(status.LastScaleInTimestamp).To(Equal(tt.status.LastScaleInTimestamp))
+			case v1alpha1.AutoScalingDecisionScaleIn:
+				g.Expect(status.LastScaleInTimestamp.Time).To(BeTemporally(">", now.Add(-time.Second)))
+				g.Expect(status.LastScaleOutTimestamp).To(Equal(tt.status.LastScaleOutTimestamp))
+			}
+		})
+	}
+}
+
+func TestSyncExternalClusterResult(t *testing.T) {
+	tests := []struct {
+		name           string
+		existing       bool
+		foreign        bool
+		garbageCollect bool
+		targetReplicas int32
+		expectErr      bool
+		expectExisted  bool
+		expectReplicas int32
+	}{
+		{
+			name:           "create the external cluster on demand",
+			targetReplicas: 2,
+			expectExisted:  true,
+			expectReplicas: 2,
+		},
+		{
+			name:           "create the external cluster garbage collected with the autoscaler",
+			garbageCollect: true,
+			targetReplicas: 2,
+			expectExisted:  true,
+			expectReplicas: 2,
+		},
+		{
+			name:           "no replicas for the absent external cluster",
+			targetReplicas: 0,
+			expectExisted:  false,
+		},
+		{
+			name:           "resize the external cluster",
+			existing:       true,
+			targetReplicas: 3,
+			expectExisted:  true,
+			expectReplicas: 3,
+		},
+		{
+			name:           "scale the external cluster to 0",
+			existing:       true,
+			targetReplicas: 0,
+			expectExisted:  true,
+			expectReplicas: 0,
+		},
+		{
+			name:           "refuse to resize the existing cluster not joining the target cluster",
+			existing:       true,
+			foreign:        true,
+			targetReplicas: 3,
+			expectErr:      true,
+			expectExisted:  true,
+			expectReplicas: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tc := newTidbCluster()
+			tc.Spec.TiDB.Replicas = 5
+			tac := newTidbClusterAutoScaler()
+			tac.UID = "tac-uid"
+			tac.Spec.TiDB.External = &v1alpha1.ExternalConfig{
+				Endpoint:    v1alpha1.ExternalEndpoint{Host: "external", Port: 8080, Path: "/recommend"},
+				MaxReplicas: 10,
+			}
+			tac.Spec.TiDB.ExternalCluster = &v1alpha1.ExternalClusterSpec{
+				Namespace: tac.Namespace,
+				Name:      "tc-tidb-auto",
+				Template: &v1alpha1.TiDBSpec{
+					ResourceRequirements: corev1.ResourceRequirements{Requests: tc.Spec.TiDB.Requests},
+				},
+				GarbageCollect: tt.garbageCollect,
+			}
+			tac.Spec.TiDB.ScaleOutIntervalSeconds = pointer.Int32Ptr(300)
+			tac.Spec.TiDB.ScaleInIntervalSeconds = pointer.Int32Ptr(500)
+
+			fakeDeps := controller.NewFakeDependencies()
+			tcIndexer := fakeDeps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer()
+			g.Expect(tcIndexer.Add(tc)).To(Succeed())
+			if tt.existing {
+				externalTc := newTidbCluster()
+				externalTc.Name = "tc-tidb-auto"
+				externalTc.Spec.TiDB.Replicas = 2
+				externalTc.Spec.TiKV = nil
+				if !tt.foreign {
+					externalTc.Spec.Cluster = &v1alpha1.TidbClusterRef{Name: tc.Name}
+				}
+				g.Expect(tcIndexer.Add(externalTc)).To(Succeed())
+			}
+			am := &autoScalerManager{deps: fakeDeps}
+
+			err := am.syncExternalResult(tc, tac, v1alpha1.TiDBMemberType, tt.targetReplicas)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+
+			primary, err := fakeDeps.TiDBClusterLister.TidbClusters(tc.Namespace).Get(tc.Name)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(primary.Spec.TiDB.Replicas).To(Equal(int32(5)))
+
+			var externalTc *v1alpha1.TidbCluster
+			if tt.existing {
+				externalTc, err = fakeDeps.TiDBClusterLister.TidbClusters(tac.Namespace).Get("tc-tidb-auto")
+			} else {
+				externalTc, err = fakeDeps.Clientset.PingcapV1alpha1().TidbClusters(tac.Namespace).Get(context.TODO(), "tc-tidb-auto", metav1.GetOptions{})
+			}
+			if !tt.expectExisted {
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(externalTc.Spec.TiDB.Replicas).To(Equal(tt.expectReplicas))
+			if tt.existing {
+				return
+			}
+			g.Expect(externalTc.Spec.Cluster).To(Equal(&v1alpha1.TidbClusterRef{Namespace: tc.Namespace, Name: tc.Name}))
+			g.Expect(externalTc.Spec.TiKV).To(BeNil())
+			g.Expect(externalTc.Spec.TiDB.Requests).To(Equal(tc.Spec.TiDB.Requests))
+			if tt.garbageCollect {
+				g.Expect(externalTc.OwnerReferences).To(HaveLen(1))
+				g.Expect(externalTc.OwnerReferences[0].UID).To(Equal(tac.UID))
+			} else {
+				g.Expect(externalTc.OwnerReferences).To(BeEmpty())
+			}
+		})
+	}
+}
@@ -129,29 +129,36 @@ func (am *autoScalerManager) updateAutoscalingClusters(tac *v1alpha1.TidbCluster
 	var errs []error
 	for _, group := range groupsToUpdate {
 		actual, oldTc, plan := groupTcMap[group].DeepCopy(), groupTcMap[group], groupPlanMap[group]
+		component := v1alpha1.MemberType(plan.Component)
 
-		switch plan.Component {
-		case v1alpha1.TiKVMemberType.String():
-			if tac.Spec.TiKV == nil || actual.Spec.TiKV.Replicas == int32(plan.Count) {
-				continue
-			}
-			if !checkAutoScaling(tac, v1alpha1.TiKVMemberType, group, actual.Spec.TiKV.Replicas, int32(plan.Count)) {
-				continue
-			}
-			actual.Spec.TiKV.Replicas = int32(plan.Count)
-		case v1alpha1.TiDBMemberType.String():
-			if tac.Spec.TiDB == nil || actual.Spec.TiDB.Replicas == int32(plan.Count) {
+		var replicas *int32
+		switch component {
+		case v1alpha1.TiKVMemberType:
+			if tac.Spec.TiKV == nil {
 				continue
 			}
-			if !checkAutoScaling(tac, v1alpha1.TiDBMemberType, group, actual.Spec.TiDB.Replicas, int32(plan.Count)) {
+			replicas = &actual.Spec.TiKV.Replicas
+		case v1alpha1.TiDBMemberType:
+			if tac.Spec.TiDB == nil {
 				continue
 			}
-			actual.Spec.TiDB.Replicas = int32(plan.Count)
+			replicas = &actual.Spec.TiDB.Replicas
 		default:
 			errs = append(errs, fmt.Errorf("unexpected component %s for group %s in autoscaling plan", plan.Component, group))
 			continue
 		}
 
+		currentReplicas, targetReplicas := *replicas, int32(plan.Count)
+		if currentReplicas == targetReplicas {
+			continue
+		}
+		message := planMessage(tac, component, plan)
+		if !checkAutoScaling(tac, component, group, currentReplicas, targetReplicas) {
+			deferAutoScaling(tac, component, group, currentReplicas, targetReplicas, message)
+			continue
+		}
+		*replicas = targetReplicas
+
 		_, err := am.deps.TiDBClusterControl.UpdateTidbCluster(actual, &actual.Status, &oldTc.Status)
 		if err != nil {
 			klog.Errorf("tac[%s/%s] failed to update tc[%s/%s] for group %s, err: %v", tac.Namespace, tac.Name, actual.Namespace, actual.Name, group, err)
@@ -159,7 +166,7 @@ func (am *autoScalerManager) updateAutoscalingClusters(tac *v1alpha1.TidbCluster
 			continue
 		}
 
-		updateLastAutoScalingTimestamp(tac, plan.Component, group)
+		recordAutoScaling(tac, component, group, currentReplicas, targetReplicas, message)
 	}
 	return errorutils.NewAggregate(errs)
 }
@@ -233,7 +240,12 @@ func (am *autoScalerManager) createAutoscalingClusters(tc *v1alpha1.TidbCluster,
 			continue
 		}
 
-		updateLastAutoScalingTimestamp(tac, component, group)
+		recordAutoScaling(tac, v1alpha1.MemberType(component), group, 0, int32(plan.Count), planMessage(tac, v1alpha1.MemberType(component), plan))
 	}
 	return errorutils.NewAggregate(errs)
 }
+
+// planMessage describes the auto-scaling plan for the recommendations
+func planMessage(tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType, plan pdapi.Plan) string {
+	return fmt.Sprintf("pd plans %d replicas of resource type %s by rules: %s", plan.Count, plan.ResourceType, describeAutoRules(getBasicAutoScalerSpec(tac, component).Rules))
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+// storageStatusKey is the key of the status of the auto-scaling on the storage pressure
+const storageStatusKey = "storage"
+
+// syncStorage scales out the tikv of the target TidbCluster by one replica when the max used percentage
+// of the storage of the tikv stores keeps exceeding the threshold for the stabilization window.
+// The tikv is never scaled in on the storage usage.
+func (am *autoScalerManager) syncStorage(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler) error {
+	storesInfo, err := controller.GetPDClient(am.deps.PDControl, tc).GetStores()
+	if err != nil {
+		klog.Errorf("tac[%s/%s] cannot get stores from pd, err: %v", tac.Namespace, tac.Name, err)
+		return err
+	}
+
+	if !evaluateStorage(tac, storesInfo, time.Now()) {
+		return nil
+	}
+
+	storage := tac.Spec.TiKV.Storage
+	currentReplicas := tc.Spec.TiKV.Replicas
+	targetReplicas := currentReplicas + 1
+	if targetReplicas > storage.MaxReplicas {
+		klog.Warningf("tac[%s/%s] tikv of tc[%s/%s] is under storage pressure, but the replicas %d reach the max replicas %d", tac.Namespace, tac.Name, tc.Namespace, tc.Name, currentReplicas, storage.MaxReplicas)
+		return nil
+	}
+	evaluation := tac.Status.TiKV[storageStatusKey].Storage
+	message := fmt.Sprintf("max used storage %.2f%% of store %s exceeds the threshold %d%% for %ds",
+		evaluation.MaxUsedPercent, evaluation.MaxUsedStoreID, storage.UsedThresholdPercent, *storage.StabilizationWindowSeconds)
+	if !checkAutoScaling(tac, v1alpha1.TiKVMemberType, storageStatusKey, currentReplicas, targetReplicas) {
+		deferAutoScaling(tac, v1alpha1.TiKVMemberType, storageStatusKey, currentReplicas, targetReplicas, message)
+		return nil
+	}
+
+	updated := tc.DeepCopy()
+	updated.Spec.TiKV.Replicas = targetReplicas
+	_, err = am.deps.TiDBClusterControl.UpdateTidbCluster(updated, &updated.Status, &tc.Status)
+	if err != nil {
+		klog.Errorf("tac[%s/%s] failed to scale out tikv of tc[%s/%s] on storage pressure, err: %v", tac.Namespace, tac.Name, tc.Namespace, tc.Name, err)
+		return err
+	}
+	klog.Infof("tac[%s/%s] scaled out tikv of tc[%s/%s] from %d to %d on storage pressure", tac.Namespace, tac.Name, tc.Namespace, tc.Name, currentReplicas, targetReplicas)
+
+	recordAutoScaling(tac, v1alpha1.TiKVMemberType, storageStatusKey, currentReplicas, targetReplicas, message)
+	// the next scale-out requires another stabilization window on the new replicas
+	evaluation.ThresholdExceededTimestamp = nil
+	return nil
+}
+
+// evaluateStorage records the storage usage of the tikv stores in the status of the tac,
+// and returns whether the max used percentage keeps exceeding the threshold for the stabilization window
+func evaluateStorage(tac *v1alpha1.TidbClusterAutoScaler, storesInfo *pdapi.StoresInfo, now time.Time) bool {
+	storage := tac.Spec.TiKV.Storage
+
+	if tac.Status.TiKV == nil {
+		tac.Status.TiKV = map[string]v1alpha1.TikvAutoScalerStatus{}
+	}
+	status := tac.Status.TiKV[storageStatusKey]
+	evaluation := &v1alpha1.StorageAutoScalerStatus{
+		LastEvaluationTimestamp: &metav1.Time{Time: now},
+	}
+	if status.Storage != nil {
+		evaluation.ThresholdExceededTimestamp = status.Storage.ThresholdExceededTimestamp
+	}
+
+	for _, store := range storesInfo.Stores {
+		if store.Store == nil || store.Status == nil || store.Status.Capacity == 0 {
+			continue
+		}
+		if store.Store.StateName != v1alpha1.TiKVStateUp || !util.MatchLabelFromStoreLabels(store.Store.Labels, label.TiKVLabelVal) {
+			continue
+		}
+		evaluation.StoreCount++
+
+		capacity, available := uint64(store.Status.Capacity), uint64(store.Status.Available)
+		var usedPercent float64
+		if available < capacity {
+			usedPercent = float64(capacity-available) / float64(capacity) * 100
+		}
+		if evaluation.MaxUsedStoreID == "" || usedPercent > evaluation.MaxUsedPercent {
+			evaluation.MaxUsedStoreID = strconv.FormatUint(store.Store.Id, 10)
+			evaluation.MaxUsedPercent = usedPercent
+			evaluation.CapacityBytes = int64(capacity)
+			evaluation.AvailableBytes = int64(available)
+		}
+	}
+
+	exceeded := evaluation.StoreCount > 0 && evaluation.MaxUsedPercent > float64(storage.UsedThresholdPercent)
+	if !exceeded {
+		evaluation.ThresholdExceededTimestamp = nil
+	} else if evaluation.ThresholdExceededTimestamp == nil {
+		evaluation.ThresholdExceededTimestamp = &metav1.Time{Time: now}
+	}
+	status.Storage = evaluation
+	tac.Status.TiKV[storageStatusKey] = status
+
+	if !exceeded {
+		return false
+	}
+	window := time.Duration(*storage.StabilizationWindowSeconds) * time.Second
+	return now.Sub(evaluation.ThresholdExceededTimestamp.Time) >= window
+}
@@ -0,0 +1,152 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/tikv/pd/pkg/typeutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestSyncStorage(t *testing.T) {
+	now := time.Now()
+	newStore := func(id uint64, engine string, usedPercent int) *pdapi.StoreInfo {
+		store := &pdapi.StoreInfo{
+			Store: &pdapi.MetaStore{
+				Store:     &metapb.Store{Id: id},
+				StateName: v1alpha1.TiKVStateUp,
+			},
+			Status: &pdapi.StoreStatus{
+				Capacity:  typeutil.ByteSize(100 * 1024),
+				Available: typeutil.ByteSize((100 - usedPercent) * 1024),
+			},
+		}
+		if engine != "" {
+			store.Store.Labels = []*metapb.StoreLabel{{Key: "engine", Value: engine}}
+		}
+		return store
+	}
+
+	tests := []struct {
+		name                      string
+		stores                    []*pdapi.StoreInfo
+		thresholdExceeded         *metav1.Time
+		maxReplicas               int32
+		expectReplicas            int32
+		expectMaxUsedStoreID      string
+		expectMaxUsedPercent      float64
+		expectThresholdExceeded   bool
+		expectLastAutoScalingTime bool
+	}{
+		{
+			name:                 "below threshold",
+			stores:               []*pdapi.StoreInfo{newStore(1, "", 50), newStore(2, "", 60)},
+			thresholdExceeded:    &metav1.Time{Time: now.Add(-10 * time.Minute)},
+			maxReplicas:          5,
+			expectReplicas:       3,
+			expectMaxUsedStoreID: "2",
+			expectMaxUsedPercent: 60,
+		},
+		{
+			name:                    "above threshold within the stabilization window",
+			stores:                  []*pdapi.StoreInfo{newStore(1, "", 50), newStore(2, "", 90)},
+			maxReplicas:             5,
+			expectReplicas:          3,
+			expectMaxUsedStoreID:    "2",
+			expectMaxUsedPercent:    90,
+			expectThresholdExceeded: true,
+		},
+		{
+			name:                      "above threshold after the stabilization window",
+			stores:                    []*pdapi.StoreInfo{newStore(1, "", 50), newStore(2, "", 90)},
+			thresholdExceeded:         &metav1.Time{Time: now.Add(-10 * time.Minute)},
+			maxReplicas:               5,
+			expectReplicas:            4,
+			expectMaxUsedStoreID:      "2",
+			expectMaxUsedPercent:      90,
+			expectLastAutoScalingTime: true,
+		},
+		{
+			name:                    "above threshold and reach the max replicas",
+			stores:                  []*pdapi.StoreInfo{newStore(1, "", 50), newStore(2, "", 90)},
+			thresholdExceeded:       &metav1.Time{Time: now.Add(-10 * time.Minute)},
+			maxReplicas:             3,
+			expectReplicas:          3,
+			expectMaxUsedStoreID:    "2",
+			expectMaxUsedPercent:    90,
+			expectThresholdExceeded: true,
+		},
+		{
+			name:                 "tiflash store above threshold",
+			stores:               []*pdapi.StoreInfo{newStore(1, "", 50), newStore(2, "tiflash", 90)},
+			thresholdExceeded:    &metav1.Time{Time: now.Add(-10 * time.Minute)},
+			maxReplicas:          5,
+			expectReplicas:       3,
+			expectMaxUsedStoreID: "1",
+			expectMaxUsedPercent: 50,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tc := newTidbCluster()
+			tc.Spec.TiKV.Replicas = 3
+			tac := newTidbClusterAutoScaler()
+			tac.Spec.TiKV.Storage = &v1alpha1.StorageAutoScalerSpec{
+				UsedThresholdPercent:       80,
+				StabilizationWindowSeconds: pointer.Int32Ptr(300),
+				MaxReplicas:                tt.maxReplicas,
+			}
+			tac.Spec.TiKV.ScaleOutIntervalSeconds = pointer.Int32Ptr(300)
+			tac.Status.TiKV = map[string]v1alpha1.TikvAutoScalerStatus{
+				storageStatusKey: {
+					Storage: &v1alpha1.StorageAutoScalerStatus{ThresholdExceededTimestamp: tt.thresholdExceeded},
+				},
+			}
+
+			fakeDeps := controller.NewFakeDependencies()
+			tcIndexer := fakeDeps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer()
+			g.Expect(tcIndexer.Add(tc)).To(Succeed())
+			pdClient := controller.NewFakePDClient(fakeDeps.PDControl.(*pdapi.FakePDControl), tc)
+			pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+				return &pdapi.StoresInfo{Count: len(tt.stores), Stores: tt.stores}, nil
+			})
+			am := &autoScalerManager{deps: fakeDeps}
+
+			g.Expect(am.syncStorage(tc, tac)).To(Succeed())
+
+			updated, err := fakeDeps.TiDBClusterLister.TidbClusters(tc.Namespace).Get(tc.Name)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(updated.Spec.TiKV.Replicas).To(Equal(tt.expectReplicas))
+
+			status := tac.Status.TiKV[storageStatusKey]
+			g.Expect(status.Storage).NotTo(BeNil())
+			g.Expect(status.Storage.MaxUsedStoreID).To(Equal(tt.expectMaxUsedStoreID))
+			g.Expect(status.Storage.MaxUsedPercent).To(BeNumerically("~", tt.expectMaxUsedPercent, 0.01))
+			g.Expect(status.Storage.LastEvaluationTimestamp).NotTo(BeNil())
+			g.Expect(status.Storage.ThresholdExceededTimestamp != nil).To(Equal(tt.expectThresholdExceeded))
+			g.Expect(status.LastAutoScalingTimestamp != nil).To(Equal(tt.expectLastAutoScalingTime))
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
@@ -44,9 +45,9 @@ func checkAutoScaling(tac *v1alpha1.TidbClusterAutoScaler, memberType v1alpha1.M
 	} else if beforeReplicas < afterReplicas {
 		switch memberType {
 		case v1alpha1.TiKVMemberType:
-			return checkAutoScalingInterval(tac, *tac.Spec.TiKV.ScaleOutIntervalSeconds, memberType, group)
+			return checkScaleOutInterval(tac, *tac.Spec.TiKV.ScaleOutIntervalSeconds, memberType, group)
 		case v1alpha1.TiDBMemberType:
-			return checkAutoScalingInterval(tac, *tac.Spec.TiDB.ScaleOutIntervalSeconds, memberType, group)
+			return checkScaleOutInterval(tac, *tac.Spec.TiDB.ScaleOutIntervalSeconds, memberType, group)
 		}
 	}
 	return true
@@ -54,29 +55,63 @@ func checkAutoScaling(tac *v1alpha1.TidbClusterAutoScaler, memberType v1alpha1.M
 
 // checkAutoScalingInterval would check whether there is enough interval duration between every two auto-scaling
 func checkAutoScalingInterval(tac *v1alpha1.TidbClusterAutoScaler, intervalSeconds int32, memberType v1alpha1.MemberType, group string) bool {
-	var lastAutoScalingTimestamp *metav1.Time
-	if memberType == v1alpha1.TiKVMemberType {
-		status, existed := tac.Status.TiKV[group]
-		if !existed {
-			return true
-		}
-		lastAutoScalingTimestamp = status.LastAutoScalingTimestamp
-	} else if memberType == v1alpha1.TiDBMemberType {
-		status, existed := tac.Status.TiDB[group]
-		if !existed {
-			return true
-		}
-		lastAutoScalingTimestamp = status.LastAutoScalingTimestamp
+	status, existed := getBasicAutoScalerStatus(tac, memberType, group)
+	if !existed {
+		return true
+	}
+	return checkIntervalSince(status.LastAutoScalingTimestamp, intervalSeconds)
+}
+
+// checkScaleOutInterval would check whether there is enough interval duration since the last auto-scaling-out,
+// the last auto-scaling is taken if the timestamps of the directions are not recorded yet
+func checkScaleOutInterval(tac *v1alpha1.TidbClusterAutoScaler, intervalSeconds int32, memberType v1alpha1.MemberType, group string) bool {
+	status, existed := getBasicAutoScalerStatus(tac, memberType, group)
+	if !existed {
+		return true
 	}
-	if lastAutoScalingTimestamp == nil {
+	lastScaleOutTimestamp := status.LastScaleOutTimestamp
+	if lastScaleOutTimestamp == nil && status.LastScaleInTimestamp == nil {
+		lastScaleOutTimestamp = status.LastAutoScalingTimestamp
+	}
+	return checkIntervalSince(lastScaleOutTimestamp, intervalSeconds)
+}
+
+func checkIntervalSince(timestamp *metav1.Time, intervalSeconds int32) bool {
+	if timestamp == nil {
 		return true
 	}
-	if intervalSeconds > int32(time.Since(lastAutoScalingTimestamp.Time).Seconds()) {
+	if intervalSeconds > int32(time.Since(timestamp.Time).Seconds()) {
 		return false
 	}
 	return true
 }
 
+func getBasicAutoScalerStatus(tac *v1alpha1.TidbClusterAutoScaler, memberType v1alpha1.MemberType, group string) (*v1alpha1.BasicAutoScalerStatus, bool) {
+	switch memberType {
+	case v1alpha1.TiKVMemberType:
+		status, existed := tac.Status.TiKV[group]
+		return &status.BasicAutoScalerStatus, existed
+	case v1alpha1.TiDBMemberType:
+		status, existed := tac.Status.TiDB[group]
+		return &status.BasicAutoScalerStatus, existed
+	}
+	return nil, false
+}
+
+// describeAutoRules describes the rules for the message of the auto-scaling recommendations
+func describeAutoRules(rules map[corev1.ResourceName]v1alpha1.AutoRule) string {
+	descs := make([]string, 0, len(rules))
+	for res, rule := range rules {
+		desc := fmt.Sprintf("%s max_threshold %v", res, rule.MaxThreshold)
+		if rule.MinThreshold != nil {
+			desc += fmt.Sprintf(" min_threshold %v", *rule.MinThreshold)
+		}
+		descs = append(descs, desc)
+	}
+	sort.Strings(descs)
+	return strings.Join(descs, ", ")
+}
+
 func defaultResources(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType) {
 	typ := fmt.Sprintf("default_%s", component.String())
 	resource := v1alpha1.AutoResource{}
@@ -198,10 +233,16 @@ func defaultTAC(tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
 
 	if tidb := tac.Spec.TiDB; tidb != nil {
 		defaultBasicAutoScaler(tac, v1alpha1.TiDBMemberType)
+		if tidb.ExternalCluster != nil && len(tidb.ExternalCluster.Namespace) < 1 {
+			tidb.ExternalCluster.Namespace = tac.Namespace
+		}
 	}
 
 	if tikv := tac.Spec.TiKV; tikv != nil {
 		defaultBasicAutoScaler(tac, v1alpha1.TiKVMemberType)
+		if tikv.Storage != nil && tikv.Storage.StabilizationWindowSeconds == nil {
+			tikv.Storage.StabilizationWindowSeconds = pointer.Int32Ptr(300)
+		}
 	}
 
 }
@@ -214,6 +255,10 @@ func validateBasicAutoScalerSpec(tac *v1alpha1.TidbClusterAutoScaler, component
 	}
 
 	if len(spec.Rules) == 0 {
+		if component == v1alpha1.TiKVMemberType && tac.Spec.TiKV.Storage != nil {
+			// tikv could be auto-scaled only on the storage pressure
+			return nil
+		}
 		return fmt.Errorf("no rules defined for component %s in %s/%s", component.String(), tac.Namespace, tac.Name)
 	}
 	resources := getSpecResources(tac, component)
@@ -281,6 +326,20 @@ func validateTAC(tac *v1alpha1.TidbClusterAutoScaler) error {
 		if err != nil {
 			return err
 		}
+		if ec := tidb.ExternalCluster; ec != nil {
+			if tidb.External == nil {
+				return fmt.Errorf("externalCluster of tidb requires external in %s/%s", tac.Namespace, tac.Name)
+			}
+			if len(ec.Name) == 0 {
+				return fmt.Errorf("no name provided for externalCluster of tidb in %s/%s", tac.Namespace, tac.Name)
+			}
+			if ec.Namespace == tac.Spec.Cluster.Namespace && ec.Name == tac.Spec.Cluster.Name {
+				return fmt.Errorf("externalCluster of tidb should not be the target cluster in %s/%s", tac.Namespace, tac.Name)
+			}
+			if ec.GarbageCollect && ec.Namespace != tac.Namespace {
+				return fmt.Errorf("externalCluster of tidb in namespace %s can't be garbage collected with %s/%s", ec.Namespace, tac.Namespace, tac.Name)
+			}
+		}
 	}
 
 	if tikv := tac.Spec.TiKV; tikv != nil {
@@ -288,6 +347,14 @@ func validateTAC(tac *v1alpha1.TidbClusterAutoScaler) error {
 		if err != nil {
 			return err
 		}
+		if storage := tikv.Storage; storage != nil {
+			if storage.UsedThresholdPercent <= 0 || storage.UsedThresholdPercent > 100 {
+				return fmt.Errorf("usedThresholdPercent (%d) should be between 1 and 100 for storage of tikv in %s/%s", storage.UsedThresholdPercent, tac.Namespace, tac.Name)
+			}
+			if storage.MaxReplicas <= 0 {
+				return fmt.Errorf("maxReplicas (%d) should be positive for storage of tikv in %s/%s", storage.MaxReplicas, tac.Namespace, tac.Name)
+			}
+		}
 	}
 
 	return nil
@@ -149,8 +149,7 @@ func (bc *backupCleaner) makeCleanJob(backup *v1alpha1.Backup) (*batchv1.Job, st
 	// mount volumes if specified
 	if backup.Spec.Local != nil {
 		klog.Info("mounting local volumes in Backup.Spec")
-		localVolume := backup.Spec.Local.Volume
-		localVolumeMount := backup.Spec.Local.VolumeMount
+		localVolume, localVolumeMount := backup.Spec.Local.GetVolumeAndMount()
 		volumes = append(volumes, localVolume)
 		volumeMounts = append(volumeMounts, localVolumeMount)
 	}
@@ -95,8 +95,24 @@ func (bm *backupManager) syncBackupJob(backup *v1alpha1.Backup) error {
 			return err
 		}
 
-		tikvImage := tc.TiKVImage()
+		var pdTC *v1alpha1.TidbCluster
+		pdTC, err = backuputil.GetPDTidbCluster(bm.deps.TiDBClusterLister, tc)
+		if err != nil {
+			reason := fmt.Sprintf("failed to fetch tidbcluster referenced by tidbcluster %s/%s", backupNamespace, backup.Spec.BR.Cluster)
+			bm.statusUpdater.Update(backup, &v1alpha1.BackupCondition{
+				Type:    v1alpha1.BackupRetryFailed,
+				Status:  corev1.ConditionTrue,
+				Reason:  reason,
+				Message: err.Error(),
+			}, nil)
+			return err
+		}
+
+		tikvImage := pdTC.TiKVImage()
 		err = backuputil.ValidateBackup(backup, tikvImage)
+		if err == nil {
+			err = backuputil.ValidateBRTarget(tc, "backup", backup.Spec.Type, backup.Spec.TableFilter)
+		}
 	}
 
 	if err != nil {
@@ -110,6 +126,17 @@ func (bm *backupManager) syncBackupJob(backup *v1alpha1.Backup) error {
 		return controller.IgnoreErrorf("invalid backup spec %s/%s cause %s", ns, name, err.Error())
 	}
 
+	var retryRecord *v1alpha1.BackoffRetryRecord
+	if backup.Spec.BR != nil && backup.Spec.BackoffRetryPolicy != nil {
+		retryRecord, err = bm.syncBackoffRetry(backup)
+		if err != nil {
+			return err
+		}
+		if retryRecord == nil && v1alpha1.IsBackupFailed(backup) {
+			return nil
+		}
+	}
+
 	_, err = bm.deps.JobLister.Jobs(ns).Get(backupJobName)
 	if err == nil {
 		// already have a backup job running，return directly
@@ -171,6 +198,10 @@ func (bm *backupManager) syncBackupJob(backup *v1alpha1.Backup) error {
 		return errMsg
 	}
 
+	if retryRecord != nil {
+		return bm.finishBackoffRetry(backup, retryRecord)
+	}
+
 	return bm.statusUpdater.Update(backup, &v1alpha1.BackupCondition{
 		Type:   v1alpha1.BackupScheduled,
 		Status: corev1.ConditionTrue,
@@ -311,6 +342,8 @@ func (bm *backupManager) makeExportJob(backup *v1alpha1.Backup) (*batchv1.Job, s
 		},
 	}
 
+	backuputil.MergePodTemplate(podSpec, backup.Spec.PodTemplate)
+
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        backup.GetBackupJobName(),
@@ -342,6 +375,10 @@ func (bm *backupManager) makeBackupJob(backup *v1alpha1.Backup) (*batchv1.Job, s
 	if err != nil {
 		return nil, fmt.Sprintf("failed to fetch tidbcluster %s/%s", backupNamespace, backup.Spec.BR.Cluster), err
 	}
+	pdTC, err := backuputil.GetPDTidbCluster(bm.deps.TiDBClusterLister, tc)
+	if err != nil {
+		return nil, fmt.Sprintf("failed to fetch tidbcluster referenced by tidbcluster %s/%s", backupNamespace, backup.Spec.BR.Cluster), err
+	}
 
 	var (
 		envVars []corev1.EnvVar
@@ -373,11 +410,12 @@ func (bm *backupManager) makeBackupJob(backup *v1alpha1.Backup) (*batchv1.Job, s
 		fmt.Sprintf("--namespace=%s", ns),
 		fmt.Sprintf("--backupName=%s", name),
 	}
-	tikvImage := tc.TiKVImage()
+	tikvImage := pdTC.TiKVImage()
 	_, tikvVersion := backuputil.ParseImage(tikvImage)
 	if tikvVersion != "" {
 		args = append(args, fmt.Sprintf("--tikvVersion=%s", tikvVersion))
 	}
+	args = append(args, fmt.Sprintf("--pdAddress=%s", backuputil.PDAddress(pdTC)))
 
 	jobLabels := util.CombineStringMap(label.NewBackup().Instance(backup.GetInstanceName()).BackupJob().Backup(name), backup.Labels)
 	podLabels := jobLabels
@@ -441,8 +479,9 @@ func (bm *backupManager) makeBackupJob(backup *v1alpha1.Backup) (*batchv1.Job, s
 
 	// mount volumes if specified
 	if backup.Spec.Local != nil {
-		volumes = append(volumes, backup.Spec.Local.Volume)
-		volumeMounts = append(volumeMounts, backup.Spec.Local.VolumeMount)
+		localVolume, localVolumeMount := backup.Spec.Local.GetVolumeAndMount()
+		volumes = append(volumes, localVolume)
+		volumeMounts = append(volumeMounts, localVolumeMount)
 	}
 
 	serviceAccount := constants.DefaultServiceAccountName
@@ -499,6 +538,8 @@ func (bm *backupManager) makeBackupJob(backup *v1alpha1.Backup) (*batchv1.Job, s
 		},
 	}
 
+	backuputil.MergePodTemplate(podSpec, backup.Spec.PodTemplate)
+
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        backup.GetBackupJobName(),
@@ -0,0 +1,346 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+var (
+	// retriableExitCodes are the exit codes of the backup container killed by a signal, which are
+	// retriable: 137 for SIGKILL and 143 for SIGTERM, e.g. the pod is evicted or the node is drained.
+	// BR exits with 1 for almost every error, so exit code 1 is only retried with a transient error.
+	retriableExitCodes = map[int32]struct{}{
+		137: {},
+		143: {},
+	}
+
+	// terminalErrorKeywords are the keywords of the errors which can not be fixed by retry,
+	// e.g. the errors of authentication or a nonexistent bucket.
+	terminalErrorKeywords = []string{
+		"AccessDenied",
+		"InvalidAccessKeyId",
+		"SignatureDoesNotMatch",
+		"ExpiredToken",
+		"NoSuchBucket",
+		"ContainerNotFound",
+		"AuthenticationFailed",
+		"permission denied",
+	}
+
+	// transientErrorKeywords are the keywords of the errors reported by BR which may be fixed by retry,
+	// e.g. the errors of network, a busy TiKV or the throttling of the storage.
+	transientErrorKeywords = []string{
+		"connection refused",
+		"connection reset by peer",
+		"i/o timeout",
+		"context deadline exceeded",
+		"TLS handshake timeout",
+		"unexpected EOF",
+		"ServerIsBusy",
+		"RegionUnavailable",
+		"NotLeader",
+		"SlowDown",
+		"RequestTimeout",
+		"ServiceUnavailable",
+	}
+)
+
+const (
+	// oomKilledReason is the reason of a container terminated for running out of memory
+	oomKilledReason = "OOMKilled"
+	// podEvictedReason is the reason of a pod evicted by the kubelet
+	podEvictedReason = "Evicted"
+	// jobBackoffLimitExceededReason is the reason of the Failed condition of a job whose pods failed
+	jobBackoffLimitExceededReason = "BackoffLimitExceeded"
+)
+
+// syncBackoffRetry detects the retriable failure of the backup job and retries the backup according
+// to the backoff retry policy. It returns the record of the retry if the backup job should be recreated.
+func (bm *backupManager) syncBackoffRetry(backup *v1alpha1.Backup) (*v1alpha1.BackoffRetryRecord, error) {
+	if v1alpha1.IsBackupRetrying(backup) {
+		return bm.retryFailedBackup(backup, time.Now())
+	}
+	if v1alpha1.IsBackupFailed(backup) {
+		return nil, bm.detectBackoffRetry(backup, time.Now())
+	}
+	return nil, nil
+}
+
+// detectBackoffRetry records a retry of the failed backup if the failure is retriable
+// and the backoff retry policy allows.
+func (bm *backupManager) detectBackoffRetry(backup *v1alpha1.Backup, now time.Time) error {
+	ns := backup.GetNamespace()
+	name := backup.GetName()
+	backupJobName := backup.GetBackupJobName()
+
+	job, err := bm.deps.JobLister.Jobs(ns).Get(backupJobName)
+	if errors.IsNotFound(err) {
+		klog.V(4).Infof("backup %s/%s is failed without job %s, skip retrying", ns, name, backupJobName)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("backup %s/%s get job %s failed, err: %v", ns, name, backupJobName, err)
+	}
+	pods, err := bm.getBackupJobPods(backup, job)
+	if err != nil {
+		return err
+	}
+	if !isBackupJobFailed(job, pods) {
+		// the failure may be reported by backup-manager before the pod terminates
+		klog.V(4).Infof("backup %s/%s is failed but job %s has not failed yet", ns, name, backupJobName)
+		return nil
+	}
+
+	retriable, retryReason := classifyBackupFailure(backup, job, pods)
+	if !retriable {
+		klog.V(4).Infof("backup %s/%s is failed with a non-retriable failure: %s", ns, name, retryReason)
+		return nil
+	}
+	record, reason := newBackoffRetryRecord(backup, retryReason, now)
+	if record == nil {
+		klog.V(4).Infof("backup %s/%s is failed and will not be retried any more: %s", ns, name, reason)
+		return nil
+	}
+
+	klog.Infof("backup %s/%s is failed with a retriable failure %s, retry %d at %s", ns, name, retryReason, record.RetryNum, record.ExpectedRetryAt)
+	err = bm.statusUpdater.Update(backup, &v1alpha1.BackupCondition{
+		Type:    v1alpha1.BackupRetryTheFailed,
+		Status:  corev1.ConditionTrue,
+		Reason:  retryReason,
+		Message: fmt.Sprintf("retry %d at %s", record.RetryNum, record.ExpectedRetryAt.Format(time.RFC3339)),
+	}, &controller.BackupUpdateStatus{
+		BackoffRetryRecord: record,
+	})
+	if err != nil {
+		return err
+	}
+	return controller.RequeueErrorf("backup %s/%s will be retried at %s", ns, name, record.ExpectedRetryAt)
+}
+
+// retryFailedBackup waits for the backoff of the retry and the deletion of the failed backup job.
+// It returns the record of the retry once the backup job can be recreated.
+func (bm *backupManager) retryFailedBackup(backup *v1alpha1.Backup, now time.Time) (*v1alpha1.BackoffRetryRecord, error) {
+	ns := backup.GetNamespace()
+	name := backup.GetName()
+	backupJobName := backup.GetBackupJobName()
+
+	records := backup.Status.BackoffRetryStatus
+	if len(records) == 0 {
+		return nil, fmt.Errorf("backup %s/%s is retrying without backoff retry record", ns, name)
+	}
+	last := records[len(records)-1]
+	if last.ExpectedRetryAt != nil && now.Before(last.ExpectedRetryAt.Time) {
+		return nil, controller.RequeueErrorf("backup %s/%s will be retried at %s", ns, name, last.ExpectedRetryAt)
+	}
+
+	job, err := bm.deps.JobLister.Jobs(ns).Get(backupJobName)
+	if err == nil {
+		if last.DetectFailedAt != nil && job.CreationTimestamp.After(last.DetectFailedAt.Time) {
+			// the job has been recreated by the retry, but the status was not updated
+			realRetryAt := job.CreationTimestamp
+			return nil, bm.finishBackoffRetry(backup, &v1alpha1.BackoffRetryRecord{
+				RetryNum:    last.RetryNum,
+				RealRetryAt: &realRetryAt,
+			})
+		}
+		if job.DeletionTimestamp == nil {
+			if err := bm.deps.JobControl.DeleteJob(backup, job); err != nil {
+				return nil, err
+			}
+		}
+		return nil, controller.RequeueErrorf("backup %s/%s is waiting for the failed job %s to be deleted", ns, name, backupJobName)
+	}
+	if !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("backup %s/%s get job %s failed, err: %v", ns, name, backupJobName, err)
+	}
+
+	if v1alpha1.IsBackupFailed(backup) {
+		err := bm.statusUpdater.Update(backup, &v1alpha1.BackupCondition{
+			Type:    v1alpha1.BackupFailed,
+			Status:  corev1.ConditionFalse,
+			Reason:  string(v1alpha1.BackupRetryTheFailed),
+			Message: fmt.Sprintf("the failure is being retried, retry %d", last.RetryNum),
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	realRetryAt := metav1.NewTime(now)
+	return &v1alpha1.BackoffRetryRecord{
+		RetryNum:    last.RetryNum,
+		RealRetryAt: &realRetryAt,
+	}, nil
+}
+
+// finishBackoffRetry records the retry once the backup job is recreated
+func (bm *backupManager) finishBackoffRetry(backup *v1alpha1.Backup, record *v1alpha1.BackoffRetryRecord) error {
+	err := bm.statusUpdater.Update(backup, &v1alpha1.BackupCondition{
+		Type:    v1alpha1.BackupRetryTheFailed,
+		Status:  corev1.ConditionFalse,
+		Reason:  "BackupJobRecreated",
+		Message: fmt.Sprintf("backup job %s is recreated for retry %d", backup.GetBackupJobName(), record.RetryNum),
+	}, &controller.BackupUpdateStatus{
+		BackoffRetryRecord: record,
+	})
+	if err != nil {
+		return err
+	}
+	// the message differs from the one of the last schedule, so that the phase is updated
+	return bm.statusUpdater.Update(backup, &v1alpha1.BackupCondition{
+		Type:    v1alpha1.BackupScheduled,
+		Status:  corev1.ConditionTrue,
+		Message: fmt.Sprintf("retry %d", record.RetryNum),
+	}, nil)
+}
+
+// getBackupJobPods returns the pods controlled by the backup job
+func (bm *backupManager) getBackupJobPods(backup *v1alpha1.Backup, job *batchv1.Job) ([]*corev1.Pod, error) {
+	ns := backup.GetNamespace()
+	name := backup.GetName()
+	selector, err := label.NewBackup().Instance(backup.GetInstanceName()).BackupJob().Backup(name).Selector()
+	if err != nil {
+		return nil, fmt.Errorf("generate selector for backup %s/%s failed, err: %v", ns, name, err)
+	}
+	pods, err := bm.deps.PodLister.Pods(ns).List(selector)
+	if err != nil {
+		return nil, fmt.Errorf("list pods of backup %s/%s with selector %s failed, err: %v", ns, name, selector, err)
+	}
+	var jobPods []*corev1.Pod
+	for _, pod := range pods {
+		if metav1.IsControlledBy(pod, job) {
+			jobPods = append(jobPods, pod)
+		}
+	}
+	return jobPods, nil
+}
+
+// isBackupJobFailed returns whether the backup job or any of its pods has failed
+func isBackupJobFailed(job *batchv1.Job, pods []*corev1.Pod) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyBackupFailure returns whether the failure of the backup job is retriable, and the reason.
+// Only the transient failures are retriable: the pod is evicted or killed by a signal, or BR fails
+// with a transient error. The backup which runs out of memory most likely runs out of memory again,
+// so OOMKilled is not retried. The other failures of BR, e.g. bad credentials or a bad
+// storage config, exit with the same code 1 and are not retried.
+func classifyBackupFailure(backup *v1alpha1.Backup, job *batchv1.Job, pods []*corev1.Pod) (bool, string) {
+	var message string
+	if _, condition := v1alpha1.GetBackupCondition(&backup.Status, v1alpha1.BackupFailed); condition != nil {
+		message = strings.ToLower(condition.Message)
+	}
+	for _, keyword := range terminalErrorKeywords {
+		if strings.Contains(message, strings.ToLower(keyword)) {
+			return false, fmt.Sprintf("TerminalError(%s)", keyword)
+		}
+	}
+
+	for _, c := range job.Status.Conditions {
+		// the job fails with BackoffLimitExceeded once its pods fail, which is classified by the pods
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue && c.Reason != jobBackoffLimitExceededReason {
+			return false, c.Reason
+		}
+	}
+
+	for _, pod := range pods {
+		if pod.Status.Reason == podEvictedReason {
+			return true, podEvictedReason
+		}
+		for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+			for _, cs := range statuses {
+				t := cs.State.Terminated
+				if t == nil {
+					continue
+				}
+				if t.Reason == oomKilledReason {
+					return false, oomKilledReason
+				}
+				if _, ok := retriableExitCodes[t.ExitCode]; ok {
+					return true, fmt.Sprintf("ExitCode(%d)", t.ExitCode)
+				}
+			}
+		}
+	}
+
+	for _, keyword := range transientErrorKeywords {
+		if strings.Contains(message, strings.ToLower(keyword)) {
+			return true, fmt.Sprintf("TransientError(%s)", keyword)
+		}
+	}
+	return false, "UnknownFailure"
+}
+
+// newBackoffRetryRecord returns the record of the next retry of the failed backup according to
+// the backoff retry policy, or nil and the reason if the backup should not be retried any more.
+// The n-th retry is expected at MinRetryDuration * 2^(n-1) after the failure is detected.
+func newBackoffRetryRecord(backup *v1alpha1.Backup, retryReason string, now time.Time) (*v1alpha1.BackoffRetryRecord, string) {
+	policy := backup.Spec.BackoffRetryPolicy
+	if policy == nil {
+		return nil, "no backoff retry policy"
+	}
+	minRetryDuration, err := policy.GetMinRetryDuration()
+	if err != nil {
+		return nil, fmt.Sprintf("invalid minRetryDuration: %v", err)
+	}
+	retryTimeout, err := policy.GetRetryTimeout()
+	if err != nil {
+		return nil, fmt.Sprintf("invalid retryTimeout: %v", err)
+	}
+
+	records := backup.Status.BackoffRetryStatus
+	retried := len(records)
+	if int32(retried) >= policy.GetMaxRetryTimes() {
+		return nil, fmt.Sprintf("exceed the max retry times %d", policy.GetMaxRetryTimes())
+	}
+	if retried > 0 && records[0].DetectFailedAt != nil && now.Sub(records[0].DetectFailedAt.Time) > retryTimeout {
+		return nil, fmt.Sprintf("exceed the retry timeout %s", retryTimeout)
+	}
+
+	var originalReason string
+	if _, condition := v1alpha1.GetBackupCondition(&backup.Status, v1alpha1.BackupFailed); condition != nil {
+		originalReason = condition.Reason
+	}
+	detectFailedAt := metav1.NewTime(now)
+	expectedRetryAt := metav1.NewTime(now.Add(minRetryDuration << uint(retried)))
+	return &v1alpha1.BackoffRetryRecord{
+		RetryNum:        retried + 1,
+		DetectFailedAt:  &detectFailedAt,
+		ExpectedRetryAt: &expectedRetryAt,
+		RetryReason:     retryReason,
+		OriginalReason:  originalReason,
+	}, ""
+}
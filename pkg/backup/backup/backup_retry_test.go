@@ -0,0 +1,317 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestClassifyBackupFailure(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	failedJob := func(reason string) *batchv1.Job {
+		return &batchv1.Job{Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: reason},
+		}}}
+	}
+	terminatedPod := func(exitCode int32, reason string) *corev1.Pod {
+		return &corev1.Pod{Status: corev1.PodStatus{
+			Phase: corev1.PodFailed,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Reason: reason}},
+			}},
+		}}
+	}
+
+	tests := []struct {
+		name            string
+		failedMessage   string
+		job             *batchv1.Job
+		pods            []*corev1.Pod
+		expectRetriable bool
+		expectReason    string
+	}{
+		{
+			name:            "BR failed with exit code 1",
+			failedMessage:   "[BR:ExternalStorage:ErrStorageInvalidConfig] invalid external storage config",
+			job:             failedJob("BackoffLimitExceeded"),
+			pods:            []*corev1.Pod{terminatedPod(1, "Error")},
+			expectRetriable: false,
+			expectReason:    "UnknownFailure",
+		},
+		{
+			name:            "BR failed with a transient error",
+			failedMessage:   "[BR:Common:ErrFailedToConnect] dial tcp 10.0.0.1:2379: connect: connection refused",
+			job:             failedJob("BackoffLimitExceeded"),
+			pods:            []*corev1.Pod{terminatedPod(1, "Error")},
+			expectRetriable: true,
+			expectReason:    "TransientError(connection refused)",
+		},
+		{
+			name:            "pod OOMKilled",
+			job:             failedJob("BackoffLimitExceeded"),
+			pods:            []*corev1.Pod{terminatedPod(137, "OOMKilled")},
+			expectRetriable: false,
+			expectReason:    "OOMKilled",
+		},
+		{
+			name: "pod evicted",
+			job:  failedJob("BackoffLimitExceeded"),
+			pods: []*corev1.Pod{{Status: corev1.PodStatus{
+				Phase:  corev1.PodFailed,
+				Reason: "Evicted",
+			}}},
+			expectRetriable: true,
+			expectReason:    "Evicted",
+		},
+		{
+			name:            "access denied",
+			failedMessage:   "[BR:ExternalStorage:ErrStorageInvalidConfig] AccessDenied: Access Denied status code: 403",
+			job:             failedJob("BackoffLimitExceeded"),
+			pods:            []*corev1.Pod{terminatedPod(1, "Error")},
+			expectRetriable: false,
+			expectReason:    "TerminalError(AccessDenied)",
+		},
+		{
+			name:            "nonexistent bucket",
+			failedMessage:   "NoSuchBucket: The specified bucket does not exist",
+			job:             failedJob("BackoffLimitExceeded"),
+			expectRetriable: false,
+			expectReason:    "TerminalError(NoSuchBucket)",
+		},
+		{
+			name:            "job deadline exceeded",
+			job:             failedJob("DeadlineExceeded"),
+			expectRetriable: false,
+			expectReason:    "DeadlineExceeded",
+		},
+		{
+			name:            "pod terminated by SIGTERM before the job fails",
+			job:             &batchv1.Job{},
+			pods:            []*corev1.Pod{terminatedPod(143, "Error")},
+			expectRetriable: true,
+			expectReason:    "ExitCode(143)",
+		},
+		{
+			name:            "pod failed with an unknown exit code",
+			job:             &batchv1.Job{},
+			pods:            []*corev1.Pod{terminatedPod(2, "Error")},
+			expectRetriable: false,
+			expectReason:    "UnknownFailure",
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test: %s", test.name)
+		backup := &v1alpha1.Backup{}
+		backup.Status.Conditions = []v1alpha1.BackupCondition{
+			{Type: v1alpha1.BackupFailed, Status: corev1.ConditionTrue, Message: test.failedMessage},
+		}
+		retriable, reason := classifyBackupFailure(backup, test.job, test.pods)
+		g.Expect(retriable).To(Equal(test.expectRetriable))
+		g.Expect(reason).To(Equal(test.expectReason))
+	}
+}
+
+func TestNewBackoffRetryRecord(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	now := time.Unix(10000, 0)
+	backup := &v1alpha1.Backup{}
+	backup.Spec.BackoffRetryPolicy = &v1alpha1.BackoffRetryPolicy{
+		MaxRetryTimes:    pointer.Int32Ptr(3),
+		MinRetryDuration: "60s",
+		RetryTimeout:     "10m",
+	}
+	backup.Status.Conditions = []v1alpha1.BackupCondition{
+		{Type: v1alpha1.BackupFailed, Status: corev1.ConditionTrue, Reason: "AlreadyFailed"},
+	}
+
+	// the first retry
+	record, _ := newBackoffRetryRecord(backup, "BackoffLimitExceeded", now)
+	g.Expect(record).NotTo(BeNil())
+	g.Expect(record.RetryNum).To(Equal(1))
+	g.Expect(record.DetectFailedAt.Time).To(Equal(now))
+	g.Expect(record.ExpectedRetryAt.Time).To(Equal(now.Add(time.Minute)))
+	g.Expect(record.RetryReason).To(Equal("BackoffLimitExceeded"))
+	g.Expect(record.OriginalReason).To(Equal("AlreadyFailed"))
+	backup.Status.BackoffRetryStatus = append(backup.Status.BackoffRetryStatus, *record)
+
+	// the backoff doubles
+	now = now.Add(2 * time.Minute)
+	record, _ = newBackoffRetryRecord(backup, "BackoffLimitExceeded", now)
+	g.Expect(record).NotTo(BeNil())
+	g.Expect(record.RetryNum).To(Equal(2))
+	g.Expect(record.ExpectedRetryAt.Time).To(Equal(now.Add(2 * time.Minute)))
+	backup.Status.BackoffRetryStatus = append(backup.Status.BackoffRetryStatus, *record)
+
+	// exceed the retry timeout since the first failure
+	record, reason := newBackoffRetryRecord(backup, "BackoffLimitExceeded", now.Add(9*time.Minute))
+	g.Expect(record).To(BeNil())
+	g.Expect(reason).To(ContainSubstring("timeout"))
+
+	// exceed the max retry times
+	record, _ = newBackoffRetryRecord(backup, "BackoffLimitExceeded", now.Add(3*time.Minute))
+	g.Expect(record).NotTo(BeNil())
+	backup.Status.BackoffRetryStatus = append(backup.Status.BackoffRetryStatus, *record)
+	record, reason = newBackoffRetryRecord(backup, "BackoffLimitExceeded", now.Add(4*time.Minute))
+	g.Expect(record).To(BeNil())
+	g.Expect(reason).To(ContainSubstring("max retry times"))
+
+	// the backup is not retried if the max retry times is 0
+	backup.Status.BackoffRetryStatus = nil
+	backup.Spec.BackoffRetryPolicy.MaxRetryTimes = pointer.Int32Ptr(0)
+	record, _ = newBackoffRetryRecord(backup, "BackoffLimitExceeded", now)
+	g.Expect(record).To(BeNil())
+}
+
+// TestBackoffRetryAcrossRestarts checks that the retries are only recorded in the status of the
+// backup, so that a restarted controller goes on with the retries of the failed backup.
+func TestBackoffRetryAcrossRestarts(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.Close()
+	deps := helper.Deps
+
+	backup := genValidBRBackups()[0]
+	backup.Spec.BackoffRetryPolicy = &v1alpha1.BackoffRetryPolicy{
+		MaxRetryTimes:    pointer.Int32Ptr(2),
+		MinRetryDuration: "5m",
+		RetryTimeout:     "30m",
+	}
+	ns := backup.Namespace
+	_, err := deps.Clientset.PingcapV1alpha1().Backups(ns).Create(context.TODO(), backup, metav1.CreateOptions{})
+	g.Expect(err).Should(BeNil())
+	helper.CreateSecret(backup)
+	helper.CreateTC(backup.Spec.BR.ClusterNamespace, backup.Spec.BR.Cluster)
+
+	getBackup := func() *v1alpha1.Backup {
+		b, err := deps.Clientset.PingcapV1alpha1().Backups(ns).Get(context.TODO(), backup.Name, metav1.GetOptions{})
+		g.Expect(err).Should(BeNil())
+		return b
+	}
+	failJob := func(bm *backupManager) {
+		job, err := deps.KubeClientset.BatchV1().Jobs(ns).Get(context.TODO(), backup.GetBackupJobName(), metav1.GetOptions{})
+		g.Expect(err).Should(BeNil())
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"},
+		}
+		_, err = deps.KubeClientset.BatchV1().Jobs(ns).UpdateStatus(context.TODO(), job, metav1.UpdateOptions{})
+		g.Expect(err).Should(BeNil())
+		g.Eventually(func() bool {
+			job, err := deps.JobLister.Jobs(ns).Get(backup.GetBackupJobName())
+			return err == nil && len(job.Status.Conditions) > 0
+		}, time.Second*10).Should(BeTrue())
+		err = bm.statusUpdater.Update(getBackup(), &v1alpha1.BackupCondition{
+			Type:    v1alpha1.BackupFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "BackupDataToRemoteFailed",
+			Message: "SlowDown: Please reduce your request rate",
+		}, nil)
+		g.Expect(err).Should(BeNil())
+	}
+	waitJobDeleted := func() {
+		g.Eventually(func() bool {
+			_, err := deps.JobLister.Jobs(ns).Get(backup.GetBackupJobName())
+			return errors.IsNotFound(err)
+		}, time.Second*10).Should(BeTrue())
+	}
+
+	bm := NewBackupManager(deps).(*backupManager)
+	g.Expect(bm.syncBackupJob(getBackup())).Should(Succeed())
+	helper.waitJobSynced(backup)
+	failJob(bm)
+
+	// the failure is detected, and the retry is recorded
+	start := time.Now().Add(-time.Hour)
+	err = bm.detectBackoffRetry(getBackup(), start)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	b := getBackup()
+	g.Expect(v1alpha1.IsBackupRetrying(b)).To(BeTrue())
+	g.Expect(b.Status.BackoffRetryStatus).To(HaveLen(1))
+	g.Expect(b.Status.BackoffRetryStatus[0].RetryNum).To(Equal(1))
+	g.Expect(b.Status.BackoffRetryStatus[0].ExpectedRetryAt.Time.Unix()).To(Equal(start.Add(5 * time.Minute).Unix()))
+	g.Expect(b.Status.BackoffRetryStatus[0].OriginalReason).To(Equal("BackupDataToRemoteFailed"))
+
+	// the controller restarts and waits for the backoff
+	bm = NewBackupManager(deps).(*backupManager)
+	_, err = bm.retryFailedBackup(getBackup(), start.Add(time.Minute))
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	_, err = deps.JobLister.Jobs(ns).Get(backup.GetBackupJobName())
+	g.Expect(err).Should(BeNil())
+
+	// the failed job is deleted after the backoff, and then recreated
+	err = bm.syncBackupJob(getBackup())
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	waitJobDeleted()
+	g.Expect(bm.syncBackupJob(getBackup())).Should(Succeed())
+	b = getBackup()
+	g.Expect(v1alpha1.IsBackupRetrying(b)).To(BeFalse())
+	g.Expect(v1alpha1.IsBackupFailed(b)).To(BeFalse())
+	g.Expect(b.Status.Phase).To(Equal(v1alpha1.BackupScheduled))
+	g.Expect(b.Status.BackoffRetryStatus).To(HaveLen(1))
+	g.Expect(b.Status.BackoffRetryStatus[0].RealRetryAt).NotTo(BeNil())
+	helper.waitJobSynced(backup)
+
+	// the recreated job fails again after another restart, the backoff doubles
+	helper.waitJobSynced(backup)
+	bm = NewBackupManager(deps).(*backupManager)
+	failJob(bm)
+	now := start.Add(10 * time.Minute)
+	err = bm.detectBackoffRetry(getBackup(), now)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	b = getBackup()
+	g.Expect(b.Status.BackoffRetryStatus).To(HaveLen(2))
+	g.Expect(b.Status.BackoffRetryStatus[1].RetryNum).To(Equal(2))
+	g.Expect(b.Status.BackoffRetryStatus[1].ExpectedRetryAt.Time.Unix()).To(Equal(now.Add(10 * time.Minute).Unix()))
+
+	// the second retry is done
+	err = bm.syncBackupJob(getBackup())
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	waitJobDeleted()
+	g.Expect(bm.syncBackupJob(getBackup())).Should(Succeed())
+	helper.waitJobSynced(backup)
+
+	// the backup is not retried any more after the max retry times
+	bm = NewBackupManager(deps).(*backupManager)
+	failJob(bm)
+	g.Expect(bm.detectBackoffRetry(getBackup(), now.Add(20*time.Minute))).Should(Succeed())
+	b = getBackup()
+	g.Expect(v1alpha1.IsBackupRetrying(b)).To(BeFalse())
+	g.Expect(v1alpha1.IsBackupFailed(b)).To(BeTrue())
+	g.Expect(b.Status.BackoffRetryStatus).To(HaveLen(2))
+	g.Expect(bm.syncBackupJob(b)).Should(Succeed())
+	_, err = deps.JobLister.Jobs(ns).Get(backup.GetBackupJobName())
+	g.Expect(err).Should(BeNil())
+}
+
+// waitJobSynced waits until the job of the backup is synced to the job lister
+func (h *helper) waitJobSynced(backup *v1alpha1.Backup) {
+	g := NewGomegaWithT(h.T)
+	g.Eventually(func() error {
+		_, err := h.Deps.JobLister.Jobs(backup.Namespace).Get(backup.GetBackupJobName())
+		return err
+	}, time.Second*10).Should(BeNil())
+}
@@ -268,6 +268,75 @@ func TestBackupManagerBR(t *testing.T) {
 		g.Expect(job.Spec.Template.Spec.Containers[0].Env).To(gomega.ContainElement(env1))
 		g.Expect(job.Spec.Template.Spec.Containers[0].Env).To(gomega.ContainElement(env2Yes))
 		g.Expect(job.Spec.Template.Spec.Containers[0].Env).NotTo(gomega.ContainElement(env2No))
+
+		// check the local storage is mounted
+		if backup.Spec.Local != nil {
+			checkLocalStorageMounted(g, backup, job)
+		}
+	}
+}
+
+func TestBackupManagerBRTiFlashOnlyCluster(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.Close()
+	deps := helper.Deps
+
+	bm := NewBackupManager(deps).(*backupManager)
+
+	// a TiFlash-only cluster served by the PD of the primary cluster
+	helper.CreateTC("ns-primary", "primary")
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "tiflash-only"},
+		Spec: v1alpha1.TidbClusterSpec{
+			TiFlash: &v1alpha1.TiFlashSpec{},
+			Cluster: &v1alpha1.TidbClusterRef{Namespace: "ns-primary", Name: "primary"},
+		},
+	}
+	_, err := deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+	g.Expect(err).Should(BeNil())
+	g.Eventually(func() error {
+		_, err := deps.TiDBClusterLister.TidbClusters(tc.Namespace).Get(tc.Name)
+		return err
+	}, time.Second*10).Should(BeNil())
+
+	// the backups share the secrets
+	helper.CreateSecret(genValidBRBackups()[0])
+	newBackup := func(name string, tp v1alpha1.BackupType) *v1alpha1.Backup {
+		backup := genValidBRBackups()[0]
+		backup.Name = name
+		backup.Spec.Type = tp
+		backup.Spec.BR.Cluster = tc.Name
+		_, err := deps.Clientset.PingcapV1alpha1().Backups(backup.Namespace).Create(context.TODO(), backup, metav1.CreateOptions{})
+		g.Expect(err).Should(BeNil())
+		return backup
+	}
+
+	// the PD address of the job is resolved through the cluster reference
+	backup := newBackup("backup-db", v1alpha1.BackupTypeDB)
+	err = bm.syncBackupJob(backup)
+	g.Expect(err).Should(BeNil())
+	helper.hasCondition(backup.Namespace, backup.Name, v1alpha1.BackupScheduled, "")
+	job, err := deps.KubeClientset.BatchV1().Jobs(backup.Namespace).Get(context.TODO(), backup.GetBackupJobName(), metav1.GetOptions{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(job.Spec.Template.Spec.Containers[0].Args).To(gomega.ContainElement("--pdAddress=primary-pd.ns-primary:2379"))
+
+	// a full backup covers the whole primary cluster and is rejected
+	backup = newBackup("backup-full", v1alpha1.BackupTypeFull)
+	err = bm.syncBackupJob(backup)
+	g.Expect(err).ShouldNot(BeNil())
+	helper.hasCondition(backup.Namespace, backup.Name, v1alpha1.BackupInvalid, "")
+	_, err = deps.KubeClientset.BatchV1().Jobs(backup.Namespace).Get(context.TODO(), backup.GetBackupJobName(), metav1.GetOptions{})
+	g.Expect(errors.IsNotFound(err)).Should(BeTrue())
+}
+
+func checkLocalStorageMounted(g *GomegaWithT, backup *v1alpha1.Backup, job *batchv1.Job) {
+	volume, volumeMount := backup.Spec.Local.GetVolumeAndMount()
+	g.Expect(job.Spec.Template.Spec.Volumes).To(gomega.ContainElement(volume))
+	g.Expect(job.Spec.Template.Spec.Containers[0].VolumeMounts).To(gomega.ContainElement(volumeMount))
+	if backup.Spec.Local.PersistentVolumeClaimName != "" {
+		g.Expect(volume.PersistentVolumeClaim.ClaimName).To(Equal(backup.Spec.Local.PersistentVolumeClaimName))
+		g.Expect(volumeMount.MountPath).To(Equal(v1alpha1.LocalStorageMountPath))
 	}
 }
 
@@ -301,8 +370,11 @@ func TestClean(t *testing.T) {
 		err = bc.Clean(backup)
 		g.Expect(err).Should(BeNil())
 		helper.hasCondition(backup.Namespace, backup.Name, v1alpha1.BackupClean, "")
-		_, err = deps.KubeClientset.BatchV1().Jobs(backup.Namespace).Get(context.TODO(), backup.GetCleanJobName(), metav1.GetOptions{})
+		cleanJob, err := deps.KubeClientset.BatchV1().Jobs(backup.Namespace).Get(context.TODO(), backup.GetCleanJobName(), metav1.GetOptions{})
 		g.Expect(err).Should(BeNil())
+		if backup.Spec.Local != nil {
+			checkLocalStorageMounted(g, backup, cleanJob)
+		}
 
 		// test already have a clean job running
 		g.Eventually(func() error {
@@ -20,6 +20,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/backup"
@@ -27,6 +28,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/util"
 	"github.com/robfig/cron"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog"
@@ -54,8 +56,12 @@ func (bm *backupScheduleManager) Sync(bs *v1alpha1.BackupSchedule) error {
 		return controller.IgnoreErrorf("backupSchedule %s/%s has been paused", bs.GetNamespace(), bs.GetName())
 	}
 
+	lastBackupRunning := false
 	if err := bm.canPerformNextBackup(bs); err != nil {
-		return err
+		if !controller.IsRequeueError(err) || !isValidConcurrencyPolicy(bs.Spec.ConcurrencyPolicy) {
+			return err
+		}
+		lastBackupRunning = true
 	}
 
 	scheduledTime, err := getLastScheduledTime(bs, bm.now)
@@ -63,9 +69,25 @@ func (bm *backupScheduleManager) Sync(bs *v1alpha1.BackupSchedule) error {
 		return err
 	}
 
-	// delete the last backup job for release the backup PVC
-	if err := bm.deleteLastBackupJob(bs); err != nil {
-		return nil
+	if lastBackupRunning {
+		switch bs.Spec.ConcurrencyPolicy {
+		case v1alpha1.BackupScheduleConcurrencyForbid:
+			// the skipped time is taken into account by getLastScheduledTime, so the skipped backup
+			// is not performed after the last backup finishes
+			bs.Status.LastSkippedTime = &metav1.Time{Time: *scheduledTime}
+			bm.deps.Recorder.Eventf(bs, corev1.EventTypeWarning, "BackupSkipped",
+				"skip the backup scheduled at %s, the last backup %s is still running", scheduledTime.Format(time.RFC3339), bs.Status.LastBackup)
+			return nil
+		case v1alpha1.BackupScheduleConcurrencyReplace:
+			if err := bm.deleteLastBackup(bs); err != nil {
+				return err
+			}
+		}
+	} else {
+		// delete the last backup job for release the backup PVC
+		if err := bm.deleteLastBackupJob(bs); err != nil {
+			return nil
+		}
 	}
 
 	backup, err := createBackup(bm.deps.BackupControl, bs, *scheduledTime)
@@ -104,6 +126,34 @@ func (bm *backupScheduleManager) deleteLastBackupJob(bs *v1alpha1.BackupSchedule
 	return bm.deps.JobControl.DeleteJob(backup, job)
 }
 
+// deleteLastBackup deletes the running last backup to be replaced by the scheduled backup,
+// the data of the backup is cleaned according to its clean policy
+func (bm *backupScheduleManager) deleteLastBackup(bs *v1alpha1.BackupSchedule) error {
+	ns := bs.GetNamespace()
+	bsName := bs.GetName()
+
+	backup, err := bm.deps.BackupLister.Backups(ns).Get(bs.Status.LastBackup)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("backup schedule %s/%s, get backup %s failed, err: %v", ns, bsName, bs.Status.LastBackup, err)
+	}
+	if err := bm.deps.BackupControl.DeleteBackup(backup); err != nil {
+		return fmt.Errorf("backup schedule %s/%s, delete running backup %s failed, err: %v", ns, bsName, backup.GetName(), err)
+	}
+	bm.deps.Recorder.Eventf(bs, corev1.EventTypeNormal, "BackupReplaced", "running backup %s is replaced by the scheduled backup", backup.GetName())
+	return nil
+}
+
+func isValidConcurrencyPolicy(policy v1alpha1.BackupScheduleConcurrencyPolicy) bool {
+	switch policy {
+	case v1alpha1.BackupScheduleConcurrencyAllow, v1alpha1.BackupScheduleConcurrencyForbid, v1alpha1.BackupScheduleConcurrencyReplace:
+		return true
+	}
+	return false
+}
+
 func (bm *backupScheduleManager) canPerformNextBackup(bs *v1alpha1.BackupSchedule) error {
 	ns := bs.GetNamespace()
 	bsName := bs.GetName()
@@ -150,6 +200,10 @@ func getLastScheduledTime(bs *v1alpha1.BackupSchedule, nowFn nowFn) (*time.Time,
 		// In any case, use the creation time of the backupSchedule as last known start time.
 		earliestTime = bs.ObjectMeta.CreationTimestamp.Time
 	}
+	// the skipped scheduled times are not performed any more
+	if bs.Status.LastSkippedTime != nil && bs.Status.LastSkippedTime.Time.After(earliestTime) {
+		earliestTime = bs.Status.LastSkippedTime.Time
+	}
 
 	now := nowFn()
 	if earliestTime.After(now) {
@@ -256,86 +310,138 @@ func (bm *backupScheduleManager) backupGC(bs *v1alpha1.BackupSchedule) {
 	ns := bs.GetNamespace()
 	bsName := bs.GetName()
 
-	// if MaxBackups and MaxReservedTime are set at the same time, MaxReservedTime is preferred.
-	if bs.Spec.MaxReservedTime != nil {
-		bm.backupGCByMaxReservedTime(bs)
-		return
-	}
-
-	if bs.Spec.MaxBackups != nil && *bs.Spec.MaxBackups > 0 {
-		bm.backupGCByMaxBackups(bs)
-		return
-	}
-	// TODO: When the backup schedule gc policy is not set, we should set a default backup gc policy.
-	klog.Warningf("backup schedule %s/%s does not set backup gc policy", ns, bsName)
-}
-
-func (bm *backupScheduleManager) backupGCByMaxReservedTime(bs *v1alpha1.BackupSchedule) {
-	ns := bs.GetNamespace()
-	bsName := bs.GetName()
-
-	reservedTime, err := time.ParseDuration(*bs.Spec.MaxReservedTime)
-	if err != nil {
-		klog.Errorf("backup schedule %s/%s, invalid MaxReservedTime %s", ns, bsName, *bs.Spec.MaxReservedTime)
+	if bs.Spec.MaxReservedTime == nil && (bs.Spec.MaxBackups == nil || *bs.Spec.MaxBackups <= 0) && bs.Spec.MaxReservedSpace == nil {
+		// TODO: When the backup schedule gc policy is not set, we should set a default backup gc policy.
+		klog.Warningf("backup schedule %s/%s does not set backup gc policy", ns, bsName)
 		return
 	}
 
 	backupsList, err := bm.getBackupList(bs)
 	if err != nil {
-		klog.Errorf("backupGCByMaxReservedTime, err: %s", err)
+		klog.Errorf("backupGC failed, err: %s", err)
 		return
 	}
+	sort.Sort(byCreateTimeDesc(backupsList))
 
-	var deleteCount int
+	// if several gc policies are set at the same time, a backup is deleted if any of them requires.
+	expired := map[string]struct{}{}
+	if bs.Spec.MaxReservedTime != nil {
+		for _, backup := range bm.expiredByMaxReservedTime(bs, backupsList) {
+			expired[backup.GetName()] = struct{}{}
+		}
+	}
+	if bs.Spec.MaxBackups != nil && *bs.Spec.MaxBackups > 0 {
+		for _, backup := range expiredByMaxBackups(bs, backupsList) {
+			expired[backup.GetName()] = struct{}{}
+		}
+	}
+	if bs.Spec.MaxReservedSpace != nil {
+		for _, backup := range expiredByMaxReservedSpace(bs, backupsList) {
+			expired[backup.GetName()] = struct{}{}
+		}
+	}
+	// never delete the most recent successful backup
 	for _, backup := range backupsList {
-		if backup.CreationTimestamp.Add(reservedTime).After(bm.now()) {
+		if v1alpha1.IsBackupComplete(backup) {
+			delete(expired, backup.GetName())
+			break
+		}
+	}
+
+	var deleteCount int32
+	var deleteBytes int64
+	defer func() {
+		if deleteCount > 0 {
+			bs.Status.LastGCDeletedBackups = deleteCount
+			bs.Status.LastGCDeletedBytes = deleteBytes
+		}
+	}()
+	// delete the oldest backups first
+	for i := len(backupsList) - 1; i >= 0; i-- {
+		backup := backupsList[i]
+		if _, ok := expired[backup.GetName()]; !ok {
 			continue
 		}
-		// delete the expired backup
 		if err := bm.deps.BackupControl.DeleteBackup(backup); err != nil {
 			klog.Errorf("backup schedule %s/%s gc backup %s failed, err %v", ns, bsName, backup.GetName(), err)
 			return
 		}
 		deleteCount += 1
+		deleteBytes += backupSize(backup)
 		klog.Infof("backup schedule %s/%s gc backup %s success", ns, bsName, backup.GetName())
 	}
 
-	if deleteCount == len(backupsList) {
+	if int(deleteCount) == len(backupsList) {
 		// All backups have been deleted, so the last backup information in the backupSchedule should be reset
 		bm.resetLastBackup(bs)
 	}
 }
 
-func (bm *backupScheduleManager) backupGCByMaxBackups(bs *v1alpha1.BackupSchedule) {
+// expiredByMaxReservedTime returns the backups created before MaxReservedTime
+func (bm *backupScheduleManager) expiredByMaxReservedTime(bs *v1alpha1.BackupSchedule, backupsList []*v1alpha1.Backup) []*v1alpha1.Backup {
 	ns := bs.GetNamespace()
 	bsName := bs.GetName()
 
-	backupsList, err := bm.getBackupList(bs)
+	reservedTime, err := time.ParseDuration(*bs.Spec.MaxReservedTime)
 	if err != nil {
-		klog.Errorf("backupGCByMaxBackups failed, err: %s", err)
-		return
+		klog.Errorf("backup schedule %s/%s, invalid MaxReservedTime %s", ns, bsName, *bs.Spec.MaxReservedTime)
+		return nil
 	}
 
-	sort.Sort(byCreateTimeDesc(backupsList))
+	var expired []*v1alpha1.Backup
+	for _, backup := range backupsList {
+		if backup.CreationTimestamp.Add(reservedTime).After(bm.now()) {
+			continue
+		}
+		expired = append(expired, backup)
+	}
+	return expired
+}
 
-	var deleteCount int
-	for i, backup := range backupsList {
-		if i < int(*bs.Spec.MaxBackups) {
+// expiredByMaxBackups returns the backups exceeding MaxBackups,
+// backupsList should be sorted by the creation time in descending order
+func expiredByMaxBackups(bs *v1alpha1.BackupSchedule, backupsList []*v1alpha1.Backup) []*v1alpha1.Backup {
+	if len(backupsList) <= int(*bs.Spec.MaxBackups) {
+		return nil
+	}
+	return backupsList[*bs.Spec.MaxBackups:]
+}
+
+// expiredByMaxReservedSpace returns the oldest completed backups whose total size with the newer
+// completed backups exceeds MaxReservedSpace, backupsList should be sorted by the creation time
+// in descending order
+func expiredByMaxReservedSpace(bs *v1alpha1.BackupSchedule, backupsList []*v1alpha1.Backup) []*v1alpha1.Backup {
+	maxSpace := bs.Spec.MaxReservedSpace.Value()
+
+	var expired []*v1alpha1.Backup
+	var total int64
+	for _, backup := range backupsList {
+		if !v1alpha1.IsBackupComplete(backup) {
 			continue
 		}
-		// delete the backup
-		if err := bm.deps.BackupControl.DeleteBackup(backup); err != nil {
-			klog.Errorf("backup schedule %s/%s gc backup %s failed, err %v", ns, bsName, backup.GetName(), err)
-			return
+		total += backupSize(backup)
+		if total > maxSpace {
+			expired = append(expired, backup)
 		}
-		deleteCount += 1
-		klog.Infof("backup schedule %s/%s gc backup %s success", ns, bsName, backup.GetName())
 	}
+	return expired
+}
 
-	if deleteCount == len(backupsList) {
-		// All backups have been deleted, so the last backup information in the backupSchedule should be reset
-		bm.resetLastBackup(bs)
+// backupSize returns the size of the backup in bytes, it's parsed from BackupSizeReadable
+// if BackupSize is not set
+func backupSize(backup *v1alpha1.Backup) int64 {
+	if backup.Status.BackupSize > 0 {
+		return backup.Status.BackupSize
+	}
+	if backup.Status.BackupSizeReadable != "" {
+		size, err := humanize.ParseBytes(backup.Status.BackupSizeReadable)
+		if err != nil {
+			klog.Warningf("backup %s/%s, invalid BackupSizeReadable %s", backup.Namespace, backup.Name, backup.Status.BackupSizeReadable)
+			return 0
+		}
+		return int64(size)
 	}
+	return 0
 }
 
 func (bm *backupScheduleManager) resetLastBackup(bs *v1alpha1.BackupSchedule) {
@@ -16,6 +16,7 @@ package backupschedule
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -26,8 +27,10 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/backup/constants"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 )
 
@@ -125,6 +128,218 @@ func TestManager(t *testing.T) {
 	helper.checkBacklist(bs.Namespace, 1)
 }
 
+func TestBackupGCByMaxReservedSpace(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.close()
+	m := NewBackupScheduleManager(helper.deps).(*backupScheduleManager)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	complete := []v1alpha1.BackupCondition{{Type: v1alpha1.BackupComplete, Status: v1.ConditionTrue}}
+	failed := []v1alpha1.BackupCondition{{Type: v1alpha1.BackupFailed, Status: v1.ConditionTrue}}
+	// the backups from the newest to the oldest
+	createBackups := func(bs *v1alpha1.BackupSchedule) {
+		backups := []struct {
+			conditions   []v1alpha1.BackupCondition
+			size         int64
+			sizeReadable string
+		}{
+			{conditions: complete, size: 600 * 1024 * 1024},
+			// running
+			{},
+			{conditions: complete, sizeReadable: "500 MB"},
+			{conditions: failed},
+			{conditions: complete, size: 400 * 1024 * 1024},
+			{conditions: complete, size: 100 * 1024 * 1024},
+		}
+		for i, b := range backups {
+			bk := &v1alpha1.Backup{}
+			bk.Namespace = bs.Namespace
+			bk.Name = fmt.Sprintf("%s-%d", bs.Name, i)
+			bk.Labels = label.NewBackupSchedule().Instance(bs.Name).BackupSchedule(bs.Name)
+			bk.CreationTimestamp = metav1.Time{Time: now.Add(-time.Duration(i) * time.Hour)}
+			bk.Status.Conditions = b.conditions
+			bk.Status.BackupSize = b.size
+			bk.Status.BackupSizeReadable = b.sizeReadable
+			helper.createBackup(bk)
+		}
+	}
+
+	type testcase struct {
+		name             string
+		maxReservedSpace string
+		maxBackups       *int32
+		maxReservedTime  *string
+		expectBackups    []string
+		expectDeleted    int32
+		expectBytes      int64
+	}
+	tests := []testcase{
+		{
+			name:             "the oldest completed backups exceeding the space are deleted",
+			maxReservedSpace: "1Gi",
+			expectBackups:    []string{"bs-0", "bs-1", "bs-3"},
+			expectDeleted:    3,
+			expectBytes:      500*1000*1000 + 400*1024*1024 + 100*1024*1024,
+		},
+		{
+			name:             "no backup exceeds the space",
+			maxReservedSpace: "2Gi",
+			expectBackups:    []string{"bs-0", "bs-1", "bs-2", "bs-3", "bs-4", "bs-5"},
+		},
+		{
+			name:             "the most aggressive policy wins",
+			maxReservedSpace: "2Gi",
+			maxBackups:       pointer.Int32Ptr(4),
+			maxReservedTime:  pointer.StringPtr("150m"),
+			expectBackups:    []string{"bs-0", "bs-1", "bs-2"},
+			expectDeleted:    3,
+			expectBytes:      400*1024*1024 + 100*1024*1024,
+		},
+		{
+			name:             "the most recent successful backup is never deleted",
+			maxReservedSpace: "100Mi",
+			maxBackups:       pointer.Int32Ptr(1),
+			expectBackups:    []string{"bs-0"},
+			expectDeleted:    5,
+			expectBytes:      500*1000*1000 + 400*1024*1024 + 100*1024*1024,
+		},
+	}
+
+	for i, test := range tests {
+		t.Log(test.name)
+		bs := &v1alpha1.BackupSchedule{}
+		bs.Namespace = fmt.Sprintf("ns-%d", i)
+		bs.Name = "bs"
+		q := resource.MustParse(test.maxReservedSpace)
+		bs.Spec.MaxReservedSpace = &q
+		bs.Spec.MaxBackups = test.maxBackups
+		bs.Spec.MaxReservedTime = test.maxReservedTime
+		createBackups(bs)
+
+		m.backupGC(bs)
+		bks := helper.checkBacklist(bs.Namespace, len(test.expectBackups))
+		var names []string
+		for _, bk := range bks.Items {
+			names = append(names, bk.Name)
+		}
+		g.Expect(names).To(ConsistOf(test.expectBackups))
+		g.Expect(bs.Status.LastGCDeletedBackups).To(Equal(test.expectDeleted))
+		g.Expect(bs.Status.LastGCDeletedBytes).To(Equal(test.expectBytes))
+	}
+}
+
+func TestConcurrencyPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.close()
+	m := NewBackupScheduleManager(helper.deps).(*backupScheduleManager)
+	recorder := helper.deps.Recorder.(*record.FakeRecorder)
+
+	t0 := time.Now().Truncate(time.Hour)
+	tick := func(hours int) time.Time { return t0.Add(time.Duration(hours) * time.Hour) }
+	hasEvent := func(reason string) bool {
+		for {
+			select {
+			case event := <-recorder.Events:
+				if strings.Contains(event, reason) {
+					return true
+				}
+			default:
+				return false
+			}
+		}
+	}
+
+	type testcase struct {
+		name   string
+		policy v1alpha1.BackupScheduleConcurrencyPolicy
+		// expectFn checks the results of the sync at the second tick while the first backup is still running
+		expectFn func(bs *v1alpha1.BackupSchedule, err error)
+	}
+	tests := []testcase{
+		{
+			name:   "not set, wait for the running backup",
+			policy: "",
+			expectFn: func(bs *v1alpha1.BackupSchedule, err error) {
+				g.Expect(err).Should(BeAssignableToTypeOf(&controller.RequeueError{}))
+				helper.checkBacklist(bs.Namespace, 1)
+				g.Expect(bs.Status.LastBackupTime.Time).To(Equal(tick(1)))
+			},
+		},
+		{
+			name:   "allow",
+			policy: v1alpha1.BackupScheduleConcurrencyAllow,
+			expectFn: func(bs *v1alpha1.BackupSchedule, err error) {
+				g.Expect(err).Should(BeNil())
+				helper.checkBacklist(bs.Namespace, 2)
+				g.Expect(bs.Status.LastBackupTime.Time).To(Equal(tick(2)))
+			},
+		},
+		{
+			name:   "replace",
+			policy: v1alpha1.BackupScheduleConcurrencyReplace,
+			expectFn: func(bs *v1alpha1.BackupSchedule, err error) {
+				g.Expect(err).Should(BeNil())
+				bks := helper.checkBacklist(bs.Namespace, 1)
+				g.Expect(bks.Items[0].Name).To(Equal(bs.GetBackupCRDName(tick(2))))
+				g.Expect(bs.Status.LastBackup).To(Equal(bs.GetBackupCRDName(tick(2))))
+				g.Expect(hasEvent("BackupReplaced")).To(BeTrue())
+			},
+		},
+		{
+			name:   "forbid",
+			policy: v1alpha1.BackupScheduleConcurrencyForbid,
+			expectFn: func(bs *v1alpha1.BackupSchedule, err error) {
+				g.Expect(err).Should(BeNil())
+				helper.checkBacklist(bs.Namespace, 1)
+				g.Expect(bs.Status.LastBackupTime.Time).To(Equal(tick(1)))
+				g.Expect(bs.Status.LastSkippedTime.Time).To(Equal(tick(2)))
+				g.Expect(hasEvent("BackupSkipped")).To(BeTrue())
+
+				// the skipped backup is not performed after the running backup completes
+				bk, err := helper.deps.BackupLister.Backups(bs.Namespace).Get(bs.Status.LastBackup)
+				g.Expect(err).Should(BeNil())
+				bk = bk.DeepCopy()
+				v1alpha1.UpdateBackupCondition(&bk.Status, &v1alpha1.BackupCondition{
+					Type:   v1alpha1.BackupComplete,
+					Status: v1.ConditionTrue,
+				})
+				helper.updateBackup(bk)
+				m.now = func() time.Time { return tick(2).Add(30 * time.Minute) }
+				g.Expect(m.Sync(bs)).Should(BeNil())
+				helper.checkBacklist(bs.Namespace, 1)
+
+				// the next tick is not drifted
+				m.now = func() time.Time { return tick(3).Add(time.Minute) }
+				g.Expect(m.Sync(bs)).Should(BeNil())
+				helper.checkBacklist(bs.Namespace, 2)
+				g.Expect(bs.Status.LastBackupTime.Time).To(Equal(tick(3)))
+			},
+		},
+	}
+
+	for i, test := range tests {
+		t.Log(test.name)
+		bs := &v1alpha1.BackupSchedule{}
+		bs.Namespace = fmt.Sprintf("ns-%d", i)
+		bs.Name = "bs"
+		bs.CreationTimestamp = metav1.Time{Time: t0}
+		bs.Spec.Schedule = "0 * * * *"
+		bs.Spec.ConcurrencyPolicy = test.policy
+
+		// the first backup is created and keeps running
+		m.now = func() time.Time { return tick(1).Add(time.Minute) }
+		g.Expect(m.Sync(bs)).Should(BeNil())
+		helper.checkBacklist(bs.Namespace, 1)
+
+		m.now = func() time.Time { return tick(2).Add(time.Minute) }
+		err := m.Sync(bs)
+		test.expectFn(bs, err)
+	}
+}
+
 func TestGetLastScheduledTime(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -46,6 +46,9 @@ func NewRestoreManager(deps *controller.Dependencies) backup.RestoreManager {
 }
 
 func (rm *restoreManager) Sync(restore *v1alpha1.Restore) error {
+	if v1alpha1.IsRestoreVerifying(restore) {
+		return rm.syncVerifyJob(restore)
+	}
 	return rm.syncRestoreJob(restore)
 }
 
@@ -80,8 +83,24 @@ func (rm *restoreManager) syncRestoreJob(restore *v1alpha1.Restore) error {
 			return err
 		}
 
-		tikvImage := tc.TiKVImage()
+		var pdTC *v1alpha1.TidbCluster
+		pdTC, err = backuputil.GetPDTidbCluster(rm.deps.TiDBClusterLister, tc)
+		if err != nil {
+			reason := fmt.Sprintf("failed to fetch tidbcluster referenced by tidbcluster %s/%s", restoreNamespace, restore.Spec.BR.Cluster)
+			rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+				Type:    v1alpha1.RestoreRetryFailed,
+				Status:  corev1.ConditionTrue,
+				Reason:  reason,
+				Message: err.Error(),
+			}, nil)
+			return err
+		}
+
+		tikvImage := pdTC.TiKVImage()
 		err = backuputil.ValidateRestore(restore, tikvImage)
+		if err == nil {
+			err = backuputil.ValidateBRTarget(tc, "restore", restore.Spec.Type, restore.Spec.TableFilter)
+		}
 	}
 
 	if err != nil {
@@ -287,6 +306,8 @@ func (rm *restoreManager) makeImportJob(restore *v1alpha1.Restore) (*batchv1.Job
 		},
 	}
 
+	backuputil.MergePodTemplate(podSpec, restore.Spec.PodTemplate)
+
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        restore.GetRestoreJobName(),
@@ -317,6 +338,10 @@ func (rm *restoreManager) makeRestoreJob(restore *v1alpha1.Restore) (*batchv1.Jo
 	if err != nil {
 		return nil, fmt.Sprintf("failed to fetch tidbcluster %s/%s", restoreNamespace, restore.Spec.BR.Cluster), err
 	}
+	pdTC, err := backuputil.GetPDTidbCluster(rm.deps.TiDBClusterLister, tc)
+	if err != nil {
+		return nil, fmt.Sprintf("failed to fetch tidbcluster referenced by tidbcluster %s/%s", restoreNamespace, restore.Spec.BR.Cluster), err
+	}
 
 	var (
 		envVars []corev1.EnvVar
@@ -347,11 +372,12 @@ func (rm *restoreManager) makeRestoreJob(restore *v1alpha1.Restore) (*batchv1.Jo
 		fmt.Sprintf("--namespace=%s", ns),
 		fmt.Sprintf("--restoreName=%s", name),
 	}
-	tikvImage := tc.TiKVImage()
+	tikvImage := pdTC.TiKVImage()
 	_, tikvVersion := backuputil.ParseImage(tikvImage)
 	if tikvVersion != "" {
 		args = append(args, fmt.Sprintf("--tikvVersion=%s", tikvVersion))
 	}
+	args = append(args, fmt.Sprintf("--pdAddress=%s", backuputil.PDAddress(pdTC)))
 
 	jobLabels := util.CombineStringMap(label.NewRestore().Instance(restore.GetInstanceName()).RestoreJob().Restore(name), restore.Labels)
 	podLabels := jobLabels
@@ -414,8 +440,9 @@ func (rm *restoreManager) makeRestoreJob(restore *v1alpha1.Restore) (*batchv1.Jo
 
 	// mount volumes if specified
 	if restore.Spec.Local != nil {
-		volumes = append(volumes, restore.Spec.Local.Volume)
-		volumeMounts = append(volumeMounts, restore.Spec.Local.VolumeMount)
+		localVolume, localVolumeMount := restore.Spec.Local.GetVolumeAndMount()
+		volumes = append(volumes, localVolume)
+		volumeMounts = append(volumeMounts, localVolumeMount)
 	}
 
 	serviceAccount := constants.DefaultServiceAccountName
@@ -472,6 +499,8 @@ func (rm *restoreManager) makeRestoreJob(restore *v1alpha1.Restore) (*batchv1.Jo
 		},
 	}
 
+	backuputil.MergePodTemplate(podSpec, restore.Spec.PodTemplate)
+
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        restore.GetRestoreJobName(),
@@ -491,6 +520,178 @@ func (rm *restoreManager) makeRestoreJob(restore *v1alpha1.Restore) (*batchv1.Jo
 	return job, "", nil
 }
 
+// syncVerifyJob creates the job verifying the restored data after the restore job succeeds,
+// the job updates the verification status and marks the restore Complete or Failed.
+func (rm *restoreManager) syncVerifyJob(restore *v1alpha1.Restore) error {
+	ns := restore.GetNamespace()
+	name := restore.GetName()
+	verifyJobName := restore.GetVerifyJobName()
+
+	job, err := rm.deps.JobLister.Jobs(ns).Get(verifyJobName)
+	if err == nil {
+		for _, c := range job.Status.Conditions {
+			if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+				return rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+					Type:    v1alpha1.RestoreFailed,
+					Status:  corev1.ConditionTrue,
+					Reason:  "VerifyJobFailed",
+					Message: fmt.Sprintf("verify job %s has failed: %s", verifyJobName, c.Message),
+				}, nil)
+			}
+		}
+		// the verify job is running, return directly
+		return nil
+	}
+
+	if !errors.IsNotFound(err) {
+		return fmt.Errorf("restore %s/%s get job %s failed, err: %v", ns, name, verifyJobName, err)
+	}
+
+	job, reason, err := rm.makeVerifyJob(restore)
+	if err != nil {
+		rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreRetryFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  reason,
+			Message: err.Error(),
+		}, nil)
+		return err
+	}
+
+	if err := rm.deps.JobControl.CreateJob(restore, job); err != nil {
+		errMsg := fmt.Errorf("create restore %s/%s verify job %s failed, err: %v", ns, name, verifyJobName, err)
+		rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreRetryFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "CreateVerifyJobFailed",
+			Message: errMsg.Error(),
+		}, nil)
+		return errMsg
+	}
+	return nil
+}
+
+func (rm *restoreManager) makeVerifyJob(restore *v1alpha1.Restore) (*batchv1.Job, string, error) {
+	ns := restore.GetNamespace()
+	name := restore.GetName()
+	restoreNamespace := ns
+	if restore.Spec.BR.ClusterNamespace != "" {
+		restoreNamespace = restore.Spec.BR.ClusterNamespace
+	}
+	tc, err := rm.deps.TiDBClusterLister.TidbClusters(restoreNamespace).Get(restore.Spec.BR.Cluster)
+	if err != nil {
+		return nil, fmt.Sprintf("failed to fetch tidbcluster %s/%s", restoreNamespace, restore.Spec.BR.Cluster), err
+	}
+
+	envVars, reason, err := backuputil.GenerateTidbPasswordEnv(ns, name, restore.Spec.To.SecretName, restore.Spec.UseKMS, rm.deps.KubeClientset)
+	if err != nil {
+		return nil, reason, err
+	}
+
+	// the verify job reads the checksums of the tables from the backup meta
+	storageEnv, reason, err := backuputil.GenerateStorageCertEnv(ns, restore.Spec.UseKMS, restore.Spec.StorageProvider, rm.deps.KubeClientset)
+	if err != nil {
+		return nil, reason, fmt.Errorf("restore %s/%s, %v", ns, name, err)
+	}
+	envVars = append(envVars, storageEnv...)
+	// set env vars specified in restore.Spec.Env
+	envVars = util.AppendOverwriteEnv(envVars, restore.Spec.Env)
+
+	args := []string{
+		"verify",
+		fmt.Sprintf("--namespace=%s", ns),
+		fmt.Sprintf("--restoreName=%s", name),
+	}
+
+	volumeMounts := []corev1.VolumeMount{}
+	volumes := []corev1.Volume{}
+	if tc.Spec.TiDB != nil && tc.Spec.TiDB.TLSClient != nil && tc.Spec.TiDB.TLSClient.Enabled && !tc.SkipTLSWhenConnectTiDB() {
+		args = append(args, "--client-tls=true")
+		clientSecretName := util.TiDBClientTLSSecretName(restore.Spec.BR.Cluster)
+		if restore.Spec.To.TLSClientSecretName != nil {
+			clientSecretName = *restore.Spec.To.TLSClientSecretName
+		}
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "tidb-client-tls",
+			ReadOnly:  true,
+			MountPath: util.TiDBClientTLSPath,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "tidb-client-tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: clientSecretName,
+				},
+			},
+		})
+	}
+
+	// mount volumes if specified
+	if restore.Spec.Local != nil {
+		localVolume, localVolumeMount := restore.Spec.Local.GetVolumeAndMount()
+		volumes = append(volumes, localVolume)
+		volumeMounts = append(volumeMounts, localVolumeMount)
+	}
+
+	jobLabels := util.CombineStringMap(label.NewRestore().Instance(restore.GetInstanceName()).RestoreJob().Restore(name), restore.Labels)
+	podLabels := jobLabels
+	jobAnnotations := restore.Annotations
+	podAnnotations := jobAnnotations
+
+	serviceAccount := constants.DefaultServiceAccountName
+	if restore.Spec.ServiceAccount != "" {
+		serviceAccount = restore.Spec.ServiceAccount
+	}
+
+	podSpec := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      podLabels,
+			Annotations: podAnnotations,
+		},
+		Spec: corev1.PodSpec{
+			SecurityContext:    restore.Spec.PodSecurityContext,
+			ServiceAccountName: serviceAccount,
+			Containers: []corev1.Container{
+				{
+					Name:            label.RestoreJobLabelVal,
+					Image:           rm.deps.CLIConfig.TiDBBackupManagerImage,
+					Args:            args,
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					VolumeMounts:    volumeMounts,
+					Env:             util.AppendEnvIfPresent(envVars, "TZ"),
+					Resources:       restore.Spec.ResourceRequirements,
+				},
+			},
+			RestartPolicy:     corev1.RestartPolicyNever,
+			Tolerations:       restore.Spec.Tolerations,
+			ImagePullSecrets:  restore.Spec.ImagePullSecrets,
+			Affinity:          restore.Spec.Affinity,
+			Volumes:           volumes,
+			PriorityClassName: restore.Spec.PriorityClassName,
+		},
+	}
+
+	backuputil.MergePodTemplate(podSpec, restore.Spec.PodTemplate)
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        restore.GetVerifyJobName(),
+			Namespace:   ns,
+			Labels:      jobLabels,
+			Annotations: jobAnnotations,
+			OwnerReferences: []metav1.OwnerReference{
+				controller.GetRestoreOwnerRef(restore),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: pointer.Int32Ptr(0),
+			Template:     *podSpec,
+		},
+	}
+
+	return job, "", nil
+}
+
 func (rm *restoreManager) ensureRestorePVCExist(restore *v1alpha1.Restore) (string, error) {
 	ns := restore.GetNamespace()
 	name := restore.GetName()
@@ -23,7 +23,10 @@ import (
 	"github.com/onsi/gomega"
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup/constants"
 	"github.com/pingcap/tidb-operator/pkg/backup/testutils"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
@@ -232,3 +235,78 @@ func TestBRRestore(t *testing.T) {
 		g.Expect(job.Spec.Template.Spec.Containers[0].Env).NotTo(gomega.ContainElement(env2No))
 	}
 }
+
+func TestBRRestoreVerify(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.Close()
+	deps := helper.Deps
+
+	for i, restore := range genValidBRRestores() {
+		restore.Spec.Verify = v1alpha1.RestoreVerifyChecksum
+		restore.Spec.VerifyMaxTableSize = "100Gi"
+		// the restore job has succeeded
+		restore.Status.Conditions = []v1alpha1.RestoreCondition{
+			{Type: v1alpha1.RestoreScheduled, Status: corev1.ConditionTrue},
+			{Type: v1alpha1.RestoreRunning, Status: corev1.ConditionTrue},
+			{Type: v1alpha1.RestoreVerifying, Status: corev1.ConditionTrue},
+		}
+		helper.createRestore(restore)
+		helper.CreateSecret(restore)
+		helper.CreateTC(restore.Spec.BR.ClusterNamespace, restore.Spec.BR.Cluster)
+
+		m := NewRestoreManager(deps)
+		err := m.Sync(restore)
+		g.Expect(err).Should(BeNil())
+		// the restore job is not created again
+		_, err = deps.KubeClientset.BatchV1().Jobs(restore.Namespace).Get(context.TODO(), restore.GetRestoreJobName(), metav1.GetOptions{})
+		g.Expect(err).ShouldNot(BeNil())
+		job, err := deps.KubeClientset.BatchV1().Jobs(restore.Namespace).Get(context.TODO(), restore.GetVerifyJobName(), metav1.GetOptions{})
+		g.Expect(err).Should(BeNil())
+
+		container := job.Spec.Template.Spec.Containers[0]
+		g.Expect(container.Args).To(Equal([]string{
+			"verify",
+			fmt.Sprintf("--namespace=%s", restore.Namespace),
+			fmt.Sprintf("--restoreName=%s", restore.Name),
+			"--client-tls=true",
+		}))
+		// the verify job connects to tidb with the password in the secret of spec.to
+		g.Expect(container.Env).To(ContainElement(corev1.EnvVar{
+			Name: "BACKUP_MANAGER_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: restore.Spec.To.SecretName},
+					Key:                  constants.TidbPasswordKey,
+				},
+			},
+		}))
+		g.Expect(container.Env).To(ContainElement(corev1.EnvVar{
+			Name:  fmt.Sprintf("env_name_%d", i),
+			Value: fmt.Sprintf("env_value_%d", i),
+		}))
+		g.Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: "tidb-client-tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: util.TiDBClientTLSSecretName(restore.Spec.BR.Cluster),
+				},
+			},
+		}))
+		g.Expect(job.Spec.Template.Spec.InitContainers).To(BeEmpty())
+
+		// the restore fails if the verify job fails
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"},
+		}
+		_, err = deps.KubeClientset.BatchV1().Jobs(restore.Namespace).UpdateStatus(context.TODO(), job, metav1.UpdateOptions{})
+		g.Expect(err).Should(BeNil())
+		g.Eventually(func() bool {
+			job, err := deps.JobLister.Jobs(restore.Namespace).Get(restore.GetVerifyJobName())
+			return err == nil && len(job.Status.Conditions) > 0
+		}, time.Second*10).Should(BeTrue())
+		err = m.Sync(restore)
+		g.Expect(err).Should(BeNil())
+		helper.hasCondition(restore.Namespace, restore.Name, v1alpha1.RestoreFailed, "VerifyJobFailed")
+	}
+}
@@ -70,5 +70,11 @@ func GenValidStorageProviders() []v1alpha1.StorageProvider {
 				},
 			},
 		},
+		{
+			Local: &v1alpha1.LocalStorageProvider{
+				Prefix:                    "prefix-",
+				PersistentVolumeClaimName: "backup-pvc",
+			},
+		},
 	}
 }
@@ -22,7 +22,9 @@ import (
 	"github.com/Masterminds/semver"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog"
@@ -396,6 +398,18 @@ func ValidateBackup(backup *v1alpha1.Backup, tikvImage string) error {
 			return fmt.Errorf("table should be configured for BR with backup type table in spec of %s/%s", ns, name)
 		}
 
+		if policy := backup.Spec.BackoffRetryPolicy; policy != nil {
+			if policy.GetMaxRetryTimes() < 0 {
+				return fmt.Errorf("maxRetryTimes of backoffRetryPolicy should not be negative in spec of %s/%s", ns, name)
+			}
+			if _, err := policy.GetMinRetryDuration(); err != nil {
+				return fmt.Errorf("invalid minRetryDuration %s of backoffRetryPolicy in spec of %s/%s, %v", policy.MinRetryDuration, ns, name, err)
+			}
+			if _, err := policy.GetRetryTimeout(); err != nil {
+				return fmt.Errorf("invalid retryTimeout %s of backoffRetryPolicy in spec of %s/%s, %v", policy.RetryTimeout, ns, name, err)
+			}
+		}
+
 		// validate storage providers
 		if backup.Spec.S3 != nil {
 			if err := validateS3(ns, name, backup.Spec.S3); err != nil {
@@ -414,6 +428,48 @@ func ValidateBackup(backup *v1alpha1.Backup, tikvImage string) error {
 	return nil
 }
 
+// GetPDTidbCluster returns the TidbCluster whose PD serves tc. A heterogeneous TidbCluster without PD,
+// e.g. a TiFlash-only cluster, is served by the PD of the TidbCluster referenced by its spec.cluster.
+func GetPDTidbCluster(tcLister listers.TidbClusterLister, tc *v1alpha1.TidbCluster) (*v1alpha1.TidbCluster, error) {
+	if !isHeterogeneousWithoutPD(tc) {
+		return tc, nil
+	}
+	ns := tc.Spec.Cluster.Namespace
+	if ns == "" {
+		ns = tc.Namespace
+	}
+	return tcLister.TidbClusters(ns).Get(tc.Spec.Cluster.Name)
+}
+
+// PDAddress returns the address of the PD service of tc
+func PDAddress(tc *v1alpha1.TidbCluster) string {
+	return fmt.Sprintf("%s-pd.%s:2379", tc.Name, tc.Namespace)
+}
+
+// ValidateBRTarget validates the type of a Backup or Restore by BR targeting tc. The data of a heterogeneous
+// TidbCluster without PD is in the TiKV of the referenced cluster, so a full backup or restore without
+// table filter, which covers the whole referenced cluster, and a raw backup or restore are rejected.
+func ValidateBRTarget(tc *v1alpha1.TidbCluster, kind string, tp v1alpha1.BackupType, tableFilter []string) error {
+	if !isHeterogeneousWithoutPD(tc) {
+		return nil
+	}
+	switch tp {
+	case "", v1alpha1.BackupTypeFull:
+		if len(tableFilter) == 0 {
+			return fmt.Errorf("tidbcluster %s/%s has no PD and refers to tidbcluster %s, a full %s without tableFilter covers the whole referenced cluster, please target the referenced cluster or specify the tables",
+				tc.Namespace, tc.Name, tc.Spec.Cluster.Name, kind)
+		}
+	case v1alpha1.BackupTypeRaw:
+		return fmt.Errorf("tidbcluster %s/%s has no PD and refers to tidbcluster %s, raw %s is not supported",
+			tc.Namespace, tc.Name, tc.Spec.Cluster.Name, kind)
+	}
+	return nil
+}
+
+func isHeterogeneousWithoutPD(tc *v1alpha1.TidbCluster) bool {
+	return tc.Spec.PD == nil && tc.Spec.Cluster != nil && tc.Spec.Cluster.Name != ""
+}
+
 // ValidateRestore checks whether a restore spec is valid.
 func ValidateRestore(restore *v1alpha1.Restore, tikvImage string) error {
 	ns := restore.Namespace
@@ -466,6 +522,35 @@ func ValidateRestore(restore *v1alpha1.Restore, tikvImage string) error {
 			}
 		}
 	}
+
+	if restore.Spec.Verify != "" {
+		if err := validateRestoreVerify(restore); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRestoreVerify checks whether the verification of the restored data is valid
+func validateRestoreVerify(restore *v1alpha1.Restore) error {
+	ns := restore.Namespace
+	name := restore.Name
+
+	if restore.Spec.Verify != v1alpha1.RestoreVerifyChecksum {
+		return fmt.Errorf("invalid verify type %s in spec of %s/%s", restore.Spec.Verify, ns, name)
+	}
+	if restore.Spec.BR == nil {
+		return fmt.Errorf("verify is only supported by BR restore in spec of %s/%s", ns, name)
+	}
+	// the verify job connects to tidb to checksum the restored tables
+	if reason := validateAccessConfig(restore.Spec.To); reason != "" {
+		return fmt.Errorf(reason, ns, name)
+	}
+	if restore.Spec.VerifyMaxTableSize != "" {
+		if _, err := resource.ParseQuantity(restore.Spec.VerifyMaxTableSize); err != nil {
+			return fmt.Errorf("invalid verifyMaxTableSize %s in spec of %s/%s: %v", restore.Spec.VerifyMaxTableSize, ns, name, err)
+		}
+	}
 	return nil
 }
 
@@ -503,6 +588,10 @@ func validateGcs(ns, name string, gcs *v1alpha1.GcsStorageProvider) error {
 
 func validateLocal(ns, name string, local *v1alpha1.LocalStorageProvider) error {
 	configuredForBR := fmt.Sprintf("configured for BR in spec of %s/%s", ns, name)
+	if local.PersistentVolumeClaimName != "" {
+		// the volume and the mount are generated from the PVC
+		return nil
+	}
 	if local.VolumeMount.Name != local.Volume.Name {
 		return fmt.Errorf("Spec.Local.Volume.Name != Spec.Local.VolumeMount.Name is %s", configuredForBR)
 	}
@@ -541,3 +630,77 @@ func canSkipSetGCLifeTime(image string) bool {
 	}
 	return true
 }
+
+// MergePodTemplate merges the pod template customized by users into the Pod template of a backup
+// or restore Job. The fields required by the operator take precedence on conflict:
+// - the labels and annotations of the operator are not overridden
+// - the affinity and the priority class name of the template override `spec.affinity` and `spec.priorityClassName`
+// - the tolerations of the template are appended to `spec.tolerations`
+// - the additional containers and volumes are appended, except the ones whose names are used by the operator
+// The service account and the containers of the operator, including their args and env, are never changed.
+func MergePodTemplate(podTemplate *corev1.PodTemplateSpec, custom *v1alpha1.BackupPodTemplate) {
+	if custom == nil {
+		return
+	}
+
+	podTemplate.Labels = mergeStringMap(podTemplate.Labels, custom.Labels)
+	podTemplate.Annotations = mergeStringMap(podTemplate.Annotations, custom.Annotations)
+
+	spec := &podTemplate.Spec
+	if custom.Affinity != nil {
+		spec.Affinity = custom.Affinity.DeepCopy()
+	}
+	if custom.PriorityClassName != "" {
+		spec.PriorityClassName = custom.PriorityClassName
+	}
+	if len(custom.Tolerations) > 0 {
+		tolerations := make([]corev1.Toleration, 0, len(spec.Tolerations)+len(custom.Tolerations))
+		tolerations = append(tolerations, spec.Tolerations...)
+		spec.Tolerations = append(tolerations, custom.Tolerations...)
+	}
+	spec.NodeSelector = mergeStringMap(spec.NodeSelector, custom.NodeSelector)
+
+	containerNames := map[string]struct{}{}
+	for _, c := range spec.InitContainers {
+		containerNames[c.Name] = struct{}{}
+	}
+	for _, c := range spec.Containers {
+		containerNames[c.Name] = struct{}{}
+	}
+	for _, c := range custom.AdditionalContainers {
+		if _, ok := containerNames[c.Name]; ok {
+			klog.Warningf("additional container %s conflicts with the container of the job, ignore it", c.Name)
+			continue
+		}
+		containerNames[c.Name] = struct{}{}
+		spec.Containers = append(spec.Containers, *c.DeepCopy())
+	}
+
+	volumeNames := map[string]struct{}{}
+	for _, v := range spec.Volumes {
+		volumeNames[v.Name] = struct{}{}
+	}
+	for _, v := range custom.AdditionalVolumes {
+		if _, ok := volumeNames[v.Name]; ok {
+			klog.Warningf("additional volume %s conflicts with the volume of the job, ignore it", v.Name)
+			continue
+		}
+		volumeNames[v.Name] = struct{}{}
+		spec.Volumes = append(spec.Volumes, *v.DeepCopy())
+	}
+}
+
+// mergeStringMap returns a new map with the keys of both maps, the values of base take precedence
+func mergeStringMap(base, custom map[string]string) map[string]string {
+	if len(custom) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(custom))
+	for k, v := range custom {
+		merged[k] = v
+	}
+	for k, v := range base {
+		merged[k] = v
+	}
+	return merged
+}
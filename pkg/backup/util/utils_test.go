@@ -21,9 +21,12 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
 )
 
 func TestCheckAllKeysExistInSecret(t *testing.T) {
@@ -493,6 +496,76 @@ func TestValidateRestore(t *testing.T) {
 	match("")
 }
 
+func TestGetPDTidbCluster(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	tcLister := listers.NewTidbClusterLister(indexer)
+	primary := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-primary", Name: "primary"},
+		Spec:       v1alpha1.TidbClusterSpec{PD: &v1alpha1.PDSpec{}, TiKV: &v1alpha1.TiKVSpec{}},
+	}
+	tiflashOnly := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "tiflash-only"},
+		Spec: v1alpha1.TidbClusterSpec{
+			TiFlash: &v1alpha1.TiFlashSpec{},
+			Cluster: &v1alpha1.TidbClusterRef{Namespace: "ns-primary", Name: "primary"},
+		},
+	}
+
+	// the referenced cluster is not found
+	_, err := GetPDTidbCluster(tcLister, tiflashOnly)
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+
+	g.Expect(indexer.Add(primary)).To(Succeed())
+	pdTC, err := GetPDTidbCluster(tcLister, tiflashOnly)
+	g.Expect(err).To(Succeed())
+	g.Expect(pdTC).To(Equal(primary))
+	g.Expect(PDAddress(pdTC)).To(Equal("primary-pd.ns-primary:2379"))
+
+	// a cluster with PD is served by itself
+	pdTC, err = GetPDTidbCluster(tcLister, primary)
+	g.Expect(err).To(Succeed())
+	g.Expect(pdTC).To(Equal(primary))
+}
+
+func TestValidateBRTarget(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "tiflash-only"},
+		Spec: v1alpha1.TidbClusterSpec{
+			TiFlash: &v1alpha1.TiFlashSpec{},
+			Cluster: &v1alpha1.TidbClusterRef{Name: "primary"},
+		},
+	}
+	tests := []struct {
+		tp          v1alpha1.BackupType
+		tableFilter []string
+		expectErr   string
+	}{
+		{tp: "", expectErr: "a full backup without tableFilter covers the whole referenced cluster"},
+		{tp: v1alpha1.BackupTypeFull, expectErr: "a full backup without tableFilter covers the whole referenced cluster"},
+		{tp: v1alpha1.BackupTypeFull, tableFilter: []string{"db.*"}},
+		{tp: v1alpha1.BackupTypeDB},
+		{tp: v1alpha1.BackupTypeTable},
+		{tp: v1alpha1.BackupTypeRaw, expectErr: "raw backup is not supported"},
+	}
+	for _, test := range tests {
+		err := ValidateBRTarget(tc, "backup", test.tp, test.tableFilter)
+		if test.expectErr == "" {
+			g.Expect(err).To(Succeed())
+		} else {
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(test.expectErr))
+		}
+	}
+
+	// all the types are valid for a cluster with PD
+	tc.Spec.PD = &v1alpha1.PDSpec{}
+	g.Expect(ValidateBRTarget(tc, "backup", v1alpha1.BackupTypeFull, nil)).To(Succeed())
+}
+
 func TestGetImageTag(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -550,3 +623,102 @@ func TestGetImageTag(t *testing.T) {
 		})
 	}
 }
+
+func TestMergePodTemplate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	newPodTemplate := func() *corev1.PodTemplateSpec {
+		return &corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{"app.kubernetes.io/component": "backup"},
+				Annotations: map[string]string{"a": "operator"},
+			},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: "tidb-backup-manager",
+				InitContainers:     []corev1.Container{{Name: "br"}},
+				Containers: []corev1.Container{{
+					Name: "backup",
+					Args: []string{"backup"},
+					Env:  []corev1.EnvVar{{Name: "BR_LOG_TO_TERM", Value: "1"}},
+				}},
+				Tolerations:       []corev1.Toleration{{Key: "spec"}},
+				Affinity:          &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}},
+				PriorityClassName: "spec",
+				Volumes:           []corev1.Volume{{Name: "backup"}},
+			},
+		}
+	}
+
+	type testcase struct {
+		name     string
+		custom   *v1alpha1.BackupPodTemplate
+		expectFn func(pt *corev1.PodTemplateSpec)
+	}
+	tests := []testcase{
+		{
+			name:   "no pod template",
+			custom: nil,
+			expectFn: func(pt *corev1.PodTemplateSpec) {
+				g.Expect(pt).To(Equal(newPodTemplate()))
+			},
+		},
+		{
+			name: "labels and annotations of the operator are not overridden",
+			custom: &v1alpha1.BackupPodTemplate{
+				Labels:      map[string]string{"app.kubernetes.io/component": "custom", "team": "dba"},
+				Annotations: map[string]string{"a": "custom", "b": "custom"},
+			},
+			expectFn: func(pt *corev1.PodTemplateSpec) {
+				g.Expect(pt.Labels).To(Equal(map[string]string{"app.kubernetes.io/component": "backup", "team": "dba"}))
+				g.Expect(pt.Annotations).To(Equal(map[string]string{"a": "operator", "b": "custom"}))
+			},
+		},
+		{
+			name: "scheduling fields",
+			custom: &v1alpha1.BackupPodTemplate{
+				Affinity:          &corev1.Affinity{PodAffinity: &corev1.PodAffinity{}},
+				Tolerations:       []corev1.Toleration{{Key: "backup-pool"}},
+				NodeSelector:      map[string]string{"pool": "backup"},
+				PriorityClassName: "custom",
+			},
+			expectFn: func(pt *corev1.PodTemplateSpec) {
+				g.Expect(pt.Spec.Affinity).To(Equal(&corev1.Affinity{PodAffinity: &corev1.PodAffinity{}}))
+				g.Expect(pt.Spec.Tolerations).To(Equal([]corev1.Toleration{{Key: "spec"}, {Key: "backup-pool"}}))
+				g.Expect(pt.Spec.NodeSelector).To(Equal(map[string]string{"pool": "backup"}))
+				g.Expect(pt.Spec.PriorityClassName).To(Equal("custom"))
+				g.Expect(pt.Spec.ServiceAccountName).To(Equal("tidb-backup-manager"))
+			},
+		},
+		{
+			name: "additional containers and volumes",
+			custom: &v1alpha1.BackupPodTemplate{
+				AdditionalContainers: []corev1.Container{
+					{Name: "s3-proxy", Image: "proxy"},
+					{Name: "backup", Args: []string{"custom"}},
+					{Name: "br"},
+				},
+				AdditionalVolumes: []corev1.Volume{
+					{Name: "proxy-config"},
+					{Name: "backup", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				},
+			},
+			expectFn: func(pt *corev1.PodTemplateSpec) {
+				g.Expect(pt.Spec.InitContainers).To(Equal(newPodTemplate().Spec.InitContainers))
+				g.Expect(pt.Spec.Containers).To(HaveLen(2))
+				g.Expect(pt.Spec.Containers[0]).To(Equal(newPodTemplate().Spec.Containers[0]))
+				g.Expect(pt.Spec.Containers[1].Name).To(Equal("s3-proxy"))
+				g.Expect(pt.Spec.Volumes).To(Equal([]corev1.Volume{{Name: "backup"}, {Name: "proxy-config"}}))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Log(test.name)
+		pt := newPodTemplate()
+		labels := pt.Labels
+		MergePodTemplate(pt, test.custom)
+		test.expectFn(pt)
+		// the maps shared with the job are not changed
+		g.Expect(labels).To(Equal(newPodTemplate().Labels))
+	}
+}
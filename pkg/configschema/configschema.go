@@ -0,0 +1,234 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configschema validates the user-provided config keys of the components
+// against the config schemas of their versions.
+//
+// The schemas are generated offline and stored in assets/<component>/v<major>.<minor>.json,
+// run hack/update-config-schema.sh to embed them after adding or changing a schema.
+// A component version without a schema is not validated, so an unknown version never
+// blocks the cluster.
+package configschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
+	"k8s.io/klog"
+)
+
+// schemaFile is the format of the schema files in assets
+type schemaFile struct {
+	// Keys are the full keys of the config items, e.g. raftstore.raft-log-gc-threshold
+	Keys []string `json:"keys"`
+	// Open are the keys of the tables accepting any sub keys, e.g. server.labels
+	Open []string `json:"open"`
+	// Deprecated are the deprecated keys and their replacements, an empty replacement
+	// means the key is removed without replacement
+	Deprecated map[string]string `json:"deprecated"`
+}
+
+// Schema is the config schema of a minor version of a component
+type Schema struct {
+	keys       map[string]struct{}
+	open       map[string]struct{}
+	deprecated map[string]string
+}
+
+// Result is the result of validating a config against a Schema
+type Result struct {
+	// Unknown are the keys not in the schema
+	Unknown []string
+	// Deprecated are the deprecated keys and their replacements
+	Deprecated map[string]string
+}
+
+// Empty returns whether there is no unknown or deprecated key
+func (r *Result) Empty() bool {
+	return r == nil || (len(r.Unknown) == 0 && len(r.Deprecated) == 0)
+}
+
+var (
+	loadOnce sync.Once
+	schemas  map[string]*Schema
+)
+
+func loadSchemas() {
+	schemas = map[string]*Schema{}
+	for name, data := range assets {
+		file := &schemaFile{}
+		if err := json.Unmarshal([]byte(data), file); err != nil {
+			klog.Errorf("failed to parse config schema %s, skip it: %v", name, err)
+			continue
+		}
+		s := &Schema{
+			keys:       map[string]struct{}{},
+			open:       map[string]struct{}{},
+			deprecated: map[string]string{},
+		}
+		for _, k := range file.Keys {
+			s.keys[k] = struct{}{}
+		}
+		for _, k := range file.Open {
+			s.open[k] = struct{}{}
+		}
+		for k, v := range file.Deprecated {
+			s.deprecated[k] = v
+		}
+		schemas[strings.TrimSuffix(name, ".json")] = s
+	}
+}
+
+// Load returns the schema of the minor version of the component, and false if the
+// version can't be parsed or there is no schema for it.
+func Load(component v1alpha1.MemberType, version string) (*Schema, bool) {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return nil, false
+	}
+	loadOnce.Do(loadSchemas)
+	s, ok := schemas[fmt.Sprintf("%s/v%d.%d", component, v.Major(), v.Minor())]
+	return s, ok
+}
+
+// Validate validates the keys of the config against the schema
+func (s *Schema) Validate(cfg *config.GenericConfig) *Result {
+	result := &Result{Deprecated: map[string]string{}}
+	if cfg == nil {
+		return result
+	}
+	s.validate(cfg.Inner(), "", result)
+	sort.Strings(result.Unknown)
+	return result
+}
+
+func (s *Schema) validate(table map[string]interface{}, prefix string, result *Result) {
+	for k, v := range table {
+		key := prefix + k
+		if replacement, ok := s.deprecated[key]; ok {
+			result.Deprecated[key] = replacement
+			continue
+		}
+		if _, ok := s.keys[key]; ok {
+			continue
+		}
+		if _, ok := s.open[key]; ok {
+			continue
+		}
+		if sub, ok := toTable(v); ok {
+			s.validate(sub, key+".", result)
+			continue
+		}
+		result.Unknown = append(result.Unknown, key)
+	}
+}
+
+// toTable converts the value to a TOML table if it's a table
+func toTable(v interface{}) (map[string]interface{}, bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return t, true
+	case map[interface{}]interface{}:
+		table := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			table[fmt.Sprint(k)] = v
+		}
+		return table, true
+	}
+	return nil, false
+}
+
+// ValidateTidbCluster validates the configs of PD, TiKV and TiDB of the TidbCluster against
+// the schemas of their versions, and returns the results of the components with unknown or
+// deprecated keys. The components without a schema for their versions are skipped.
+func ValidateTidbCluster(tc *v1alpha1.TidbCluster) map[v1alpha1.MemberType]*Result {
+	results := map[v1alpha1.MemberType]*Result{}
+	validate := func(component v1alpha1.MemberType, version string, cfg *config.GenericConfig) {
+		if cfg == nil {
+			return
+		}
+		schema, ok := Load(component, version)
+		if !ok {
+			klog.V(4).Infof("no config schema for %s %s of tc %s/%s, skip validating", component, version, tc.Namespace, tc.Name)
+			return
+		}
+		if result := schema.Validate(cfg); !result.Empty() {
+			results[component] = result
+		}
+	}
+
+	if tc.Spec.PD != nil && tc.Spec.PD.Config != nil {
+		validate(v1alpha1.PDMemberType, tc.PDVersion(), tc.Spec.PD.Config.GenericConfig)
+	}
+	if tc.Spec.TiKV != nil && tc.Spec.TiKV.Config != nil {
+		validate(v1alpha1.TiKVMemberType, tc.TiKVVersion(), tc.Spec.TiKV.Config.GenericConfig)
+	}
+	if tc.Spec.TiDB != nil && tc.Spec.TiDB.Config != nil {
+		validate(v1alpha1.TiDBMemberType, tc.TiDBVersion(), tc.Spec.TiDB.Config.GenericConfig)
+	}
+	return results
+}
+
+// Warnings returns the human readable warnings of the results
+func Warnings(results map[v1alpha1.MemberType]*Result) []string {
+	var warnings []string
+	for _, component := range sortedComponents(results) {
+		result := results[component]
+		for _, key := range result.Unknown {
+			warnings = append(warnings, fmt.Sprintf("%s: unknown config key %q, it's ignored by %s", component, key, component))
+		}
+		keys := make([]string, 0, len(result.Deprecated))
+		for key := range result.Deprecated {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if replacement := result.Deprecated[key]; replacement != "" {
+				warnings = append(warnings, fmt.Sprintf("%s: config key %q is deprecated, use %q instead", component, key, replacement))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("%s: config key %q is deprecated", component, key))
+			}
+		}
+	}
+	return warnings
+}
+
+// UnknownKeysMessage returns the message describing the unknown keys of the results,
+// and an empty string if there is no unknown key.
+func UnknownKeysMessage(results map[v1alpha1.MemberType]*Result) string {
+	var msgs []string
+	for _, component := range sortedComponents(results) {
+		if unknown := results[component].Unknown; len(unknown) > 0 {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", component, strings.Join(unknown, ", ")))
+		}
+	}
+	if len(msgs) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("unknown config keys are ignored, %s", strings.Join(msgs, "; "))
+}
+
+func sortedComponents(results map[v1alpha1.MemberType]*Result) []v1alpha1.MemberType {
+	components := make([]v1alpha1.MemberType, 0, len(results))
+	for component := range results {
+		components = append(components, component)
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i] < components[j] })
+	return components
+}
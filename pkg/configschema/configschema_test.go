@@ -0,0 +1,167 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configschema
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
+)
+
+func TestLoad(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	loadOnce.Do(loadSchemas)
+	// all the assets are valid
+	g.Expect(schemas).To(HaveLen(len(assets)))
+
+	type testcase struct {
+		name      string
+		component v1alpha1.MemberType
+		version   string
+		expectOK  bool
+	}
+	tests := []testcase{
+		{name: "tikv v5.0", component: v1alpha1.TiKVMemberType, version: "v5.0.1", expectOK: true},
+		{name: "pd v5.0", component: v1alpha1.PDMemberType, version: "v5.0.0", expectOK: true},
+		{name: "tidb v5.0", component: v1alpha1.TiDBMemberType, version: "v5.0.6", expectOK: true},
+		{name: "unknown version", component: v1alpha1.TiKVMemberType, version: "v99.0.0", expectOK: false},
+		{name: "unparsable version", component: v1alpha1.TiKVMemberType, version: "latest", expectOK: false},
+		{name: "component without schema", component: v1alpha1.TiFlashMemberType, version: "v5.0.1", expectOK: false},
+	}
+	for _, test := range tests {
+		t.Log(test.name)
+		s, ok := Load(test.component, test.version)
+		g.Expect(ok).To(Equal(test.expectOK))
+		if test.expectOK {
+			g.Expect(s).NotTo(BeNil())
+		}
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name             string
+		component        v1alpha1.MemberType
+		config           string
+		expectUnknown    []string
+		expectDeprecated map[string]string
+	}
+	tests := []testcase{
+		{
+			name:      "valid keys",
+			component: v1alpha1.TiKVMemberType,
+			config: `
+log-level = "info"
+[raftstore]
+raft-log-gc-threshold = 50
+[rocksdb.defaultcf]
+block-size = "64KB"
+`,
+			expectDeprecated: map[string]string{},
+		},
+		{
+			name:      "unknown keys",
+			component: v1alpha1.TiKVMemberType,
+			config: `
+[raftstore]
+raft-log-gc-theshold = 50
+[rocksdbb.defaultcf]
+block-size = "64KB"
+`,
+			expectUnknown:    []string{"raftstore.raft-log-gc-theshold", "rocksdbb.defaultcf.block-size"},
+			expectDeprecated: map[string]string{},
+		},
+		{
+			name:      "open table accepts any keys",
+			component: v1alpha1.TiKVMemberType,
+			config: `
+[server.labels]
+zone = "z1"
+host = "h1"
+`,
+			expectDeprecated: map[string]string{},
+		},
+		{
+			name:      "deprecated keys",
+			component: v1alpha1.PDMemberType,
+			config: `
+log-level = "info"
+[schedule]
+disable-remove-down-replica = true
+disable-raft-learner = true
+enable-remove-extra-replica = true
+`,
+			expectDeprecated: map[string]string{
+				"log-level":                            "log.level",
+				"schedule.disable-remove-down-replica": "schedule.enable-remove-down-replica",
+				"schedule.disable-raft-learner":        "",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Log(test.name)
+		s, ok := Load(test.component, "v5.0.1")
+		g.Expect(ok).To(BeTrue())
+		cfg := config.New(map[string]interface{}{})
+		g.Expect(cfg.UnmarshalTOML([]byte(test.config))).To(Succeed())
+		result := s.Validate(cfg)
+		g.Expect(result.Unknown).To(Equal(test.expectUnknown))
+		g.Expect(result.Deprecated).To(Equal(test.expectDeprecated))
+	}
+}
+
+func TestValidateTidbCluster(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{
+		Spec: v1alpha1.TidbClusterSpec{
+			Version: "v5.0.1",
+			PD: &v1alpha1.PDSpec{
+				BaseImage: "pingcap/pd",
+				Config:    v1alpha1.NewPDConfig(),
+			},
+			TiKV: &v1alpha1.TiKVSpec{
+				BaseImage: "pingcap/tikv",
+				Config:    v1alpha1.NewTiKVConfig(),
+			},
+			TiDB: &v1alpha1.TiDBSpec{
+				BaseImage: "pingcap/tidb",
+				Config:    v1alpha1.NewTiDBConfig(),
+			},
+		},
+	}
+	tc.Spec.PD.Config.Set("schedule.disable-make-up-replica", true)
+	tc.Spec.TiKV.Config.Set("raftstore.raft-log-gc-theshold", 50)
+	tc.Spec.TiDB.Config.Set("log.level", "info")
+
+	results := ValidateTidbCluster(tc)
+	g.Expect(results).To(HaveLen(2))
+	g.Expect(UnknownKeysMessage(results)).To(Equal("unknown config keys are ignored, tikv: raftstore.raft-log-gc-theshold"))
+	g.Expect(Warnings(results)).To(Equal([]string{
+		`pd: config key "schedule.disable-make-up-replica" is deprecated, use "schedule.enable-make-up-replica" instead`,
+		`tikv: unknown config key "raftstore.raft-log-gc-theshold", it's ignored by tikv`,
+	}))
+
+	// the components of unknown versions are not validated
+	version := "nightly"
+	tc.Spec.TiKV.Version = &version
+	results = ValidateTidbCluster(tc)
+	g.Expect(results).To(HaveLen(1))
+	g.Expect(UnknownKeysMessage(results)).To(BeEmpty())
+}
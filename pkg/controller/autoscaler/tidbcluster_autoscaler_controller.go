@@ -19,6 +19,7 @@ import (
 
 	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/tidb-operator/pkg/autoscaler/autoscaler"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"k8s.io/apimachinery/pkg/api/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -43,8 +44,11 @@ func NewController(deps *controller.Dependencies) *Controller {
 			"tidbclusterautoscaler",
 		),
 	}
-	tidbAutoScalerInformer := deps.InformerFactory.Pingcap().V1alpha1().TidbClusterAutoScalers()
-	controller.WatchForObject(tidbAutoScalerInformer.Informer(), t.queue)
+	for _, informer := range deps.Informers(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().TidbClusterAutoScalers().Informer()
+	}) {
+		controller.WatchForObject(informer, t.queue)
+	}
 	return t
 }
 
@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/backup/backup"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -28,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
@@ -39,7 +41,7 @@ type Controller struct {
 	// control returns an interface capable of syncing a backup.
 	// Abstracted out for testing.
 	control ControlInterface
-	// backups that need to be synced.
+	// backups that need to be synced, the ones whose last sync failed are synced first.
 	queue workqueue.RateLimitingInterface
 }
 
@@ -48,24 +50,33 @@ func NewController(deps *controller.Dependencies) *Controller {
 	c := &Controller{
 		deps:    deps,
 		control: NewDefaultBackupControl(deps.Clientset, backup.NewBackupManager(deps)),
-		queue: workqueue.NewNamedRateLimitingQueue(
+		queue: controller.NewPriorityRateLimitingQueue(
 			controller.NewControllerRateLimiter(1*time.Second, 100*time.Second),
 			"backup",
+			controller.DefaultHighPriorityBurst,
 		),
 	}
 
-	backupInformer := deps.InformerFactory.Pingcap().V1alpha1().Backups()
-	jobInformer := deps.KubeInformerFactory.Batch().V1().Jobs()
-	backupInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: c.updateBackup,
-		UpdateFunc: func(old, cur interface{}) {
-			c.updateBackup(cur)
-		},
-		DeleteFunc: c.updateBackup,
+	backupInformers := deps.Informers(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().Backups().Informer()
 	})
-	jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		DeleteFunc: c.deleteJob,
+	jobInformers := deps.KubeInformers(func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Batch().V1().Jobs().Informer()
 	})
+	for _, informer := range backupInformers {
+		informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: c.updateBackup,
+			UpdateFunc: func(old, cur interface{}) {
+				c.updateBackup(cur)
+			},
+			DeleteFunc: c.updateBackup,
+		})
+	}
+	for _, informer := range jobInformers {
+		informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			DeleteFunc: c.deleteJob,
+		})
+	}
 
 	return c
 }
@@ -164,7 +175,19 @@ func (c *Controller) updateBackup(cur interface{}) {
 		return
 	}
 
+	if v1alpha1.IsBackupRetrying(newBackup) {
+		klog.V(4).Infof("backup %s/%s is retrying the failure, enqueue", ns, name)
+		c.enqueueBackup(newBackup)
+		return
+	}
+
 	if v1alpha1.IsBackupFailed(newBackup) {
+		if newBackup.Spec.BR != nil && newBackup.Spec.BackoffRetryPolicy != nil {
+			// the backup manager decides whether the failure can be retried
+			klog.V(4).Infof("backup %s/%s is Failed with backoff retry policy, enqueue", ns, name)
+			c.enqueueBackup(newBackup)
+			return
+		}
 		klog.V(4).Infof("backup %s/%s is Failed, skipping.", ns, name)
 		return
 	}
@@ -52,6 +52,7 @@ func TestBackupControllerUpdateBackup(t *testing.T) {
 		name                 string
 		backupHasBeenDeleted bool
 		conditionType        v1alpha1.BackupConditionType // only one condition used now.
+		backoffRetryPolicy   bool
 		beforeUpdateFn       func(*GomegaWithT, *Controller, *v1alpha1.Backup)
 		expectFn             func(*GomegaWithT, *Controller)
 		afterUpdateFn        func(*GomegaWithT, *Controller, *v1alpha1.Backup)
@@ -76,6 +77,11 @@ func TestBackupControllerUpdateBackup(t *testing.T) {
 			backup.DeletionTimestamp = &metav1.Time{Time: time.Now()}
 		}
 
+		if test.backoffRetryPolicy {
+			backup.Spec.BR = &v1alpha1.BRConfig{Cluster: "demo1"}
+			backup.Spec.BackoffRetryPolicy = &v1alpha1.BackoffRetryPolicy{}
+		}
+
 		if test.beforeUpdateFn != nil {
 			test.beforeUpdateFn(g, bkc, backup)
 		}
@@ -165,6 +171,24 @@ func TestBackupControllerUpdateBackup(t *testing.T) {
 				g.Expect(bkc.queue.Len()).To(Equal(0))
 			},
 		},
+		{
+			name:                 "backup has been failed with backoff retry policy",
+			backupHasBeenDeleted: false,
+			conditionType:        v1alpha1.BackupFailed,
+			backoffRetryPolicy:   true,
+			expectFn: func(g *GomegaWithT, bkc *Controller) {
+				g.Expect(bkc.queue.Len()).To(Equal(1))
+			},
+		},
+		{
+			name:                 "backup is retrying the failure",
+			backupHasBeenDeleted: false,
+			conditionType:        v1alpha1.BackupRetryTheFailed,
+			backoffRetryPolicy:   true,
+			expectFn: func(g *GomegaWithT, bkc *Controller) {
+				g.Expect(bkc.queue.Len()).To(Equal(1))
+			},
+		},
 		{
 			name:                 "backup has been scheduled with failed pod",
 			backupHasBeenDeleted: false,
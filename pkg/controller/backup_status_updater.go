@@ -47,6 +47,16 @@ type BackupUpdateStatus struct {
 	BackupSize *int64
 	// CommitTs is the snapshot time point of tidb cluster.
 	CommitTs *string
+	// ProgressStep is the step name of the progress.
+	ProgressStep *string
+	// Progress is the progress percentage of the step.
+	Progress *float64
+	// ProgressUpdateTime is the time at which the progress was updated.
+	ProgressUpdateTime *metav1.Time
+	// DeletedObjects is the number of the objects deleted by the step.
+	DeletedObjects *int64
+	// BackoffRetryRecord is the record of a retry of the failed backup.
+	BackoffRetryRecord *v1alpha1.BackoffRetryRecord
 }
 
 // BackupConditionUpdaterInterface enables updating Backup conditions.
@@ -77,9 +87,9 @@ func (u *realBackupConditionUpdater) Update(backup *v1alpha1.Backup, condition *
 	backupName := backup.GetName()
 	var isUpdate bool
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		updateBackupStatus(&backup.Status, newStatus)
+		isStatusUpdate := updateBackupStatus(&backup.Status, newStatus)
 		isUpdate = v1alpha1.UpdateBackupCondition(&backup.Status, condition)
-		if isUpdate {
+		if isStatusUpdate || isUpdate {
 			_, updateErr := u.cli.PingcapV1alpha1().Backups(ns).Update(context.TODO(), backup, metav1.UpdateOptions{})
 			if updateErr == nil {
 				klog.Infof("Backup: [%s/%s] updated successfully", ns, backupName)
@@ -100,29 +110,124 @@ func (u *realBackupConditionUpdater) Update(backup *v1alpha1.Backup, condition *
 }
 
 // updateBackupStatus updates existing Backup status
-// from the fields in BackupUpdateStatus.
-func updateBackupStatus(status *v1alpha1.BackupStatus, newStatus *BackupUpdateStatus) {
+// from the fields in BackupUpdateStatus, and returns whether the status is changed.
+func updateBackupStatus(status *v1alpha1.BackupStatus, newStatus *BackupUpdateStatus) bool {
 	if newStatus == nil {
-		return
+		return false
 	}
-	if newStatus.BackupPath != nil {
+	isUpdate := false
+	if newStatus.BackupPath != nil && status.BackupPath != *newStatus.BackupPath {
 		status.BackupPath = *newStatus.BackupPath
+		isUpdate = true
 	}
-	if newStatus.TimeStarted != nil {
+	if newStatus.TimeStarted != nil && !status.TimeStarted.Equal(newStatus.TimeStarted) {
 		status.TimeStarted = *newStatus.TimeStarted
+		isUpdate = true
 	}
-	if newStatus.TimeCompleted != nil {
+	if newStatus.TimeCompleted != nil && !status.TimeCompleted.Equal(newStatus.TimeCompleted) {
 		status.TimeCompleted = *newStatus.TimeCompleted
+		isUpdate = true
 	}
-	if newStatus.BackupSizeReadable != nil {
+	if newStatus.BackupSizeReadable != nil && status.BackupSizeReadable != *newStatus.BackupSizeReadable {
 		status.BackupSizeReadable = *newStatus.BackupSizeReadable
+		isUpdate = true
 	}
-	if newStatus.BackupSize != nil {
+	if newStatus.BackupSize != nil && status.BackupSize != *newStatus.BackupSize {
 		status.BackupSize = *newStatus.BackupSize
+		isUpdate = true
 	}
-	if newStatus.CommitTs != nil {
+	if newStatus.CommitTs != nil && status.CommitTs != *newStatus.CommitTs {
 		status.CommitTs = *newStatus.CommitTs
+		isUpdate = true
 	}
+	if newStatus.ProgressStep != nil && newStatus.Progress != nil {
+		if updateProgress(status, *newStatus.ProgressStep, *newStatus.Progress, newStatus.DeletedObjects, newStatus.ProgressUpdateTime) {
+			isUpdate = true
+		}
+	}
+	if newStatus.BackoffRetryRecord != nil {
+		if updateBackoffRetryStatus(status, newStatus.BackoffRetryRecord) {
+			isUpdate = true
+		}
+	}
+	return isUpdate
+}
+
+// updateBackoffRetryStatus appends the record of a new retry to the Backup status, or fills the
+// unset fields of the existing record with the same RetryNum. The fields of a record are only set
+// once, so that a retry decided from a stale Backup does not overwrite the recorded one.
+func updateBackoffRetryStatus(status *v1alpha1.BackupStatus, record *v1alpha1.BackoffRetryRecord) bool {
+	n := len(status.BackoffRetryStatus)
+	if record.RetryNum == n+1 {
+		status.BackoffRetryStatus = append(status.BackoffRetryStatus, *record.DeepCopy())
+		return true
+	}
+	if record.RetryNum < 1 || record.RetryNum > n {
+		return false
+	}
+	isUpdate := false
+	existing := &status.BackoffRetryStatus[record.RetryNum-1]
+	if existing.DetectFailedAt == nil && record.DetectFailedAt != nil {
+		existing.DetectFailedAt = record.DetectFailedAt.DeepCopy()
+		isUpdate = true
+	}
+	if existing.ExpectedRetryAt == nil && record.ExpectedRetryAt != nil {
+		existing.ExpectedRetryAt = record.ExpectedRetryAt.DeepCopy()
+		isUpdate = true
+	}
+	if existing.RealRetryAt == nil && record.RealRetryAt != nil {
+		existing.RealRetryAt = record.RealRetryAt.DeepCopy()
+		isUpdate = true
+	}
+	if existing.RetryReason == "" && record.RetryReason != "" {
+		existing.RetryReason = record.RetryReason
+		isUpdate = true
+	}
+	if existing.OriginalReason == "" && record.OriginalReason != "" {
+		existing.OriginalReason = record.OriginalReason
+		isUpdate = true
+	}
+	return isUpdate
+}
+
+// updateProgress updates the progress of the step in the Backup status.
+// BR may be restarted with the backup pod and report a lower progress of the same step,
+// so only the max progress and the max deleted objects of each step are kept.
+func updateProgress(status *v1alpha1.BackupStatus, step string, progress float64, deletedObjects *int64, updateTime *metav1.Time) bool {
+	now := metav1.Now()
+	if updateTime == nil {
+		updateTime = &now
+	}
+	var deleted int64
+	if deletedObjects != nil {
+		deleted = *deletedObjects
+	}
+	for i, p := range status.Progresses {
+		if p.Step != step {
+			continue
+		}
+		if progress <= p.Progress && deleted <= p.DeletedObjects {
+			return false
+		}
+		if progress > p.Progress {
+			p.Progress = progress
+		}
+		if deleted > p.DeletedObjects {
+			p.DeletedObjects = deleted
+		}
+		p.LastTransitionTime = *updateTime
+		// keep the latest updated step the last one
+		status.Progresses = append(status.Progresses[:i], status.Progresses[i+1:]...)
+		status.Progresses = append(status.Progresses, p)
+		return true
+	}
+	status.Progresses = append(status.Progresses, v1alpha1.Progress{
+		Step:               step,
+		Progress:           progress,
+		DeletedObjects:     deleted,
+		LastTransitionTime: *updateTime,
+	})
+	return true
 }
 
 var _ BackupConditionUpdaterInterface = &realBackupConditionUpdater{}
@@ -29,6 +29,7 @@ func TestUpdateBackupStatus(t *testing.T) {
 		status       *v1alpha1.BackupStatus
 		updateStatus *BackupUpdateStatus
 		expectStatus *v1alpha1.BackupStatus
+		expectUpdate bool
 	}{
 		{
 			name:         "updateStatus is nil",
@@ -47,16 +48,155 @@ func TestUpdateBackupStatus(t *testing.T) {
 			status:       newBackupStatus(),
 			updateStatus: newUpdateBackupStatus(),
 			expectStatus: newExpectBackupStatus(),
+			expectUpdate: true,
+		},
+		{
+			name:         "fields are not changed",
+			status:       newExpectBackupStatus(),
+			updateStatus: newUpdateBackupStatus(),
+			expectStatus: newExpectBackupStatus(),
 		},
 	}
 
 	for _, test := range tests {
 		t.Logf("test: %+v", test.name)
-		updateBackupStatus(test.status, test.updateStatus)
+		isUpdate := updateBackupStatus(test.status, test.updateStatus)
+		g.Expect(isUpdate).Should(Equal(test.expectUpdate))
 		g.Expect(*test.status).Should(Equal(*test.expectStatus))
 	}
 }
 
+func TestUpdateBRProgress(t *testing.T) {
+	g := NewGomegaWithT(t)
+	t1 := metav1.Time{Time: time.Unix(100, 0)}
+	t2 := metav1.Time{Time: time.Unix(200, 0)}
+	tests := []struct {
+		name           string
+		progresses     []v1alpha1.Progress
+		step           string
+		progress       float64
+		expectUpdate   bool
+		expectProgress []v1alpha1.Progress
+	}{
+		{
+			name:         "new step",
+			step:         "Full backup",
+			progress:     10,
+			expectUpdate: true,
+			expectProgress: []v1alpha1.Progress{
+				{Step: "Full backup", Progress: 10, LastTransitionTime: t2},
+			},
+		},
+		{
+			name: "progress of the step increases",
+			progresses: []v1alpha1.Progress{
+				{Step: "Full backup", Progress: 10, LastTransitionTime: t1},
+				{Step: "Checksum", Progress: 50, LastTransitionTime: t1},
+			},
+			step:         "Full backup",
+			progress:     20.5,
+			expectUpdate: true,
+			expectProgress: []v1alpha1.Progress{
+				{Step: "Checksum", Progress: 50, LastTransitionTime: t1},
+				{Step: "Full backup", Progress: 20.5, LastTransitionTime: t2},
+			},
+		},
+		{
+			name: "progress of the step regresses after BR restarts",
+			progresses: []v1alpha1.Progress{
+				{Step: "Full backup", Progress: 60, LastTransitionTime: t1},
+			},
+			step:     "Full backup",
+			progress: 5,
+			expectProgress: []v1alpha1.Progress{
+				{Step: "Full backup", Progress: 60, LastTransitionTime: t1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test: %+v", test.name)
+		status := &v1alpha1.BackupStatus{Progresses: test.progresses}
+		isUpdate := updateBackupStatus(status, &BackupUpdateStatus{
+			ProgressStep:       &test.step,
+			Progress:           &test.progress,
+			ProgressUpdateTime: &t2,
+		})
+		g.Expect(isUpdate).Should(Equal(test.expectUpdate))
+		g.Expect(status.Progresses).Should(Equal(test.expectProgress))
+	}
+}
+
+func TestUpdateCleanProgress(t *testing.T) {
+	g := NewGomegaWithT(t)
+	t1 := metav1.Time{Time: time.Unix(100, 0)}
+	t2 := metav1.Time{Time: time.Unix(200, 0)}
+	step := v1alpha1.BackupCleanProgressStep
+	tests := []struct {
+		name           string
+		progresses     []v1alpha1.Progress
+		progress       float64
+		deleted        int64
+		expectUpdate   bool
+		expectProgress []v1alpha1.Progress
+	}{
+		{
+			name:         "new step",
+			deleted:      1000,
+			expectUpdate: true,
+			expectProgress: []v1alpha1.Progress{
+				{Step: step, DeletedObjects: 1000, LastTransitionTime: t2},
+			},
+		},
+		{
+			name: "more objects deleted",
+			progresses: []v1alpha1.Progress{
+				{Step: step, DeletedObjects: 1000, LastTransitionTime: t1},
+			},
+			deleted:      2000,
+			expectUpdate: true,
+			expectProgress: []v1alpha1.Progress{
+				{Step: step, DeletedObjects: 2000, LastTransitionTime: t2},
+			},
+		},
+		{
+			name: "clean completes",
+			progresses: []v1alpha1.Progress{
+				{Step: step, DeletedObjects: 2000, LastTransitionTime: t1},
+			},
+			progress:     100,
+			deleted:      2000,
+			expectUpdate: true,
+			expectProgress: []v1alpha1.Progress{
+				{Step: step, Progress: 100, DeletedObjects: 2000, LastTransitionTime: t2},
+			},
+		},
+		{
+			name: "count not changed",
+			progresses: []v1alpha1.Progress{
+				{Step: step, DeletedObjects: 2000, LastTransitionTime: t1},
+			},
+			deleted: 2000,
+			expectProgress: []v1alpha1.Progress{
+				{Step: step, DeletedObjects: 2000, LastTransitionTime: t1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test: %+v", test.name)
+		status := &v1alpha1.BackupStatus{Progresses: test.progresses}
+		isUpdate := updateBackupStatus(status, &BackupUpdateStatus{
+			ProgressStep:       &step,
+			Progress:           &test.progress,
+			DeletedObjects:     &test.deleted,
+			ProgressUpdateTime: &t2,
+		})
+		g.Expect(isUpdate).Should(Equal(test.expectUpdate))
+		g.Expect(status.Progresses).Should(Equal(test.expectProgress))
+	}
+}
+
 func newUpdateBackupStatus() *BackupUpdateStatus {
 	ts := "421762809912885269"
 	start, _ := time.Parse(time.RFC3339, "2020-12-25T21:46:59Z")
@@ -121,3 +261,58 @@ func newExpectBackupStatus() *v1alpha1.BackupStatus {
 	s.BackupSize = size
 	return s
 }
+
+func TestUpdateBackoffRetryStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+	t1 := metav1.Time{Time: time.Unix(100, 0)}
+	t2 := metav1.Time{Time: time.Unix(200, 0)}
+	t3 := metav1.Time{Time: time.Unix(300, 0)}
+	first := v1alpha1.BackoffRetryRecord{RetryNum: 1, DetectFailedAt: &t1, ExpectedRetryAt: &t2, RetryReason: "BackoffLimitExceeded"}
+	tests := []struct {
+		name         string
+		records      []v1alpha1.BackoffRetryRecord
+		record       v1alpha1.BackoffRetryRecord
+		expectUpdate bool
+		expectRecord []v1alpha1.BackoffRetryRecord
+	}{
+		{
+			name:         "first retry",
+			record:       first,
+			expectUpdate: true,
+			expectRecord: []v1alpha1.BackoffRetryRecord{first},
+		},
+		{
+			name:         "the retry is done",
+			records:      []v1alpha1.BackoffRetryRecord{first},
+			record:       v1alpha1.BackoffRetryRecord{RetryNum: 1, RealRetryAt: &t3},
+			expectUpdate: true,
+			expectRecord: []v1alpha1.BackoffRetryRecord{
+				{RetryNum: 1, DetectFailedAt: &t1, ExpectedRetryAt: &t2, RealRetryAt: &t3, RetryReason: "BackoffLimitExceeded"},
+			},
+		},
+		{
+			name:         "the retry is decided again from a stale backup",
+			records:      []v1alpha1.BackoffRetryRecord{first},
+			record:       v1alpha1.BackoffRetryRecord{RetryNum: 1, DetectFailedAt: &t2, ExpectedRetryAt: &t3, RetryReason: "BackoffLimitExceeded"},
+			expectUpdate: false,
+			expectRecord: []v1alpha1.BackoffRetryRecord{first},
+		},
+		{
+			name:         "a retry is skipped",
+			records:      []v1alpha1.BackoffRetryRecord{first},
+			record:       v1alpha1.BackoffRetryRecord{RetryNum: 3, DetectFailedAt: &t3},
+			expectUpdate: false,
+			expectRecord: []v1alpha1.BackoffRetryRecord{first},
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test: %+v", test.name)
+		status := &v1alpha1.BackupStatus{BackoffRetryStatus: test.records}
+		isUpdate := updateBackupStatus(status, &BackupUpdateStatus{
+			BackoffRetryRecord: &test.record,
+		})
+		g.Expect(isUpdate).Should(Equal(test.expectUpdate))
+		g.Expect(status.BackoffRetryStatus).Should(Equal(test.expectRecord))
+	}
+}
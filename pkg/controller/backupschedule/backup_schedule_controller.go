@@ -20,6 +20,7 @@ import (
 	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/backup/backupschedule"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"k8s.io/apimachinery/pkg/api/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -50,14 +51,17 @@ func NewController(deps *controller.Dependencies) *Controller {
 		),
 	}
 
-	backupScheduleInformer := deps.InformerFactory.Pingcap().V1alpha1().BackupSchedules()
-	backupScheduleInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: c.enqueueBackupSchedule,
-		UpdateFunc: func(old, cur interface{}) {
-			c.enqueueBackupSchedule(cur)
-		},
-		DeleteFunc: c.enqueueBackupSchedule,
-	})
+	for _, informer := range deps.Informers(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().BackupSchedules().Informer()
+	}) {
+		informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: c.enqueueBackupSchedule,
+			UpdateFunc: func(old, cur interface{}) {
+				c.enqueueBackupSchedule(cur)
+			},
+			DeleteFunc: c.enqueueBackupSchedule,
+		})
+	}
 
 	return c
 }
@@ -15,10 +15,18 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -40,11 +48,37 @@ type ConfigMapControlInterface interface {
 	DeleteConfigMap(controller runtime.Object, cm *corev1.ConfigMap) error
 	// GetConfigMap get the ConfigMap by configMap name
 	GetConfigMap(controller runtime.Object, cm *corev1.ConfigMap) (*corev1.ConfigMap, error)
+	// ApplyPartitionedConfigMap creates or updates the given ConfigMap owned by the controller object,
+	// the data exceeding ConfigMapPartitionSize is split across the partitions of the ConfigMap
+	ApplyPartitionedConfigMap(controller runtime.Object, cm *corev1.ConfigMap) error
+	// GetConfigMapMerged get the ConfigMap by configMap name, the data of a partitioned ConfigMap
+	// is reassembled from its partitions
+	GetConfigMapMerged(controller runtime.Object, cm *corev1.ConfigMap) (*corev1.ConfigMap, error)
+	// ResyncConfigMap gets the ConfigMap from the API server bypassing any cache, and refreshes
+	// the cache with it, it's used after the ConfigMap is found drifted by an external change
+	ResyncConfigMap(controller runtime.Object, name, namespace string) (*corev1.ConfigMap, error)
+	// RenderConfigMap returns a copy of the ConfigMap rendered by render, the ConfigMap is cached by key
+	// and isn't rendered again until the hash of spec, i.e. what the ConfigMap is rendered from, changes
+	RenderConfigMap(key string, spec interface{}, render func() (*corev1.ConfigMap, error)) (*corev1.ConfigMap, error)
+	// ForgetRenderedConfigMap drops the ConfigMap cached by key by RenderConfigMap, it's called after
+	// the owner of the ConfigMap is deleted
+	ForgetRenderedConfigMap(key string)
 }
 
+const (
+	// ConfigMapPartitionSize is the max size of the data in a partition of a ConfigMap,
+	// it keeps the partitions under the 1MiB limit of etcd with room for the metadata
+	ConfigMapPartitionSize = 900 * 1024
+	// ConfigMapPartitionsAnnKey is the annotation key of the index ConfigMap of a partitioned ConfigMap
+	ConfigMapPartitionsAnnKey = "tidb.pingcap.com/configmap-partitions"
+	// configMapIndexKey is the key of the index ConfigMap recording where the data is
+	configMapIndexKey = "partitions.json"
+)
+
 type realConfigMapControl struct {
-	kubeCli  kubernetes.Interface
-	recorder record.EventRecorder
+	kubeCli     kubernetes.Interface
+	recorder    record.EventRecorder
+	renderCache configMapRenderCache
 }
 
 // NewRealSecretControl creates a new SecretControlInterface
@@ -59,9 +93,29 @@ func NewRealConfigMapControl(
 }
 
 func (c *realConfigMapControl) CreateConfigMap(owner runtime.Object, cm *corev1.ConfigMap) (*corev1.ConfigMap, error) {
-	created, err := c.kubeCli.CoreV1().ConfigMaps(cm.Namespace).Create(context.Background(), cm, metav1.CreateOptions{})
-	c.recordConfigMapEvent("create", owner, cm, err)
-	return created, err
+	ns := cm.GetNamespace()
+	cmName := cm.GetName()
+
+	created, err := c.kubeCli.CoreV1().ConfigMaps(ns).Create(context.Background(), cm, metav1.CreateOptions{})
+	if !apierrors.IsAlreadyExists(err) {
+		c.recordConfigMapEvent("create", owner, cm, "", err)
+		return created, err
+	}
+
+	// the ConfigMap is created by another sync racing with us or left by the previous cluster
+	// with the same name, take the existing one and update it if its data differs
+	existing, err := c.kubeCli.CoreV1().ConfigMaps(ns).Get(context.Background(), cmName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if apiequality.Semantic.DeepEqual(existing.Data, cm.Data) {
+		klog.Infof("ConfigMap: [%s/%s] already exists", ns, cmName)
+		return existing, nil
+	}
+	klog.Infof("ConfigMap: [%s/%s] already exists with different data, update it", ns, cmName)
+	updated := existing.DeepCopy()
+	updated.Data = cm.Data
+	return c.UpdateConfigMap(owner, updated)
 }
 
 func (c *realConfigMapControl) UpdateConfigMap(owner runtime.Object, cm *corev1.ConfigMap) (*corev1.ConfigMap, error) {
@@ -69,6 +123,12 @@ func (c *realConfigMapControl) UpdateConfigMap(owner runtime.Object, cm *corev1.
 	cmName := cm.GetName()
 	cmData := cm.Data
 
+	// the data before the update is only used to tell which keys are changed in the event
+	var oldData map[string]string
+	if existing, err := c.kubeCli.CoreV1().ConfigMaps(ns).Get(context.Background(), cmName, metav1.GetOptions{}); err == nil && existing != nil {
+		oldData = existing.Data
+	}
+
 	var updatedCm *corev1.ConfigMap
 	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		var updateErr error
@@ -81,18 +141,20 @@ func (c *realConfigMapControl) UpdateConfigMap(owner runtime.Object, cm *corev1.
 		if updated, err := c.kubeCli.CoreV1().ConfigMaps(cm.Namespace).Get(context.Background(), cmName, metav1.GetOptions{}); err != nil {
 			utilruntime.HandleError(fmt.Errorf("error getting updated ConfigMap %s/%s from lister: %v", ns, cmName, err))
 		} else {
+			oldData = updated.Data
 			cm = updated.DeepCopy()
 			cm.Data = cmData
 		}
 
 		return updateErr
 	})
+	c.recordConfigMapEvent("update", owner, cm, diffConfigMapData(oldData, cmData), err)
 	return updatedCm, err
 }
 
 func (c *realConfigMapControl) DeleteConfigMap(owner runtime.Object, cm *corev1.ConfigMap) error {
 	err := c.kubeCli.CoreV1().ConfigMaps(cm.Namespace).Delete(context.TODO(), cm.Name, metav1.DeleteOptions{})
-	c.recordConfigMapEvent("delete", owner, cm, err)
+	c.recordConfigMapEvent("delete", owner, cm, "", err)
 	return err
 }
 
@@ -101,7 +163,76 @@ func (c *realConfigMapControl) GetConfigMap(owner runtime.Object, cm *corev1.Con
 	return existConfigMap, err
 }
 
-func (c *realConfigMapControl) recordConfigMapEvent(verb string, owner runtime.Object, cm *corev1.ConfigMap, err error) {
+func (c *realConfigMapControl) ApplyPartitionedConfigMap(owner runtime.Object, cm *corev1.ConfigMap) error {
+	index, parts, err := SplitConfigMap(cm, ConfigMapPartitionSize)
+	if err != nil {
+		return err
+	}
+	existing, err := c.kubeCli.CoreV1().ConfigMaps(cm.Namespace).Get(context.TODO(), cm.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if apierrors.IsNotFound(err) {
+		existing = nil
+	}
+
+	// the partitions are applied before the index, so that the index never refers to a missing partition
+	apply := func(desired *corev1.ConfigMap) error {
+		_, err := c.kubeCli.CoreV1().ConfigMaps(desired.Namespace).Get(context.TODO(), desired.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			_, err = c.CreateConfigMap(owner, desired)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		_, err = c.UpdateConfigMap(owner, desired)
+		return err
+	}
+	for _, part := range parts {
+		if err := apply(part); err != nil {
+			return err
+		}
+	}
+	if err := apply(index); err != nil {
+		return err
+	}
+
+	for _, name := range stalePartitions(existing, parts) {
+		stale := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: cm.Namespace, Name: name}}
+		if err := c.DeleteConfigMap(owner, stale); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *realConfigMapControl) GetConfigMapMerged(owner runtime.Object, cm *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	index, err := c.GetConfigMap(owner, cm)
+	if err != nil {
+		return nil, err
+	}
+	return mergeConfigMap(index, func(name string) (*corev1.ConfigMap, error) {
+		return c.kubeCli.CoreV1().ConfigMaps(cm.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	})
+}
+
+// ResyncConfigMap gets the ConfigMap from the API server, realConfigMapControl doesn't cache
+// ConfigMaps so there is nothing to refresh
+func (c *realConfigMapControl) ResyncConfigMap(_ runtime.Object, name, namespace string) (*corev1.ConfigMap, error) {
+	return c.kubeCli.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+func (c *realConfigMapControl) RenderConfigMap(key string, spec interface{}, render func() (*corev1.ConfigMap, error)) (*corev1.ConfigMap, error) {
+	return c.renderCache.render(key, spec, render)
+}
+
+func (c *realConfigMapControl) ForgetRenderedConfigMap(key string) {
+	c.renderCache.forget(key)
+}
+
+// recordConfigMapEvent records the event of the ConfigMap, the detail is appended to the message of a successful event
+func (c *realConfigMapControl) recordConfigMapEvent(verb string, owner runtime.Object, cm *corev1.ConfigMap, detail string, err error) {
 	kind := owner.GetObjectKind().GroupVersionKind().Kind
 	var name string
 	if accessor, ok := owner.(metav1.ObjectMetaAccessor); ok {
@@ -112,6 +243,9 @@ func (c *realConfigMapControl) recordConfigMapEvent(verb string, owner runtime.O
 		reason := fmt.Sprintf("Successful%s", strings.Title(verb))
 		msg := fmt.Sprintf("%s ConfigMap %s for %s/%s successful",
 			strings.ToLower(verb), cmName, kind, name)
+		if detail != "" {
+			msg += ", " + detail
+		}
 		c.recorder.Event(owner, corev1.EventTypeNormal, reason, msg)
 	} else {
 		reason := fmt.Sprintf("Failed%s", strings.Title(verb))
@@ -121,26 +255,293 @@ func (c *realConfigMapControl) recordConfigMapEvent(verb string, owner runtime.O
 	}
 }
 
+// diffConfigMapData summarizes the keys changed, added and removed from the old data to the new data.
+// Only the key names are reported, as the values may be sensitive.
+func diffConfigMapData(oldData, newData map[string]string) string {
+	var changed, added, removed []string
+	for key, val := range newData {
+		oldVal, ok := oldData[key]
+		if !ok {
+			added = append(added, key)
+		} else if oldVal != val {
+			changed = append(changed, key)
+		}
+	}
+	for key := range oldData {
+		if _, ok := newData[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	if len(changed)+len(added)+len(removed) == 0 {
+		return "no data changed"
+	}
+
+	var diffs []string
+	for _, diff := range []struct {
+		action string
+		keys   []string
+	}{
+		{"changed", changed},
+		{"added", added},
+		{"removed", removed},
+	} {
+		if len(diff.keys) > 0 {
+			sort.Strings(diff.keys)
+			diffs = append(diffs, fmt.Sprintf("%s keys: %s", diff.action, strings.Join(diff.keys, ",")))
+		}
+	}
+	return strings.Join(diffs, "; ")
+}
+
 var _ ConfigMapControlInterface = &realConfigMapControl{}
 
+// configMapRenderCache caches the rendered ConfigMaps with the hash of the spec they're rendered from,
+// so that rendering the config and the start script is skipped if nothing changes since the last sync
+type configMapRenderCache struct {
+	lock    sync.Mutex
+	entries map[string]configMapRenderEntry
+}
+
+type configMapRenderEntry struct {
+	specHash string
+	cm       *corev1.ConfigMap
+}
+
+// render returns a copy of the cached ConfigMap of key if the hash of spec matches, otherwise the
+// ConfigMap is rendered and cached in place of the stale one. A failed rendering isn't cached.
+func (c *configMapRenderCache) render(key string, spec interface{}, render func() (*corev1.ConfigMap, error)) (*corev1.ConfigMap, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash the spec of ConfigMap %s, error: %v", key, err)
+	}
+	specHash := fmt.Sprintf("%x", sha256.Sum256(data))
+
+	c.lock.Lock()
+	entry, ok := c.entries[key]
+	c.lock.Unlock()
+	if ok && entry.specHash == specHash {
+		return entry.cm.DeepCopy(), nil
+	}
+
+	cm, err := render()
+	if err != nil || cm == nil {
+		return cm, err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]configMapRenderEntry{}
+	}
+	// the callers may modify the returned ConfigMap, e.g. suffix the name with the digest of the data
+	c.entries[key] = configMapRenderEntry{specHash: specHash, cm: cm.DeepCopy()}
+	return cm, nil
+}
+
+// forget drops the cached ConfigMap of key
+func (c *configMapRenderCache) forget(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.entries, key)
+}
+
+// configMapChunk is a chunk of the value of a key of a partitioned ConfigMap
+type configMapChunk struct {
+	// Name is the name of the partition where the chunk is
+	Name string `json:"name"`
+	// Key is the key of the chunk in the partition
+	Key string `json:"key"`
+}
+
+// configMapIndex records the chunks of the values of the keys of a partitioned ConfigMap in order
+type configMapIndex struct {
+	Keys map[string][]configMapChunk `json:"keys"`
+}
+
+// ConfigMapPartitionName returns the name of the i-th partition of a partitioned ConfigMap
+func ConfigMapPartitionName(name string, i int) string {
+	return fmt.Sprintf("%s-part-%d", name, i)
+}
+
+// SplitConfigMap splits the data of the ConfigMap into partitions of at most maxSize bytes of data.
+// The returned index ConfigMap has the name of cm and records where the data is, the partitions are
+// named by ConfigMapPartitionName. If the data fits in maxSize, cm is returned without partitions.
+func SplitConfigMap(cm *corev1.ConfigMap, maxSize int) (*corev1.ConfigMap, []*corev1.ConfigMap, error) {
+	size := 0
+	keys := make([]string, 0, len(cm.Data))
+	for k, v := range cm.Data {
+		size += len(k) + len(v)
+		keys = append(keys, k)
+	}
+	if size <= maxSize {
+		return cm, nil, nil
+	}
+	sort.Strings(keys)
+
+	var parts []*corev1.ConfigMap
+	partSize := 0
+	newPart := func() *corev1.ConfigMap {
+		part := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            ConfigMapPartitionName(cm.Name, len(parts)),
+				Namespace:       cm.Namespace,
+				Labels:          cm.Labels,
+				OwnerReferences: cm.OwnerReferences,
+			},
+			Data: map[string]string{},
+		}
+		parts = append(parts, part)
+		partSize = 0
+		return part
+	}
+
+	index := configMapIndex{Keys: map[string][]configMapChunk{}}
+	part := newPart()
+	for _, k := range keys {
+		chunks, err := splitConfigMapValue(k, cm.Data[k], maxSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i, chunk := range chunks {
+			chunkKey := k
+			if len(chunks) > 1 {
+				chunkKey = fmt.Sprintf("%s.%d", k, i)
+			}
+			if partSize+len(chunkKey)+len(chunk) > maxSize && len(part.Data) > 0 {
+				part = newPart()
+			}
+			part.Data[chunkKey] = chunk
+			partSize += len(chunkKey) + len(chunk)
+			index.Keys[k] = append(index.Keys[k], configMapChunk{Name: part.Name, Key: chunkKey})
+		}
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return nil, nil, err
+	}
+	annotations := map[string]string{}
+	for k, v := range cm.Annotations {
+		annotations[k] = v
+	}
+	annotations[ConfigMapPartitionsAnnKey] = strconv.Itoa(len(parts))
+	indexCm := cm.DeepCopy()
+	indexCm.Annotations = annotations
+	indexCm.Data = map[string]string{configMapIndexKey: string(data)}
+	return indexCm, parts, nil
+}
+
+// splitConfigMapValue splits the value into chunks fitting in a partition with the key of the chunk,
+// the value is split at the boundaries of UTF-8 characters.
+func splitConfigMapValue(key, value string, maxSize int) ([]string, error) {
+	// reserve the room for the suffix of the chunk key
+	limit := maxSize - len(key) - 16
+	if limit < utf8.UTFMax {
+		return nil, fmt.Errorf("key %s is too long to fit in a ConfigMap partition of %d bytes", key, maxSize)
+	}
+	if len(value) <= limit {
+		return []string{value}, nil
+	}
+	var chunks []string
+	for len(value) > limit {
+		end := limit
+		for end > 0 && !utf8.RuneStart(value[end]) {
+			end--
+		}
+		chunks = append(chunks, value[:end])
+		value = value[end:]
+	}
+	return append(chunks, value), nil
+}
+
+// MergeConfigMap reassembles the data of a partitioned ConfigMap from its index and partitions,
+// a ConfigMap which is not partitioned is returned as is.
+func MergeConfigMap(index *corev1.ConfigMap, parts []*corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	partsByName := map[string]*corev1.ConfigMap{}
+	for _, part := range parts {
+		partsByName[part.Name] = part
+	}
+	return mergeConfigMap(index, func(name string) (*corev1.ConfigMap, error) {
+		part, ok := partsByName[name]
+		if !ok {
+			return nil, fmt.Errorf("partition %s of ConfigMap %s/%s is not found", name, index.Namespace, index.Name)
+		}
+		return part, nil
+	})
+}
+
+func mergeConfigMap(index *corev1.ConfigMap, getPart func(name string) (*corev1.ConfigMap, error)) (*corev1.ConfigMap, error) {
+	if _, ok := index.Annotations[ConfigMapPartitionsAnnKey]; !ok {
+		return index, nil
+	}
+	var idx configMapIndex
+	if err := json.Unmarshal([]byte(index.Data[configMapIndexKey]), &idx); err != nil {
+		return nil, fmt.Errorf("failed to parse the index of ConfigMap %s/%s, error: %v", index.Namespace, index.Name, err)
+	}
+
+	parts := map[string]*corev1.ConfigMap{}
+	data := map[string]string{}
+	for k, chunks := range idx.Keys {
+		var value strings.Builder
+		for _, chunk := range chunks {
+			part, ok := parts[chunk.Name]
+			if !ok {
+				var err error
+				part, err = getPart(chunk.Name)
+				if err != nil {
+					return nil, err
+				}
+				parts[chunk.Name] = part
+			}
+			v, ok := part.Data[chunk.Key]
+			if !ok {
+				return nil, fmt.Errorf("key %s is not found in partition %s of ConfigMap %s/%s", chunk.Key, chunk.Name, index.Namespace, index.Name)
+			}
+			value.WriteString(v)
+		}
+		data[k] = value.String()
+	}
+
+	merged := index.DeepCopy()
+	delete(merged.Annotations, ConfigMapPartitionsAnnKey)
+	merged.Data = data
+	return merged, nil
+}
+
+// stalePartitions returns the names of the partitions of the existing ConfigMap which are not
+// in the desired partitions.
+func stalePartitions(existing *corev1.ConfigMap, desired []*corev1.ConfigMap) []string {
+	if existing == nil {
+		return nil
+	}
+	count, err := strconv.Atoi(existing.Annotations[ConfigMapPartitionsAnnKey])
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for i := len(desired); i < count; i++ {
+		names = append(names, ConfigMapPartitionName(existing.Name, i))
+	}
+	return names
+}
+
 // NewFakeConfigMapControl returns a FakeConfigMapControl
-func NewFakeConfigMapControl(cmInformer coreinformers.ConfigMapInformer) *FakeConfigMapControl {
+func NewFakeConfigMapControl(kubeCli kubernetes.Interface, cmInformer coreinformers.ConfigMapInformer) *FakeConfigMapControl {
 	return &FakeConfigMapControl{
-		cmInformer.Informer().GetIndexer(),
-		RequestTracker{},
-		RequestTracker{},
-		RequestTracker{},
-		RequestTracker{},
+		KubeCli:   kubeCli,
+		CmIndexer: cmInformer.Informer().GetIndexer(),
 	}
 }
 
 // FakeConfigMapControl is a fake ConfigMapControlInterface
 type FakeConfigMapControl struct {
+	// KubeCli is the live source of ResyncConfigMap
+	KubeCli                kubernetes.Interface
 	CmIndexer              cache.Indexer
 	createConfigMapTracker RequestTracker
 	updateConfigMapTracker RequestTracker
 	deleteConfigMapTracker RequestTracker
 	getConfigMapTracker    RequestTracker
+	renderCache            configMapRenderCache
 }
 
 // SetCreateConfigMapError sets the error attributes of createConfigMapTracker
@@ -198,4 +599,70 @@ func (c *FakeConfigMapControl) GetConfigMap(controller runtime.Object, cm *corev
 	return cm, nil
 }
 
+// ApplyPartitionedConfigMap adds or updates the ConfigMap and its partitions in CmIndexer
+func (c *FakeConfigMapControl) ApplyPartitionedConfigMap(_ runtime.Object, cm *corev1.ConfigMap) error {
+	index, parts, err := SplitConfigMap(cm, ConfigMapPartitionSize)
+	if err != nil {
+		return err
+	}
+	for _, desired := range append(parts, index) {
+		_, exist, err := c.CmIndexer.Get(desired)
+		if err != nil {
+			return err
+		}
+		if exist {
+			err = c.CmIndexer.Update(desired)
+		} else {
+			err = c.CmIndexer.Add(desired)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetConfigMapMerged gets the ConfigMap from CmIndexer and reassembles its partitions
+func (c *FakeConfigMapControl) GetConfigMapMerged(_ runtime.Object, cm *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	get := func(name string) (*corev1.ConfigMap, error) {
+		obj, exist, err := c.CmIndexer.GetByKey(fmt.Sprintf("%s/%s", cm.Namespace, name))
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			return nil, apierrors.NewNotFound(corev1.Resource("configmap"), name)
+		}
+		return obj.(*corev1.ConfigMap), nil
+	}
+	index, err := get(cm.Name)
+	if err != nil {
+		return nil, err
+	}
+	return mergeConfigMap(index, get)
+}
+
+// ResyncConfigMap gets the ConfigMap from KubeCli and refreshes it in CmIndexer
+func (c *FakeConfigMapControl) ResyncConfigMap(_ runtime.Object, name, namespace string) (*corev1.ConfigMap, error) {
+	live, err := c.KubeCli.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if _, exist, err := c.CmIndexer.Get(live); err != nil {
+		return nil, err
+	} else if exist {
+		return live, c.CmIndexer.Update(live)
+	}
+	return live, c.CmIndexer.Add(live)
+}
+
+// RenderConfigMap caches the rendered ConfigMap the same as realConfigMapControl
+func (c *FakeConfigMapControl) RenderConfigMap(key string, spec interface{}, render func() (*corev1.ConfigMap, error)) (*corev1.ConfigMap, error) {
+	return c.renderCache.render(key, spec, render)
+}
+
+// ForgetRenderedConfigMap drops the cached ConfigMap the same as realConfigMapControl
+func (c *FakeConfigMapControl) ForgetRenderedConfigMap(key string) {
+	c.renderCache.forget(key)
+}
+
 var _ ConfigMapControlInterface = &FakeConfigMapControl{}
@@ -14,14 +14,18 @@
 package controller
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes/fake"
 	core "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/record"
@@ -64,6 +68,59 @@ func TestConfigMapControlCreatesConfigMapFailed(t *testing.T) {
 	g.Expect(events[0]).To(ContainSubstring(corev1.EventTypeWarning))
 }
 
+func TestConfigMapControlCreatesConfigMapAlreadyExists(t *testing.T) {
+	g := NewGomegaWithT(t)
+	recorder := record.NewFakeRecorder(10)
+	tc := newTidbCluster()
+	cm := newConfigMap()
+	existing := newConfigMap()
+	existing.ResourceVersion = "1"
+	fakeClient := &fake.Clientset{}
+	control := NewRealConfigMapControl(fakeClient, recorder)
+	fakeClient.AddReactor("create", "configmaps", func(action core.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewAlreadyExists(action.GetResource().GroupResource(), cm.Name)
+	})
+	fakeClient.AddReactor("get", "configmaps", func(action core.Action) (bool, runtime.Object, error) {
+		return true, existing, nil
+	})
+	created, err := control.CreateConfigMap(tc, cm)
+	g.Expect(err).To(Succeed())
+	g.Expect(created.ResourceVersion).To(Equal("1"))
+
+	events := collectEvents(recorder.Events)
+	g.Expect(events).To(BeEmpty())
+}
+
+func TestConfigMapControlCreatesConfigMapAlreadyExistsWithDifferentData(t *testing.T) {
+	g := NewGomegaWithT(t)
+	recorder := record.NewFakeRecorder(10)
+	tc := newTidbCluster()
+	cm := newConfigMap()
+	existing := newConfigMap()
+	existing.ResourceVersion = "1"
+	existing.Data = map[string]string{"file": "stale"}
+	fakeClient := &fake.Clientset{}
+	control := NewRealConfigMapControl(fakeClient, recorder)
+	fakeClient.AddReactor("create", "configmaps", func(action core.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewAlreadyExists(action.GetResource().GroupResource(), cm.Name)
+	})
+	fakeClient.AddReactor("get", "configmaps", func(action core.Action) (bool, runtime.Object, error) {
+		return true, existing, nil
+	})
+	fakeClient.AddReactor("update", "configmaps", func(action core.Action) (bool, runtime.Object, error) {
+		update := action.(core.UpdateAction)
+		return true, update.GetObject(), nil
+	})
+	updated, err := control.CreateConfigMap(tc, cm)
+	g.Expect(err).To(Succeed())
+	g.Expect(updated.ResourceVersion).To(Equal("1"))
+	g.Expect(updated.Data).To(Equal(cm.Data))
+
+	events := collectEvents(recorder.Events)
+	g.Expect(events).To(HaveLen(1))
+	g.Expect(events[0]).To(ContainSubstring(corev1.EventTypeNormal))
+}
+
 func TestConfigMapControlUpdateConfigMap(t *testing.T) {
 	g := NewGomegaWithT(t)
 	recorder := record.NewFakeRecorder(10)
@@ -105,6 +162,35 @@ func TestConfigMapControlUpdateConfigMapConflictSuccess(t *testing.T) {
 	g.Expect(updatecm.Data["file"]).To(Equal("test"))
 }
 
+func TestConfigMapControlUpdateConfigMapEvent(t *testing.T) {
+	g := NewGomegaWithT(t)
+	recorder := record.NewFakeRecorder(10)
+	tc := newTidbCluster()
+	cm := newConfigMap()
+	cm.Data = map[string]string{"file": "new-value", "added": "added-value", "unchanged": "value"}
+	oldcm := newConfigMap()
+	oldcm.Data = map[string]string{"file": "old-value", "removed": "removed-value", "unchanged": "value"}
+	fakeClient := &fake.Clientset{}
+	control := NewRealConfigMapControl(fakeClient, recorder)
+	fakeClient.AddReactor("get", "configmaps", func(action core.Action) (bool, runtime.Object, error) {
+		return true, oldcm, nil
+	})
+	fakeClient.AddReactor("update", "configmaps", func(action core.Action) (bool, runtime.Object, error) {
+		update := action.(core.UpdateAction)
+		return true, update.GetObject(), nil
+	})
+	_, err := control.UpdateConfigMap(tc, cm)
+	g.Expect(err).To(Succeed())
+
+	events := collectEvents(recorder.Events)
+	g.Expect(events).To(HaveLen(1))
+	g.Expect(events[0]).To(ContainSubstring(corev1.EventTypeNormal))
+	g.Expect(events[0]).To(ContainSubstring("changed keys: file; added keys: added; removed keys: removed"))
+	for _, value := range []string{"new-value", "old-value", "added-value", "removed-value"} {
+		g.Expect(events[0]).NotTo(ContainSubstring(value))
+	}
+}
+
 func TestConfigMapControlDeleteConfigMap(t *testing.T) {
 	g := NewGomegaWithT(t)
 	recorder := record.NewFakeRecorder(10)
@@ -140,6 +226,171 @@ func TestConfigMapControlDeleteConfigMapFailed(t *testing.T) {
 	g.Expect(events[0]).To(ContainSubstring(corev1.EventTypeWarning))
 }
 
+func TestSplitConfigMap(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// a config spanning two partitions, with multi-byte characters at the boundary
+	config := strings.Repeat("a", ConfigMapPartitionSize-100) + strings.Repeat("中", ConfigMapPartitionSize/6)
+	cm := newConfigMap()
+	cm.Data = map[string]string{
+		"config-file":    config,
+		"startup-script": "#!/bin/sh",
+	}
+
+	index, parts, err := SplitConfigMap(cm, ConfigMapPartitionSize)
+	g.Expect(err).To(Succeed())
+	g.Expect(parts).To(HaveLen(2))
+	g.Expect(index.Name).To(Equal("test"))
+	g.Expect(index.Annotations).To(HaveKeyWithValue(ConfigMapPartitionsAnnKey, "2"))
+	for i, part := range parts {
+		g.Expect(part.Name).To(Equal(ConfigMapPartitionName("test", i)))
+		size := 0
+		for k, v := range part.Data {
+			g.Expect(utf8.ValidString(v)).To(BeTrue())
+			size += len(k) + len(v)
+		}
+		g.Expect(size).To(BeNumerically("<=", ConfigMapPartitionSize))
+	}
+
+	merged, err := MergeConfigMap(index, parts)
+	g.Expect(err).To(Succeed())
+	g.Expect(merged.Data).To(Equal(cm.Data))
+	g.Expect(merged.Annotations).NotTo(HaveKey(ConfigMapPartitionsAnnKey))
+
+	_, err = MergeConfigMap(index, parts[:1])
+	g.Expect(err).To(HaveOccurred())
+
+	// the ConfigMap fitting in a partition is not split
+	small := newConfigMap()
+	small.Data = map[string]string{"config-file": "small"}
+	index, parts, err = SplitConfigMap(small, ConfigMapPartitionSize)
+	g.Expect(err).To(Succeed())
+	g.Expect(parts).To(BeEmpty())
+	g.Expect(index).To(Equal(small))
+}
+
+func TestConfigMapControlPartitionedConfigMap(t *testing.T) {
+	g := NewGomegaWithT(t)
+	recorder := record.NewFakeRecorder(100)
+	tc := newTidbCluster()
+	fakeClient := fake.NewSimpleClientset()
+	control := NewRealConfigMapControl(fakeClient, recorder)
+
+	cm := newConfigMap()
+	cm.Data = map[string]string{
+		"config-file": strings.Repeat("a", ConfigMapPartitionSize+1024),
+	}
+	g.Expect(control.ApplyPartitionedConfigMap(tc, cm)).To(Succeed())
+	cms, err := fakeClient.CoreV1().ConfigMaps("default").List(context.TODO(), metav1.ListOptions{})
+	g.Expect(err).To(Succeed())
+	g.Expect(cms.Items).To(HaveLen(3))
+	merged, err := control.GetConfigMapMerged(tc, newConfigMap())
+	g.Expect(err).To(Succeed())
+	g.Expect(merged.Data).To(Equal(cm.Data))
+
+	// the stale partitions are deleted when the ConfigMap shrinks
+	cm.Data = map[string]string{"config-file": "small"}
+	g.Expect(control.ApplyPartitionedConfigMap(tc, cm)).To(Succeed())
+	cms, err = fakeClient.CoreV1().ConfigMaps("default").List(context.TODO(), metav1.ListOptions{})
+	g.Expect(err).To(Succeed())
+	g.Expect(cms.Items).To(HaveLen(1))
+	merged, err = control.GetConfigMapMerged(tc, newConfigMap())
+	g.Expect(err).To(Succeed())
+	g.Expect(merged.Data).To(Equal(cm.Data))
+}
+
+func TestConfigMapControlResyncConfigMap(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTidbCluster()
+
+	live := newConfigMap()
+	live.Data = map[string]string{"config-file": "live"}
+	cached := newConfigMap()
+	cached.Data = map[string]string{"config-file": "cached"}
+	kubeCli := fake.NewSimpleClientset(live)
+
+	control := NewRealConfigMapControl(kubeCli, record.NewFakeRecorder(10))
+	cm, err := control.ResyncConfigMap(tc, "test", "default")
+	g.Expect(err).To(Succeed())
+	g.Expect(cm.Data).To(Equal(live.Data))
+
+	// the fake refreshes the older copy in its cache
+	informerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	fakeControl := NewFakeConfigMapControl(kubeCli, informerFactory.Core().V1().ConfigMaps())
+	g.Expect(fakeControl.CmIndexer.Add(cached)).To(Succeed())
+	cm, err = fakeControl.ResyncConfigMap(tc, "test", "default")
+	g.Expect(err).To(Succeed())
+	g.Expect(cm.Data).To(Equal(live.Data))
+	obj, exist, err := fakeControl.CmIndexer.GetByKey("default/test")
+	g.Expect(err).To(Succeed())
+	g.Expect(exist).To(BeTrue())
+	g.Expect(obj.(*corev1.ConfigMap).Data).To(Equal(live.Data))
+
+	_, err = fakeControl.ResyncConfigMap(tc, "not-exist", "default")
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestConfigMapControlRenderConfigMap(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	spec := map[string]string{"config": "v1"}
+	renders := 0
+	render := func() (*corev1.ConfigMap, error) {
+		renders++
+		cm := newConfigMap()
+		cm.Data["config-file"] = spec["config"]
+		return cm, nil
+	}
+
+	for _, control := range []ConfigMapControlInterface{
+		NewRealConfigMapControl(&fake.Clientset{}, record.NewFakeRecorder(10)),
+		NewFakeConfigMapControl(fake.NewSimpleClientset(), kubeinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Core().V1().ConfigMaps()),
+	} {
+		renders = 0
+		spec["config"] = "v1"
+
+		// the ConfigMap is rendered once across the syncs of the same spec
+		cm, err := control.RenderConfigMap("default/test", spec, render)
+		g.Expect(err).To(Succeed())
+		g.Expect(cm.Data["config-file"]).To(Equal("v1"))
+		cm.Name = "test-modified"
+		cm, err = control.RenderConfigMap("default/test", spec, render)
+		g.Expect(err).To(Succeed())
+		g.Expect(renders).To(Equal(1))
+		g.Expect(cm.Name).To(Equal("test"))
+		g.Expect(cm.Data["config-file"]).To(Equal("v1"))
+
+		// the ConfigMap is rendered again after the spec changes
+		spec["config"] = "v2"
+		cm, err = control.RenderConfigMap("default/test", spec, render)
+		g.Expect(err).To(Succeed())
+		g.Expect(renders).To(Equal(2))
+		g.Expect(cm.Data["config-file"]).To(Equal("v2"))
+
+		// the ConfigMaps are cached by key
+		_, err = control.RenderConfigMap("default/other", spec, render)
+		g.Expect(err).To(Succeed())
+		g.Expect(renders).To(Equal(3))
+
+		// a failed rendering isn't cached
+		spec["config"] = "v3"
+		_, err = control.RenderConfigMap("default/test", spec, func() (*corev1.ConfigMap, error) {
+			return nil, errors.New("render failed")
+		})
+		g.Expect(err).To(HaveOccurred())
+		cm, err = control.RenderConfigMap("default/test", spec, render)
+		g.Expect(err).To(Succeed())
+		g.Expect(renders).To(Equal(4))
+		g.Expect(cm.Data["config-file"]).To(Equal("v3"))
+
+		// the ConfigMap is rendered again after it's forgotten
+		control.ForgetRenderedConfigMap("default/test")
+		_, err = control.RenderConfigMap("default/test", spec, render)
+		g.Expect(err).To(Succeed())
+		g.Expect(renders).To(Equal(5))
+	}
+}
+
 func newConfigMap() *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
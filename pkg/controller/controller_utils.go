@@ -413,6 +413,25 @@ func setIfNotEmpty(container map[string]string, key, value string) {
 	}
 }
 
+// setOrDelete set the value into map when value is not empty, and delete the key otherwise
+func setOrDelete(container map[string]string, key, value string) {
+	if value != "" {
+		container[key] = value
+	} else {
+		delete(container, key)
+	}
+}
+
+// hasLabels returns whether all the labels are in the container
+func hasLabels(container, labels map[string]string) bool {
+	for k, v := range labels {
+		if container[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
 // RequestTracker is used by unit test for mocking request error
 type RequestTracker struct {
 	requests int
@@ -15,6 +15,7 @@ package controller
 
 import (
 	"flag"
+	"strings"
 	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -38,6 +39,7 @@ import (
 	corelisterv1 "k8s.io/client-go/listers/core/v1"
 	extensionslister "k8s.io/client-go/listers/extensions/v1beta1"
 	storagelister "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -54,6 +56,10 @@ type CLIConfig struct {
 	// Controls whether operator should manage kubernetes cluster
 	// wide TiDB clusters
 	ClusterScoped bool
+	// WatchNamespaces is a comma-separated list of the namespaces watched by
+	// tidb-operator if it's not cluster scoped, defaults to the namespace of
+	// tidb-operator
+	WatchNamespaces string
 
 	ClusterPermissionNode bool
 	ClusterPermissionPV   bool
@@ -83,28 +89,76 @@ type CLIConfig struct {
 	// Selector is used to filter CR labels to decide
 	// what resources should be watched and synced by controller
 	Selector string
+	// PVExtraLabelKeys is a comma-separated list of PVC label keys
+	// which are synced to PV in addition to the ones managed by operator
+	PVExtraLabelKeys string
+	// EventBudgetPerSync is the max number of events emitted for an object
+	// in a sync, the excess events are suppressed. 0 means no limit
+	EventBudgetPerSync int
+	// EventDedupWindow is the window in which the identical events of an object
+	// are emitted once, the suppressed ones are counted. 0 means no deduplication
+	EventDedupWindow time.Duration
+	// PDStatusSyncStaleThreshold is the duration after which the PD status is
+	// considered stale if it can't be synced from the PD cluster. 0 means never
+	PDStatusSyncStaleThreshold time.Duration
+	// PDFailoverRecoveryCooldown is the duration after the failover recovery during which
+	// the failover of the recovered PD members is suppressed. 0 means no cooldown
+	PDFailoverRecoveryCooldown time.Duration
+	// PDNodeNotReadyTimeout is the max duration the PD failover is deferred while the node of
+	// the unhealthy PD member is NotReady, as the member may come back with the node. 0 means no deferral
+	PDNodeNotReadyTimeout time.Duration
+	// FailoverMinHealthyRatio is the min ratio of the healthy PD members and TiKV stores
+	// in a cluster, the failover of PD and TiKV is refused below it. 0 means no limit
+	FailoverMinHealthyRatio float64
+	// MaxConcurrentFailoversPerNamespace is the max number of failovers in flight
+	// at the same time in a namespace, the excess ones are requeued. 0 means no limit
+	MaxConcurrentFailoversPerNamespace int
+	// FailoverSummaryInterval is the interval of the event summarizing the failovers
+	// of a tidb cluster. 0 means no summary
+	FailoverSummaryInterval time.Duration
+	// FailoverWebhookURL is the URL the notifications of the failovers are posted to,
+	// the failures of posting are only logged. Empty means no notification
+	FailoverWebhookURL string
+	// ClusterClientCertTemplate is the template of the name of the secret of the client certificate the operator
+	// uses to access the tidb clusters with TLS enabled, {cluster} is replaced by the name of the cluster. The
+	// <cluster>-cluster-client-secret is used if it's empty or the secret doesn't exist
+	ClusterClientCertTemplate string
+	// WorkerTransferTimeout is the max duration the upgrade of a dm-worker waits for
+	// its bound source to be transferred to another dm-worker
+	WorkerTransferTimeout time.Duration
+	// FinalizerStuckTimeout is the duration after which the cleanup of a tidb cluster being
+	// deleted is considered stuck, and warning events are emitted on the failures of the cleanup
+	FinalizerStuckTimeout time.Duration
 }
 
 // DefaultCLIConfig returns the default command line configuration
 func DefaultCLIConfig() *CLIConfig {
 	return &CLIConfig{
-		Workers:                5,
-		ClusterScoped:          true,
-		AutoFailover:           true,
-		PDFailoverPeriod:       5 * time.Minute,
-		TiKVFailoverPeriod:     5 * time.Minute,
-		TiDBFailoverPeriod:     5 * time.Minute,
-		TiFlashFailoverPeriod:  5 * time.Minute,
-		MasterFailoverPeriod:   5 * time.Minute,
-		WorkerFailoverPeriod:   5 * time.Minute,
-		LeaseDuration:          15 * time.Second,
-		RenewDeadline:          10 * time.Second,
-		RetryPeriod:            2 * time.Second,
-		WaitDuration:           5 * time.Second,
-		ResyncDuration:         30 * time.Second,
-		TiDBBackupManagerImage: "pingcap/tidb-backup-manager:latest",
-		TiDBDiscoveryImage:     "pingcap/tidb-operator:latest",
-		Selector:               "",
+		Workers:                    5,
+		ClusterScoped:              true,
+		AutoFailover:               true,
+		PDFailoverPeriod:           5 * time.Minute,
+		TiKVFailoverPeriod:         5 * time.Minute,
+		TiDBFailoverPeriod:         5 * time.Minute,
+		TiFlashFailoverPeriod:      5 * time.Minute,
+		MasterFailoverPeriod:       5 * time.Minute,
+		WorkerFailoverPeriod:       5 * time.Minute,
+		WorkerTransferTimeout:      5 * time.Minute,
+		LeaseDuration:              15 * time.Second,
+		RenewDeadline:              10 * time.Second,
+		RetryPeriod:                2 * time.Second,
+		WaitDuration:               5 * time.Second,
+		ResyncDuration:             30 * time.Second,
+		TiDBBackupManagerImage:     "pingcap/tidb-backup-manager:latest",
+		TiDBDiscoveryImage:         "pingcap/tidb-operator:latest",
+		Selector:                   "",
+		EventBudgetPerSync:         DefaultEventBudgetPerSync,
+		EventDedupWindow:           DefaultEventDedupWindow,
+		PDStatusSyncStaleThreshold: 10 * time.Minute,
+		PDFailoverRecoveryCooldown: 5 * time.Minute,
+		PDNodeNotReadyTimeout:      5 * time.Minute,
+		FailoverSummaryInterval:    24 * time.Hour,
+		FinalizerStuckTimeout:      10 * time.Minute,
 	}
 }
 
@@ -114,6 +168,7 @@ func (c *CLIConfig) AddFlag(_ *flag.FlagSet) {
 	flag.BoolVar(&c.PrintVersion, "version", false, "Show version and quit")
 	flag.IntVar(&c.Workers, "workers", c.Workers, "The number of workers that are allowed to sync concurrently. Larger number = more responsive management, but more CPU (and network) load")
 	flag.BoolVar(&c.ClusterScoped, "cluster-scoped", c.ClusterScoped, "Whether tidb-operator should manage kubernetes cluster wide TiDB Clusters")
+	flag.StringVar(&c.WatchNamespaces, "watch-namespaces", c.WatchNamespaces, "Comma-separated list of the namespaces watched by tidb-operator if cluster-scoped is false, defaults to the namespace of tidb-operator")
 	flag.BoolVar(&c.ClusterPermissionNode, "cluster-permission-node", c.ClusterPermissionNode, "Whether tidb-operator should have node permissions even if cluster-scoped is false")
 	flag.BoolVar(&c.ClusterPermissionPV, "cluster-permission-pv", c.ClusterPermissionPV, "Whether tidb-operator should have persistent volume permissions even if cluster-scoped is false")
 	flag.BoolVar(&c.ClusterPermissionSC, "cluster-permission-sc", c.ClusterPermissionSC, "Whether tidb-operator should have storage class permissions even if cluster-scoped is false")
@@ -124,6 +179,7 @@ func (c *CLIConfig) AddFlag(_ *flag.FlagSet) {
 	flag.DurationVar(&c.TiDBFailoverPeriod, "tidb-failover-period", c.TiDBFailoverPeriod, "TiDB failover period")
 	flag.DurationVar(&c.MasterFailoverPeriod, "dm-master-failover-period", c.MasterFailoverPeriod, "dm-master failover period")
 	flag.DurationVar(&c.WorkerFailoverPeriod, "dm-worker-failover-period", c.WorkerFailoverPeriod, "dm-worker failover period")
+	flag.DurationVar(&c.WorkerTransferTimeout, "dm-worker-transfer-source-timeout", c.WorkerTransferTimeout, "The max duration the upgrade of a dm-worker waits for its bound source to be transferred to another dm-worker")
 	flag.DurationVar(&c.ResyncDuration, "resync-duration", c.ResyncDuration, "Resync time of informer")
 	flag.BoolVar(&c.TestMode, "test-mode", false, "whether tidb-operator run in test mode")
 	flag.StringVar(&c.TiDBBackupManagerImage, "tidb-backup-manager-image", c.TiDBBackupManagerImage, "The image of backup manager tool")
@@ -131,6 +187,18 @@ func (c *CLIConfig) AddFlag(_ *flag.FlagSet) {
 	flag.StringVar(&c.TiDBDiscoveryImage, "tidb-discovery-image", c.TiDBDiscoveryImage, "The image of the tidb discovery service")
 	flag.BoolVar(&c.PodWebhookEnabled, "pod-webhook-enabled", false, "Whether Pod admission webhook is enabled")
 	flag.StringVar(&c.Selector, "selector", c.Selector, "Selector (label query) to filter on, supports '=', '==', and '!='")
+	flag.StringVar(&c.PVExtraLabelKeys, "pv-extra-label-keys", c.PVExtraLabelKeys, "Comma-separated list of PVC label keys that are synced to PV in addition to the labels managed by tidb-operator")
+	flag.DurationVar(&c.PDStatusSyncStaleThreshold, "pd-status-sync-stale-threshold", c.PDStatusSyncStaleThreshold, "The duration after which the PD status is considered stale if it can't be synced from the PD cluster, 0 means never")
+	flag.DurationVar(&c.PDFailoverRecoveryCooldown, "pd-failover-recovery-cooldown", c.PDFailoverRecoveryCooldown, "The duration after the PD failover recovery during which the failover of the recovered PD members is suppressed, 0 means no cooldown")
+	flag.DurationVar(&c.PDNodeNotReadyTimeout, "pd-node-not-ready-timeout", c.PDNodeNotReadyTimeout, "The max duration the PD failover is deferred while the node of the unhealthy PD member is NotReady, 0 means no deferral")
+	flag.Float64Var(&c.FailoverMinHealthyRatio, "failover-min-healthy-ratio", c.FailoverMinHealthyRatio, "The min ratio of the healthy PD members and TiKV stores in a cluster, the failover of PD and TiKV is refused below it, 0 means no limit")
+	flag.IntVar(&c.MaxConcurrentFailoversPerNamespace, "max-concurrent-failovers-per-namespace", c.MaxConcurrentFailoversPerNamespace, "The max number of failovers in flight at the same time in a namespace, the excess ones are requeued, 0 means no limit")
+	flag.DurationVar(&c.FailoverSummaryInterval, "failover-summary-interval", c.FailoverSummaryInterval, "The interval of the event summarizing the failovers of a TidbCluster, 0 means no summary")
+	flag.StringVar(&c.FailoverWebhookURL, "failover-webhook-url", c.FailoverWebhookURL, "The URL the notifications of the failovers are posted to in JSON, empty means no notification")
+	flag.StringVar(&c.ClusterClientCertTemplate, "cluster-client-cert-template", c.ClusterClientCertTemplate, "The template of the name of the secret of the client certificate used to access the TiDB clusters with TLS enabled, e.g. {cluster}-operator-client-secret, {cluster} is replaced by the name of the cluster, the <cluster>-cluster-client-secret is used if it's empty or the secret doesn't exist")
+	flag.DurationVar(&c.FinalizerStuckTimeout, "finalizer-stuck-timeout", c.FinalizerStuckTimeout, "The duration after which the cleanup of a TidbCluster being deleted is considered stuck, the finalizer can be removed by force with the annotation tidb.pingcap.com/force-remove-finalizer then")
+	flag.IntVar(&c.EventBudgetPerSync, "event-budget-per-sync", c.EventBudgetPerSync, "The max number of events emitted for an object in a sync, the excess events are suppressed with a summary event, 0 means no limit")
+	flag.DurationVar(&c.EventDedupWindow, "event-dedup-window", c.EventDedupWindow, "The window in which the identical events of an object are emitted once, the count of the suppressed ones is appended to the first event after the window, 0 means no deduplication")
 
 	// see https://pkg.go.dev/k8s.io/client-go/tools/leaderelection#LeaderElectionConfig for the config
 	flag.DurationVar(&c.LeaseDuration, "leader-lease-duration", c.LeaseDuration, "leader-lease-duration is the duration that non-leader candidates will wait to force acquire leadership")
@@ -138,6 +206,34 @@ func (c *CLIConfig) AddFlag(_ *flag.FlagSet) {
 	flag.DurationVar(&c.RetryPeriod, "leader-retry-period", c.RetryPeriod, "leader-retry-period is the duration the LeaderElector clients should wait between tries of actions")
 }
 
+// GetPVExtraLabelKeys returns the PVC label keys which should be synced to PV
+func (c *CLIConfig) GetPVExtraLabelKeys() []string {
+	var keys []string
+	for _, key := range strings.Split(c.PVExtraLabelKeys, ",") {
+		if key = strings.TrimSpace(key); len(key) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// GetWatchNamespaces returns the namespaces watched by tidb-operator if it's not cluster scoped,
+// the namespace of tidb-operator is returned if --watch-namespaces is not set
+func (c *CLIConfig) GetWatchNamespaces(ns string) []string {
+	var namespaces []string
+	seen := map[string]bool{}
+	for _, namespace := range strings.Split(c.WatchNamespaces, ",") {
+		if namespace = strings.TrimSpace(namespace); len(namespace) > 0 && !seen[namespace] {
+			seen[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	if len(namespaces) == 0 {
+		return []string{ns}
+	}
+	return namespaces
+}
+
 // HasNodePermission returns whether the user has permission for node operations.
 func (c *CLIConfig) HasNodePermission() bool {
 	return c.ClusterScoped || c.ClusterPermissionNode
@@ -190,6 +286,16 @@ type Dependencies struct {
 	LabelFilterKubeInformerFactory kubeinformers.SharedInformerFactory
 	Recorder                       record.EventRecorder
 
+	// WatchNamespaces are the namespaces watched by tidb-operator, empty if it's cluster scoped.
+	WatchNamespaces []string
+	// InformerFactories, KubeInformerFactories and LabelFilterKubeInformerFactories are the informer
+	// factories of the watched namespaces in order, or the cluster-wide ones if tidb-operator is cluster
+	// scoped. InformerFactory, KubeInformerFactory and LabelFilterKubeInformerFactory are the first of them.
+	// The listers below read from all of them, use Informers and KubeInformers to watch the resources.
+	InformerFactories                []informers.SharedInformerFactory
+	KubeInformerFactories            []kubeinformers.SharedInformerFactory
+	LabelFilterKubeInformerFactories []kubeinformers.SharedInformerFactory
+
 	// Listers
 	ServiceLister               corelisterv1.ServiceLister
 	EndpointLister              corelisterv1.EndpointsLister
@@ -215,6 +321,47 @@ type Dependencies struct {
 
 	// Controls
 	Controls
+
+	// FailoverPreDeleteHook is invoked before failover deletes a failure member and its Pod and PVCs,
+	// a non-nil error aborts the failover of the member. Nil means no check.
+	FailoverPreDeleteHook FailoverPreDeleteHook
+}
+
+// FailoverPreDeleteHook checks whether the failure member of the Pod can be deleted by failover,
+// e.g. by an external policy engine, and returns a non-nil error to veto the deletion.
+type FailoverPreDeleteHook func(tc *v1alpha1.TidbCluster, podName string) error
+
+// Informers returns the informers of a resource in all the informer factories,
+// informerFor returns the informer of the resource in a factory.
+func (deps *Dependencies) Informers(informerFor func(informers.SharedInformerFactory) cache.SharedIndexInformer) []cache.SharedIndexInformer {
+	var result []cache.SharedIndexInformer
+	for _, f := range deps.InformerFactories {
+		result = append(result, informerFor(f))
+	}
+	return result
+}
+
+// KubeInformers returns the informers of a resource in all the kube informer factories,
+// informerFor returns the informer of the resource in a factory.
+func (deps *Dependencies) KubeInformers(informerFor func(kubeinformers.SharedInformerFactory) cache.SharedIndexInformer) []cache.SharedIndexInformer {
+	var result []cache.SharedIndexInformer
+	for _, f := range deps.KubeInformerFactories {
+		result = append(result, informerFor(f))
+	}
+	return result
+}
+
+// fanOutIndexerOf returns the indexer over the informers of the watched namespaces,
+// the indexer of the informer is returned as is if there is only one.
+func fanOutIndexerOf(namespaces []string, informers []cache.SharedIndexInformer) cache.Indexer {
+	if len(informers) == 1 {
+		return informers[0].GetIndexer()
+	}
+	indexers := make([]cache.Indexer, 0, len(informers))
+	for _, informer := range informers {
+		indexers = append(indexers, informer.GetIndexer())
+	}
+	return NewFanOutIndexer(namespaces, indexers)
 }
 
 func newRealControls(
@@ -222,34 +369,30 @@ func newRealControls(
 	clientset versioned.Interface,
 	kubeClientset kubernetes.Interface,
 	genericCli client.Client,
-	informerFactory informers.SharedInformerFactory,
-	kubeInformerFactory kubeinformers.SharedInformerFactory,
+	deps *Dependencies,
 	recorder record.EventRecorder) Controls {
 	// Shared variables to construct `Dependencies` and some of its fields
 	var (
-		pdControl         = pdapi.NewDefaultPDControl(kubeClientset)
-		tikvControl       = tikvapi.NewDefaultTiKVControl(kubeClientset)
-		tiflashControl    = tiflashapi.NewDefaultTiFlashControl(kubeClientset)
+		pdControl         = pdapi.NewDefaultPDControlWithClientTLSSecret(kubeClientset, cliCfg.ClusterClientCertTemplate)
+		tikvControl       = tikvapi.NewDefaultTiKVControlWithClientTLSSecret(kubeClientset, cliCfg.ClusterClientCertTemplate)
+		tiflashControl    = tiflashapi.NewDefaultTiFlashControlWithClientTLSSecret(kubeClientset, cliCfg.ClusterClientCertTemplate)
 		masterControl     = dmapi.NewDefaultMasterControl(kubeClientset)
 		genericCtrl       = NewRealGenericControl(genericCli, recorder)
-		tidbClusterLister = informerFactory.Pingcap().V1alpha1().TidbClusters().Lister()
-		dmClusterLister   = informerFactory.Pingcap().V1alpha1().DMClusters().Lister()
-		statefulSetLister = kubeInformerFactory.Apps().V1().StatefulSets().Lister()
-		serviceLister     = kubeInformerFactory.Core().V1().Services().Lister()
-		pvcLister         = kubeInformerFactory.Core().V1().PersistentVolumeClaims().Lister()
-		podLister         = kubeInformerFactory.Core().V1().Pods().Lister()
-		pvLister          corelisterv1.PersistentVolumeLister
+		tidbClusterLister = deps.TiDBClusterLister
+		dmClusterLister   = deps.DMClusterLister
+		statefulSetLister = deps.StatefulSetLister
+		serviceLister     = deps.ServiceLister
+		pvcLister         = deps.PVCLister
+		podLister         = deps.PodLister
+		pvLister          = deps.PVLister
 	)
-	if cliCfg.HasPVPermission() {
-		pvLister = kubeInformerFactory.Core().V1().PersistentVolumes().Lister()
-	}
 
 	return Controls{
 		JobControl:         NewRealJobControl(kubeClientset, recorder),
 		ConfigMapControl:   NewRealConfigMapControl(kubeClientset, recorder),
 		StatefulSetControl: NewRealStatefuSetControl(kubeClientset, statefulSetLister, recorder),
 		ServiceControl:     NewRealServiceControl(kubeClientset, serviceLister, recorder),
-		PVControl:          NewRealPVControl(kubeClientset, pvcLister, pvLister, recorder),
+		PVControl:          NewRealPVControl(kubeClientset, pvcLister, pvLister, recorder, cliCfg.GetPVExtraLabelKeys()),
 		PVCControl:         NewRealPVCControl(kubeClientset, recorder, pvcLister),
 		GeneralPVCControl:  NewRealGeneralPVCControl(kubeClientset, recorder),
 		GenericControl:     genericCtrl,
@@ -272,11 +415,15 @@ func newDependencies(
 	clientset versioned.Interface,
 	kubeClientset kubernetes.Interface,
 	genericCli client.Client,
-	informerFactory informers.SharedInformerFactory,
-	kubeInformerFactory kubeinformers.SharedInformerFactory,
-	labelFilterKubeInformerFactory kubeinformers.SharedInformerFactory,
+	namespaces []string,
+	informerFactories []informers.SharedInformerFactory,
+	kubeInformerFactories []kubeinformers.SharedInformerFactory,
+	labelFilterKubeInformerFactories []kubeinformers.SharedInformerFactory,
 	recorder record.EventRecorder) *Dependencies {
 
+	// the cluster scoped resources are watched by the first kube informer factory only,
+	// the namespace of a factory doesn't apply to them
+	kubeInformerFactory := kubeInformerFactories[0]
 	var (
 		nodeLister corelisterv1.NodeLister
 		pvLister   corelisterv1.PersistentVolumeLister
@@ -298,51 +445,94 @@ func newDependencies(
 		klog.Info("no permission for storage classes, skip creating sc lister")
 	}
 
-	return &Dependencies{
-		CLIConfig:                      cliCfg,
-		InformerFactory:                informerFactory,
-		Clientset:                      clientset,
-		KubeClientset:                  kubeClientset,
-		GenericClient:                  genericCli,
-		KubeInformerFactory:            kubeInformerFactory,
-		LabelFilterKubeInformerFactory: labelFilterKubeInformerFactory,
-		Recorder:                       recorder,
-
-		// Listers
-		ServiceLister:               kubeInformerFactory.Core().V1().Services().Lister(),
-		EndpointLister:              kubeInformerFactory.Core().V1().Endpoints().Lister(),
-		PVCLister:                   kubeInformerFactory.Core().V1().PersistentVolumeClaims().Lister(),
-		PVLister:                    pvLister,
-		PodLister:                   kubeInformerFactory.Core().V1().Pods().Lister(),
-		NodeLister:                  nodeLister,
-		SecretLister:                kubeInformerFactory.Core().V1().Secrets().Lister(),
-		ConfigMapLister:             labelFilterKubeInformerFactory.Core().V1().ConfigMaps().Lister(),
-		StatefulSetLister:           kubeInformerFactory.Apps().V1().StatefulSets().Lister(),
-		DeploymentLister:            kubeInformerFactory.Apps().V1().Deployments().Lister(),
-		StorageClassLister:          scLister,
-		JobLister:                   kubeInformerFactory.Batch().V1().Jobs().Lister(),
-		IngressLister:               kubeInformerFactory.Extensions().V1beta1().Ingresses().Lister(),
-		TiDBClusterLister:           informerFactory.Pingcap().V1alpha1().TidbClusters().Lister(),
-		TiDBClusterAutoScalerLister: informerFactory.Pingcap().V1alpha1().TidbClusterAutoScalers().Lister(),
-		DMClusterLister:             informerFactory.Pingcap().V1alpha1().DMClusters().Lister(),
-		BackupLister:                informerFactory.Pingcap().V1alpha1().Backups().Lister(),
-		RestoreLister:               informerFactory.Pingcap().V1alpha1().Restores().Lister(),
-		BackupScheduleLister:        informerFactory.Pingcap().V1alpha1().BackupSchedules().Lister(),
-		TiDBInitializerLister:       informerFactory.Pingcap().V1alpha1().TidbInitializers().Lister(),
-		TiDBMonitorLister:           informerFactory.Pingcap().V1alpha1().TidbMonitors().Lister(),
+	deps := &Dependencies{
+		CLIConfig:                        cliCfg,
+		InformerFactory:                  informerFactories[0],
+		Clientset:                        clientset,
+		KubeClientset:                    kubeClientset,
+		GenericClient:                    genericCli,
+		KubeInformerFactory:              kubeInformerFactory,
+		LabelFilterKubeInformerFactory:   labelFilterKubeInformerFactories[0],
+		Recorder:                         recorder,
+		WatchNamespaces:                  namespaces,
+		InformerFactories:                informerFactories,
+		KubeInformerFactories:            kubeInformerFactories,
+		LabelFilterKubeInformerFactories: labelFilterKubeInformerFactories,
+
+		PVLister:           pvLister,
+		NodeLister:         nodeLister,
+		StorageClassLister: scLister,
+	}
+
+	// Listers of the namespaced resources, which read from the informers of all the watched namespaces
+	pingcapIndexer := func(informerFor func(informers.SharedInformerFactory) cache.SharedIndexInformer) cache.Indexer {
+		return fanOutIndexerOf(namespaces, deps.Informers(informerFor))
 	}
+	kubeIndexer := func(informerFor func(kubeinformers.SharedInformerFactory) cache.SharedIndexInformer) cache.Indexer {
+		return fanOutIndexerOf(namespaces, deps.KubeInformers(informerFor))
+	}
+	var labelFilterConfigMapInformers []cache.SharedIndexInformer
+	for _, f := range labelFilterKubeInformerFactories {
+		labelFilterConfigMapInformers = append(labelFilterConfigMapInformers, f.Core().V1().ConfigMaps().Informer())
+	}
+
+	deps.ServiceLister = corelisterv1.NewServiceLister(kubeIndexer(func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().Services().Informer()
+	}))
+	deps.EndpointLister = corelisterv1.NewEndpointsLister(kubeIndexer(func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().Endpoints().Informer()
+	}))
+	deps.PVCLister = corelisterv1.NewPersistentVolumeClaimLister(kubeIndexer(func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().PersistentVolumeClaims().Informer()
+	}))
+	deps.PodLister = corelisterv1.NewPodLister(kubeIndexer(func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().Pods().Informer()
+	}))
+	deps.SecretLister = corelisterv1.NewSecretLister(kubeIndexer(func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().Secrets().Informer()
+	}))
+	deps.ConfigMapLister = corelisterv1.NewConfigMapLister(fanOutIndexerOf(namespaces, labelFilterConfigMapInformers))
+	deps.StatefulSetLister = appslisters.NewStatefulSetLister(kubeIndexer(func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Apps().V1().StatefulSets().Informer()
+	}))
+	deps.DeploymentLister = appslisters.NewDeploymentLister(kubeIndexer(func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Apps().V1().Deployments().Informer()
+	}))
+	deps.JobLister = batchlisters.NewJobLister(kubeIndexer(func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Batch().V1().Jobs().Informer()
+	}))
+	deps.IngressLister = extensionslister.NewIngressLister(kubeIndexer(func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Extensions().V1beta1().Ingresses().Informer()
+	}))
+	deps.TiDBClusterLister = listers.NewTidbClusterLister(pingcapIndexer(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().TidbClusters().Informer()
+	}))
+	deps.TiDBClusterAutoScalerLister = listers.NewTidbClusterAutoScalerLister(pingcapIndexer(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().TidbClusterAutoScalers().Informer()
+	}))
+	deps.DMClusterLister = listers.NewDMClusterLister(pingcapIndexer(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().DMClusters().Informer()
+	}))
+	deps.BackupLister = listers.NewBackupLister(pingcapIndexer(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().Backups().Informer()
+	}))
+	deps.RestoreLister = listers.NewRestoreLister(pingcapIndexer(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().Restores().Informer()
+	}))
+	deps.BackupScheduleLister = listers.NewBackupScheduleLister(pingcapIndexer(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().BackupSchedules().Informer()
+	}))
+	deps.TiDBInitializerLister = listers.NewTidbInitializerLister(pingcapIndexer(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().TidbInitializers().Informer()
+	}))
+	deps.TiDBMonitorLister = listers.NewTidbMonitorLister(pingcapIndexer(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().TidbMonitors().Informer()
+	}))
+	return deps
 }
 
 // NewDependencies is used to construct the dependencies
 func NewDependencies(ns string, cliCfg *CLIConfig, clientset versioned.Interface, kubeClientset kubernetes.Interface, genericCli client.Client) *Dependencies {
-	var (
-		options     []informers.SharedInformerOption
-		kubeoptions []kubeinformers.SharedInformerOption
-	)
-	if !cliCfg.ClusterScoped {
-		options = append(options, informers.WithNamespace(ns))
-		kubeoptions = append(kubeoptions, kubeinformers.WithNamespace(ns))
-	}
 	tweakListOptionsFunc := func(options *metav1.ListOptions) {
 		if len(options.LabelSelector) > 0 {
 			options.LabelSelector += ",app.kubernetes.io/managed-by=tidb-operator"
@@ -350,27 +540,54 @@ func NewDependencies(ns string, cliCfg *CLIConfig, clientset versioned.Interface
 			options.LabelSelector = "app.kubernetes.io/managed-by=tidb-operator"
 		}
 	}
-	labelKubeOptions := append(kubeoptions, kubeinformers.WithTweakListOptions(tweakListOptionsFunc))
+	labelTweakListOptionsFunc := tweakListOptionsFunc
 	tweakListOptionsFunc = func(options *metav1.ListOptions) {
 		if len(cliCfg.Selector) > 0 {
 			options.LabelSelector = cliCfg.Selector
 		}
 	}
-	options = append(options, informers.WithTweakListOptions(tweakListOptionsFunc))
 
-	// Initialize the informer factories
-	informerFactory := informers.NewSharedInformerFactoryWithOptions(clientset, cliCfg.ResyncDuration, options...)
-	kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClientset, cliCfg.ResyncDuration, kubeoptions...)
-	labelFilterKubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClientset, cliCfg.ResyncDuration, labelKubeOptions...)
+	// Initialize the informer factories, one for each of the watched namespaces if tidb-operator
+	// is not cluster scoped, or a cluster-wide one otherwise
+	var namespaces []string
+	if !cliCfg.ClusterScoped {
+		namespaces = cliCfg.GetWatchNamespaces(ns)
+	}
+	var (
+		informerFactories                []informers.SharedInformerFactory
+		kubeInformerFactories            []kubeinformers.SharedInformerFactory
+		labelFilterKubeInformerFactories []kubeinformers.SharedInformerFactory
+	)
+	newInformerFactories := func(namespace string, namespaced bool) {
+		options := []informers.SharedInformerOption{informers.WithTweakListOptions(tweakListOptionsFunc)}
+		var kubeoptions []kubeinformers.SharedInformerOption
+		if namespaced {
+			options = append(options, informers.WithNamespace(namespace))
+			kubeoptions = append(kubeoptions, kubeinformers.WithNamespace(namespace))
+		}
+		labelKubeOptions := append(kubeoptions, kubeinformers.WithTweakListOptions(labelTweakListOptionsFunc))
+		informerFactories = append(informerFactories, informers.NewSharedInformerFactoryWithOptions(clientset, cliCfg.ResyncDuration, options...))
+		kubeInformerFactories = append(kubeInformerFactories, kubeinformers.NewSharedInformerFactoryWithOptions(kubeClientset, cliCfg.ResyncDuration, kubeoptions...))
+		labelFilterKubeInformerFactories = append(labelFilterKubeInformerFactories, kubeinformers.NewSharedInformerFactoryWithOptions(kubeClientset, cliCfg.ResyncDuration, labelKubeOptions...))
+	}
+	if len(namespaces) == 0 {
+		newInformerFactories(metav1.NamespaceAll, false)
+	}
+	for _, namespace := range namespaces {
+		newInformerFactories(namespace, true)
+	}
 
 	// Initialize the event recorder
 	eventBroadcaster := record.NewBroadcasterWithCorrelatorOptions(record.CorrelatorOptions{QPS: 1})
 	eventBroadcaster.StartLogging(klog.V(2).Infof)
 	eventBroadcaster.StartRecordingToSink(&eventv1.EventSinkImpl{
 		Interface: eventv1.New(kubeClientset.CoreV1().RESTClient()).Events("")})
-	recorder := eventBroadcaster.NewRecorder(v1alpha1.Scheme, corev1.EventSource{Component: "tidb-controller-manager"})
-	deps := newDependencies(cliCfg, clientset, kubeClientset, genericCli, informerFactory, kubeInformerFactory, labelFilterKubeInformerFactory, recorder)
-	deps.Controls = newRealControls(cliCfg, clientset, kubeClientset, genericCli, informerFactory, kubeInformerFactory, recorder)
+	// the events are deduplicated before the budget, so that the suppressed duplicates don't use up the budget
+	recorder := NewDedupingRecorder(
+		NewBudgetEventRecorder(eventBroadcaster.NewRecorder(v1alpha1.Scheme, corev1.EventSource{Component: "tidb-controller-manager"}), cliCfg.EventBudgetPerSync),
+		cliCfg.EventDedupWindow)
+	deps := newDependencies(cliCfg, clientset, kubeClientset, genericCli, namespaces, informerFactories, kubeInformerFactories, labelFilterKubeInformerFactories, recorder)
+	deps.Controls = newRealControls(cliCfg, clientset, kubeClientset, genericCli, deps, recorder)
 	return deps
 }
 
@@ -379,7 +596,7 @@ func newFakeControl(kubeClientset kubernetes.Interface, informerFactory informer
 	// Shared variables to construct `Dependencies` and some of its fields
 	return Controls{
 		JobControl:         NewFakeJobControl(kubeInformerFactory.Batch().V1().Jobs()),
-		ConfigMapControl:   NewFakeConfigMapControl(kubeInformerFactory.Core().V1().ConfigMaps()),
+		ConfigMapControl:   NewFakeConfigMapControl(kubeClientset, kubeInformerFactory.Core().V1().ConfigMaps()),
 		StatefulSetControl: NewFakeStatefulSetControl(kubeInformerFactory.Apps().V1().StatefulSets()),
 		ServiceControl:     NewFakeServiceControl(kubeInformerFactory.Core().V1().Services(), kubeInformerFactory.Core().V1().Endpoints()),
 		PVControl:          NewFakePVControl(kubeInformerFactory.Core().V1().PersistentVolumes(), kubeInformerFactory.Core().V1().PersistentVolumeClaims()),
@@ -419,7 +636,11 @@ func NewFakeDependencies() *Dependencies {
 	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
 	labelFilterKubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
 	recorder := record.NewFakeRecorder(100)
-	deps := newDependencies(cliCfg, cli, kubeCli, genCli, informerFactory, kubeInformerFactory, labelFilterKubeInformerFactory, recorder)
+	deps := newDependencies(cliCfg, cli, kubeCli, genCli, nil,
+		[]informers.SharedInformerFactory{informerFactory},
+		[]kubeinformers.SharedInformerFactory{kubeInformerFactory},
+		[]kubeinformers.SharedInformerFactory{labelFilterKubeInformerFactory},
+		recorder)
 	deps.Controls = newFakeControl(kubeCli, informerFactory, kubeInformerFactory)
 	return deps
 }
@@ -50,3 +50,13 @@ func TestFakeTidbCluster(t *testing.T) {
 		}, time.Second*10).Should(BeNil())
 	}
 }
+
+func TestCLIConfigGetWatchNamespaces(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cliCfg := DefaultCLIConfig()
+	g.Expect(cliCfg.GetWatchNamespaces("operator")).To(Equal([]string{"operator"}))
+
+	cliCfg.WatchNamespaces = "ns1, ns2,,ns1,ns3"
+	g.Expect(cliCfg.GetWatchNamespaces("operator")).To(Equal([]string{"ns1", "ns2", "ns3"}))
+}
@@ -19,6 +19,7 @@ import (
 
 	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	mm "github.com/pingcap/tidb-operator/pkg/manager/member"
 	"github.com/pingcap/tidb-operator/pkg/manager/meta"
@@ -28,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
@@ -49,7 +51,7 @@ func NewController(deps *controller.Dependencies) *Controller {
 		control: NewDefaultDMClusterControl(
 			deps.DMClusterControl,
 			mm.NewMasterMemberManager(deps, mm.NewMasterScaler(deps), mm.NewMasterUpgrader(deps), mm.NewMasterFailover(deps)),
-			mm.NewWorkerMemberManager(deps, mm.NewWorkerScaler(deps), mm.NewWorkerFailover(deps)),
+			mm.NewWorkerMemberManager(deps, mm.NewWorkerScaler(deps), mm.NewWorkerUpgrader(deps), mm.NewWorkerFailover(deps)),
 			meta.NewReclaimPolicyManager(deps),
 			mm.NewOrphanPodsCleaner(deps),
 			mm.NewRealPVCCleaner(deps),
@@ -64,22 +66,30 @@ func NewController(deps *controller.Dependencies) *Controller {
 		),
 	}
 
-	dmClusterInformer := deps.InformerFactory.Pingcap().V1alpha1().DMClusters()
-	statefulsetInformer := deps.KubeInformerFactory.Apps().V1().StatefulSets()
-	dmClusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: c.enqueueDMCluster,
-		UpdateFunc: func(old, cur interface{}) {
-			c.enqueueDMCluster(cur)
-		},
-		DeleteFunc: c.enqueueDMCluster,
+	dmClusterInformers := deps.Informers(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().DMClusters().Informer()
 	})
-	statefulsetInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: c.addStatefulSet,
-		UpdateFunc: func(old, cur interface{}) {
-			c.updateStatefulSet(old, cur)
-		},
-		DeleteFunc: c.deleteStatefulSet,
+	statefulsetInformers := deps.KubeInformers(func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Apps().V1().StatefulSets().Informer()
 	})
+	for _, informer := range dmClusterInformers {
+		informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: c.enqueueDMCluster,
+			UpdateFunc: func(old, cur interface{}) {
+				c.enqueueDMCluster(cur)
+			},
+			DeleteFunc: c.enqueueDMCluster,
+		})
+	}
+	for _, informer := range statefulsetInformers {
+		informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: c.addStatefulSet,
+			UpdateFunc: func(old, cur interface{}) {
+				c.updateStatefulSet(old, cur)
+			},
+			DeleteFunc: c.deleteStatefulSet,
+		})
+	}
 	return c
 }
 
@@ -145,7 +155,9 @@ func (c *Controller) sync(key string) error {
 		return err
 	}
 
-	return c.syncDMCluster(dc.DeepCopy())
+	dc = dc.DeepCopy()
+	defer controller.StartEventBudget(c.deps.Recorder, dc)()
+	return c.syncDMCluster(dc)
 }
 
 func (c *Controller) syncDMCluster(dc *v1alpha1.DMCluster) error {
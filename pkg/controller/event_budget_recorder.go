@@ -0,0 +1,151 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+)
+
+const (
+	// DefaultEventBudgetPerSync is the default max number of events emitted for an object in a sync
+	DefaultEventBudgetPerSync = 10
+	// EventsSuppressedReason is the reason of the summary event of the suppressed events
+	EventsSuppressedReason = "EventsSuppressed"
+)
+
+// eventBudget is the event budget of an object in a sync
+type eventBudget struct {
+	object     runtime.Object
+	emitted    int
+	suppressed int
+}
+
+// BudgetEventRecorder is a record.EventRecorder which emits at most budget events for an object
+// in a sync started by StartSync. The excess events are dropped and counted, and a single summary
+// event is emitted for them when the sync finishes. The events of an object out of a sync are not
+// limited.
+type BudgetEventRecorder struct {
+	record.EventRecorder
+	budget int
+
+	lock    sync.Mutex
+	budgets map[string]*eventBudget
+}
+
+// NewBudgetEventRecorder returns a BudgetEventRecorder wrapping recorder,
+// budget <= 0 means no limit
+func NewBudgetEventRecorder(recorder record.EventRecorder, budget int) *BudgetEventRecorder {
+	return &BudgetEventRecorder{
+		EventRecorder: recorder,
+		budget:        budget,
+		budgets:       map[string]*eventBudget{},
+	}
+}
+
+// StartSync starts the event budget of the object for a sync, and returns the function
+// to finish the sync, which emits the summary event of the suppressed events.
+func (r *BudgetEventRecorder) StartSync(object runtime.Object) func() {
+	key, ok := eventBudgetKey(object)
+	if !ok || r.budget <= 0 {
+		return func() {}
+	}
+	r.lock.Lock()
+	r.budgets[key] = &eventBudget{object: object}
+	r.lock.Unlock()
+
+	return func() {
+		r.lock.Lock()
+		b := r.budgets[key]
+		delete(r.budgets, key)
+		r.lock.Unlock()
+
+		if b != nil && b.suppressed > 0 {
+			klog.Warningf("%d events are suppressed in a sync, key: %s", b.suppressed, key)
+			r.EventRecorder.Event(b.object, corev1.EventTypeWarning, EventsSuppressedReason,
+				fmt.Sprintf("%d additional events suppressed", b.suppressed))
+		}
+	}
+}
+
+// allow returns whether an event of the object is allowed by the budget of the current sync
+func (r *BudgetEventRecorder) allow(object runtime.Object) bool {
+	key, ok := eventBudgetKey(object)
+	if !ok {
+		return true
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	b, ok := r.budgets[key]
+	if !ok {
+		return true
+	}
+	if b.emitted >= r.budget {
+		b.suppressed++
+		return false
+	}
+	b.emitted++
+	return true
+}
+
+func (r *BudgetEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if r.allow(object) {
+		r.EventRecorder.Event(object, eventtype, reason, message)
+	}
+}
+
+func (r *BudgetEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if r.allow(object) {
+		r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	}
+}
+
+func (r *BudgetEventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if r.allow(object) {
+		r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	}
+}
+
+var _ record.EventRecorder = &BudgetEventRecorder{}
+
+// StartEventBudget starts the event budget of the object for a sync if the recorder is a
+// BudgetEventRecorder, or a DedupingRecorder wrapping one, and returns the function to finish the sync.
+func StartEventBudget(recorder record.EventRecorder, object runtime.Object) func() {
+	switch r := recorder.(type) {
+	case *BudgetEventRecorder:
+		return r.StartSync(object)
+	case *DedupingRecorder:
+		return StartEventBudget(r.EventRecorder, object)
+	}
+	return func() {}
+}
+
+// eventBudgetKey returns the key of the event budget of the object, which is the UID of the object,
+// or namespace/name if the UID is not set
+func eventBudgetKey(object runtime.Object) (string, bool) {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return "", false
+	}
+	if uid := accessor.GetUID(); uid != "" {
+		return string(uid), true
+	}
+	return fmt.Sprintf("%s/%s", accessor.GetNamespace(), accessor.GetName()), true
+}
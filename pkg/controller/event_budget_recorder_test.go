@@ -0,0 +1,127 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestBudgetEventRecorder(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name         string
+		budget       int
+		events       int
+		expectEvents int
+		// the number of the suppressed events in the summary event, 0 means no summary event
+		expectSuppressed int
+	}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+
+		fakeRecorder := record.NewFakeRecorder(100)
+		recorder := NewBudgetEventRecorder(fakeRecorder, test.budget)
+		tc := newTidbCluster()
+		finish := StartEventBudget(recorder, tc)
+		for i := 0; i < test.events; i++ {
+			recorder.Eventf(tc, corev1.EventTypeWarning, "Unhealthy", "member %d is unhealthy", i)
+		}
+		finish()
+
+		events := collectEvents(fakeRecorder.Events)
+		expectEvents := test.expectEvents
+		if test.expectSuppressed > 0 {
+			expectEvents++
+			g.Expect(events[len(events)-1]).To(Equal(fmt.Sprintf("%s %s %d additional events suppressed",
+				corev1.EventTypeWarning, EventsSuppressedReason, test.expectSuppressed)))
+		}
+		g.Expect(events).To(HaveLen(expectEvents))
+	}
+
+	tests := []testcase{
+		{
+			name:         "events within the budget",
+			budget:       10,
+			events:       10,
+			expectEvents: 10,
+		},
+		{
+			name:             "events exceed the budget",
+			budget:           10,
+			events:           42,
+			expectEvents:     10,
+			expectSuppressed: 32,
+		},
+		{
+			name:         "no limit",
+			budget:       0,
+			events:       42,
+			expectEvents: 42,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i])
+	}
+}
+
+func TestBudgetEventRecorderPerObjectPerSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fakeRecorder := record.NewFakeRecorder(100)
+	recorder := NewBudgetEventRecorder(fakeRecorder, 2)
+	tc1 := newTidbCluster()
+	tc1.UID = types.UID("tc1")
+	tc2 := newTidbCluster()
+	tc2.UID = types.UID("tc2")
+
+	finish1 := recorder.StartSync(tc1)
+	finish2 := recorder.StartSync(tc2)
+	for i := 0; i < 3; i++ {
+		recorder.Event(tc1, corev1.EventTypeNormal, "Synced", "tc1")
+		recorder.Event(tc2, corev1.EventTypeNormal, "Synced", "tc2")
+	}
+	finish1()
+	finish2()
+	// the budget of an object is independent of the other objects
+	g.Expect(collectEvents(fakeRecorder.Events)).To(Equal([]string{
+		"Normal Synced tc1",
+		"Normal Synced tc2",
+		"Normal Synced tc1",
+		"Normal Synced tc2",
+		"Warning EventsSuppressed 1 additional events suppressed",
+		"Warning EventsSuppressed 1 additional events suppressed",
+	}))
+
+	// the budget is reset for the next sync
+	finish1 = recorder.StartSync(tc1)
+	recorder.Event(tc1, corev1.EventTypeNormal, "Synced", "tc1")
+	recorder.Event(tc1, corev1.EventTypeNormal, "Synced", "tc1")
+	finish1()
+	g.Expect(collectEvents(fakeRecorder.Events)).To(HaveLen(2))
+
+	// the events out of a sync are not limited
+	for i := 0; i < 3; i++ {
+		recorder.Event(tc1, corev1.EventTypeNormal, "Synced", "tc1")
+	}
+	g.Expect(collectEvents(fakeRecorder.Events)).To(HaveLen(3))
+}
@@ -0,0 +1,198 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// DefaultEventDedupWindow is the default window in which the identical events of an object are deduplicated
+	DefaultEventDedupWindow = 5 * time.Minute
+	// eventDedupMessagePrefixLen is the length of the prefix of the message by which the events are
+	// considered identical
+	eventDedupMessagePrefixLen = 128
+)
+
+// eventDedupKey identifies the identical events of an object
+type eventDedupKey struct {
+	object        string
+	reason        string
+	messagePrefix string
+}
+
+// eventDedupWindow is the window of the identical events started by the emitted one
+type eventDedupWindow struct {
+	start      time.Time
+	window     time.Duration
+	suppressed int
+	// last is the last event suppressed in the window, it's emitted with the count of the suppressed
+	// events if the window is pruned before another identical event is emitted
+	last *dedupEvent
+}
+
+// dedupEvent is an event to emit by the DedupingRecorder
+type dedupEvent struct {
+	object      runtime.Object
+	annotations map[string]string
+	eventtype   string
+	reason      string
+	message     string
+}
+
+// DedupingRecorder is a record.EventRecorder which emits an event of an object once in a window, the
+// identical events, i.e. the events of the same object and reason with the same message prefix, are
+// dropped and counted until the window rolls over. The first identical event after the window is
+// emitted with " (x N)" appended, N is the number of the events suppressed in the last window. If the
+// events stop recurring, the last suppressed one is emitted with " (x N)" appended once its window is pruned.
+// The window of an event can be set by EventInWindow, e.g. the failover events of a component are
+// deduplicated in the FailoverEventInterval of the component.
+type DedupingRecorder struct {
+	record.EventRecorder
+	window time.Duration
+	now    func() time.Time
+
+	lock      sync.Mutex
+	windows   map[eventDedupKey]*eventDedupWindow
+	lastPrune time.Time
+}
+
+// NewDedupingRecorder returns a DedupingRecorder wrapping recorder,
+// window <= 0 means no deduplication
+func NewDedupingRecorder(recorder record.EventRecorder, window time.Duration) *DedupingRecorder {
+	return &DedupingRecorder{
+		EventRecorder: recorder,
+		window:        window,
+		now:           time.Now,
+		windows:       map[eventDedupKey]*eventDedupWindow{},
+	}
+}
+
+// dedup returns whether the event should be emitted, with the count of the events suppressed in the last
+// window appended to its message, and the summaries of the pruned windows to emit. The identical events
+// are deduplicated in window, or in the window of the recorder if window <= 0.
+func (r *DedupingRecorder) dedup(event *dedupEvent, window time.Duration) (bool, []*dedupEvent) {
+	if window <= 0 {
+		window = r.window
+	}
+	if window <= 0 {
+		return true, nil
+	}
+	objectKey, ok := eventBudgetKey(event.object)
+	if !ok {
+		return true, nil
+	}
+	prefix := event.message
+	if len(prefix) > eventDedupMessagePrefixLen {
+		prefix = prefix[:eventDedupMessagePrefixLen]
+	}
+	key := eventDedupKey{object: objectKey, reason: event.reason, messagePrefix: prefix}
+
+	now := r.now()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	summaries := r.prune(now, window)
+	w, ok := r.windows[key]
+	if ok && now.Sub(w.start) < w.window {
+		w.suppressed++
+		w.last = event
+		return false, summaries
+	}
+	if ok && w.suppressed > 0 {
+		event.message = fmt.Sprintf("%s (x %d)", event.message, w.suppressed)
+	}
+	r.windows[key] = &eventDedupWindow{start: now, window: window}
+	return true, summaries
+}
+
+// prune removes the windows which roll over for more than a window, i.e. the events stop recurring, and
+// returns the last suppressed events of them with the count appended. It runs at most once in the window
+// of the recorder, or in the window of the event if the recorder has none.
+func (r *DedupingRecorder) prune(now time.Time, window time.Duration) []*dedupEvent {
+	if r.window > 0 {
+		window = r.window
+	}
+	if now.Sub(r.lastPrune) < window {
+		return nil
+	}
+	r.lastPrune = now
+	var summaries []*dedupEvent
+	for key, w := range r.windows {
+		if now.Sub(w.start) < 2*w.window {
+			continue
+		}
+		if w.suppressed > 0 && w.last != nil {
+			summary := *w.last
+			summary.message = fmt.Sprintf("%s (x %d)", summary.message, w.suppressed)
+			summaries = append(summaries, &summary)
+		}
+		delete(r.windows, key)
+	}
+	return summaries
+}
+
+// record emits the summaries of the pruned windows and the event if it isn't deduplicated, the events
+// are emitted after the lock is released as the underlying recorder may block
+func (r *DedupingRecorder) record(event *dedupEvent, window time.Duration) {
+	emit, summaries := r.dedup(event, window)
+	for _, summary := range summaries {
+		r.emit(summary)
+	}
+	if emit {
+		r.emit(event)
+	}
+}
+
+func (r *DedupingRecorder) emit(event *dedupEvent) {
+	if event.annotations != nil {
+		r.EventRecorder.AnnotatedEventf(event.object, event.annotations, event.eventtype, event.reason, "%s", event.message)
+		return
+	}
+	r.EventRecorder.Event(event.object, event.eventtype, event.reason, event.message)
+}
+
+// EventInWindow emits the event, the identical events are deduplicated in window instead of
+// the window of the recorder, window <= 0 means the window of the recorder
+func (r *DedupingRecorder) EventInWindow(object runtime.Object, window time.Duration, eventtype, reason, message string) {
+	r.record(&dedupEvent{object: object, eventtype: eventtype, reason: reason, message: message}, window)
+}
+
+func (r *DedupingRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.record(&dedupEvent{object: object, eventtype: eventtype, reason: reason, message: message}, 0)
+}
+
+func (r *DedupingRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *DedupingRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.record(&dedupEvent{object: object, annotations: annotations, eventtype: eventtype, reason: reason, message: fmt.Sprintf(messageFmt, args...)}, 0)
+}
+
+var _ record.EventRecorder = &DedupingRecorder{}
+
+// RecordEventInWindow emits the event by the recorder, the identical events are deduplicated in window if
+// the recorder is a DedupingRecorder, otherwise the event is emitted as is.
+func RecordEventInWindow(recorder record.EventRecorder, object runtime.Object, window time.Duration, eventtype, reason, message string) {
+	if r, ok := recorder.(*DedupingRecorder); ok {
+		r.EventInWindow(object, window, eventtype, reason, message)
+		return
+	}
+	recorder.Event(object, eventtype, reason, message)
+}
@@ -0,0 +1,185 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestDedupingRecorder(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fakeRecorder := record.NewFakeRecorder(100)
+	recorder := NewDedupingRecorder(fakeRecorder, 5*time.Minute)
+	now := time.Now()
+	recorder.now = func() time.Time { return now }
+	tc1 := newTidbCluster()
+	tc1.UID = types.UID("tc1")
+	tc2 := newTidbCluster()
+	tc2.UID = types.UID("tc2")
+
+	// the identical events are emitted once in the window
+	for i := 0; i < 3; i++ {
+		recorder.Eventf(tc1, corev1.EventTypeWarning, "PDMemberUnhealthy", "%s is unhealthy", "pd-0")
+		recorder.Eventf(tc1, corev1.EventTypeWarning, "PDMemberUnhealthy", "%s is unhealthy", "pd-1")
+		recorder.Event(tc2, corev1.EventTypeWarning, "PDMemberUnhealthy", "pd-0 is unhealthy")
+		recorder.Event(tc1, corev1.EventTypeWarning, "TiKVStoreDown", "pd-0 is unhealthy")
+		now = now.Add(time.Minute)
+	}
+	g.Expect(collectEvents(fakeRecorder.Events)).To(Equal([]string{
+		"Warning PDMemberUnhealthy pd-0 is unhealthy",
+		"Warning PDMemberUnhealthy pd-1 is unhealthy",
+		"Warning PDMemberUnhealthy pd-0 is unhealthy",
+		"Warning TiKVStoreDown pd-0 is unhealthy",
+	}))
+
+	// the count of the suppressed events is appended to the first event after the window rolls over
+	now = now.Add(2 * time.Minute)
+	recorder.Eventf(tc1, corev1.EventTypeWarning, "PDMemberUnhealthy", "%s is unhealthy", "pd-0")
+	recorder.Eventf(tc1, corev1.EventTypeWarning, "PDMemberUnhealthy", "%s is unhealthy", "pd-0")
+	g.Expect(collectEvents(fakeRecorder.Events)).To(Equal([]string{
+		"Warning PDMemberUnhealthy pd-0 is unhealthy (x 2)",
+	}))
+
+	// no count is appended if nothing is suppressed in the last window, and the last suppressed events
+	// of the windows pruned as the events stop recurring are emitted with the count
+	now = now.Add(5 * time.Minute)
+	recorder.Eventf(tc1, corev1.EventTypeWarning, "PDMemberUnhealthy", "%s is unhealthy", "pd-0")
+	now = now.Add(5 * time.Minute)
+	recorder.Eventf(tc1, corev1.EventTypeWarning, "PDMemberUnhealthy", "%s is unhealthy", "pd-0")
+	events := collectEvents(fakeRecorder.Events)
+	g.Expect(events).To(HaveLen(5))
+	g.Expect(events[:3]).To(ConsistOf(
+		"Warning PDMemberUnhealthy pd-1 is unhealthy (x 2)",
+		"Warning PDMemberUnhealthy pd-0 is unhealthy (x 2)",
+		"Warning TiKVStoreDown pd-0 is unhealthy (x 2)",
+	))
+	g.Expect(events[3:]).To(Equal([]string{
+		"Warning PDMemberUnhealthy pd-0 is unhealthy (x 1)",
+		"Warning PDMemberUnhealthy pd-0 is unhealthy",
+	}))
+
+	// the windows of the events not recurring are pruned
+	now = now.Add(10 * time.Minute)
+	recorder.Event(tc2, corev1.EventTypeNormal, "Synced", "synced")
+	g.Expect(recorder.windows).To(HaveLen(1))
+	g.Expect(collectEvents(fakeRecorder.Events)).To(Equal([]string{"Normal Synced synced"}))
+
+	// no deduplication
+	recorder = NewDedupingRecorder(fakeRecorder, 0)
+	for i := 0; i < 3; i++ {
+		recorder.Event(tc1, corev1.EventTypeWarning, "PDMemberUnhealthy", "pd-0 is unhealthy")
+	}
+	g.Expect(collectEvents(fakeRecorder.Events)).To(HaveLen(3))
+}
+
+func TestDedupingRecorderEventInWindow(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fakeRecorder := record.NewFakeRecorder(100)
+	recorder := NewDedupingRecorder(fakeRecorder, 5*time.Minute)
+	now := time.Now()
+	recorder.now = func() time.Time { return now }
+	tc := newTidbCluster()
+
+	emit := func() {
+		RecordEventInWindow(recorder, tc, time.Minute, corev1.EventTypeWarning, "TiDBMemberUnhealthy", "tidb-0 is unhealthy")
+		RecordEventInWindow(recorder, tc, 10*time.Minute, corev1.EventTypeWarning, "PDMemberUnhealthy", "pd-0 is unhealthy")
+		RecordEventInWindow(recorder, tc, 0, corev1.EventTypeWarning, "TiKVStoreDown", "tikv-0 is down")
+	}
+
+	// the events are deduplicated in their own windows, or in the window of the recorder
+	emit()
+	now = now.Add(30 * time.Second)
+	emit()
+	g.Expect(collectEvents(fakeRecorder.Events)).To(Equal([]string{
+		"Warning TiDBMemberUnhealthy tidb-0 is unhealthy",
+		"Warning PDMemberUnhealthy pd-0 is unhealthy",
+		"Warning TiKVStoreDown tikv-0 is down",
+	}))
+
+	// the window shorter than the one of the recorder rolls over first, with the count of the suppressed events
+	now = now.Add(30 * time.Second)
+	emit()
+	g.Expect(collectEvents(fakeRecorder.Events)).To(Equal([]string{
+		"Warning TiDBMemberUnhealthy tidb-0 is unhealthy (x 1)",
+	}))
+
+	// the window of the recorder rolls over while the longer one doesn't
+	now = now.Add(4 * time.Minute)
+	emit()
+	g.Expect(collectEvents(fakeRecorder.Events)).To(Equal([]string{
+		"Warning TiDBMemberUnhealthy tidb-0 is unhealthy",
+		"Warning TiKVStoreDown tikv-0 is down (x 2)",
+	}))
+
+	now = now.Add(5 * time.Minute)
+	emit()
+	g.Expect(collectEvents(fakeRecorder.Events)).To(Equal([]string{
+		"Warning TiDBMemberUnhealthy tidb-0 is unhealthy",
+		"Warning PDMemberUnhealthy pd-0 is unhealthy (x 3)",
+		"Warning TiKVStoreDown tikv-0 is down",
+	}))
+
+	// the windows are pruned by their own length once the events stop recurring
+	now = now.Add(10 * time.Minute)
+	recorder.Event(tc, corev1.EventTypeNormal, "Synced", "synced")
+	g.Expect(recorder.windows).To(HaveLen(2))
+	now = now.Add(10 * time.Minute)
+	recorder.Event(tc, corev1.EventTypeNormal, "Synced", "synced")
+	g.Expect(recorder.windows).To(HaveLen(1))
+	g.Expect(collectEvents(fakeRecorder.Events)).To(Equal([]string{"Normal Synced synced", "Normal Synced synced"}))
+
+	// the window of an event applies even if the recorder doesn't deduplicate
+	recorder = NewDedupingRecorder(fakeRecorder, 0)
+	for i := 0; i < 3; i++ {
+		RecordEventInWindow(recorder, tc, time.Minute, corev1.EventTypeWarning, "TiDBMemberUnhealthy", "tidb-0 is unhealthy")
+		recorder.Event(tc, corev1.EventTypeWarning, "TiKVStoreDown", "tikv-0 is down")
+	}
+	g.Expect(collectEvents(fakeRecorder.Events)).To(HaveLen(4))
+
+	// the events are emitted as is by the other recorders
+	for i := 0; i < 3; i++ {
+		RecordEventInWindow(fakeRecorder, tc, time.Minute, corev1.EventTypeWarning, "TiDBMemberUnhealthy", "tidb-0 is unhealthy")
+	}
+	g.Expect(collectEvents(fakeRecorder.Events)).To(HaveLen(3))
+}
+
+func TestDedupingRecorderWithBudget(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fakeRecorder := record.NewFakeRecorder(100)
+	recorder := NewDedupingRecorder(NewBudgetEventRecorder(fakeRecorder, 2), 5*time.Minute)
+	tc := newTidbCluster()
+
+	// the budget is started through the DedupingRecorder, and the duplicates don't use up the budget
+	finish := StartEventBudget(recorder, tc)
+	for i := 0; i < 3; i++ {
+		recorder.Event(tc, corev1.EventTypeWarning, "PDMemberUnhealthy", "pd-0 is unhealthy")
+	}
+	recorder.Event(tc, corev1.EventTypeWarning, "PDMemberUnhealthy", "pd-1 is unhealthy")
+	recorder.Event(tc, corev1.EventTypeWarning, "PDMemberUnhealthy", "pd-2 is unhealthy")
+	finish()
+	g.Expect(collectEvents(fakeRecorder.Events)).To(Equal([]string{
+		"Warning PDMemberUnhealthy pd-0 is unhealthy",
+		"Warning PDMemberUnhealthy pd-1 is unhealthy",
+		"Warning EventsSuppressed 1 additional events suppressed",
+	}))
+}
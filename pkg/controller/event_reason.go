@@ -0,0 +1,84 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+// EventReason is the reason of the events about the members of a component,
+// the reason of an event is prefixed with the component, e.g. PDMemberUnhealthy
+type EventReason string
+
+const (
+	// MemberUnhealthy is the reason of the event when a member is unhealthy
+	MemberUnhealthy EventReason = "MemberUnhealthy"
+	// PeerMemberUnhealthy is the reason of the event when a member of the peer cluster is unhealthy
+	PeerMemberUnhealthy EventReason = "PeerMemberUnhealthy"
+	// MemberIDInvalid is the reason of the event when the id of a member can't be parsed
+	MemberIDInvalid EventReason = "MemberIDInvalid"
+	// MemberDeleted is the reason of the event when a failure member is deleted from the cluster
+	MemberDeleted EventReason = "MemberDeleted"
+	// FailoverVetoed is the reason of the event when the failover of a failure member is vetoed
+	FailoverVetoed EventReason = "FailoverVetoed"
+	// FailurePodPreserved is the reason of the event when the pod of a failure member is preserved
+	FailurePodPreserved EventReason = "FailurePodPreserved"
+	// PodDeleted is the reason of the event when the pod of a failure member is deleted
+	PodDeleted EventReason = "PodDeleted"
+	// FailoverCooldown is the reason of the event when the failover of a recently recovered member is suppressed
+	FailoverCooldown EventReason = "FailoverCooldown"
+	// FailoverTriggered is the reason of the event when a member is marked as failure on demand
+	FailoverTriggered EventReason = "FailoverTriggered"
+	// TransferSourceTimeout is the reason of the event when a member is upgraded before its source is transferred
+	TransferSourceTimeout EventReason = "TransferSourceTimeout"
+	// TransferSourceFailed is the reason of the event when a member is upgraded after its source fails to be transferred
+	TransferSourceFailed EventReason = "TransferSourceFailed"
+	// MemberNodeNotReady is the reason of the event when the failover of a member is deferred because its node is NotReady
+	MemberNodeNotReady EventReason = "MemberNodeNotReady"
+	// MemberProtected is the reason of the event when an unhealthy member is protected from the failover
+	MemberProtected EventReason = "MemberProtected"
+	// UpgradeProgress is the reason of the event when the upgrade advances to the member of a new ordinal
+	UpgradeProgress EventReason = "UpgradeProgress"
+)
+
+// eventReasonPrefixes are the prefixes of the event reasons and the condition types of the components
+var eventReasonPrefixes = map[v1alpha1.MemberType]string{
+	v1alpha1.PDMemberType:       "PD",
+	v1alpha1.TiKVMemberType:     "TiKV",
+	v1alpha1.TiDBMemberType:     "TiDB",
+	v1alpha1.TiFlashMemberType:  "TiFlash",
+	v1alpha1.TiCDCMemberType:    "TiCDC",
+	v1alpha1.PumpMemberType:     "Pump",
+	v1alpha1.DMMasterMemberType: "DMMaster",
+	v1alpha1.DMWorkerMemberType: "DMWorker",
+}
+
+// For returns the reason of the event about the members of the component
+func (r EventReason) For(memberType v1alpha1.MemberType) string {
+	return componentPrefix(memberType) + string(r)
+}
+
+// ConditionTypeFor returns the condition type of the component prefixed with the component,
+// e.g. PDFailoverBlocked, so that the conditions of the components are kept independently
+func ConditionTypeFor(condType v1alpha1.TidbClusterConditionType, memberType v1alpha1.MemberType) v1alpha1.TidbClusterConditionType {
+	return v1alpha1.TidbClusterConditionType(componentPrefix(memberType) + string(condType))
+}
+
+func componentPrefix(memberType v1alpha1.MemberType) string {
+	prefix, ok := eventReasonPrefixes[memberType]
+	if !ok {
+		prefix = string(memberType)
+	}
+	return prefix
+}
@@ -0,0 +1,32 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+func TestEventReasonFor(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(MemberUnhealthy.For(v1alpha1.PDMemberType)).To(Equal("PDMemberUnhealthy"))
+	g.Expect(PeerMemberUnhealthy.For(v1alpha1.PDMemberType)).To(Equal("PDPeerMemberUnhealthy"))
+	g.Expect(MemberDeleted.For(v1alpha1.TiFlashMemberType)).To(Equal("TiFlashMemberDeleted"))
+	g.Expect(PodDeleted.For(v1alpha1.DMMasterMemberType)).To(Equal("DMMasterPodDeleted"))
+	// the member types without a prefix are used as is
+	g.Expect(MemberUnhealthy.For(v1alpha1.TidbMonitorMemberType)).To(Equal("tidbmonitorMemberUnhealthy"))
+}
@@ -0,0 +1,203 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"errors"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+)
+
+var errReadOnlyIndexer = errors.New("the fan-out indexer is read only")
+
+// fanOutIndexer is a read only cache.Indexer over the indexers of the informers of multiple namespaces,
+// e.g. the informers of the per-namespace informer factories. The client-go listers are built on top of
+// it, so that a lister lists the objects of all the namespaces.
+//
+// The objects are read from the indexer of their namespace first, and then from the other indexers in
+// order, so the indexers may be cluster-wide ones as well.
+type fanOutIndexer struct {
+	namespaces []string
+	indexers   []cache.Indexer
+}
+
+var _ cache.Indexer = &fanOutIndexer{}
+
+// NewFanOutIndexer returns a read only cache.Indexer over the indexers, the i-th indexer is the indexer of
+// the i-th namespace.
+func NewFanOutIndexer(namespaces []string, indexers []cache.Indexer) cache.Indexer {
+	return &fanOutIndexer{
+		namespaces: namespaces,
+		indexers:   indexers,
+	}
+}
+
+// indexersFor returns the indexers in the order of precedence for the namespace
+func (f *fanOutIndexer) indexersFor(namespace string) []cache.Indexer {
+	for i, ns := range f.namespaces {
+		if ns == namespace && i < len(f.indexers) {
+			ordered := make([]cache.Indexer, 0, len(f.indexers))
+			ordered = append(ordered, f.indexers[i])
+			ordered = append(ordered, f.indexers[:i]...)
+			return append(ordered, f.indexers[i+1:]...)
+		}
+	}
+	return f.indexers
+}
+
+// namespaceIndexer returns the indexer of the namespace, nil if the namespace is not watched by any
+// of the indexers separately
+func (f *fanOutIndexer) namespaceIndexer(namespace string) cache.Indexer {
+	for i, ns := range f.namespaces {
+		if ns == namespace && i < len(f.indexers) {
+			return f.indexers[i]
+		}
+	}
+	return nil
+}
+
+func (f *fanOutIndexer) Add(obj interface{}) error {
+	return errReadOnlyIndexer
+}
+
+func (f *fanOutIndexer) Update(obj interface{}) error {
+	return errReadOnlyIndexer
+}
+
+func (f *fanOutIndexer) Delete(obj interface{}) error {
+	return errReadOnlyIndexer
+}
+
+func (f *fanOutIndexer) Replace(list []interface{}, resourceVersion string) error {
+	return errReadOnlyIndexer
+}
+
+func (f *fanOutIndexer) Resync() error {
+	return errReadOnlyIndexer
+}
+
+func (f *fanOutIndexer) AddIndexers(newIndexers cache.Indexers) error {
+	return errReadOnlyIndexer
+}
+
+func (f *fanOutIndexer) List() []interface{} {
+	var items []interface{}
+	for _, indexer := range f.indexers {
+		items = append(items, indexer.List()...)
+	}
+	return items
+}
+
+func (f *fanOutIndexer) ListKeys() []string {
+	var keys []string
+	for _, indexer := range f.indexers {
+		keys = append(keys, indexer.ListKeys()...)
+	}
+	return keys
+}
+
+func (f *fanOutIndexer) Get(obj interface{}) (interface{}, bool, error) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return nil, false, cache.KeyError{Obj: obj, Err: err}
+	}
+	return f.GetByKey(key)
+}
+
+func (f *fanOutIndexer) GetByKey(key string) (interface{}, bool, error) {
+	namespace, _, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, indexer := range f.indexersFor(namespace) {
+		item, exists, err := indexer.GetByKey(key)
+		if err != nil {
+			return nil, false, err
+		}
+		if exists {
+			return item, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (f *fanOutIndexer) Index(indexName string, obj interface{}) ([]interface{}, error) {
+	if indexName == cache.NamespaceIndex {
+		if accessor, err := meta.Accessor(obj); err == nil {
+			if indexer := f.namespaceIndexer(accessor.GetNamespace()); indexer != nil {
+				return indexer.Index(indexName, obj)
+			}
+		}
+	}
+	var items []interface{}
+	for _, indexer := range f.indexers {
+		objs, err := indexer.Index(indexName, obj)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, objs...)
+	}
+	return items, nil
+}
+
+func (f *fanOutIndexer) IndexKeys(indexName, indexedValue string) ([]string, error) {
+	if indexName == cache.NamespaceIndex {
+		if indexer := f.namespaceIndexer(indexedValue); indexer != nil {
+			return indexer.IndexKeys(indexName, indexedValue)
+		}
+	}
+	var keys []string
+	for _, indexer := range f.indexers {
+		k, err := indexer.IndexKeys(indexName, indexedValue)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k...)
+	}
+	return keys, nil
+}
+
+func (f *fanOutIndexer) ListIndexFuncValues(indexName string) []string {
+	values := sets.NewString()
+	for _, indexer := range f.indexers {
+		values.Insert(indexer.ListIndexFuncValues(indexName)...)
+	}
+	return values.List()
+}
+
+func (f *fanOutIndexer) ByIndex(indexName, indexedValue string) ([]interface{}, error) {
+	if indexName == cache.NamespaceIndex {
+		if indexer := f.namespaceIndexer(indexedValue); indexer != nil {
+			return indexer.ByIndex(indexName, indexedValue)
+		}
+	}
+	var items []interface{}
+	for _, indexer := range f.indexers {
+		objs, err := indexer.ByIndex(indexName, indexedValue)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, objs...)
+	}
+	return items, nil
+}
+
+func (f *fanOutIndexer) GetIndexers() cache.Indexers {
+	if len(f.indexers) == 0 {
+		return cache.Indexers{}
+	}
+	return f.indexers[0].GetIndexers()
+}
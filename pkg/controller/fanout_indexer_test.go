@@ -0,0 +1,115 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisterv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newPodIndexerForFanOut(g *GomegaWithT, pods ...*corev1.Pod) cache.Indexer {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, pod := range pods {
+		g.Expect(indexer.Add(pod)).To(Succeed())
+	}
+	return indexer
+}
+
+func newPodForFanOut(ns, name, owner string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+			Labels:    map[string]string{"owner": owner},
+		},
+	}
+}
+
+func podNames(pods []*corev1.Pod) []string {
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Namespace+"/"+pod.Name)
+	}
+	return names
+}
+
+func TestFanOutIndexerList(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	indexer := NewFanOutIndexer([]string{"ns1", "ns2"}, []cache.Indexer{
+		newPodIndexerForFanOut(g, newPodForFanOut("ns1", "a", "x"), newPodForFanOut("ns1", "b", "y")),
+		newPodIndexerForFanOut(g, newPodForFanOut("ns2", "a", "x")),
+	})
+	lister := corelisterv1.NewPodLister(indexer)
+
+	pods, err := lister.List(labels.Everything())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(podNames(pods)).To(ConsistOf("ns1/a", "ns1/b", "ns2/a"))
+
+	pods, err = lister.List(labels.SelectorFromSet(labels.Set{"owner": "x"}))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(podNames(pods)).To(ConsistOf("ns1/a", "ns2/a"))
+
+	pods, err = lister.Pods("ns2").List(labels.Everything())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(podNames(pods)).To(ConsistOf("ns2/a"))
+
+	pods, err = lister.Pods("ns3").List(labels.Everything())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pods).To(BeEmpty())
+
+	g.Expect(indexer.ListKeys()).To(ConsistOf("ns1/a", "ns1/b", "ns2/a"))
+	g.Expect(indexer.ListIndexFuncValues(cache.NamespaceIndex)).To(Equal([]string{"ns1", "ns2"}))
+	g.Expect(indexer.Add(newPodForFanOut("ns1", "c", "x"))).NotTo(Succeed())
+}
+
+func TestFanOutIndexerGet(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// the indexer of ns2 is listed first, and it has a stale copy of ns1/a, e.g. a cluster-wide indexer
+	indexer := NewFanOutIndexer([]string{"ns2", "ns1"}, []cache.Indexer{
+		newPodIndexerForFanOut(g, newPodForFanOut("ns2", "a", "ns2"), newPodForFanOut("ns1", "a", "ns2"), newPodForFanOut("ns3", "a", "ns2")),
+		newPodIndexerForFanOut(g, newPodForFanOut("ns1", "a", "ns1")),
+	})
+	lister := corelisterv1.NewPodLister(indexer)
+
+	// the indexer of the namespace takes precedence
+	pod, err := lister.Pods("ns1").Get("a")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pod.Labels["owner"]).To(Equal("ns1"))
+
+	pod, err = lister.Pods("ns2").Get("a")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pod.Labels["owner"]).To(Equal("ns2"))
+
+	// the indexers are looked up in order for the namespace not watched separately
+	pod, err = lister.Pods("ns3").Get("a")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pod.Labels["owner"]).To(Equal("ns2"))
+
+	_, err = lister.Pods("ns1").Get("b")
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+
+	item, exists, err := indexer.Get(newPodForFanOut("ns1", "a", ""))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(exists).To(BeTrue())
+	g.Expect(item.(*corev1.Pod).Labels["owner"]).To(Equal("ns1"))
+}
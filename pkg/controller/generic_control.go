@@ -550,7 +550,7 @@ type FakeGenericControl struct {
 // NewFakeGenericControl returns a FakeGenericControl
 func NewFakeGenericControl(initObjects ...runtime.Object) *FakeGenericControl {
 	fakeCli := fake.NewFakeClientWithScheme(scheme.Scheme, initObjects...)
-	control := NewRealGenericControl(fakeCli, record.NewFakeRecorder(10))
+	control := NewRealGenericControl(fakeCli, record.NewFakeRecorder(100))
 	return &FakeGenericControl{
 		fakeCli,
 		control,
@@ -40,6 +40,8 @@ type PodControlInterface interface {
 	// TODO change this to UpdatePod
 	UpdateMetaInfo(*v1alpha1.TidbCluster, *corev1.Pod) (*corev1.Pod, error)
 	DeletePod(runtime.Object, *corev1.Pod) error
+	// DeletePodWithGracePeriod deletes the Pod with the grace period, nil means the default grace period of the Pod
+	DeletePodWithGracePeriod(runtime.Object, *corev1.Pod, *int64) error
 	UpdatePod(runtime.Object, *corev1.Pod) (*corev1.Pod, error)
 }
 
@@ -203,6 +205,10 @@ func (c *realPodControl) UpdateMetaInfo(tc *v1alpha1.TidbCluster, pod *corev1.Po
 }
 
 func (c *realPodControl) DeletePod(controller runtime.Object, pod *corev1.Pod) error {
+	return c.DeletePodWithGracePeriod(controller, pod, nil)
+}
+
+func (c *realPodControl) DeletePodWithGracePeriod(controller runtime.Object, pod *corev1.Pod, gracePeriodSeconds *int64) error {
 	controllerMo, ok := controller.(metav1.Object)
 	if !ok {
 		return fmt.Errorf("%T is not a metav1.Object, cannot call setControllerReference", controller)
@@ -213,7 +219,7 @@ func (c *realPodControl) DeletePod(controller runtime.Object, pod *corev1.Pod) e
 
 	podName := pod.GetName()
 	preconditions := metav1.Preconditions{UID: &pod.UID, ResourceVersion: &pod.ResourceVersion}
-	deleteOptions := metav1.DeleteOptions{Preconditions: &preconditions}
+	deleteOptions := metav1.DeleteOptions{Preconditions: &preconditions, GracePeriodSeconds: gracePeriodSeconds}
 	err := c.kubeCli.CoreV1().Pods(namespace).Delete(context.TODO(), podName, deleteOptions)
 	if err != nil {
 		klog.Errorf("failed to delete Pod: [%s/%s], %s: %s, %v", namespace, podName, kind, namespace, err)
@@ -259,6 +265,9 @@ type FakePodControl struct {
 	getClusterTracker RequestTracker
 	getMemberTracker  RequestTracker
 	getStoreTracker   RequestTracker
+
+	// DeleteGracePeriods records the grace periods of the deleted Pods by name
+	DeleteGracePeriods map[string]*int64
 }
 
 // NewFakePodControl returns a FakePodControl
@@ -270,6 +279,7 @@ func NewFakePodControl(podInformer coreinformers.PodInformer) *FakePodControl {
 		RequestTracker{},
 		RequestTracker{},
 		RequestTracker{},
+		map[string]*int64{},
 	}
 }
 
@@ -333,13 +343,18 @@ func (c *FakePodControl) UpdateMetaInfo(_ *v1alpha1.TidbCluster, pod *corev1.Pod
 	return pod, c.PodIndexer.Update(pod)
 }
 
-func (c *FakePodControl) DeletePod(_ runtime.Object, pod *corev1.Pod) error {
+func (c *FakePodControl) DeletePod(controller runtime.Object, pod *corev1.Pod) error {
+	return c.DeletePodWithGracePeriod(controller, pod, nil)
+}
+
+func (c *FakePodControl) DeletePodWithGracePeriod(_ runtime.Object, pod *corev1.Pod, gracePeriodSeconds *int64) error {
 	defer c.deletePodTracker.Inc()
 	if c.deletePodTracker.ErrorReady() {
 		defer c.deletePodTracker.Reset()
 		return c.deletePodTracker.GetError()
 	}
 
+	c.DeleteGracePeriods[pod.Name] = gracePeriodSeconds
 	return c.PodIndexer.Delete(pod)
 }
 
@@ -0,0 +1,235 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/metrics"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// DefaultHighPriorityBurst is the default max number of the high priority items handed out
+// in a row while there are low priority items waiting.
+const DefaultHighPriorityBurst = 5
+
+// PriorityRateLimitingInterface is a rate limiting work queue with two priority tiers. The items in
+// the high priority tier are handed out before the ones in the low priority tier, except that a low
+// priority item is handed out after at most a burst of high priority ones, so that the low priority
+// items are not starved.
+//
+// An item is in the high priority tier if it's added by AddHighPriority, or it's requeued by
+// AddRateLimited and not forgotten yet, i.e. its last sync failed.
+type PriorityRateLimitingInterface interface {
+	workqueue.RateLimitingInterface
+	// AddHighPriority adds the item into the high priority tier, the item is moved to the
+	// high priority tier if it's waiting in the low priority tier already.
+	AddHighPriority(item interface{})
+}
+
+type priorityQueue struct {
+	name        string
+	rateLimiter workqueue.RateLimiter
+	// highPriorityBurst is the max number of the high priority items handed out in a row
+	// while there are low priority items waiting
+	highPriorityBurst int
+
+	cond *sync.Cond
+	high []interface{}
+	low  []interface{}
+	// dirty are the items to be processed, and whether they are of high priority
+	dirty map[interface{}]bool
+	// processing are the items being processed
+	processing map[interface{}]struct{}
+	// failed are the items requeued by AddRateLimited and not forgotten
+	failed       map[interface{}]struct{}
+	burst        int
+	shuttingDown bool
+}
+
+var _ PriorityRateLimitingInterface = &priorityQueue{}
+
+// NewPriorityRateLimitingQueue returns a rate limiting work queue with two priority tiers, the depth of
+// each tier is exported as a metric labeled by the name.
+func NewPriorityRateLimitingQueue(rateLimiter workqueue.RateLimiter, name string, highPriorityBurst int) PriorityRateLimitingInterface {
+	if highPriorityBurst < 1 {
+		highPriorityBurst = 1
+	}
+	return &priorityQueue{
+		name:              name,
+		rateLimiter:       rateLimiter,
+		highPriorityBurst: highPriorityBurst,
+		cond:              sync.NewCond(&sync.Mutex{}),
+		dirty:             map[interface{}]bool{},
+		processing:        map[interface{}]struct{}{},
+		failed:            map[interface{}]struct{}{},
+	}
+}
+
+func (q *priorityQueue) Add(item interface{}) {
+	q.add(item, false)
+}
+
+func (q *priorityQueue) AddHighPriority(item interface{}) {
+	q.add(item, true)
+}
+
+func (q *priorityQueue) add(item interface{}, highPriority bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	if _, failed := q.failed[item]; failed {
+		highPriority = true
+	}
+
+	if queuedHigh, dirty := q.dirty[item]; dirty {
+		if !highPriority || queuedHigh {
+			return
+		}
+		q.dirty[item] = true
+		if _, processing := q.processing[item]; !processing {
+			// promote the item waiting in the low priority tier
+			q.low = removeItem(q.low, item)
+			q.high = append(q.high, item)
+			q.updateMetrics()
+		}
+		return
+	}
+
+	q.dirty[item] = highPriority
+	if _, processing := q.processing[item]; processing {
+		return
+	}
+	q.push(item, highPriority)
+	q.cond.Signal()
+}
+
+// push appends the item to the tier, it must be called with the lock held
+func (q *priorityQueue) push(item interface{}, highPriority bool) {
+	if highPriority {
+		q.high = append(q.high, item)
+	} else {
+		q.low = append(q.low, item)
+	}
+	q.updateMetrics()
+}
+
+func (q *priorityQueue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return len(q.high) + len(q.low)
+}
+
+func (q *priorityQueue) Get() (interface{}, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for len(q.high) == 0 && len(q.low) == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if len(q.high) == 0 && len(q.low) == 0 {
+		// the queue is shutting down
+		return nil, true
+	}
+
+	var item interface{}
+	if len(q.high) > 0 && (len(q.low) == 0 || q.burst < q.highPriorityBurst) {
+		item, q.high = q.high[0], q.high[1:]
+		if len(q.low) > 0 {
+			q.burst++
+		}
+	} else {
+		item, q.low = q.low[0], q.low[1:]
+		q.burst = 0
+	}
+	q.processing[item] = struct{}{}
+	delete(q.dirty, item)
+	q.updateMetrics()
+	return item, false
+}
+
+func (q *priorityQueue) Done(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	delete(q.processing, item)
+	if highPriority, dirty := q.dirty[item]; dirty {
+		q.push(item, highPriority)
+		q.cond.Signal()
+	}
+}
+
+func (q *priorityQueue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+func (q *priorityQueue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
+
+func (q *priorityQueue) AddAfter(item interface{}, duration time.Duration) {
+	if q.ShuttingDown() {
+		return
+	}
+	if duration <= 0 {
+		q.Add(item)
+		return
+	}
+	time.AfterFunc(duration, func() {
+		q.Add(item)
+	})
+}
+
+func (q *priorityQueue) AddRateLimited(item interface{}) {
+	q.cond.L.Lock()
+	q.failed[item] = struct{}{}
+	q.cond.L.Unlock()
+	q.AddAfter(item, q.rateLimiter.When(item))
+}
+
+func (q *priorityQueue) Forget(item interface{}) {
+	q.cond.L.Lock()
+	delete(q.failed, item)
+	q.cond.L.Unlock()
+	q.rateLimiter.Forget(item)
+}
+
+func (q *priorityQueue) NumRequeues(item interface{}) int {
+	return q.rateLimiter.NumRequeues(item)
+}
+
+// updateMetrics exports the depth of the tiers, it must be called with the lock held
+func (q *priorityQueue) updateMetrics() {
+	if q.name == "" {
+		return
+	}
+	metrics.QueueDepth.WithLabelValues(q.name, metrics.QueuePriorityHigh).Set(float64(len(q.high)))
+	metrics.QueueDepth.WithLabelValues(q.name, metrics.QueuePriorityLow).Set(float64(len(q.low)))
+}
+
+// removeItem removes the item from the items in place
+func removeItem(items []interface{}, item interface{}) []interface{} {
+	for i := range items {
+		if items[i] == item {
+			return append(items[:i], items[i+1:]...)
+		}
+	}
+	return items
+}
@@ -0,0 +1,144 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func newPriorityQueueForTest(name string, burst int) PriorityRateLimitingInterface {
+	return NewPriorityRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(0, 0), name, burst)
+}
+
+// drain gets all the items in the queue in order and marks them done
+func drain(q PriorityRateLimitingInterface) []interface{} {
+	var items []interface{}
+	for q.Len() > 0 {
+		item, _ := q.Get()
+		q.Done(item)
+		items = append(items, item)
+	}
+	return items
+}
+
+func queueDepth(g *GomegaWithT, name, priority string) float64 {
+	m := &dto.Metric{}
+	g.Expect(metrics.QueueDepth.WithLabelValues(name, priority).Write(m)).To(Succeed())
+	return m.GetGauge().GetValue()
+}
+
+func TestPriorityQueueOrder(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	q := newPriorityQueueForTest("", DefaultHighPriorityBurst)
+	q.Add("a")
+	q.Add("b")
+	q.AddHighPriority("c")
+	q.Add("a")
+	g.Expect(q.Len()).To(Equal(3))
+	g.Expect(drain(q)).To(Equal([]interface{}{"c", "a", "b"}))
+
+	// the item waiting in the low priority tier is promoted
+	q.Add("a")
+	q.Add("b")
+	q.AddHighPriority("b")
+	q.Add("b")
+	g.Expect(q.Len()).To(Equal(2))
+	g.Expect(drain(q)).To(Equal([]interface{}{"b", "a"}))
+}
+
+func TestPriorityQueueAntiStarvation(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	q := newPriorityQueueForTest("", 2)
+	q.Add("l1")
+	q.Add("l2")
+	for _, item := range []string{"h1", "h2", "h3", "h4", "h5"} {
+		q.AddHighPriority(item)
+	}
+	g.Expect(drain(q)).To(Equal([]interface{}{"h1", "h2", "l1", "h3", "h4", "l2", "h5"}))
+}
+
+func TestPriorityQueueFailedItems(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	q := newPriorityQueueForTest("", DefaultHighPriorityBurst)
+	q.Add("a")
+	q.AddRateLimited("b")
+	g.Expect(q.NumRequeues("b")).To(Equal(1))
+	g.Expect(drain(q)).To(Equal([]interface{}{"b", "a"}))
+
+	// the failed item is enqueued into the high priority tier until it's forgotten
+	q.Add("a")
+	q.Add("b")
+	g.Expect(drain(q)).To(Equal([]interface{}{"b", "a"}))
+	q.Forget("b")
+	g.Expect(q.NumRequeues("b")).To(Equal(0))
+	q.Add("b")
+	q.Add("a")
+	g.Expect(drain(q)).To(Equal([]interface{}{"b", "a"}))
+	q.Add("a")
+	q.Add("b")
+	g.Expect(drain(q)).To(Equal([]interface{}{"a", "b"}))
+}
+
+func TestPriorityQueueProcessing(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	q := newPriorityQueueForTest("", DefaultHighPriorityBurst)
+	q.Add("a")
+	q.Add("b")
+	item, shutdown := q.Get()
+	g.Expect(shutdown).To(BeFalse())
+	g.Expect(item).To(Equal("a"))
+
+	// the item being processed is requeued after it's done
+	q.AddHighPriority("a")
+	g.Expect(q.Len()).To(Equal(1))
+	q.Done("a")
+	g.Expect(q.Len()).To(Equal(2))
+	g.Expect(drain(q)).To(Equal([]interface{}{"a", "b"}))
+
+	q.AddAfter("c", 10*time.Millisecond)
+	g.Eventually(q.Len).Should(Equal(1))
+
+	q.ShutDown()
+	g.Expect(q.ShuttingDown()).To(BeTrue())
+	q.Add("d")
+	g.Expect(drain(q)).To(Equal([]interface{}{"c"}))
+	_, shutdown = q.Get()
+	g.Expect(shutdown).To(BeTrue())
+}
+
+func TestPriorityQueueMetrics(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	q := newPriorityQueueForTest("test-priority-queue", DefaultHighPriorityBurst)
+	q.Add("a")
+	q.Add("b")
+	q.AddHighPriority("c")
+	g.Expect(queueDepth(g, "test-priority-queue", metrics.QueuePriorityHigh)).To(Equal(1.0))
+	g.Expect(queueDepth(g, "test-priority-queue", metrics.QueuePriorityLow)).To(Equal(2.0))
+
+	item, _ := q.Get()
+	q.Done(item)
+	g.Expect(queueDepth(g, "test-priority-queue", metrics.QueuePriorityHigh)).To(Equal(0.0))
+	g.Expect(queueDepth(g, "test-priority-queue", metrics.QueuePriorityLow)).To(Equal(2.0))
+}
@@ -17,14 +17,17 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -36,11 +39,30 @@ import (
 
 // PVControlInterface manages PVs used in TidbCluster
 type PVControlInterface interface {
+	// PatchPVReclaimPolicy patches the reclaim policy of the PV, the policy before the change is recorded
+	// in the annotation tidb.pingcap.com/original-reclaim-policy
 	PatchPVReclaimPolicy(runtime.Object, *corev1.PersistentVolume, corev1.PersistentVolumeReclaimPolicy) error
+	// RestorePVReclaimPolicy reverts the reclaim policy of the PV to the one recorded by PatchPVReclaimPolicy
+	// and removes the record, nothing is done if there is no record
+	RestorePVReclaimPolicy(runtime.Object, *corev1.PersistentVolume) error
 	UpdateMetaInfo(runtime.Object, *corev1.PersistentVolume) (*corev1.PersistentVolume, error)
+	UpdatePV(runtime.Object, *corev1.PersistentVolume) (*corev1.PersistentVolume, error)
 	PatchPVClaimRef(runtime.Object, *corev1.PersistentVolume, string) error
 	CreatePV(obj runtime.Object, pv *corev1.PersistentVolume) error
+	// GetPV gets the PV from the lister, which may be stale right after a create or update
 	GetPV(name string) (*corev1.PersistentVolume, error)
+	// GetPVLive gets the PV from the API server directly, it's for the callers that need
+	// strong consistency, use GetPV in the hot paths
+	GetPVLive(name string) (*corev1.PersistentVolume, error)
+	// GetPVWithRetry polls the PV from the API server until it appears or the timeout passes,
+	// it's for the callers that get the PV right after creating it
+	GetPVWithRetry(name string, timeout time.Duration) (*corev1.PersistentVolume, error)
+	// FindOrphanedPVs returns the PVs selected by the selector whose claimRef targets a PVC which no longer exists,
+	// it only reports them and nothing is deleted
+	FindOrphanedPVs(selector labels.Selector) ([]*corev1.PersistentVolume, error)
+	// EnsureRetainPolicy patches the reclaim policy of the data PVs to Retain if it's not yet, the other PVs
+	// are skipped, it returns the count of the PVs patched
+	EnsureRetainPolicy(obj runtime.Object, pvs []*corev1.PersistentVolume) (int, error)
 }
 
 type realPVControl struct {
@@ -48,6 +70,9 @@ type realPVControl struct {
 	pvcLister corelisters.PersistentVolumeClaimLister
 	pvLister  corelisters.PersistentVolumeLister
 	recorder  record.EventRecorder
+	// extraLabelKeys are the label keys synced from PVC to PV
+	// in addition to the ones managed by tidb-operator
+	extraLabelKeys []string
 }
 
 // NewRealPVControl creates a new PVControlInterface
@@ -56,12 +81,14 @@ func NewRealPVControl(
 	pvcLister corelisters.PersistentVolumeClaimLister,
 	pvLister corelisters.PersistentVolumeLister,
 	recorder record.EventRecorder,
+	extraLabelKeys []string,
 ) PVControlInterface {
 	return &realPVControl{
-		kubeCli:   kubeCli,
-		pvcLister: pvcLister,
-		pvLister:  pvLister,
-		recorder:  recorder,
+		kubeCli:        kubeCli,
+		pvcLister:      pvcLister,
+		pvLister:       pvLister,
+		recorder:       recorder,
+		extraLabelKeys: extraLabelKeys,
 	}
 }
 
@@ -74,19 +101,153 @@ func (c *realPVControl) PatchPVReclaimPolicy(obj runtime.Object, pv *corev1.Pers
 	name := metaObj.GetName()
 	pvName := pv.GetName()
 	patchBytes := []byte(fmt.Sprintf(`{"spec":{"persistentVolumeReclaimPolicy":"%s"}}`, reclaimPolicy))
+	if original := pv.Spec.PersistentVolumeReclaimPolicy; original != "" && original != reclaimPolicy {
+		patchBytes = []byte(fmt.Sprintf(`{"metadata":{"annotations":{"%s":"%s"}},"spec":{"persistentVolumeReclaimPolicy":"%s"}}`,
+			label.AnnOriginalReclaimPolicyKey, original, reclaimPolicy))
+	}
+
+	err := c.patchPVReclaimPolicy(pvName, patchBytes, reclaimPolicy)
+	c.recordPVEvent("patch", obj, name, pvName, err)
+	return err
+}
 
+func (c *realPVControl) RestorePVReclaimPolicy(obj runtime.Object, pv *corev1.PersistentVolume) error {
+	metaObj, ok := obj.(metav1.Object)
+	if !ok {
+		return fmt.Errorf("%+v is not a runtime.Object, cannot get controller from it", obj)
+	}
+
+	original, ok := pv.Annotations[label.AnnOriginalReclaimPolicyKey]
+	if !ok {
+		return nil
+	}
+	name := metaObj.GetName()
+	pvName := pv.GetName()
+	reclaimPolicy := corev1.PersistentVolumeReclaimPolicy(original)
+	patchBytes := []byte(fmt.Sprintf(`{"metadata":{"annotations":{"%s":null}},"spec":{"persistentVolumeReclaimPolicy":"%s"}}`,
+		label.AnnOriginalReclaimPolicyKey, reclaimPolicy))
+
+	err := c.patchPVReclaimPolicy(pvName, patchBytes, reclaimPolicy)
+	c.recordPVEvent("restore", obj, name, pvName, err)
+	return err
+}
+
+// patchPVReclaimPolicy patches the PV and verifies the reclaim policy read back
+func (c *realPVControl) patchPVReclaimPolicy(pvName string, patchBytes []byte, reclaimPolicy corev1.PersistentVolumeReclaimPolicy) error {
 	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		_, err := c.kubeCli.CoreV1().PersistentVolumes().Patch(context.TODO(), pvName, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
 		return err
 	})
-	c.recordPVEvent("patch", obj, name, pvName, err)
-	return err
+	if err != nil {
+		return err
+	}
+	patched, err := c.GetPVLive(pvName)
+	if err != nil {
+		return err
+	}
+	return verifyPVReclaimPolicy(patched, reclaimPolicy)
+}
+
+// verifyPVReclaimPolicy returns an error if the reclaim policy of the PV read back after the patch is not the
+// intended one, the patched value may be changed silently by the API server, e.g. by an admission webhook
+func verifyPVReclaimPolicy(pv *corev1.PersistentVolume, reclaimPolicy corev1.PersistentVolumeReclaimPolicy) error {
+	if pv.Spec.PersistentVolumeReclaimPolicy != reclaimPolicy {
+		return fmt.Errorf("reclaim policy of PV %s is %s after patching it to %s", pv.Name, pv.Spec.PersistentVolumeReclaimPolicy, reclaimPolicy)
+	}
+	return nil
 }
 
 func (c *realPVControl) GetPV(name string) (*corev1.PersistentVolume, error) {
 	return c.pvLister.Get(name)
 }
 
+func (c *realPVControl) GetPVLive(name string) (*corev1.PersistentVolume, error) {
+	return c.kubeCli.CoreV1().PersistentVolumes().Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+func (c *realPVControl) GetPVWithRetry(name string, timeout time.Duration) (*corev1.PersistentVolume, error) {
+	return getPVWithRetry(c.GetPVLive, name, timeout)
+}
+
+// getPVWithRetryInterval is the interval of polling the PV in GetPVWithRetry
+const getPVWithRetryInterval = 100 * time.Millisecond
+
+// getPVWithRetry polls the PV with getPVLive until it's found, the errors other than not found are returned immediately
+func getPVWithRetry(getPVLive func(string) (*corev1.PersistentVolume, error), name string, timeout time.Duration) (*corev1.PersistentVolume, error) {
+	var pv *corev1.PersistentVolume
+	err := wait.PollImmediate(getPVWithRetryInterval, timeout, func() (bool, error) {
+		var err error
+		pv, err = getPVLive(name)
+		if apierrs.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+	if err == wait.ErrWaitTimeout {
+		return nil, fmt.Errorf("timed out waiting for PV %s to appear after %v", name, timeout)
+	}
+	return pv, err
+}
+
+func (c *realPVControl) FindOrphanedPVs(selector labels.Selector) ([]*corev1.PersistentVolume, error) {
+	pvs, err := c.pvLister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	return findOrphanedPVs(pvs, c.pvcLister)
+}
+
+// findOrphanedPVs returns the PVs whose claimRef targets a PVC which no longer exists, a PVC recreated
+// with the same name is a different one, so the UID is compared as well
+func findOrphanedPVs(pvs []*corev1.PersistentVolume, pvcLister corelisters.PersistentVolumeClaimLister) ([]*corev1.PersistentVolume, error) {
+	var orphans []*corev1.PersistentVolume
+	for _, pv := range pvs {
+		ref := pv.Spec.ClaimRef
+		if ref == nil {
+			continue
+		}
+		pvc, err := pvcLister.PersistentVolumeClaims(ref.Namespace).Get(ref.Name)
+		if err != nil && !apierrs.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get PVC %s/%s of PV %s, error: %v", ref.Namespace, ref.Name, pv.Name, err)
+		}
+		if pvc == nil || (ref.UID != "" && pvc.UID != ref.UID) {
+			orphans = append(orphans, pv)
+		}
+	}
+	return orphans, nil
+}
+
+func (c *realPVControl) EnsureRetainPolicy(obj runtime.Object, pvs []*corev1.PersistentVolume) (int, error) {
+	return ensureRetainPolicy(c, obj, pvs)
+}
+
+// ensureRetainPolicy patches the reclaim policy of the data PVs to Retain with the control,
+// it stops at the first failure and returns the count of the PVs patched before it
+func ensureRetainPolicy(control PVControlInterface, obj runtime.Object, pvs []*corev1.PersistentVolume) (int, error) {
+	changed := 0
+	for _, pv := range pvs {
+		if !IsDataPV(pv) || pv.Spec.PersistentVolumeReclaimPolicy == corev1.PersistentVolumeReclaimRetain {
+			continue
+		}
+		if err := control.PatchPVReclaimPolicy(obj, pv, corev1.PersistentVolumeReclaimRetain); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+// IsDataPV returns whether the PV holds the data of PD or TiKV, which is lost if the PV is deleted,
+// the component is told by the label synced from the PVC
+func IsDataPV(pv *corev1.PersistentVolume) bool {
+	switch pv.Labels[label.ComponentLabelKey] {
+	case label.PDLabelVal, label.TiKVLabelVal:
+		return true
+	default:
+		return false
+	}
+}
+
 func (c *realPVControl) CreatePV(obj runtime.Object, pv *corev1.PersistentVolume) error {
 	metaObj, ok := obj.(metav1.Object)
 	if !ok {
@@ -137,7 +298,7 @@ func (c *realPVControl) UpdateMetaInfo(obj runtime.Object, pv *corev1.Persistent
 	pvName := pv.GetName()
 	pvcRef := pv.Spec.ClaimRef
 	if pvcRef == nil {
-		klog.Warningf("PV: [%s] doesn't have a ClaimRef, skipping, %s: %s/%s", kind, pvName, ns, name)
+		klog.Warningf("PV doesn't have a ClaimRef, skipping, pv=%s namespace=%s kind=%s name=%s", pvName, ns, kind, name)
 		return pv, nil
 	}
 
@@ -148,7 +309,7 @@ func (c *realPVControl) UpdateMetaInfo(obj runtime.Object, pv *corev1.Persistent
 			return pv, err
 		}
 
-		klog.Warningf("PV: [%s]'s PVC: [%s/%s] doesn't exist, skipping. %s: %s", pvName, ns, pvcName, kind, name)
+		klog.Warningf("PVC of PV doesn't exist, skipping, pv=%s namespace=%s kind=%s name=%s pvc=%s", pvName, ns, kind, name, pvcName)
 		return pv, nil
 	}
 
@@ -157,6 +318,8 @@ func (c *realPVControl) UpdateMetaInfo(obj runtime.Object, pv *corev1.Persistent
 	clusterID := pvc.Labels[label.ClusterIDLabelKey]
 	memberID := pvc.Labels[label.MemberIDLabelKey]
 	storeID := pvc.Labels[label.StoreIDLabelKey]
+	backupEligible := backupEligibleVal(component)
+	node := localPVNodeName(pv)
 
 	if pv.Labels[label.NamespaceLabelKey] == ns &&
 		pv.Labels[label.ComponentLabelKey] == component &&
@@ -166,8 +329,11 @@ func (c *realPVControl) UpdateMetaInfo(obj runtime.Object, pv *corev1.Persistent
 		pv.Labels[label.ClusterIDLabelKey] == clusterID &&
 		pv.Labels[label.MemberIDLabelKey] == memberID &&
 		pv.Labels[label.StoreIDLabelKey] == storeID &&
-		pv.Annotations[label.AnnPodNameKey] == podName {
-		klog.V(4).Infof("pv %s already has labels and annotations synced, skipping. %s: %s/%s", pvName, kind, ns, name)
+		pv.Labels[label.NodeLabelKey] == node &&
+		pv.Annotations[label.AnnPodNameKey] == podName &&
+		pv.Annotations[label.AnnBackupEligibleKey] == backupEligible &&
+		c.extraLabelsSynced(pv, pvc) {
+		klog.V(4).Infof("PV already has labels and annotations synced, skipping, pv=%s namespace=%s kind=%s name=%s", pvName, ns, kind, name)
 		return pv, nil
 	}
 
@@ -178,9 +344,17 @@ func (c *realPVControl) UpdateMetaInfo(obj runtime.Object, pv *corev1.Persistent
 	pv.Labels[label.InstanceLabelKey] = pvc.Labels[label.InstanceLabelKey]
 
 	setIfNotEmpty(pv.Labels, label.ClusterIDLabelKey, clusterID)
-	setIfNotEmpty(pv.Labels, label.MemberIDLabelKey, memberID)
-	setIfNotEmpty(pv.Labels, label.StoreIDLabelKey, storeID)
+	// the member and store IDs change when the member is rebuilt, don't keep the stale ones
+	setOrDelete(pv.Labels, label.MemberIDLabelKey, memberID)
+	setOrDelete(pv.Labels, label.StoreIDLabelKey, storeID)
+	setIfNotEmpty(pv.Labels, label.NodeLabelKey, node)
 	setIfNotEmpty(pv.Annotations, label.AnnPodNameKey, podName)
+	pv.Annotations[label.AnnBackupEligibleKey] = backupEligible
+	for _, key := range c.extraLabelKeys {
+		if val, ok := pvc.Labels[key]; ok {
+			pv.Labels[key] = val
+		}
+	}
 
 	labels := pv.GetLabels()
 	ann := pv.GetAnnotations()
@@ -189,10 +363,10 @@ func (c *realPVControl) UpdateMetaInfo(obj runtime.Object, pv *corev1.Persistent
 		var updateErr error
 		updatePV, updateErr = c.kubeCli.CoreV1().PersistentVolumes().Update(context.TODO(), pv, metav1.UpdateOptions{})
 		if updateErr == nil {
-			klog.Infof("PV: [%s] updated successfully, %s: %s/%s", pvName, kind, ns, name)
+			klog.Infof("PV updated successfully, pv=%s namespace=%s kind=%s name=%s", pvName, ns, kind, name)
 			return nil
 		}
-		klog.Errorf("failed to update PV: [%s], %s %s/%s, error: %v", pvName, kind, ns, name, err)
+		klog.Errorf("failed to update PV, pv=%s namespace=%s kind=%s name=%s, error: %v", pvName, ns, kind, name, updateErr)
 
 		if updated, err := c.pvLister.Get(pvName); err == nil {
 			// make a copy so we don't mutate the shared cache
@@ -208,6 +382,93 @@ func (c *realPVControl) UpdateMetaInfo(obj runtime.Object, pv *corev1.Persistent
 	return updatePV, err
 }
 
+// UpdatePV updates the metadata of the PV
+func (c *realPVControl) UpdatePV(obj runtime.Object, pv *corev1.PersistentVolume) (*corev1.PersistentVolume, error) {
+	metaObj, ok := obj.(metav1.Object)
+	if !ok {
+		return nil, fmt.Errorf("%+v is not a runtime.Object, cannot get controller from it", obj)
+	}
+
+	ns := metaObj.GetNamespace()
+	name := metaObj.GetName()
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	pvName := pv.GetName()
+
+	labels := pv.GetLabels()
+	ann := pv.GetAnnotations()
+	ownerRefs := pv.GetOwnerReferences()
+	var updatePV *corev1.PersistentVolume
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		var updateErr error
+		updatePV, updateErr = c.kubeCli.CoreV1().PersistentVolumes().Update(context.TODO(), pv, metav1.UpdateOptions{})
+		if updateErr == nil {
+			klog.Infof("PV updated successfully, pv=%s namespace=%s kind=%s name=%s", pvName, ns, kind, name)
+			return nil
+		}
+		klog.Errorf("failed to update PV, pv=%s namespace=%s kind=%s name=%s, error: %v", pvName, ns, kind, name, updateErr)
+
+		if updated, err := c.pvLister.Get(pvName); err == nil {
+			// make a copy so we don't mutate the shared cache
+			pv = updated.DeepCopy()
+			pv.Labels = labels
+			pv.Annotations = ann
+			pv.OwnerReferences = ownerRefs
+		} else {
+			utilruntime.HandleError(fmt.Errorf("error getting updated PV %s/%s from lister: %v", ns, pvName, err))
+		}
+		return updateErr
+	})
+	c.recordPVEvent("update", obj, name, pvName, err)
+	return updatePV, err
+}
+
+// extraLabelsSynced returns whether the extra labels of the PVC are synced to the PV, a label present on
+// the PVC is synced, even if its value is empty
+func (c *realPVControl) extraLabelsSynced(pv *corev1.PersistentVolume, pvc *corev1.PersistentVolumeClaim) bool {
+	for _, key := range c.extraLabelKeys {
+		val, ok := pvc.Labels[key]
+		if !ok {
+			continue
+		}
+		if pvVal, pvOK := pv.Labels[key]; !pvOK || pvVal != val {
+			return false
+		}
+	}
+	return true
+}
+
+// backupEligibleVal returns the value of the backup-eligible annotation of the PV of the component,
+// only the data volumes of PD and TiKV are snapshotted by the volume backup
+func backupEligibleVal(component string) string {
+	switch component {
+	case label.PDLabelVal, label.TiKVLabelVal:
+		return "true"
+	default:
+		return "false"
+	}
+}
+
+// localPVNodeName returns the name of the node which the local PV is pinned to by its node affinity,
+// and an empty string if the PV is not a local PV or it's not pinned to a single node
+func localPVNodeName(pv *corev1.PersistentVolume) string {
+	if pv.Spec.Local == nil || pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return ""
+	}
+	for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		for _, expr := range term.MatchExpressions {
+			if expr.Key == corev1.LabelHostname && expr.Operator == corev1.NodeSelectorOpIn && len(expr.Values) == 1 {
+				return expr.Values[0]
+			}
+		}
+		for _, field := range term.MatchFields {
+			if field.Key == "metadata.name" && field.Operator == corev1.NodeSelectorOpIn && len(field.Values) == 1 {
+				return field.Values[0]
+			}
+		}
+	}
+	return ""
+}
+
 func (c *realPVControl) recordPVEvent(verb string, obj runtime.Object, objName, pvName string, err error) {
 	if err == nil {
 		reason := fmt.Sprintf("Successful%s", strings.Title(verb))
@@ -230,16 +491,35 @@ type FakePVControl struct {
 	PVIndexer       cache.Indexer
 	updatePVTracker RequestTracker
 	createPVTracker RequestTracker
+	// cacheLagging simulates the lister lagging behind the API server,
+	// the PVs created when it's true are only visible to GetPVLive
+	cacheLagging bool
+	laggingPVs   map[string]*corev1.PersistentVolume
 }
 
 // NewFakePVControl returns a FakePVControl
 func NewFakePVControl(pvInformer coreinformers.PersistentVolumeInformer, pvcInformer coreinformers.PersistentVolumeClaimInformer) *FakePVControl {
 	return &FakePVControl{
-		pvcInformer.Lister(),
-		pvInformer.Informer().GetIndexer(),
-		RequestTracker{},
-		RequestTracker{},
+		PVCLister:  pvcInformer.Lister(),
+		PVIndexer:  pvInformer.Informer().GetIndexer(),
+		laggingPVs: map[string]*corev1.PersistentVolume{},
+	}
+}
+
+// SetCacheLagging sets whether the lister lags behind the API server, the PVs created
+// when lagging are synced to PVIndexer after the lagging is turned off
+func (c *FakePVControl) SetCacheLagging(lagging bool) error {
+	c.cacheLagging = lagging
+	if lagging {
+		return nil
+	}
+	for name, pv := range c.laggingPVs {
+		if err := c.PVIndexer.Add(pv); err != nil {
+			return err
+		}
+		delete(c.laggingPVs, name)
 	}
+	return nil
 }
 
 // SetUpdatePVError sets the error attributes of updatePVTracker
@@ -254,9 +534,53 @@ func (c *FakePVControl) PatchPVReclaimPolicy(_ runtime.Object, pv *corev1.Persis
 		defer c.updatePVTracker.Reset()
 		return c.updatePVTracker.GetError()
 	}
+	pv = pv.DeepCopy()
+	if original := pv.Spec.PersistentVolumeReclaimPolicy; original != "" && original != reclaimPolicy {
+		if pv.Annotations == nil {
+			pv.Annotations = map[string]string{}
+		}
+		pv.Annotations[label.AnnOriginalReclaimPolicyKey] = string(original)
+	}
 	pv.Spec.PersistentVolumeReclaimPolicy = reclaimPolicy
+	return c.updatePVReclaimPolicy(pv)
+}
 
-	return c.PVIndexer.Update(pv)
+// RestorePVReclaimPolicy reverts the reclaim policy of PV to the one recorded
+func (c *FakePVControl) RestorePVReclaimPolicy(_ runtime.Object, pv *corev1.PersistentVolume) error {
+	defer c.updatePVTracker.Inc()
+	if c.updatePVTracker.ErrorReady() {
+		defer c.updatePVTracker.Reset()
+		return c.updatePVTracker.GetError()
+	}
+	original, ok := pv.Annotations[label.AnnOriginalReclaimPolicyKey]
+	if !ok {
+		return nil
+	}
+	pv = pv.DeepCopy()
+	delete(pv.Annotations, label.AnnOriginalReclaimPolicyKey)
+	pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimPolicy(original)
+	return c.updatePVReclaimPolicy(pv)
+}
+
+// updatePVReclaimPolicy updates the PV in PVIndexer and verifies the reclaim policy read back
+func (c *FakePVControl) updatePVReclaimPolicy(pv *corev1.PersistentVolume) error {
+	if err := c.PVIndexer.Update(pv); err != nil {
+		return err
+	}
+
+	obj, exist, err := c.PVIndexer.Get(pv)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return apierrs.NewNotFound(corev1.Resource("persistentvolumes"), pv.Name)
+	}
+	return verifyPVReclaimPolicy(obj.(*corev1.PersistentVolume), pv.Spec.PersistentVolumeReclaimPolicy)
+}
+
+// EnsureRetainPolicy patches the reclaim policy of the data PVs to Retain
+func (c *FakePVControl) EnsureRetainPolicy(obj runtime.Object, pvs []*corev1.PersistentVolume) (int, error) {
+	return ensureRetainPolicy(c, obj, pvs)
 }
 
 // UpdateMetaInfo update the meta info of pv
@@ -290,9 +614,22 @@ func (c *FakePVControl) UpdateMetaInfo(obj runtime.Object, pv *corev1.Persistent
 	pv.Labels[label.ComponentLabelKey] = pvc.Labels[label.ComponentLabelKey]
 
 	setIfNotEmpty(pv.Labels, label.ClusterIDLabelKey, pvc.Labels[label.ClusterIDLabelKey])
-	setIfNotEmpty(pv.Labels, label.MemberIDLabelKey, pvc.Labels[label.MemberIDLabelKey])
-	setIfNotEmpty(pv.Labels, label.StoreIDLabelKey, pvc.Labels[label.StoreIDLabelKey])
+	setOrDelete(pv.Labels, label.MemberIDLabelKey, pvc.Labels[label.MemberIDLabelKey])
+	setOrDelete(pv.Labels, label.StoreIDLabelKey, pvc.Labels[label.StoreIDLabelKey])
+	setIfNotEmpty(pv.Labels, label.NodeLabelKey, localPVNodeName(pv))
 	setIfNotEmpty(pv.Annotations, label.AnnPodNameKey, pvc.Annotations[label.AnnPodNameKey])
+	pv.Annotations[label.AnnBackupEligibleKey] = backupEligibleVal(pvc.Labels[label.ComponentLabelKey])
+	return pv, c.PVIndexer.Update(pv)
+}
+
+// UpdatePV updates the PV in PVIndexer
+func (c *FakePVControl) UpdatePV(_ runtime.Object, pv *corev1.PersistentVolume) (*corev1.PersistentVolume, error) {
+	defer c.updatePVTracker.Inc()
+	if c.updatePVTracker.ErrorReady() {
+		defer c.updatePVTracker.Reset()
+		return nil, c.updatePVTracker.GetError()
+	}
+
 	return pv, c.PVIndexer.Update(pv)
 }
 
@@ -303,6 +640,8 @@ func (c *FakePVControl) PatchPVClaimRef(obj runtime.Object, pv *corev1.Persisten
 		return c.updatePVTracker.GetError()
 	}
 	pv.Spec.ClaimRef.Name = pvcName
+	pv.Spec.ClaimRef.ResourceVersion = ""
+	pv.Spec.ClaimRef.UID = ""
 
 	return c.PVIndexer.Update(pv)
 }
@@ -315,6 +654,10 @@ func (c *FakePVControl) CreatePV(_ runtime.Object, pv *corev1.PersistentVolume)
 		return c.createPVTracker.GetError()
 	}
 
+	if c.cacheLagging {
+		c.laggingPVs[pv.GetName()] = pv
+		return nil
+	}
 	return c.PVIndexer.Add(pv)
 }
 
@@ -325,10 +668,42 @@ func (c *FakePVControl) GetPV(name string) (*corev1.PersistentVolume, error) {
 		return nil, err
 	}
 	if !existed {
-		return nil, fmt.Errorf("pvc[%s] not existed", name)
+		return nil, apierrs.NewNotFound(corev1.Resource("persistentvolumes"), name)
 	}
 	a := obj.(*corev1.PersistentVolume)
 	return a, nil
 }
 
+// FindOrphanedPVs returns the PVs in PVIndexer whose claimRef targets a PVC not in PVCLister
+func (c *FakePVControl) FindOrphanedPVs(selector labels.Selector) ([]*corev1.PersistentVolume, error) {
+	var pvs []*corev1.PersistentVolume
+	err := cache.ListAll(c.PVIndexer, selector, func(obj interface{}) {
+		pvs = append(pvs, obj.(*corev1.PersistentVolume))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return findOrphanedPVs(pvs, c.PVCLister)
+}
+
+// GetPVLive gets the PV from PVIndexer and the PVs not synced to PVIndexer yet
+func (c *FakePVControl) GetPVLive(name string) (*corev1.PersistentVolume, error) {
+	if pv, ok := c.laggingPVs[name]; ok {
+		return pv, nil
+	}
+	obj, existed, err := c.PVIndexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !existed {
+		return nil, apierrs.NewNotFound(corev1.Resource("persistentvolumes"), name)
+	}
+	return obj.(*corev1.PersistentVolume), nil
+}
+
+// GetPVWithRetry polls GetPVLive of the fake until the PV appears or the timeout passes
+func (c *FakePVControl) GetPVWithRetry(name string, timeout time.Duration) (*corev1.PersistentVolume, error) {
+	return getPVWithRetry(c.GetPVLive, name, timeout)
+}
+
 var _ PVControlInterface = &FakePVControl{}
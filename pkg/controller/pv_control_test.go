@@ -16,8 +16,10 @@ package controller
 import (
 	"errors"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,10 +37,15 @@ func TestPVControlPatchPVReclaimPolicySuccess(t *testing.T) {
 	fakeClient, pvcInformer, pvInformer, recorder := newFakeRecorderAndPVCInformer()
 	tc := newTidbCluster()
 	pv := newPV()
-	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder)
+	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder, nil)
 	fakeClient.AddReactor("patch", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
 		return true, nil, nil
 	})
+	fakeClient.AddReactor("get", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
+		patched := pv.DeepCopy()
+		patched.Spec.PersistentVolumeReclaimPolicy = *tc.Spec.PVReclaimPolicy
+		return true, patched, nil
+	})
 	err := control.PatchPVReclaimPolicy(tc, pv, *tc.Spec.PVReclaimPolicy)
 	g.Expect(err).To(Succeed())
 
@@ -52,7 +59,7 @@ func TestPVControlPatchPVReclaimPolicyFailed(t *testing.T) {
 	fakeClient, pvcInformer, pvInformer, recorder := newFakeRecorderAndPVCInformer()
 	tc := newTidbCluster()
 	pv := newPV()
-	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder)
+	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder, nil)
 	fakeClient.AddReactor("patch", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
 		return true, nil, apierrors.NewInternalError(errors.New("API server down"))
 	})
@@ -69,7 +76,7 @@ func TestPVControlPatchPVReclaimPolicyConflictSuccess(t *testing.T) {
 	fakeClient, pvcInformer, pvInformer, recorder := newFakeRecorderAndPVCInformer()
 	tc := newTidbCluster()
 	pv := newPV()
-	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder)
+	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder, nil)
 
 	conflict := false
 	fakeClient.AddReactor("patch", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
@@ -79,6 +86,11 @@ func TestPVControlPatchPVReclaimPolicyConflictSuccess(t *testing.T) {
 		}
 		return true, nil, nil
 	})
+	fakeClient.AddReactor("get", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
+		patched := pv.DeepCopy()
+		patched.Spec.PersistentVolumeReclaimPolicy = *tc.Spec.PVReclaimPolicy
+		return true, patched, nil
+	})
 	err := control.PatchPVReclaimPolicy(tc, pv, *tc.Spec.PVReclaimPolicy)
 	g.Expect(err).To(Succeed())
 
@@ -87,6 +99,99 @@ func TestPVControlPatchPVReclaimPolicyConflictSuccess(t *testing.T) {
 	g.Expect(events[0]).To(ContainSubstring(corev1.EventTypeNormal))
 }
 
+func TestPVControlPatchPVReclaimPolicyVerifyFailed(t *testing.T) {
+	g := NewGomegaWithT(t)
+	fakeClient, pvcInformer, pvInformer, recorder := newFakeRecorderAndPVCInformer()
+	tc := newTidbCluster()
+	pv := newPV()
+	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder, nil)
+	// an admission controller changes the patched reclaim policy back to Delete
+	fakeClient.AddReactor("patch", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+	fakeClient.AddReactor("get", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
+		admitted := pv.DeepCopy()
+		admitted.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimDelete
+		return true, admitted, nil
+	})
+	err := control.PatchPVReclaimPolicy(tc, pv, corev1.PersistentVolumeReclaimRetain)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("reclaim policy of PV pv-1 is Delete after patching it to Retain"))
+
+	events := collectEvents(recorder.Events)
+	g.Expect(events).To(HaveLen(1))
+	g.Expect(events[0]).To(ContainSubstring(corev1.EventTypeWarning))
+}
+
+func TestFakePVControlPatchPVReclaimPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTidbCluster()
+	_, pvcInformer, pvInformer, _ := newFakeRecorderAndPVCInformer()
+	control := NewFakePVControl(pvInformer, pvcInformer)
+	pv := newPV()
+	g.Expect(control.PVIndexer.Add(pv)).To(Succeed())
+
+	g.Expect(control.PatchPVReclaimPolicy(tc, pv, corev1.PersistentVolumeReclaimRetain)).To(Succeed())
+	patched, err := control.GetPV(pv.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(patched.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+	// the PV passed in is not modified
+	g.Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimDelete))
+}
+
+func TestPVControlRestorePVReclaimPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+	_, pvcInformer, pvInformer, recorder := newFakeRecorderAndPVCInformer()
+	tc := newTidbCluster()
+	pv := newPV()
+	pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+	fakeClient := fake.NewSimpleClientset(pv)
+	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder, nil)
+
+	// the policy before the change is stamped
+	g.Expect(control.PatchPVReclaimPolicy(tc, pv, corev1.PersistentVolumeReclaimDelete)).To(Succeed())
+	patched, err := control.GetPVLive(pv.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(patched.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimDelete))
+	g.Expect(patched.Annotations).To(HaveKeyWithValue(label.AnnOriginalReclaimPolicyKey, string(corev1.PersistentVolumeReclaimRetain)))
+
+	// the policy is reverted and the stamp is removed
+	g.Expect(control.RestorePVReclaimPolicy(tc, patched)).To(Succeed())
+	restored, err := control.GetPVLive(pv.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(restored.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+	g.Expect(restored.Annotations).NotTo(HaveKey(label.AnnOriginalReclaimPolicyKey))
+
+	// nothing is restored without the stamp
+	g.Expect(control.RestorePVReclaimPolicy(tc, restored)).To(Succeed())
+	events := collectEvents(recorder.Events)
+	g.Expect(events).To(HaveLen(2))
+	g.Expect(events[0]).To(ContainSubstring("SuccessfulPatch"))
+	g.Expect(events[1]).To(ContainSubstring("SuccessfulRestore"))
+}
+
+func TestFakePVControlRestorePVReclaimPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTidbCluster()
+	_, pvcInformer, pvInformer, _ := newFakeRecorderAndPVCInformer()
+	control := NewFakePVControl(pvInformer, pvcInformer)
+	pv := newPV()
+	g.Expect(control.PVIndexer.Add(pv)).To(Succeed())
+
+	g.Expect(control.PatchPVReclaimPolicy(tc, pv, corev1.PersistentVolumeReclaimRetain)).To(Succeed())
+	patched, err := control.GetPV(pv.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(patched.Annotations).To(HaveKeyWithValue(label.AnnOriginalReclaimPolicyKey, string(corev1.PersistentVolumeReclaimDelete)))
+
+	g.Expect(control.RestorePVReclaimPolicy(tc, patched)).To(Succeed())
+	restored, err := control.GetPV(pv.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(restored.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimDelete))
+	g.Expect(restored.Annotations).NotTo(HaveKey(label.AnnOriginalReclaimPolicyKey))
+	// the PV passed in is not modified
+	g.Expect(patched.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+}
+
 func TestPVControlUpdateMetaInfoSuccess(t *testing.T) {
 	g := NewGomegaWithT(t)
 	tc := newTidbCluster()
@@ -95,7 +200,7 @@ func TestPVControlUpdateMetaInfoSuccess(t *testing.T) {
 	pvc := newPVC(tc)
 	fakeClient, pvcInformer, pvInformer, recorder := newFakeRecorderAndPVCInformer()
 	pvcInformer.Informer().GetIndexer().Add(pvc)
-	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder)
+	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder, nil)
 	fakeClient.AddReactor("get", "persistentvolumeclaims", func(action core.Action) (bool, runtime.Object, error) {
 		return true, nil, nil
 	})
@@ -115,7 +220,7 @@ func TestPVControlUpdateMetaInfoUpdatePVFailed(t *testing.T) {
 	pvc := newPVC(tc)
 	fakeClient, pvcInformer, pvInformer, recorder := newFakeRecorderAndPVCInformer()
 	pvcInformer.Informer().GetIndexer().Add(pvc)
-	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder)
+	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder, nil)
 	fakeClient.AddReactor("get", "persistentvolumeclaims", func(action core.Action) (bool, runtime.Object, error) {
 		return true, nil, nil
 	})
@@ -131,7 +236,7 @@ func TestPVControlUpdateMetaInfoGetPVCFailed(t *testing.T) {
 	tc := newTidbCluster()
 	pv := newPV()
 	fakeClient, pvcInformer, pvInformer, recorder := newFakeRecorderAndPVCInformer()
-	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder)
+	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder, nil)
 	fakeClient.AddReactor("get", "persistentvolumeclaims", func(action core.Action) (bool, runtime.Object, error) {
 		return true, nil, apierrors.NewNotFound(action.GetResource().GroupResource(), action.GetResource().Resource)
 	})
@@ -152,7 +257,7 @@ func TestPVControlUpdateMetaInfoConflictSuccess(t *testing.T) {
 	fakeClient, pvcInformer, pvInformer, recorder := newFakeRecorderAndPVCInformer()
 	pvcInformer.Informer().GetIndexer().Add(pvc)
 	pvInformer.Informer().GetIndexer().Add(oldPV)
-	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder)
+	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder, nil)
 	fakeClient.AddReactor("get", "persistentvolumeclaims", func(action core.Action) (bool, runtime.Object, error) {
 		return true, nil, nil
 	})
@@ -170,6 +275,421 @@ func TestPVControlUpdateMetaInfoConflictSuccess(t *testing.T) {
 	g.Expect(updatePV.Annotations["a"]).To(Equal("b"))
 }
 
+func TestPVControlUpdateMetaInfoExtraLabels(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTidbCluster()
+	pv := newPV()
+	pvc := newPVC(tc)
+	pvc.Labels = map[string]string{"topology.example.com/rack": "rack-1"}
+	fakeClient, pvcInformer, pvInformer, recorder := newFakeRecorderAndPVCInformer()
+	pvcInformer.Informer().GetIndexer().Add(pvc)
+	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder, []string{"topology.example.com/rack"})
+	updated := 0
+	fakeClient.AddReactor("update", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
+		updated++
+		update := action.(core.UpdateAction)
+		return true, update.GetObject(), nil
+	})
+	updatePV, err := control.UpdateMetaInfo(tc, pv)
+	g.Expect(err).To(Succeed())
+	g.Expect(updated).To(Equal(1))
+	g.Expect(updatePV.Labels["topology.example.com/rack"]).To(Equal("rack-1"))
+
+	// already synced, skip updating
+	_, err = control.UpdateMetaInfo(tc, updatePV)
+	g.Expect(err).To(Succeed())
+	g.Expect(updated).To(Equal(1))
+
+	// the extra label changes on the PVC
+	pvc = pvc.DeepCopy()
+	pvc.Labels["topology.example.com/rack"] = "rack-2"
+	pvcInformer.Informer().GetIndexer().Update(pvc)
+	updatePV, err = control.UpdateMetaInfo(tc, updatePV)
+	g.Expect(err).To(Succeed())
+	g.Expect(updated).To(Equal(2))
+	g.Expect(updatePV.Labels["topology.example.com/rack"]).To(Equal("rack-2"))
+
+	// the extra label with an empty value is synced once
+	pvc = pvc.DeepCopy()
+	pvc.Labels["topology.example.com/rack"] = ""
+	pvcInformer.Informer().GetIndexer().Update(pvc)
+	updatePV, err = control.UpdateMetaInfo(tc, updatePV)
+	g.Expect(err).To(Succeed())
+	g.Expect(updated).To(Equal(3))
+	g.Expect(updatePV.Labels).To(HaveKeyWithValue("topology.example.com/rack", ""))
+	_, err = control.UpdateMetaInfo(tc, updatePV)
+	g.Expect(err).To(Succeed())
+	g.Expect(updated).To(Equal(3))
+}
+
+func TestPVControlUpdateMetaInfoBackupEligible(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name      string
+		component string
+		expectVal string
+	}
+	tests := []testcase{
+		{name: "tikv data volume", component: label.TiKVLabelVal, expectVal: "true"},
+		{name: "pd data volume", component: label.PDLabelVal, expectVal: "true"},
+		{name: "monitor volume", component: label.TiDBMonitorVal, expectVal: "false"},
+	}
+
+	for _, test := range tests {
+		t.Log(test.name)
+		tc := newTidbCluster()
+		pv := newPV()
+		pvc := newPVC(tc)
+		pvc.Labels = map[string]string{label.ComponentLabelKey: test.component}
+		fakeClient, pvcInformer, pvInformer, recorder := newFakeRecorderAndPVCInformer()
+		pvcInformer.Informer().GetIndexer().Add(pvc)
+		control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder, nil)
+		updated := 0
+		fakeClient.AddReactor("update", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
+			updated++
+			update := action.(core.UpdateAction)
+			return true, update.GetObject(), nil
+		})
+		updatePV, err := control.UpdateMetaInfo(tc, pv)
+		g.Expect(err).To(Succeed())
+		g.Expect(updated).To(Equal(1))
+		g.Expect(updatePV.Annotations[label.AnnBackupEligibleKey]).To(Equal(test.expectVal))
+
+		// already synced, skip updating
+		_, err = control.UpdateMetaInfo(tc, updatePV)
+		g.Expect(err).To(Succeed())
+		g.Expect(updated).To(Equal(1))
+
+		// the annotation is restored if it's changed
+		updatePV.Annotations[label.AnnBackupEligibleKey] = ""
+		updatePV, err = control.UpdateMetaInfo(tc, updatePV)
+		g.Expect(err).To(Succeed())
+		g.Expect(updated).To(Equal(2))
+		g.Expect(updatePV.Annotations[label.AnnBackupEligibleKey]).To(Equal(test.expectVal))
+	}
+}
+
+func TestPVControlUpdateMetaInfoLocalPVNode(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	localPV := func(affinity *corev1.VolumeNodeAffinity) *corev1.PersistentVolume {
+		pv := newPV()
+		pv.Spec.Local = &corev1.LocalVolumeSource{Path: "/mnt/disks/ssd0"}
+		pv.Spec.NodeAffinity = affinity
+		return pv
+	}
+	hostnameAffinity := &corev1.VolumeNodeAffinity{
+		Required: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      corev1.LabelHostname,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{"node-1"},
+				}},
+			}},
+		},
+	}
+	zoneAffinity := &corev1.VolumeNodeAffinity{
+		Required: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      corev1.LabelZoneFailureDomainStable,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{"zone-a"},
+				}},
+			}},
+		},
+	}
+	cloudPV := newPV()
+	cloudPV.Spec.NodeAffinity = zoneAffinity
+
+	type testcase struct {
+		name       string
+		pv         *corev1.PersistentVolume
+		expectNode string
+	}
+	tests := []testcase{
+		{name: "local pv pinned to a node", pv: localPV(hostnameAffinity), expectNode: "node-1"},
+		{name: "local pv without affinity", pv: localPV(nil), expectNode: ""},
+		{name: "local pv not pinned to a node", pv: localPV(zoneAffinity), expectNode: ""},
+		{name: "non-local pv", pv: cloudPV, expectNode: ""},
+	}
+
+	for _, test := range tests {
+		t.Log(test.name)
+		tc := newTidbCluster()
+		pvc := newPVC(tc)
+		fakeClient, pvcInformer, pvInformer, recorder := newFakeRecorderAndPVCInformer()
+		pvcInformer.Informer().GetIndexer().Add(pvc)
+		control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder, nil)
+		updated := 0
+		fakeClient.AddReactor("update", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
+			updated++
+			update := action.(core.UpdateAction)
+			return true, update.GetObject(), nil
+		})
+		updatePV, err := control.UpdateMetaInfo(tc, test.pv)
+		g.Expect(err).To(Succeed())
+		g.Expect(updated).To(Equal(1))
+		node, ok := updatePV.Labels[label.NodeLabelKey]
+		g.Expect(ok).To(Equal(test.expectNode != ""))
+		g.Expect(node).To(Equal(test.expectNode))
+
+		// already synced, skip updating
+		_, err = control.UpdateMetaInfo(tc, updatePV)
+		g.Expect(err).To(Succeed())
+		g.Expect(updated).To(Equal(1))
+	}
+}
+
+func TestPVControlUpdateMetaInfoStoreIDChanged(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name          string
+		pvStoreID     string
+		pvcStoreID    string
+		expectStoreID string
+	}
+	tests := []testcase{
+		{name: "store rebuilt", pvStoreID: "1", pvcStoreID: "2", expectStoreID: "2"},
+		{name: "store id assigned", pvStoreID: "", pvcStoreID: "2", expectStoreID: "2"},
+		{name: "store id removed", pvStoreID: "1", pvcStoreID: "", expectStoreID: ""},
+	}
+
+	for _, test := range tests {
+		t.Log(test.name)
+		tc := newTidbCluster()
+		pvc := newPVC(tc)
+		pvc.Labels = map[string]string{}
+		if test.pvcStoreID != "" {
+			pvc.Labels[label.StoreIDLabelKey] = test.pvcStoreID
+		}
+		pv := newPV()
+		pv.Labels = map[string]string{}
+		if test.pvStoreID != "" {
+			pv.Labels[label.StoreIDLabelKey] = test.pvStoreID
+		}
+		fakeClient, pvcInformer, pvInformer, recorder := newFakeRecorderAndPVCInformer()
+		pvcInformer.Informer().GetIndexer().Add(pvc)
+		control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder, nil)
+		updated := 0
+		fakeClient.AddReactor("update", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
+			updated++
+			update := action.(core.UpdateAction)
+			return true, update.GetObject(), nil
+		})
+		updatePV, err := control.UpdateMetaInfo(tc, pv)
+		g.Expect(err).To(Succeed())
+		g.Expect(updated).To(Equal(1))
+		storeID, ok := updatePV.Labels[label.StoreIDLabelKey]
+		g.Expect(ok).To(Equal(test.expectStoreID != ""))
+		g.Expect(storeID).To(Equal(test.expectStoreID))
+
+		// already synced, skip updating
+		_, err = control.UpdateMetaInfo(tc, updatePV)
+		g.Expect(err).To(Succeed())
+		g.Expect(updated).To(Equal(1))
+	}
+}
+
+func TestPVControlUpdatePVConflictSuccess(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTidbCluster()
+	pv := newPV()
+	pv.OwnerReferences = []metav1.OwnerReference{{APIVersion: "v1", Kind: "ConfigMap", Name: "owner", UID: types.UID("owner")}}
+	oldPV := newPV()
+	fakeClient, _, pvInformer, recorder := newFakeRecorderAndPVCInformer()
+	pvInformer.Informer().GetIndexer().Add(oldPV)
+	control := NewRealPVControl(fakeClient, nil, pvInformer.Lister(), recorder, nil)
+	conflict := false
+	fakeClient.AddReactor("update", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
+		update := action.(core.UpdateAction)
+		if !conflict {
+			conflict = true
+			return true, oldPV, apierrors.NewConflict(action.GetResource().GroupResource(), pv.Name, errors.New("conflict"))
+		}
+		return true, update.GetObject(), nil
+	})
+	updatePV, err := control.UpdatePV(tc, pv)
+	g.Expect(err).To(Succeed())
+	g.Expect(conflict).To(BeTrue())
+	g.Expect(updatePV.OwnerReferences).To(Equal(pv.OwnerReferences))
+
+	events := collectEvents(recorder.Events)
+	g.Expect(events).To(HaveLen(1))
+	g.Expect(events[0]).To(ContainSubstring(corev1.EventTypeNormal))
+}
+
+func TestPVControlGetPVLive(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTidbCluster()
+	pv := newPV()
+	fakeClient, _, pvInformer, recorder := newFakeRecorderAndPVCInformer()
+	control := NewRealPVControl(fakeClient, nil, pvInformer.Lister(), recorder, nil)
+	fakeClient.AddReactor("create", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
+		create := action.(core.CreateAction)
+		return true, create.GetObject(), nil
+	})
+	fakeClient.AddReactor("get", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
+		return true, pv, nil
+	})
+	g.Expect(control.CreatePV(tc, pv)).To(Succeed())
+
+	// the lister has not synced the created PV yet
+	_, err := control.GetPV(pv.Name)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	livePV, err := control.GetPVLive(pv.Name)
+	g.Expect(err).To(Succeed())
+	g.Expect(livePV.Name).To(Equal(pv.Name))
+}
+
+func TestPVControlGetPVWithRetry(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTidbCluster()
+	pv := newPV()
+	_, _, pvInformer, recorder := newFakeRecorderAndPVCInformer()
+	// the PVs are tracked by the client to be read back
+	fakeClient := fake.NewSimpleClientset()
+	control := NewRealPVControl(fakeClient, nil, pvInformer.Lister(), recorder, nil)
+	// the PV is not found by the first gets
+	gets := 0
+	fakeClient.PrependReactor("get", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
+		gets++
+		if gets < 3 {
+			return true, nil, apierrors.NewNotFound(corev1.Resource("persistentvolumes"), pv.Name)
+		}
+		return false, nil, nil
+	})
+	g.Expect(control.CreatePV(tc, pv)).To(Succeed())
+
+	got, err := control.GetPVWithRetry(pv.Name, 5*time.Second)
+	g.Expect(err).To(Succeed())
+	g.Expect(got.Name).To(Equal(pv.Name))
+	g.Expect(gets).To(Equal(3))
+
+	// time out if the PV never appears
+	_, err = control.GetPVWithRetry("not-exist", 300*time.Millisecond)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("timed out waiting for PV not-exist"))
+}
+
+func TestFakePVControlGetPVLive(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTidbCluster()
+	_, pvcInformer, pvInformer, _ := newFakeRecorderAndPVCInformer()
+	control := NewFakePVControl(pvInformer, pvcInformer)
+
+	// the PV created when the lister lags is only visible to GetPVLive
+	g.Expect(control.SetCacheLagging(true)).To(Succeed())
+	pv := newPV()
+	g.Expect(control.CreatePV(tc, pv)).To(Succeed())
+	_, err := control.GetPV(pv.Name)
+	g.Expect(err).To(HaveOccurred())
+	livePV, err := control.GetPVLive(pv.Name)
+	g.Expect(err).To(Succeed())
+	g.Expect(livePV).To(Equal(pv))
+
+	// both see the PV after the lister catches up
+	g.Expect(control.SetCacheLagging(false)).To(Succeed())
+	cachedPV, err := control.GetPV(pv.Name)
+	g.Expect(err).To(Succeed())
+	g.Expect(cachedPV).To(Equal(pv))
+	livePV, err = control.GetPVLive(pv.Name)
+	g.Expect(err).To(Succeed())
+	g.Expect(livePV).To(Equal(pv))
+
+	_, err = control.GetPVLive("not-exist")
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestPVControlFindOrphanedPVs(t *testing.T) {
+	g := NewGomegaWithT(t)
+	fakeClient, pvcInformer, pvInformer, recorder := newFakeRecorderAndPVCInformer()
+	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder, nil)
+
+	clusterLabels := label.New().Instance("demo")
+	bound := newPV()
+	bound.Labels = clusterLabels.Labels()
+	orphaned := newPV()
+	orphaned.Name = "pv-2"
+	orphaned.Labels = clusterLabels.Labels()
+	orphaned.Spec.ClaimRef.Name = "pvc-2"
+	// the orphaned PV of another cluster is not selected
+	otherOrphaned := newPV()
+	otherOrphaned.Name = "pv-3"
+	otherOrphaned.Labels = label.New().Instance("other").Labels()
+	otherOrphaned.Spec.ClaimRef.Name = "pvc-3"
+	for _, pv := range []*corev1.PersistentVolume{bound, orphaned, otherOrphaned} {
+		g.Expect(pvInformer.Informer().GetIndexer().Add(pv)).To(Succeed())
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pvc-1",
+			Namespace: corev1.NamespaceDefault,
+			UID:       types.UID("test"),
+		},
+	}
+	g.Expect(pvcInformer.Informer().GetIndexer().Add(pvc)).To(Succeed())
+
+	selector, err := clusterLabels.Selector()
+	g.Expect(err).NotTo(HaveOccurred())
+	orphans, err := control.FindOrphanedPVs(selector)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(orphans).To(Equal([]*corev1.PersistentVolume{orphaned}))
+
+	// the PVC recreated with the same name is not the one claimed
+	pvc = pvc.DeepCopy()
+	pvc.UID = types.UID("recreated")
+	g.Expect(pvcInformer.Informer().GetIndexer().Update(pvc)).To(Succeed())
+	orphans, err = control.FindOrphanedPVs(selector)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(orphans).To(ConsistOf(bound, orphaned))
+}
+
+func TestPVControlEnsureRetainPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+	fakeClient, pvcInformer, pvInformer, recorder := newFakeRecorderAndPVCInformer()
+	tc := newTidbCluster()
+	control := NewRealPVControl(fakeClient, pvcInformer.Lister(), pvInformer.Lister(), recorder, nil)
+
+	tikv := newPV()
+	tikv.Labels = map[string]string{label.ComponentLabelKey: label.TiKVLabelVal}
+	// the data PV retained already is not patched
+	pd := newPV()
+	pd.Name = "pv-2"
+	pd.Labels = map[string]string{label.ComponentLabelKey: label.PDLabelVal}
+	pd.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+	// the PV without data is skipped
+	tidb := newPV()
+	tidb.Name = "pv-3"
+	tidb.Labels = map[string]string{label.ComponentLabelKey: label.TiDBLabelVal}
+
+	patches := map[string]string{}
+	fakeClient.AddReactor("patch", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
+		patch := action.(core.PatchAction)
+		patches[patch.GetName()] = string(patch.GetPatch())
+		return true, nil, nil
+	})
+	fakeClient.AddReactor("get", "persistentvolumes", func(action core.Action) (bool, runtime.Object, error) {
+		patched := newPV()
+		patched.Name = action.(core.GetAction).GetName()
+		patched.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+		return true, patched, nil
+	})
+	changed, err := control.EnsureRetainPolicy(tc, []*corev1.PersistentVolume{tikv, pd, tidb})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changed).To(Equal(1))
+	g.Expect(patches).To(Equal(map[string]string{
+		tikv.Name: `{"metadata":{"annotations":{"tidb.pingcap.com/original-reclaim-policy":"Delete"}},"spec":{"persistentVolumeReclaimPolicy":"Retain"}}`,
+	}))
+
+	events := collectEvents(recorder.Events)
+	g.Expect(events).To(HaveLen(1))
+	g.Expect(events[0]).To(ContainSubstring(tikv.Name))
+}
+
 func newFakeRecorderAndPVCInformer() (*fake.Clientset, coreinformers.PersistentVolumeClaimInformer, coreinformers.PersistentVolumeInformer, *record.FakeRecorder) {
 	fakeClient := &fake.Clientset{}
 	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(fakeClient, 0)
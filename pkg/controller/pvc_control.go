@@ -98,10 +98,11 @@ func (c *realPVCControl) CreatePVC(controller runtime.Object, pvc *corev1.Persis
 	_, err := c.kubeCli.CoreV1().PersistentVolumeClaims(namespace).Create(context.TODO(), pvc, metav1.CreateOptions{})
 	if err != nil {
 		klog.Errorf("failed to create PVC: [%s/%s], %s: %s, %v", namespace, pvcName, kind, name, err)
+	} else {
+		klog.V(4).Infof("create PVC: [%s/%s] successfully, %s: %s", namespace, pvcName, kind, name)
 	}
-	klog.V(4).Infof("create PVC: [%s/%s] successfully, %s: %s", namespace, pvcName, kind, name)
 	c.recordPVCEvent("create", kind, name, controller, pvcName, err)
-	return nil
+	return err
 }
 
 func (c *realPVCControl) UpdatePVC(controller runtime.Object, pvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
@@ -117,6 +118,7 @@ func (c *realPVCControl) UpdatePVC(controller runtime.Object, pvc *corev1.Persis
 
 	labels := pvc.GetLabels()
 	ann := pvc.GetAnnotations()
+	ownerRefs := pvc.GetOwnerReferences()
 	var updatePVC *corev1.PersistentVolumeClaim
 	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		var updateErr error
@@ -132,6 +134,7 @@ func (c *realPVCControl) UpdatePVC(controller runtime.Object, pvc *corev1.Persis
 			pvc = updated.DeepCopy()
 			pvc.Labels = labels
 			pvc.Annotations = ann
+			pvc.OwnerReferences = ownerRefs
 		} else {
 			utilruntime.HandleError(fmt.Errorf("error getting updated PVC %s/%s from lister: %v", namespace, pvcName, err))
 		}
@@ -165,11 +168,13 @@ func (c *realPVCControl) UpdateMetaInfo(controller runtime.Object, pvc *corev1.P
 		pvc.Labels = make(map[string]string)
 	}
 
+	standardLabels := label.Label(pvc.Labels).Standard()
 	if pvc.Labels[label.ClusterIDLabelKey] == clusterID &&
 		pvc.Labels[label.MemberIDLabelKey] == memberID &&
 		pvc.Labels[label.StoreIDLabelKey] == storeID &&
 		pvc.Labels[label.AnnPodNameKey] == podName &&
-		pvc.Annotations[label.AnnPodNameKey] == podName {
+		pvc.Annotations[label.AnnPodNameKey] == podName &&
+		hasLabels(pvc.Labels, standardLabels) {
 		klog.V(4).Infof("pvc %s/%s already has labels and annotations synced, skipping, %s: %s", namespace, pvcName, kind, name)
 		return pvc, nil
 	}
@@ -179,6 +184,9 @@ func (c *realPVCControl) UpdateMetaInfo(controller runtime.Object, pvc *corev1.P
 	setIfNotEmpty(pvc.Labels, label.StoreIDLabelKey, storeID)
 	setIfNotEmpty(pvc.Labels, label.AnnPodNameKey, podName)
 	setIfNotEmpty(pvc.Annotations, label.AnnPodNameKey, podName)
+	for k, v := range standardLabels {
+		pvc.Labels[k] = v
+	}
 
 	labels := pvc.GetLabels()
 	ann := pvc.GetAnnotations()
@@ -250,6 +258,11 @@ func (c *FakePVCControl) SetDeletePVCError(err error, after int) {
 	c.deletePVCTracker.SetError(err).SetAfter(after)
 }
 
+// SetCreatePVCError sets the error attributes of createPVCTracker
+func (c *FakePVCControl) SetCreatePVCError(err error, after int) {
+	c.createPVCTracker.SetError(err).SetAfter(after)
+}
+
 // DeletePVC deletes the pvc
 func (c *FakePVCControl) DeletePVC(_ runtime.Object, pvc *corev1.PersistentVolumeClaim) error {
 	defer c.deletePVCTracker.Inc()
@@ -301,6 +314,9 @@ func (c *FakePVCControl) UpdateMetaInfo(_ runtime.Object, pvc *corev1.Persistent
 	setIfNotEmpty(pvc.Labels, label.StoreIDLabelKey, pod.Labels[label.StoreIDLabelKey])
 	setIfNotEmpty(pvc.Labels, label.AnnPodNameKey, pod.GetName())
 	setIfNotEmpty(pvc.Annotations, label.AnnPodNameKey, pod.GetName())
+	for k, v := range label.Label(pvc.Labels).Standard() {
+		pvc.Labels[k] = v
+	}
 	return nil, c.PVCIndexer.Update(pvc)
 }
 
@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/backup/restore"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -52,14 +53,17 @@ func NewController(deps *controller.Dependencies) *Controller {
 		),
 	}
 
-	restoreInformer := deps.InformerFactory.Pingcap().V1alpha1().Restores()
-	restoreInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: c.updateRestore,
-		UpdateFunc: func(old, cur interface{}) {
-			c.updateRestore(cur)
-		},
-		DeleteFunc: c.enqueueRestore,
-	})
+	for _, informer := range deps.Informers(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().Restores().Informer()
+	}) {
+		informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: c.updateRestore,
+			UpdateFunc: func(old, cur interface{}) {
+				c.updateRestore(cur)
+			},
+			DeleteFunc: c.enqueueRestore,
+		})
+	}
 	return c
 }
 
@@ -155,6 +159,14 @@ func (c *Controller) updateRestore(cur interface{}) {
 		return
 	}
 
+	if v1alpha1.IsRestoreVerifying(newRestore) {
+		// the conditions Scheduled and Running are still true when the restored data is being verified,
+		// so check it first to sync the verify job.
+		klog.V(4).Infof("restore %s/%s is Verifying, enqueue", ns, name)
+		c.enqueueRestore(newRestore)
+		return
+	}
+
 	if v1alpha1.IsRestoreScheduled(newRestore) || v1alpha1.IsRestoreRunning(newRestore) {
 		selector, err := label.NewRestore().Instance(newRestore.GetInstanceName()).RestoreJob().Restore(name).Selector()
 		if err != nil {
@@ -124,6 +124,13 @@ func TestRestoreControllerUpdateRestore(t *testing.T) {
 			},
 			afterUpdateFn: updatingToFail,
 		},
+		{
+			name:          "restore is verifying",
+			conditionType: v1alpha1.RestoreVerifying,
+			expectFn: func(g *GomegaWithT, rtc *Controller) {
+				g.Expect(rtc.queue.Len()).To(Equal(1))
+			},
+		},
 		{
 			name:           "restore has been running with failed pod",
 			conditionType:  v1alpha1.RestoreRunning,
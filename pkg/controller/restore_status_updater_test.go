@@ -65,6 +65,7 @@ func newUpdateRestoreStatus() *RestoreUpdateStatus {
 		CommitTs:      &ts,
 		TimeCompleted: &metav1.Time{Time: end},
 		TimeStarted:   &metav1.Time{Time: start},
+		Verification:  newRestoreVerificationStatus(),
 	}
 }
 
@@ -101,5 +102,18 @@ func newExpectRestoreStatus() *v1alpha1.RestoreStatus {
 	s.CommitTs = ts
 	s.TimeStarted = metav1.Time{Time: start}
 	s.TimeCompleted = metav1.Time{Time: end}
+	s.Verification = newRestoreVerificationStatus()
 	return s
 }
+
+func newRestoreVerificationStatus() *v1alpha1.RestoreVerificationStatus {
+	return &v1alpha1.RestoreVerificationStatus{
+		Type:          v1alpha1.RestoreVerifyChecksum,
+		PassedTables:  1,
+		SkippedTables: 1,
+		Tables: []v1alpha1.RestoreTableVerification{
+			{Table: "test.t1", Result: v1alpha1.RestoreTableVerificationPassed},
+			{Table: "test.t2", Result: v1alpha1.RestoreTableVerificationSkipped, Message: "table size 200Gi exceeds 100Gi"},
+		},
+	}
+}
@@ -14,10 +14,16 @@
 package tidbcluster
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/configschema"
 	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
 )
 
 // TidbClusterConditionUpdater interface that translates cluster state into
@@ -33,6 +39,8 @@ var _ TidbClusterConditionUpdater = &tidbClusterConditionUpdater{}
 
 func (u *tidbClusterConditionUpdater) Update(tc *v1alpha1.TidbCluster) error {
 	u.updateReadyCondition(tc)
+	u.updateConfigUnknownKeysCondition(tc)
+	u.updatePausedCondition(tc)
 	// in the future, we may return error when we need to Kubernetes API, etc.
 	return nil
 }
@@ -79,3 +87,66 @@ func (u *tidbClusterConditionUpdater) updateReadyCondition(tc *v1alpha1.TidbClus
 	cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterReady, status, reason, message)
 	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
 }
+
+// updateConfigUnknownKeysCondition validates the configs of the components against the config schemas
+// of their versions, and reports the unknown keys by the ConfigUnknownKeys condition. The unknown keys
+// never fail the sync because the schemas may be outdated for the versions.
+func (u *tidbClusterConditionUpdater) updateConfigUnknownKeysCondition(tc *v1alpha1.TidbCluster) {
+	msg := configschema.UnknownKeysMessage(configschema.ValidateTidbCluster(tc))
+	if msg != "" {
+		klog.Warningf("tc %s/%s: %s", tc.Namespace, tc.Name, msg)
+		cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.ConfigUnknownKeys, v1.ConditionTrue, utiltidbcluster.UnknownConfigKeysFound, msg)
+		utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+		return
+	}
+
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.ConfigUnknownKeys)
+	if cond != nil && cond.Status == v1.ConditionTrue {
+		newCond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.ConfigUnknownKeys, v1.ConditionFalse, utiltidbcluster.UnknownConfigKeysRemoved, "all config keys are known")
+		utiltidbcluster.SetTidbClusterCondition(&tc.Status, *newCond)
+	}
+}
+
+// updatePausedCondition sets the Paused condition while the reconciliation of the cluster or any of its components
+// is paused, so that it's visible that the changes of the spec are not applied.
+func (u *tidbClusterConditionUpdater) updatePausedCondition(tc *v1alpha1.TidbCluster) {
+	var msg string
+	if tc.Spec.Paused {
+		msg = "the reconciliation of the tidb cluster is paused"
+	} else {
+		var paused []string
+		for _, component := range []v1alpha1.MemberType{
+			v1alpha1.PDMemberType,
+			v1alpha1.TiKVMemberType,
+			v1alpha1.TiDBMemberType,
+			v1alpha1.TiFlashMemberType,
+			v1alpha1.TiCDCMemberType,
+			v1alpha1.PumpMemberType,
+		} {
+			if tc.ShouldPauseReconcile(component) {
+				paused = append(paused, component.String())
+			}
+		}
+		if len(paused) > 0 {
+			msg = fmt.Sprintf("the reconciliation of %s is paused", strings.Join(paused, ", "))
+		}
+	}
+	if msg != "" {
+		cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterPaused, v1.ConditionTrue, utiltidbcluster.ReconcilePaused, msg)
+		utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+		// the condition with the same status and reason is kept, refresh the message when what is paused changes
+		for i := range tc.Status.Conditions {
+			if c := &tc.Status.Conditions[i]; c.Type == v1alpha1.TidbClusterPaused && c.Message != msg {
+				c.Message = msg
+				c.LastUpdateTime = metav1.Now()
+			}
+		}
+		return
+	}
+
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterPaused)
+	if cond != nil && cond.Status == v1.ConditionTrue {
+		newCond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterPaused, v1.ConditionFalse, utiltidbcluster.ReconcileResumed, "the reconciliation is resumed")
+		utiltidbcluster.SetTidbClusterCondition(&tc.Status, *newCond)
+	}
+}
@@ -360,3 +360,100 @@ func TestTidbClusterConditionUpdater_Ready(t *testing.T) {
 		})
 	}
 }
+
+func TestTidbClusterConditionUpdater_ConfigUnknownKeys(t *testing.T) {
+	tc := &v1alpha1.TidbCluster{
+		Spec: v1alpha1.TidbClusterSpec{
+			Version: "v5.0.1",
+			TiKV: &v1alpha1.TiKVSpec{
+				ComponentSpec: v1alpha1.ComponentSpec{
+					Image: "pingcap/tikv:v5.0.1",
+				},
+				Config: v1alpha1.NewTiKVConfig(),
+			},
+		},
+	}
+	conditionUpdater := &tidbClusterConditionUpdater{}
+
+	// no condition if all keys are known
+	tc.Spec.TiKV.Config.Set("raftstore.raft-log-gc-threshold", 50)
+	conditionUpdater.Update(tc)
+	if cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.ConfigUnknownKeys); cond != nil {
+		t.Errorf("unexpected condition: %v", cond)
+	}
+
+	// typo of raftstore.raft-log-gc-threshold
+	tc.Spec.TiKV.Config.Set("raftstore.raft-log-gc-theshold", 50)
+	conditionUpdater.Update(tc)
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.ConfigUnknownKeys)
+	if diff := cmp.Diff(v1.ConditionTrue, cond.Status); diff != "" {
+		t.Errorf("unexpected status (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff(utiltidbcluster.UnknownConfigKeysFound, cond.Reason); diff != "" {
+		t.Errorf("unexpected reason (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff("unknown config keys are ignored, tikv: raftstore.raft-log-gc-theshold", cond.Message); diff != "" {
+		t.Errorf("unexpected message (-want, +got): %s", diff)
+	}
+
+	// the condition is cleared after the typo is fixed
+	tc.Spec.TiKV.Config.Del("raftstore.raft-log-gc-theshold")
+	conditionUpdater.Update(tc)
+	cond = utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.ConfigUnknownKeys)
+	if diff := cmp.Diff(v1.ConditionFalse, cond.Status); diff != "" {
+		t.Errorf("unexpected status (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff(utiltidbcluster.UnknownConfigKeysRemoved, cond.Reason); diff != "" {
+		t.Errorf("unexpected reason (-want, +got): %s", diff)
+	}
+}
+
+func TestTidbClusterConditionUpdater_Paused(t *testing.T) {
+	tc := &v1alpha1.TidbCluster{
+		Spec: v1alpha1.TidbClusterSpec{
+			PD:   &v1alpha1.PDSpec{},
+			TiKV: &v1alpha1.TiKVSpec{},
+		},
+	}
+	conditionUpdater := &tidbClusterConditionUpdater{}
+
+	// no condition if nothing is paused
+	conditionUpdater.Update(tc)
+	if cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterPaused); cond != nil {
+		t.Errorf("unexpected condition: %v", cond)
+	}
+
+	// tikv is paused
+	tc.Spec.TiKV.Paused = true
+	conditionUpdater.Update(tc)
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterPaused)
+	if diff := cmp.Diff(v1.ConditionTrue, cond.Status); diff != "" {
+		t.Errorf("unexpected status (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff(utiltidbcluster.ReconcilePaused, cond.Reason); diff != "" {
+		t.Errorf("unexpected reason (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff("the reconciliation of tikv is paused", cond.Message); diff != "" {
+		t.Errorf("unexpected message (-want, +got): %s", diff)
+	}
+
+	// the whole cluster is paused
+	tc.Spec.Paused = true
+	conditionUpdater.Update(tc)
+	cond = utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterPaused)
+	if diff := cmp.Diff("the reconciliation of the tidb cluster is paused", cond.Message); diff != "" {
+		t.Errorf("unexpected message (-want, +got): %s", diff)
+	}
+
+	// the condition is cleared after the reconciliation is resumed
+	tc.Spec.Paused = false
+	tc.Spec.TiKV.Paused = false
+	conditionUpdater.Update(tc)
+	cond = utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterPaused)
+	if diff := cmp.Diff(v1.ConditionFalse, cond.Status); diff != "" {
+		t.Errorf("unexpected status (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff(utiltidbcluster.ReconcileResumed, cond.Reason); diff != "" {
+		t.Errorf("unexpected reason (-want, +got): %s", diff)
+	}
+}
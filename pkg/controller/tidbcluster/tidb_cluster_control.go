@@ -14,6 +14,9 @@
 package tidbcluster
 
 import (
+	"time"
+
+	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/defaulting"
 	v1alpha1validation "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
@@ -21,6 +24,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/manager"
 	"github.com/pingcap/tidb-operator/pkg/manager/member"
 	"github.com/pingcap/tidb-operator/pkg/metrics"
+	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	errorutils "k8s.io/apimachinery/pkg/util/errors"
@@ -28,6 +32,9 @@ import (
 	"k8s.io/klog"
 )
 
+// controllerName is the value of the controller label of the reconciliation metrics
+const controllerName = "tidbcluster"
+
 // ControlInterface implements the control logic for updating TidbClusters and their children StatefulSets.
 // It is implemented as an interface to allow for extensions that provide different semantics.
 // Currently, there is only one implementation.
@@ -45,6 +52,7 @@ func NewDefaultTidbClusterControl(
 	tidbMemberManager manager.Manager,
 	reclaimPolicyManager manager.Manager,
 	metaManager manager.Manager,
+	ownerRefManager manager.Manager,
 	orphanPodsCleaner member.OrphanPodsCleaner,
 	pvcCleaner member.PVCCleanerInterface,
 	pvcResizer member.PVCResizerInterface,
@@ -53,6 +61,8 @@ func NewDefaultTidbClusterControl(
 	ticdcMemberManager manager.Manager,
 	discoveryManager member.TidbDiscoveryManager,
 	tidbClusterStatusManager manager.Manager,
+	failoverSummarizer member.FailoverSummarizer,
+	finalizer member.TidbClusterFinalizer,
 	conditionUpdater TidbClusterConditionUpdater,
 	recorder record.EventRecorder) ControlInterface {
 	return &defaultTidbClusterControl{
@@ -62,6 +72,7 @@ func NewDefaultTidbClusterControl(
 		tidbMemberManager:        tidbMemberManager,
 		reclaimPolicyManager:     reclaimPolicyManager,
 		metaManager:              metaManager,
+		ownerRefManager:          ownerRefManager,
 		orphanPodsCleaner:        orphanPodsCleaner,
 		pvcCleaner:               pvcCleaner,
 		pvcResizer:               pvcResizer,
@@ -70,6 +81,8 @@ func NewDefaultTidbClusterControl(
 		ticdcMemberManager:       ticdcMemberManager,
 		discoveryManager:         discoveryManager,
 		tidbClusterStatusManager: tidbClusterStatusManager,
+		failoverSummarizer:       failoverSummarizer,
+		finalizer:                finalizer,
 		conditionUpdater:         conditionUpdater,
 		recorder:                 recorder,
 	}
@@ -82,6 +95,7 @@ type defaultTidbClusterControl struct {
 	tidbMemberManager        manager.Manager
 	reclaimPolicyManager     manager.Manager
 	metaManager              manager.Manager
+	ownerRefManager          manager.Manager
 	orphanPodsCleaner        member.OrphanPodsCleaner
 	pvcCleaner               member.PVCCleanerInterface
 	pvcResizer               member.PVCResizerInterface
@@ -90,6 +104,8 @@ type defaultTidbClusterControl struct {
 	ticdcMemberManager       manager.Manager
 	discoveryManager         member.TidbDiscoveryManager
 	tidbClusterStatusManager manager.Manager
+	failoverSummarizer       member.FailoverSummarizer
+	finalizer                member.TidbClusterFinalizer
 	conditionUpdater         TidbClusterConditionUpdater
 	recorder                 record.EventRecorder
 }
@@ -97,6 +113,13 @@ type defaultTidbClusterControl struct {
 // UpdateStatefulSet executes the core logic loop for a tidbcluster.
 func (c *defaultTidbClusterControl) UpdateTidbCluster(tc *v1alpha1.TidbCluster) error {
 	c.defaulting(tc)
+	// the tidb cluster being deleted is only cleaned up, even if its spec is invalid
+	if deleting, err := c.finalizer.Finalize(tc); deleting || err != nil {
+		if deleting {
+			deleteUnhealthyMetrics(tc.GetNamespace(), tc.GetName())
+		}
+		return err
+	}
 	if !c.validate(tc) {
 		return nil // fatal error, no need to retry on invalid object
 	}
@@ -108,6 +131,11 @@ func (c *defaultTidbClusterControl) UpdateTidbCluster(tc *v1alpha1.TidbCluster)
 		errs = append(errs, err)
 	}
 
+	// the failovers are summarized even if the sync fails, as they may be recorded
+	// before the failure
+	c.failoverSummarizer.Summarize(tc)
+	c.recordUnhealthyMetrics(tc)
+
 	if err := c.conditionUpdater.Update(tc); err != nil {
 		errs = append(errs, err)
 	}
@@ -139,6 +167,12 @@ func (c *defaultTidbClusterControl) defaulting(tc *v1alpha1.TidbCluster) {
 
 func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster) error {
 	c.recordMetrics(tc)
+	// healing the owner references of the objects left by a deleted TidbCluster with the same name,
+	// this must be done before any other sync in case the objects are deleted by the garbage collector
+	if err := c.ownerRefManager.Sync(tc); err != nil {
+		return err
+	}
+
 	// syncing all PVs managed by operator's reclaim policy to Retain
 	if err := c.reclaimPolicyManager.Sync(tc); err != nil {
 		return err
@@ -172,7 +206,7 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	//   - upgrade the pd cluster
 	//   - scale out/in the pd cluster
 	//   - failover the pd cluster
-	if err := c.pdMemberManager.Sync(tc); err != nil {
+	if err := c.syncMember(tc, v1alpha1.PDMemberType, c.pdMemberManager); err != nil {
 		return err
 	}
 
@@ -185,7 +219,7 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	//   - upgrade the tiflash cluster
 	//   - scale out/in the tiflash cluster
 	//   - failover the tiflash cluster
-	if err := c.tiflashMemberManager.Sync(tc); err != nil {
+	if err := c.syncMember(tc, v1alpha1.TiFlashMemberType, c.tiflashMemberManager); err != nil {
 		return err
 	}
 
@@ -198,12 +232,12 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	//   - upgrade the tikv cluster
 	//   - scale out/in the tikv cluster
 	//   - failover the tikv cluster
-	if err := c.tikvMemberManager.Sync(tc); err != nil {
+	if err := c.syncMember(tc, v1alpha1.TiKVMemberType, c.tikvMemberManager); err != nil {
 		return err
 	}
 
 	// syncing the pump cluster
-	if err := c.pumpMemberManager.Sync(tc); err != nil {
+	if err := c.syncMember(tc, v1alpha1.PumpMemberType, c.pumpMemberManager); err != nil {
 		return err
 	}
 
@@ -215,14 +249,14 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	//   - upgrade the tidb cluster
 	//   - scale out/in the tidb cluster
 	//   - failover the tidb cluster
-	if err := c.tidbMemberManager.Sync(tc); err != nil {
+	if err := c.syncMember(tc, v1alpha1.TiDBMemberType, c.tidbMemberManager); err != nil {
 		return err
 	}
 
 	//   - waiting for the pd cluster available(pd cluster is in quorum)
 	//   - create or update ticdc deployment
 	//   - sync ticdc cluster status from pd to TidbCluster object
-	if err := c.ticdcMemberManager.Sync(tc); err != nil {
+	if err := c.syncMember(tc, v1alpha1.TiCDCMemberType, c.ticdcMemberManager); err != nil {
 		return err
 	}
 
@@ -255,6 +289,27 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	return c.tidbClusterStatusManager.Sync(tc)
 }
 
+// syncMember syncs the component by its member manager, and records the result and the duration of the sync
+func (c *defaultTidbClusterControl) syncMember(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, m manager.Manager) error {
+	start := time.Now()
+	err := m.Sync(tc)
+	metrics.ObserveReconcile(controllerName, memberType.String(), reconcileResult(err), start)
+	return err
+}
+
+// reconcileResult returns the result of a reconciliation for the metrics, a requeue error means the
+// reconciliation is waiting for something, e.g. the upgrade of a pod, rather than failing
+func reconcileResult(err error) string {
+	switch {
+	case err == nil:
+		return metrics.ReconcileSuccess
+	case perrors.Find(err, controller.IsRequeueError) != nil:
+		return metrics.ReconcileRequeue
+	default:
+		return metrics.ReconcileError
+	}
+}
+
 func (c *defaultTidbClusterControl) recordMetrics(tc *v1alpha1.TidbCluster) {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
@@ -278,6 +333,58 @@ func (c *defaultTidbClusterControl) recordMetrics(tc *v1alpha1.TidbCluster) {
 	}
 }
 
+// unhealthyMetricsComponents are the components whose unhealthiness is recorded in the metrics
+var unhealthyMetricsComponents = []v1alpha1.MemberType{
+	v1alpha1.PDMemberType,
+	v1alpha1.TiKVMemberType,
+	v1alpha1.TiDBMemberType,
+	v1alpha1.TiFlashMemberType,
+	v1alpha1.TiCDCMemberType,
+	v1alpha1.PumpMemberType,
+}
+
+// recordUnhealthyMetrics records whether each component is unhealthy according to the status synced,
+// so that the alerts can fire on a component keeping unhealthy. The series of the components
+// removed from the spec are deleted.
+func (c *defaultTidbClusterControl) recordUnhealthyMetrics(tc *v1alpha1.TidbCluster) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	set := func(memberType v1alpha1.MemberType, enabled bool, unhealthy func() bool) {
+		if !enabled {
+			metrics.ClusterUnhealthy.DeleteLabelValues(ns, tcName, memberType.String())
+			return
+		}
+		value := 0.0
+		if unhealthy() {
+			value = 1
+		}
+		metrics.ClusterUnhealthy.WithLabelValues(ns, tcName, memberType.String()).Set(value)
+	}
+	set(v1alpha1.PDMemberType, tc.Spec.PD != nil, func() bool { return !tc.PDAllMembersReady() })
+	set(v1alpha1.TiKVMemberType, tc.Spec.TiKV != nil, func() bool { return !tc.TiKVAllStoresReady() })
+	set(v1alpha1.TiDBMemberType, tc.Spec.TiDB != nil, func() bool { return !tc.TiDBAllMembersReady() })
+	set(v1alpha1.TiFlashMemberType, tc.Spec.TiFlash != nil, func() bool { return !tc.TiFlashAllStoresReady() })
+	set(v1alpha1.TiCDCMemberType, tc.Spec.TiCDC != nil, func() bool {
+		return !statefulSetAllReady(tc.Status.TiCDC.StatefulSet, tc.Spec.TiCDC.Replicas)
+	})
+	set(v1alpha1.PumpMemberType, tc.Spec.Pump != nil, func() bool {
+		return !statefulSetAllReady(tc.Status.Pump.StatefulSet, tc.Spec.Pump.Replicas)
+	})
+}
+
+// deleteUnhealthyMetrics deletes the series of the components of the deleted TidbCluster
+func deleteUnhealthyMetrics(ns, tcName string) {
+	for _, memberType := range unhealthyMetricsComponents {
+		metrics.ClusterUnhealthy.DeleteLabelValues(ns, tcName, memberType.String())
+	}
+}
+
+// statefulSetAllReady returns whether the desired replicas of the StatefulSet are all ready, it's used
+// for the components without the health of their members in the status
+func statefulSetAllReady(status *apps.StatefulSetStatus, replicas int32) bool {
+	return status != nil && status.ReadyReplicas >= replicas
+}
+
 var _ ControlInterface = &defaultTidbClusterControl{}
 
 type FakeTidbClusterControlInterface struct {
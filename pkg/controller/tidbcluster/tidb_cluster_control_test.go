@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -25,6 +26,8 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	mm "github.com/pingcap/tidb-operator/pkg/manager/member"
 	"github.com/pingcap/tidb-operator/pkg/manager/meta"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
@@ -294,6 +297,78 @@ func TestTidbClusterStatusEquality(t *testing.T) {
 	g.Expect(apiequality.Semantic.DeepEqual(&tcStatus, tcStatusCopy)).To(Equal(false))
 }
 
+func TestTidbClusterControlMetrics(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// the metrics are global, drop the series left by the other tests
+	metrics.ReconcileTotal.Reset()
+	metrics.ReconcileDuration.Reset()
+	metrics.ClusterUnhealthy.Reset()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.ReconcileTotal, metrics.ReconcileDuration, metrics.ClusterUnhealthy)
+
+	tc := newTidbClusterForTidbClusterControl()
+	tc.Name = "test-metrics"
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+		"test-metrics-pd-0": {Name: "test-metrics-pd-0", Health: true},
+		"test-metrics-pd-1": {Name: "test-metrics-pd-1", Health: true},
+		"test-metrics-pd-2": {Name: "test-metrics-pd-2", Health: true},
+	}
+	control, _, _, _, tikvMemberManager, _, _, _, _ := newFakeTidbClusterControl()
+	tikvMemberManager.SetSyncError(controller.RequeueErrorf("tikv is upgrading"))
+	tcc := &Controller{control: control}
+	g.Expect(tcc.syncTidbCluster(tc)).To(HaveOccurred())
+
+	gather := func() map[string]float64 {
+		families, err := registry.Gather()
+		g.Expect(err).NotTo(HaveOccurred())
+		series := map[string]float64{}
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				var labels []string
+				for _, label := range metric.GetLabel() {
+					labels = append(labels, fmt.Sprintf("%s=%s", label.GetName(), label.GetValue()))
+				}
+				series[fmt.Sprintf("%s{%s}", family.GetName(), strings.Join(labels, ","))] = metric.GetGauge().GetValue()
+			}
+		}
+		return series
+	}
+	series := gather()
+
+	for _, s := range []string{
+		"tidb_operator_reconcile_total{component=pd,controller=tidbcluster,result=success}",
+		"tidb_operator_reconcile_total{component=tiflash,controller=tidbcluster,result=success}",
+		"tidb_operator_reconcile_total{component=tikv,controller=tidbcluster,result=requeue}",
+		"tidb_operator_reconcile_total{component=cluster,controller=tidbcluster,result=requeue}",
+		"tidb_operator_reconcile_duration_seconds{component=pd,controller=tidbcluster}",
+		"tidb_operator_reconcile_duration_seconds{component=cluster,controller=tidbcluster}",
+	} {
+		g.Expect(series).To(HaveKey(s))
+	}
+	// tidb isn't synced after the sync of tikv is requeued
+	g.Expect(series).NotTo(HaveKey("tidb_operator_reconcile_total{component=tidb,controller=tidbcluster,result=success}"))
+
+	g.Expect(series).To(HaveKeyWithValue("tidb_operator_cluster_unhealthy{component=pd,name=test-metrics,namespace=default}", 0.0))
+	g.Expect(series).To(HaveKeyWithValue("tidb_operator_cluster_unhealthy{component=tikv,name=test-metrics,namespace=default}", 1.0))
+	g.Expect(series).To(HaveKeyWithValue("tidb_operator_cluster_unhealthy{component=tidb,name=test-metrics,namespace=default}", 1.0))
+	g.Expect(series).NotTo(HaveKey("tidb_operator_cluster_unhealthy{component=tiflash,name=test-metrics,namespace=default}"))
+
+	// the series of the component removed from the spec is deleted
+	tc.Spec.TiDB = nil
+	g.Expect(tcc.syncTidbCluster(tc)).To(HaveOccurred())
+	series = gather()
+	g.Expect(series).To(HaveKey("tidb_operator_cluster_unhealthy{component=tikv,name=test-metrics,namespace=default}"))
+	g.Expect(series).NotTo(HaveKey("tidb_operator_cluster_unhealthy{component=tidb,name=test-metrics,namespace=default}"))
+
+	// the series of the deleted cluster are deleted
+	tc.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	g.Expect(tcc.syncTidbCluster(tc)).NotTo(HaveOccurred())
+	for s := range gather() {
+		g.Expect(s).NotTo(HavePrefix("tidb_operator_cluster_unhealthy"))
+	}
+}
+
 func newFakeTidbClusterControl() (
 	ControlInterface,
 	*meta.FakeReclaimPolicyManager,
@@ -314,6 +389,7 @@ func newFakeTidbClusterControl() (
 	tidbMemberManager := mm.NewFakeTiDBMemberManager()
 	reclaimPolicyManager := meta.NewFakeReclaimPolicyManager()
 	metaManager := meta.NewFakeMetaManager()
+	ownerRefManager := meta.NewFakeOwnerRefManager()
 	orphanPodCleaner := mm.NewFakeOrphanPodsCleaner()
 	pvcCleaner := mm.NewFakePVCCleaner()
 	pumpMemberManager := mm.NewFakePumpMemberManager()
@@ -329,6 +405,7 @@ func newFakeTidbClusterControl() (
 		tidbMemberManager,
 		reclaimPolicyManager,
 		metaManager,
+		ownerRefManager,
 		orphanPodCleaner,
 		pvcCleaner,
 		pvcResizer,
@@ -337,6 +414,8 @@ func newFakeTidbClusterControl() (
 		ticdcMemberManager,
 		discoveryManager,
 		statusManager,
+		mm.NewFakeFailoverSummarizer(),
+		mm.NewFakeTidbClusterFinalizer(),
 		&tidbClusterConditionUpdater{},
 		recorder,
 	)
@@ -19,16 +19,18 @@ import (
 
 	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	mm "github.com/pingcap/tidb-operator/pkg/manager/member"
 	"github.com/pingcap/tidb-operator/pkg/manager/meta"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
 	apps "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
 )
 
@@ -38,54 +40,68 @@ type Controller struct {
 	// control returns an interface capable of syncing a tidb cluster.
 	// Abstracted out for testing.
 	control ControlInterface
-	// tidbclusters that need to be synced.
-	queue workqueue.RateLimitingInterface
+	// tidbclusters that need to be synced, the ones with failures are synced first.
+	queue controller.PriorityRateLimitingInterface
 }
 
 // NewController creates a tidbcluster controller.
 func NewController(deps *controller.Dependencies) *Controller {
+	// the failovers of all the components in a namespace share the limit
+	failoverLimiter := mm.NewFailoverLimiter(deps)
 	c := &Controller{
 		deps: deps,
 		control: NewDefaultTidbClusterControl(
 			deps.TiDBClusterControl,
-			mm.NewPDMemberManager(deps, mm.NewPDScaler(deps), mm.NewPDUpgrader(deps), mm.NewPDFailover(deps)),
-			mm.NewTiKVMemberManager(deps, mm.NewTiKVFailover(deps), mm.NewTiKVScaler(deps), mm.NewTiKVUpgrader(deps)),
-			mm.NewTiDBMemberManager(deps, mm.NewTiDBScaler(deps), mm.NewTiDBUpgrader(deps), mm.NewTiDBFailover(deps)),
+			mm.NewPDMemberManager(deps, mm.NewPDScaler(deps), mm.NewPDUpgrader(deps), mm.NewLimitedFailover(mm.NewPDFailover(deps), v1alpha1.PDMemberType, failoverLimiter)),
+			mm.NewTiKVMemberManager(deps, mm.NewLimitedFailover(mm.NewTiKVFailover(deps), v1alpha1.TiKVMemberType, failoverLimiter), mm.NewTiKVScaler(deps), mm.NewTiKVUpgrader(deps)),
+			mm.NewTiDBMemberManager(deps, mm.NewTiDBScaler(deps), mm.NewTiDBUpgrader(deps), mm.NewLimitedFailover(mm.NewTiDBFailover(deps), v1alpha1.TiDBMemberType, failoverLimiter)),
 			meta.NewReclaimPolicyManager(deps),
 			meta.NewMetaManager(deps),
+			meta.NewOwnerRefManager(deps),
 			mm.NewOrphanPodsCleaner(deps),
 			mm.NewRealPVCCleaner(deps),
 			mm.NewPVCResizer(deps),
 			mm.NewPumpMemberManager(deps, mm.NewPumpScaler(deps)),
-			mm.NewTiFlashMemberManager(deps, mm.NewTiFlashFailover(deps), mm.NewTiFlashScaler(deps), mm.NewTiFlashUpgrader(deps)),
+			mm.NewTiFlashMemberManager(deps, mm.NewLimitedFailover(mm.NewTiFlashFailover(deps), v1alpha1.TiFlashMemberType, failoverLimiter), mm.NewTiFlashScaler(deps), mm.NewTiFlashUpgrader(deps)),
 			mm.NewTiCDCMemberManager(deps, mm.NewTiCDCScaler(deps), mm.NewTiCDCUpgrader(deps)),
 			mm.NewTidbDiscoveryManager(deps),
 			mm.NewTidbClusterStatusManager(deps),
+			mm.NewFailoverSummarizer(deps),
+			mm.NewTidbClusterFinalizer(deps),
 			&tidbClusterConditionUpdater{},
 			deps.Recorder,
 		),
-		queue: workqueue.NewNamedRateLimitingQueue(
+		queue: controller.NewPriorityRateLimitingQueue(
 			controller.NewControllerRateLimiter(1*time.Second, 100*time.Second),
 			"tidbcluster",
+			controller.DefaultHighPriorityBurst,
 		),
 	}
 
-	tidbClusterInformer := deps.InformerFactory.Pingcap().V1alpha1().TidbClusters()
-	statefulsetInformer := deps.KubeInformerFactory.Apps().V1().StatefulSets()
-	tidbClusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: c.enqueueTidbCluster,
-		UpdateFunc: func(old, cur interface{}) {
-			c.enqueueTidbCluster(cur)
-		},
-		DeleteFunc: c.enqueueTidbCluster,
+	tidbClusterInformers := deps.Informers(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().TidbClusters().Informer()
 	})
-	statefulsetInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: c.addStatefulSet,
-		UpdateFunc: func(old, cur interface{}) {
-			c.updateStatefulSet(old, cur)
-		},
-		DeleteFunc: c.deleteStatefulSet,
+	statefulsetInformers := deps.KubeInformers(func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Apps().V1().StatefulSets().Informer()
 	})
+	for _, informer := range tidbClusterInformers {
+		informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: c.enqueueTidbCluster,
+			UpdateFunc: func(old, cur interface{}) {
+				c.enqueueTidbCluster(cur)
+			},
+			DeleteFunc: c.enqueueTidbCluster,
+		})
+	}
+	for _, informer := range statefulsetInformers {
+		informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: c.addStatefulSet,
+			UpdateFunc: func(old, cur interface{}) {
+				c.updateStatefulSet(old, cur)
+			},
+			DeleteFunc: c.deleteStatefulSet,
+		})
+	}
 
 	return c
 }
@@ -146,17 +162,24 @@ func (c *Controller) sync(key string) error {
 	tc, err := c.deps.TiDBClusterLister.TidbClusters(ns).Get(name)
 	if errors.IsNotFound(err) {
 		klog.Infof("TidbCluster has been deleted %v", key)
+		deleteUnhealthyMetrics(ns, name)
+		mm.ForgetRenderedConfigMaps(c.deps.ConfigMapControl, ns, name)
 		return nil
 	}
 	if err != nil {
 		return err
 	}
 
-	return c.syncTidbCluster(tc.DeepCopy())
+	tc = tc.DeepCopy()
+	defer controller.StartEventBudget(c.deps.Recorder, tc)()
+	return c.syncTidbCluster(tc)
 }
 
 func (c *Controller) syncTidbCluster(tc *v1alpha1.TidbCluster) error {
-	return c.control.UpdateTidbCluster(tc)
+	start := time.Now()
+	err := c.control.UpdateTidbCluster(tc)
+	metrics.ObserveReconcile(controllerName, "cluster", reconcileResult(err), start)
+	return err
 }
 
 // enqueueTidbCluster enqueues the given tidbcluster in the work queue.
@@ -166,9 +189,40 @@ func (c *Controller) enqueueTidbCluster(obj interface{}) {
 		utilruntime.HandleError(fmt.Errorf("Cound't get key for object %+v: %v", obj, err))
 		return
 	}
+	if tc, ok := obj.(*v1alpha1.TidbCluster); ok && hasUnhealthyMember(tc) {
+		c.queue.AddHighPriority(key)
+		return
+	}
 	c.queue.Add(key)
 }
 
+// hasUnhealthyMember returns whether any member of the tidbcluster is unhealthy or failed over according
+// to its status, such tidbclusters are synced before the healthy ones.
+func hasUnhealthyMember(tc *v1alpha1.TidbCluster) bool {
+	for _, member := range tc.Status.PD.Members {
+		if !member.Health {
+			return true
+		}
+	}
+	for _, member := range tc.Status.TiDB.Members {
+		if !member.Health {
+			return true
+		}
+	}
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.State != v1alpha1.TiKVStateUp {
+			return true
+		}
+	}
+	for _, store := range tc.Status.TiFlash.Stores {
+		if store.State != v1alpha1.TiKVStateUp {
+			return true
+		}
+	}
+	return len(tc.Status.PD.FailureMembers) > 0 || len(tc.Status.TiDB.FailureMembers) > 0 ||
+		len(tc.Status.TiKV.FailureStores) > 0 || len(tc.Status.TiFlash.FailureStores) > 0
+}
+
 // addStatefulSet adds the tidbcluster for the statefulset to the sync queue
 func (c *Controller) addStatefulSet(obj interface{}) {
 	set := obj.(*apps.StatefulSet)
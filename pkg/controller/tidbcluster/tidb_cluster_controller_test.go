@@ -47,6 +47,31 @@ func TestTidbClusterControllerEnqueueTidbClusterFailed(t *testing.T) {
 	g.Expect(tcc.queue.Len()).To(Equal(0))
 }
 
+func TestTidbClusterControllerEnqueueUnhealthyTidbClusterFirst(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tcc := NewController(controller.NewFakeDependencies())
+	tcc.control = NewFakeTidbClusterControlInterface()
+
+	healthy := newTidbCluster()
+	healthy.Name = "healthy"
+	unhealthy := newTidbCluster()
+	unhealthy.Name = "unhealthy"
+	unhealthy.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"1": {ID: "1", State: v1alpha1.TiKVStateUp},
+		"2": {ID: "2", State: v1alpha1.TiKVStateDown},
+	}
+	g.Expect(hasUnhealthyMember(healthy)).To(BeFalse())
+	g.Expect(hasUnhealthyMember(unhealthy)).To(BeTrue())
+
+	tcc.enqueueTidbCluster(healthy)
+	tcc.enqueueTidbCluster(unhealthy)
+	g.Expect(tcc.queue.Len()).To(Equal(2))
+	key, _ := tcc.queue.Get()
+	g.Expect(key).To(Equal(unhealthy.Namespace + "/unhealthy"))
+	key, _ = tcc.queue.Get()
+	g.Expect(key).To(Equal(healthy.Namespace + "/healthy"))
+}
+
 func TestTidbClusterControllerAddStatefulSet(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
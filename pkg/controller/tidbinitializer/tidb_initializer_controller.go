@@ -21,12 +21,14 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
 
 	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/manager/member"
 )
@@ -49,14 +51,20 @@ func NewController(deps *controller.Dependencies) *Controller {
 		),
 	}
 
-	tidbInitializerInformer := deps.InformerFactory.Pingcap().V1alpha1().TidbInitializers()
-	jobInformer := deps.KubeInformerFactory.Batch().V1().Jobs()
-	controller.WatchForObject(tidbInitializerInformer.Informer(), c.queue)
+	for _, informer := range deps.Informers(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().TidbInitializers().Informer()
+	}) {
+		controller.WatchForObject(informer, c.queue)
+	}
 	m := make(map[string]string)
 	m[label.ComponentLabelKey] = label.InitJobLabelVal
-	controller.WatchForController(jobInformer.Informer(), c.queue, func(ns, name string) (runtime.Object, error) {
-		return c.deps.TiDBInitializerLister.TidbInitializers(ns).Get(name)
-	}, m)
+	for _, informer := range deps.KubeInformers(func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Batch().V1().Jobs().Informer()
+	}) {
+		controller.WatchForController(informer, c.queue, func(ns, name string) (runtime.Object, error) {
+			return c.deps.TiDBInitializerLister.TidbInitializers(ns).Get(name)
+		}, m)
+	}
 
 	return c
 }
@@ -18,12 +18,15 @@ import (
 	"time"
 
 	perrors "github.com/pingcap/errors"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/monitor/monitor"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
@@ -48,16 +51,64 @@ func NewController(deps *controller.Dependencies) *Controller {
 		),
 	}
 
-	tidbMonitorInformer := deps.InformerFactory.Pingcap().V1alpha1().TidbMonitors()
-	statefulsetInformer := deps.KubeInformerFactory.Apps().V1().StatefulSets()
-	controller.WatchForObject(tidbMonitorInformer.Informer(), c.queue)
-	controller.WatchForController(statefulsetInformer.Informer(), c.queue, func(ns, name string) (runtime.Object, error) {
-		return c.deps.TiDBMonitorLister.TidbMonitors(ns).Get(name)
-	}, nil)
+	for _, informer := range deps.Informers(func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Pingcap().V1alpha1().TidbMonitors().Informer()
+	}) {
+		controller.WatchForObject(informer, c.queue)
+	}
+	for _, informer := range deps.KubeInformers(func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Apps().V1().StatefulSets().Informer()
+	}) {
+		controller.WatchForController(informer, c.queue, func(ns, name string) (runtime.Object, error) {
+			return c.deps.TiDBMonitorLister.TidbMonitors(ns).Get(name)
+		}, nil)
+	}
+	// rotating the grafana credentials rolls out the grafana
+	for _, informer := range deps.KubeInformers(func(f kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().Secrets().Informer()
+	}) {
+		informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: c.enqueueMonitorsForSecret,
+			UpdateFunc: func(_, cur interface{}) {
+				c.enqueueMonitorsForSecret(cur)
+			},
+			DeleteFunc: c.enqueueMonitorsForSecret,
+		})
+	}
 
 	return c
 }
 
+// enqueueMonitorsForSecret enqueues the TidbMonitors whose grafana credentials are in the secret
+func (c *Controller) enqueueMonitorsForSecret(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("couldn't get key for object %+v: %v", obj, err))
+		return
+	}
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	tms, err := c.deps.TiDBMonitorLister.TidbMonitors(ns).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list TidbMonitors in namespace %s: %v", ns, err))
+		return
+	}
+	for _, tm := range tms {
+		if !monitor.IsGrafanaCredentialsSecret(tm, ns, name) {
+			continue
+		}
+		tmKey, err := cache.MetaNamespaceKeyFunc(tm)
+		if err != nil {
+			utilruntime.HandleError(err)
+			continue
+		}
+		c.queue.Add(tmKey)
+	}
+}
+
 func (c *Controller) Run(workers int, stopCh <-chan struct{}) {
 	defer utilruntime.HandleCrash()
 	defer c.queue.ShutDown()
@@ -15,15 +15,19 @@ package discovery
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"sync"
 
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
 	"github.com/pingcap/tidb-operator/pkg/dmapi"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog"
 )
@@ -31,6 +35,10 @@ import (
 // TiDBDiscovery helps new PD and dm-master member to discover all other members in cluster bootstrap phase.
 type TiDBDiscovery interface {
 	Discover(string) (string, error)
+	// DiscoverV2 discovers the start args of a PD member like Discover, and tells whether the data dir
+	// of the member should be purged as its previous member ID is no longer in the PD cluster,
+	// memberID 0 means the previous member ID is unknown
+	DiscoverV2(advertisePeerUrl string, memberID uint64) (*DiscoverResult, error)
 	DiscoverDM(string) (string, error)
 	VerifyPDEndpoint(string) (string, error)
 }
@@ -44,6 +52,15 @@ type tidbDiscovery struct {
 	masterControl dmapi.MasterControlInterface
 }
 
+// DiscoverResult is the result of the discovery of a PD member
+type DiscoverResult struct {
+	// Args are the args to start the PD member, e.g. --join or --initial-cluster
+	Args string `json:"args"`
+	// Purge indicates the previous member of the PD member is no longer in the PD cluster,
+	// e.g. it's removed by scaling in, the data dir should be wiped before joining with Args
+	Purge bool `json:"purge,omitempty"`
+}
+
 type clusterInfo struct {
 	resourceVersion string
 	peers           map[string]struct{}
@@ -68,18 +85,48 @@ func NewTiDBDiscovery(pdControl pdapi.PDControlInterface, masterControl dmapi.Ma
 }
 
 func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
+	args, _, err := d.discover(advertisePeerUrl)
+	return args, err
+}
+
+func (d *tidbDiscovery) DiscoverV2(advertisePeerUrl string, memberID uint64) (*DiscoverResult, error) {
+	args, membersInfo, err := d.discover(advertisePeerUrl)
+	if err != nil {
+		return nil, err
+	}
+	result := &DiscoverResult{Args: args}
+	// the members are nil when the PD cluster is being initialized
+	if memberID == 0 || membersInfo == nil {
+		return result, nil
+	}
+	result.Purge = true
+	for _, member := range membersInfo.Members {
+		if member.GetMemberId() == memberID {
+			result.Purge = false
+			break
+		}
+	}
+	if result.Purge {
+		klog.Infof("previous member %d of %s is no longer in the PD cluster, it should be purged before joining", memberID, advertisePeerUrl)
+	}
+	return result, nil
+}
+
+// discover returns the start args of a PD member, and the members of the PD cluster
+// if the PD member joins an existing PD cluster.
+func (d *tidbDiscovery) discover(advertisePeerUrl string) (string, *pdapi.MembersInfo, error) {
 	d.lock.Lock()
 	defer d.lock.Unlock()
 
 	if advertisePeerUrl == "" {
-		return "", fmt.Errorf("advertisePeerUrl is empty")
+		return "", nil, fmt.Errorf("advertisePeerUrl is empty")
 	}
 	klog.Infof("advertisePeerUrl is: %s", advertisePeerUrl)
 	strArr := strings.Split(advertisePeerUrl, ":")
 	hostArr := strings.Split(strArr[0], ".")
 
 	if len(hostArr) < 4 || hostArr[3] != "svc" {
-		return "", fmt.Errorf("advertisePeerUrl format is wrong: %s", advertisePeerUrl)
+		return "", nil, fmt.Errorf("advertisePeerUrl format is wrong: %s", advertisePeerUrl)
 	}
 
 	podName, peerServiceName, ns := hostArr[0], hostArr[1], hostArr[2]
@@ -87,11 +134,11 @@ func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 	podNamespace := os.Getenv("MY_POD_NAMESPACE")
 
 	if ns != podNamespace {
-		return "", fmt.Errorf("the peer's namespace: %s is not equal to discovery namespace: %s", ns, podNamespace)
+		return "", nil, fmt.Errorf("the peer's namespace: %s is not equal to discovery namespace: %s", ns, podNamespace)
 	}
 	tc, err := d.cli.PingcapV1alpha1().TidbClusters(ns).Get(context.TODO(), tcName, metav1.GetOptions{})
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 	keyName := fmt.Sprintf("%s/%s", ns, tcName)
 
@@ -105,20 +152,51 @@ func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 	currentCluster = d.clusters[keyName]
 	currentCluster.peers[podName] = struct{}{}
 
+	initialCluster := func() string {
+		// Initialize the PD cluster with the FQDN format service record if tc.Spec.ClusterDomain is set.
+		if len(tc.Spec.ClusterDomain) > 0 {
+			return fmt.Sprintf("--initial-cluster=%s=%s://%s", strArr[0], tc.Scheme(), advertisePeerUrl)
+		}
+		// Initialize the PD cluster in the normal format service record.
+		return fmt.Sprintf("--initial-cluster=%s=%s://%s", podName, tc.Scheme(), advertisePeerUrl)
+	}
+	// The pod bootstrapping the PD cluster is recorded in the TidbCluster, as the peers are lost when
+	// the discovery service restarts. The pod asking again, e.g. restarted before the PD cluster is
+	// initialized, is told to bootstrap again, and the other pods never bootstrap another PD cluster.
+	// Once the PD cluster is initialized, the pod asking again has lost its data, e.g. its PVC is
+	// replaced, it joins the PD cluster like the others instead of bootstrapping another one.
+	bootstrapPod := tc.Annotations[label.AnnPDBootstrappedKey]
+	if bootstrapPod == podName && tc.Spec.Cluster == nil && len(tc.Spec.PDAddresses) == 0 && !pdClusterInitialized(tc) {
+		delete(currentCluster.peers, podName)
+		return initialCluster(), nil, nil
+	}
+
 	// Should take failover replicas into consideration
-	if len(currentCluster.peers) == int(tc.PDStsDesiredReplicas()) && tc.Spec.Cluster == nil {
+	if bootstrapPod == "" && len(currentCluster.peers) == int(tc.PDStsDesiredReplicas()) && tc.Spec.Cluster == nil {
 		delete(currentCluster.peers, podName)
 		pdAddresses := tc.Spec.PDAddresses
 		// Join an existing PD cluster if tc.Spec.PDAddresses is set
 		if len(pdAddresses) != 0 {
-			return fmt.Sprintf("--join=%s", strings.Join(pdAddresses, ",")), nil
+			return fmt.Sprintf("--join=%s", strings.Join(pdAddresses, ",")), nil, nil
 		}
-		// Initialize the PD cluster with the FQDN format service record if tc.Spec.ClusterDomain is set.
-		if len(tc.Spec.ClusterDomain) > 0 {
-			return fmt.Sprintf("--initial-cluster=%s=%s://%s", strArr[0], tc.Scheme(), advertisePeerUrl), nil
+		// the patch carries the resource version, it fails with a conflict if the TidbCluster is changed
+		// since it's read, so the bootstrap decision is made only once, the pod retries on error
+		mergePatch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"resourceVersion": tc.ResourceVersion,
+				"annotations": map[string]interface{}{
+					label.AnnPDBootstrappedKey: podName,
+				},
+			},
+		})
+		if err != nil {
+			return "", nil, err
 		}
-		// Initialize the PD cluster in the normal format service record.
-		return fmt.Sprintf("--initial-cluster=%s=%s://%s", podName, tc.Scheme(), advertisePeerUrl), nil
+		if _, err := d.cli.PingcapV1alpha1().TidbClusters(ns).Patch(context.TODO(), tcName, types.MergePatchType, mergePatch, metav1.PatchOptions{}); err != nil {
+			return "", nil, fmt.Errorf("failed to record %s bootstrapping the PD cluster of %s, error: %v", podName, keyName, err)
+		}
+		klog.Infof("%s is recorded to bootstrap the PD cluster of %s", podName, keyName)
+		return initialCluster(), nil, nil
 	}
 
 	var pdClients []pdapi.PDClient
@@ -147,7 +225,7 @@ func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 		}
 	}
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	membersArr := make([]string, 0)
@@ -169,7 +247,13 @@ func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 		membersArr = append(membersArr, memberURL)
 	}
 	delete(currentCluster.peers, podName)
-	return fmt.Sprintf("--join=%s", strings.Join(membersArr, ",")), nil
+	return fmt.Sprintf("--join=%s", strings.Join(membersArr, ",")), membersInfo, nil
+}
+
+// pdClusterInitialized returns whether the PD cluster of the TidbCluster has been initialized. It's decided
+// from the status synced by the operator rather than PD, which may be unreachable after it's initialized.
+func pdClusterInitialized(tc *v1alpha1.TidbCluster) bool {
+	return tc.Status.ClusterID != "" || len(tc.Status.PD.Members) > 0
 }
 
 func (d *tidbDiscovery) DiscoverDM(advertisePeerUrl string) (string, error) {
@@ -22,6 +22,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
 	"github.com/pingcap/tidb-operator/pkg/controller"
@@ -533,6 +534,149 @@ func TestDiscoveryDiscovery(t *testing.T) {
 	}
 }
 
+func TestDiscoveryBootstrapAcrossRestart(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	os.Setenv("MY_POD_NAMESPACE", "default")
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	fakePDControl := pdapi.NewFakePDControl(kubeCli)
+	pdClient := pdapi.NewFakePDClient()
+	tc := newTC()
+	cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+	fakePDControl.SetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), pdClient)
+	initialized := false
+	members := []string{"demo-pd-2"}
+	pdClient.AddReaction(pdapi.GetMembersActionType, func(action *pdapi.Action) (interface{}, error) {
+		if !initialized {
+			return nil, fmt.Errorf("pd cluster is not initialized")
+		}
+		membersInfo := &pdapi.MembersInfo{}
+		for _, name := range members {
+			membersInfo.Members = append(membersInfo.Members, &pdpb.Member{
+				Name:     name,
+				PeerUrls: []string{fmt.Sprintf("http://%s.demo-pd-peer.default.svc:2380", name)},
+			})
+		}
+		return membersInfo, nil
+	})
+	url := func(podName string) string {
+		return fmt.Sprintf("%s.demo-pd-peer.default.svc:2380", podName)
+	}
+
+	td := NewTiDBDiscovery(fakePDControl, dmapi.NewFakeMasterControl(kubeCli), cli, kubeCli)
+	_, err := td.Discover(url("demo-pd-0"))
+	g.Expect(err).To(HaveOccurred())
+	_, err = td.Discover(url("demo-pd-1"))
+	g.Expect(err).To(HaveOccurred())
+	args, err := td.Discover(url("demo-pd-2"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal("--initial-cluster=demo-pd-2=http://demo-pd-2.demo-pd-peer.default.svc:2380"))
+	tc, err = cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Get(context.TODO(), tc.Name, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Annotations).To(HaveKeyWithValue(label.AnnPDBootstrappedKey, "demo-pd-2"))
+
+	// the discovery service restarts, and demo-pd-2 restarts before the PD cluster is initialized
+	td = NewTiDBDiscovery(fakePDControl, dmapi.NewFakeMasterControl(kubeCli), cli, kubeCli)
+	_, err = td.Discover(url("demo-pd-1"))
+	g.Expect(err).To(HaveOccurred())
+	args, err = td.Discover(url("demo-pd-2"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal("--initial-cluster=demo-pd-2=http://demo-pd-2.demo-pd-peer.default.svc:2380"))
+	initialized = true
+	// without the record, demo-pd-0 would complete the peers and bootstrap another PD cluster
+	args, err = td.Discover(url("demo-pd-0"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal("--join=http://demo-pd-2.demo-pd-peer.default.svc:2379"))
+	args, err = td.Discover(url("demo-pd-1"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal("--join=http://demo-pd-2.demo-pd-peer.default.svc:2379"))
+
+	// the PVC of demo-pd-2 is replaced after the PD cluster is initialized, it joins the PD cluster
+	// instead of bootstrapping another one
+	members = []string{"demo-pd-0", "demo-pd-1", "demo-pd-2"}
+	tc.Status.ClusterID = "6868"
+	_, err = cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Update(context.TODO(), tc, metav1.UpdateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	args, err = td.Discover(url("demo-pd-2"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(args).To(Equal("--join=http://demo-pd-0.demo-pd-peer.default.svc:2379,http://demo-pd-1.demo-pd-peer.default.svc:2379"))
+}
+
+func TestDiscoveryDiscoverV2(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// the PD cluster is scaled in from 5 to 3
+	members := &pdapi.MembersInfo{
+		Members: []*pdpb.Member{
+			{Name: "demo-pd-0", MemberId: 100, PeerUrls: []string{"http://demo-pd-0.demo-pd-peer.default.svc:2380"}},
+			{Name: "demo-pd-1", MemberId: 101, PeerUrls: []string{"http://demo-pd-1.demo-pd-peer.default.svc:2380"}},
+			{Name: "demo-pd-2", MemberId: 102, PeerUrls: []string{"http://demo-pd-2.demo-pd-peer.default.svc:2380"}},
+		},
+	}
+	tests := []struct {
+		name        string
+		url         string
+		memberID    uint64
+		replicas    int32
+		expectArgs  string
+		expectPurge bool
+	}{
+		{
+			name:        "previous member is in the cluster",
+			url:         "demo-pd-1.demo-pd-peer.default.svc:2380",
+			memberID:    101,
+			replicas:    3,
+			expectArgs:  "--join=http://demo-pd-0.demo-pd-peer.default.svc:2379,http://demo-pd-2.demo-pd-peer.default.svc:2379",
+			expectPurge: false,
+		},
+		{
+			name:        "previous member of a stale ordinal is removed",
+			url:         "demo-pd-4.demo-pd-peer.default.svc:2380",
+			memberID:    104,
+			replicas:    3,
+			expectArgs:  "--join=http://demo-pd-0.demo-pd-peer.default.svc:2379,http://demo-pd-1.demo-pd-peer.default.svc:2379,http://demo-pd-2.demo-pd-peer.default.svc:2379",
+			expectPurge: true,
+		},
+		{
+			name:        "previous member is unknown",
+			url:         "demo-pd-4.demo-pd-peer.default.svc:2380",
+			replicas:    3,
+			expectArgs:  "--join=http://demo-pd-0.demo-pd-peer.default.svc:2379,http://demo-pd-1.demo-pd-peer.default.svc:2379,http://demo-pd-2.demo-pd-peer.default.svc:2379",
+			expectPurge: false,
+		},
+		{
+			name:        "initialize the cluster",
+			url:         "demo-pd-0.demo-pd-peer.default.svc:2380",
+			memberID:    100,
+			replicas:    1,
+			expectArgs:  "--initial-cluster=demo-pd-0=http://demo-pd-0.demo-pd-peer.default.svc:2380",
+			expectPurge: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Log(tt.name)
+		cli := fake.NewSimpleClientset()
+		kubeCli := kubefake.NewSimpleClientset()
+		fakePDControl := pdapi.NewFakePDControl(kubeCli)
+		pdClient := pdapi.NewFakePDClient()
+		tc := newTC()
+		tc.Spec.PD.Replicas = tt.replicas
+		cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+		fakePDControl.SetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), pdClient)
+		pdClient.AddReaction(pdapi.GetMembersActionType, func(action *pdapi.Action) (interface{}, error) {
+			return members, nil
+		})
+
+		td := NewTiDBDiscovery(fakePDControl, dmapi.NewFakeMasterControl(kubeCli), cli, kubeCli)
+		os.Setenv("MY_POD_NAMESPACE", "default")
+		result, err := td.DiscoverV2(tt.url, tt.memberID)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.Args).To(Equal(tt.expectArgs))
+		g.Expect(result.Purge).To(Equal(tt.expectPurge))
+	}
+}
+
 func TestDiscoveryDMDiscovery(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -13,6 +13,11 @@
 
 package server
 
+import "crypto/tls"
+
 type Server interface {
 	ListenAndServe(addr string)
+	// ListenAndServeTLS serves HTTPS with the given TLS config,
+	// the certificate is expected to be provided by tlsConfig.GetCertificate
+	ListenAndServeTLS(addr string, tlsConfig *tls.Config)
 }
@@ -100,3 +100,8 @@ func (p *proxyServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 func (p *proxyServer) ListenAndServe(addr string) {
 	klog.Fatal(http.ListenAndServe(addr, p))
 }
+
+func (p *proxyServer) ListenAndServeTLS(addr string, tlsConfig *tls.Config) {
+	srv := &http.Server{Addr: addr, Handler: p, TLSConfig: tlsConfig}
+	klog.Fatal(srv.ListenAndServeTLS("", ""))
+}
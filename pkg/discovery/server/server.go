@@ -14,10 +14,12 @@
 package server
 
 import (
+	"crypto/tls"
 	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/pingcap/tidb-operator/pkg/dmapi"
@@ -30,9 +32,14 @@ import (
 	"k8s.io/klog"
 )
 
+// discoverFunc returns the start args of a member of a component by its advertise peer URL
+type discoverFunc func(advertisePeerURL string) (string, error)
+
 type server struct {
 	discovery discovery.TiDBDiscovery
 	container *restful.Container
+	// discoverers are the discover functions of the components, keyed by the register type
+	discoverers map[string]discoverFunc
 }
 
 // NewServer creates a new server.
@@ -41,6 +48,11 @@ func NewServer(pdControl pdapi.PDControlInterface, masterControl dmapi.MasterCon
 		discovery: discovery.NewTiDBDiscovery(pdControl, masterControl, cli, kubeCli),
 		container: restful.NewContainer(),
 	}
+	s.discoverers = map[string]discoverFunc{
+		"pd":        s.discovery.Discover,
+		"dm":        s.discovery.DiscoverDM,
+		"dm-master": s.discovery.DiscoverDM,
+	}
 	s.registerHandlers()
 	return s
 }
@@ -49,6 +61,9 @@ func (s *server) registerHandlers() {
 	ws := new(restful.WebService)
 	ws.Route(ws.GET("/new/{advertise-peer-url}").To(s.newHandler))
 	ws.Route(ws.GET("/new/{advertise-peer-url}/{register-type}").To(s.newHandler))
+	ws.Route(ws.GET("/new/dm-master/{advertise-peer-url}").To(s.newComponentHandler("dm-master")))
+	// v2 returns the start args of a PD member along with the purge hint in JSON
+	ws.Route(ws.GET("/new/v2/{advertise-peer-url}").To(s.newV2Handler))
 	ws.Route(ws.GET("/verify/{pd-url}").To(s.newVerifyHandler))
 	s.container.Add(ws)
 }
@@ -57,12 +72,28 @@ func (s *server) ListenAndServe(addr string) {
 	klog.Fatal(http.ListenAndServe(addr, s.container.ServeMux))
 }
 
+func (s *server) ListenAndServeTLS(addr string, tlsConfig *tls.Config) {
+	srv := &http.Server{Addr: addr, Handler: s.container.ServeMux, TLSConfig: tlsConfig}
+	klog.Fatal(srv.ListenAndServeTLS("", ""))
+}
+
 func (s *server) newHandler(req *restful.Request, resp *restful.Response) {
-	encodedAdvertisePeerURL := req.PathParameter("advertise-peer-url")
 	registerType := req.PathParameter("register-type")
 	if registerType == "" {
 		registerType = "pd"
 	}
+	s.discover(req, resp, registerType)
+}
+
+// newComponentHandler returns the handler discovering the members of the component
+func (s *server) newComponentHandler(registerType string) restful.RouteFunction {
+	return func(req *restful.Request, resp *restful.Response) {
+		s.discover(req, resp, registerType)
+	}
+}
+
+func (s *server) discover(req *restful.Request, resp *restful.Response, registerType string) {
+	encodedAdvertisePeerURL := req.PathParameter("advertise-peer-url")
 	data, err := base64.StdEncoding.DecodeString(encodedAdvertisePeerURL)
 	if err != nil {
 		klog.Errorf("failed to decode advertise-peer-url: %s, register-type is: %s", encodedAdvertisePeerURL, registerType)
@@ -73,13 +104,8 @@ func (s *server) newHandler(req *restful.Request, resp *restful.Response) {
 	}
 	advertisePeerURL := string(data)
 
-	var result string
-	switch registerType {
-	case "pd":
-		result, err = s.discovery.Discover(advertisePeerURL)
-	case "dm":
-		result, err = s.discovery.DiscoverDM(advertisePeerURL)
-	default:
+	discoverFn, ok := s.discoverers[registerType]
+	if !ok {
 		err = fmt.Errorf("invalid register-type %s", registerType)
 		klog.Errorf("%v", err)
 		if werr := resp.WriteError(http.StatusInternalServerError, err); werr != nil {
@@ -87,6 +113,7 @@ func (s *server) newHandler(req *restful.Request, resp *restful.Response) {
 		}
 		return
 	}
+	result, err := discoverFn(advertisePeerURL)
 	if err != nil {
 		klog.Errorf("failed to discover: %s, %v, register-type is: %s", advertisePeerURL, err, registerType)
 		if werr := resp.WriteError(http.StatusInternalServerError, err); werr != nil {
@@ -102,6 +129,46 @@ func (s *server) newHandler(req *restful.Request, resp *restful.Response) {
 
 }
 
+func (s *server) newV2Handler(req *restful.Request, resp *restful.Response) {
+	encodedAdvertisePeerURL := req.PathParameter("advertise-peer-url")
+	data, err := base64.StdEncoding.DecodeString(encodedAdvertisePeerURL)
+	if err != nil {
+		klog.Errorf("failed to decode advertise-peer-url: %s", encodedAdvertisePeerURL)
+		if werr := resp.WriteError(http.StatusInternalServerError, err); werr != nil {
+			klog.Errorf("failed to writeError: %v", werr)
+		}
+		return
+	}
+	advertisePeerURL := string(data)
+
+	// member-id is the ID of the previous member in the data dir, it's optional
+	var memberID uint64
+	if id := req.QueryParameter("member-id"); id != "" {
+		memberID, err = strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			klog.Errorf("failed to parse member-id: %s of %s", id, advertisePeerURL)
+			if werr := resp.WriteError(http.StatusBadRequest, err); werr != nil {
+				klog.Errorf("failed to writeError: %v", werr)
+			}
+			return
+		}
+	}
+
+	result, err := s.discovery.DiscoverV2(advertisePeerURL, memberID)
+	if err != nil {
+		klog.Errorf("failed to discover: %s, %v", advertisePeerURL, err)
+		if werr := resp.WriteError(http.StatusInternalServerError, err); werr != nil {
+			klog.Errorf("failed to writeError: %v", werr)
+		}
+		return
+	}
+
+	klog.Infof("generated args for %s: %s, purge: %t", advertisePeerURL, result.Args, result.Purge)
+	if err := resp.WriteAsJson(result); err != nil {
+		klog.Errorf("failed to writeJson: %+v, %v", result, err)
+	}
+}
+
 func (s *server) newVerifyHandler(req *restful.Request, resp *restful.Response) {
 	encodedPDPeerURL := req.PathParameter("pd-url")
 	data, err := base64.StdEncoding.DecodeString(encodedPDPeerURL)
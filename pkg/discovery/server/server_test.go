@@ -16,6 +16,7 @@ package server
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -30,6 +31,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	"github.com/pingcap/tidb-operator/pkg/discovery"
 	"github.com/pingcap/tidb-operator/pkg/dmapi"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	"golang.org/x/sync/errgroup"
@@ -146,6 +148,58 @@ func TestServer(t *testing.T) {
 	}
 }
 
+func TestServerV2(t *testing.T) {
+	os.Setenv("MY_POD_NAMESPACE", "default")
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	fakePDControl := pdapi.NewFakePDControl(kubeCli)
+	pdClient := pdapi.NewFakePDClient()
+	s := NewServer(fakePDControl, dmapi.NewFakeMasterControl(kubeCli), cli, kubeCli)
+	httpServer := httptest.NewServer(s.(*server).container.ServeMux)
+	defer httpServer.Close()
+
+	pdClient.AddReaction(pdapi.GetMembersActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.MembersInfo{
+			Members: []*pdpb.Member{
+				{Name: "foo-pd-0", MemberId: 100, PeerUrls: []string{"http://foo-pd-0.foo-pd-peer.default.svc:2380"}},
+			},
+		}, nil
+	})
+	cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+	fakePDControl.SetPDClient(pdapi.Namespace(tc.Namespace), tc.Name, pdClient)
+
+	// a pod with a stale ordinal restarts with the data of a removed member
+	svc := base64.StdEncoding.EncodeToString([]byte("foo-pd-4.foo-pd-peer.default.svc:2380"))
+	resp, err := http.Get(httpServer.URL + fmt.Sprintf("/new/v2/%s?member-id=104", svc))
+	if err != nil {
+		t.Fatalf("get pd info failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status code expects %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	result := &discovery.DiscoverResult{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		t.Fatalf("decode result failed: %v", err)
+	}
+	if !result.Purge {
+		t.Errorf("purge expects true")
+	}
+	if result.Args != "--join=http://foo-pd-0.foo-pd-peer.default.svc:2379" {
+		t.Errorf("unexpected args %q", result.Args)
+	}
+
+	// invalid member id
+	resp, err = http.Get(httpServer.URL + fmt.Sprintf("/new/v2/%s?member-id=foo", svc))
+	if err != nil {
+		t.Fatalf("get pd info failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status code expects %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
 func TestDMServer(t *testing.T) {
 	os.Setenv("MY_POD_NAMESPACE", "default")
 	cli := fake.NewSimpleClientset()
@@ -257,3 +311,65 @@ func TestVerifyServer(t *testing.T) {
 		t.Errorf("verify pdEndpoint failed: %v", err)
 	}
 }
+
+func TestDMMasterServer(t *testing.T) {
+	os.Setenv("MY_POD_NAMESPACE", "default")
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	fakeMasterControl := dmapi.NewFakeMasterControl(kubeCli)
+	s := NewServer(pdapi.NewFakePDControl(kubeCli), fakeMasterControl, cli, kubeCli)
+	httpServer := httptest.NewServer(s.(*server).container.ServeMux)
+	defer httpServer.Close()
+
+	// the cluster to bootstrap
+	bootstrapDC := dc.DeepCopy()
+	bootstrapDC.Spec.Master.Replicas = 1
+	cli.PingcapV1alpha1().DMClusters(bootstrapDC.Namespace).Create(context.TODO(), bootstrapDC, metav1.CreateOptions{})
+
+	// the cluster to join
+	joinDC := dc.DeepCopy()
+	joinDC.Name = "bar"
+	cli.PingcapV1alpha1().DMClusters(joinDC.Namespace).Create(context.TODO(), joinDC, metav1.CreateOptions{})
+	masterClient := dmapi.NewFakeMasterClient()
+	masterClient.AddReaction(dmapi.GetMastersActionType, func(action *dmapi.Action) (interface{}, error) {
+		return []*dmapi.MastersInfo{
+			{Name: "bar-dm-master-0", PeerURLs: []string{"http://bar-dm-master-0.bar-dm-master-peer:8291"}},
+		}, nil
+	})
+	fakeMasterControl.SetMasterClient(joinDC.Namespace, joinDC.Name, masterClient)
+
+	tests := []struct {
+		name   string
+		svc    string
+		expect string
+	}{
+		{
+			name:   "first member bootstraps the cluster",
+			svc:    "foo-dm-master-0.foo-dm-master-peer:8291",
+			expect: "--initial-cluster=foo-dm-master-0=http://foo-dm-master-0.foo-dm-master-peer:8291",
+		},
+		{
+			name:   "member joins the existing cluster",
+			svc:    "bar-dm-master-1.bar-dm-master-peer:8291",
+			expect: "--join=http://bar-dm-master-0.bar-dm-master-peer:8261",
+		},
+	}
+	for _, tt := range tests {
+		url := httpServer.URL + fmt.Sprintf("/new/dm-master/%s", base64.StdEncoding.EncodeToString([]byte(tt.svc)))
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Fatalf("%s: get dm-master info failed: %v", tt.name, err)
+		}
+		data, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("%s: read response failed: %v", tt.name, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: status code expects %d, got %d", tt.name, http.StatusOK, resp.StatusCode)
+		}
+		if string(data) != tt.expect {
+			t.Errorf("%s: expects %q, got %q", tt.name, tt.expect, string(data))
+		}
+	}
+}
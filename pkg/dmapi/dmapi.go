@@ -14,6 +14,7 @@
 package dmapi
 
 import (
+	"bytes"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -36,11 +37,14 @@ type MasterClient interface {
 	EvictLeader() error
 	DeleteMaster(name string) error
 	DeleteWorker(name string) error
+	// TransferSource transfers the source to the worker
+	TransferSource(source, worker string) error
 }
 
 var (
 	membersPrefix = "apis/v1alpha1/members"
 	leaderPrefix  = "apis/v1alpha1/leader"
+	sourcesPrefix = "api/v1/sources"
 )
 
 type RespHeader struct {
@@ -48,6 +52,10 @@ type RespHeader struct {
 	Msg    string `json:"msg,omitempty"`
 }
 
+type transferSourceReq struct {
+	WorkerName string `json:"worker_name"`
+}
+
 type MastersInfo struct {
 	Name       string   `json:"name,omitempty"`
 	MemberID   string   `json:"memberID,omitempty"`
@@ -225,6 +233,19 @@ func (c *masterClient) DeleteWorker(name string) error {
 	return c.deleteMember(query)
 }
 
+func (c *masterClient) TransferSource(source, worker string) error {
+	apiURL := fmt.Sprintf("%s/%s/%s/transfer", c.url, sourcesPrefix, source)
+	data, err := json.Marshal(&transferSourceReq{WorkerName: worker})
+	if err != nil {
+		return err
+	}
+	_, err = httputil.PostBodyOK(c.httpClient, apiURL, bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("unable to transfer source %s to worker %s, err: %s", source, worker, err)
+	}
+	return nil
+}
+
 // NewMasterClient returns a new MasterClient
 func NewMasterClient(url string, timeout time.Duration, tlsConfig *tls.Config, disableKeepalive bool) MasterClient {
 	return &masterClient{
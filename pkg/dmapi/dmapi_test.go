@@ -208,3 +208,25 @@ func TestDeleteMember(t *testing.T) {
 		g.Expect(err).NotTo(HaveOccurred())
 	}
 }
+
+func TestTransferSource(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+		g.Expect(request.Method).To(Equal("POST"), "check method")
+		g.Expect(request.URL.Path).To(Equal(fmt.Sprintf("/%s/mysql-replica-01/transfer", sourcesPrefix)), "check url")
+
+		req := &transferSourceReq{}
+		g.Expect(json.NewDecoder(request.Body).Decode(req)).To(Succeed())
+		if req.WorkerName != "dm-worker-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	defer svc.Close()
+
+	masterClient := NewMasterClient(svc.URL, DefaultTimeout, &tls.Config{}, false)
+	g.Expect(masterClient.TransferSource("mysql-replica-01", "dm-worker-1")).To(Succeed())
+	g.Expect(masterClient.TransferSource("mysql-replica-01", "dm-worker-2")).NotTo(Succeed())
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// blockingConditionReconciler maintains a blocking condition (e.g. UpgradeBlocked) of a TidbCluster
+// on behalf of one component, and records events only when the condition transitions, so that
+// a condition which stays blocked does not produce duplicate events, and a condition whose check
+// passes again, e.g. after a manual intervention, is cleared with a Resolved event.
+//
+// The condition type is prefixed by the component, e.g. PDUpgradeBlocked, so that the condition
+// blocked by a component is neither overwritten nor resolved by another component.
+type blockingConditionReconciler struct {
+	recorder record.EventRecorder
+	condType v1alpha1.TidbClusterConditionType
+}
+
+func newBlockingConditionReconciler(recorder record.EventRecorder, condType v1alpha1.TidbClusterConditionType, component v1alpha1.MemberType) *blockingConditionReconciler {
+	return &blockingConditionReconciler{
+		recorder: recorder,
+		condType: controller.ConditionTypeFor(condType, component),
+	}
+}
+
+// Block sets the condition to True with the blocking reason, a Warning event is recorded
+// if the condition was not blocked by the same reason before.
+func (r *blockingConditionReconciler) Block(tc *v1alpha1.TidbCluster, reason, message string) {
+	cur := utiltidbcluster.GetTidbClusterCondition(tc.Status, r.condType)
+	if cur != nil && cur.Status == corev1.ConditionTrue && cur.Reason == reason {
+		if cur.Message != message {
+			// the same check is still blocking, only refresh the message
+			for i := range tc.Status.Conditions {
+				if tc.Status.Conditions[i].Type == r.condType {
+					tc.Status.Conditions[i].Message = message
+					tc.Status.Conditions[i].LastUpdateTime = metav1.Now()
+				}
+			}
+		}
+		return
+	}
+
+	cond := utiltidbcluster.NewTidbClusterCondition(r.condType, corev1.ConditionTrue, reason, message)
+	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+	r.recorder.Event(tc, corev1.EventTypeWarning, string(r.condType), message)
+}
+
+// Resolve sets the condition to False if it was blocked, and records a Resolved event.
+func (r *blockingConditionReconciler) Resolve(tc *v1alpha1.TidbCluster) {
+	cur := utiltidbcluster.GetTidbClusterCondition(tc.Status, r.condType)
+	if cur == nil || cur.Status != corev1.ConditionTrue {
+		return
+	}
+
+	message := fmt.Sprintf("%s is resolved, previous reason: %s", r.condType, cur.Reason)
+	cond := utiltidbcluster.NewTidbClusterCondition(r.condType, corev1.ConditionFalse, utiltidbcluster.Resolved, message)
+	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+	r.recorder.Event(tc, corev1.EventTypeNormal, utiltidbcluster.Resolved, message)
+}
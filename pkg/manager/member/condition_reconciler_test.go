@@ -0,0 +1,207 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestBlockingConditionReconciler(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type action struct {
+		component v1alpha1.MemberType
+		resolve   bool
+		reason    string
+		message   string
+
+		expectStatus  corev1.ConditionStatus
+		expectReason  string
+		expectMessage string
+		expectEvents  []string
+	}
+	type testcase struct {
+		name    string
+		actions []action
+	}
+
+	testFn := func(test testcase) {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		recorder := record.NewFakeRecorder(10)
+
+		for _, a := range test.actions {
+			r := newBlockingConditionReconciler(recorder, v1alpha1.UpgradeBlocked, a.component)
+			if a.resolve {
+				r.Resolve(tc)
+			} else {
+				r.Block(tc, a.reason, a.message)
+			}
+
+			cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, controller.ConditionTypeFor(v1alpha1.UpgradeBlocked, a.component))
+			if a.expectStatus == "" {
+				g.Expect(cond).To(BeNil())
+			} else {
+				g.Expect(cond).NotTo(BeNil())
+				g.Expect(cond.Status).To(Equal(a.expectStatus))
+				g.Expect(cond.Reason).To(Equal(a.expectReason))
+				g.Expect(cond.Message).To(Equal(a.expectMessage))
+			}
+			events := collectEvents(recorder.Events)
+			if len(a.expectEvents) == 0 {
+				g.Expect(events).To(BeEmpty())
+			} else {
+				g.Expect(events).To(Equal(a.expectEvents))
+			}
+		}
+	}
+
+	tests := []testcase{
+		{
+			name: "resolve without blocking",
+			actions: []action{
+				{component: v1alpha1.PDMemberType, resolve: true},
+			},
+		},
+		{
+			name: "set, resolve and re-set",
+			actions: []action{
+				{
+					component:     v1alpha1.PDMemberType,
+					reason:        utiltidbcluster.UpgradedPodNotReady,
+					message:       "upgraded pod test-pd-2 is not ready",
+					expectStatus:  corev1.ConditionTrue,
+					expectReason:  utiltidbcluster.UpgradedPodNotReady,
+					expectMessage: "upgraded pod test-pd-2 is not ready",
+					expectEvents:  []string{"Warning PDUpgradeBlocked upgraded pod test-pd-2 is not ready"},
+				},
+				{
+					component:     v1alpha1.PDMemberType,
+					reason:        utiltidbcluster.UpgradedPodNotReady,
+					message:       "upgraded pod test-pd-2 is not ready",
+					expectStatus:  corev1.ConditionTrue,
+					expectReason:  utiltidbcluster.UpgradedPodNotReady,
+					expectMessage: "upgraded pod test-pd-2 is not ready",
+				},
+				{
+					component:     v1alpha1.PDMemberType,
+					resolve:       true,
+					expectStatus:  corev1.ConditionFalse,
+					expectReason:  utiltidbcluster.Resolved,
+					expectMessage: "PDUpgradeBlocked is resolved, previous reason: UpgradedPodNotReady",
+					expectEvents:  []string{"Normal Resolved PDUpgradeBlocked is resolved, previous reason: UpgradedPodNotReady"},
+				},
+				{
+					component:     v1alpha1.PDMemberType,
+					resolve:       true,
+					expectStatus:  corev1.ConditionFalse,
+					expectReason:  utiltidbcluster.Resolved,
+					expectMessage: "PDUpgradeBlocked is resolved, previous reason: UpgradedPodNotReady",
+				},
+				{
+					component:     v1alpha1.PDMemberType,
+					reason:        utiltidbcluster.UpgradedPodNotReady,
+					message:       "upgraded pod test-pd-1 is not ready",
+					expectStatus:  corev1.ConditionTrue,
+					expectReason:  utiltidbcluster.UpgradedPodNotReady,
+					expectMessage: "upgraded pod test-pd-1 is not ready",
+					expectEvents:  []string{"Warning PDUpgradeBlocked upgraded pod test-pd-1 is not ready"},
+				},
+			},
+		},
+		{
+			name: "refresh the message of the same reason without events",
+			actions: []action{
+				{
+					component:     v1alpha1.TiKVMemberType,
+					reason:        utiltidbcluster.UpgradedStoreNotUp,
+					message:       "store 1 of upgraded pod test-tikv-2 is Down",
+					expectStatus:  corev1.ConditionTrue,
+					expectReason:  utiltidbcluster.UpgradedStoreNotUp,
+					expectMessage: "store 1 of upgraded pod test-tikv-2 is Down",
+					expectEvents:  []string{"Warning TiKVUpgradeBlocked store 1 of upgraded pod test-tikv-2 is Down"},
+				},
+				{
+					component:     v1alpha1.TiKVMemberType,
+					reason:        utiltidbcluster.UpgradedStoreNotUp,
+					message:       "store 1 of upgraded pod test-tikv-2 is Offline",
+					expectStatus:  corev1.ConditionTrue,
+					expectReason:  utiltidbcluster.UpgradedStoreNotUp,
+					expectMessage: "store 1 of upgraded pod test-tikv-2 is Offline",
+				},
+				{
+					component:     v1alpha1.TiKVMemberType,
+					reason:        utiltidbcluster.UpgradedPodNotReady,
+					message:       "upgraded pod test-tikv-2 is not ready",
+					expectStatus:  corev1.ConditionTrue,
+					expectReason:  utiltidbcluster.UpgradedPodNotReady,
+					expectMessage: "upgraded pod test-tikv-2 is not ready",
+					expectEvents:  []string{"Warning TiKVUpgradeBlocked upgraded pod test-tikv-2 is not ready"},
+				},
+			},
+		},
+		{
+			name: "the conditions of the components are kept independently",
+			actions: []action{
+				{
+					component:     v1alpha1.PDMemberType,
+					reason:        utiltidbcluster.UpgradedPodNotReady,
+					message:       "upgraded pod test-pd-2 is not ready",
+					expectStatus:  corev1.ConditionTrue,
+					expectReason:  utiltidbcluster.UpgradedPodNotReady,
+					expectMessage: "upgraded pod test-pd-2 is not ready",
+					expectEvents:  []string{"Warning PDUpgradeBlocked upgraded pod test-pd-2 is not ready"},
+				},
+				{
+					// the same reason of another component is not deduplicated with the one of PD
+					component:     v1alpha1.TiKVMemberType,
+					reason:        utiltidbcluster.UpgradedPodNotReady,
+					message:       "upgraded pod test-tikv-2 is not ready",
+					expectStatus:  corev1.ConditionTrue,
+					expectReason:  utiltidbcluster.UpgradedPodNotReady,
+					expectMessage: "upgraded pod test-tikv-2 is not ready",
+					expectEvents:  []string{"Warning TiKVUpgradeBlocked upgraded pod test-tikv-2 is not ready"},
+				},
+				{
+					component:     v1alpha1.TiKVMemberType,
+					resolve:       true,
+					expectStatus:  corev1.ConditionFalse,
+					expectReason:  utiltidbcluster.Resolved,
+					expectMessage: "TiKVUpgradeBlocked is resolved, previous reason: UpgradedPodNotReady",
+					expectEvents:  []string{"Normal Resolved TiKVUpgradeBlocked is resolved, previous reason: UpgradedPodNotReady"},
+				},
+				{
+					// the block of PD survives the resolve of TiKV
+					component:     v1alpha1.PDMemberType,
+					reason:        utiltidbcluster.UpgradedPodNotReady,
+					message:       "upgraded pod test-pd-2 is not ready",
+					expectStatus:  corev1.ConditionTrue,
+					expectReason:  utiltidbcluster.UpgradedPodNotReady,
+					expectMessage: "upgraded pod test-pd-2 is not ready",
+				},
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(tests[i])
+	}
+}
@@ -31,6 +31,8 @@ type masterFailover struct {
 	deps *controller.Dependencies
 }
 
+var _ DMFailover = &masterFailover{}
+
 // NewMasterFailover returns a master Failover
 func NewMasterFailover(deps *controller.Dependencies) DMFailover {
 	return &masterFailover{
@@ -86,6 +86,7 @@ func (m *masterMemberManager) syncMasterServiceForDMCluster(dc *v1alpha1.DMClust
 	dcName := dc.GetName()
 
 	newSvc := m.getNewMasterServiceForDMCluster(dc)
+	addStandardLabels(newSvc)
 	oldSvcTmp, err := m.deps.ServiceLister.Services(ns).Get(controller.DMMasterMemberName(dcName))
 	if errors.IsNotFound(err) {
 		err = controller.SetServiceLastAppliedConfigAnnotation(newSvc)
@@ -105,8 +106,10 @@ func (m *masterMemberManager) syncMasterServiceForDMCluster(dc *v1alpha1.DMClust
 	if err != nil {
 		return err
 	}
-	if !equal {
+	svcLabels, labelsChanged := mergeStandardLabels(newSvc, oldSvc)
+	if !equal || labelsChanged {
 		svc := *oldSvc
+		svc.Labels = svcLabels
 		svc.Spec = newSvc.Spec
 		err = controller.SetServiceLastAppliedConfigAnnotation(&svc)
 		if err != nil {
@@ -133,6 +136,7 @@ func (m *masterMemberManager) syncMasterHeadlessServiceForDMCluster(dc *v1alpha1
 	dcName := dc.GetName()
 
 	newSvc := getNewMasterHeadlessServiceForDMCluster(dc)
+	addStandardLabels(newSvc)
 	oldSvc, err := m.deps.ServiceLister.Services(ns).Get(controller.DMMasterPeerMemberName(dcName))
 	if errors.IsNotFound(err) {
 		err = controller.SetServiceLastAppliedConfigAnnotation(newSvc)
@@ -149,8 +153,10 @@ func (m *masterMemberManager) syncMasterHeadlessServiceForDMCluster(dc *v1alpha1
 	if err != nil {
 		return err
 	}
-	if !equal {
+	svcLabels, labelsChanged := mergeStandardLabels(newSvc, oldSvc)
+	if !equal || labelsChanged {
 		svc := *oldSvc
+		svc.Labels = svcLabels
 		svc.Spec = newSvc.Spec
 		err = controller.SetServiceLastAppliedConfigAnnotation(&svc)
 		if err != nil {
@@ -192,6 +198,7 @@ func (m *masterMemberManager) syncMasterStatefulSetForDMCluster(dc *v1alpha1.DMC
 	if err != nil {
 		return err
 	}
+	addStandardLabelsToStatefulSet(newMasterSet)
 	if setNotExist {
 		err = SetStatefulSetLastAppliedConfigAnnotation(newMasterSet)
 		if err != nil {
@@ -204,6 +211,8 @@ func (m *masterMemberManager) syncMasterStatefulSetForDMCluster(dc *v1alpha1.DMC
 		return controller.RequeueErrorf("DMCluster: [%s/%s], waiting for dm-master cluster running", ns, dcName)
 	}
 
+	syncPodManagementPolicy(dc, newMasterSet, oldMasterSet)
+
 	// Force update takes precedence over scaling because force upgrade won't take effect when cluster gets stuck at scaling
 	if !dc.Status.Master.Synced && NeedForceUpgrade(dc.Annotations) {
 		dc.Status.Master.Phase = v1alpha1.UpgradePhase
@@ -371,6 +380,7 @@ func (m *masterMemberManager) syncMasterConfigMap(dc *v1alpha1.DMCluster, set *a
 	if err != nil {
 		return nil, err
 	}
+	addStandardLabels(newCm)
 	return m.deps.TypedControl.CreateOrUpdateConfigMap(dc, newCm)
 }
 
@@ -696,7 +706,7 @@ func getNewMasterSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 				},
 			},
 			ServiceName:         controller.DMMasterPeerMemberName(dcName),
-			PodManagementPolicy: apps.ParallelPodManagement,
+			PodManagementPolicy: baseMasterSpec.PodManagementPolicy(),
 			UpdateStrategy: apps.StatefulSetUpdateStrategy{
 				Type: apps.RollingUpdateStatefulSetStrategyType,
 				RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/dmapi"
+	utildmcluster "github.com/pingcap/tidb-operator/pkg/util/dmcluster"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
@@ -320,6 +321,32 @@ func TestMasterMemberManagerSyncUpdate(t *testing.T) {
 				g.Expect(dc.Status.Master.Members["master3"].Health).To(Equal(false))
 			},
 		},
+		{
+			name: "podManagementPolicy of the existing statefulset is kept",
+			modify: func(dc *v1alpha1.DMCluster) {
+				dc.Spec.Master.PodManagementPolicy = apps.OrderedReadyPodManagement
+			},
+			leaderInfo: dmapi.MembersLeader{
+				Name: "master1",
+				Addr: "http://master1:2379",
+			},
+			masterInfos: []*dmapi.MastersInfo{
+				{Name: "master1", MemberID: "1", ClientURLs: []string{"http://master1:2379"}, Alive: true},
+				{Name: "master2", MemberID: "2", ClientURLs: []string{"http://master2:2379"}, Alive: true},
+				{Name: "master3", MemberID: "3", ClientURLs: []string{"http://master3:2379"}, Alive: true},
+			},
+			err: false,
+			expectStatefulSetFn: func(g *GomegaWithT, set *apps.StatefulSet, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(set.Spec.PodManagementPolicy).To(Equal(apps.ParallelPodManagement))
+			},
+			expectDMClusterFn: func(g *GomegaWithT, dc *v1alpha1.DMCluster) {
+				cond := utildmcluster.GetDMClusterCondition(dc.Status, v1alpha1.DMClusterPodManagementPolicyNotApplied)
+				g.Expect(cond).NotTo(BeNil())
+				g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+				g.Expect(cond.Reason).To(Equal(utildmcluster.PodManagementPolicyChanged))
+			},
+		},
 		{
 			name: "error when update dm-master service",
 			modify: func(dc *v1alpha1.DMCluster) {
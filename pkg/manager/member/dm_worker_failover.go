@@ -28,6 +28,8 @@ type workerFailover struct {
 	deps *controller.Dependencies
 }
 
+var _ DMFailover = &workerFailover{}
+
 // NewWorkerFailover returns a worker Failover
 func NewWorkerFailover(deps *controller.Dependencies) DMFailover {
 	return &workerFailover{deps: deps}
@@ -44,14 +44,16 @@ const (
 type workerMemberManager struct {
 	deps     *controller.Dependencies
 	scaler   Scaler
+	upgrader DMUpgrader
 	failover DMFailover
 }
 
 // NewWorkerMemberManager returns a *ticdcMemberManager
-func NewWorkerMemberManager(deps *controller.Dependencies, scaler Scaler, failover DMFailover) manager.DMManager {
+func NewWorkerMemberManager(deps *controller.Dependencies, scaler Scaler, upgrader DMUpgrader, failover DMFailover) manager.DMManager {
 	return &workerMemberManager{
 		deps:     deps,
 		scaler:   scaler,
+		upgrader: upgrader,
 		failover: failover,
 	}
 }
@@ -85,6 +87,7 @@ func (m *workerMemberManager) syncWorkerHeadlessServiceForDMCluster(dc *v1alpha1
 	dcName := dc.GetName()
 
 	newSvc := getNewWorkerHeadlessServiceForDMCluster(dc)
+	addStandardLabels(newSvc)
 	oldSvcTmp, err := m.deps.ServiceLister.Services(ns).Get(controller.DMWorkerPeerMemberName(dcName))
 	if errors.IsNotFound(err) {
 		err = controller.SetServiceLastAppliedConfigAnnotation(newSvc)
@@ -103,8 +106,10 @@ func (m *workerMemberManager) syncWorkerHeadlessServiceForDMCluster(dc *v1alpha1
 	if err != nil {
 		return err
 	}
-	if !equal {
+	svcLabels, labelsChanged := mergeStandardLabels(newSvc, oldSvc)
+	if !equal || labelsChanged {
 		svc := *oldSvc
+		svc.Labels = svcLabels
 		svc.Spec = newSvc.Spec
 		err = controller.SetServiceLastAppliedConfigAnnotation(&svc)
 		if err != nil {
@@ -189,6 +194,7 @@ func (m *workerMemberManager) syncWorkerStatefulSetForDMCluster(dc *v1alpha1.DMC
 	if err != nil {
 		return err
 	}
+	addStandardLabelsToStatefulSet(newSts)
 
 	if stsNotExist {
 		err = SetStatefulSetLastAppliedConfigAnnotation(newSts)
@@ -202,6 +208,8 @@ func (m *workerMemberManager) syncWorkerStatefulSetForDMCluster(dc *v1alpha1.DMC
 		return nil
 	}
 
+	syncPodManagementPolicy(dc, newSts, oldSts)
+
 	if err := m.scaler.Scale(dc, oldSts, newSts); err != nil {
 		return err
 	}
@@ -217,6 +225,12 @@ func (m *workerMemberManager) syncWorkerStatefulSetForDMCluster(dc *v1alpha1.DMC
 		}
 	}
 
+	if !templateEqual(newSts, oldSts) || dc.Status.Worker.Phase == v1alpha1.UpgradePhase {
+		if err := m.upgrader.Upgrade(dc, oldSts, newSts); err != nil {
+			return err
+		}
+	}
+
 	return UpdateStatefulSet(m.deps.StatefulSetControl, dc, newSts, oldSts)
 }
 
@@ -321,6 +335,7 @@ func (m *workerMemberManager) syncWorkerConfigMap(dc *v1alpha1.DMCluster, set *a
 	if err != nil {
 		return nil, err
 	}
+	addStandardLabels(newCm)
 	return m.deps.TypedControl.CreateOrUpdateConfigMap(dc, newCm)
 }
 
@@ -415,6 +430,11 @@ func getNewWorkerSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 	podAnnotations := util.CombineStringMap(controller.AnnProm(8262), baseWorkerSpec.Annotations())
 	stsAnnotations := getStsAnnotations(dc.Annotations, label.DMWorkerLabelVal)
 
+	deleteSlotsNumber, err := util.GetDeleteSlotsNumber(stsAnnotations)
+	if err != nil {
+		return nil, fmt.Errorf("get delete slots number of statefulset %s/%s failed, err:%v", ns, setName, err)
+	}
+
 	workerContainer := corev1.Container{
 		Name:            v1alpha1.DMWorkerMemberType.String(),
 		Image:           dc.WorkerImage(),
@@ -506,10 +526,12 @@ func getNewWorkerSetForDMCluster(dc *v1alpha1.DMCluster, cm *corev1.ConfigMap) (
 				},
 			},
 			ServiceName:         controller.DMWorkerPeerMemberName(dcName),
-			PodManagementPolicy: apps.ParallelPodManagement,
+			PodManagementPolicy: baseWorkerSpec.PodManagementPolicy(),
 			UpdateStrategy: apps.StatefulSetUpdateStrategy{
 				Type: apps.RollingUpdateStatefulSetStrategyType,
-			},
+				RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{
+					Partition: pointer.Int32Ptr(dc.WorkerStsDesiredReplicas() + deleteSlotsNumber),
+				}},
 		},
 	}
 
@@ -701,6 +701,7 @@ func newFakeWorkerMemberManager() (*workerMemberManager, *workerFakeControls, *w
 	pmm := &workerMemberManager{
 		deps:     fakeDeps,
 		scaler:   NewFakeWorkerScaler(),
+		upgrader: NewFakeWorkerUpgrader(),
 		failover: NewFakeWorkerFailover(),
 	}
 	controls := &workerFakeControls{
@@ -0,0 +1,215 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/dmapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+)
+
+const (
+	// TransferSourceBeginTime is the key of the begin time of transferring the source of the dm-worker
+	TransferSourceBeginTime = "transferSourceBeginTime"
+)
+
+type workerUpgrader struct {
+	deps *controller.Dependencies
+}
+
+// NewWorkerUpgrader returns a workerUpgrader
+func NewWorkerUpgrader(deps *controller.Dependencies) DMUpgrader {
+	return &workerUpgrader{
+		deps: deps,
+	}
+}
+
+func (u *workerUpgrader) Upgrade(dc *v1alpha1.DMCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	return u.gracefulUpgrade(dc, oldSet, newSet)
+}
+
+func (u *workerUpgrader) gracefulUpgrade(dc *v1alpha1.DMCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	ns := dc.GetNamespace()
+	dcName := dc.GetName()
+	if !dc.Status.Worker.Synced {
+		return fmt.Errorf("dmcluster: [%s/%s]'s dm-worker status sync failed, can not to be upgraded", ns, dcName)
+	}
+	if dc.WorkerScaling() {
+		klog.Infof("DMCluster: [%s/%s]'s dm-worker is scaling, can not upgrade dm-worker", ns, dcName)
+		_, podSpec, err := GetLastAppliedConfig(oldSet)
+		if err != nil {
+			return err
+		}
+		newSet.Spec.Template.Spec = *podSpec
+		return nil
+	}
+
+	dc.Status.Worker.Phase = v1alpha1.UpgradePhase
+	if !templateEqual(newSet, oldSet) {
+		return nil
+	}
+
+	if dc.Status.Worker.StatefulSet.UpdateRevision == dc.Status.Worker.StatefulSet.CurrentRevision {
+		return nil
+	}
+
+	if oldSet.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType || oldSet.Spec.UpdateStrategy.RollingUpdate == nil {
+		// Manually bypass tidb-operator to modify statefulset directly, the same as dm-master,
+		// we will let the native statefulset controller do the upgrade completely, the running tasks may be interrupted.
+		newSet.Spec.UpdateStrategy = oldSet.Spec.UpdateStrategy
+		klog.Warningf("dmcluster: [%s/%s] dm-worker statefulset %s UpdateStrategy has been modified manually", ns, dcName, oldSet.GetName())
+		return nil
+	}
+
+	setUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
+		i := podOrdinals[_i]
+		podName := DMWorkerPodName(dcName, i)
+		pod, err := u.deps.PodLister.Pods(ns).Get(podName)
+		if err != nil {
+			return fmt.Errorf("gracefulUpgrade: failed to get pods %s for cluster %s/%s, error: %s", podName, ns, dcName, err)
+		}
+
+		revision, exist := pod.Labels[apps.ControllerRevisionHashLabelKey]
+		if !exist {
+			return controller.RequeueErrorf("dmcluster: [%s/%s]'s dm-worker pod: [%s] has no label: %s", ns, dcName, podName, apps.ControllerRevisionHashLabelKey)
+		}
+
+		if revision == dc.Status.Worker.StatefulSet.UpdateRevision {
+			if member, exist := dc.Status.Worker.Members[podName]; !exist || member.Stage == v1alpha1.DMWorkerStateOffline {
+				return controller.RequeueErrorf("dmcluster: [%s/%s]'s dm-worker upgraded pod: [%s] is not ready", ns, dcName, podName)
+			}
+			continue
+		}
+
+		return u.upgradeWorkerPod(dc, i, pod, newSet)
+	}
+
+	return nil
+}
+
+// upgradeWorkerPod upgrades the dm-worker pod after its bound source is transferred to another dm-worker,
+// so that the tasks of the source are not interrupted by the upgrade. The pod is upgraded anyway after
+// the transfer timeout.
+func (u *workerUpgrader) upgradeWorkerPod(dc *v1alpha1.DMCluster, ordinal int32, pod *corev1.Pod, newSet *apps.StatefulSet) error {
+	ns := dc.GetNamespace()
+	dcName := dc.GetName()
+	podName := pod.GetName()
+
+	workers, err := controller.GetMasterClient(u.deps.DMMasterControl, dc).GetWorkers()
+	if err != nil {
+		return err
+	}
+	var upgradeWorker *dmapi.WorkersInfo
+	for _, worker := range workers {
+		if worker.Name == podName {
+			upgradeWorker = worker
+			break
+		}
+	}
+	if upgradeWorker == nil || upgradeWorker.Stage != v1alpha1.DMWorkerStateBound {
+		setUpgradePartition(newSet, ordinal)
+		return nil
+	}
+
+	beginTimeStr, transferring := pod.Annotations[TransferSourceBeginTime]
+	if !transferring {
+		return u.beginTransferSource(dc, upgradeWorker, workers, pod, newSet, ordinal)
+	}
+
+	beginTime, err := time.Parse(time.RFC3339, beginTimeStr)
+	if err != nil {
+		klog.Errorf("parse annotation:[%s] to time failed.", TransferSourceBeginTime)
+		return err
+	}
+	timeout := u.deps.CLIConfig.WorkerTransferTimeout
+	if time.Now().After(beginTime.Add(timeout)) {
+		u.deps.Recorder.Eventf(dc, corev1.EventTypeWarning, controller.TransferSourceTimeout.For(v1alpha1.DMWorkerMemberType),
+			"source %s of dm-worker %s is not transferred in %v, upgrade it anyway", upgradeWorker.Source, podName, timeout)
+		setUpgradePartition(newSet, ordinal)
+		return nil
+	}
+
+	return controller.RequeueErrorf("dmcluster: [%s/%s]'s dm-worker pod: [%s] is transferring source %s", ns, dcName, podName, upgradeWorker.Source)
+}
+
+// beginTransferSource transfers the source of the dm-worker to a free dm-worker, and records the begin time
+// in the annotations of the pod. If there isn't any free dm-worker or the source fails to be transferred, the
+// dm-worker is upgraded directly and the source is rebound by dm-master once a dm-worker is free.
+func (u *workerUpgrader) beginTransferSource(dc *v1alpha1.DMCluster, upgradeWorker *dmapi.WorkersInfo, workers []*dmapi.WorkersInfo,
+	pod *corev1.Pod, newSet *apps.StatefulSet, ordinal int32) error {
+	ns := dc.GetNamespace()
+	dcName := dc.GetName()
+	podName := pod.GetName()
+
+	var freeWorker *dmapi.WorkersInfo
+	for _, worker := range workers {
+		if worker.Name != podName && worker.Stage == v1alpha1.DMWorkerStateFree {
+			freeWorker = worker
+			break
+		}
+	}
+	if freeWorker == nil {
+		klog.Infof("dm-worker upgrader: no free dm-worker to transfer source %s of %s/%s, rely on the rebinding of dm-master",
+			upgradeWorker.Source, ns, podName)
+		setUpgradePartition(newSet, ordinal)
+		return nil
+	}
+
+	err := controller.GetMasterClient(u.deps.DMMasterControl, dc).TransferSource(upgradeWorker.Source, freeWorker.Name)
+	if err != nil {
+		klog.Errorf("dm-worker upgrader: failed to transfer source %s of %s/%s to %s, rely on the rebinding of dm-master, %v",
+			upgradeWorker.Source, ns, podName, freeWorker.Name, err)
+		u.deps.Recorder.Eventf(dc, corev1.EventTypeWarning, controller.TransferSourceFailed.For(v1alpha1.DMWorkerMemberType),
+			"failed to transfer source %s of dm-worker %s to %s, upgrade it anyway: %v", upgradeWorker.Source, podName, freeWorker.Name, err)
+		setUpgradePartition(newSet, ordinal)
+		return nil
+	}
+	klog.Infof("dm-worker upgrader: transfer source %s of %s/%s to %s successfully", upgradeWorker.Source, ns, podName, freeWorker.Name)
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	now := time.Now().Format(time.RFC3339)
+	pod.Annotations[TransferSourceBeginTime] = now
+	_, err = u.deps.PodControl.UpdatePod(dc, pod)
+	if err != nil {
+		klog.Errorf("dm-worker upgrader: failed to set pod %s/%s annotation %s to %s, %v",
+			ns, podName, TransferSourceBeginTime, now, err)
+		return err
+	}
+	return controller.RequeueErrorf("dmcluster: [%s/%s]'s dm-worker pod: [%s] is transferring source %s", ns, dcName, podName, upgradeWorker.Source)
+}
+
+type fakeWorkerUpgrader struct{}
+
+// NewFakeWorkerUpgrader returns a fakeWorkerUpgrader
+func NewFakeWorkerUpgrader() DMUpgrader {
+	return &fakeWorkerUpgrader{}
+}
+
+func (u *fakeWorkerUpgrader) Upgrade(dc *v1alpha1.DMCluster, _ *apps.StatefulSet, _ *apps.StatefulSet) error {
+	if !dc.Status.Worker.Synced {
+		return fmt.Errorf("dmcluster: dm-worker status sync failed, can not to be upgraded")
+	}
+	dc.Status.Worker.Phase = v1alpha1.UpgradePhase
+	return nil
+}
@@ -0,0 +1,305 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/dmapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+)
+
+func TestWorkerUpgraderUpgrade(t *testing.T) {
+	upgradePodName := DMWorkerPodName(upgradeTcName, 1)
+
+	tests := []struct {
+		name              string
+		synced            bool
+		upgradeStage      string
+		freeWorker        bool
+		transferBeginTime *time.Time
+		transferErr       bool
+		upgradedOffline   bool
+		expectErr         bool
+		expectRequeue     bool
+		expectPartition   int32
+		expectTransfer    bool
+		expectAnnotation  bool
+		expectEvent       string
+	}{
+		{
+			name:            "status not synced",
+			synced:          false,
+			upgradeStage:    v1alpha1.DMWorkerStateFree,
+			expectErr:       true,
+			expectPartition: 3,
+		},
+		{
+			name:            "upgrade the free worker",
+			synced:          true,
+			upgradeStage:    v1alpha1.DMWorkerStateFree,
+			expectPartition: 1,
+		},
+		{
+			name:             "transfer the source of the bound worker",
+			synced:           true,
+			upgradeStage:     v1alpha1.DMWorkerStateBound,
+			freeWorker:       true,
+			expectErr:        true,
+			expectRequeue:    true,
+			expectPartition:  2,
+			expectTransfer:   true,
+			expectAnnotation: true,
+		},
+		{
+			name:            "failed to transfer the source of the bound worker",
+			synced:          true,
+			upgradeStage:    v1alpha1.DMWorkerStateBound,
+			freeWorker:      true,
+			transferErr:     true,
+			expectPartition: 1,
+			expectTransfer:  true,
+			expectEvent:     "DMWorkerTransferSourceFailed",
+		},
+		{
+			name:            "upgrade the bound worker without any free worker",
+			synced:          true,
+			upgradeStage:    v1alpha1.DMWorkerStateBound,
+			expectPartition: 1,
+		},
+		{
+			name:              "wait for the transfer of the source",
+			synced:            true,
+			upgradeStage:      v1alpha1.DMWorkerStateBound,
+			freeWorker:        true,
+			transferBeginTime: func() *time.Time { t := time.Now().Add(-time.Minute); return &t }(),
+			expectErr:         true,
+			expectRequeue:     true,
+			expectPartition:   2,
+			expectAnnotation:  true,
+		},
+		{
+			name:              "upgrade the worker after the source is transferred",
+			synced:            true,
+			upgradeStage:      v1alpha1.DMWorkerStateFree,
+			transferBeginTime: func() *time.Time { t := time.Now().Add(-time.Minute); return &t }(),
+			expectPartition:   1,
+			expectAnnotation:  true,
+		},
+		{
+			name:              "upgrade the bound worker after the transfer timeout",
+			synced:            true,
+			upgradeStage:      v1alpha1.DMWorkerStateBound,
+			freeWorker:        true,
+			transferBeginTime: func() *time.Time { t := time.Now().Add(-time.Hour); return &t }(),
+			expectPartition:   1,
+			expectAnnotation:  true,
+			expectEvent:       "DMWorkerTransferSourceTimeout",
+		},
+		{
+			name:            "upgraded worker is offline",
+			synced:          true,
+			upgradeStage:    v1alpha1.DMWorkerStateFree,
+			upgradedOffline: true,
+			expectErr:       true,
+			expectRequeue:   true,
+			expectPartition: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			fakeDeps := controller.NewFakeDependencies()
+			upgrader := &workerUpgrader{deps: fakeDeps}
+			dc := newDMClusterForWorkerUpgrader()
+			dc.Status.Worker.Synced = tt.synced
+			if tt.upgradedOffline {
+				podName := DMWorkerPodName(upgradeTcName, 2)
+				dc.Status.Worker.Members[podName] = v1alpha1.WorkerMember{Name: podName, Stage: v1alpha1.DMWorkerStateOffline}
+			}
+
+			workers := []*dmapi.WorkersInfo{
+				{Name: DMWorkerPodName(upgradeTcName, 0), Stage: v1alpha1.DMWorkerStateBound, Source: "mysql-replica-00"},
+				{Name: upgradePodName, Stage: tt.upgradeStage},
+				{Name: DMWorkerPodName(upgradeTcName, 2), Stage: v1alpha1.DMWorkerStateBound, Source: "mysql-replica-02"},
+			}
+			if tt.upgradeStage == v1alpha1.DMWorkerStateBound {
+				workers[1].Source = "mysql-replica-01"
+			}
+			if tt.freeWorker {
+				workers = append(workers, &dmapi.WorkersInfo{Name: "dm-worker-external", Stage: v1alpha1.DMWorkerStateFree})
+			}
+			transferred := false
+			masterClient := controller.NewFakeMasterClient(fakeDeps.DMMasterControl.(*dmapi.FakeMasterControl), dc)
+			masterClient.AddReaction(dmapi.GetWorkersActionType, func(action *dmapi.Action) (interface{}, error) {
+				return workers, nil
+			})
+			masterClient.AddReaction(dmapi.TransferSourceActionType, func(action *dmapi.Action) (interface{}, error) {
+				transferred = true
+				g.Expect(action.Source).To(Equal("mysql-replica-01"))
+				g.Expect(action.Name).To(Equal("dm-worker-external"))
+				if tt.transferErr {
+					return nil, fmt.Errorf("failed to transfer source")
+				}
+				return nil, nil
+			})
+
+			podIndexer := fakeDeps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+			pods := getWorkerPods()
+			if tt.transferBeginTime != nil {
+				pods[1].Annotations = map[string]string{TransferSourceBeginTime: tt.transferBeginTime.Format(time.RFC3339)}
+			}
+			for _, pod := range pods {
+				g.Expect(podIndexer.Add(pod)).To(Succeed())
+			}
+
+			newSet := newStatefulSetForWorkerUpgrader()
+			oldSet := newSet.DeepCopy()
+			g.Expect(SetStatefulSetLastAppliedConfigAnnotation(oldSet)).To(Succeed())
+			newSet.Spec.UpdateStrategy.RollingUpdate.Partition = pointer.Int32Ptr(3)
+
+			err := upgrader.Upgrade(dc, oldSet, newSet)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(controller.IsRequeueError(err)).To(Equal(tt.expectRequeue))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(tt.expectPartition))
+			g.Expect(transferred).To(Equal(tt.expectTransfer))
+
+			pod, err := fakeDeps.PodLister.Pods(corev1.NamespaceDefault).Get(upgradePodName)
+			g.Expect(err).NotTo(HaveOccurred())
+			_, annotated := pod.Annotations[TransferSourceBeginTime]
+			g.Expect(annotated).To(Equal(tt.expectAnnotation))
+
+			events := collectEvents(fakeDeps.Recorder.(*record.FakeRecorder).Events)
+			if tt.expectEvent != "" {
+				g.Expect(events).To(ContainElement(ContainSubstring(tt.expectEvent)))
+			} else {
+				g.Expect(events).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func newStatefulSetForWorkerUpgrader() *apps.StatefulSet {
+	return &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controller.DMWorkerMemberName(upgradeTcName),
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas: pointer.Int32Ptr(3),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "dm-worker",
+							Image: "dm-test-image",
+						},
+					},
+				},
+			},
+			UpdateStrategy: apps.StatefulSetUpdateStrategy{
+				Type:          apps.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{Partition: pointer.Int32Ptr(2)},
+			},
+		},
+		Status: apps.StatefulSetStatus{
+			CurrentRevision: "1",
+			UpdateRevision:  "2",
+			ReadyReplicas:   3,
+			Replicas:        3,
+			CurrentReplicas: 2,
+			UpdatedReplicas: 1,
+		},
+	}
+}
+
+func newDMClusterForWorkerUpgrader() *v1alpha1.DMCluster {
+	podName0 := DMWorkerPodName(upgradeTcName, 0)
+	podName1 := DMWorkerPodName(upgradeTcName, 1)
+	podName2 := DMWorkerPodName(upgradeTcName, 2)
+	return &v1alpha1.DMCluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DMCluster",
+			APIVersion: "pingcap.com/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      upgradeTcName,
+			Namespace: corev1.NamespaceDefault,
+			UID:       types.UID(upgradeTcName),
+			Labels:    label.NewDM().Instance(upgradeInstanceName),
+		},
+		Spec: v1alpha1.DMClusterSpec{
+			Worker: &v1alpha1.WorkerSpec{
+				BaseImage:        "dm-test-image",
+				Replicas:         3,
+				StorageClassName: pointer.StringPtr("my-storage-class"),
+			},
+			Version: "v2.0.0-rc.2",
+		},
+		Status: v1alpha1.DMClusterStatus{
+			Worker: v1alpha1.WorkerStatus{
+				Phase: v1alpha1.NormalPhase,
+				StatefulSet: &apps.StatefulSetStatus{
+					CurrentRevision: "1",
+					UpdateRevision:  "2",
+					ReadyReplicas:   3,
+					Replicas:        3,
+					CurrentReplicas: 2,
+					UpdatedReplicas: 1,
+				},
+				Members: map[string]v1alpha1.WorkerMember{
+					podName0: {Name: podName0, Stage: v1alpha1.DMWorkerStateBound},
+					podName1: {Name: podName1, Stage: v1alpha1.DMWorkerStateBound},
+					podName2: {Name: podName2, Stage: v1alpha1.DMWorkerStateBound},
+				},
+			},
+		},
+	}
+}
+
+func getWorkerPods() []*corev1.Pod {
+	lc := label.NewDM().Instance(upgradeInstanceName).DMWorker().Labels()
+	lc[apps.ControllerRevisionHashLabelKey] = "1"
+	lu := label.NewDM().Instance(upgradeInstanceName).DMWorker().Labels()
+	lu[apps.ControllerRevisionHashLabelKey] = "2"
+	var pods []*corev1.Pod
+	for i, labels := range []map[string]string{lc, lc, lu} {
+		pods = append(pods, &corev1.Pod{
+			TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      DMWorkerPodName(upgradeTcName, int32(i)),
+				Namespace: corev1.NamespaceDefault,
+				Labels:    labels,
+			},
+		})
+	}
+	return pods
+}
@@ -13,7 +13,14 @@
 
 package member
 
-import "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	"k8s.io/klog"
+)
 
 // TODO: move this to a centralized place
 // Since the "Unhealthy" is a very universal event reason string, which could apply to all the TiDB/DM cluster components,
@@ -37,3 +44,96 @@ type DMFailover interface {
 	Recover(*v1alpha1.DMCluster)
 	RemoveUndesiredFailures(*v1alpha1.DMCluster)
 }
+
+// FailoverActionType is the type of the action taken by the failover
+type FailoverActionType string
+
+const (
+	// FailoverActionMarkFailure marks the unhealthy member as a failure member
+	FailoverActionMarkFailure FailoverActionType = "MarkFailure"
+	// FailoverActionDeleteMember deletes the failure member from the cluster
+	FailoverActionDeleteMember FailoverActionType = "DeleteMember"
+	// FailoverActionDeletePod deletes the Pod of the failure member
+	FailoverActionDeletePod FailoverActionType = "DeletePod"
+	// FailoverActionDeletePVC deletes a PVC of the failure member
+	FailoverActionDeletePVC FailoverActionType = "DeletePVC"
+	// FailoverActionPreservePod releases the Pod and PVCs of the failure member from the StatefulSet for inspection
+	FailoverActionPreservePod FailoverActionType = "PreservePod"
+)
+
+// FailoverAction is an action the failover would take
+type FailoverAction struct {
+	Type     FailoverActionType
+	PodName  string
+	MemberID string
+	// PVCName is only set for FailoverActionDeletePVC
+	PVCName string
+}
+
+// FailoverPlanner returns the actions the failover would take in the current round
+// without executing them, it's used to dry-run the failover.
+type FailoverPlanner interface {
+	PlanFailover(*v1alpha1.TidbCluster) ([]FailoverAction, error)
+}
+
+// checkClusterHealthFloor refuses the failover of the component if the healthy ratio of the PD members
+// and TiKV stores of the cluster is below CLIConfig.FailoverMinHealthyRatio. The failure members are
+// unhealthy already, failing over another component in a degraded cluster may compound the outage,
+// e.g. a TiKV store sharing a node with the unhealthy PD members.
+// The FailoverBlocked condition of the component is set and a RequeueError is returned if it's refused.
+func checkClusterHealthFloor(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, component v1alpha1.MemberType) error {
+	msg, below := clusterHealthBelowFloor(deps, tc)
+	if !below {
+		return nil
+	}
+
+	klog.Warningf("%s failover: TidbCluster %s/%s, %s, can't failover", component, tc.GetNamespace(), tc.GetName(), msg)
+	blocking := newBlockingConditionReconciler(deps.Recorder, v1alpha1.FailoverBlocked, component)
+	blocking.Block(tc, utiltidbcluster.ClusterHealthBelowFloor, msg)
+	return controller.RequeueErrorf("TidbCluster: %s/%s, %s, can't failover %s", tc.GetNamespace(), tc.GetName(), msg, component)
+}
+
+// clusterHealthBelowFloor returns whether the healthy ratio of the cluster is below CLIConfig.FailoverMinHealthyRatio
+// and the message describing it, it has no side effects.
+func clusterHealthBelowFloor(deps *controller.Dependencies, tc *v1alpha1.TidbCluster) (string, bool) {
+	floor := deps.CLIConfig.FailoverMinHealthyRatio
+	if floor <= 0 {
+		return "", false
+	}
+
+	healthy, total := clusterHealthyMembers(tc)
+	if total == 0 {
+		return "", false
+	}
+	ratio := float64(healthy) / float64(total)
+	if ratio >= floor {
+		return "", false
+	}
+	return fmt.Sprintf("healthy pd members and tikv stores %d / %d is below the floor %.2f", healthy, total, floor), true
+}
+
+// clusterHealthyMembers returns the count of the healthy and all PD members and TiKV stores, the peer
+// members and stores in the other Kubernetes clusters are counted as they serve the data too
+func clusterHealthyMembers(tc *v1alpha1.TidbCluster) (healthy int, total int) {
+	for _, member := range tc.Status.PD.Members {
+		total++
+		if member.Health {
+			healthy++
+		}
+	}
+	for _, member := range tc.Status.PD.PeerMembers {
+		total++
+		if member.Health {
+			healthy++
+		}
+	}
+	for _, stores := range []map[string]v1alpha1.TiKVStore{tc.Status.TiKV.Stores, tc.Status.TiKV.PeerStores} {
+		for _, store := range stores {
+			total++
+			if store.State == v1alpha1.TiKVStateUp {
+				healthy++
+			}
+		}
+	}
+	return healthy, total
+}
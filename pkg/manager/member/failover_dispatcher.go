@@ -0,0 +1,83 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/errors"
+)
+
+// failoverDispatcher dispatches the failover of a TidbCluster to the Failover of each component,
+// the components absent from the spec or paused are skipped.
+type failoverDispatcher struct {
+	failovers map[v1alpha1.MemberType]Failover
+}
+
+var _ Failover = &failoverDispatcher{}
+
+// NewFailoverDispatcher returns a Failover dispatching to the Failover of each component in failovers,
+// the components are failed over in the order of PD, TiKV, TiDB and TiFlash.
+func NewFailoverDispatcher(failovers map[v1alpha1.MemberType]Failover) Failover {
+	return &failoverDispatcher{failovers: failovers}
+}
+
+// Failover fails over each component, the failure of a component doesn't stop the failover of the others,
+// the errors are aggregated.
+func (d *failoverDispatcher) Failover(tc *v1alpha1.TidbCluster) error {
+	var errs []error
+	d.forEach(tc, func(failover Failover) {
+		if err := failover.Failover(tc); err != nil {
+			errs = append(errs, err)
+		}
+	})
+	return errors.NewAggregate(errs)
+}
+
+func (d *failoverDispatcher) Recover(tc *v1alpha1.TidbCluster) {
+	d.forEach(tc, func(failover Failover) {
+		failover.Recover(tc)
+	})
+}
+
+func (d *failoverDispatcher) RemoveUndesiredFailures(tc *v1alpha1.TidbCluster) {
+	d.forEach(tc, func(failover Failover) {
+		failover.RemoveUndesiredFailures(tc)
+	})
+}
+
+func (d *failoverDispatcher) forEach(tc *v1alpha1.TidbCluster, fn func(Failover)) {
+	for _, component := range failoverComponents {
+		failover, ok := d.failovers[component]
+		if !ok || !componentInSpec(tc, component) || tc.ShouldPauseReconcile(component) {
+			continue
+		}
+		fn(failover)
+	}
+}
+
+// componentInSpec returns whether the component is in the spec of the TidbCluster
+func componentInSpec(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType) bool {
+	switch component {
+	case v1alpha1.PDMemberType:
+		return tc.Spec.PD != nil
+	case v1alpha1.TiKVMemberType:
+		return tc.Spec.TiKV != nil
+	case v1alpha1.TiDBMemberType:
+		return tc.Spec.TiDB != nil
+	case v1alpha1.TiFlashMemberType:
+		return tc.Spec.TiFlash != nil
+	default:
+		return false
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// recordingFailover records the calls of the failover of a component
+type recordingFailover struct {
+	component v1alpha1.MemberType
+	calls     *[]string
+	err       error
+}
+
+func (f *recordingFailover) Failover(_ *v1alpha1.TidbCluster) error {
+	*f.calls = append(*f.calls, fmt.Sprintf("Failover %s", f.component))
+	return f.err
+}
+
+func (f *recordingFailover) Recover(_ *v1alpha1.TidbCluster) {
+	*f.calls = append(*f.calls, fmt.Sprintf("Recover %s", f.component))
+}
+
+func (f *recordingFailover) RemoveUndesiredFailures(_ *v1alpha1.TidbCluster) {
+	*f.calls = append(*f.calls, fmt.Sprintf("RemoveUndesiredFailures %s", f.component))
+}
+
+func TestFailoverDispatcher(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var calls []string
+	failovers := map[v1alpha1.MemberType]Failover{}
+	for _, component := range failoverComponents {
+		failovers[component] = &recordingFailover{component: component, calls: &calls}
+	}
+	dispatcher := NewFailoverDispatcher(failovers)
+
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: metav1.NamespaceDefault},
+		Spec: v1alpha1.TidbClusterSpec{
+			PD:      &v1alpha1.PDSpec{},
+			TiKV:    &v1alpha1.TiKVSpec{},
+			TiDB:    &v1alpha1.TiDBSpec{},
+			TiFlash: &v1alpha1.TiFlashSpec{},
+		},
+	}
+
+	// each component is failed over in order
+	g.Expect(dispatcher.Failover(tc)).To(Succeed())
+	dispatcher.Recover(tc)
+	dispatcher.RemoveUndesiredFailures(tc)
+	g.Expect(calls).To(Equal([]string{
+		"Failover pd", "Failover tikv", "Failover tidb", "Failover tiflash",
+		"Recover pd", "Recover tikv", "Recover tidb", "Recover tiflash",
+		"RemoveUndesiredFailures pd", "RemoveUndesiredFailures tikv", "RemoveUndesiredFailures tidb", "RemoveUndesiredFailures tiflash",
+	}))
+
+	// the components absent from the spec or paused are skipped
+	calls = nil
+	tc.Spec.TiFlash = nil
+	tc.Spec.TiKV.Paused = true
+	g.Expect(dispatcher.Failover(tc)).To(Succeed())
+	g.Expect(calls).To(Equal([]string{"Failover pd", "Failover tidb"}))
+
+	// the failure of a component doesn't stop the failover of the others
+	calls = nil
+	failovers[v1alpha1.PDMemberType].(*recordingFailover).err = fmt.Errorf("pd failover failed")
+	err := dispatcher.Failover(tc)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("pd failover failed"))
+	g.Expect(calls).To(Equal([]string{"Failover pd", "Failover tidb"}))
+}
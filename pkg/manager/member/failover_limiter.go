@@ -0,0 +1,242 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
+)
+
+// failoverComponents are the components of a tidb cluster whose failovers share the limit
+var failoverComponents = []v1alpha1.MemberType{
+	v1alpha1.PDMemberType,
+	v1alpha1.TiKVMemberType,
+	v1alpha1.TiDBMemberType,
+	v1alpha1.TiFlashMemberType,
+}
+
+// FailoverLimiter limits the number of failovers in flight at the same time in a namespace.
+// When a node dies, all the clusters having members on it fail over together, the limiter
+// keeps them from overwhelming the PD and Kubernetes APIs.
+//
+// A failover is in flight from the failure member or store being recorded in the status of
+// the TidbCluster until the member is deleted or recovers, which spans several syncs, so the
+// failovers in flight are counted from the status of the TidbClusters in the namespace. The
+// failovers being started by the concurrent syncs are counted too, as their failure members
+// are not in the status yet.
+type FailoverLimiter struct {
+	deps  *controller.Dependencies
+	limit int
+
+	lock     sync.Mutex
+	starting map[string]int
+}
+
+// NewFailoverLimiter returns a FailoverLimiter allowing --max-concurrent-failovers-per-namespace
+// failovers in flight in a namespace at the same time, a limit <= 0 means no limit.
+func NewFailoverLimiter(deps *controller.Dependencies) *FailoverLimiter {
+	return &FailoverLimiter{
+		deps:     deps,
+		limit:    deps.CLIConfig.MaxConcurrentFailoversPerNamespace,
+		starting: map[string]int{},
+	}
+}
+
+// TryAcquire acquires a slot for the failover of the component of the cluster without blocking,
+// the returned function releases the slot and must be called if the slot is acquired. The
+// component having failovers in flight always gets a slot to carry them on.
+func (l *FailoverLimiter) TryAcquire(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType) (release func(), ok bool, err error) {
+	if l == nil || l.limit <= 0 || inFlightFailovers(tc, component) > 0 {
+		return func() {}, true, nil
+	}
+
+	ns := tc.GetNamespace()
+	inFlight, err := l.inFlightFailovers(tc)
+	if err != nil {
+		return nil, false, err
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if inFlight+l.starting[ns] >= l.limit {
+		return nil, false, nil
+	}
+	l.starting[ns]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.lock.Lock()
+			defer l.lock.Unlock()
+			l.starting[ns]--
+			if l.starting[ns] <= 0 {
+				delete(l.starting, ns)
+			}
+		})
+	}, true, nil
+}
+
+// inFlightFailovers returns the number of the failovers in flight in the namespace of the cluster,
+// the latest status of the cluster is used instead of the one in the lister.
+func (l *FailoverLimiter) inFlightFailovers(tc *v1alpha1.TidbCluster) (int, error) {
+	ns := tc.GetNamespace()
+	tcs, err := l.deps.TiDBClusterLister.TidbClusters(ns).List(labels.Everything())
+	if err != nil {
+		return 0, fmt.Errorf("failed to list TidbClusters in namespace %s: %v", ns, err)
+	}
+
+	count := 0
+	for _, component := range failoverComponents {
+		count += inFlightFailovers(tc, component)
+	}
+	for _, other := range tcs {
+		if other.GetName() == tc.GetName() {
+			continue
+		}
+		for _, component := range failoverComponents {
+			count += inFlightFailovers(other, component)
+		}
+	}
+	return count, nil
+}
+
+// inFlightFailovers returns the number of the failovers of the component in progress. The failover of PD
+// is in progress until the failure member is deleted or recovers. The failovers of TiKV, TiDB and TiFlash
+// are done once the new members are up, though the failure members may stay unhealthy until they're
+// recovered, so only the failure members not replaced by the new members yet are counted.
+func inFlightFailovers(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType) int {
+	switch component {
+	case v1alpha1.PDMemberType:
+		count := 0
+		for _, failureMember := range tc.Status.PD.FailureMembers {
+			if failureMember.MemberDeleted {
+				continue
+			}
+			if member, ok := tc.Status.PD.Members[failureMember.PodName]; ok && member.Health {
+				continue
+			}
+			count++
+		}
+		return count
+	case v1alpha1.TiKVMemberType:
+		if tc.Spec.TiKV == nil {
+			return 0
+		}
+		return unreplacedFailures(failingStores(tc.Status.TiKV.Stores, tc.Status.TiKV.FailureStores),
+			upStores(tc.Status.TiKV.Stores), tc.Spec.TiKV.Replicas)
+	case v1alpha1.TiDBMemberType:
+		if tc.Spec.TiDB == nil {
+			return 0
+		}
+		failing, healthy := 0, 0
+		for _, failureMember := range tc.Status.TiDB.FailureMembers {
+			if member, ok := tc.Status.TiDB.Members[failureMember.PodName]; ok && !member.Health {
+				failing++
+			}
+		}
+		for _, member := range tc.Status.TiDB.Members {
+			if member.Health {
+				healthy++
+			}
+		}
+		return unreplacedFailures(failing, healthy, tc.Spec.TiDB.Replicas)
+	case v1alpha1.TiFlashMemberType:
+		if tc.Spec.TiFlash == nil {
+			return 0
+		}
+		return unreplacedFailures(failingStores(tc.Status.TiFlash.Stores, tc.Status.TiFlash.FailureStores),
+			upStores(tc.Status.TiFlash.Stores), tc.Spec.TiFlash.Replicas)
+	}
+	return 0
+}
+
+// failingStores returns the number of the failure stores which are not Up, the store turns
+// tombstone once it is deleted
+func failingStores(stores map[string]v1alpha1.TiKVStore, failureStores map[string]v1alpha1.TiKVFailureStore) int {
+	count := 0
+	for _, failureStore := range failureStores {
+		if store, ok := stores[failureStore.StoreID]; ok && store.State != v1alpha1.TiKVStateUp {
+			count++
+		}
+	}
+	return count
+}
+
+func upStores(stores map[string]v1alpha1.TiKVStore) int {
+	count := 0
+	for _, store := range stores {
+		if store.State == v1alpha1.TiKVStateUp {
+			count++
+		}
+	}
+	return count
+}
+
+// unreplacedFailures returns the number of the failure members not replaced yet, i.e. the healthy
+// members are still less than the replicas
+func unreplacedFailures(failing, healthy int, replicas int32) int {
+	missing := int(replicas) - healthy
+	if missing < 0 {
+		return 0
+	}
+	if missing < failing {
+		return missing
+	}
+	return failing
+}
+
+type limitedFailover struct {
+	failover  Failover
+	component v1alpha1.MemberType
+	limiter   *FailoverLimiter
+}
+
+var _ Failover = &limitedFailover{}
+
+// NewLimitedFailover wraps the Failover of the component so that it only proceeds when the
+// limiter has a free slot for the namespace of the cluster, otherwise a RequeueError is returned.
+func NewLimitedFailover(failover Failover, component v1alpha1.MemberType, limiter *FailoverLimiter) Failover {
+	return &limitedFailover{
+		failover:  failover,
+		component: component,
+		limiter:   limiter,
+	}
+}
+
+func (f *limitedFailover) Failover(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	release, ok, err := f.limiter.TryAcquire(tc, f.component)
+	if err != nil {
+		return fmt.Errorf("%s failover: TidbCluster %s/%s, %v", f.component, ns, tc.GetName(), err)
+	}
+	if !ok {
+		klog.Infof("%s failover: TidbCluster %s/%s, too many failovers in flight in the namespace, wait for the next round", f.component, ns, tc.GetName())
+		return controller.RequeueErrorf("TidbCluster: %s/%s, too many failovers in flight in the namespace, requeue %s failover", ns, tc.GetName(), f.component)
+	}
+	defer release()
+	return f.failover.Failover(tc)
+}
+
+func (f *limitedFailover) Recover(tc *v1alpha1.TidbCluster) {
+	f.failover.Recover(tc)
+}
+
+func (f *limitedFailover) RemoveUndesiredFailures(tc *v1alpha1.TidbCluster) {
+	f.failover.RemoveUndesiredFailures(tc)
+}
@@ -0,0 +1,228 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// blockingFailover blocks in Failover until unblock is closed
+type blockingFailover struct {
+	*fakePDFailover
+	entered chan struct{}
+	unblock chan struct{}
+}
+
+func (f *blockingFailover) Failover(_ *v1alpha1.TidbCluster) error {
+	f.entered <- struct{}{}
+	<-f.unblock
+	return nil
+}
+
+func TestLimitedFailover(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	limit := 2
+	total := 5
+	inner := &blockingFailover{
+		fakePDFailover: &fakePDFailover{},
+		entered:        make(chan struct{}, total+1),
+		unblock:        make(chan struct{}),
+	}
+	fakeDeps := controller.NewFakeDependencies()
+	fakeDeps.CLIConfig.MaxConcurrentFailoversPerNamespace = limit
+	limiter := NewFailoverLimiter(fakeDeps)
+	failover := NewLimitedFailover(inner, v1alpha1.PDMemberType, limiter)
+
+	newTC := func(ns string, i int) *v1alpha1.TidbCluster {
+		return &v1alpha1.TidbCluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: fmt.Sprintf("tc-%d", i)},
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, total)
+	for i := 0; i < limit; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- failover.Failover(newTC("ns", i))
+		}(i)
+	}
+	// wait for the failovers holding the slots
+	for i := 0; i < limit; i++ {
+		<-inner.entered
+	}
+
+	// the excess failovers in the namespace are requeued
+	for i := limit; i < total; i++ {
+		err := failover.Failover(newTC("ns", i))
+		g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+		g.Expect(err.Error()).To(ContainSubstring("too many failovers"))
+	}
+
+	// the other namespaces are not affected
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs <- failover.Failover(newTC("ns2", 0))
+	}()
+	<-inner.entered
+
+	close(inner.unblock)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+	g.Expect(limiter.starting).To(BeEmpty())
+
+	// the slots are released after the failovers are done
+	g.Expect(failover.Failover(newTC("ns", 0))).To(Succeed())
+	<-inner.entered
+
+	// no limit
+	fakeDeps.CLIConfig.MaxConcurrentFailoversPerNamespace = 0
+	release, ok, err := NewFailoverLimiter(fakeDeps).TryAcquire(newTC("ns", 0), v1alpha1.PDMemberType)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ok).To(BeTrue())
+	release()
+}
+
+// markingFailover records a failure member of an unhealthy PD member like the PD failover does
+type markingFailover struct {
+	*fakePDFailover
+	called int
+}
+
+func (f *markingFailover) Failover(tc *v1alpha1.TidbCluster) error {
+	f.called++
+	for name, member := range tc.Status.PD.Members {
+		if member.Health {
+			continue
+		}
+		if tc.Status.PD.FailureMembers == nil {
+			tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{}
+		}
+		if _, ok := tc.Status.PD.FailureMembers[name]; !ok {
+			tc.Status.PD.FailureMembers[name] = v1alpha1.PDFailureMember{PodName: name}
+		}
+	}
+	return nil
+}
+
+func TestLimitedFailoverInFlight(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fakeDeps := controller.NewFakeDependencies()
+	fakeDeps.CLIConfig.MaxConcurrentFailoversPerNamespace = 1
+	tcIndexer := fakeDeps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer()
+	limiter := NewFailoverLimiter(fakeDeps)
+	inner := &markingFailover{fakePDFailover: &fakePDFailover{}}
+	failover := NewLimitedFailover(inner, v1alpha1.PDMemberType, limiter)
+
+	newTC := func(name string) *v1alpha1.TidbCluster {
+		tc := &v1alpha1.TidbCluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+		}
+		tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+			name + "-pd-0": {Name: name + "-pd-0", Health: false},
+			name + "-pd-1": {Name: name + "-pd-1", Health: true},
+			name + "-pd-2": {Name: name + "-pd-2", Health: true},
+		}
+		g.Expect(tcIndexer.Add(tc)).To(Succeed())
+		return tc
+	}
+	tc1 := newTC("tc1")
+	tc2 := newTC("tc2")
+
+	// sync runs the failover of the cluster and writes back the status like the controller does
+	sync := func(tc *v1alpha1.TidbCluster) error {
+		err := failover.Failover(tc)
+		g.Expect(tcIndexer.Update(tc)).To(Succeed())
+		return err
+	}
+
+	// the failover of tc1 takes the only slot and keeps it in the following syncs
+	g.Expect(sync(tc1)).To(Succeed())
+	g.Expect(inFlightFailovers(tc1, v1alpha1.PDMemberType)).To(Equal(1))
+	for i := 0; i < 3; i++ {
+		err := sync(tc2)
+		g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+		g.Expect(sync(tc1)).To(Succeed())
+	}
+	g.Expect(inner.called).To(Equal(4))
+	g.Expect(tc2.Status.PD.FailureMembers).To(BeEmpty())
+
+	// the slot is freed once the failed member of tc1 is deleted
+	failureMember := tc1.Status.PD.FailureMembers["tc1-pd-0"]
+	failureMember.MemberDeleted = true
+	tc1.Status.PD.FailureMembers["tc1-pd-0"] = failureMember
+	g.Expect(tcIndexer.Update(tc1)).To(Succeed())
+	g.Expect(sync(tc2)).To(Succeed())
+	g.Expect(tc2.Status.PD.FailureMembers).To(HaveLen(1))
+
+	// the slot is freed once the failed member of tc2 recovers
+	g.Expect(controller.IsRequeueError(sync(tc1))).To(BeTrue())
+	tc3 := newTC("tc3")
+	g.Expect(controller.IsRequeueError(sync(tc3))).To(BeTrue())
+	member := tc2.Status.PD.Members["tc2-pd-0"]
+	member.Health = true
+	tc2.Status.PD.Members["tc2-pd-0"] = member
+	g.Expect(tcIndexer.Update(tc2)).To(Succeed())
+	g.Expect(sync(tc3)).To(Succeed())
+}
+
+func TestInFlightFailovers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{}
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"1": {ID: "1", State: v1alpha1.TiKVStateDown},
+		"2": {ID: "2", State: v1alpha1.TiKVStateUp},
+	}
+	tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+		"1": {StoreID: "1"},
+		"2": {StoreID: "2"},
+		// the store deleted
+		"3": {StoreID: "3"},
+	}
+	tc.Status.TiDB.Members = map[string]v1alpha1.TiDBMember{
+		"tidb-0": {Name: "tidb-0", Health: false},
+		"tidb-1": {Name: "tidb-1", Health: true},
+	}
+	tc.Status.TiDB.FailureMembers = map[string]v1alpha1.TiDBFailureMember{
+		"tidb-0": {PodName: "tidb-0"},
+		"tidb-1": {PodName: "tidb-1"},
+	}
+	tc.Spec.TiKV = &v1alpha1.TiKVSpec{Replicas: 2}
+	tc.Spec.TiDB = &v1alpha1.TiDBSpec{Replicas: 2}
+	g.Expect(inFlightFailovers(tc, v1alpha1.TiKVMemberType)).To(Equal(1))
+	g.Expect(inFlightFailovers(tc, v1alpha1.TiDBMemberType)).To(Equal(1))
+	g.Expect(inFlightFailovers(tc, v1alpha1.PDMemberType)).To(Equal(0))
+	g.Expect(inFlightFailovers(tc, v1alpha1.TiFlashMemberType)).To(Equal(0))
+
+	// the failovers are done once the new members are up, though the failure members stay unhealthy
+	tc.Status.TiKV.Stores["4"] = v1alpha1.TiKVStore{ID: "4", State: v1alpha1.TiKVStateUp}
+	tc.Status.TiDB.Members["tidb-2"] = v1alpha1.TiDBMember{Name: "tidb-2", Health: true}
+	g.Expect(inFlightFailovers(tc, v1alpha1.TiKVMemberType)).To(Equal(0))
+	g.Expect(inFlightFailovers(tc, v1alpha1.TiDBMemberType)).To(Equal(0))
+}
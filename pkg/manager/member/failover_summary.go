@@ -0,0 +1,126 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// FailoverSummary is the reason of the event summarizing the failovers of a tidb cluster
+	FailoverSummary = "FailoverSummary"
+)
+
+// summarizedFailoverComponents are the components whose failovers are summarized, in the order of the event message
+var summarizedFailoverComponents = []v1alpha1.MemberType{
+	v1alpha1.PDMemberType,
+	v1alpha1.TiKVMemberType,
+	v1alpha1.TiDBMemberType,
+	v1alpha1.TiFlashMemberType,
+}
+
+// FailoverSummarizer counts the failovers of a tidb cluster and emits a normal event
+// summarizing them once per interval, the progress is tracked in the status.
+type FailoverSummarizer interface {
+	Summarize(tc *v1alpha1.TidbCluster)
+}
+
+type failoverSummarizer struct {
+	deps *controller.Dependencies
+	now  func() time.Time
+}
+
+// NewFailoverSummarizer returns a FailoverSummarizer
+func NewFailoverSummarizer(deps *controller.Dependencies) FailoverSummarizer {
+	return &failoverSummarizer{
+		deps: deps,
+		now:  time.Now,
+	}
+}
+
+func (s *failoverSummarizer) Summarize(tc *v1alpha1.TidbCluster) {
+	interval := s.deps.CLIConfig.FailoverSummaryInterval
+	if interval <= 0 {
+		return
+	}
+
+	now := metav1.NewTime(s.now())
+	summary := tc.Status.FailoverSummary
+	if summary == nil {
+		// the failovers before the summary is enabled are not counted
+		tc.Status.FailoverSummary = &v1alpha1.FailoverSummaryStatus{
+			LastSummaryTime:  now,
+			LastFailoverTime: now,
+		}
+		return
+	}
+
+	// a failover is counted when its failure member or failure store is recorded,
+	// the ones recorded after the latest failover counted are new
+	lastFailoverTime := summary.LastFailoverTime
+	count := func(component v1alpha1.MemberType, createdAt metav1.Time) {
+		if !createdAt.After(summary.LastFailoverTime.Time) {
+			return
+		}
+		if summary.Counts == nil {
+			summary.Counts = map[v1alpha1.MemberType]int32{}
+		}
+		summary.Counts[component]++
+		if createdAt.After(lastFailoverTime.Time) {
+			lastFailoverTime = createdAt
+		}
+	}
+	for _, m := range tc.Status.PD.FailureMembers {
+		count(v1alpha1.PDMemberType, m.CreatedAt)
+	}
+	for _, store := range tc.Status.TiKV.FailureStores {
+		count(v1alpha1.TiKVMemberType, store.CreatedAt)
+	}
+	for _, m := range tc.Status.TiDB.FailureMembers {
+		count(v1alpha1.TiDBMemberType, m.CreatedAt)
+	}
+	for _, store := range tc.Status.TiFlash.FailureStores {
+		count(v1alpha1.TiFlashMemberType, store.CreatedAt)
+	}
+	summary.LastFailoverTime = lastFailoverTime
+
+	if now.Sub(summary.LastSummaryTime.Time) < interval {
+		return
+	}
+	counts := make([]string, 0, len(summarizedFailoverComponents))
+	for _, component := range summarizedFailoverComponents {
+		counts = append(counts, fmt.Sprintf("%s %d", component, summary.Counts[component]))
+	}
+	msg := fmt.Sprintf("failovers since %s: %s", summary.LastSummaryTime.UTC().Format(time.RFC3339), strings.Join(counts, ", "))
+	s.deps.Recorder.Event(tc, corev1.EventTypeNormal, FailoverSummary, msg)
+	summary.LastSummaryTime = now
+	summary.Counts = nil
+}
+
+type fakeFailoverSummarizer struct {
+}
+
+func (s *fakeFailoverSummarizer) Summarize(_ *v1alpha1.TidbCluster) {
+}
+
+func NewFakeFailoverSummarizer() FailoverSummarizer {
+	return &fakeFailoverSummarizer{}
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestFailoverSummarizer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	deps.CLIConfig.FailoverSummaryInterval = 24 * time.Hour
+	recorder := deps.Recorder.(*record.FakeRecorder)
+
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	summarizer := &failoverSummarizer{
+		deps: deps,
+		now:  func() time.Time { return now },
+	}
+	at := func(d time.Duration) metav1.Time {
+		return metav1.NewTime(now.Add(d))
+	}
+
+	tc := newTidbClusterForPD()
+	// the failovers before the summary is enabled are not counted
+	tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{
+		"test-pd-0": {PodName: "test-pd-0", CreatedAt: at(-time.Hour)},
+	}
+	summarizer.Summarize(tc)
+	g.Expect(tc.Status.FailoverSummary).NotTo(BeNil())
+	g.Expect(tc.Status.FailoverSummary.LastSummaryTime.Time).To(Equal(now))
+	g.Expect(collectEvents(recorder.Events)).To(BeEmpty())
+
+	now = now.Add(time.Hour)
+	tc.Status.PD.FailureMembers["test-pd-1"] = v1alpha1.PDFailureMember{PodName: "test-pd-1", CreatedAt: at(-time.Minute)}
+	tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+		"1": {PodName: "test-tikv-0", StoreID: "1", CreatedAt: at(-time.Minute)},
+		"2": {PodName: "test-tikv-1", StoreID: "2", CreatedAt: at(-time.Minute)},
+	}
+	summarizer.Summarize(tc)
+	g.Expect(tc.Status.FailoverSummary.Counts).To(Equal(map[v1alpha1.MemberType]int32{
+		v1alpha1.PDMemberType:   1,
+		v1alpha1.TiKVMemberType: 2,
+	}))
+	g.Expect(collectEvents(recorder.Events)).To(BeEmpty())
+
+	// the failovers counted are not counted again
+	now = now.Add(time.Hour)
+	tc.Status.TiDB.FailureMembers = map[string]v1alpha1.TiDBFailureMember{
+		"test-tidb-0": {PodName: "test-tidb-0", CreatedAt: at(-time.Minute)},
+	}
+	summarizer.Summarize(tc)
+	g.Expect(tc.Status.FailoverSummary.Counts).To(Equal(map[v1alpha1.MemberType]int32{
+		v1alpha1.PDMemberType:   1,
+		v1alpha1.TiKVMemberType: 2,
+		v1alpha1.TiDBMemberType: 1,
+	}))
+
+	// the summary is emitted after the interval
+	now = now.Add(23 * time.Hour)
+	summarizer.Summarize(tc)
+	events := collectEvents(recorder.Events)
+	g.Expect(events).To(HaveLen(1))
+	g.Expect(events[0]).To(Equal("Normal FailoverSummary failovers since 2021-06-01T00:00:00Z: pd 1, tikv 2, tidb 1, tiflash 0"))
+	g.Expect(tc.Status.FailoverSummary.LastSummaryTime.Time).To(Equal(now))
+	g.Expect(tc.Status.FailoverSummary.Counts).To(BeEmpty())
+
+	// the next summary only counts the new failovers
+	now = now.Add(24 * time.Hour)
+	tc.Status.TiFlash.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+		"3": {PodName: "test-tiflash-0", StoreID: "3", CreatedAt: at(-time.Hour)},
+	}
+	summarizer.Summarize(tc)
+	events = collectEvents(recorder.Events)
+	g.Expect(events).To(HaveLen(1))
+	g.Expect(events[0]).To(HaveSuffix("pd 0, tikv 0, tidb 0, tiflash 1"))
+
+	// disabled
+	deps.CLIConfig.FailoverSummaryInterval = 0
+	now = now.Add(48 * time.Hour)
+	summarizer.Summarize(tc)
+	g.Expect(collectEvents(recorder.Events)).To(BeEmpty())
+}
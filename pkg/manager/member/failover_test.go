@@ -0,0 +1,139 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+)
+
+func TestClusterHealthyMembers(t *testing.T) {
+	tests := []struct {
+		name          string
+		peerStores    map[string]v1alpha1.TiKVStore
+		expectHealthy int
+		expectTotal   int
+	}{
+		{
+			name:          "local members and stores",
+			expectHealthy: 3,
+			expectTotal:   5,
+		},
+		{
+			name: "peer stores in the other kubernetes clusters",
+			peerStores: map[string]v1alpha1.TiKVStore{
+				"11": {ID: "11", State: v1alpha1.TiKVStateUp, IP: "tikv-0.peer.svc.cluster2"},
+				"12": {ID: "12", State: v1alpha1.TiKVStateUp, IP: "tikv-1.peer.svc.cluster2"},
+				"13": {ID: "13", State: v1alpha1.TiKVStateDown, IP: "tikv-2.peer.svc.cluster2"},
+			},
+			expectHealthy: 5,
+			expectTotal:   8,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tc := newTidbClusterForPD()
+			tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+				"pd-0": {Name: "pd-0", ID: "0", Health: true},
+				"pd-1": {Name: "pd-1", ID: "1", Health: false},
+			}
+			tc.Status.PD.PeerMembers = map[string]v1alpha1.PDMember{
+				"peer-pd-0": {Name: "peer-pd-0", ID: "10", Health: true},
+			}
+			tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+				"1": {ID: "1", State: v1alpha1.TiKVStateUp, PodName: "tikv-0"},
+				"2": {ID: "2", State: v1alpha1.TiKVStateDown, PodName: "tikv-1"},
+			}
+			tc.Status.TiKV.PeerStores = tt.peerStores
+
+			healthy, total := clusterHealthyMembers(tc)
+			g.Expect(healthy).To(Equal(tt.expectHealthy))
+			g.Expect(total).To(Equal(tt.expectTotal))
+		})
+	}
+}
+
+func TestFailoverEventIntervalOfPDAndTiDB(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fakeDeps := controller.NewFakeDependencies()
+	fakeRecorder := fakeDeps.Recorder.(*record.FakeRecorder)
+	// the failover events are deduplicated by the recorder of the controller manager in the interval of the component
+	fakeDeps.Recorder = controller.NewDedupingRecorder(fakeRecorder, controller.DefaultEventDedupWindow)
+	pdFailover := &pdFailover{deps: fakeDeps}
+	tidbFailover := &tidbFailover{deps: fakeDeps}
+
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.FailoverEventInterval = pointer.StringPtr("1h")
+	tc.Spec.TiDB.FailoverEventInterval = pointer.StringPtr("100ms")
+	tc.Spec.TiDB.MaxFailoverCount = pointer.Int32Ptr(3)
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+		"test-pd-0": {Name: "test-pd-0", ID: "0", Health: false},
+		"test-pd-1": {Name: "test-pd-1", ID: "1", Health: true},
+		"test-pd-2": {Name: "test-pd-2", ID: "2", Health: true},
+	}
+	tc.Status.TiDB.Members = map[string]v1alpha1.TiDBMember{
+		"test-tidb-0": {Name: "test-tidb-0", Health: false, LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour))},
+	}
+	podIndexer := fakeDeps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+	g.Expect(podIndexer.Add(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-tidb-0", Namespace: tc.Namespace},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}},
+		},
+	})).To(Succeed())
+
+	// sync reports the unhealthy PD member and fails over the unhealthy TiDB member, which churns after the recovery
+	sync := func() (pdEvents []string, tidbEvents []string) {
+		pdFailover.reportUnhealthyMembers(tc)
+		tidbFailover.Recover(tc)
+		g.Expect(tidbFailover.Failover(tc)).To(Succeed())
+		for _, event := range collectEvents(fakeRecorder.Events) {
+			if strings.Contains(event, "test-pd-0") {
+				pdEvents = append(pdEvents, event)
+			}
+			if strings.Contains(event, "test-tidb-0") {
+				tidbEvents = append(tidbEvents, event)
+			}
+		}
+		return
+	}
+
+	pdEvents, tidbEvents := sync()
+	g.Expect(pdEvents).To(HaveLen(1))
+	g.Expect(tidbEvents).To(HaveLen(1))
+
+	pdEvents, tidbEvents = sync()
+	g.Expect(pdEvents).To(BeEmpty())
+	g.Expect(tidbEvents).To(BeEmpty())
+
+	// the interval of TiDB passes while the one of PD doesn't, the interval of TiDB overrides the
+	// longer default window of the recorder, and the suppressed events are counted in the next one
+	time.Sleep(200 * time.Millisecond)
+	pdEvents, tidbEvents = sync()
+	g.Expect(pdEvents).To(BeEmpty())
+	g.Expect(tidbEvents).To(HaveLen(1))
+	g.Expect(tidbEvents[0]).To(HaveSuffix("(x 1)"))
+}
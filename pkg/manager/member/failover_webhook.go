@@ -0,0 +1,80 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"k8s.io/klog"
+)
+
+// failoverWebhookTimeout is the timeout of posting a notification to the failover webhook
+const failoverWebhookTimeout = 5 * time.Second
+
+// FailoverNotification is the JSON payload posted to the failover webhook
+type FailoverNotification struct {
+	// Cluster is the namespace and name of the tidb cluster, e.g. default/basic
+	Cluster   string              `json:"cluster"`
+	Component v1alpha1.MemberType `json:"component"`
+	// Member is the name of the Pod of the member
+	Member    string             `json:"member"`
+	Action    FailoverActionType `json:"action"`
+	Timestamp time.Time          `json:"timestamp"`
+}
+
+// notifyFailoverWebhook posts the notification of the failover action on the member to CLIConfig.FailoverWebhookURL
+// if it's set. The notification is posted in the background and the failures are only logged, so an unreachable
+// webhook never blocks the failover.
+func notifyFailoverWebhook(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, podName string, action FailoverActionType) {
+	url := deps.CLIConfig.FailoverWebhookURL
+	if url == "" {
+		return
+	}
+	notification := FailoverNotification{
+		Cluster:   fmt.Sprintf("%s/%s", tc.GetNamespace(), tc.GetName()),
+		Component: memberType,
+		Member:    podName,
+		Action:    action,
+		Timestamp: time.Now(),
+	}
+	go func() {
+		if err := postFailoverNotification(url, notification); err != nil {
+			klog.Warningf("failed to notify the failover webhook of the %s action on %s member %s of tc %s, error: %v",
+				action, memberType, podName, notification.Cluster, err)
+		}
+	}()
+}
+
+func postFailoverNotification(url string, notification FailoverNotification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: failoverWebhookTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -34,6 +34,7 @@ const (
 	skipReasonOrphanPodsCleanerPodHasBeenScheduled = "orphan pods cleaner: pod has been scheduled"
 	skipReasonOrphanPodsCleanerPodIsNotFound       = "orphan pods cleaner: pod does not exist anymore"
 	skipReasonOrphanPodsCleanerPodRecreated        = "orphan pods cleaner: pod is recreated before deletion"
+	skipReasonOrphanPodsCleanerPaused              = "orphan pods cleaner: the reconciliation of the component is paused"
 )
 
 // OrphanPodsCleaner implements the logic for cleaning the orphan pods(has no pvc)
@@ -99,6 +100,11 @@ func (c *orphanPodsCleaner) Clean(meta metav1.Object) (map[string]string, error)
 			continue
 		}
 
+		if componentPaused(meta, l) {
+			skipReason[podName] = skipReasonOrphanPodsCleanerPaused
+			continue
+		}
+
 		if len(pod.Spec.NodeName) > 0 {
 			skipReason[podName] = skipReasonOrphanPodsCleanerPodHasBeenScheduled
 			continue
@@ -39,6 +39,7 @@ func TestOrphanPodsCleanerClean(t *testing.T) {
 		pvcs            []*corev1.PersistentVolumeClaim
 		deletePodFailed bool
 		testOnDM        bool
+		paused          bool
 		expectFn        func(*GomegaWithT, map[string]string, *orphanPodsCleaner, error)
 	}{
 		{
@@ -241,6 +242,42 @@ func TestOrphanPodsCleanerClean(t *testing.T) {
 				g.Expect(strings.Contains(err.Error(), "not found")).To(BeTrue())
 			},
 		},
+		{
+			name: "pvc is not found but pd is paused",
+			pods: []*corev1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod-1",
+						Namespace: metav1.NamespaceDefault,
+						Labels:    label.New().Instance(tc.GetInstanceName()).PD().Labels(),
+					},
+					Spec: corev1.PodSpec{
+						Volumes: []corev1.Volume{
+							{
+								Name: "pd",
+								VolumeSource: corev1.VolumeSource{
+									PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+										ClaimName: "pvc-1",
+									},
+								},
+							},
+						},
+					},
+					Status: corev1.PodStatus{
+						Phase: corev1.PodPending,
+					},
+				},
+			},
+			pvcs:   []*corev1.PersistentVolumeClaim{},
+			paused: true,
+			expectFn: func(g *GomegaWithT, skipReason map[string]string, opc *orphanPodsCleaner, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(len(skipReason)).To(Equal(1))
+				g.Expect(skipReason["pod-1"]).To(Equal(skipReasonOrphanPodsCleanerPaused))
+				_, err = opc.deps.PodLister.Pods("default").Get("pod-1")
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
 		{
 			name: "one of two pvcs is not found",
 			pods: []*corev1.Pod{
@@ -636,6 +673,10 @@ func TestOrphanPodsCleanerClean(t *testing.T) {
 
 			if tt.testOnDM {
 				skipReason, err = opc.Clean(dc)
+			} else if tt.paused {
+				pausedTC := tc.DeepCopy()
+				pausedTC.Spec.Paused = true
+				skipReason, err = opc.Clean(pausedTC)
 			} else {
 				skipReason, err = opc.Clean(tc)
 			}
@@ -0,0 +1,104 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+// usesExternalPD returns whether the components of the TidbCluster connect to the external PD
+// endpoints in spec.pdAddresses, i.e. the TidbCluster has no local PD.
+func usesExternalPD(tc *v1alpha1.TidbCluster) bool {
+	return tc.Spec.PD == nil && len(tc.Spec.PDAddresses) > 0
+}
+
+// syncExternalPDEndpoints probes the external PD endpoints of a TidbCluster without local PD, and records
+// their health in .Status.PD.PeerMembers keyed by the address, so that the PD client falls back to the
+// healthy ones. The health is kept out of the start scripts of the components, which list the addresses
+// in the order of spec.pdAddresses, so that the pods are not rolled when the health changes.
+// The ExternalPDUnreachable condition is set if none of the endpoints is reachable.
+func (m *pdMemberManager) syncExternalPDEndpoints(tc *v1alpha1.TidbCluster) {
+	if !usesExternalPD(tc) {
+		return
+	}
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	peerMembers := map[string]v1alpha1.PDMember{}
+	healthyCount := 0
+	for _, address := range tc.Spec.PDAddresses {
+		pdClient := m.deps.PDControl.GetPeerPDClient(pdapi.Namespace(ns), tcName, tc.IsTLSClusterEnabled(), address, address)
+		healthy, err := externalPDEndpointHealthy(pdClient, address)
+		if err != nil {
+			klog.Warningf("tc %s/%s: external pd endpoint %s is unreachable, err: %v", ns, tcName, address, err)
+		}
+		if healthy {
+			healthyCount++
+		}
+
+		member := v1alpha1.PDMember{
+			Name:               address,
+			ClientURL:          address,
+			Health:             healthy,
+			LastTransitionTime: metav1.Now(),
+		}
+		if old, exist := tc.Status.PD.PeerMembers[address]; exist && old.Health == healthy {
+			member.LastTransitionTime = old.LastTransitionTime
+		}
+		peerMembers[address] = member
+	}
+	tc.Status.PD.PeerMembers = peerMembers
+
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.ExternalPDUnreachable)
+	if healthyCount == 0 {
+		msg := fmt.Sprintf("none of the external pd endpoints %s is reachable", strings.Join(tc.Spec.PDAddresses, ","))
+		if cond == nil || cond.Status != corev1.ConditionTrue {
+			m.deps.Recorder.Event(tc, corev1.EventTypeWarning, utiltidbcluster.ExternalPDEndpointsUnreachable, msg)
+		}
+		newCond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.ExternalPDUnreachable, corev1.ConditionTrue, utiltidbcluster.ExternalPDEndpointsUnreachable, msg)
+		utiltidbcluster.SetTidbClusterCondition(&tc.Status, *newCond)
+		return
+	}
+	if cond != nil && cond.Status == corev1.ConditionTrue {
+		msg := fmt.Sprintf("%d of the external pd endpoints are reachable", healthyCount)
+		newCond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.ExternalPDUnreachable, corev1.ConditionFalse, utiltidbcluster.ExternalPDEndpointsReachable, msg)
+		utiltidbcluster.SetTidbClusterCondition(&tc.Status, *newCond)
+	}
+}
+
+// externalPDEndpointHealthy returns whether the external PD endpoint is reachable and the PD member serving
+// the address is healthy. The endpoint is healthy if it's reachable but no member serves the address,
+// e.g. the address is a load balancer in front of the PD cluster.
+func externalPDEndpointHealthy(pdClient pdapi.PDClient, address string) (bool, error) {
+	healthInfo, err := pdClient.GetHealth()
+	if err != nil {
+		return false, err
+	}
+	for _, member := range healthInfo.Healths {
+		for _, url := range member.ClientUrls {
+			if url == address {
+				return member.Health, nil
+			}
+		}
+	}
+	return true, nil
+}
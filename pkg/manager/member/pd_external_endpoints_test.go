@@ -0,0 +1,107 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestPDMemberManagerSyncExternalPDEndpoints(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	const (
+		pd0 = "http://pd0:2379"
+		pd1 = "http://pd1:2379"
+		pd2 = "http://pd2:2379"
+	)
+	healths := &pdapi.HealthInfo{Healths: []pdapi.MemberHealth{
+		{Name: "pd0", ClientUrls: []string{pd0}, Health: true},
+		{Name: "pd1", ClientUrls: []string{pd1}, Health: false},
+		{Name: "pd2", ClientUrls: []string{pd2}, Health: true},
+	}}
+	reachable := map[string]bool{pd0: true, pd1: true, pd2: false}
+
+	tc := newTidbClusterForPD()
+	tc.Spec.PD = nil
+	tc.Spec.PDAddresses = []string{pd2, pd1, pd0}
+	tc.Spec.TiKV.Config = v1alpha1.NewTiKVConfig()
+	tc.Spec.TiDB.Config = v1alpha1.NewTiDBConfig()
+
+	pmm, _, _ := newFakePDMemberManager()
+	recorder := pmm.deps.Recorder.(*record.FakeRecorder)
+	pdControl := pmm.deps.PDControl.(*pdapi.FakePDControl)
+	for _, address := range tc.Spec.PDAddresses {
+		address := address
+		pdClient := controller.NewFakePDClientWithAddress(pdControl, address)
+		pdClient.AddReaction(pdapi.GetHealthActionType, func(action *pdapi.Action) (interface{}, error) {
+			if !reachable[address] {
+				return nil, fmt.Errorf("%s is unreachable", address)
+			}
+			return healths, nil
+		})
+	}
+
+	// pd0 is healthy, pd1 is reachable but unhealthy, and pd2 is unreachable
+	g.Expect(pmm.Sync(tc)).To(Succeed())
+	g.Expect(tc.Status.PD.PeerMembers).To(HaveLen(3))
+	g.Expect(tc.Status.PD.PeerMembers[pd0].Health).To(BeTrue())
+	g.Expect(tc.Status.PD.PeerMembers[pd0].ClientURL).To(Equal(pd0))
+	g.Expect(tc.Status.PD.PeerMembers[pd1].Health).To(BeFalse())
+	g.Expect(tc.Status.PD.PeerMembers[pd2].Health).To(BeFalse())
+	g.Expect(utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.ExternalPDUnreachable)).To(BeNil())
+	g.Expect(collectEvents(recorder.Events)).To(BeEmpty())
+
+	// the start scripts list the addresses in the order of spec.pdAddresses regardless of the health
+	tikvCm, err := getTikVConfigMap(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tikvCm.Data["startup-script"]).To(ContainSubstring(fmt.Sprintf("--pd=%s,%s,%s", pd2, pd1, pd0)))
+	tidbCm, err := getTiDBConfigMap(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tidbCm.Data["startup-script"]).To(ContainSubstring("--path=pd2:2379,pd1:2379,pd0:2379"))
+
+	// all the endpoints are unreachable
+	reachable[pd0], reachable[pd1] = false, false
+	g.Expect(pmm.Sync(tc)).To(Succeed())
+	for _, member := range tc.Status.PD.PeerMembers {
+		g.Expect(member.Health).To(BeFalse())
+	}
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.ExternalPDUnreachable)
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(cond.Reason).To(Equal(utiltidbcluster.ExternalPDEndpointsUnreachable))
+	events := collectEvents(recorder.Events)
+	g.Expect(events).To(HaveLen(1))
+	g.Expect(events[0]).To(ContainSubstring(utiltidbcluster.ExternalPDEndpointsUnreachable))
+
+	// the event is not recorded again while the endpoints keep unreachable
+	g.Expect(pmm.Sync(tc)).To(Succeed())
+	g.Expect(collectEvents(recorder.Events)).To(BeEmpty())
+
+	// pd0 recovers
+	reachable[pd0] = true
+	g.Expect(pmm.Sync(tc)).To(Succeed())
+	g.Expect(tc.Status.PD.PeerMembers[pd0].Health).To(BeTrue())
+	cond = utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.ExternalPDUnreachable)
+	g.Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(cond.Reason).To(Equal(utiltidbcluster.ExternalPDEndpointsReachable))
+}
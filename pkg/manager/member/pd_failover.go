@@ -14,25 +14,34 @@
 package member
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/util"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog"
+	"k8s.io/kubernetes/pkg/util/slice"
 )
 
 type pdFailover struct {
 	deps *controller.Dependencies
 }
 
+var _ Failover = &pdFailover{}
+
 // NewPDFailover returns a pd Failover
 func NewPDFailover(deps *controller.Dependencies) Failover {
 	return &pdFailover{
@@ -51,19 +60,25 @@ func (f *pdFailover) Failover(tc *v1alpha1.TidbCluster) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 
-	if !tc.Status.PD.Synced {
-		return fmt.Errorf("TidbCluster: %s/%s .Status.PD.Synced = false, can't failover", ns, tcName)
-	}
-	if tc.Status.PD.FailureMembers == nil {
-		tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{}
+	if tc.Status.PD.Synced {
+		if tc.Status.PD.FailureMembers == nil {
+			tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{}
+		}
+		f.pruneRecoveredMembers(tc)
+		f.reportUnhealthyMembers(tc)
+	} else {
+		f.checkPDStatusSyncStale(tc)
 	}
 
-	inQuorum, healthCount := f.isPDInQuorum(tc)
-	if !inQuorum {
-		return fmt.Errorf("TidbCluster: %s/%s's pd cluster is not healthy, healthy %d / desired %d,"+
-			" replicas %d, failureCount %d, can't failover",
-			ns, tcName, healthCount, tc.PDStsDesiredReplicas(), tc.Spec.PD.Replicas, len(tc.Status.PD.FailureMembers))
+	blocking := newBlockingConditionReconciler(f.deps.Recorder, v1alpha1.FailoverBlocked, v1alpha1.PDMemberType)
+	if block := f.failoverBlock(tc); block != nil {
+		if block.reason != "" {
+			klog.Warningf("pd failover: TidbCluster %s/%s, %s, can't failover", ns, tcName, block.message)
+			blocking.Block(tc, block.reason, block.message)
+		}
+		return block.err
 	}
+	blocking.Resolve(tc)
 
 	pdDeletedFailureReplicas := tc.GetPDDeletedFailureReplicas()
 	if pdDeletedFailureReplicas >= *tc.Spec.PD.MaxFailoverCount {
@@ -81,99 +96,626 @@ func (f *pdFailover) Failover(tc *v1alpha1.TidbCluster) error {
 	return f.tryToDeleteAFailureMember(tc)
 }
 
+// pdFailoverBlock describes why the failover of the pd cluster is blocked in the current round
+type pdFailoverBlock struct {
+	// reason is the reason of the FailoverBlocked condition, the condition is left unchanged if it's empty
+	reason  string
+	message string
+	err     error
+}
+
+// failoverBlock returns why the failover can't take any action in the current round, nil if it can.
+// It has no side effects, so the gates are shared by Failover and PlanFailover.
+func (f *pdFailover) failoverBlock(tc *v1alpha1.TidbCluster) *pdFailoverBlock {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if !tc.Status.PD.Synced {
+		return &pdFailoverBlock{err: fmt.Errorf("TidbCluster: %s/%s .Status.PD.Synced = false, can't failover", ns, tcName)}
+	}
+	if inQuorum, healthCount := pdInQuorum(tc); !inQuorum {
+		return &pdFailoverBlock{
+			reason:  utiltidbcluster.PDQuorumLost,
+			message: fmt.Sprintf("pd cluster is not healthy, healthy %d / desired %d", healthCount, tc.PDStsDesiredReplicas()),
+			err: fmt.Errorf("TidbCluster: %s/%s's pd cluster is not healthy, healthy %d / desired %d,"+
+				" replicas %d, failureCount %d, can't failover",
+				ns, tcName, healthCount, tc.PDStsDesiredReplicas(), tc.Spec.PD.Replicas, len(tc.Status.PD.FailureMembers)),
+		}
+	}
+	// the members are keyed by name, the failover may delete the wrong member if the member ids are duplicated,
+	// e.g. in a split brain, so it's refused until the duplication is resolved
+	if msg := duplicatedPDMemberIDs(tc); msg != "" {
+		return &pdFailoverBlock{
+			reason:  utiltidbcluster.PDMemberIDDuplicated,
+			message: msg,
+			err:     controller.RequeueErrorf("TidbCluster: %s/%s, %s, can't failover", ns, tcName, msg),
+		}
+	}
+	// the floor only refuses to mark a new failure member, the failure member marked already is still deleted
+	// so that it's replaced
+	if _, failureMember := notDeletedFailureMember(tc); failureMember == nil {
+		if msg, below := clusterHealthBelowFloor(f.deps, tc); below {
+			return &pdFailoverBlock{
+				reason:  utiltidbcluster.ClusterHealthBelowFloor,
+				message: msg,
+				err:     controller.RequeueErrorf("TidbCluster: %s/%s, %s, can't failover %s", ns, tcName, msg, v1alpha1.PDMemberType),
+			}
+		}
+	}
+	return nil
+}
+
+// PlanFailover returns the actions Failover would take in the current round without executing them,
+// neither the status of tc nor any resources are changed, so it can be used to dry-run the failover.
+// The FailoverPreDeleteHook is not called because it may have side effects, the plan is the one
+// taken if the hook allows the deletion.
+func (f *pdFailover) PlanFailover(tc *v1alpha1.TidbCluster) ([]FailoverAction, error) {
+	ns := tc.GetNamespace()
+
+	if block := f.failoverBlock(tc); block != nil {
+		return nil, block.err
+	}
+	if tc.GetPDDeletedFailureReplicas() >= *tc.Spec.PD.MaxFailoverCount {
+		return nil, nil
+	}
+
+	failurePDName, failureMember := notDeletedFailureMember(tc)
+	if failureMember == nil {
+		if podName, triggered := tc.PDFailoverTriggered(); triggered {
+			pdName, reason := f.triggeredPDMember(tc, podName)
+			if reason != "" {
+				return nil, nil
+			}
+			return []FailoverAction{{
+				Type:     FailoverActionMarkFailure,
+				PodName:  podName,
+				MemberID: tc.Status.PD.Members[pdName].ID,
+			}}, nil
+		}
+		pdName, ok := f.nextFailoverCandidate(tc)
+		if !ok {
+			return nil, nil
+		}
+		if _, notReady := f.nodeNotReadyDeadline(ns, strings.Split(pdName, ".")[0]); notReady {
+			return nil, nil
+		}
+		return []FailoverAction{{
+			Type:     FailoverActionMarkFailure,
+			PodName:  strings.Split(pdName, ".")[0],
+			MemberID: tc.Status.PD.Members[pdName].ID,
+		}}, nil
+	}
+
+	if tc.PDMemberProtected(failureMember.MemberID) {
+		return nil, nil
+	}
+	failurePodName := strings.Split(failurePDName, ".")[0]
+	pod, err := f.failurePod(tc, failureMember, failurePodName)
+	if err != nil {
+		return nil, err
+	}
+	deletePod := FailoverAction{
+		Type:     FailoverActionDeletePod,
+		PodName:  failurePodName,
+		MemberID: failureMember.MemberID,
+	}
+
+	if tc.PDFailoverMode() == v1alpha1.FailoverModePodOnly {
+		if pod == nil || pod.DeletionTimestamp != nil {
+			return nil, nil
+		}
+		return []FailoverAction{deletePod}, nil
+	}
+
+	actions := []FailoverAction{{
+		Type:     FailoverActionDeleteMember,
+		PodName:  failurePodName,
+		MemberID: failureMember.MemberID,
+	}}
+	if pod != nil && f.shouldPreservePod(tc) {
+		return append(actions, FailoverAction{
+			Type:     FailoverActionPreservePod,
+			PodName:  failurePodName,
+			MemberID: failureMember.MemberID,
+		}), nil
+	}
+	if pod != nil && pod.DeletionTimestamp == nil {
+		actions = append(actions, deletePod)
+	}
+	pvcs, err := f.failureMemberPVCs(tc, failureMember, failurePodName)
+	if err != nil {
+		return nil, err
+	}
+	if pod != nil && hasReadWriteOncePodPVC(pvcs) {
+		// the PVCs are deleted in a later round after the pod is gone
+		return actions, nil
+	}
+	for _, pvc := range pvcs {
+		actions = append(actions, FailoverAction{
+			Type:     FailoverActionDeletePVC,
+			PodName:  failurePodName,
+			MemberID: failureMember.MemberID,
+			PVCName:  pvc.Name,
+		})
+	}
+	return actions, nil
+}
+
+// checkPDStatusSyncStale reports the PD status which has not been synced for longer than the threshold,
+// the PD cluster may be down and need a manual intervention, but the failover is still refused because
+// the status it depends on is outdated.
+func (f *pdFailover) checkPDStatusSyncStale(tc *v1alpha1.TidbCluster) {
+	threshold := f.deps.CLIConfig.PDStatusSyncStaleThreshold
+	unsyncedSince := tc.Status.PD.UnsyncedSince
+	if threshold <= 0 || unsyncedSince == nil {
+		return
+	}
+	if time.Since(unsyncedSince.Time) < threshold {
+		return
+	}
+
+	msg := fmt.Sprintf("pd status has not been synced since %s, longer than %s, the pd cluster may be down and need a manual intervention",
+		unsyncedSince.Format(time.RFC3339), threshold)
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, controller.ConditionTypeFor(v1alpha1.FailoverBlocked, v1alpha1.PDMemberType))
+	if cond == nil || cond.Status != apiv1.ConditionTrue || cond.Reason != utiltidbcluster.PDStatusSyncStale {
+		klog.Warningf("pd failover: TidbCluster %s/%s, %s", tc.GetNamespace(), tc.GetName(), msg)
+		f.deps.Recorder.Event(tc, apiv1.EventTypeWarning, utiltidbcluster.PDStatusSyncStale, msg)
+	}
+	blocking := newBlockingConditionReconciler(f.deps.Recorder, v1alpha1.FailoverBlocked, v1alpha1.PDMemberType)
+	blocking.Block(tc, utiltidbcluster.PDStatusSyncStale, msg)
+}
+
 func (f *pdFailover) Recover(tc *v1alpha1.TidbCluster) {
+	// the recovered members may flap again right after the recovery, record them to suppress their failover for a while
+	if f.deps.CLIConfig.PDFailoverRecoveryCooldown > 0 && len(tc.Status.PD.FailureMembers) > 0 {
+		if tc.Status.PD.RecoveredMembers == nil {
+			tc.Status.PD.RecoveredMembers = map[string]metav1.Time{}
+		}
+		now := metav1.Now()
+		for pdName := range tc.Status.PD.FailureMembers {
+			tc.Status.PD.RecoveredMembers[pdName] = now
+		}
+	}
+	for pdName, failureMember := range tc.Status.PD.FailureMembers {
+		// the data of a member deleted from the pd cluster must not be reused
+		if failureMember.MemberDeleted {
+			continue
+		}
+		f.rebindFailoverOriginPVs(tc, strings.Split(pdName, ".")[0])
+	}
 	tc.Status.PD.FailureMembers = nil
 	klog.Infof("pd failover: clearing pd failoverMembers, %s/%s", tc.GetNamespace(), tc.GetName())
 }
 
-func (f *pdFailover) tryToMarkAPeerAsFailure(tc *v1alpha1.TidbCluster) error {
+// rebindFailoverOriginPVs rebinds the retained PVs of the failure pod to the PVCs of the pod, so that the
+// PVCs created for the pod next time bind to the original disks rather than newly provisioned ones.
+// The PVs are only rebound if they are released and no PVC of the name exists, the member must
+// not have been deleted from the pd cluster.
+func (f *pdFailover) rebindFailoverOriginPVs(tc *v1alpha1.TidbCluster, podName string) {
+	if !f.deps.CLIConfig.HasPVPermission() {
+		return
+	}
 	ns := tc.GetNamespace()
-
-	for pdName, pdMember := range tc.Status.PD.Members {
-		if pdMember.LastTransitionTime.IsZero() {
+	selector, err := label.New().Instance(tc.GetInstanceName()).PD().Namespace(ns).Selector()
+	if err != nil {
+		klog.Errorf("pd failover[Recover]: failed to get PV selector for tc %s/%s, error: %v", ns, tc.GetName(), err)
+		return
+	}
+	pvs, err := f.deps.PVControl.FindOrphanedPVs(selector)
+	if err != nil {
+		klog.Errorf("pd failover[Recover]: failed to find the orphaned PVs of tc %s/%s, error: %v", ns, tc.GetName(), err)
+		return
+	}
+	for _, pv := range pvs {
+		ref := pv.Spec.ClaimRef
+		if pv.Annotations[label.AnnFailoverOriginKey] != podName || ref.Namespace != ns || ref.UID == "" {
 			continue
 		}
-		podName := strings.Split(pdName, ".")[0]
-		if !f.isPodDesired(tc, podName) {
+		// the PVC is recreated and bound to another PV already
+		if _, err := f.deps.PVCLister.PersistentVolumeClaims(ns).Get(ref.Name); !errors.IsNotFound(err) {
+			klog.Infof("pd failover[Recover]: PVC %s/%s of PV %s exists or can't be got, skip rebinding, error: %v", ns, ref.Name, pv.Name, err)
 			continue
 		}
-
-		if tc.Status.PD.FailureMembers == nil {
-			tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{}
+		// clearing the UID of the claimRef reserves the PV for the next PVC with the name
+		if err := f.deps.PVControl.PatchPVClaimRef(tc, pv.DeepCopy(), ref.Name); err != nil {
+			klog.Errorf("pd failover[Recover]: failed to rebind PV %s to PVC %s/%s, error: %v", pv.Name, ns, ref.Name, err)
+			continue
 		}
-		failoverDeadline := pdMember.LastTransitionTime.Add(f.deps.CLIConfig.PDFailoverPeriod)
-		_, exist := tc.Status.PD.FailureMembers[pdName]
+		klog.Infof("pd failover[Recover]: rebind PV %s of failure pod %s/%s to PVC %s/%s", pv.Name, ns, podName, ns, ref.Name)
+	}
+}
 
-		if pdMember.Health || time.Now().Before(failoverDeadline) || exist {
+// stampFailoverOrigin annotates the retained PVs of the PVCs of the failure pod with the name of the pod
+// before the pod is deleted in the PodOnly failover mode, so that the PVs can be rebound to the pod on
+// recovery if the PVCs are lost meanwhile. The PVs which are deleted with their PVCs are skipped.
+func (f *pdFailover) stampFailoverOrigin(tc *v1alpha1.TidbCluster, podName string, pvcs []*apiv1.PersistentVolumeClaim) error {
+	if !f.deps.CLIConfig.HasPVPermission() {
+		return nil
+	}
+	for _, pvc := range pvcs {
+		if pvc.Spec.VolumeName == "" {
+			continue
+		}
+		pv, err := f.deps.PVControl.GetPV(pvc.Spec.VolumeName)
+		if errors.IsNotFound(err) {
 			continue
 		}
-
-		pod, err := f.deps.PodLister.Pods(ns).Get(podName)
 		if err != nil {
-			return fmt.Errorf("tryToMarkAPeerAsFailure: failed to get pod %s/%s, error: %s", ns, podName, err)
+			return fmt.Errorf("pd failover: failed to get PV %s of PVC %s/%s, error: %v", pvc.Spec.VolumeName, pvc.Namespace, pvc.Name, err)
 		}
+		if pv.Spec.PersistentVolumeReclaimPolicy != apiv1.PersistentVolumeReclaimRetain || pv.Annotations[label.AnnFailoverOriginKey] == podName {
+			continue
+		}
+		newPV := pv.DeepCopy()
+		if newPV.Annotations == nil {
+			newPV.Annotations = map[string]string{}
+		}
+		newPV.Annotations[label.AnnFailoverOriginKey] = podName
+		if _, err := f.deps.PVControl.UpdatePV(tc, newPV); err != nil {
+			return fmt.Errorf("pd failover: failed to annotate PV %s with failure pod %s/%s, error: %v", pv.Name, pvc.Namespace, podName, err)
+		}
+	}
+	return nil
+}
 
-		pvcs, err := util.ResolvePVCFromPod(pod, f.deps.PVCLister)
-		if err != nil {
-			return fmt.Errorf("tryToMarkAPeerAsFailure: failed to get pvcs for pod %s/%s, error: %s", ns, pod.Name, err)
+// failoverCooldownDeadline returns the end of the cooldown after the recovery of the member,
+// and whether the member is still in the cooldown
+func (f *pdFailover) failoverCooldownDeadline(tc *v1alpha1.TidbCluster, pdName string) (time.Time, bool) {
+	recoveredAt, ok := tc.Status.PD.RecoveredMembers[pdName]
+	if !ok {
+		return time.Time{}, false
+	}
+	deadline := recoveredAt.Add(f.deps.CLIConfig.PDFailoverRecoveryCooldown)
+	return deadline, time.Now().Before(deadline)
+}
+
+// pruneRecoveredMembers removes the recovered members whose cooldown has passed
+func (f *pdFailover) pruneRecoveredMembers(tc *v1alpha1.TidbCluster) {
+	for pdName := range tc.Status.PD.RecoveredMembers {
+		if _, inCooldown := f.failoverCooldownDeadline(tc, pdName); !inCooldown {
+			delete(tc.Status.PD.RecoveredMembers, pdName)
 		}
+	}
+	if len(tc.Status.PD.RecoveredMembers) == 0 {
+		tc.Status.PD.RecoveredMembers = nil
+	}
+}
+
+func (f *pdFailover) tryToMarkAPeerAsFailure(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
 
-		f.deps.Recorder.Eventf(tc, apiv1.EventTypeWarning, "PDMemberUnhealthy", "%s/%s(%s) is unhealthy", ns, podName, pdMember.ID)
+	if podName, triggered := tc.PDFailoverTriggered(); triggered {
+		return f.tryToMarkTriggeredPeerAsFailure(tc, podName)
+	}
 
-		// mark a peer member failed and return an error to skip reconciliation
-		// note that status of tidb cluster will be updated always
-		pvcUIDSet := make(map[types.UID]struct{})
-		for _, pvc := range pvcs {
-			pvcUIDSet[pvc.UID] = struct{}{}
+	pdName, ok := f.nextFailoverCandidate(tc)
+	// report the unhealthy members skipped before the candidate
+	for _, name := range f.unhealthyPDMembers(tc) {
+		if ok && name == pdName {
+			break
 		}
-		tc.Status.PD.FailureMembers[pdName] = v1alpha1.PDFailureMember{
-			PodName:       podName,
-			MemberID:      pdMember.ID,
-			PVCUIDSet:     pvcUIDSet,
-			MemberDeleted: false,
-			CreatedAt:     metav1.Now(),
+		if ineligible := f.failoverIneligibility(tc, name); ineligible != nil && ineligible.reason != "" {
+			klog.Infof("pd failover: %s", ineligible.message)
+			controller.RecordEventInWindow(f.deps.Recorder, tc, tc.FailoverEventInterval(v1alpha1.PDMemberType), ineligible.eventType, ineligible.reason.For(v1alpha1.PDMemberType), ineligible.message)
 		}
-		return controller.RequeueErrorf("marking Pod: %s/%s pd member: %s as failure", ns, podName, pdMember.Name)
+	}
+	if !ok {
+		return nil
 	}
 
+	pdMember := tc.Status.PD.Members[pdName]
+	podName := strings.Split(pdName, ".")[0]
+	if deadline, notReady := f.nodeNotReadyDeadline(ns, podName); notReady {
+		klog.Infof("pd failover: the node of pd member %s/%s is NotReady, failover is deferred until %s", ns, podName, deadline.Format(time.RFC3339))
+		controller.RecordEventInWindow(f.deps.Recorder, tc, tc.FailoverEventInterval(v1alpha1.PDMemberType), apiv1.EventTypeWarning, controller.MemberNodeNotReady.For(v1alpha1.PDMemberType),
+			fmt.Sprintf("%s/%s(%s) is unhealthy, but its node is NotReady, failover is deferred until %s", ns, podName, pdMember.ID, deadline.Format(time.RFC3339)))
+		return controller.RequeueErrorf("pd failover: the node of pd member %s/%s is NotReady, failover is deferred until %s", ns, podName, deadline.Format(time.RFC3339))
+	}
+
+	return f.markPeerAsFailure(tc, pdName, false)
+}
+
+// nextFailoverCandidate returns the name of the unhealthy member which is marked as failure next, i.e. the
+// eligible one of the lowest ordinal, false is returned if no member is eligible for failover. The failover
+// of the candidate may still be deferred, e.g. while its node is NotReady.
+func (f *pdFailover) nextFailoverCandidate(tc *v1alpha1.TidbCluster) (string, bool) {
+	for _, pdName := range f.unhealthyPDMembers(tc) {
+		if f.failoverIneligibility(tc, pdName) == nil {
+			return pdName, true
+		}
+	}
+	return "", false
+}
+
+// pdFailoverIneligibility describes why an unhealthy member is not eligible for failover, the reason
+// is empty if the member is skipped silently
+type pdFailoverIneligibility struct {
+	eventType string
+	reason    controller.EventReason
+	message   string
+}
+
+// failoverIneligibility returns why the unhealthy member is not eligible for failover, nil if it's eligible
+func (f *pdFailover) failoverIneligibility(tc *v1alpha1.TidbCluster, pdName string) *pdFailoverIneligibility {
+	ns := tc.GetNamespace()
+	pdMember := tc.Status.PD.Members[pdName]
+	podName := strings.Split(pdName, ".")[0]
+
+	if _, exist := tc.Status.PD.FailureMembers[pdName]; exist {
+		return &pdFailoverIneligibility{}
+	}
+	if deadline, inCooldown := f.failoverCooldownDeadline(tc, pdName); inCooldown {
+		return &pdFailoverIneligibility{
+			eventType: apiv1.EventTypeNormal,
+			reason:    controller.FailoverCooldown,
+			message:   fmt.Sprintf("%s/%s(%s) is unhealthy, but failover is in cooldown until %s after the recovery", ns, podName, pdMember.ID, deadline.Format(time.RFC3339)),
+		}
+	}
+	// the member ID is used to delete the member from the pd cluster later,
+	// skip the member if the ID is malformed rather than failing at deletion
+	if _, err := strconv.ParseUint(pdMember.ID, 10, 64); err != nil {
+		return &pdFailoverIneligibility{
+			eventType: apiv1.EventTypeWarning,
+			reason:    controller.MemberIDInvalid,
+			message:   fmt.Sprintf("%s/%s has an invalid member id %q, skip failover", ns, podName, pdMember.ID),
+		}
+	}
+	if tc.PDMemberProtected(pdMember.ID) {
+		return &pdFailoverIneligibility{
+			eventType: apiv1.EventTypeWarning,
+			reason:    controller.MemberProtected,
+			message:   fmt.Sprintf("%s/%s(%s) is unhealthy, but it's protected from failover", ns, podName, pdMember.ID),
+		}
+	}
 	return nil
 }
 
-// tryToDeleteAFailureMember tries to delete a PD member and associated Pod & PVC.
-// On success, new Pod & PVC will be created.
-// Note that this will fail if the kubelet on the node on which failed Pod was running is not responding.
-func (f *pdFailover) tryToDeleteAFailureMember(tc *v1alpha1.TidbCluster) error {
+// nodeNotReadyDeadline returns the deadline until which the failover of the pod is deferred because its node is
+// NotReady, e.g. in a network partition, the member may come back with the node. The failover is not deferred
+// if the node is deleted or the node can't be got.
+func (f *pdFailover) nodeNotReadyDeadline(ns, podName string) (time.Time, bool) {
+	timeout := f.deps.CLIConfig.PDNodeNotReadyTimeout
+	if timeout <= 0 || f.deps.NodeLister == nil {
+		return time.Time{}, false
+	}
+	pod, err := f.deps.PodLister.Pods(ns).Get(podName)
+	if err != nil || pod.Spec.NodeName == "" {
+		return time.Time{}, false
+	}
+	node, err := f.deps.NodeLister.Get(pod.Spec.NodeName)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			klog.Warningf("pd failover: failed to get node %s of pod %s/%s, error: %v", pod.Spec.NodeName, ns, podName, err)
+		}
+		return time.Time{}, false
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type != apiv1.NodeReady || cond.Status == apiv1.ConditionTrue {
+			continue
+		}
+		deadline := cond.LastTransitionTime.Add(timeout)
+		return deadline, time.Now().Before(deadline)
+	}
+	return time.Time{}, false
+}
+
+// tryToMarkTriggeredPeerAsFailure marks the member of the pod named by the tidb.pingcap.com/pd-trigger-failover
+// annotation as failure regardless of its health, the failover period and the cooldown after the recovery,
+// as long as the pd cluster keeps the quorum without it. The annotation is cleared once it's handled.
+func (f *pdFailover) tryToMarkTriggeredPeerAsFailure(tc *v1alpha1.TidbCluster, podName string) error {
+	ns := tc.GetNamespace()
+
+	pdName, reason := f.triggeredPDMember(tc, podName)
+	if reason != "" {
+		klog.Warningf("pd failover: refuse to fail over pd member %s/%s on demand, %s", ns, podName, reason)
+		f.deps.Recorder.Eventf(tc, apiv1.EventTypeWarning, controller.FailoverVetoed.For(v1alpha1.PDMemberType), "failover of %s/%s on demand is refused, %s", ns, podName, reason)
+		return f.clearFailoverTrigger(tc)
+	}
+
+	err := f.markPeerAsFailure(tc, pdName, true)
+	if _, marked := tc.Status.PD.FailureMembers[pdName]; !marked {
+		return err
+	}
+	if clearErr := f.clearFailoverTrigger(tc); clearErr != nil {
+		return clearErr
+	}
+	return err
+}
+
+// triggeredPDMember returns the name of the member of the pod to fail over on demand,
+// the reason is returned if the member can't be failed over.
+func (f *pdFailover) triggeredPDMember(tc *v1alpha1.TidbCluster, podName string) (string, string) {
+	for _, pdName := range sortedPDMemberNames(tc.Status.PD.Members) {
+		if strings.Split(pdName, ".")[0] != podName {
+			continue
+		}
+		pdMember := tc.Status.PD.Members[pdName]
+		if _, exist := tc.Status.PD.FailureMembers[pdName]; exist {
+			return "", "it's a failure member already"
+		}
+		if !f.isPodDesired(tc, podName) {
+			return "", "the pod is not desired"
+		}
+		if _, err := strconv.ParseUint(pdMember.ID, 10, 64); err != nil {
+			return "", fmt.Sprintf("the member id %q is invalid", pdMember.ID)
+		}
+		if tc.PDMemberProtected(pdMember.ID) {
+			return "", "the member is protected from failover"
+		}
+		// the member is out of the pd cluster once it's deleted, the rest must still be in quorum
+		_, healthCount := pdInQuorum(tc)
+		if pdMember.Health {
+			healthCount--
+		}
+		if healthCount <= (len(tc.Status.PD.Members)+len(tc.Status.PD.PeerMembers))/2 {
+			return "", fmt.Sprintf("the pd cluster would lose quorum without it, healthy %d after failover / desired %d", healthCount, tc.PDStsDesiredReplicas())
+		}
+		return pdName, ""
+	}
+	return "", "it's not a member of the pd cluster"
+}
+
+// clearFailoverTrigger removes the tidb.pingcap.com/pd-trigger-failover annotation from tc
+func (f *pdFailover) clearFailoverTrigger(tc *v1alpha1.TidbCluster) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
-	var failureMember *v1alpha1.PDFailureMember
-	var failurePodName string
-	var failurePDName string
 
+	// only the annotation is patched, an update of the whole object may revert the changes made meanwhile
+	mergePatch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				label.AnnPDTriggerFailoverKey: nil,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	updated, err := f.deps.Clientset.PingcapV1alpha1().TidbClusters(ns).Patch(context.TODO(), tcName, types.MergePatchType, mergePatch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("pd failover: failed to clear annotation %s of tc %s/%s, error: %v", label.AnnPDTriggerFailoverKey, ns, tcName, err)
+	}
+	// keep the status changed in this round, it's updated later with the new resource version
+	tc.ObjectMeta = updated.ObjectMeta
+	return nil
+}
+
+// markPeerAsFailure marks the member as failure and returns an error to skip the reconciliation,
+// note that status of tidb cluster will be updated always
+func (f *pdFailover) markPeerAsFailure(tc *v1alpha1.TidbCluster, pdName string, triggered bool) error {
+	ns := tc.GetNamespace()
+	pdMember := tc.Status.PD.Members[pdName]
+	podName := strings.Split(pdName, ".")[0]
+
+	pod, err := f.deps.PodLister.Pods(ns).Get(podName)
+	if err != nil {
+		return fmt.Errorf("tryToMarkAPeerAsFailure: failed to get pod %s/%s, error: %s", ns, podName, err)
+	}
+
+	pvcs, err := util.ResolvePVCFromPod(pod, f.deps.PVCLister)
+	if err != nil {
+		return fmt.Errorf("tryToMarkAPeerAsFailure: failed to get pvcs for pod %s/%s, error: %s", ns, pod.Name, err)
+	}
+
+	if triggered {
+		f.deps.Recorder.Eventf(tc, apiv1.EventTypeWarning, controller.FailoverTriggered.For(v1alpha1.PDMemberType), "%s/%s(%s) is failed over on demand by the annotation %s", ns, podName, pdMember.ID, label.AnnPDTriggerFailoverKey)
+	} else {
+		f.deps.Recorder.Eventf(tc, apiv1.EventTypeWarning, controller.MemberUnhealthy.For(v1alpha1.PDMemberType), "%s/%s(%s) is unhealthy", ns, podName, pdMember.ID)
+	}
+
+	if tc.Status.PD.FailureMembers == nil {
+		tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{}
+	}
+	pvcUIDSet := make(map[types.UID]struct{})
+	for _, pvc := range pvcs {
+		pvcUIDSet[pvc.UID] = struct{}{}
+	}
+	tc.Status.PD.FailureMembers[pdName] = v1alpha1.PDFailureMember{
+		PodName:       podName,
+		MemberID:      pdMember.ID,
+		PVCUIDSet:     pvcUIDSet,
+		MemberDeleted: false,
+		CreatedAt:     metav1.Now(),
+		Triggered:     triggered,
+	}
+	notifyFailoverWebhook(f.deps, tc, v1alpha1.PDMemberType, podName, FailoverActionMarkFailure)
+	return controller.RequeueErrorf("marking Pod: %s/%s pd member: %s as failure", ns, podName, pdMember.Name)
+}
+
+// unhealthyPDMembers returns the names of the desired members which have been unhealthy for longer than
+// the failover period, in the order of their ordinals. It has no side effects, the members which are
+// marked as failure members already are included.
+func (f *pdFailover) unhealthyPDMembers(tc *v1alpha1.TidbCluster) []string {
+	var names []string
+	for _, pdName := range sortedPDMemberNames(tc.Status.PD.Members) {
+		pdMember := tc.Status.PD.Members[pdName]
+		if pdMember.Health || pdMember.LastTransitionTime.IsZero() {
+			continue
+		}
+		if !f.isPodDesired(tc, strings.Split(pdName, ".")[0]) {
+			continue
+		}
+		failoverDeadline := pdMember.LastTransitionTime.Add(f.deps.CLIConfig.PDFailoverPeriod)
+		if time.Now().Before(failoverDeadline) {
+			continue
+		}
+		names = append(names, pdName)
+	}
+	return names
+}
+
+// notDeletedFailureMember returns the name of the first failure member which is not deleted yet
+// in the order of their ordinals, nil is returned if there is no such member.
+func notDeletedFailureMember(tc *v1alpha1.TidbCluster) (string, *v1alpha1.PDFailureMember) {
+	names := make([]string, 0, len(tc.Status.PD.FailureMembers))
 	for pdName := range tc.Status.PD.FailureMembers {
+		names = append(names, pdName)
+	}
+	for _, pdName := range sortPDNamesByOrdinal(names) {
 		pdMember := tc.Status.PD.FailureMembers[pdName]
 		if !pdMember.MemberDeleted {
-			failureMember = &pdMember
-			failurePodName = strings.Split(pdName, ".")[0]
-			failurePDName = pdName
-			break
+			return pdName, &pdMember
 		}
 	}
+	return "", nil
+}
+
+// tryToDeleteAFailureMember tries to delete a PD member and associated Pod & PVC.
+// On success, new Pod & PVC will be created.
+// Note that this will fail if the kubelet on the node on which failed Pod was running is not responding.
+func (f *pdFailover) tryToDeleteAFailureMember(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	failurePDName, failureMember := notDeletedFailureMember(tc)
 	if failureMember == nil {
 		klog.Infof("No PD FailureMembers to delete for tc %s/%s", ns, tcName)
 		return nil
 	}
+	failurePodName := strings.Split(failurePDName, ".")[0]
+
+	// the member may be protected after it's marked as a failure member, keep it in the pd cluster
+	if tc.PDMemberProtected(failureMember.MemberID) {
+		controller.RecordEventInWindow(f.deps.Recorder, tc, tc.FailoverEventInterval(v1alpha1.PDMemberType), apiv1.EventTypeWarning, controller.MemberProtected.For(v1alpha1.PDMemberType),
+			fmt.Sprintf("failure member %s/%s(%s) is protected from failover, skip deleting it", ns, failurePodName, failureMember.MemberID))
+		return nil
+	}
+
+	if f.deps.FailoverPreDeleteHook != nil {
+		if err := f.deps.FailoverPreDeleteHook(tc, failurePodName); err != nil {
+			f.deps.Recorder.Eventf(tc, apiv1.EventTypeWarning, controller.FailoverVetoed.For(v1alpha1.PDMemberType), "failover of failure member %s/%s is vetoed: %v", ns, failurePodName, err)
+			return controller.RequeueErrorf("pd failover[tryToDeleteAFailureMember]: pre-delete hook vetoed deleting failure member %s/%s, error: %v", ns, failurePodName, err)
+		}
+	}
+
+	if tc.PDFailoverMode() == v1alpha1.FailoverModePodOnly {
+		return f.tryToDeleteAFailurePod(tc, failureMember, failurePodName)
+	}
 
 	memberID, err := strconv.ParseUint(failureMember.MemberID, 10, 64)
 	if err != nil {
 		return err
 	}
 	// invoke deleteMember api to delete a member from the pd cluster
-	if err := controller.GetPDClient(f.deps.PDControl, tc).DeleteMemberByID(memberID); err != nil {
+	pdClient := controller.GetPDClient(f.deps.PDControl, tc)
+	if err := pdClient.DeleteMemberByID(memberID); err != nil {
 		klog.Errorf("pd failover[tryToDeleteAFailureMember]: failed to delete member %s/%s(%d), error: %v", ns, failurePodName, memberID, err)
 		return err
 	}
 	klog.Infof("pd failover[tryToDeleteAFailureMember]: delete member %s/%s(%d) successfully", ns, failurePodName, memberID)
-	f.deps.Recorder.Eventf(tc, apiv1.EventTypeWarning, "PDMemberDeleted", "failure member %s/%s(%d) deleted from PD cluster", ns, failurePodName, memberID)
+	// PD removes the member asynchronously, don't delete the PVCs of a member PD still thinks exists
+	if tc.PDFailoverWaitForMemberDeleted() {
+		members, err := pdClient.GetMembers()
+		if err != nil {
+			return fmt.Errorf("pd failover[tryToDeleteAFailureMember]: failed to get members of tc %s/%s, error: %v", ns, tcName, err)
+		}
+		for _, member := range members.Members {
+			if member.MemberId == memberID {
+				return controller.RequeueErrorf("pd failover[tryToDeleteAFailureMember]: member %s/%s(%d) has not left the PD cluster yet", ns, failurePodName, memberID)
+			}
+		}
+	}
+	f.deps.Recorder.Eventf(tc, apiv1.EventTypeWarning, controller.MemberDeleted.For(v1alpha1.PDMemberType), "failure member %s/%s(%d) deleted from PD cluster", ns, failurePodName, memberID)
 
 	// The order of old PVC deleting and the new Pod creating is not guaranteed by Kubernetes.
 	// If new Pod is created before old PVCs are deleted, the Statefulset will try to use the old PVCs and skip creating new PVCs.
@@ -183,33 +725,170 @@ func (f *pdFailover) tryToDeleteAFailureMember(tc *v1alpha1.TidbCluster) error {
 	// 2. If the old PVCs are first deleted successfully here, the new Pods will try to mount non-existing PVCs, which will pend forever.
 	//    This is where OrphanPodsCleaner kicks in, which will delete the pending Pods in this situation.
 	//    Please refer to orphan_pods_cleaner.go for details.
-	pod, err := f.deps.PodLister.Pods(ns).Get(failurePodName)
-	if err != nil && !errors.IsNotFound(err) {
-		return fmt.Errorf("pd failover[tryToDeleteAFailureMember]: failed to get pod %s/%s for tc %s/%s, error: %s", ns, failurePodName, ns, tcName, err)
+	pod, err := f.failurePod(tc, failureMember, failurePodName)
+	if err != nil {
+		return err
+	}
+	if pod != nil && f.shouldPreservePod(tc) {
+		if err := f.preserveFailurePod(tc, pod); err != nil {
+			return err
+		}
+		setMemberDeleted(tc, failurePDName)
+		return nil
+	}
+
+	pvcs, err := f.failureMemberPVCs(tc, failureMember, failurePodName)
+	if err != nil {
+		return err
 	}
 	if pod != nil {
 		if pod.DeletionTimestamp == nil {
-			if err := f.deps.PodControl.DeletePod(tc, pod); err != nil {
+			if err := f.deps.PodControl.DeletePodWithGracePeriod(tc, pod, tc.Spec.PD.GracePeriodSeconds); err != nil {
 				return err
 			}
 		}
-	} else {
-		klog.Infof("pd failover[tryToDeleteAFailureMember]: failure pod %s/%s not found, skip", ns, failurePodName)
+		// the ReadWriteOncePod PVCs can't be mounted by the replacement until the failure pod is gone,
+		// don't consider the member deleted before it's removed
+		if hasReadWriteOncePodPVC(pvcs) {
+			return controller.RequeueErrorf("pd failover[tryToDeleteAFailureMember]: failure pod %s/%s holding ReadWriteOncePod PVCs is not gone yet", ns, failurePodName)
+		}
+	}
+	for _, pvc := range pvcs {
+		if err := f.deps.PVCControl.DeletePVC(tc, pvc); err != nil {
+			klog.Errorf("pd failover[tryToDeleteAFailureMember]: failed to delete PVC: %s/%s, error: %s", ns, pvc.Name, err)
+			return err
+		}
+		klog.Infof("pd failover[tryToDeleteAFailureMember]: delete PVC %s/%s successfully", ns, pvc.Name)
 	}
 
+	setMemberDeleted(tc, failurePDName)
+	return nil
+}
+
+// failurePod returns the pod of the failure member, it's nil if the pod is not found or it has been
+// recreated since the member was marked as failure, the recreated pod is not the failure one.
+func (f *pdFailover) failurePod(tc *v1alpha1.TidbCluster, failureMember *v1alpha1.PDFailureMember, failurePodName string) (*apiv1.Pod, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	pod, err := f.deps.PodLister.Pods(ns).Get(failurePodName)
+	if errors.IsNotFound(err) {
+		klog.Infof("pd failover: failure pod %s/%s not found, skip", ns, failurePodName)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pd failover: failed to get pod %s/%s for tc %s/%s, error: %s", ns, failurePodName, ns, tcName, err)
+	}
+	if isPDFailurePodRecreated(failureMember, pod) {
+		klog.Infof("pd failover: failure pod %s/%s is recreated, skip", ns, failurePodName)
+		return nil, nil
+	}
+	return pod, nil
+}
+
+// isPDFailurePodRecreated returns whether the pod is created after the member was marked as failure,
+// the creation time is unknown for the failure members marked by an older version.
+func isPDFailurePodRecreated(failureMember *v1alpha1.PDFailureMember, pod *apiv1.Pod) bool {
+	return !failureMember.CreatedAt.IsZero() && failureMember.CreatedAt.Before(&pod.CreationTimestamp)
+}
+
+// shouldPreservePod returns whether the pod of the failure member should be preserved instead of being deleted,
+// the pod is released from the statefulset and its ordinal is skipped with the delete slots, so
+// it's only possible with the advanced statefulset.
+func (f *pdFailover) shouldPreservePod(tc *v1alpha1.TidbCluster) bool {
+	if !tc.Spec.PD.PreservePodOnFailover {
+		return false
+	}
+	if !features.DefaultFeatureGate.Enabled(features.AdvancedStatefulSet) {
+		klog.Warningf("pd failover: preserving the failure pod of tc %s/%s requires the AdvancedStatefulSet feature, delete it instead", tc.GetNamespace(), tc.GetName())
+		return false
+	}
+	return true
+}
+
+// preserveFailurePod keeps the pod and PVCs of the failure member for inspection. The ordinal of the pod is
+// added to the PD delete slots so that the replacement is created with a new ordinal, then the pod is released
+// from the statefulset by removing its owner reference and its component label, and labeled as a preserved
+// failure pod.
+func (f *pdFailover) preserveFailurePod(tc *v1alpha1.TidbCluster, pod *apiv1.Pod) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	ordinal, err := util.GetOrdinalFromPodName(pod.Name)
+	if err != nil {
+		return fmt.Errorf("pd failover[preserveFailurePod]: failed to parse ordinal from Pod name for %s/%s, error: %s", ns, pod.Name, err)
+	}
+
+	// the delete slot must be persisted before the pod is released, or the statefulset will try to
+	// create the replacement with the name of the preserved pod
+	deleteSlots := tc.PDDeleteSlots()
+	if !deleteSlots.Has(ordinal) {
+		deleteSlots.Insert(ordinal)
+		slots, err := json.Marshal(deleteSlots.List())
+		if err != nil {
+			return err
+		}
+		mergePatch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]string{
+					label.AnnPDDeleteSlots: string(slots),
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		updated, err := f.deps.Clientset.PingcapV1alpha1().TidbClusters(ns).Patch(context.TODO(), tcName, types.MergePatchType, mergePatch, metav1.PatchOptions{})
+		if err != nil {
+			return fmt.Errorf("pd failover[preserveFailurePod]: failed to add delete slot %d to tc %s/%s, error: %v", ordinal, ns, tcName, err)
+		}
+		// keep the status changed in this round, it's updated later with the new resource version
+		tc.ObjectMeta = updated.ObjectMeta
+	}
+
+	if _, ok := pod.Labels[label.PreservedFailurePodLabelKey]; !ok {
+		newPod := pod.DeepCopy()
+		if newPod.Labels == nil {
+			newPod.Labels = map[string]string{}
+		}
+		newPod.Labels[label.PreservedFailurePodLabelKey] = newPod.Labels[label.ComponentLabelKey]
+		delete(newPod.Labels, label.ComponentLabelKey)
+		var refs []metav1.OwnerReference
+		for _, ref := range newPod.OwnerReferences {
+			if ref.Controller == nil || !*ref.Controller {
+				refs = append(refs, ref)
+			}
+		}
+		newPod.OwnerReferences = refs
+		if _, err := f.deps.PodControl.UpdatePod(tc, newPod); err != nil {
+			return fmt.Errorf("pd failover[preserveFailurePod]: failed to release pod %s/%s from the statefulset, error: %v", ns, pod.Name, err)
+		}
+	}
+
+	f.deps.Recorder.Eventf(tc, apiv1.EventTypeWarning, controller.FailurePodPreserved.For(v1alpha1.PDMemberType),
+		"failure pod %s/%s and its PVCs are preserved for inspection, please delete them and the delete slot %d of pd when done", ns, pod.Name, ordinal)
+	klog.Infof("pd failover[preserveFailurePod]: preserve failure pod %s/%s of tc %s/%s", ns, pod.Name, ns, tcName)
+	return nil
+}
+
+// failureMemberPVCs returns the PVCs of the failure member to delete, the PVCs which are being deleted
+// or created after the member is marked as failure are excluded.
+func (f *pdFailover) failureMemberPVCs(tc *v1alpha1.TidbCluster, failureMember *v1alpha1.PDFailureMember, failurePodName string) ([]*apiv1.PersistentVolumeClaim, error) {
+	ns := tc.GetNamespace()
 	ordinal, err := util.GetOrdinalFromPodName(failurePodName)
 	if err != nil {
-		return fmt.Errorf("pd failover[tryToDeleteAFailureMember]: failed to parse ordinal from Pod name for %s/%s, error: %s", ns, failurePodName, err)
+		return nil, fmt.Errorf("pd failover: failed to parse ordinal from Pod name for %s/%s, error: %s", ns, failurePodName, err)
 	}
 	pvcSelector, err := GetPVCSelectorForPod(tc, v1alpha1.PDMemberType, ordinal)
 	if err != nil {
-		return fmt.Errorf("pd failover[tryToDeleteAFailureMember]: failed to get PVC selector for Pod %s/%s, error: %s", ns, failurePodName, err)
+		return nil, fmt.Errorf("pd failover: failed to get PVC selector for Pod %s/%s, error: %s", ns, failurePodName, err)
 	}
 	pvcs, err := f.deps.PVCLister.PersistentVolumeClaims(ns).List(pvcSelector)
 	if err != nil && !errors.IsNotFound(err) {
-		return fmt.Errorf("pd failover[tryToDeleteAFailureMember]: failed to get PVCs for pod %s/%s, error: %s", ns, failurePodName, err)
+		return nil, fmt.Errorf("pd failover: failed to get PVCs for pod %s/%s, error: %s", ns, failurePodName, err)
 	}
 
+	var result []*apiv1.PersistentVolumeClaim
 	for _, pvc := range pvcs {
 		_, pvcUIDExist := failureMember.PVCUIDSet[pvc.GetUID()]
 		// for backward compatibility, if there exists failureMembers and user upgrades operator to newer version
@@ -218,16 +897,44 @@ func (f *pdFailover) tryToDeleteAFailureMember(tc *v1alpha1.TidbCluster) error {
 			pvcUIDExist = true
 		}
 		if pvc.DeletionTimestamp == nil && pvcUIDExist {
-			if err := f.deps.PVCControl.DeletePVC(tc, pvc); err != nil {
-				klog.Errorf("pd failover[tryToDeleteAFailureMember]: failed to delete PVC: %s/%s, error: %s", ns, pvc.Name, err)
-				return err
-			}
-			klog.Infof("pd failover[tryToDeleteAFailureMember]: delete PVC %s/%s successfully", ns, pvc.Name)
+			result = append(result, pvc)
 		}
 	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
 
-	setMemberDeleted(tc, failurePDName)
-	return nil
+// tryToDeleteAFailurePod only deletes the Pod of the failure member in the PodOnly failover mode,
+// the member is kept in the PD cluster and the PVCs are kept, so the recreated Pod joins
+// the PD cluster with the same data. The failure member is never marked as deleted,
+// so no more replicas are added, and it's cleared when the member becomes healthy again.
+func (f *pdFailover) tryToDeleteAFailurePod(tc *v1alpha1.TidbCluster, failureMember *v1alpha1.PDFailureMember, failurePodName string) error {
+	ns := tc.GetNamespace()
+
+	pod, err := f.failurePod(tc, failureMember, failurePodName)
+	if err != nil {
+		return err
+	}
+	// the pod has been deleted or recreated since the member was marked as failure, wait for it to be healthy
+	if pod == nil || pod.DeletionTimestamp != nil {
+		klog.Infof("pd failover[tryToDeleteAFailurePod]: failure pod %s/%s is deleted or recreated, skip", ns, failurePodName)
+		return nil
+	}
+
+	pvcs, err := f.failureMemberPVCs(tc, failureMember, failurePodName)
+	if err != nil {
+		return err
+	}
+	if err := f.stampFailoverOrigin(tc, failurePodName, pvcs); err != nil {
+		return err
+	}
+	if err := f.deps.PodControl.DeletePodWithGracePeriod(tc, pod, tc.Spec.PD.GracePeriodSeconds); err != nil {
+		return err
+	}
+	f.deps.Recorder.Eventf(tc, apiv1.EventTypeWarning, controller.PodDeleted.For(v1alpha1.PDMemberType), "pod %s/%s of failure member %s is deleted", ns, failurePodName, failureMember.MemberID)
+	return controller.RequeueErrorf("pd failover[tryToDeleteAFailurePod]: pod %s/%s of failure member is deleted", ns, failurePodName)
 }
 
 func (f *pdFailover) isPodDesired(tc *v1alpha1.TidbCluster, podName string) bool {
@@ -250,27 +957,109 @@ func setMemberDeleted(tc *v1alpha1.TidbCluster, pdName string) {
 	klog.Infof("pd failover: set pd member: %s/%s deleted", tc.GetName(), pdName)
 }
 
-// is healthy PD more than a half
-func (f *pdFailover) isPDInQuorum(tc *v1alpha1.TidbCluster) (bool, int) {
-	healthCount := 0
+// reportUnhealthyMembers records the events of the unhealthy members
+func (f *pdFailover) reportUnhealthyMembers(tc *v1alpha1.TidbCluster) {
 	ns := tc.GetNamespace()
-	for podName, pdMember := range tc.Status.PD.Members {
+	interval := tc.FailoverEventInterval(v1alpha1.PDMemberType)
+	for _, podName := range sortedPDMemberNames(tc.Status.PD.Members) {
+		pdMember := tc.Status.PD.Members[podName]
+		if !pdMember.Health {
+			controller.RecordEventInWindow(f.deps.Recorder, tc, interval, apiv1.EventTypeWarning, controller.MemberUnhealthy.For(v1alpha1.PDMemberType),
+				fmt.Sprintf("%s/%s(%s) is unhealthy", ns, podName, pdMember.ID))
+		}
+	}
+	for _, name := range sortedPDMemberNames(tc.Status.PD.PeerMembers) {
+		pdMember := tc.Status.PD.PeerMembers[name]
+		if !pdMember.Health {
+			controller.RecordEventInWindow(f.deps.Recorder, tc, interval, apiv1.EventTypeWarning, controller.PeerMemberUnhealthy.For(v1alpha1.PDMemberType),
+				fmt.Sprintf("%s(%s) is unhealthy", pdMember.Name, pdMember.ID))
+		}
+	}
+}
+
+// duplicatedPDMemberIDs returns the message describing the member ids reported by more than one PD member
+// of .Status.PD.Members and .Status.PD.PeerMembers, it's empty if the member ids are unique. A member
+// reported by both of them under the same name is the same member.
+func duplicatedPDMemberIDs(tc *v1alpha1.TidbCluster) string {
+	memberNames := map[string][]string{}
+	for _, members := range []map[string]v1alpha1.PDMember{tc.Status.PD.Members, tc.Status.PD.PeerMembers} {
+		for _, name := range sortedPDMemberNames(members) {
+			id := members[name].ID
+			if id == "" || slice.ContainsString(memberNames[id], name, nil) {
+				continue
+			}
+			memberNames[id] = append(memberNames[id], name)
+		}
+	}
+
+	var duplicated []string
+	for id, names := range memberNames {
+		if len(names) > 1 {
+			duplicated = append(duplicated, fmt.Sprintf("member id %s is reported by %s", id, strings.Join(names, ", ")))
+		}
+	}
+	sort.Strings(duplicated)
+	return strings.Join(duplicated, "; ")
+}
+
+// pdInQuorum returns whether the healthy PD members are more than a half and the count of them
+func pdInQuorum(tc *v1alpha1.TidbCluster) (bool, int) {
+	healthCount := 0
+	for _, pdMember := range tc.Status.PD.Members {
 		if pdMember.Health {
 			healthCount++
-		} else {
-			f.deps.Recorder.Eventf(tc, apiv1.EventTypeWarning, "PDMemberUnhealthy", "%s/%s(%s) is unhealthy", ns, podName, pdMember.ID)
 		}
 	}
 	for _, pdMember := range tc.Status.PD.PeerMembers {
 		if pdMember.Health {
 			healthCount++
-		} else {
-			f.deps.Recorder.Eventf(tc, apiv1.EventTypeWarning, "PDPeerMemberUnhealthy", "%s(%s) is unhealthy", pdMember.Name, pdMember.ID)
 		}
 	}
 	return healthCount > (len(tc.Status.PD.Members)+len(tc.Status.PD.PeerMembers))/2, healthCount
 }
 
+// sortedPDMemberNames returns the names of the members in the order of their ordinals, e.g. pd-0, pd-1, pd-2,
+// so the failover handles the members and records the events in a stable order.
+// The names without an ordinal come last in lexical order.
+func sortedPDMemberNames(members map[string]v1alpha1.PDMember) []string {
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	return sortPDNamesByOrdinal(names)
+}
+
+// sortPDNamesByOrdinal returns the member names sorted in the same order as sortedPDMemberNames
+func sortPDNamesByOrdinal(memberNames []string) []string {
+	type ordinalName struct {
+		name    string
+		ordinal int32
+		err     error
+	}
+	names := make([]ordinalName, 0, len(memberNames))
+	for _, name := range memberNames {
+		ordinal, err := util.GetOrdinalFromPodName(strings.Split(name, ".")[0])
+		names = append(names, ordinalName{name: name, ordinal: ordinal, err: err})
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := names[i], names[j]
+		if (a.err == nil) != (b.err == nil) {
+			return a.err == nil
+		}
+		if a.err == nil && a.ordinal != b.ordinal {
+			return a.ordinal < b.ordinal
+		}
+		return a.name < b.name
+	})
+	sorted := make([]string, 0, len(names))
+	for _, n := range names {
+		sorted = append(sorted, n.name)
+	}
+	return sorted
+}
+
+var _ FailoverPlanner = &pdFailover{}
+
 type fakePDFailover struct{}
 
 // NewFakePDFailover returns a fake Failover
@@ -14,20 +14,27 @@
 package member
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"sort"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
@@ -92,6 +99,47 @@ func TestPDFailoverFailover(t *testing.T) {
 				g.Expect(events).To(HaveLen(0))
 			},
 		},
+		{
+			name: "pd status sync failed within the stale threshold",
+			update: func(tc *v1alpha1.TidbCluster) {
+				allMembersReady(tc)
+				tc.Status.PD.UnsyncedSince = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+			},
+			maxFailoverCount: 3,
+			hasPVC:           true,
+			hasPod:           true,
+			statusSyncFailed: true,
+			errExpectFn:      errExpectNotNil,
+			expectFn: func(tc *v1alpha1.TidbCluster, _ *pdFailover) {
+				g.Expect(utiltidbcluster.GetTidbClusterCondition(tc.Status, controller.ConditionTypeFor(v1alpha1.FailoverBlocked, v1alpha1.PDMemberType))).To(BeNil())
+				events := collectEvents(recorder.Events)
+				g.Expect(events).To(HaveLen(0))
+			},
+		},
+		{
+			name: "pd status sync failed beyond the stale threshold",
+			update: func(tc *v1alpha1.TidbCluster) {
+				oneNotReadyMember(tc)
+				tc.Status.PD.UnsyncedSince = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			},
+			maxFailoverCount: 3,
+			hasPVC:           true,
+			hasPod:           true,
+			statusSyncFailed: true,
+			errExpectFn:      errExpectNotNil,
+			expectFn: func(tc *v1alpha1.TidbCluster, _ *pdFailover) {
+				// the failover is still refused
+				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
+				cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, controller.ConditionTypeFor(v1alpha1.FailoverBlocked, v1alpha1.PDMemberType))
+				g.Expect(cond).NotTo(BeNil())
+				g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+				g.Expect(cond.Reason).To(Equal(utiltidbcluster.PDStatusSyncStale))
+				events := collectEvents(recorder.Events)
+				g.Expect(events).To(HaveLen(2))
+				g.Expect(events[0]).To(ContainSubstring("Warning PDStatusSyncStale pd status has not been synced since"))
+				g.Expect(events[1]).To(ContainSubstring("Warning PDFailoverBlocked pd status has not been synced since"))
+			},
+		},
 		{
 			name:                     "two members are not ready, not in quorum",
 			update:                   twoMembersNotReady,
@@ -110,11 +158,15 @@ func TestPDFailoverFailover(t *testing.T) {
 			expectFn: func(tc *v1alpha1.TidbCluster, _ *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
+				cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, controller.ConditionTypeFor(v1alpha1.FailoverBlocked, v1alpha1.PDMemberType))
+				g.Expect(cond).NotTo(BeNil())
+				g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+				g.Expect(cond.Reason).To(Equal(utiltidbcluster.PDQuorumLost))
 				events := collectEvents(recorder.Events)
-				sort.Strings(events)
-				g.Expect(events).To(HaveLen(2))
+				g.Expect(events).To(HaveLen(3))
 				g.Expect(events[0]).To(ContainSubstring("test-pd-0(0) is unhealthy"))
 				g.Expect(events[1]).To(ContainSubstring("test-pd-1(12891273174085095651) is unhealthy"))
+				g.Expect(events[2]).To(ContainSubstring("Warning PDFailoverBlocked pd cluster is not healthy"))
 			},
 		},
 		{
@@ -133,7 +185,6 @@ func TestPDFailoverFailover(t *testing.T) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
 				events := collectEvents(recorder.Events)
-				sort.Strings(events)
 				g.Expect(events).To(HaveLen(2))
 				g.Expect(events[0]).To(ContainSubstring("test-pd-0(0) is unhealthy"))
 				g.Expect(events[1]).To(ContainSubstring("test-pd-1(12891273174085095651) is unhealthy"))
@@ -168,9 +219,9 @@ func TestPDFailoverFailover(t *testing.T) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
 				events := collectEvents(recorder.Events)
-				sort.Strings(events)
 				g.Expect(events).To(HaveLen(2))
 				g.Expect(events[0]).To(ContainSubstring("test-pd-1(12891273174085095651) is unhealthy"))
+				g.Expect(events[1]).To(ContainSubstring(controller.PeerMemberUnhealthy.For(v1alpha1.PDMemberType) + " test-pd-0(0) is unhealthy"))
 			},
 		},
 		{
@@ -196,6 +247,63 @@ func TestPDFailoverFailover(t *testing.T) {
 				g.Expect(events[0]).To(ContainSubstring("failure member default/test-pd-1(12891273174085095651) deleted from PD cluster"))
 			},
 		},
+		{
+			name: "two members are ready and a failure member in PodOnly mode",
+			update: func(tc *v1alpha1.TidbCluster) {
+				oneFailureMember(tc)
+				tc.Spec.PD.FailoverMode = v1alpha1.FailoverModePodOnly
+			},
+			maxFailoverCount:         3,
+			hasPVC:                   true,
+			hasPod:                   true,
+			podWithDeletionTimestamp: false,
+			delMemberFailed:          true,
+			delPodFailed:             false,
+			delPVCFailed:             false,
+			errExpectFn:              errExpectRequeue,
+			expectFn: func(tc *v1alpha1.TidbCluster, pf *pdFailover) {
+				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
+				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(1))
+				pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+				pd1, ok := tc.Status.PD.FailureMembers[pd1Name]
+				g.Expect(ok).To(Equal(true))
+				g.Expect(pd1.MemberDeleted).To(Equal(false))
+				_, err := pf.deps.PodLister.Pods(metav1.NamespaceDefault).Get(pd1Name)
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+				pvcs, err := pf.deps.PVCLister.PersistentVolumeClaims(metav1.NamespaceDefault).List(labels.Everything())
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(pvcs).To(HaveLen(2))
+				events := collectEvents(recorder.Events)
+				g.Expect(events).To(HaveLen(1))
+				g.Expect(events[0]).To(ContainSubstring("pod default/test-pd-1 of failure member 12891273174085095651 is deleted"))
+			},
+		},
+		{
+			name: "the pod of a failure member is being deleted in PodOnly mode",
+			update: func(tc *v1alpha1.TidbCluster) {
+				oneFailureMember(tc)
+				tc.Spec.PD.FailoverMode = v1alpha1.FailoverModePodOnly
+			},
+			maxFailoverCount:         3,
+			hasPVC:                   true,
+			hasPod:                   true,
+			podWithDeletionTimestamp: true,
+			delMemberFailed:          true,
+			delPodFailed:             false,
+			delPVCFailed:             false,
+			errExpectFn:              errExpectNil,
+			expectFn: func(tc *v1alpha1.TidbCluster, pf *pdFailover) {
+				pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+				g.Expect(tc.Status.PD.FailureMembers[pd1Name].MemberDeleted).To(Equal(false))
+				_, err := pf.deps.PodLister.Pods(metav1.NamespaceDefault).Get(pd1Name)
+				g.Expect(err).NotTo(HaveOccurred())
+				pvcs, err := pf.deps.PVCLister.PersistentVolumeClaims(metav1.NamespaceDefault).List(labels.Everything())
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(pvcs).To(HaveLen(2))
+				events := collectEvents(recorder.Events)
+				g.Expect(events).To(HaveLen(0))
+			},
+		},
 		{
 			name: "has one not ready member, but not exceed deadline",
 			update: func(tc *v1alpha1.TidbCluster) {
@@ -248,6 +356,33 @@ func TestPDFailoverFailover(t *testing.T) {
 				g.Expect(events[0]).To(ContainSubstring("test-pd-1(12891273174085095651) is unhealthy"))
 			},
 		},
+		{
+			name: "has one not ready member, and exceed deadline, but memberID is not numeric",
+			update: func(tc *v1alpha1.TidbCluster) {
+				oneNotReadyMember(tc)
+				pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+				pd1 := tc.Status.PD.Members[pd1Name]
+				pd1.ID = "wrong-id"
+				tc.Status.PD.Members[pd1Name] = pd1
+			},
+			maxFailoverCount:         3,
+			hasPVC:                   true,
+			hasPod:                   true,
+			podWithDeletionTimestamp: false,
+			delMemberFailed:          false,
+			delPodFailed:             false,
+			delPVCFailed:             false,
+			statusSyncFailed:         false,
+			errExpectFn:              errExpectNil,
+			expectFn: func(tc *v1alpha1.TidbCluster, _ *pdFailover) {
+				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
+				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
+				events := collectEvents(recorder.Events)
+				g.Expect(events).To(HaveLen(2))
+				g.Expect(events[0]).To(ContainSubstring("test-pd-1(wrong-id) is unhealthy"))
+				g.Expect(events[1]).To(ContainSubstring(controller.MemberIDInvalid.For(v1alpha1.PDMemberType) + " default/test-pd-1 has an invalid member id \"wrong-id\", skip failover"))
+			},
+		},
 		{
 			name:                     "has one not ready member, don't have pvc",
 			update:                   oneNotReadyMember,
@@ -299,7 +434,7 @@ func TestPDFailoverFailover(t *testing.T) {
 				events := collectEvents(recorder.Events)
 				g.Expect(events).To(HaveLen(2))
 				g.Expect(events[0]).To(ContainSubstring("test-pd-1(12891273174085095651) is unhealthy"))
-				g.Expect(events[1]).To(ContainSubstring("PDMemberUnhealthy default/test-pd-1(12891273174085095651) is unhealthy"))
+				g.Expect(events[1]).To(ContainSubstring(controller.MemberUnhealthy.For(v1alpha1.PDMemberType) + " default/test-pd-1(12891273174085095651) is unhealthy"))
 			},
 		},
 		{
@@ -604,6 +739,403 @@ func TestPDFailoverFailover(t *testing.T) {
 	}
 }
 
+func TestPDFailoverWaitForMemberDeleted(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+	tc.Spec.PD.WaitForMemberDeleted = pointer.BoolPtr(true)
+	tc.Status.PD.Synced = true
+	oneFailureMember(tc)
+	pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+
+	pdFailover, pvcIndexer, podIndexer, fakePDControl, _, _ := newFakePDFailover()
+	pdClient := controller.NewFakePDClient(fakePDControl, tc)
+	pdClient.AddReaction(pdapi.DeleteMemberByIDActionType, func(action *pdapi.Action) (interface{}, error) {
+		return nil, nil
+	})
+	// PD reports the failure member on the first check and removes it asynchronously
+	getMembersCount := 0
+	pdClient.AddReaction(pdapi.GetMembersActionType, func(action *pdapi.Action) (interface{}, error) {
+		getMembersCount++
+		members := []*pdpb.Member{
+			{Name: ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 0), MemberId: 0},
+			{Name: ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 2), MemberId: 2},
+		}
+		if getMembersCount == 1 {
+			members = append(members, &pdpb.Member{Name: pd1Name, MemberId: 12891273174085095651})
+		}
+		return &pdapi.MembersInfo{Members: members}, nil
+	})
+
+	pod := newPodForPDFailover(tc, v1alpha1.PDMemberType, 1)
+	pvc := newPVCForPDFailover(tc, v1alpha1.PDMemberType, 1)
+	pvc.Name = pvc.Name + "-1"
+	pvc.UID = pvc.UID + "-1"
+	pvc.Labels[label.AnnPodNameKey] = pod.GetName()
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name},
+		},
+	})
+	g.Expect(podIndexer.Add(pod)).To(Succeed())
+	g.Expect(pvcIndexer.Add(pvc)).To(Succeed())
+
+	// the member is still in the PD cluster, requeue without deleting the PVC
+	err := pdFailover.Failover(tc)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(tc.Status.PD.FailureMembers[pd1Name].MemberDeleted).To(BeFalse())
+	_, err = pdFailover.deps.PVCLister.PersistentVolumeClaims(metav1.NamespaceDefault).Get(pvc.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the member has left the PD cluster, the PVC is deleted
+	err = pdFailover.Failover(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(getMembersCount).To(Equal(2))
+	g.Expect(tc.Status.PD.FailureMembers[pd1Name].MemberDeleted).To(BeTrue())
+	_, err = pdFailover.deps.PVCLister.PersistentVolumeClaims(metav1.NamespaceDefault).Get(pvc.Name)
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestPDFailoverReadWriteOncePodPVC(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+	tc.Status.PD.Synced = true
+	oneFailureMember(tc)
+	pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+
+	pdFailover, pvcIndexer, podIndexer, fakePDControl, _, _ := newFakePDFailover()
+	pdClient := controller.NewFakePDClient(fakePDControl, tc)
+	pdClient.AddReaction(pdapi.DeleteMemberByIDActionType, func(action *pdapi.Action) (interface{}, error) {
+		return nil, nil
+	})
+
+	// the failure pod is being deleted but still present
+	pod := newPodForPDFailover(tc, v1alpha1.PDMemberType, 1)
+	pod.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	pvc := newPVCForPDFailover(tc, v1alpha1.PDMemberType, 1)
+	pvc.Name = pvc.Name + "-1"
+	pvc.UID = pvc.UID + "-1"
+	pvc.Labels[label.AnnPodNameKey] = pod.GetName()
+	pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{ReadWriteOncePod}
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name},
+		},
+	})
+	g.Expect(podIndexer.Add(pod)).To(Succeed())
+	g.Expect(pvcIndexer.Add(pvc)).To(Succeed())
+
+	// requeue without deleting the PVC until the pod is gone
+	err := pdFailover.Failover(tc)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("ReadWriteOncePod"))
+	g.Expect(tc.Status.PD.FailureMembers[pd1Name].MemberDeleted).To(BeFalse())
+	_, err = pdFailover.deps.PVCLister.PersistentVolumeClaims(metav1.NamespaceDefault).Get(pvc.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the pod is gone, the PVC is deleted
+	g.Expect(podIndexer.Delete(pod)).To(Succeed())
+	err = pdFailover.Failover(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Status.PD.FailureMembers[pd1Name].MemberDeleted).To(BeTrue())
+	_, err = pdFailover.deps.PVCLister.PersistentVolumeClaims(metav1.NamespaceDefault).Get(pvc.Name)
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestPDFailoverRecreatedPod(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+	tc.Status.PD.Synced = true
+	oneFailureMember(tc)
+	pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+	failureMember := tc.Status.PD.FailureMembers[pd1Name]
+	failureMember.CreatedAt = metav1.NewTime(time.Now().Add(-time.Hour))
+	tc.Status.PD.FailureMembers[pd1Name] = failureMember
+
+	pdFailover, pvcIndexer, podIndexer, fakePDControl, podControl, _ := newFakePDFailover()
+	pdClient := controller.NewFakePDClient(fakePDControl, tc)
+	pdClient.AddReaction(pdapi.DeleteMemberByIDActionType, func(action *pdapi.Action) (interface{}, error) {
+		return nil, nil
+	})
+
+	// the pod is recreated after the member is marked as failure
+	pod := newPodForPDFailover(tc, v1alpha1.PDMemberType, 1)
+	pod.CreationTimestamp = metav1.Now()
+	pvc := newPVCForPDFailover(tc, v1alpha1.PDMemberType, 1)
+	pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{ReadWriteOncePod}
+	g.Expect(podIndexer.Add(pod)).To(Succeed())
+	g.Expect(pvcIndexer.Add(pvc)).To(Succeed())
+
+	g.Expect(pdFailover.Failover(tc)).To(Succeed())
+	g.Expect(podControl.DeleteGracePeriods).NotTo(HaveKey(pod.Name))
+	g.Expect(tc.Status.PD.FailureMembers[pd1Name].MemberDeleted).To(BeTrue())
+}
+
+func TestPDFailoverFailoverOrigin(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+	tc.Spec.PD.FailoverMode = v1alpha1.FailoverModePodOnly
+	tc.Status.PD.Synced = true
+	oneFailureMember(tc)
+	pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+	pd2Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 2)
+
+	pdFailover, pvcIndexer, podIndexer, _, _, _ := newFakePDFailover()
+	pvIndexer := pdFailover.deps.PVControl.(*controller.FakePVControl).PVIndexer
+
+	pod := newPodForPDFailover(tc, v1alpha1.PDMemberType, 1)
+	pvc := newPVCForPDFailover(tc, v1alpha1.PDMemberType, 1)
+	pvc.UID = pvc.UID + "-1"
+	pvc.Labels[label.AnnPodNameKey] = pod.GetName()
+	g.Expect(podIndexer.Add(pod)).To(Succeed())
+	g.Expect(pvcIndexer.Add(pvc)).To(Succeed())
+	newPV := func(name, pvcName string, uid types.UID) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: label.New().Instance(tc.GetInstanceName()).PD().Namespace(tc.GetNamespace()).Labels(),
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+				ClaimRef:                      &corev1.ObjectReference{Namespace: tc.GetNamespace(), Name: pvcName, UID: uid},
+			},
+		}
+	}
+	g.Expect(pvIndexer.Add(newPV(pvc.Spec.VolumeName, pvc.Name, pvc.UID))).To(Succeed())
+	// the PV released by the failover of another pod
+	otherPV := newPV("pv-2", ordinalPVCName(v1alpha1.PDMemberType, controller.PDMemberName(tc.GetName()), 2), "pvc-2-uid")
+	otherPV.Annotations = map[string]string{label.AnnFailoverOriginKey: pd2Name}
+	g.Expect(pvIndexer.Add(otherPV)).To(Succeed())
+
+	// the PV is annotated with the failure pod before the pod is deleted
+	err := pdFailover.Failover(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(tc.Status.PD.FailureMembers[pd1Name].MemberDeleted).To(BeFalse())
+	pv, err := pdFailover.deps.PVControl.GetPV(pvc.Spec.VolumeName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pv.Annotations).To(HaveKeyWithValue(label.AnnFailoverOriginKey, pd1Name))
+
+	// the PV isn't rebound while the PVC exists
+	recovered := tc.DeepCopy()
+	pdFailover.Recover(recovered)
+	pv, err = pdFailover.deps.PVControl.GetPV(pvc.Spec.VolumeName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pv.Spec.ClaimRef.UID).To(Equal(pvc.UID))
+
+	// the PV isn't rebound if the member is deleted from the pd cluster
+	g.Expect(pvcIndexer.Delete(pvc)).To(Succeed())
+	deleted := tc.DeepCopy()
+	setMemberDeleted(deleted, pd1Name)
+	pdFailover.Recover(deleted)
+	pv, err = pdFailover.deps.PVControl.GetPV(pvc.Spec.VolumeName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pv.Spec.ClaimRef.UID).To(Equal(pvc.UID))
+
+	// the PVC is lost, the original PV is rebound to the PVC on recovery
+	pdFailover.Recover(tc)
+	g.Expect(tc.Status.PD.FailureMembers).To(BeEmpty())
+	pv, err = pdFailover.deps.PVControl.GetPV(pvc.Spec.VolumeName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pv.Spec.ClaimRef.Name).To(Equal(pvc.Name))
+	g.Expect(pv.Spec.ClaimRef.UID).To(BeEmpty())
+	// the PV of the pod which isn't recovered is kept
+	pv, err = pdFailover.deps.PVControl.GetPV(otherPV.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pv.Spec.ClaimRef.UID).To(Equal(types.UID("pvc-2-uid")))
+}
+
+func TestPDFailoverPreservePodOnFailover(t *testing.T) {
+	g := NewGomegaWithT(t)
+	features.DefaultFeatureGate.Set("AdvancedStatefulSet=true")
+	defer features.DefaultFeatureGate.Set("AdvancedStatefulSet=false")
+
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+	tc.Spec.PD.PreservePodOnFailover = true
+	tc.Status.PD.Synced = true
+	oneFailureMember(tc)
+	pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+
+	pdFailover, pvcIndexer, podIndexer, fakePDControl, _, _ := newFakePDFailover()
+	recorder := pdFailover.deps.Recorder.(*record.FakeRecorder)
+	stored, err := pdFailover.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	// the annotation added meanwhile is kept
+	if stored.Annotations == nil {
+		stored.Annotations = map[string]string{}
+	}
+	stored.Annotations["test"] = "kept"
+	_, err = pdFailover.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Update(context.TODO(), stored, metav1.UpdateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	pdClient := controller.NewFakePDClient(fakePDControl, tc)
+	pdClient.AddReaction(pdapi.DeleteMemberByIDActionType, func(action *pdapi.Action) (interface{}, error) {
+		return nil, nil
+	})
+
+	pod := newPodForPDFailover(tc, v1alpha1.PDMemberType, 1)
+	pod.Labels = label.New().Instance(tc.GetInstanceName()).PD().Labels()
+	pod.OwnerReferences = []metav1.OwnerReference{
+		{Kind: "StatefulSet", Name: controller.PDMemberName(tc.GetName()), Controller: pointer.BoolPtr(true)},
+	}
+	pvc := newPVCForPDFailover(tc, v1alpha1.PDMemberType, 1)
+	pvc.Name = pvc.Name + "-1"
+	pvc.UID = pvc.UID + "-1"
+	pvc.Labels[label.AnnPodNameKey] = pod.GetName()
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name},
+		},
+	})
+	g.Expect(podIndexer.Add(pod)).To(Succeed())
+	g.Expect(pvcIndexer.Add(pvc)).To(Succeed())
+
+	err = pdFailover.Failover(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Status.PD.FailureMembers[pd1Name].MemberDeleted).To(BeTrue())
+
+	// the pod and PVC survive, the pod is released from the statefulset
+	preserved, err := pdFailover.deps.PodLister.Pods(metav1.NamespaceDefault).Get(pod.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(preserved.Labels).To(HaveKeyWithValue(label.PreservedFailurePodLabelKey, label.PDLabelVal))
+	g.Expect(preserved.Labels).NotTo(HaveKey(label.ComponentLabelKey))
+	g.Expect(metav1.GetControllerOf(preserved)).To(BeNil())
+	_, err = pdFailover.deps.PVCLister.PersistentVolumeClaims(metav1.NamespaceDefault).Get(pvc.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the replacement is created with a new ordinal
+	g.Expect(tc.PDDeleteSlots().List()).To(Equal([]int32{1}))
+	updated, err := pdFailover.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Get(context.TODO(), tc.Name, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(updated.Annotations).To(HaveKeyWithValue(label.AnnPDDeleteSlots, "[1]"))
+	g.Expect(updated.Annotations).To(HaveKeyWithValue("test", "kept"))
+
+	events := collectEvents(recorder.Events)
+	g.Expect(events).To(ContainElement(ContainSubstring(controller.FailurePodPreserved.For(v1alpha1.PDMemberType))))
+}
+
+func TestPDFailoverGracePeriodSeconds(t *testing.T) {
+	tests := []struct {
+		name               string
+		gracePeriodSeconds *int64
+	}{
+		{
+			name: "default grace period",
+		},
+		{
+			name:               "immediate deletion",
+			gracePeriodSeconds: pointer.Int64Ptr(0),
+		},
+		{
+			name:               "longer grace period",
+			gracePeriodSeconds: pointer.Int64Ptr(120),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tc := newTidbClusterForPD()
+			tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+			tc.Spec.PD.GracePeriodSeconds = tt.gracePeriodSeconds
+			tc.Status.PD.Synced = true
+			oneFailureMember(tc)
+
+			pdFailover, pvcIndexer, podIndexer, fakePDControl, podControl, _ := newFakePDFailover()
+			pdClient := controller.NewFakePDClient(fakePDControl, tc)
+			pdClient.AddReaction(pdapi.DeleteMemberByIDActionType, func(action *pdapi.Action) (interface{}, error) {
+				return nil, nil
+			})
+			pod := newPodForPDFailover(tc, v1alpha1.PDMemberType, 1)
+			g.Expect(podIndexer.Add(pod)).To(Succeed())
+			g.Expect(pvcIndexer.Add(newPVCForPDFailover(tc, v1alpha1.PDMemberType, 1))).To(Succeed())
+
+			g.Expect(pdFailover.Failover(tc)).To(Succeed())
+			g.Expect(podControl.DeleteGracePeriods).To(HaveKey(pod.Name))
+			g.Expect(podControl.DeleteGracePeriods[pod.Name]).To(Equal(tt.gracePeriodSeconds))
+		})
+	}
+}
+
+func TestPDFailoverPreDeleteHook(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name         string
+		hookErr      error
+		expectVetoed bool
+	}
+	tests := []testcase{
+		{
+			name:         "hook vetoes the failover",
+			hookErr:      fmt.Errorf("member is protected by policy"),
+			expectVetoed: true,
+		},
+		{
+			name:         "hook allows the failover",
+			expectVetoed: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+		tc.Status.PD.Synced = true
+		oneFailureMember(tc)
+		pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+
+		pdFailover, _, podIndexer, fakePDControl, _, _ := newFakePDFailover()
+		recorder := record.NewFakeRecorder(100)
+		pdFailover.deps.Recorder = recorder
+		var hookTC *v1alpha1.TidbCluster
+		var hookPodName string
+		pdFailover.deps.FailoverPreDeleteHook = func(tc *v1alpha1.TidbCluster, podName string) error {
+			hookTC = tc
+			hookPodName = podName
+			return test.hookErr
+		}
+		deleteMemberCalled := false
+		pdClient := controller.NewFakePDClient(fakePDControl, tc)
+		pdClient.AddReaction(pdapi.DeleteMemberByIDActionType, func(action *pdapi.Action) (interface{}, error) {
+			deleteMemberCalled = true
+			return nil, nil
+		})
+		pod := newPodForPDFailover(tc, v1alpha1.PDMemberType, 1)
+		g.Expect(podIndexer.Add(pod)).To(Succeed())
+
+		err := pdFailover.Failover(tc)
+		g.Expect(hookTC).To(Equal(tc))
+		g.Expect(hookPodName).To(Equal(pd1Name))
+		events := collectEvents(recorder.Events)
+		if test.expectVetoed {
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			g.Expect(deleteMemberCalled).To(BeFalse())
+			g.Expect(tc.Status.PD.FailureMembers[pd1Name].MemberDeleted).To(BeFalse())
+			_, err = pdFailover.deps.PodLister.Pods(metav1.NamespaceDefault).Get(pod.Name)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(events).To(HaveLen(1))
+			g.Expect(events[0]).To(ContainSubstring(controller.FailoverVetoed.For(v1alpha1.PDMemberType)))
+			g.Expect(events[0]).To(ContainSubstring("member is protected by policy"))
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(deleteMemberCalled).To(BeTrue())
+			g.Expect(tc.Status.PD.FailureMembers[pd1Name].MemberDeleted).To(BeTrue())
+			g.Expect(events).To(HaveLen(1))
+			g.Expect(events[0]).To(ContainSubstring(controller.MemberDeleted.For(v1alpha1.PDMemberType)))
+		}
+	}
+}
+
 func TestPDFailoverRecovery(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -706,6 +1238,390 @@ func TestPDFailoverRecovery(t *testing.T) {
 	}
 }
 
+func TestPDFailoverRecoveryCooldown(t *testing.T) {
+	tests := []struct {
+		name          string
+		cooldown      time.Duration
+		recoveredAgo  time.Duration
+		expectFailure bool
+	}{
+		{
+			name:          "unhealthy right after the recovery",
+			cooldown:      5 * time.Minute,
+			expectFailure: false,
+		},
+		{
+			name:          "unhealthy after the cooldown",
+			cooldown:      5 * time.Minute,
+			recoveredAgo:  10 * time.Minute,
+			expectFailure: true,
+		},
+		{
+			name:          "no cooldown",
+			cooldown:      0,
+			expectFailure: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tc := newTidbClusterForPD()
+			tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+			tc.Status.PD.Synced = true
+			oneFailureMember(tc)
+			pd1 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+
+			pdFailover, pvcIndexer, podIndexer, _, _, _ := newFakePDFailover()
+			pdFailover.deps.CLIConfig.PDFailoverRecoveryCooldown = tt.cooldown
+			recorder := pdFailover.deps.Recorder.(*record.FakeRecorder)
+			g.Expect(podIndexer.Add(newPodWithPVCForPDFailover(tc, v1alpha1.PDMemberType, 1))).To(Succeed())
+			g.Expect(pvcIndexer.Add(newPVCForPDFailover(tc, v1alpha1.PDMemberType, 1))).To(Succeed())
+
+			pdFailover.Recover(tc)
+			g.Expect(tc.Status.PD.FailureMembers).To(BeEmpty())
+			if tt.cooldown > 0 {
+				g.Expect(tc.Status.PD.RecoveredMembers).To(HaveKey(pd1))
+				tc.Status.PD.RecoveredMembers[pd1] = metav1.NewTime(tc.Status.PD.RecoveredMembers[pd1].Add(-tt.recoveredAgo))
+			} else {
+				g.Expect(tc.Status.PD.RecoveredMembers).To(BeEmpty())
+			}
+
+			// the recovered member flaps again
+			oneNotReadyMember(tc)
+			err := pdFailover.Failover(tc)
+			events := collectEvents(recorder.Events)
+			if tt.expectFailure {
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+				g.Expect(tc.Status.PD.FailureMembers).To(HaveKey(pd1))
+				g.Expect(tc.Status.PD.RecoveredMembers).To(BeNil())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(tc.Status.PD.FailureMembers).To(BeEmpty())
+				g.Expect(tc.Status.PD.RecoveredMembers).To(HaveKey(pd1))
+				g.Expect(events).To(HaveLen(2))
+				g.Expect(events[0]).To(ContainSubstring(controller.MemberUnhealthy.For(v1alpha1.PDMemberType)))
+				g.Expect(events[1]).To(ContainSubstring(corev1.EventTypeNormal))
+				g.Expect(events[1]).To(ContainSubstring(controller.FailoverCooldown.For(v1alpha1.PDMemberType)))
+			}
+		})
+	}
+}
+
+func TestPDFailoverProtectedMember(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+	tc.Spec.PD.ProtectedMemberIDs = []string{"12891273174085095651"}
+	tc.Status.PD.Synced = true
+	oneNotReadyMember(tc)
+
+	pdFailover, pvcIndexer, podIndexer, _, _, _ := newFakePDFailover()
+	recorder := pdFailover.deps.Recorder.(*record.FakeRecorder)
+	g.Expect(podIndexer.Add(newPodWithPVCForPDFailover(tc, v1alpha1.PDMemberType, 1))).To(Succeed())
+	g.Expect(pvcIndexer.Add(newPVCForPDFailover(tc, v1alpha1.PDMemberType, 1))).To(Succeed())
+
+	err := pdFailover.Failover(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Status.PD.FailureMembers).To(BeEmpty())
+	events := collectEvents(recorder.Events)
+	g.Expect(events).To(HaveLen(2))
+	g.Expect(events[0]).To(ContainSubstring(controller.MemberUnhealthy.For(v1alpha1.PDMemberType)))
+	g.Expect(events[1]).To(ContainSubstring(corev1.EventTypeWarning))
+	g.Expect(events[1]).To(ContainSubstring(controller.MemberProtected.For(v1alpha1.PDMemberType)))
+
+	// the member is failed over once it's no longer protected
+	tc.Spec.PD.ProtectedMemberIDs = nil
+	err = pdFailover.Failover(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(tc.Status.PD.FailureMembers).To(HaveKey(ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)))
+}
+
+func TestPDFailoverProtectedFailureMember(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+	tc.Status.PD.Synced = true
+	oneFailureMember(tc)
+	// the member is protected after it's marked as a failure member
+	tc.Spec.PD.ProtectedMemberIDs = []string{"12891273174085095651"}
+	pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+
+	pdFailover, pvcIndexer, podIndexer, fakePDControl, podControl, _ := newFakePDFailover()
+	recorder := pdFailover.deps.Recorder.(*record.FakeRecorder)
+	deleteMemberCalled := false
+	pdClient := controller.NewFakePDClient(fakePDControl, tc)
+	pdClient.AddReaction(pdapi.DeleteMemberByIDActionType, func(action *pdapi.Action) (interface{}, error) {
+		deleteMemberCalled = true
+		return nil, nil
+	})
+	g.Expect(podIndexer.Add(newPodForPDFailover(tc, v1alpha1.PDMemberType, 1))).To(Succeed())
+	g.Expect(pvcIndexer.Add(newPVCForPDFailover(tc, v1alpha1.PDMemberType, 1))).To(Succeed())
+
+	g.Expect(pdFailover.Failover(tc)).To(Succeed())
+	g.Expect(deleteMemberCalled).To(BeFalse())
+	g.Expect(podControl.DeleteGracePeriods).To(BeEmpty())
+	g.Expect(tc.Status.PD.FailureMembers[pd1Name].MemberDeleted).To(BeFalse())
+	events := collectEvents(recorder.Events)
+	g.Expect(events).To(ContainElement(ContainSubstring(controller.MemberProtected.For(v1alpha1.PDMemberType))))
+}
+
+func TestPDFailoverClusterHealthFloor(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	downStores := func(tc *v1alpha1.TidbCluster) {
+		tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
+		for _, id := range []string{"1", "2", "3"} {
+			tc.Status.TiKV.Stores[id] = v1alpha1.TiKVStore{ID: id, State: v1alpha1.TiKVStateDown}
+		}
+	}
+
+	// no new failure member is marked below the floor
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+	tc.Status.PD.Synced = true
+	oneNotReadyMember(tc)
+	downStores(tc)
+	pdFailover, pvcIndexer, podIndexer, fakePDControl, _, _ := newFakePDFailover()
+	pdFailover.deps.CLIConfig.FailoverMinHealthyRatio = 0.6
+	g.Expect(podIndexer.Add(newPodWithPVCForPDFailover(tc, v1alpha1.PDMemberType, 1))).To(Succeed())
+	g.Expect(pvcIndexer.Add(newPVCForPDFailover(tc, v1alpha1.PDMemberType, 1))).To(Succeed())
+	err := pdFailover.Failover(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(tc.Status.PD.FailureMembers).To(BeEmpty())
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, controller.ConditionTypeFor(v1alpha1.FailoverBlocked, v1alpha1.PDMemberType))
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Reason).To(Equal(utiltidbcluster.ClusterHealthBelowFloor))
+
+	// the failure member marked already is still deleted
+	tc = newTidbClusterForPD()
+	tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+	tc.Status.PD.Synced = true
+	oneFailureMember(tc)
+	downStores(tc)
+	pdClient := controller.NewFakePDClient(fakePDControl, tc)
+	pdClient.AddReaction(pdapi.DeleteMemberByIDActionType, func(action *pdapi.Action) (interface{}, error) {
+		return nil, nil
+	})
+	g.Expect(pdFailover.Failover(tc)).To(Succeed())
+	g.Expect(tc.Status.PD.FailureMembers[ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)].MemberDeleted).To(BeTrue())
+}
+
+func TestPDFailoverNodeNotReady(t *testing.T) {
+	tests := []struct {
+		name          string
+		nodeExist     bool
+		nodeReady     corev1.ConditionStatus
+		notReadyAgo   time.Duration
+		expectFailure bool
+		expectEvent   bool
+	}{
+		{
+			name:          "node is NotReady",
+			nodeExist:     true,
+			nodeReady:     corev1.ConditionUnknown,
+			notReadyAgo:   time.Minute,
+			expectFailure: false,
+			expectEvent:   true,
+		},
+		{
+			name:          "node is NotReady for longer than the timeout",
+			nodeExist:     true,
+			nodeReady:     corev1.ConditionFalse,
+			notReadyAgo:   10 * time.Minute,
+			expectFailure: true,
+		},
+		{
+			name:          "node is Ready",
+			nodeExist:     true,
+			nodeReady:     corev1.ConditionTrue,
+			notReadyAgo:   time.Minute,
+			expectFailure: true,
+		},
+		{
+			name:          "node is deleted",
+			nodeExist:     false,
+			expectFailure: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tc := newTidbClusterForPD()
+			tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+			tc.Status.PD.Synced = true
+			oneNotReadyMember(tc)
+			pd1 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+
+			pdFailover, pvcIndexer, podIndexer, _, _, _ := newFakePDFailover()
+			pdFailover.deps.CLIConfig.PDNodeNotReadyTimeout = 5 * time.Minute
+			recorder := pdFailover.deps.Recorder.(*record.FakeRecorder)
+			pod := newPodWithPVCForPDFailover(tc, v1alpha1.PDMemberType, 1)
+			pod.Spec.NodeName = "node-1"
+			g.Expect(podIndexer.Add(pod)).To(Succeed())
+			g.Expect(pvcIndexer.Add(newPVCForPDFailover(tc, v1alpha1.PDMemberType, 1))).To(Succeed())
+			if tt.nodeExist {
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+					Status: corev1.NodeStatus{
+						Conditions: []corev1.NodeCondition{
+							{
+								Type:               corev1.NodeReady,
+								Status:             tt.nodeReady,
+								LastTransitionTime: metav1.NewTime(time.Now().Add(-tt.notReadyAgo)),
+							},
+						},
+					},
+				}
+				nodeIndexer := pdFailover.deps.KubeInformerFactory.Core().V1().Nodes().Informer().GetIndexer()
+				g.Expect(nodeIndexer.Add(node)).To(Succeed())
+			}
+
+			err := pdFailover.Failover(tc)
+			g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			if tt.expectFailure {
+				g.Expect(tc.Status.PD.FailureMembers).To(HaveKey(pd1))
+			} else {
+				g.Expect(tc.Status.PD.FailureMembers).To(BeEmpty())
+			}
+			events := collectEvents(recorder.Events)
+			if tt.expectEvent {
+				g.Expect(events).To(HaveLen(2))
+				g.Expect(events[0]).To(ContainSubstring(controller.MemberUnhealthy.For(v1alpha1.PDMemberType)))
+				g.Expect(events[1]).To(ContainSubstring(corev1.EventTypeWarning))
+				g.Expect(events[1]).To(ContainSubstring("PDMemberNodeNotReady"))
+			} else {
+				g.Expect(events).NotTo(ContainElement(ContainSubstring(controller.MemberNodeNotReady.For(v1alpha1.PDMemberType))))
+			}
+		})
+	}
+}
+
+func TestPDFailoverTriggered(t *testing.T) {
+	tests := []struct {
+		name          string
+		members       func(tc *v1alpha1.TidbCluster)
+		triggered     int32
+		expectFailure bool
+		expectReason  controller.EventReason
+	}{
+		{
+			name:          "fail over a healthy member",
+			members:       allMembersReady,
+			triggered:     1,
+			expectFailure: true,
+			expectReason:  controller.FailoverTriggered,
+		},
+		{
+			name:         "refuse to lose quorum",
+			members:      oneNotReadyMember,
+			triggered:    0,
+			expectReason: controller.FailoverVetoed,
+		},
+		{
+			name:         "refuse an unknown member",
+			members:      allMembersReady,
+			triggered:    5,
+			expectReason: controller.FailoverVetoed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tc := newTidbClusterForPD()
+			tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+			tc.Status.PD.Synced = true
+			tt.members(tc)
+			podName := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), tt.triggered)
+			tc.Annotations = map[string]string{label.AnnPDTriggerFailoverKey: podName}
+
+			pdFailover, pvcIndexer, podIndexer, _, _, _ := newFakePDFailover()
+			recorder := pdFailover.deps.Recorder.(*record.FakeRecorder)
+			stored, err := pdFailover.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+			g.Expect(err).NotTo(HaveOccurred())
+			// the annotation added meanwhile is kept
+			stored.Annotations["test"] = "kept"
+			_, err = pdFailover.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Update(context.TODO(), stored, metav1.UpdateOptions{})
+			g.Expect(err).NotTo(HaveOccurred())
+			for ordinal := int32(0); ordinal < 3; ordinal++ {
+				g.Expect(podIndexer.Add(newPodWithPVCForPDFailover(tc, v1alpha1.PDMemberType, ordinal))).To(Succeed())
+				g.Expect(pvcIndexer.Add(newPVCForPDFailover(tc, v1alpha1.PDMemberType, ordinal))).To(Succeed())
+			}
+
+			err = pdFailover.Failover(tc)
+			if tt.expectFailure {
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+				g.Expect(tc.Status.PD.FailureMembers).To(HaveLen(1))
+				g.Expect(tc.Status.PD.FailureMembers).To(HaveKey(podName))
+				g.Expect(tc.Status.PD.FailureMembers[podName].Triggered).To(BeTrue())
+				g.Expect(tc.Status.PD.FailureMembers[podName].MemberDeleted).To(BeFalse())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(tc.Status.PD.FailureMembers).To(BeEmpty())
+			}
+			events := collectEvents(recorder.Events)
+			g.Expect(strings.Join(events, "\n")).To(ContainSubstring(tt.expectReason.For(v1alpha1.PDMemberType)))
+
+			// the annotation is consumed
+			g.Expect(tc.Annotations).NotTo(HaveKey(label.AnnPDTriggerFailoverKey))
+			updated, err := pdFailover.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Get(context.TODO(), tc.Name, metav1.GetOptions{})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(updated.Annotations).NotTo(HaveKey(label.AnnPDTriggerFailoverKey))
+			g.Expect(updated.Annotations).To(HaveKeyWithValue("test", "kept"))
+		})
+	}
+}
+
+func TestPDFailoverWebhook(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	notifications := make(chan FailoverNotification, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification FailoverNotification
+		if err := json.NewDecoder(r.Body).Decode(&notification); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		notifications <- notification
+	}))
+	defer server.Close()
+
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+	tc.Status.PD.Synced = true
+	oneNotReadyMember(tc)
+	pd1 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+
+	pdFailover, pvcIndexer, podIndexer, _, _, _ := newFakePDFailover()
+	pdFailover.deps.CLIConfig.FailoverWebhookURL = server.URL
+	g.Expect(podIndexer.Add(newPodWithPVCForPDFailover(tc, v1alpha1.PDMemberType, 1))).To(Succeed())
+	g.Expect(pvcIndexer.Add(newPVCForPDFailover(tc, v1alpha1.PDMemberType, 1))).To(Succeed())
+
+	before := time.Now()
+	err := pdFailover.Failover(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(tc.Status.PD.FailureMembers).To(HaveKey(pd1))
+
+	var notification FailoverNotification
+	g.Eventually(notifications, 5*time.Second).Should(Receive(&notification))
+	g.Expect(notification.Cluster).To(Equal(fmt.Sprintf("%s/%s", tc.GetNamespace(), tc.GetName())))
+	g.Expect(notification.Component).To(Equal(v1alpha1.PDMemberType))
+	g.Expect(notification.Member).To(Equal(pd1))
+	g.Expect(notification.Action).To(Equal(FailoverActionMarkFailure))
+	g.Expect(notification.Timestamp).To(BeTemporally(">=", before.Truncate(time.Second)))
+
+	// the unreachable webhook doesn't block the failover
+	server.Close()
+	tc.Status.PD.FailureMembers = nil
+	err = pdFailover.Failover(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(tc.Status.PD.FailureMembers).To(HaveKey(pd1))
+}
+
 func newFakePDFailover() (*pdFailover, cache.Indexer, cache.Indexer, *pdapi.FakePDControl, *controller.FakePodControl, *controller.FakePVCControl) {
 	fakeDeps := controller.NewFakeDependencies()
 	pdFailover := &pdFailover{deps: fakeDeps}
@@ -813,6 +1729,404 @@ func twoMembersNotReadyWithPeerMembers(tc *v1alpha1.TidbCluster) {
 	}
 }
 
+func TestPDFailoverDuplicatedMemberID(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+	tc.Status.PD.Synced = true
+	oneNotReadyMember(tc)
+	pd1 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+	pd2 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 2)
+	member := tc.Status.PD.Members[pd2]
+	member.ID = tc.Status.PD.Members[pd1].ID
+	tc.Status.PD.Members[pd2] = member
+
+	pdFailover, pvcIndexer, podIndexer, _, _, _ := newFakePDFailover()
+	recorder := pdFailover.deps.Recorder.(*record.FakeRecorder)
+	g.Expect(podIndexer.Add(newPodWithPVCForPDFailover(tc, v1alpha1.PDMemberType, 1))).To(Succeed())
+	g.Expect(pvcIndexer.Add(newPVCForPDFailover(tc, v1alpha1.PDMemberType, 1))).To(Succeed())
+
+	// the failover is blocked while the member id is duplicated
+	err := pdFailover.Failover(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("member id 12891273174085095651 is reported by %s, %s", pd1, pd2)))
+	g.Expect(tc.Status.PD.FailureMembers).To(BeEmpty())
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, controller.ConditionTypeFor(v1alpha1.FailoverBlocked, v1alpha1.PDMemberType))
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(cond.Reason).To(Equal(utiltidbcluster.PDMemberIDDuplicated))
+	events := collectEvents(recorder.Events)
+	g.Expect(events).To(ContainElement(ContainSubstring("Warning PDFailoverBlocked")))
+
+	_, err = pdFailover.PlanFailover(tc)
+	g.Expect(err).To(HaveOccurred())
+
+	// the failover proceeds once the duplication is resolved
+	member.ID = "2"
+	tc.Status.PD.Members[pd2] = member
+	err = pdFailover.Failover(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(tc.Status.PD.FailureMembers).To(HaveKey(pd1))
+	cond = utiltidbcluster.GetTidbClusterCondition(tc.Status, controller.ConditionTypeFor(v1alpha1.FailoverBlocked, v1alpha1.PDMemberType))
+	g.Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+	events = collectEvents(recorder.Events)
+	g.Expect(events).To(ContainElement(ContainSubstring(utiltidbcluster.Resolved)))
+}
+
+func TestDuplicatedPDMemberIDs(t *testing.T) {
+	tests := []struct {
+		name        string
+		members     map[string]v1alpha1.PDMember
+		peerMembers map[string]v1alpha1.PDMember
+		expect      string
+	}{
+		{
+			name: "unique ids",
+			members: map[string]v1alpha1.PDMember{
+				"pd-0": {Name: "pd-0", ID: "1"},
+				"pd-1": {Name: "pd-1", ID: "2"},
+			},
+			peerMembers: map[string]v1alpha1.PDMember{
+				"peer-pd-0": {Name: "peer-pd-0", ID: "3"},
+			},
+			expect: "",
+		},
+		{
+			name: "empty ids are ignored",
+			members: map[string]v1alpha1.PDMember{
+				"pd-0": {Name: "pd-0"},
+				"pd-1": {Name: "pd-1"},
+			},
+			expect: "",
+		},
+		{
+			name: "the same member in the members and the peer members",
+			members: map[string]v1alpha1.PDMember{
+				"pd-0": {Name: "pd-0", ID: "1"},
+			},
+			peerMembers: map[string]v1alpha1.PDMember{
+				"pd-0": {Name: "pd-0", ID: "1"},
+			},
+			expect: "",
+		},
+		{
+			name: "duplicated across the members and the peer members",
+			members: map[string]v1alpha1.PDMember{
+				"pd-0": {Name: "pd-0", ID: "1"},
+				"pd-1": {Name: "pd-1", ID: "2"},
+			},
+			peerMembers: map[string]v1alpha1.PDMember{
+				"peer-pd-0": {Name: "peer-pd-0", ID: "2"},
+			},
+			expect: "member id 2 is reported by pd-1, peer-pd-0",
+		},
+		{
+			name: "more than one duplicated ids",
+			members: map[string]v1alpha1.PDMember{
+				"pd-0": {Name: "pd-0", ID: "1"},
+				"pd-1": {Name: "pd-1", ID: "2"},
+				"pd-2": {Name: "pd-2", ID: "1"},
+				"pd-3": {Name: "pd-3", ID: "2"},
+			},
+			expect: "member id 1 is reported by pd-0, pd-2; member id 2 is reported by pd-1, pd-3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tc := newTidbClusterForPD()
+			tc.Status.PD.Members = tt.members
+			tc.Status.PD.PeerMembers = tt.peerMembers
+			g.Expect(duplicatedPDMemberIDs(tc)).To(Equal(tt.expect))
+		})
+	}
+}
+
+func TestPDFailoverUnhealthyPDMembers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name        string
+		update      func(*v1alpha1.TidbCluster)
+		expectNames []int32
+	}
+	tests := []testcase{
+		{name: "all members are ready", update: allMembersReady},
+		{name: "one member is not ready", update: oneNotReadyMember, expectNames: []int32{1}},
+		{name: "one not ready member is a failure member", update: oneNotReadyMemberAndAFailureMember, expectNames: []int32{1}},
+		{name: "lastTransitionTime is zero", update: twoMembersNotReady},
+		{
+			name: "mixed health",
+			update: func(tc *v1alpha1.TidbCluster) {
+				pd0 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 0)
+				pd1 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+				pd2 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 2)
+				pd3 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 3)
+				tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+					pd0: {Name: pd0, ID: "0", Health: false, LastTransitionTime: metav1.Time{Time: time.Now().Add(-10 * time.Minute)}},
+					// not exceed deadline
+					pd1: {Name: pd1, ID: "1", Health: false, LastTransitionTime: metav1.Time{Time: time.Now().Add(-1 * time.Minute)}},
+					pd2: {Name: pd2, ID: "2", Health: false, LastTransitionTime: metav1.Time{Time: time.Now().Add(-20 * time.Minute)}},
+					// not desired
+					pd3: {Name: pd3, ID: "3", Health: false, LastTransitionTime: metav1.Time{Time: time.Now().Add(-20 * time.Minute)}},
+				}
+			},
+			expectNames: []int32{0, 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		test.update(tc)
+		failureMembers := len(tc.Status.PD.FailureMembers)
+		pdFailover, _, _, _, _, _ := newFakePDFailover()
+
+		var expectNames []string
+		for _, ordinal := range test.expectNames {
+			expectNames = append(expectNames, ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), ordinal))
+		}
+		g.Expect(pdFailover.unhealthyPDMembers(tc)).To(Equal(expectNames))
+		// no side effects
+		g.Expect(tc.Status.PD.FailureMembers).To(HaveLen(failureMembers))
+		g.Expect(collectEvents(pdFailover.deps.Recorder.(*record.FakeRecorder).Events)).To(BeEmpty())
+	}
+}
+
+func TestPDFailoverNextFailoverCandidate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	twoMembersUnhealthy := func(tc *v1alpha1.TidbCluster) {
+		pd0 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 0)
+		pd1 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+		pd2 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 2)
+		tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+			pd0: {Name: pd0, ID: "0", Health: false, LastTransitionTime: metav1.Time{Time: time.Now().Add(-10 * time.Minute)}},
+			pd1: {Name: pd1, ID: "1", Health: true},
+			pd2: {Name: pd2, ID: "2", Health: false, LastTransitionTime: metav1.Time{Time: time.Now().Add(-20 * time.Minute)}},
+		}
+	}
+
+	type testcase struct {
+		name   string
+		update func(*v1alpha1.TidbCluster)
+		// expectOrdinal is the ordinal of the candidate, -1 if there is no candidate
+		expectOrdinal int32
+	}
+	tests := []testcase{
+		{name: "all members are ready", update: allMembersReady, expectOrdinal: -1},
+		{name: "one member is not ready", update: oneNotReadyMember, expectOrdinal: 1},
+		{name: "one not ready member is a failure member", update: oneNotReadyMemberAndAFailureMember, expectOrdinal: -1},
+		{
+			name: "one not ready member is protected",
+			update: func(tc *v1alpha1.TidbCluster) {
+				oneNotReadyMember(tc)
+				tc.Spec.PD.ProtectedMemberIDs = []string{"12891273174085095651"}
+			},
+			expectOrdinal: -1,
+		},
+		{name: "the lowest ordinal of the unhealthy members", update: twoMembersUnhealthy, expectOrdinal: 0},
+		{
+			name: "the lowest ordinal is in cooldown",
+			update: func(tc *v1alpha1.TidbCluster) {
+				twoMembersUnhealthy(tc)
+				tc.Status.PD.RecoveredMembers = map[string]metav1.Time{
+					ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 0): metav1.Now(),
+				}
+			},
+			expectOrdinal: 2,
+		},
+		{
+			name: "the lowest ordinal has an invalid member id",
+			update: func(tc *v1alpha1.TidbCluster) {
+				twoMembersUnhealthy(tc)
+				pd0 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 0)
+				member := tc.Status.PD.Members[pd0]
+				member.ID = "invalid"
+				tc.Status.PD.Members[pd0] = member
+			},
+			expectOrdinal: 2,
+		},
+		{
+			name: "the lowest ordinal is a failure member",
+			update: func(tc *v1alpha1.TidbCluster) {
+				twoMembersUnhealthy(tc)
+				pd0 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 0)
+				tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{
+					pd0: {PodName: pd0, MemberID: "0"},
+				}
+			},
+			expectOrdinal: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		test.update(tc)
+		pdFailover, _, _, _, _, _ := newFakePDFailover()
+		pdFailover.deps.CLIConfig.PDFailoverRecoveryCooldown = 5 * time.Minute
+
+		pdName, ok := pdFailover.nextFailoverCandidate(tc)
+		if test.expectOrdinal < 0 {
+			g.Expect(ok).To(BeFalse())
+		} else {
+			g.Expect(ok).To(BeTrue())
+			g.Expect(pdName).To(Equal(ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), test.expectOrdinal)))
+		}
+		// the selection is deterministic and has no side effects
+		for i := 0; i < 10; i++ {
+			name, _ := pdFailover.nextFailoverCandidate(tc)
+			g.Expect(name).To(Equal(pdName))
+		}
+		g.Expect(collectEvents(pdFailover.deps.Recorder.(*record.FakeRecorder).Events)).To(BeEmpty())
+	}
+}
+
+func TestPDFailoverPlanFailover(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name          string
+		update        func(*v1alpha1.TidbCluster)
+		expectActions []FailoverActionType
+	}
+	tests := []testcase{
+		{name: "all members are ready", update: allMembersReady},
+		{
+			name:          "one not ready member, exceeded deadline",
+			update:        oneNotReadyMember,
+			expectActions: []FailoverActionType{FailoverActionMarkFailure},
+		},
+		{
+			name:   "one failure member",
+			update: oneNotReadyMemberAndAFailureMember,
+			expectActions: []FailoverActionType{
+				FailoverActionDeleteMember,
+				FailoverActionDeletePod,
+				FailoverActionDeletePVC,
+				FailoverActionDeletePVC,
+			},
+		},
+		{
+			name: "one failure member in PodOnly mode",
+			update: func(tc *v1alpha1.TidbCluster) {
+				oneNotReadyMemberAndAFailureMember(tc)
+				tc.Spec.PD.FailoverMode = v1alpha1.FailoverModePodOnly
+				for name, member := range tc.Status.PD.FailureMembers {
+					member.CreatedAt = metav1.Now()
+					tc.Status.PD.FailureMembers[name] = member
+				}
+			},
+			expectActions: []FailoverActionType{FailoverActionDeletePod},
+		},
+	}
+
+	for _, test := range tests {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+		tc.Status.PD.Synced = true
+		test.update(tc)
+		status := tc.Status.DeepCopy()
+
+		pdFailover, pvcIndexer, podIndexer, _, _, _ := newFakePDFailover()
+		pod := newPodForPDFailover(tc, v1alpha1.PDMemberType, 1)
+		pod.CreationTimestamp = metav1.Time{Time: time.Now().Add(-time.Hour)}
+		for i := 1; i <= 2; i++ {
+			pvc := newPVCForPDFailover(tc, v1alpha1.PDMemberType, 1)
+			pvc.Name = fmt.Sprintf("%s-%d", pvc.Name, i)
+			pvc.UID = types.UID(fmt.Sprintf("%s-%d", pvc.UID, i))
+			pvc.Labels[label.AnnPodNameKey] = pod.GetName()
+			pvcIndexer.Add(pvc)
+		}
+		podIndexer.Add(pod)
+
+		actions, err := pdFailover.PlanFailover(tc)
+		g.Expect(err).NotTo(HaveOccurred())
+		var actionTypes []FailoverActionType
+		for _, action := range actions {
+			actionTypes = append(actionTypes, action.Type)
+			g.Expect(action.PodName).To(Equal(pod.GetName()))
+			g.Expect(action.MemberID).To(Equal("12891273174085095651"))
+		}
+		g.Expect(actionTypes).To(Equal(test.expectActions))
+		if len(actions) == 4 {
+			g.Expect(actions[2].PVCName).To(Equal(ordinalPVCName(v1alpha1.PDMemberType, controller.PDMemberName(tc.GetName()), 1) + "-1"))
+			g.Expect(actions[3].PVCName).To(Equal(ordinalPVCName(v1alpha1.PDMemberType, controller.PDMemberName(tc.GetName()), 1) + "-2"))
+		}
+
+		// nothing is executed
+		g.Expect(tc.Status).To(Equal(*status))
+		g.Expect(collectEvents(pdFailover.deps.Recorder.(*record.FakeRecorder).Events)).To(BeEmpty())
+		_, err = pdFailover.deps.PodLister.Pods(pod.GetNamespace()).Get(pod.GetName())
+		g.Expect(err).NotTo(HaveOccurred())
+		pvcs, err := pdFailover.deps.PVCLister.PersistentVolumeClaims(pod.GetNamespace()).List(labels.Everything())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(pvcs).To(HaveLen(2))
+	}
+
+	// the failover is refused
+	tc := newTidbClusterForPD()
+	tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+	twoMembersNotReady(tc)
+	pdFailover, _, _, _, _, _ := newFakePDFailover()
+	_, err := pdFailover.PlanFailover(tc)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("Synced = false"))
+	tc.Status.PD.Synced = true
+	_, err = pdFailover.PlanFailover(tc)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("not healthy"))
+	g.Expect(tc.Status.Conditions).To(BeEmpty())
+
+	// the plan is refused by the same gates as the failover
+	tc = newTidbClusterForPD()
+	tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
+	tc.Status.PD.Synced = true
+	oneNotReadyMember(tc)
+	pd2Member := tc.Status.PD.Members[ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 2)]
+	pd2Member.ID = tc.Status.PD.Members[ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 0)].ID
+	tc.Status.PD.Members[pd2Member.Name] = pd2Member
+	_, planErr := pdFailover.PlanFailover(tc)
+	g.Expect(planErr).To(HaveOccurred())
+	err = pdFailover.Failover(tc)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(planErr.Error()).To(Equal(err.Error()))
+}
+
+func TestNotDeletedFailureMember(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{
+		"test-pd-10": {PodName: "test-pd-10"},
+		"test-pd-2":  {PodName: "test-pd-2"},
+		"test-pd-1":  {PodName: "test-pd-1", MemberDeleted: true},
+	}
+	pdName, failureMember := notDeletedFailureMember(tc)
+	g.Expect(pdName).To(Equal("test-pd-2"))
+	g.Expect(failureMember.PodName).To(Equal("test-pd-2"))
+}
+
+func TestSortedPDMemberNames(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	members := map[string]v1alpha1.PDMember{}
+	for _, name := range []string{"test-pd-10", "test-pd-2", "test-pd-0.test-pd-peer.default.svc", "pd-in-another-cluster", "test-pd-1"} {
+		members[name] = v1alpha1.PDMember{Name: name}
+	}
+	g.Expect(sortedPDMemberNames(members)).To(Equal([]string{
+		"test-pd-0.test-pd-peer.default.svc",
+		"test-pd-1",
+		"test-pd-2",
+		"test-pd-10",
+		"pd-in-another-cluster",
+	}))
+}
+
 func errExpectNil(g *GomegaWithT, err error) {
 	g.Expect(err).NotTo(HaveOccurred())
 }
@@ -848,6 +2162,22 @@ func newPodForPDFailover(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberTyp
 	}
 }
 
+// newPodWithPVCForPDFailover returns the pod mounting the PVC returned by newPVCForPDFailover
+func newPodWithPVCForPDFailover(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, ordinal int32) *corev1.Pod {
+	pod := newPodForPDFailover(tc, memberType, ordinal)
+	pod.Spec.Volumes = []corev1.Volume{
+		{
+			Name: memberType.String(),
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: ordinalPVCName(memberType, controller.PDMemberName(tc.GetName()), ordinal),
+				},
+			},
+		},
+	}
+	return pod
+}
+
 func collectEvents(source <-chan string) []string {
 	done := false
 	events := make([]string, 0)
@@ -69,8 +69,9 @@ func NewPDMemberManager(dependencies *controller.Dependencies, pdScaler Scaler,
 }
 
 func (m *pdMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
-	// If pd is not specified return
+	// If pd is not specified, only check the external pd endpoints
 	if tc.Spec.PD == nil {
+		m.syncExternalPDEndpoints(tc)
 		return nil
 	}
 
@@ -89,7 +90,7 @@ func (m *pdMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 }
 
 func (m *pdMemberManager) syncPDServiceForTidbCluster(tc *v1alpha1.TidbCluster) error {
-	if tc.Spec.Paused {
+	if tc.ShouldPauseReconcile(v1alpha1.PDMemberType) {
 		klog.V(4).Infof("tidb cluster %s/%s is paused, skip syncing for pd service", tc.GetNamespace(), tc.GetName())
 		return nil
 	}
@@ -98,6 +99,7 @@ func (m *pdMemberManager) syncPDServiceForTidbCluster(tc *v1alpha1.TidbCluster)
 	tcName := tc.GetName()
 
 	newSvc := m.getNewPDServiceForTidbCluster(tc)
+	addStandardLabels(newSvc)
 	oldSvcTmp, err := m.deps.ServiceLister.Services(ns).Get(controller.PDMemberName(tcName))
 	if errors.IsNotFound(err) {
 		err = controller.SetServiceLastAppliedConfigAnnotation(newSvc)
@@ -116,8 +118,10 @@ func (m *pdMemberManager) syncPDServiceForTidbCluster(tc *v1alpha1.TidbCluster)
 	if err != nil {
 		return err
 	}
-	if !equal {
+	svcLabels, labelsChanged := mergeStandardLabels(newSvc, oldSvc)
+	if !equal || labelsChanged {
 		svc := *oldSvc
+		svc.Labels = svcLabels
 		svc.Spec = newSvc.Spec
 		// TODO add unit test
 		err = controller.SetServiceLastAppliedConfigAnnotation(&svc)
@@ -133,7 +137,7 @@ func (m *pdMemberManager) syncPDServiceForTidbCluster(tc *v1alpha1.TidbCluster)
 }
 
 func (m *pdMemberManager) syncPDHeadlessServiceForTidbCluster(tc *v1alpha1.TidbCluster) error {
-	if tc.Spec.Paused {
+	if tc.ShouldPauseReconcile(v1alpha1.PDMemberType) {
 		klog.V(4).Infof("tidb cluster %s/%s is paused, skip syncing for pd headless service", tc.GetNamespace(), tc.GetName())
 		return nil
 	}
@@ -142,6 +146,7 @@ func (m *pdMemberManager) syncPDHeadlessServiceForTidbCluster(tc *v1alpha1.TidbC
 	tcName := tc.GetName()
 
 	newSvc := getNewPDHeadlessServiceForTidbCluster(tc)
+	addStandardLabels(newSvc)
 	oldSvc, err := m.deps.ServiceLister.Services(ns).Get(controller.PDPeerMemberName(tcName))
 	if errors.IsNotFound(err) {
 		err = controller.SetServiceLastAppliedConfigAnnotation(newSvc)
@@ -158,8 +163,10 @@ func (m *pdMemberManager) syncPDHeadlessServiceForTidbCluster(tc *v1alpha1.TidbC
 	if err != nil {
 		return err
 	}
-	if !equal {
+	svcLabels, labelsChanged := mergeStandardLabels(newSvc, oldSvc)
+	if !equal || labelsChanged {
 		svc := *oldSvc
+		svc.Labels = svcLabels
 		svc.Spec = newSvc.Spec
 		err = controller.SetServiceLastAppliedConfigAnnotation(&svc)
 		if err != nil {
@@ -188,7 +195,7 @@ func (m *pdMemberManager) syncPDStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 		klog.Errorf("failed to sync TidbCluster: [%s/%s]'s status, error: %v", ns, tcName, err)
 	}
 
-	if tc.Spec.Paused {
+	if tc.ShouldPauseReconcile(v1alpha1.PDMemberType) {
 		klog.V(4).Infof("tidb cluster %s/%s is paused, skip syncing for pd statefulset", tc.GetNamespace(), tc.GetName())
 		return nil
 	}
@@ -201,6 +208,7 @@ func (m *pdMemberManager) syncPDStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 	if err != nil {
 		return err
 	}
+	addStandardLabelsToStatefulSet(newPDSet)
 	if setNotExist {
 		err = SetStatefulSetLastAppliedConfigAnnotation(newPDSet)
 		if err != nil {
@@ -233,7 +241,7 @@ func (m *pdMemberManager) syncPDStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 	if m.deps.CLIConfig.AutoFailover {
 		if m.shouldRecover(tc) {
 			m.failover.Recover(tc)
-		} else if tc.PDAllPodsStarted() && !tc.PDAllMembersReady() || tc.PDAutoFailovering() {
+		} else if tc.PDAllPodsStarted() && !tc.PDAllMembersReady() || tc.PDAutoFailovering() || m.failoverTriggered(tc) {
 			if err := m.failover.Failover(tc); err != nil {
 				return err
 			}
@@ -254,6 +262,12 @@ func (m *pdMemberManager) shouldRecover(tc *v1alpha1.TidbCluster) bool {
 	if tc.Status.PD.FailureMembers == nil {
 		return false
 	}
+	// The members marked as failure on demand may be healthy, they are failed over anyway.
+	for _, failureMember := range tc.Status.PD.FailureMembers {
+		if failureMember.Triggered && !failureMember.MemberDeleted {
+			return false
+		}
+	}
 	// If all desired replicas (excluding failover pods) of tidb cluster are
 	// healthy, we can perform our failover recovery operation.
 	// Note that failover pods may fail (e.g. lack of resources) and we don't care
@@ -285,6 +299,23 @@ func (m *pdMemberManager) shouldRecover(tc *v1alpha1.TidbCluster) bool {
 	return true
 }
 
+// failoverTriggered checks whether a failover is requested on demand by the annotation,
+// the failover is needed even if all members are ready.
+func (m *pdMemberManager) failoverTriggered(tc *v1alpha1.TidbCluster) bool {
+	_, triggered := tc.PDFailoverTriggered()
+	return triggered && tc.PDAllPodsStarted()
+}
+
+// markPDStatusUnsynced marks the PD status not synced, the time since when it has failed to be synced is kept,
+// so that the status is not changed in every sync while the PD cluster is down
+func markPDStatusUnsynced(tc *v1alpha1.TidbCluster) {
+	tc.Status.PD.Synced = false
+	if tc.Status.PD.UnsyncedSince == nil {
+		now := metav1.Now()
+		tc.Status.PD.UnsyncedSince = &now
+	}
+}
+
 func (m *pdMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set *apps.StatefulSet) error {
 	if set == nil {
 		// skip if not created yet
@@ -314,7 +345,7 @@ func (m *pdMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set *a
 
 	healthInfo, err := pdClient.GetHealth()
 	if err != nil {
-		tc.Status.PD.Synced = false
+		markPDStatusUnsynced(tc)
 		// get endpoints info
 		eps, epErr := m.deps.EndpointLister.Endpoints(ns).Get(controller.PDMemberName(tcName))
 		if epErr != nil {
@@ -329,13 +360,13 @@ func (m *pdMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set *a
 
 	cluster, err := pdClient.GetCluster()
 	if err != nil {
-		tc.Status.PD.Synced = false
+		markPDStatusUnsynced(tc)
 		return err
 	}
 	tc.Status.ClusterID = strconv.FormatUint(cluster.Id, 10)
 	leader, err := pdClient.GetPDLeader()
 	if err != nil {
-		tc.Status.PD.Synced = false
+		markPDStatusUnsynced(tc)
 		return err
 	}
 
@@ -387,6 +418,7 @@ func (m *pdMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set *a
 	}
 
 	tc.Status.PD.Synced = true
+	tc.Status.PD.UnsyncedSince = nil
 	tc.Status.PD.Members = pdStatus
 	tc.Status.PD.PeerMembers = peerPDStatus
 	tc.Status.PD.Image = ""
@@ -407,10 +439,16 @@ func (m *pdMemberManager) syncPDConfigMap(tc *v1alpha1.TidbCluster, set *apps.St
 	if tc.Spec.PD.Config == nil {
 		return nil, nil
 	}
-	newCm, err := getPDConfigMap(tc)
+	// the ConfigMap is only rendered again after what it's rendered from changes
+	cmKey := renderedConfigMapKey(tc.Namespace, controller.PDMemberName(tc.Name))
+	cmSpec := []interface{}{tc.UID, tc.Labels, tc.Spec, tc.SkipTLSWhenConnectTiDB()}
+	newCm, err := m.deps.ConfigMapControl.RenderConfigMap(cmKey, cmSpec, func() (*corev1.ConfigMap, error) {
+		return getPDConfigMap(tc)
+	})
 	if err != nil {
 		return nil, err
 	}
+	addStandardLabels(newCm)
 
 	var inUseName string
 	if set != nil {
@@ -572,6 +610,10 @@ func getNewPDSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (
 			Name: "tidb-client-tls", ReadOnly: true, MountPath: tidbClientCertPath,
 		})
 	}
+	discoveryTLSMount, discoveryTLSVolume := discoveryTLSMountVolume(tc)
+	if tc.IsDiscoveryTLSEnabled() {
+		volMounts = append(volMounts, discoveryTLSMount)
+	}
 
 	vols := []corev1.Volume{
 		annVolume,
@@ -627,6 +669,9 @@ func getNewPDSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (
 			},
 		})
 	}
+	if tc.IsDiscoveryTLSEnabled() {
+		vols = append(vols, discoveryTLSVolume)
+	}
 	// handle StorageVolumes and AdditionalVolumeMounts in ComponentSpec
 	storageVolMounts, additionalPVCs := util.BuildStorageVolumeAndVolumeMount(tc.Spec.PD.StorageVolumes, tc.Spec.PD.StorageClassName, v1alpha1.PDMemberType)
 	volMounts = append(volMounts, storageVolMounts...)
@@ -842,9 +887,10 @@ func getPDConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 		return nil, err
 	}
 	startScript, err := RenderPDStartScript(&PDStartScriptModel{
-		Scheme:        tc.Scheme(),
-		DataDir:       filepath.Join(pdDataVolumeMountPath, tc.Spec.PD.DataSubDir),
-		ClusterDomain: tc.Spec.ClusterDomain,
+		DiscoveryStartScriptModel: DiscoveryStartScriptModel{DiscoveryTLSEnabled: tc.IsDiscoveryTLSEnabled()},
+		Scheme:                    tc.Scheme(),
+		DataDir:                   filepath.Join(pdDataVolumeMountPath, tc.Spec.PD.DataSubDir),
+		ClusterDomain:             tc.Spec.ClusterDomain,
 	})
 	if err != nil {
 		return nil, err
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
@@ -432,6 +433,23 @@ func TestPDMemberManagerSyncUpdate(t *testing.T) {
 	}
 }
 
+func TestMarkPDStatusUnsynced(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Status.PD.Synced = true
+	markPDStatusUnsynced(tc)
+	g.Expect(tc.Status.PD.Synced).To(BeFalse())
+	g.Expect(tc.Status.PD.UnsyncedSince).NotTo(BeNil())
+
+	// the time since when the status has failed to be synced is kept in the later syncs
+	since := metav1.NewTime(time.Now().Add(-time.Hour))
+	tc.Status.PD.UnsyncedSince = &since
+	status := tc.Status.PD.DeepCopy()
+	markPDStatusUnsynced(tc)
+	g.Expect(tc.Status.PD).To(Equal(*status))
+}
+
 func TestPDMemberManagerPdStatefulSetIsUpgrading(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
@@ -1781,6 +1799,36 @@ func TestGetNewPDSetForTidbCluster(t *testing.T) {
 				}))
 			},
 		},
+		{
+			name: "PD requesting the discovery service with TLS",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					PD:        &v1alpha1.PDSpec{},
+					TiKV:      &v1alpha1.TiKVSpec{},
+					TiDB:      &v1alpha1.TiDBSpec{},
+					Discovery: v1alpha1.DiscoverySpec{TLSSecret: "discovery-tls"},
+				},
+			},
+			testSts: func(sts *apps.StatefulSet) {
+				g := NewGomegaWithT(t)
+				g.Expect(sts.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+					Name: "discovery-tls",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: "discovery-tls",
+							Items:      []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+						},
+					},
+				}))
+				g.Expect(sts.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+					Name: "discovery-tls", ReadOnly: true, MountPath: "/var/lib/discovery-tls",
+				}))
+			},
+		},
 		// TODO add more tests
 	}
 
@@ -17,9 +17,11 @@ import (
 	"fmt"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/util"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -103,6 +105,10 @@ func (s *pdScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSet
 		return nil
 	}
 
+	if pass := s.preCheckQuorum(tc, memberName, pdPodName); !pass {
+		return nil
+	}
+
 	pdClient := controller.GetPDClient(s.deps.PDControl, tc)
 	leader, err := pdClient.GetPDLeader()
 	if err != nil {
@@ -127,6 +133,7 @@ func (s *pdScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSet
 			if err != nil {
 				return err
 			}
+			return controller.RequeueErrorf("tc[%s/%s]'s pd pod[%s/%s] is transferring pd leader,can't scale-in now", ns, tcName, ns, memberName)
 		} else {
 			for _, member := range tc.Status.PD.PeerMembers {
 				if member.Health && member.Name != memberName {
@@ -147,6 +154,17 @@ func (s *pdScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSet
 	}
 	klog.Infof("pdScaler.ScaleIn: delete member %s successfully", memberName)
 
+	// wait for the member to be removed from the pd cluster before deferring deleting the PVCs
+	members, err := pdClient.GetMembers()
+	if err != nil {
+		return err
+	}
+	for _, member := range members.Members {
+		if member.Name == memberName || member.Name == pdPodName {
+			return controller.RequeueErrorf("tc[%s/%s]'s pd member %s is still in the pd cluster, wait for it to be removed", ns, tcName, memberName)
+		}
+	}
+
 	pod, err := s.deps.PodLister.Pods(ns).Get(pdPodName)
 	if err != nil {
 		return fmt.Errorf("pdScaler.ScaleIn: failed to get pod %s/%s for pd in tc %s/%s, error: %s", ns, pdPodName, ns, tcName, err)
@@ -190,6 +208,59 @@ func (s *pdScaler) preCheckUpMembers(tc *v1alpha1.TidbCluster, podName string) b
 	return true
 }
 
+// preCheckQuorum checks whether the pd cluster keeps its quorum after the member is removed.
+// The removal must be committed by a quorum of the current members, and the rest members
+// must still form a quorum of the new membership.
+func (s *pdScaler) preCheckQuorum(tc *v1alpha1.TidbCluster, memberName, podName string) bool {
+	if tc.Annotations[label.AnnForceScaleInPDKey] == label.AnnForceScaleInPDVal {
+		klog.Warningf("skip the quorum check of scaling in pd member %s of TidbCluster [%s/%s] by annotation %s", memberName, tc.GetNamespace(), tc.GetName(), label.AnnForceScaleInPDKey)
+		return true
+	}
+
+	total, healthy := 0, 0
+	exist, removingHealthy := false, false
+	for _, members := range []map[string]v1alpha1.PDMember{tc.Status.PD.Members, tc.Status.PD.PeerMembers} {
+		for name, member := range members {
+			total++
+			if member.Health {
+				healthy++
+			}
+			if name == memberName || name == podName {
+				exist = true
+				removingHealthy = member.Health
+			}
+		}
+	}
+	// the member has been removed from the pd cluster
+	if !exist {
+		return true
+	}
+
+	remaining := healthy
+	if removingHealthy {
+		remaining--
+	}
+	var errMsg string
+	if quorum := total/2 + 1; healthy < quorum {
+		errMsg = fmt.Sprintf("can't scale in PD of TidbCluster [%s/%s], cause only %d of %d members are healthy, less than the quorum %d, podname %s", tc.GetNamespace(), tc.GetName(), healthy, total, quorum, podName)
+	} else if quorum := (total-1)/2 + 1; total > 1 && remaining < quorum {
+		errMsg = fmt.Sprintf("can't scale in PD of TidbCluster [%s/%s], cause only %d healthy members would be left, less than the quorum %d of %d members, podname %s", tc.GetNamespace(), tc.GetName(), remaining, quorum, total-1, podName)
+	}
+	if len(errMsg) > 0 {
+		klog.Error(errMsg)
+		s.deps.Recorder.Event(tc, v1.EventTypeWarning, "FailedScaleIn", errMsg)
+		cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.PDScaleInBlocked, v1.ConditionTrue, utiltidbcluster.PDQuorumUnsafe, errMsg)
+		utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+		return false
+	}
+
+	if cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.PDScaleInBlocked); cond != nil && cond.Status == v1.ConditionTrue {
+		newCond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.PDScaleInBlocked, v1.ConditionFalse, utiltidbcluster.PDQuorumSafe, fmt.Sprintf("pd member %s can be removed safely", memberName))
+		utiltidbcluster.SetTidbClusterCondition(&tc.Status, *newCond)
+	}
+	return true
+}
+
 type fakePDScaler struct{}
 
 // NewFakePDScaler returns a fake Scaler
@@ -24,11 +24,13 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 )
 
@@ -212,6 +214,7 @@ func TestPDScalerScaleIn(t *testing.T) {
 
 	testFn := func(test testcase, t *testing.T) {
 		tc := newTidbClusterForPD()
+		normalPDMember(tc)
 
 		if test.pdUpgrading {
 			tc.Status.PD.Phase = v1alpha1.UpgradePhase
@@ -268,6 +271,16 @@ func TestPDScalerScaleIn(t *testing.T) {
 			}
 			return &leader, nil
 		})
+		pdClient.AddReaction(pdapi.GetMembersActionType, func(action *pdapi.Action) (interface{}, error) {
+			return &pdapi.MembersInfo{
+				Members: []*pdpb.Member{
+					{Name: PdPodName(tc.GetName(), 0)},
+					{Name: PdPodName(tc.GetName(), 1)},
+					{Name: PdPodName(tc.GetName(), 2)},
+					{Name: PdPodName(tc.GetName(), 3)},
+				},
+			}, nil
+		})
 
 		if test.deleteMemberErr {
 			pdClient.AddReaction(pdapi.DeleteMemberActionType, func(action *pdapi.Action) (interface{}, error) {
@@ -381,6 +394,249 @@ func TestPDScalerScaleIn(t *testing.T) {
 	}
 }
 
+func TestPDScalerScaleInQuorum(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type step struct {
+		replicas    int32
+		unhealthy   []int32
+		leader      int32
+		memberExist bool
+		errExpectFn func(*GomegaWithT, error)
+		calls       []string
+		changed     bool
+		blocked     bool
+	}
+	type testcase struct {
+		name        string
+		annotations map[string]string
+		desired     int32
+		steps       []step
+	}
+
+	testFn := func(test testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		tc.Annotations = test.annotations
+		tc.Status.PD.Synced = true
+		scaler, pdControl, pvcIndexer, podIndexer, _ := newFakePDScaler()
+		recorder := scaler.deps.Recorder.(*record.FakeRecorder)
+
+		for _, st := range test.steps {
+			oldSet := newStatefulSetForPDScale()
+			oldSet.Spec.Replicas = pointer.Int32Ptr(st.replicas)
+			newSet := oldSet.DeepCopy()
+			newSet.Spec.Replicas = pointer.Int32Ptr(test.desired)
+
+			tc.Status.PD.Members = map[string]v1alpha1.PDMember{}
+			members := []*pdpb.Member{}
+			for i := int32(0); i < st.replicas; i++ {
+				name := PdPodName(tc.GetName(), i)
+				tc.Status.PD.Members[name] = v1alpha1.PDMember{Name: name, Health: true}
+				if i < st.replicas-1 || st.memberExist {
+					members = append(members, &pdpb.Member{Name: name})
+				}
+			}
+			for _, i := range st.unhealthy {
+				name := PdPodName(tc.GetName(), i)
+				tc.Status.PD.Members[name] = v1alpha1.PDMember{Name: name, Health: false}
+			}
+
+			pvc := _newPVCForStatefulSet(oldSet, v1alpha1.PDMemberType, tc.Name, st.replicas-1)
+			pvcIndexer.Add(pvc)
+			pod := &corev1.Pod{
+				TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      PdPodName(tc.GetName(), st.replicas-1),
+					Namespace: corev1.NamespaceDefault,
+				},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: pvc.Name,
+								},
+							},
+						},
+					},
+				},
+			}
+			podIndexer.Add(pod)
+
+			var calls []string
+			pdClient := controller.NewFakePDClient(pdControl, tc)
+			pdClient.AddReaction(pdapi.GetPDLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+				return &pdpb.Member{Name: PdPodName(tc.GetName(), st.leader)}, nil
+			})
+			pdClient.AddReaction(pdapi.TransferPDLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+				calls = append(calls, "TransferPDLeader "+action.Name)
+				return nil, nil
+			})
+			pdClient.AddReaction(pdapi.DeleteMemberActionType, func(action *pdapi.Action) (interface{}, error) {
+				calls = append(calls, "DeleteMember "+action.Name)
+				return nil, nil
+			})
+			pdClient.AddReaction(pdapi.GetMembersActionType, func(action *pdapi.Action) (interface{}, error) {
+				return &pdapi.MembersInfo{Members: members}, nil
+			})
+
+			err := scaler.Scale(tc, oldSet, newSet)
+			st.errExpectFn(g, err)
+			g.Expect(calls).To(Equal(st.calls))
+			if st.changed {
+				g.Expect(*newSet.Spec.Replicas).To(Equal(st.replicas - 1))
+			} else {
+				g.Expect(*newSet.Spec.Replicas).To(Equal(st.replicas))
+			}
+
+			events := collectEvents(recorder.Events)
+			cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.PDScaleInBlocked)
+			if st.blocked {
+				g.Expect(events).To(HaveLen(1))
+				g.Expect(events[0]).To(ContainSubstring("FailedScaleIn"))
+				g.Expect(cond).NotTo(BeNil())
+				g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+			} else {
+				g.Expect(events).To(HaveLen(0))
+				if cond != nil {
+					g.Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+				}
+			}
+
+			pvc, err = scaler.deps.PVCLister.PersistentVolumeClaims(corev1.NamespaceDefault).Get(pvc.Name)
+			g.Expect(err).NotTo(HaveOccurred())
+			_, deferDeleting := pvc.Annotations[label.AnnPVCDeferDeleting]
+			g.Expect(deferDeleting).To(Equal(st.changed))
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:    "scale in from 3 to 1 is split into two steps",
+			desired: 1,
+			steps: []step{
+				{
+					replicas:    3,
+					errExpectFn: errExpectNil,
+					calls:       []string{"DeleteMember test-pd-2"},
+					changed:     true,
+				},
+				{
+					replicas:    2,
+					errExpectFn: errExpectNil,
+					calls:       []string{"DeleteMember test-pd-1"},
+					changed:     true,
+				},
+			},
+		},
+		{
+			name:    "scale in is blocked when it breaks the quorum",
+			desired: 2,
+			steps: []step{
+				{
+					replicas:    3,
+					unhealthy:   []int32{0},
+					errExpectFn: errExpectNil,
+					changed:     false,
+					blocked:     true,
+				},
+				{
+					replicas:    3,
+					errExpectFn: errExpectNil,
+					calls:       []string{"DeleteMember test-pd-2"},
+					changed:     true,
+				},
+			},
+		},
+		{
+			name:    "scale in is blocked when the quorum is lost",
+			desired: 2,
+			steps: []step{
+				{
+					replicas:    3,
+					unhealthy:   []int32{0, 1},
+					errExpectFn: errExpectNil,
+					changed:     false,
+					blocked:     true,
+				},
+			},
+		},
+		{
+			name:    "remove an unhealthy member",
+			desired: 2,
+			steps: []step{
+				{
+					replicas:    3,
+					unhealthy:   []int32{2},
+					errExpectFn: errExpectNil,
+					calls:       []string{"DeleteMember test-pd-2"},
+					changed:     true,
+				},
+			},
+		},
+		{
+			name: "skip the quorum check by annotation",
+			annotations: map[string]string{
+				label.AnnForceScaleInPDKey: label.AnnForceScaleInPDVal,
+			},
+			desired: 2,
+			steps: []step{
+				{
+					replicas:    3,
+					unhealthy:   []int32{0},
+					errExpectFn: errExpectNil,
+					calls:       []string{"DeleteMember test-pd-2"},
+					changed:     true,
+				},
+			},
+		},
+		{
+			name:    "transfer leader before deleting the member",
+			desired: 2,
+			steps: []step{
+				{
+					replicas:    3,
+					leader:      2,
+					errExpectFn: errExpectRequeue,
+					calls:       []string{"TransferPDLeader test-pd-0"},
+					changed:     false,
+				},
+				{
+					replicas:    3,
+					leader:      0,
+					errExpectFn: errExpectNil,
+					calls:       []string{"DeleteMember test-pd-2"},
+					changed:     true,
+				},
+			},
+		},
+		{
+			name:    "wait for the member to be removed from the pd cluster",
+			desired: 2,
+			steps: []step{
+				{
+					replicas:    3,
+					memberExist: true,
+					errExpectFn: errExpectRequeue,
+					calls:       []string{"DeleteMember test-pd-2"},
+					changed:     false,
+				},
+				{
+					replicas:    3,
+					errExpectFn: errExpectNil,
+					calls:       []string{"DeleteMember test-pd-2"},
+					changed:     true,
+				},
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(tests[i], t)
+	}
+}
+
 func TestPDScalerScaleInBlockByOtherComponents(t *testing.T) {
 	// check if PD scale in is blocked when other components are using PD
 	g := NewGomegaWithT(t)
@@ -87,7 +87,7 @@ func (m *pumpMemberManager) syncPumpStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 		return err
 	}
 
-	if tc.Spec.Paused {
+	if tc.ShouldPauseReconcile(v1alpha1.PumpMemberType) {
 		klog.V(4).Infof("tikv cluster %s/%s is paused, skip syncing for pump statefulset", tc.GetNamespace(), tc.GetName())
 		return nil
 	}
@@ -101,6 +101,7 @@ func (m *pumpMemberManager) syncPumpStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 	if err != nil {
 		return err
 	}
+	addStandardLabelsToStatefulSet(newSet)
 	if notFound {
 		err = SetStatefulSetLastAppliedConfigAnnotation(newSet)
 		if err != nil {
@@ -109,6 +110,8 @@ func (m *pumpMemberManager) syncPumpStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 		return m.deps.StatefulSetControl.CreateStatefulSet(tc, newSet)
 	}
 
+	syncPodManagementPolicy(tc, newSet, oldSet)
+
 	if err := m.scaler.Scale(tc, oldSet, newSet); err != nil {
 		return err
 	}
@@ -191,12 +194,13 @@ func (m *pumpMemberManager) syncTiDBClusterStatus(tc *v1alpha1.TidbCluster, set
 }
 
 func (m *pumpMemberManager) syncHeadlessService(tc *v1alpha1.TidbCluster) error {
-	if tc.Spec.Paused {
+	if tc.ShouldPauseReconcile(v1alpha1.PumpMemberType) {
 		klog.V(4).Infof("tikv cluster %s/%s is paused, skip syncing for pump headless service", tc.GetNamespace(), tc.GetName())
 		return nil
 	}
 
 	newSvc := getNewPumpHeadlessService(tc)
+	addStandardLabels(newSvc)
 	oldSvc, err := m.deps.ServiceLister.Services(newSvc.Namespace).Get(newSvc.Name)
 	if errors.IsNotFound(err) {
 		err = controller.SetServiceLastAppliedConfigAnnotation(newSvc)
@@ -215,8 +219,10 @@ func (m *pumpMemberManager) syncHeadlessService(tc *v1alpha1.TidbCluster) error
 	}
 	isOrphan := metav1.GetControllerOf(oldSvc) == nil
 
-	if !equal || isOrphan {
+	svcLabels, labelsChanged := mergeStandardLabels(newSvc, oldSvc)
+	if !equal || labelsChanged || isOrphan {
 		svc := *oldSvc
+		svc.Labels = svcLabels
 		svc.Spec = newSvc.Spec
 		err = controller.SetServiceLastAppliedConfigAnnotation(&svc)
 		if err != nil {
@@ -240,6 +246,7 @@ func (m *pumpMemberManager) syncConfigMap(tc *v1alpha1.TidbCluster, set *appsv1.
 	if err != nil {
 		return nil, err
 	}
+	addStandardLabels(newCm)
 
 	var inUseName string
 	if set != nil {
@@ -367,6 +374,10 @@ func getNewPumpStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*app
 			Name: pumpCertVolumeMount, ReadOnly: true, MountPath: pumpCertPath,
 		})
 	}
+	discoveryTLSMount, discoveryTLSVolume := discoveryTLSMountVolume(tc)
+	if tc.IsDiscoveryTLSEnabled() {
+		volumeMounts = append(volumeMounts, discoveryTLSMount)
+	}
 	containers := []corev1.Container{
 		{
 			Name:            "pump",
@@ -423,6 +434,9 @@ func getNewPumpStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*app
 			},
 		})
 	}
+	if tc.IsDiscoveryTLSEnabled() {
+		volumes = append(volumes, discoveryTLSVolume)
+	}
 
 	volumeClaims := []corev1.PersistentVolumeClaim{
 		{
@@ -473,6 +487,7 @@ func getNewPumpStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*app
 			UpdateStrategy: apps.StatefulSetUpdateStrategy{
 				Type: spec.StatefulSetUpdateStrategy(),
 			},
+			PodManagementPolicy: spec.PodManagementPolicy(),
 		},
 	}, nil
 }
@@ -497,11 +512,12 @@ func getPumpStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 	}
 
 	return RenderPumpStartScript(&PumpStartScriptModel{
-		Scheme:        scheme,
-		ClusterName:   tc.Name,
-		LogLevel:      getPumpLogLevel(tc),
-		ClusterDomain: tc.Spec.ClusterDomain,
-		Namespace:     tc.GetNamespace(),
+		DiscoveryStartScriptModel: DiscoveryStartScriptModel{DiscoveryTLSEnabled: tc.IsDiscoveryTLSEnabled()},
+		Scheme:                    scheme,
+		ClusterName:               tc.Name,
+		LogLevel:                  getPumpLogLevel(tc),
+		ClusterDomain:             tc.Spec.ClusterDomain,
+		Namespace:                 tc.GetNamespace(),
 	})
 }
 
@@ -42,6 +42,7 @@ const (
 	skipReasonPVCCleanerPVCHasBeenDeleted        = "pvc cleaner: pvc has been deleted"
 	skipReasonPVCCleanerPVCNotFound              = "pvc cleaner: not found pvc from apiserver"
 	skipReasonPVCCleanerPVCChanged               = "pvc cleaner: pvc changed before deletion"
+	skipReasonPVCCleanerPaused                   = "pvc cleaner: the reconciliation of the component is paused"
 )
 
 // PVCCleaner implements the logic for cleaning the pvc related resource
@@ -101,6 +102,11 @@ func (c *realPVCCleaner) reclaimPV(meta metav1.Object) (map[string]string, error
 			continue
 		}
 
+		if componentPaused(meta, l) {
+			skipReason[pvcName] = skipReasonPVCCleanerPaused
+			continue
+		}
+
 		if pvc.Status.Phase != corev1.ClaimBound {
 			// If pvc is not bound yet, it will not be processed
 			skipReason[pvcName] = skipReasonPVCCleanerPVCNotBound
@@ -220,6 +226,11 @@ func (c *realPVCCleaner) cleanScheduleLock(meta metav1.Object) (map[string]strin
 			continue
 		}
 
+		if componentPaused(meta, l) {
+			skipReason[pvcName] = skipReasonPVCCleanerPaused
+			continue
+		}
+
 		if pvc.Annotations[label.AnnPVCDeferDeleting] != "" {
 			if _, exist := pvc.Annotations[label.AnnPVCPodScheduling]; !exist {
 				// The defer deleting PVC without pod scheduling annotation, do nothing
@@ -94,9 +94,10 @@ func (p *pvcResizer) Resize(tc *v1alpha1.TidbCluster) error {
 	// for example "pd-${tcName}-pd" (for tc.Spec.PD.Requests) or "pd-log-${tcName}-pd" (for tc.Spec.PD.storageVolumes elements with name "log").
 	// Reference implementation of BuildStorageVolumeAndVolumeMount().
 	// Note: for TiFlash, it is currently "data0-${tcName}-tiflash" (for tc.Spec.TiFlash.StorageClaims elements, in list definition order)
+	// The PVCs of the paused components are not patched.
 
 	// patch PD PVCs
-	if tc.Spec.PD != nil {
+	if tc.Spec.PD != nil && !tc.ShouldPauseReconcile(v1alpha1.PDMemberType) {
 		pvcPrefix2Quantity := make(map[string]resource.Quantity)
 		pdMemberType := v1alpha1.PDMemberType.String()
 		if quantity, ok := tc.Spec.PD.Requests[corev1.ResourceStorage]; ok {
@@ -116,7 +117,7 @@ func (p *pvcResizer) Resize(tc *v1alpha1.TidbCluster) error {
 		}
 	}
 	// patch TiDB PVCs
-	if tc.Spec.TiDB != nil {
+	if tc.Spec.TiDB != nil && !tc.ShouldPauseReconcile(v1alpha1.TiDBMemberType) {
 		pvcPrefix2Quantity := make(map[string]resource.Quantity)
 		tidbMemberType := v1alpha1.TiDBMemberType.String()
 		for _, sv := range tc.Spec.TiDB.StorageVolumes {
@@ -132,7 +133,7 @@ func (p *pvcResizer) Resize(tc *v1alpha1.TidbCluster) error {
 		}
 	}
 	// patch TiKV PVCs
-	if tc.Spec.TiKV != nil {
+	if tc.Spec.TiKV != nil && !tc.ShouldPauseReconcile(v1alpha1.TiKVMemberType) {
 		pvcPrefix2Quantity := make(map[string]resource.Quantity)
 		tikvMemberType := v1alpha1.TiKVMemberType.String()
 		if quantity, ok := tc.Spec.TiKV.Requests[corev1.ResourceStorage]; ok {
@@ -152,7 +153,7 @@ func (p *pvcResizer) Resize(tc *v1alpha1.TidbCluster) error {
 		}
 	}
 	// patch TiFlash PVCs
-	if tc.Spec.TiFlash != nil {
+	if tc.Spec.TiFlash != nil && !tc.ShouldPauseReconcile(v1alpha1.TiFlashMemberType) {
 		pvcPrefix2Quantity := make(map[string]resource.Quantity)
 		tiflashMemberType := v1alpha1.TiFlashMemberType.String()
 		for i, claim := range tc.Spec.TiFlash.StorageClaims {
@@ -166,7 +167,7 @@ func (p *pvcResizer) Resize(tc *v1alpha1.TidbCluster) error {
 		}
 	}
 	// patch TiCDC PVCs
-	if tc.Spec.TiCDC != nil {
+	if tc.Spec.TiCDC != nil && !tc.ShouldPauseReconcile(v1alpha1.TiCDCMemberType) {
 		pvcPrefix2Quantity := make(map[string]resource.Quantity)
 		ticdcMemberType := v1alpha1.TiCDCMemberType.String()
 		for _, sv := range tc.Spec.TiCDC.StorageVolumes {
@@ -182,7 +183,7 @@ func (p *pvcResizer) Resize(tc *v1alpha1.TidbCluster) error {
 		}
 	}
 	// patch Pump PVCs
-	if tc.Spec.Pump != nil {
+	if tc.Spec.Pump != nil && !tc.ShouldPauseReconcile(v1alpha1.PumpMemberType) {
 		pvcPrefix2Quantity := make(map[string]resource.Quantity)
 		pumpMemberType := v1alpha1.PumpMemberType.String()
 		if quantity, ok := tc.Spec.Pump.Requests[corev1.ResourceStorage]; ok {
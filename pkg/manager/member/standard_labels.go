@@ -0,0 +1,114 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
+)
+
+// The standard labels (see label.StandardLabelKeys) are added to the generated objects in addition to the
+// legacy labels. Services, ConfigMaps, PVCs and the metadata of StatefulSets get them immediately, while
+// adding labels to the pod template of a StatefulSet restarts all pods, so the labels are staged and added
+// to the pod template with the next rolling update of the StatefulSet, e.g. an upgrade.
+
+// addStandardLabels adds the standard labels derived from the legacy labels of obj to obj. The labels
+// are copied first as the map may be shared with a selector, e.g. the selector of a StatefulSet.
+func addStandardLabels(obj metav1.Object) {
+	l := util.CopyStringMap(obj.GetLabels())
+	if l == nil {
+		l = map[string]string{}
+	}
+	for k, v := range label.Label(l).Standard() {
+		l[k] = v
+	}
+	obj.SetLabels(l)
+}
+
+// addStandardLabelsToStatefulSet adds the standard labels to the StatefulSet and its pod template,
+// the labels of the pod template are staged by UpdateStatefulSet if the StatefulSet exists
+func addStandardLabelsToStatefulSet(set *apps.StatefulSet) {
+	addStandardLabels(set)
+	addStandardLabels(&set.Spec.Template)
+}
+
+// mergeStandardLabels returns a copy of the labels of oldObj with the standard labels of newObj added,
+// and whether the labels are changed
+func mergeStandardLabels(newObj, oldObj metav1.Object) (map[string]string, bool) {
+	changed := false
+	l := util.CopyStringMap(oldObj.GetLabels())
+	if l == nil {
+		l = map[string]string{}
+	}
+	for _, k := range label.StandardLabelKeys {
+		v, ok := newObj.GetLabels()[k]
+		if !ok || l[k] == v {
+			continue
+		}
+		l[k] = v
+		changed = true
+	}
+	return l, changed
+}
+
+// stageStandardPodLabels removes the standard labels added to the pod template of newSet and records
+// them in the pending labels annotation of newSet if they are the only change of the pod template,
+// so that they are added with the next change of the pod template instead of restarting pods.
+func stageStandardPodLabels(newSet, oldSet *apps.StatefulSet) {
+	pending := map[string]string{}
+	template := newSet.Spec.Template.DeepCopy()
+	for _, k := range label.StandardLabelKeys {
+		v, ok := template.Labels[k]
+		if !ok {
+			continue
+		}
+		oldV, oldOK := oldSet.Spec.Template.Labels[k]
+		if oldOK && oldV == v {
+			continue
+		}
+		pending[k] = v
+		if oldOK {
+			template.Labels[k] = oldV
+		} else {
+			delete(template.Labels, k)
+		}
+	}
+	if len(pending) == 0 || !podTemplateEqual(template, oldSet) {
+		return
+	}
+
+	klog.V(4).Infof("statefulset %s/%s: stage labels %v of the pod template to the next rolling update", oldSet.Namespace, oldSet.Name, pending)
+	newSet.Spec.Template = *template
+	if newSet.Annotations == nil {
+		newSet.Annotations = map[string]string{}
+	}
+	newSet.Annotations[label.AnnPendingPodLabelsKey] = labels.Set(pending).String()
+}
+
+// podTemplateEqual compares the pod template with the last applied pod template of the StatefulSet
+func podTemplateEqual(template *corev1.PodTemplateSpec, set *apps.StatefulSet) bool {
+	spec, _, err := GetLastAppliedConfig(set)
+	if err != nil {
+		return false
+	}
+	oldTemplate := spec.Template.DeepCopy()
+	delete(oldTemplate.Annotations, LastAppliedConfigAnnotation)
+	return apiequality.Semantic.DeepEqual(*oldTemplate, *template)
+}
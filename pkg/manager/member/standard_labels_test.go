@@ -0,0 +1,131 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+)
+
+func TestUpdateStatefulSetStagesStandardPodLabels(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	setInformer := kubeinformers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 0).Apps().V1().StatefulSets()
+	setControl := controller.NewFakeStatefulSetControl(setInformer)
+
+	newSet := func(image string) *apps.StatefulSet {
+		l := label.New().Instance(tc.GetInstanceName()).PD()
+		return &apps.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-pd",
+				Namespace:       tc.Namespace,
+				Labels:          l.Copy(),
+				OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+			},
+			Spec: apps.StatefulSetSpec{
+				Replicas: pointer.Int32Ptr(3),
+				Selector: l.LabelSelector(),
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: l.Copy()},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "pd", Image: image}},
+					},
+				},
+			},
+		}
+	}
+	getSet := func() *apps.StatefulSet {
+		set, err := setInformer.Lister().StatefulSets(tc.Namespace).Get("test-pd")
+		g.Expect(err).NotTo(HaveOccurred())
+		return set.DeepCopy()
+	}
+
+	// a StatefulSet created before the standard labels are introduced
+	oldSet := newSet("pd:v1")
+	g.Expect(SetStatefulSetLastAppliedConfigAnnotation(oldSet)).To(Succeed())
+	g.Expect(setInformer.Informer().GetIndexer().Add(oldSet)).To(Succeed())
+
+	// the standard labels are added to the StatefulSet immediately and staged for the pod template
+	set := newSet("pd:v1")
+	addStandardLabelsToStatefulSet(set)
+	g.Expect(UpdateStatefulSet(setControl, tc, set, getSet())).To(Succeed())
+	updated := getSet()
+	g.Expect(updated.Labels).To(HaveKeyWithValue(label.PartOfLabelKey, "tidb-cluster"))
+	g.Expect(updated.Spec.Template.Labels).NotTo(HaveKey(label.PartOfLabelKey))
+	g.Expect(updated.Spec.Template.Spec.Containers[0].Image).To(Equal("pd:v1"))
+	g.Expect(updated.Annotations).To(HaveKeyWithValue(label.AnnPendingPodLabelsKey, "app.kubernetes.io/part-of=tidb-cluster"))
+	g.Expect(podTemplateEqual(&oldSet.Spec.Template, updated)).To(BeTrue())
+
+	// nothing changes in the following syncs
+	set = newSet("pd:v1")
+	addStandardLabelsToStatefulSet(set)
+	g.Expect(UpdateStatefulSet(setControl, tc, set, getSet())).To(Succeed())
+	g.Expect(getSet()).To(Equal(updated))
+
+	// the staged labels are added to the pod template with an unrelated upgrade
+	set = newSet("pd:v2")
+	addStandardLabelsToStatefulSet(set)
+	g.Expect(UpdateStatefulSet(setControl, tc, set, getSet())).To(Succeed())
+	updated = getSet()
+	g.Expect(updated.Labels).To(HaveKeyWithValue(label.PartOfLabelKey, "tidb-cluster"))
+	g.Expect(updated.Spec.Template.Labels).To(HaveKeyWithValue(label.PartOfLabelKey, "tidb-cluster"))
+	g.Expect(updated.Spec.Template.Spec.Containers[0].Image).To(Equal("pd:v2"))
+	g.Expect(updated.Annotations).NotTo(HaveKey(label.AnnPendingPodLabelsKey))
+}
+
+func TestMergeStandardLabels(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	newSvc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Labels: label.New().Instance("demo").PD().Labels()}}
+	addStandardLabels(newSvc)
+	g.Expect(newSvc.Labels).To(HaveKeyWithValue(label.PartOfLabelKey, "tidb-cluster"))
+
+	oldSvc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"foo": "bar"}}}
+	labels, changed := mergeStandardLabels(newSvc, oldSvc)
+	g.Expect(changed).To(BeTrue())
+	g.Expect(labels).To(Equal(map[string]string{"foo": "bar", label.PartOfLabelKey: "tidb-cluster"}))
+	g.Expect(oldSvc.Labels).NotTo(HaveKey(label.PartOfLabelKey))
+
+	oldSvc.Labels = labels
+	_, changed = mergeStandardLabels(newSvc, oldSvc)
+	g.Expect(changed).To(BeFalse())
+}
+
+func TestAddStandardLabelsToStatefulSetKeepsSelector(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// the labels of the StatefulSet, the selector and the pod template share the same map
+	l := label.NewDM().Instance("demo").DMMaster().Labels()
+	set := &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Labels: l},
+		Spec: apps.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: l},
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: l}},
+		},
+	}
+	addStandardLabelsToStatefulSet(set)
+	g.Expect(set.Labels).To(HaveKeyWithValue(label.PartOfLabelKey, "dm-cluster"))
+	g.Expect(set.Spec.Template.Labels).To(HaveKeyWithValue(label.PartOfLabelKey, "dm-cluster"))
+	g.Expect(set.Spec.Selector.MatchLabels).NotTo(HaveKey(label.PartOfLabelKey))
+}
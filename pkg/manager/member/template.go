@@ -19,6 +19,27 @@ import (
 	"text/template"
 )
 
+// discoveryTLSCAPath is the path of the CA to verify the certificate served by the discovery service
+const discoveryTLSCAPath = "/var/lib/discovery-tls/ca.crt"
+
+// DiscoveryStartScriptModel is embedded in the models of the start scripts requesting the discovery service
+type DiscoveryStartScriptModel struct {
+	// DiscoveryTLSEnabled is whether the discovery service serves HTTPS
+	DiscoveryTLSEnabled bool
+}
+
+// DiscoveryWget returns the wget command to request the discovery service at ${discovery_url}
+func (m DiscoveryStartScriptModel) DiscoveryWget() string {
+	return discoveryWget(m.DiscoveryTLSEnabled)
+}
+
+func discoveryWget(tlsEnabled bool) string {
+	if tlsEnabled {
+		return "wget -qO- -T 3 --ca-certificate=" + discoveryTLSCAPath + " https://${discovery_url}"
+	}
+	return "wget -qO- -T 3 http://${discovery_url}"
+}
+
 // TODO(aylei): it is hard to maintain script in go literal, we should figure out a better solution
 // tidbStartScriptTpl is the template string of tidb start script
 // Note: changing this will cause a rolling-update of tidb-servers
@@ -53,7 +74,7 @@ POD_NAME=${POD_NAME:-$HOSTNAME}{{ if .FormatClusterDomain }}
 pd_url="{{ .Path }}"
 encoded_domain_url=$(echo $pd_url | base64 | tr "\n" " " | sed "s/ //g")
 discovery_url="${CLUSTER_NAME}-discovery.${NAMESPACE}:10261"
-until result=$(wget -qO- -T 3 http://${discovery_url}/verify/${encoded_domain_url} 2>/dev/null | sed 's/http:\/\///g'); do
+until result=$({{ .DiscoveryWget }}/verify/${encoded_domain_url} 2>/dev/null | sed 's/http:\/\///g'); do
 echo "waiting for the verification of PD endpoints ..."
 sleep $((RANDOM % 5))
 done
@@ -91,6 +112,8 @@ exec /tidb-server ${ARGS}
 `))
 
 type TidbStartScriptModel struct {
+	DiscoveryStartScriptModel
+
 	EnablePlugin    bool
 	PluginDirectory string
 	PluginList      string
@@ -179,6 +202,19 @@ ARGS="--data-dir={{ .DataDir }} \
 --config=/etc/pd/pd.toml \
 "
 
+if [[ -d {{ .DataDir }}/member/wal && -f {{ .DataDir }}/member_id ]]
+then
+# The previous member in the data dir may be removed from the PD cluster, e.g. by scaling in,
+# purge the data dir to join as a new member if so. Start with the data dir as usual if the
+# discovery service can not tell, e.g. the PD cluster is not available.
+member_id=` + "`" + `cat {{ .DataDir }}/member_id` + "`" + `
+if result=$({{ .DiscoveryWget }}/new/v2/${encoded_domain_url}?member-id=${member_id} 2>/dev/null) && echo "${result}" | grep -q '"purge": *true'
+then
+echo "previous member ${member_id} is removed from the pd cluster, purging the data dir ..."
+rm -rf {{ .DataDir }}/member {{ .DataDir }}/join {{ .DataDir }}/member_id
+fi
+fi
+
 if [[ -f {{ .DataDir }}/join ]]
 then
 # The content of the join file is:
@@ -190,13 +226,23 @@ join=${join%,}
 ARGS="${ARGS} --join=${join}"
 elif [[ ! -d {{ .DataDir }}/member/wal ]]
 then
-until result=$(wget -qO- -T 3 http://${discovery_url}/new/${encoded_domain_url} 2>/dev/null); do
+until result=$({{ .DiscoveryWget }}/new/${encoded_domain_url} 2>/dev/null); do
 echo "waiting for discovery service to return start args ..."
 sleep $((RANDOM % 5))
 done
 ARGS="${ARGS}${result}"
 fi
 
+# Record the ID of the member in the data dir once pd-server serves, so that the discovery service
+# can tell whether the member is removed from the PD cluster when it restarts.
+member_name={{- if .ClusterDomain }}${domain}{{- else }}${POD_NAME}{{- end }}
+(
+until member_id=$(wget -qO- -T 3 {{- if eq .Scheme "https" }} --ca-certificate=/var/lib/pd-tls/ca.crt --certificate=/var/lib/pd-tls/tls.crt --private-key=/var/lib/pd-tls/tls.key{{- end }} {{ .Scheme }}://${domain}:2379/pd/api/v1/members 2>/dev/null | tr -d ' \n' | tr '{},' '\n\n\n' | awk -F':' -v name="\"${member_name}\"" '$1 == "\"name\"" { found = ($2 == name); next } found && $1 == "\"member_id\"" { print $2; exit }') && [[ -n "${member_id}" ]]; do
+sleep 5
+done
+echo ${member_id} > {{ .DataDir }}/member_id
+) &
+
 echo "starting pd-server ..."
 sleep $((RANDOM % 10))
 echo "/pd-server ${ARGS}"
@@ -204,6 +250,8 @@ exec /pd-server ${ARGS}
 `))
 
 type PDStartScriptModel struct {
+	DiscoveryStartScriptModel
+
 	Scheme        string
 	DataDir       string
 	ClusterDomain string
@@ -254,7 +302,7 @@ pd_url="{{ .PDAddress }}"
 encoded_domain_url=$(echo $pd_url | base64 | tr "\n" " " | sed "s/ //g")
 discovery_url="${CLUSTER_NAME}-discovery.${NAMESPACE}:10261"
 
-until result=$(wget -qO- -T 3 http://${discovery_url}/verify/${encoded_domain_url} 2>/dev/null); do
+until result=$({{ .DiscoveryWget }}/verify/${encoded_domain_url} 2>/dev/null); do
 echo "waiting for the verification of PD endpoints ..."
 sleep $((RANDOM % 5))
 done
@@ -282,6 +330,8 @@ exec /tikv-server ${ARGS}
 `))
 
 type TiKVStartScriptModel struct {
+	DiscoveryStartScriptModel
+
 	EnableAdvertiseStatusAddr bool
 	AdvertiseStatusAddr       string
 	DataDir                   string
@@ -306,7 +356,7 @@ var pumpStartScriptTpl = template.Must(template.New("pump-start-script").Parse(`
 pd_url="{{ .Scheme }}://{{ .ClusterName }}-pd:2379"
 encoded_domain_url=$(echo $pd_url | base64 | tr "\n" " " | sed "s/ //g")
 discovery_url="{{ .ClusterName }}-discovery.{{ .Namespace }}:10261"
-until result=$(wget -qO- -T 3 http://${discovery_url}/verify/${encoded_domain_url} 2>/dev/null); do
+until result=$({{ .DiscoveryWget }}/verify/${encoded_domain_url} 2>/dev/null); do
 echo "waiting for the verification of PD endpoints ..."
 sleep $((RANDOM % 5))
 done
@@ -332,6 +382,8 @@ if [ $? == 0 ]; then
 fi`))
 
 type PumpStartScriptModel struct {
+	DiscoveryStartScriptModel
+
 	Scheme        string
 	ClusterName   string
 	LogLevel      string
@@ -363,13 +415,21 @@ var tidbInitStartScriptTpl = template.Must(template.New("tidb-init-start-script"
 host = '{{ .ClusterName }}-tidb'
 permit_host = '{{ .PermitHost }}'
 port = 4000
+root_password = ''
+{{- if and .SQLFiles .PasswordSet }}
+# the password of root is set by the last job if only the SQL files are executed
+if os.environ.get('INIT_SQL_FILES_ONLY') == 'true' and os.path.exists('/etc/tidb/password/root'):
+    with open('/etc/tidb/password/root', 'r') as f:
+        lines = f.read().splitlines()
+        root_password = lines[0] if len(lines) > 0 else ''
+{{- end }}
 retry_count = 0
 for i in range(0, 10):
     try:
 {{- if .TLS }}
-        conn = MySQLdb.connect(host=host, port=port, user='root', charset='utf8mb4',connect_timeout=5, ssl={'ca': '{{ .CAPath }}', 'cert': '{{ .CertPath }}', 'key': '{{ .KeyPath }}'})
+        conn = MySQLdb.connect(host=host, port=port, user='root', passwd=root_password, charset='utf8mb4',connect_timeout=5, ssl={'ca': '{{ .CAPath }}', 'cert': '{{ .CertPath }}', 'key': '{{ .KeyPath }}'})
 {{- else }}
-        conn = MySQLdb.connect(host=host, port=port, user='root', connect_timeout=5, charset='utf8mb4')
+        conn = MySQLdb.connect(host=host, port=port, user='root', passwd=root_password, connect_timeout=5, charset='utf8mb4')
 {{- end }}
     except MySQLdb.OperationalError as e:
         print(e)
@@ -379,6 +439,50 @@ for i in range(0, 10):
     break
 if retry_count == 10:
     sys.exit(1)
+{{- if .SQLFiles }}
+
+def execute_sql_files():
+    import hashlib, json
+    sql_files_dir = '/etc/tidb/init-sql'
+    applied_hashes = json.loads(os.environ.get('APPLIED_SQL_HASHES') or '{}') or {}
+    applied = []
+    for name in sorted(os.listdir(sql_files_dir)):
+        for key in sorted(os.listdir(os.path.join(sql_files_dir, name))):
+            if key.startswith('.'):
+                continue
+            file = name + '/' + key
+            with open(os.path.join(sql_files_dir, file), 'rb') as f:
+                content = f.read()
+            if applied_hashes.get(file) == hashlib.sha256(content).hexdigest():
+                continue
+            statements = []
+            statement = ''
+            for line in content.decode('utf-8').splitlines():
+                if not statement.strip() and (not line.strip() or line.strip().startswith('--')):
+                    continue
+                statement += line + '\n'
+                if line.rstrip().endswith(';'):
+                    statements.append(statement)
+                    statement = ''
+            if statement.strip():
+                statements.append(statement)
+            for index, statement in enumerate(statements):
+                try:
+                    conn.cursor().execute(statement)
+                    conn.commit()
+                except MySQLdb.Error as e:
+                    print(e)
+                    with open('/dev/termination-log', 'w') as log:
+                        json.dump({'applied': applied, 'file': file, 'statement': index, 'error': str(e)[:1024]}, log)
+                    sys.exit(1)
+            applied.append(file)
+            print('executed SQL file ' + file)
+
+if os.environ.get('INIT_SQL_FILES_ONLY') == 'true':
+    execute_sql_files()
+    conn.close()
+    sys.exit(0)
+{{- end }}
 
 {{- if .PasswordSet }}
 password_dir = '/etc/tidb/password'
@@ -400,6 +504,9 @@ with open('/data/init.sql', 'r') as sql:
         conn.cursor().execute(line)
         conn.commit()
 {{- end }}
+{{- if .SQLFiles }}
+execute_sql_files()
+{{- end }}
 if permit_host != '%%':
     conn.cursor().execute("update mysql.user set Host=%s where User='root';", (permit_host,))
 conn.cursor().execute("flush privileges;")
@@ -412,6 +519,7 @@ type TiDBInitStartScriptModel struct {
 	PermitHost  string
 	PasswordSet bool
 	InitSQL     bool
+	SQLFiles    bool
 	TLS         bool
 	CAPath      string
 	CertPath    string
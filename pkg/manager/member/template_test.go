@@ -15,6 +15,7 @@ package member
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -502,6 +503,19 @@ ARGS="--data-dir=/var/lib/pd \
 --config=/etc/pd/pd.toml \
 "
 
+if [[ -d /var/lib/pd/member/wal && -f /var/lib/pd/member_id ]]
+then
+# The previous member in the data dir may be removed from the PD cluster, e.g. by scaling in,
+# purge the data dir to join as a new member if so. Start with the data dir as usual if the
+# discovery service can not tell, e.g. the PD cluster is not available.
+member_id=` + "`" + `cat /var/lib/pd/member_id` + "`" + `
+if result=$(wget -qO- -T 3 http://${discovery_url}/new/v2/${encoded_domain_url}?member-id=${member_id} 2>/dev/null) && echo "${result}" | grep -q '"purge": *true'
+then
+echo "previous member ${member_id} is removed from the pd cluster, purging the data dir ..."
+rm -rf /var/lib/pd/member /var/lib/pd/join /var/lib/pd/member_id
+fi
+fi
+
 if [[ -f /var/lib/pd/join ]]
 then
 # The content of the join file is:
@@ -520,6 +534,16 @@ done
 ARGS="${ARGS}${result}"
 fi
 
+# Record the ID of the member in the data dir once pd-server serves, so that the discovery service
+# can tell whether the member is removed from the PD cluster when it restarts.
+member_name=${POD_NAME}
+(
+until member_id=$(wget -qO- -T 3 ://${domain}:2379/pd/api/v1/members 2>/dev/null | tr -d ' \n' | tr '{},' '\n\n\n' | awk -F':' -v name="\"${member_name}\"" '$1 == "\"name\"" { found = ($2 == name); next } found && $1 == "\"member_id\"" { print $2; exit }') && [[ -n "${member_id}" ]]; do
+sleep 5
+done
+echo ${member_id} > /var/lib/pd/member_id
+) &
+
 echo "starting pd-server ..."
 sleep $((RANDOM % 10))
 echo "/pd-server ${ARGS}"
@@ -596,6 +620,19 @@ ARGS="--data-dir=/var/lib/pd/data \
 --config=/etc/pd/pd.toml \
 "
 
+if [[ -d /var/lib/pd/data/member/wal && -f /var/lib/pd/data/member_id ]]
+then
+# The previous member in the data dir may be removed from the PD cluster, e.g. by scaling in,
+# purge the data dir to join as a new member if so. Start with the data dir as usual if the
+# discovery service can not tell, e.g. the PD cluster is not available.
+member_id=` + "`" + `cat /var/lib/pd/data/member_id` + "`" + `
+if result=$(wget -qO- -T 3 http://${discovery_url}/new/v2/${encoded_domain_url}?member-id=${member_id} 2>/dev/null) && echo "${result}" | grep -q '"purge": *true'
+then
+echo "previous member ${member_id} is removed from the pd cluster, purging the data dir ..."
+rm -rf /var/lib/pd/data/member /var/lib/pd/data/join /var/lib/pd/data/member_id
+fi
+fi
+
 if [[ -f /var/lib/pd/data/join ]]
 then
 # The content of the join file is:
@@ -614,6 +651,16 @@ done
 ARGS="${ARGS}${result}"
 fi
 
+# Record the ID of the member in the data dir once pd-server serves, so that the discovery service
+# can tell whether the member is removed from the PD cluster when it restarts.
+member_name=${POD_NAME}
+(
+until member_id=$(wget -qO- -T 3 ://${domain}:2379/pd/api/v1/members 2>/dev/null | tr -d ' \n' | tr '{},' '\n\n\n' | awk -F':' -v name="\"${member_name}\"" '$1 == "\"name\"" { found = ($2 == name); next } found && $1 == "\"member_id\"" { print $2; exit }') && [[ -n "${member_id}" ]]; do
+sleep 5
+done
+echo ${member_id} > /var/lib/pd/data/member_id
+) &
+
 echo "starting pd-server ..."
 sleep $((RANDOM % 10))
 echo "/pd-server ${ARGS}"
@@ -691,6 +738,19 @@ ARGS="--data-dir=/var/lib/pd/data \
 --config=/etc/pd/pd.toml \
 "
 
+if [[ -d /var/lib/pd/data/member/wal && -f /var/lib/pd/data/member_id ]]
+then
+# The previous member in the data dir may be removed from the PD cluster, e.g. by scaling in,
+# purge the data dir to join as a new member if so. Start with the data dir as usual if the
+# discovery service can not tell, e.g. the PD cluster is not available.
+member_id=` + "`" + `cat /var/lib/pd/data/member_id` + "`" + `
+if result=$(wget -qO- -T 3 http://${discovery_url}/new/v2/${encoded_domain_url}?member-id=${member_id} 2>/dev/null) && echo "${result}" | grep -q '"purge": *true'
+then
+echo "previous member ${member_id} is removed from the pd cluster, purging the data dir ..."
+rm -rf /var/lib/pd/data/member /var/lib/pd/data/join /var/lib/pd/data/member_id
+fi
+fi
+
 if [[ -f /var/lib/pd/data/join ]]
 then
 # The content of the join file is:
@@ -709,6 +769,16 @@ done
 ARGS="${ARGS}${result}"
 fi
 
+# Record the ID of the member in the data dir once pd-server serves, so that the discovery service
+# can tell whether the member is removed from the PD cluster when it restarts.
+member_name=${domain}
+(
+until member_id=$(wget -qO- -T 3 ://${domain}:2379/pd/api/v1/members 2>/dev/null | tr -d ' \n' | tr '{},' '\n\n\n' | awk -F':' -v name="\"${member_name}\"" '$1 == "\"name\"" { found = ($2 == name); next } found && $1 == "\"member_id\"" { print $2; exit }') && [[ -n "${member_id}" ]]; do
+sleep 5
+done
+echo ${member_id} > /var/lib/pd/data/member_id
+) &
+
 echo "starting pd-server ..."
 sleep $((RANDOM % 10))
 echo "/pd-server ${ARGS}"
@@ -734,6 +804,47 @@ exec /pd-server ${ARGS}
 	}
 }
 
+func TestRenderStartScriptWithDiscoveryTLS(t *testing.T) {
+	discovery := DiscoveryStartScriptModel{DiscoveryTLSEnabled: true}
+	pdScript, err := RenderPDStartScript(&PDStartScriptModel{
+		DiscoveryStartScriptModel: discovery,
+		Scheme:                    "https",
+		DataDir:                   pdDataVolumeMountPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tikvScript, err := RenderTiKVStartScript(&TiKVStartScriptModel{
+		DiscoveryStartScriptModel: discovery,
+		DataDir:                   tikvDataVolumeMountPath,
+		ClusterDomain:             "cluster.local",
+		PDAddress:                 "https://${CLUSTER_NAME}-pd:2379",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for script, want := range map[string]string{
+		pdScript:   "until result=$(wget -qO- -T 3 --ca-certificate=/var/lib/discovery-tls/ca.crt https://${discovery_url}/new/${encoded_domain_url} 2>/dev/null); do",
+		tikvScript: "until result=$(wget -qO- -T 3 --ca-certificate=/var/lib/discovery-tls/ca.crt https://${discovery_url}/verify/${encoded_domain_url} 2>/dev/null); do",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("expect the script to contain %q, got:\n%s", want, script)
+		}
+		if strings.Contains(script, "http://${discovery_url}") {
+			t.Errorf("expect the script not to request the discovery service with HTTP, got:\n%s", script)
+		}
+	}
+	for _, want := range []string{
+		"if result=$(wget -qO- -T 3 --ca-certificate=/var/lib/discovery-tls/ca.crt https://${discovery_url}/new/v2/${encoded_domain_url}?member-id=${member_id} 2>/dev/null)",
+		"until member_id=$(wget -qO- -T 3 --ca-certificate=/var/lib/pd-tls/ca.crt --certificate=/var/lib/pd-tls/tls.crt --private-key=/var/lib/pd-tls/tls.key https://${domain}:2379/pd/api/v1/members",
+	} {
+		if !strings.Contains(pdScript, want) {
+			t.Errorf("expect the script to contain %q, got:\n%s", want, pdScript)
+		}
+	}
+}
+
 func TestRenderPumpStartScript(t *testing.T) {
 	tests := []struct {
 		name          string
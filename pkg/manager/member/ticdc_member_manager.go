@@ -102,6 +102,7 @@ func (m *ticdcMemberManager) syncTiCDCConfigMap(tc *v1alpha1.TidbCluster, set *a
 	if err != nil {
 		return nil, err
 	}
+	addStandardLabels(newCm)
 
 	var inUseName string
 	if set != nil {
@@ -151,7 +152,7 @@ func (m *ticdcMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 			ns, tcName, err)
 	}
 
-	if tc.Spec.Paused {
+	if tc.ShouldPauseReconcile(v1alpha1.TiCDCMemberType) {
 		klog.Infof("TidbCluster %s/%s is paused, skip syncing ticdc statefulset", tc.GetNamespace(), tc.GetName())
 		return nil
 	}
@@ -165,6 +166,7 @@ func (m *ticdcMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 	if err != nil {
 		return err
 	}
+	addStandardLabelsToStatefulSet(newSts)
 
 	if stsNotExist {
 		if !tc.PDIsAvailable() {
@@ -182,6 +184,8 @@ func (m *ticdcMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 		return nil
 	}
 
+	syncPodManagementPolicy(tc, newSts, oldSts)
+
 	// Scaling takes precedence over upgrading because:
 	// - if a pod fails in the upgrading, users may want to delete it or add
 	//   new replicas
@@ -244,7 +248,7 @@ func (m *ticdcMemberManager) syncTiCDCStatus(tc *v1alpha1.TidbCluster, sts *apps
 }
 
 func (m *ticdcMemberManager) syncCDCHeadlessService(tc *v1alpha1.TidbCluster) error {
-	if tc.Spec.Paused {
+	if tc.ShouldPauseReconcile(v1alpha1.TiCDCMemberType) {
 		klog.Infof("TidbCluster %s/%s is paused, skip syncing ticdc service", tc.GetNamespace(), tc.GetName())
 		return nil
 	}
@@ -253,6 +257,7 @@ func (m *ticdcMemberManager) syncCDCHeadlessService(tc *v1alpha1.TidbCluster) er
 	tcName := tc.GetName()
 
 	newSvc := getNewCDCHeadlessService(tc)
+	addStandardLabels(newSvc)
 	oldSvcTmp, err := m.deps.ServiceLister.Services(ns).Get(controller.TiCDCPeerMemberName(tcName))
 	if errors.IsNotFound(err) {
 		err = controller.SetServiceLastAppliedConfigAnnotation(newSvc)
@@ -271,8 +276,10 @@ func (m *ticdcMemberManager) syncCDCHeadlessService(tc *v1alpha1.TidbCluster) er
 	if err != nil {
 		return err
 	}
-	if !equal {
+	svcLabels, labelsChanged := mergeStandardLabels(newSvc, oldSvc)
+	if !equal || labelsChanged {
 		svc := *oldSvc
+		svc.Labels = svcLabels
 		svc.Spec = newSvc.Spec
 		err = controller.SetServiceLastAppliedConfigAnnotation(&svc)
 		if err != nil {
@@ -384,6 +391,12 @@ func getNewTiCDCStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*ap
 		cmdArgs = append(cmdArgs, fmt.Sprintf("--config=%s", "/etc/ticdc/ticdc.toml"))
 	}
 
+	if tc.IsDiscoveryTLSEnabled() {
+		discoveryTLSMount, discoveryTLSVolume := discoveryTLSMountVolume(tc)
+		volMounts = append(volMounts, discoveryTLSMount)
+		vols = append(vols, discoveryTLSVolume)
+	}
+
 	// handle StorageVolumes and AdditionalVolumeMounts in ComponentSpec
 	storageVolMounts, additionalPVCs := util.BuildStorageVolumeAndVolumeMount(tc.Spec.TiCDC.StorageVolumes, tc.Spec.TiCDC.StorageClassName, v1alpha1.TiCDCMemberType)
 	volMounts = append(volMounts, storageVolMounts...)
@@ -403,13 +416,13 @@ func getNewTiCDCStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*ap
 pd_url="%s"
 encoded_domain_url=$(echo $pd_url | base64 | tr "\n" " " | sed "s/ //g")
 discovery_url="%s-discovery.${NAMESPACE}:10261"
-until result=$(wget -qO- -T 3 http://${discovery_url}/verify/${encoded_domain_url} 2>/dev/null); do
+until result=$(%s/verify/${encoded_domain_url} 2>/dev/null); do
 echo "waiting for the verification of PD endpoints ..."
 sleep 2
 done
 `
 
-		script += fmt.Sprintf(str, pdAddr, tc.GetName())
+		script += fmt.Sprintf(str, pdAddr, tc.GetName(), discoveryWget(tc.IsDiscoveryTLSEnabled()))
 		script += "\n" + strings.Join(append([]string{"exec"}, cmdArgs...), " ")
 	} else {
 		script = strings.Join(cmdArgs, " ")
@@ -530,7 +543,7 @@ done
 				Spec: podSpec,
 			},
 			ServiceName:         headlessSvcName,
-			PodManagementPolicy: apps.ParallelPodManagement,
+			PodManagementPolicy: baseTiCDCSpec.PodManagementPolicy(),
 			UpdateStrategy:      updateStrategy,
 		},
 	}
@@ -68,7 +68,8 @@ func (m *realTidbDiscoveryManager) Reconcile(obj client.Object) error {
 			APIGroups:     []string{v1alpha1.GroupName},
 			Resources:     []string{v1alpha1.TiDBClusterName},
 			ResourceNames: []string{metaObj.GetName()},
-			Verbs:         []string{"get"},
+			// patch is required to record the PD pod bootstrapping the PD cluster
+			Verbs: []string{"get", "patch"},
 		}
 	case *v1alpha1.DMCluster:
 		clusterPolicyRule = rbacv1.PolicyRule{
@@ -91,7 +92,7 @@ func (m *realTidbDiscoveryManager) Reconcile(obj client.Object) error {
 			{
 				APIGroups: []string{corev1.GroupName},
 				Resources: []string{"secrets"},
-				Verbs:     []string{"get", "list"},
+				Verbs:     []string{"get", "list", "watch"},
 			},
 		},
 	})
@@ -236,6 +237,9 @@ func (m *realTidbDiscoveryManager) getTidbDiscoveryDeployment(obj metav1.Object)
 	podSpec.ServiceAccountName = meta.Name
 
 	podSpec.Volumes = append(podSpec.Volumes, baseSpec.AdditionalVolumes()...)
+	if tc, ok := obj.(*v1alpha1.TidbCluster); ok && tc.IsDiscoveryTLSEnabled() {
+		podSpec.Containers[0].Args = append(podSpec.Containers[0].Args, "--tls-secret="+tc.Spec.Discovery.TLSSecret)
+	}
 	if tc, ok := obj.(*v1alpha1.TidbCluster); ok && tc.IsTLSClusterEnabled() {
 		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
 			Name: "pd-tls",
@@ -58,6 +58,7 @@ func TestTidbDiscoveryManager_Reconcile(t *testing.T) {
 				g.Expect(err).To(Succeed())
 				g.Expect(deploys).To(HaveLen(1))
 				g.Expect(deploys[0].Name).To((Equal("test-discovery")))
+				g.Expect(deploys[0].Spec.Template.Spec.Containers[0].Args).To(BeEmpty())
 			},
 			errOnCreateOrUpdate: false,
 		},
@@ -96,6 +97,18 @@ func TestTidbDiscoveryManager_Reconcile(t *testing.T) {
 			},
 			errOnCreateOrUpdate: false,
 		},
+		{
+			name: "Serving with TLS Secret",
+			prepare: func(tc *v1alpha1.TidbCluster, ctrl *controller.FakeGenericControl) {
+				tc.Spec.Discovery.TLSSecret = "discovery-tls"
+			},
+			expect: func(deploys []appsv1.Deployment, tc *v1alpha1.TidbCluster, err error) {
+				g.Expect(err).To(Succeed())
+				g.Expect(deploys).To(HaveLen(1))
+				g.Expect(deploys[0].Spec.Template.Spec.Containers[0].Args).To(Equal([]string{"--tls-secret=discovery-tls"}))
+			},
+			errOnCreateOrUpdate: false,
+		},
 		{
 			name: "Create or update resource error",
 			expect: func(deploys []appsv1.Deployment, tc *v1alpha1.TidbCluster, err error) {
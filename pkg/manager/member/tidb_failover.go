@@ -29,6 +29,8 @@ type tidbFailover struct {
 	deps *controller.Dependencies
 }
 
+var _ Failover = &tidbFailover{}
+
 // NewTiDBFailover returns a tidbFailover instance
 func NewTiDBFailover(deps *controller.Dependencies) Failover {
 	return &tidbFailover{
@@ -90,7 +92,7 @@ func (f *tidbFailover) Failover(tc *v1alpha1.TidbCluster) error {
 				CreatedAt: metav1.Now(),
 			}
 			msg := fmt.Sprintf("tidb[%s] is unhealthy", tidbMember.Name)
-			f.deps.Recorder.Event(tc, corev1.EventTypeWarning, unHealthEventReason, fmt.Sprintf(unHealthEventMsgPattern, "tidb", tidbMember.Name, msg))
+			controller.RecordEventInWindow(f.deps.Recorder, tc, tc.FailoverEventInterval(v1alpha1.TiDBMemberType), corev1.EventTypeWarning, unHealthEventReason, fmt.Sprintf(unHealthEventMsgPattern, "tidb", tidbMember.Name, msg))
 			break
 		}
 	}
@@ -15,8 +15,12 @@ package member
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"path"
+	"sort"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -27,6 +31,7 @@ import (
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog"
@@ -47,6 +52,13 @@ const (
 	startScriptDir      = "/usr/local/bin"
 	startKey            = "start-script"
 	initStartKey        = "init-start-script"
+	initSqlFilesKey     = "init-sql-files"
+	initSqlFilesDir     = "/etc/tidb/init-sql"
+	// appliedSqlHashesEnv is the env of the hashes of the SQL files executed, the start script skips them
+	appliedSqlHashesEnv = "APPLIED_SQL_HASHES"
+	// initSqlFilesOnlyEnv is the env to indicate the start script executes the SQL files only, it's set
+	// when the job is created again to execute the changed files
+	initSqlFilesOnlyEnv = "INIT_SQL_FILES_ONLY"
 )
 
 // InitManager implements the logic for syncing TidbInitializer.
@@ -84,7 +96,11 @@ func (m *tidbInitManager) Sync(ti *v1alpha1.TidbInitializer) error {
 	if err != nil {
 		return err
 	}
-	return m.updateStatus(ti.DeepCopy())
+	newTI := ti.DeepCopy()
+	if err := m.updateStatus(newTI); err != nil {
+		return err
+	}
+	return m.rerunTiDBInitJob(newTI)
 }
 
 func (m *tidbInitManager) updateStatus(ti *v1alpha1.TidbInitializer) error {
@@ -95,21 +111,11 @@ func (m *tidbInitManager) updateStatus(ti *v1alpha1.TidbInitializer) error {
 		return fmt.Errorf("updateStatus: failed to get job %s for TidbInitializer %s/%s, error: %s", name, ns, ti.Name, err)
 	}
 
-	phase := v1alpha1.InitializePhaseRunning
-	if len(job.Status.Conditions) > 0 {
-		for _, c := range job.Status.Conditions {
-			if c.Type == batchv1.JobComplete && c.Status == corev1.ConditionTrue {
-				phase = v1alpha1.InitializePhaseCompleted
-				break
-			}
-			if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
-				phase = v1alpha1.InitializePhaseFailed
-				break
-			}
-		}
+	phase := getInitJobPhase(job)
+	update, err := m.syncInitSqlStatus(ti, job, phase)
+	if err != nil {
+		return err
 	}
-
-	var update bool
 	if !apiequality.Semantic.DeepEqual(ti.Status.JobStatus, job.Status) {
 		job.Status.DeepCopyInto(&ti.Status.JobStatus)
 		update = true
@@ -125,6 +131,178 @@ func (m *tidbInitManager) updateStatus(ti *v1alpha1.TidbInitializer) error {
 	return nil
 }
 
+func getInitJobPhase(job *batchv1.Job) v1alpha1.InitializePhase {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobComplete && c.Status == corev1.ConditionTrue {
+			return v1alpha1.InitializePhaseCompleted
+		}
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return v1alpha1.InitializePhaseFailed
+		}
+	}
+	return v1alpha1.InitializePhaseRunning
+}
+
+// initSqlResult is the termination message the start script writes when it fails executing the SQL files
+type initSqlResult struct {
+	// Applied are the files executed before the failure
+	Applied []string `json:"applied"`
+	// File is the file of the failed statement
+	File string `json:"file"`
+	// Statement is the index of the failed statement in the file
+	Statement int32 `json:"statement"`
+	// Error is the error of the failed statement
+	Error string `json:"error"`
+}
+
+// syncInitSqlStatus records the hashes of the SQL files executed by the finished job and the statement failing
+// the job in the status, it returns whether the status is changed
+func (m *tidbInitManager) syncInitSqlStatus(ti *v1alpha1.TidbInitializer, job *batchv1.Job, phase v1alpha1.InitializePhase) (bool, error) {
+	jobHashes, err := getInitSqlHashes(job)
+	if err != nil || len(jobHashes) == 0 {
+		return false, err
+	}
+
+	var applied []string
+	var failure *v1alpha1.InitSqlFailure
+	switch phase {
+	case v1alpha1.InitializePhaseCompleted:
+		// the files skipped by the job were executed before with the same content
+		for file := range jobHashes {
+			applied = append(applied, file)
+		}
+	case v1alpha1.InitializePhaseFailed:
+		result, err := m.getInitSqlResult(job)
+		if err != nil {
+			return false, err
+		}
+		if result != nil {
+			applied = result.Applied
+			if result.File != "" {
+				failure = &v1alpha1.InitSqlFailure{
+					File:           result.File,
+					StatementIndex: result.Statement,
+					Message:        result.Error,
+				}
+			}
+		}
+	default:
+		return false, nil
+	}
+
+	hashes := util.CopyStringMap(ti.Status.AppliedSqlHashes)
+	for _, file := range applied {
+		if hash, ok := jobHashes[file]; ok {
+			if hashes == nil {
+				hashes = map[string]string{}
+			}
+			hashes[file] = hash
+		}
+	}
+	var update bool
+	if !apiequality.Semantic.DeepEqual(ti.Status.AppliedSqlHashes, hashes) {
+		ti.Status.AppliedSqlHashes = hashes
+		update = true
+	}
+	if !apiequality.Semantic.DeepEqual(ti.Status.SqlFailure, failure) {
+		if failure != nil {
+			klog.Warningf("TidbInitializer %s/%s: statement %d of SQL file %s failed, error: %s", ti.Namespace, ti.Name, failure.StatementIndex, failure.File, failure.Message)
+		}
+		ti.Status.SqlFailure = failure
+		update = true
+	}
+	return update, nil
+}
+
+// getInitSqlResult returns the termination message of the start script in the pod of the failed job, it
+// returns nil if the job failed before executing the SQL files
+func (m *tidbInitManager) getInitSqlResult(job *batchv1.Job) (*initSqlResult, error) {
+	selector := labels.SelectorFromSet(labels.Set{"controller-uid": string(job.UID)})
+	pods, err := m.deps.PodLister.Pods(job.Namespace).List(selector)
+	if err != nil {
+		return nil, fmt.Errorf("getInitSqlResult: failed to list pods of job %s/%s, error: %s", job.Namespace, job.Name, err)
+	}
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != containerName || status.State.Terminated == nil || status.State.Terminated.Message == "" {
+				continue
+			}
+			result := &initSqlResult{}
+			if err := json.Unmarshal([]byte(status.State.Terminated.Message), result); err != nil {
+				klog.Warningf("getInitSqlResult: failed to parse the termination message of pod %s/%s, error: %s", pod.Namespace, pod.Name, err)
+				continue
+			}
+			return result, nil
+		}
+	}
+	return nil, nil
+}
+
+// rerunTiDBInitJob deletes the finished job if the content of the SQL files is changed after the job is created
+// and spec.rerunOnChange is true, the job is created again in the next sync to execute the changed files
+func (m *tidbInitManager) rerunTiDBInitJob(ti *v1alpha1.TidbInitializer) error {
+	if !ti.Spec.RerunOnChange || len(ti.Spec.InitSqlConfigMapNames) == 0 {
+		return nil
+	}
+	ns := ti.Namespace
+	jobName := controller.TiDBInitializerMemberName(ti.Spec.Clusters.Name)
+	job, err := m.deps.JobLister.Jobs(ns).Get(jobName)
+	if err != nil {
+		return fmt.Errorf("rerunTiDBInitJob: failed to get job %s for TidbInitializer %s/%s, error: %s", jobName, ns, ti.Name, err)
+	}
+	if job.DeletionTimestamp != nil || getInitJobPhase(job) == v1alpha1.InitializePhaseRunning {
+		return nil
+	}
+
+	jobHashes, err := getInitSqlHashes(job)
+	if err != nil {
+		return err
+	}
+	hashes, err := m.getInitSqlFileHashes(ti)
+	if err != nil {
+		return err
+	}
+	if apiequality.Semantic.DeepEqual(jobHashes, hashes) {
+		return nil
+	}
+	klog.Infof("TidbInitializer %s/%s: the SQL files are changed, delete job %s to execute them again", ns, ti.Name, jobName)
+	return m.deps.JobControl.DeleteJob(ti, job)
+}
+
+// getInitSqlFileHashes returns the sha256 hashes of the content of the SQL files of the configmaps in
+// spec.initSqlConfigMapNames, keyed by <configmap name>/<key>
+func (m *tidbInitManager) getInitSqlFileHashes(ti *v1alpha1.TidbInitializer) (map[string]string, error) {
+	if len(ti.Spec.InitSqlConfigMapNames) == 0 {
+		return nil, nil
+	}
+	hashes := map[string]string{}
+	for _, name := range ti.Spec.InitSqlConfigMapNames {
+		// the configmaps are not labeled, so they are not in the cache of ConfigMapLister
+		cm, err := m.deps.KubeClientset.CoreV1().ConfigMaps(ti.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("getInitSqlFileHashes: failed to get configmap %s for TidbInitializer %s/%s, error: %s", name, ti.Namespace, ti.Name, err)
+		}
+		for key, content := range cm.Data {
+			sum := sha256.Sum256([]byte(content))
+			hashes[path.Join(name, key)] = hex.EncodeToString(sum[:])
+		}
+	}
+	return hashes, nil
+}
+
+// getInitSqlHashes returns the hashes of the SQL files recorded in the annotation of the job
+func getInitSqlHashes(job *batchv1.Job) (map[string]string, error) {
+	ann, ok := job.Annotations[label.AnnInitSqlHashesKey]
+	if !ok {
+		return nil, nil
+	}
+	hashes := map[string]string{}
+	if err := json.Unmarshal([]byte(ann), &hashes); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s of job %s/%s, error: %s", label.AnnInitSqlHashesKey, job.Namespace, job.Name, err)
+	}
+	return hashes, nil
+}
+
 func (m *tidbInitManager) updateInitializer(ti *v1alpha1.TidbInitializer) (*v1alpha1.TidbInitializer, error) {
 	ns := ti.GetNamespace()
 	tiName := ti.GetName()
@@ -171,7 +349,8 @@ func (m *tidbInitManager) syncTiDBInitConfigMap(ti *v1alpha1.TidbInitializer) er
 	if err != nil {
 		return err
 	}
-	if exist {
+	// the start script is kept up to date with the SQL files, as they may be set after the initialization
+	if exist && len(ti.Spec.InitSqlConfigMapNames) == 0 {
 		return nil
 	}
 
@@ -189,6 +368,10 @@ func (m *tidbInitManager) syncTiDBInitConfigMap(ti *v1alpha1.TidbInitializer) er
 		return err
 	}
 
+	if exist {
+		_, err = m.deps.TypedControl.CreateOrUpdateConfigMap(ti, newCm)
+		return err
+	}
 	err = m.deps.TypedControl.Create(ti, newCm)
 	if errors.IsAlreadyExists(err) {
 		klog.Infof("Configmap %s/%s already exists", newCm.Namespace, newCm.Name)
@@ -349,6 +532,52 @@ func (m *tidbInitManager) makeTiDBInitJob(ti *v1alpha1.TidbInitializer) (*batchv
 
 	meta, initLabel := getInitMeta(ti)
 
+	var sqlEnvs []corev1.EnvVar
+	if len(ti.Spec.InitSqlConfigMapNames) > 0 {
+		hashes, err := m.getInitSqlFileHashes(ti)
+		if err != nil {
+			return nil, err
+		}
+		hashesData, err := json.Marshal(hashes)
+		if err != nil {
+			return nil, err
+		}
+		appliedData, err := json.Marshal(ti.Status.AppliedSqlHashes)
+		if err != nil {
+			return nil, err
+		}
+		if meta.Annotations == nil {
+			meta.Annotations = map[string]string{}
+		}
+		meta.Annotations[label.AnnInitSqlHashesKey] = string(hashesData)
+
+		sqlEnvs = []corev1.EnvVar{{Name: appliedSqlHashesEnv, Value: string(appliedData)}}
+		// the password and initSql are set already if the last job completed or failed in the SQL files
+		if ti.Status.Phase == v1alpha1.InitializePhaseCompleted || ti.Status.SqlFailure != nil {
+			sqlEnvs = append(sqlEnvs, corev1.EnvVar{Name: initSqlFilesOnlyEnv, Value: "true"})
+		}
+
+		// the configmaps are mounted in the lexical order of the names, in which the files are executed
+		names := append([]string{}, ti.Spec.InitSqlConfigMapNames...)
+		sort.Strings(names)
+		for i, name := range names {
+			volName := fmt.Sprintf("%s-%d", initSqlFilesKey, i)
+			vms = append(vms, corev1.VolumeMount{
+				Name: volName, ReadOnly: true, MountPath: path.Join(initSqlFilesDir, name),
+			})
+			vs = append(vs, corev1.Volume{
+				Name: volName,
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: name,
+						},
+					},
+				},
+			})
+		}
+	}
+
 	podSpec := &corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels:      util.CombineStringMap(initLabel, ti.ObjectMeta.Labels),
@@ -379,7 +608,7 @@ func (m *tidbInitManager) makeTiDBInitJob(ti *v1alpha1.TidbInitializer) (*batchv
 					Image:        ti.Spec.Image,
 					Command:      cmds,
 					VolumeMounts: vms,
-					Env:          envs,
+					Env:          util.AppendEnv(envs, sqlEnvs),
 				},
 			},
 			RestartPolicy: corev1.RestartPolicyNever,
@@ -431,6 +660,7 @@ func getTiDBInitConfigMap(ti *v1alpha1.TidbInitializer, tlsClientEnabled bool) (
 		PermitHost:  permitHost,
 		InitSQL:     initSQL,
 		PasswordSet: passwdSet,
+		SQLFiles:    len(ti.Spec.InitSqlConfigMapNames) > 0,
 	}
 	if tlsClientEnabled {
 		initModel.TLS = true
@@ -14,17 +14,25 @@
 package member
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	apps "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 )
 
 func TestTiDBInitManagerSync(t *testing.T) {
@@ -154,3 +162,272 @@ func newTidbInitializerForTiDB() *v1alpha1.TidbInitializer {
 		},
 	}
 }
+
+func sqlFileHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// newFakeTiDBInitManagerForSqlFiles returns the manager with the tidb cluster and the configmaps of the SQL files
+func newFakeTiDBInitManagerForSqlFiles(g *GomegaWithT, cms map[string]map[string]string) (*tidbInitManager, *fakeIndexers) {
+	tim, tmm, indexers := newFakeTiDBInitManager()
+	_, err := tmm.deps.Controls.TiDBClusterControl.UpdateTidbCluster(newTidbClusterForTiDB(), nil, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	for name, data := range cms {
+		_, err := tim.deps.KubeClientset.CoreV1().ConfigMaps(corev1.NamespaceDefault).Create(context.TODO(), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: corev1.NamespaceDefault},
+			Data:       data,
+		}, metav1.CreateOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+	return tim, indexers
+}
+
+func newInitJobForSqlFiles(g *GomegaWithT, hashes map[string]string, conditionType batchv1.JobConditionType) *batchv1.Job {
+	data, err := json.Marshal(hashes)
+	g.Expect(err).NotTo(HaveOccurred())
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        controller.TiDBInitializerMemberName("test"),
+			Namespace:   corev1.NamespaceDefault,
+			UID:         types.UID("test-job"),
+			Annotations: map[string]string{label.AnnInitSqlHashesKey: string(data)},
+		},
+	}
+	if conditionType != "" {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue}}
+	}
+	return job
+}
+
+func TestTiDBInitManagerInitSqlFilesJob(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tim, _ := newFakeTiDBInitManagerForSqlFiles(g, map[string]map[string]string{
+		"b-sql": {"2.sql": "insert into t values (2);", "1.sql": "insert into t values (1);"},
+		"a-sql": {"1.sql": "create table t (id int);"},
+	})
+	ti := newTidbInitializerForTiDB()
+	ti.Spec.InitSqlConfigMapNames = []string{"b-sql", "a-sql"}
+	ti.Status.Phase = v1alpha1.InitializePhaseCompleted
+	ti.Status.AppliedSqlHashes = map[string]string{"a-sql/1.sql": sqlFileHash("create table t (id int);")}
+
+	job, err := tim.makeTiDBInitJob(ti)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the configmaps are mounted in the lexical order
+	podSpec := job.Spec.Template.Spec
+	var mountPaths []string
+	for _, vm := range podSpec.Containers[0].VolumeMounts {
+		if strings.HasPrefix(vm.Name, initSqlFilesKey) {
+			mountPaths = append(mountPaths, vm.MountPath)
+		}
+	}
+	g.Expect(mountPaths).To(Equal([]string{"/etc/tidb/init-sql/a-sql", "/etc/tidb/init-sql/b-sql"}))
+	var cmNames []string
+	for _, v := range podSpec.Volumes {
+		if strings.HasPrefix(v.Name, initSqlFilesKey) {
+			cmNames = append(cmNames, v.ConfigMap.Name)
+		}
+	}
+	g.Expect(cmNames).To(Equal([]string{"a-sql", "b-sql"}))
+
+	// the hashes of all the files are recorded in the job
+	hashes, err := getInitSqlHashes(job)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hashes).To(Equal(map[string]string{
+		"a-sql/1.sql": sqlFileHash("create table t (id int);"),
+		"b-sql/1.sql": sqlFileHash("insert into t values (1);"),
+		"b-sql/2.sql": sqlFileHash("insert into t values (2);"),
+	}))
+
+	// the start script skips the files applied, and executes the SQL files only after the completed job
+	envs := map[string]string{}
+	for _, env := range podSpec.Containers[0].Env {
+		envs[env.Name] = env.Value
+	}
+	applied := map[string]string{}
+	g.Expect(json.Unmarshal([]byte(envs[appliedSqlHashesEnv]), &applied)).To(Succeed())
+	g.Expect(applied).To(Equal(ti.Status.AppliedSqlHashes))
+	g.Expect(envs[initSqlFilesOnlyEnv]).To(Equal("true"))
+	g.Expect(podSpec.InitContainers[0].Env).To(BeEmpty())
+
+	// the SQL files are executed with the password of root set by the last job
+	ti.Spec.PasswordSecret = pointer.StringPtr("tidb-secret")
+	job, err = tim.makeTiDBInitJob(ti)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(job.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: passwdKey, ReadOnly: true, MountPath: passwdPath}))
+	cm, err := getTiDBInitConfigMap(ti, false)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cm.Data[startKey]).To(ContainSubstring("open('/etc/tidb/password/root', 'r')"))
+	g.Expect(cm.Data[startKey]).To(ContainSubstring("passwd=root_password"))
+	ti.Spec.PasswordSecret = nil
+
+	// the whole script is executed for the first job
+	ti.Status = v1alpha1.TidbInitializerStatus{}
+	job, err = tim.makeTiDBInitJob(ti)
+	g.Expect(err).NotTo(HaveOccurred())
+	for _, env := range job.Spec.Template.Spec.Containers[0].Env {
+		g.Expect(env.Name).NotTo(Equal(initSqlFilesOnlyEnv))
+	}
+
+	// the configmap must exist
+	ti.Spec.InitSqlConfigMapNames = append(ti.Spec.InitSqlConfigMapNames, "c-sql")
+	_, err = tim.makeTiDBInitJob(ti)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestTiDBInitManagerSyncInitSqlStatus(t *testing.T) {
+	jobHashes := map[string]string{
+		"a-sql/1.sql": "hash-a1",
+		"b-sql/1.sql": "hash-b1",
+		"b-sql/2.sql": "hash-b2",
+	}
+	tests := []struct {
+		name               string
+		conditionType      batchv1.JobConditionType
+		terminationMessage string
+		expectUpdate       bool
+		expectHashes       map[string]string
+		expectFailure      *v1alpha1.InitSqlFailure
+	}{
+		{
+			name:         "job is running",
+			expectHashes: map[string]string{"a-sql/1.sql": "hash-a1"},
+		},
+		{
+			name:          "job is completed",
+			conditionType: batchv1.JobComplete,
+			expectUpdate:  true,
+			expectHashes:  jobHashes,
+		},
+		{
+			name:               "job fails in a SQL file",
+			conditionType:      batchv1.JobFailed,
+			terminationMessage: `{"applied": ["b-sql/1.sql"], "file": "b-sql/2.sql", "statement": 3, "error": "syntax error"}`,
+			expectUpdate:       true,
+			expectHashes:       map[string]string{"a-sql/1.sql": "hash-a1", "b-sql/1.sql": "hash-b1"},
+			expectFailure:      &v1alpha1.InitSqlFailure{File: "b-sql/2.sql", StatementIndex: 3, Message: "syntax error"},
+		},
+		{
+			name:          "job fails before the SQL files",
+			conditionType: batchv1.JobFailed,
+			expectUpdate:  true,
+			expectHashes:  map[string]string{"a-sql/1.sql": "hash-a1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tim, indexers := newFakeTiDBInitManagerForSqlFiles(g, nil)
+			job := newInitJobForSqlFiles(g, jobHashes, tt.conditionType)
+			if tt.terminationMessage != "" {
+				g.Expect(indexers.pod.Add(&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      job.Name + "-abcde",
+						Namespace: job.Namespace,
+						Labels:    map[string]string{"controller-uid": string(job.UID)},
+					},
+					Status: corev1.PodStatus{
+						ContainerStatuses: []corev1.ContainerStatus{{
+							Name: containerName,
+							State: corev1.ContainerState{
+								Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: tt.terminationMessage},
+							},
+						}},
+					},
+				})).To(Succeed())
+			}
+			ti := newTidbInitializerForTiDB()
+			ti.Spec.InitSqlConfigMapNames = []string{"a-sql", "b-sql"}
+			ti.Status.AppliedSqlHashes = map[string]string{"a-sql/1.sql": "hash-a1"}
+			// the failure of the last job is cleared
+			ti.Status.SqlFailure = &v1alpha1.InitSqlFailure{File: "a-sql/1.sql"}
+			if tt.conditionType == "" {
+				ti.Status.SqlFailure = nil
+			}
+
+			update, err := tim.syncInitSqlStatus(ti, job, getInitJobPhase(job))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(update).To(Equal(tt.expectUpdate))
+			g.Expect(ti.Status.AppliedSqlHashes).To(Equal(tt.expectHashes))
+			if tt.expectFailure != nil {
+				g.Expect(ti.Status.SqlFailure).To(Equal(tt.expectFailure))
+			} else {
+				g.Expect(ti.Status.SqlFailure).To(BeNil())
+			}
+
+			// the status is not changed again
+			update, err = tim.syncInitSqlStatus(ti, job, getInitJobPhase(job))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(update).To(BeFalse())
+		})
+	}
+}
+
+func TestTiDBInitManagerRerunTiDBInitJob(t *testing.T) {
+	cms := map[string]map[string]string{
+		"a-sql": {"1.sql": "create table t (id int);"},
+	}
+	hashes := map[string]string{"a-sql/1.sql": sqlFileHash("create table t (id int);")}
+	changedHashes := map[string]string{"a-sql/1.sql": sqlFileHash("create table t (id bigint);")}
+	tests := []struct {
+		name          string
+		rerunOnChange bool
+		conditionType batchv1.JobConditionType
+		jobHashes     map[string]string
+		expectRerun   bool
+	}{
+		{
+			name:          "content is not changed",
+			rerunOnChange: true,
+			conditionType: batchv1.JobComplete,
+			jobHashes:     hashes,
+		},
+		{
+			name:          "content is changed",
+			rerunOnChange: true,
+			conditionType: batchv1.JobComplete,
+			jobHashes:     changedHashes,
+			expectRerun:   true,
+		},
+		{
+			name:          "content is changed after the failure",
+			rerunOnChange: true,
+			conditionType: batchv1.JobFailed,
+			jobHashes:     changedHashes,
+			expectRerun:   true,
+		},
+		{
+			name:          "content is changed without rerunOnChange",
+			conditionType: batchv1.JobComplete,
+			jobHashes:     changedHashes,
+		},
+		{
+			name:          "job is running",
+			rerunOnChange: true,
+			jobHashes:     changedHashes,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tim, indexers := newFakeTiDBInitManagerForSqlFiles(g, cms)
+			job := newInitJobForSqlFiles(g, tt.jobHashes, tt.conditionType)
+			g.Expect(indexers.job.Add(job)).To(Succeed())
+			ti := newTidbInitializerForTiDB()
+			ti.Spec.InitSqlConfigMapNames = []string{"a-sql"}
+			ti.Spec.RerunOnChange = tt.rerunOnChange
+
+			g.Expect(tim.rerunTiDBInitJob(ti)).To(Succeed())
+			_, err := tim.deps.JobLister.Jobs(job.Namespace).Get(job.Name)
+			if tt.expectRerun {
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
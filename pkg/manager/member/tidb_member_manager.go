@@ -132,8 +132,32 @@ func (m *tidbMemberManager) checkTLSClientCert(tc *v1alpha1.TidbCluster) error {
 	return nil
 }
 
+// setTLSServerCertHash sets the hash of the server-side certificate secret to the Pod template if TiDB should
+// be restarted on the renewal of the certificate, the renewal rolls out TiDB the same way as an upgrade.
+// The hash is not set by default because the mounted secret is updated in place.
+func (m *tidbMemberManager) setTLSServerCertHash(tc *v1alpha1.TidbCluster, set *apps.StatefulSet) error {
+	if !tc.Spec.TiDB.IsTLSClientEnabled() || !tc.Spec.TiDB.TLSClient.RestartOnRenew {
+		return nil
+	}
+	ns := tc.GetNamespace()
+	secretName := tlsClientSecretName(tc)
+	secret, err := m.deps.SecretLister.Secrets(ns).Get(secretName)
+	if err != nil {
+		return fmt.Errorf("setTLSServerCertHash: failed to get secret %s/%s, error: %v", ns, secretName, err)
+	}
+	hash, err := Sha256Sum(secret.Data)
+	if err != nil {
+		return err
+	}
+	if set.Spec.Template.Annotations == nil {
+		set.Spec.Template.Annotations = map[string]string{}
+	}
+	set.Spec.Template.Annotations[label.AnnTLSServerCertHashKey] = hash
+	return nil
+}
+
 func (m *tidbMemberManager) syncTiDBHeadlessServiceForTidbCluster(tc *v1alpha1.TidbCluster) error {
-	if tc.Spec.Paused {
+	if tc.ShouldPauseReconcile(v1alpha1.TiDBMemberType) {
 		klog.V(4).Infof("tidb cluster %s/%s is paused, skip syncing for tidb headless service", tc.GetNamespace(), tc.GetName())
 		return nil
 	}
@@ -142,6 +166,7 @@ func (m *tidbMemberManager) syncTiDBHeadlessServiceForTidbCluster(tc *v1alpha1.T
 	tcName := tc.GetName()
 
 	newSvc := getNewTiDBHeadlessServiceForTidbCluster(tc)
+	addStandardLabels(newSvc)
 	oldSvcTmp, err := m.deps.ServiceLister.Services(ns).Get(controller.TiDBPeerMemberName(tcName))
 	if errors.IsNotFound(err) {
 		err = controller.SetServiceLastAppliedConfigAnnotation(newSvc)
@@ -160,8 +185,10 @@ func (m *tidbMemberManager) syncTiDBHeadlessServiceForTidbCluster(tc *v1alpha1.T
 	if err != nil {
 		return err
 	}
-	if !equal {
+	svcLabels, labelsChanged := mergeStandardLabels(newSvc, oldSvc)
+	if !equal || labelsChanged {
 		svc := *oldSvc
+		svc.Labels = svcLabels
 		svc.Spec = newSvc.Spec
 		err = controller.SetServiceLastAppliedConfigAnnotation(&svc)
 		if err != nil {
@@ -189,7 +216,7 @@ func (m *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 		return err
 	}
 
-	if tc.Spec.Paused {
+	if tc.ShouldPauseReconcile(v1alpha1.TiDBMemberType) {
 		klog.V(4).Infof("tidb cluster %s/%s is paused, skip syncing for tidb statefulset", tc.GetNamespace(), tc.GetName())
 		return nil
 	}
@@ -203,6 +230,10 @@ func (m *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 	if err != nil {
 		return err
 	}
+	if err := m.setTLSServerCertHash(tc, newTiDBSet); err != nil {
+		return err
+	}
+	addStandardLabelsToStatefulSet(newTiDBSet)
 
 	if setNotExist {
 		err = SetStatefulSetLastAppliedConfigAnnotation(newTiDBSet)
@@ -217,6 +248,8 @@ func (m *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 		return nil
 	}
 
+	syncPodManagementPolicy(tc, newTiDBSet, oldTiDBSet)
+
 	// Scaling takes precedence over upgrading because:
 	// - if a pod fails in the upgrading, users may want to delete it or add
 	//   new replicas
@@ -272,7 +305,7 @@ func (m *tidbMemberManager) shouldRecover(tc *v1alpha1.TidbCluster) bool {
 }
 
 func (m *tidbMemberManager) syncTiDBService(tc *v1alpha1.TidbCluster) error {
-	if tc.Spec.Paused {
+	if tc.ShouldPauseReconcile(v1alpha1.TiDBMemberType) {
 		klog.V(4).Infof("tidb cluster %s/%s is paused, skip syncing for tidb service", tc.GetNamespace(), tc.GetName())
 		return nil
 	}
@@ -282,6 +315,7 @@ func (m *tidbMemberManager) syncTiDBService(tc *v1alpha1.TidbCluster) error {
 	if newSvc == nil {
 		return nil
 	}
+	addStandardLabels(newSvc)
 
 	ns := newSvc.Namespace
 
@@ -356,6 +390,7 @@ func (m *tidbMemberManager) syncTiDBConfigMap(tc *v1alpha1.TidbCluster, set *app
 	if err != nil {
 		return nil, err
 	}
+	addStandardLabels(newCm)
 
 	var inUseName string
 	if set != nil {
@@ -397,15 +432,22 @@ func getTiDBConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 
 	plugins := tc.Spec.TiDB.Plugins
 	tidbStartScriptModel := &TidbStartScriptModel{
-		EnablePlugin:    len(plugins) > 0,
-		PluginDirectory: "/plugins",
-		PluginList:      strings.Join(plugins, ","),
-		ClusterDomain:   tc.Spec.ClusterDomain,
+		DiscoveryStartScriptModel: DiscoveryStartScriptModel{DiscoveryTLSEnabled: tc.IsDiscoveryTLSEnabled()},
+		EnablePlugin:              len(plugins) > 0,
+		PluginDirectory:           "/plugins",
+		PluginList:                strings.Join(plugins, ","),
+		ClusterDomain:             tc.Spec.ClusterDomain,
 	}
 
 	if tc.HeterogeneousWithoutLocalPD() {
 		// FIXME: not work for across k8s cluster without local pd
 		tidbStartScriptModel.Path = controller.PDMemberName(tc.Spec.Cluster.Name) + ":2379"
+	} else if usesExternalPD(tc) {
+		var paths []string
+		for _, address := range tc.Spec.PDAddresses {
+			paths = append(paths, strings.TrimPrefix(strings.TrimPrefix(address, "http://"), "https://"))
+		}
+		tidbStartScriptModel.Path = strings.Join(paths, ",")
 	} else {
 		tidbStartScriptModel.Path = "${CLUSTER_NAME}-pd:2379"
 	}
@@ -562,6 +604,10 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 			Name: "tidb-server-tls", ReadOnly: true, MountPath: serverCertPath,
 		})
 	}
+	discoveryTLSMount, discoveryTLSVolume := discoveryTLSMountVolume(tc)
+	if tc.IsDiscoveryTLSEnabled() {
+		volMounts = append(volMounts, discoveryTLSMount)
+	}
 
 	vols := []corev1.Volume{
 		annoVolume,
@@ -601,6 +647,9 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 			},
 		})
 	}
+	if tc.IsDiscoveryTLSEnabled() {
+		vols = append(vols, discoveryTLSVolume)
+	}
 
 	sysctls := "sysctl -w"
 	var initContainers []corev1.Container
@@ -823,7 +872,7 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 				Spec: podSpec,
 			},
 			ServiceName:         controller.TiDBPeerMemberName(tcName),
-			PodManagementPolicy: apps.ParallelPodManagement,
+			PodManagementPolicy: baseTiDBSpec.PodManagementPolicy(),
 			UpdateStrategy:      updateStrategy,
 		},
 	}
@@ -826,6 +826,86 @@ func newFakeTiDBMemberManager() (*tidbMemberManager, *controller.FakeStatefulSet
 	return tmm, setControl, tidbControl, indexers
 }
 
+func TestTiDBMemberManagerSetTLSServerCertHash(t *testing.T) {
+	tests := []struct {
+		name       string
+		tlsClient  *v1alpha1.TiDBTLSClient
+		tlsCluster bool
+		expectHash bool
+	}{
+		{
+			name: "tls client disabled",
+		},
+		{
+			name:      "tls client disabled with restart on renew",
+			tlsClient: &v1alpha1.TiDBTLSClient{RestartOnRenew: true},
+		},
+		{
+			name:      "tls client enabled",
+			tlsClient: &v1alpha1.TiDBTLSClient{Enabled: true},
+		},
+		{
+			name:       "tls client and cluster enabled",
+			tlsClient:  &v1alpha1.TiDBTLSClient{Enabled: true},
+			tlsCluster: true,
+		},
+		{
+			name:       "tls client enabled with restart on renew",
+			tlsClient:  &v1alpha1.TiDBTLSClient{Enabled: true, RestartOnRenew: true},
+			expectHash: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tc := newTidbClusterForTiDB()
+			tc.Spec.TiDB.TLSClient = tt.tlsClient
+			if tt.tlsCluster {
+				tc.Spec.TLSCluster = &v1alpha1.TLSCluster{Enabled: true}
+			}
+			tmm, _, _, indexers := newFakeTiDBMemberManager()
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      tlsClientSecretName(tc),
+					Namespace: tc.Namespace,
+				},
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       []byte("cert"),
+					corev1.TLSPrivateKeyKey: []byte("key"),
+				},
+			}
+			g.Expect(indexers.secret.Add(secret)).To(Succeed())
+
+			hashOf := func() string {
+				set := &apps.StatefulSet{}
+				g.Expect(tmm.setTLSServerCertHash(tc, set)).To(Succeed())
+				return set.Spec.Template.Annotations[label.AnnTLSServerCertHashKey]
+			}
+
+			hash := hashOf()
+			if !tt.expectHash {
+				g.Expect(hash).To(BeEmpty())
+			} else {
+				g.Expect(hash).NotTo(BeEmpty())
+			}
+			// the hash is unchanged if the certificate is unchanged
+			g.Expect(hashOf()).To(Equal(hash))
+
+			// the renewal of the certificate changes the hash only if the restart is opted in
+			renewed := secret.DeepCopy()
+			renewed.Data[corev1.TLSCertKey] = []byte("renewed cert")
+			g.Expect(indexers.secret.Update(renewed)).To(Succeed())
+			if !tt.expectHash {
+				g.Expect(hashOf()).To(BeEmpty())
+			} else {
+				g.Expect(hashOf()).NotTo(Equal(hash))
+			}
+		})
+	}
+}
+
 func TestGetNewTiDBHeadlessServiceForTidbCluster(t *testing.T) {
 	tests := []struct {
 		name     string
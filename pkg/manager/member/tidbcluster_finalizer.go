@@ -0,0 +1,224 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+	"k8s.io/kubernetes/pkg/util/slice"
+)
+
+const (
+	// FinalizerStuck is the reason of the event when the cleanup of a tidb cluster being deleted keeps failing
+	FinalizerStuck = "FinalizerStuck"
+	// FinalizerForceRemoved is the reason of the event when the finalizer is removed without the cleanup
+	FinalizerForceRemoved = "FinalizerForceRemoved"
+)
+
+// TidbClusterFinalizer manages the tidb.pingcap.com/tidbcluster-cleanup finalizer of a tidb cluster. The finalizer
+// is added to the cluster not being deleted, and it's removed after the cluster being deleted is cleaned up:
+//   - the reclaim policy of the PVs is restored to Delete if the termination policy is Delete
+//   - the evict leader schedulers of the TiKV stores are removed from the PD shared with other clusters
+type TidbClusterFinalizer interface {
+	// Finalize returns whether the tidb cluster is being deleted, the cluster being deleted should not be synced
+	Finalize(tc *v1alpha1.TidbCluster) (bool, error)
+}
+
+type tidbClusterFinalizer struct {
+	deps *controller.Dependencies
+}
+
+// NewTidbClusterFinalizer returns a TidbClusterFinalizer
+func NewTidbClusterFinalizer(deps *controller.Dependencies) TidbClusterFinalizer {
+	return &tidbClusterFinalizer{
+		deps: deps,
+	}
+}
+
+func (f *tidbClusterFinalizer) Finalize(tc *v1alpha1.TidbCluster) (bool, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	hasFinalizer := slice.ContainsString(tc.Finalizers, label.TidbClusterCleanupFinalizer, nil)
+
+	if tc.DeletionTimestamp == nil {
+		if hasFinalizer {
+			return false, nil
+		}
+		newTC := tc.DeepCopy()
+		newTC.Finalizers = append(newTC.Finalizers, label.TidbClusterCleanupFinalizer)
+		return false, f.update(tc, newTC, "add")
+	}
+	if !hasFinalizer {
+		return true, nil
+	}
+
+	if _, force := tc.Annotations[label.AnnForceRemoveFinalizerKey]; force {
+		klog.Warningf("tidbcluster finalizer: tc %s/%s is deleted without the cleanup, as annotation %s is set", ns, tcName, label.AnnForceRemoveFinalizerKey)
+		f.deps.Recorder.Eventf(tc, corev1.EventTypeWarning, FinalizerForceRemoved, "finalizer %s is removed without the cleanup", label.TidbClusterCleanupFinalizer)
+		return true, f.removeFinalizer(tc)
+	}
+
+	if err := f.cleanup(tc); err != nil {
+		if timeout := f.deps.CLIConfig.FinalizerStuckTimeout; timeout > 0 && time.Since(tc.DeletionTimestamp.Time) > timeout {
+			f.deps.Recorder.Eventf(tc, corev1.EventTypeWarning, FinalizerStuck, "the cleanup has blocked the deletion since %s: %v, annotate %s to remove finalizer %s without the cleanup",
+				tc.DeletionTimestamp.Format(time.RFC3339), err, label.AnnForceRemoveFinalizerKey, label.TidbClusterCleanupFinalizer)
+		}
+		return true, err
+	}
+	klog.Infof("tidbcluster finalizer: tc %s/%s is cleaned up", ns, tcName)
+	return true, f.removeFinalizer(tc)
+}
+
+// cleanup cleans up the tidb cluster being deleted, it's idempotent
+func (f *tidbClusterFinalizer) cleanup(tc *v1alpha1.TidbCluster) error {
+	if err := f.restorePVReclaimPolicy(tc); err != nil {
+		return err
+	}
+	f.removeEvictLeaderSchedulers(tc)
+	return nil
+}
+
+// restorePVReclaimPolicy restores the reclaim policy of the PVs of the cluster to Delete if the termination
+// policy is Delete, the PVs are kept as is otherwise
+func (f *tidbClusterFinalizer) restorePVReclaimPolicy(tc *v1alpha1.TidbCluster) error {
+	if tc.GetTerminationPolicy() != v1alpha1.TerminationPolicyDelete {
+		return nil
+	}
+	if f.deps.PVLister == nil {
+		klog.V(4).Infof("Persistent volumes lister is unavailable, skip restoring reclaim policy for tc %s/%s. This may be caused by no relevant permissions", tc.GetNamespace(), tc.GetName())
+		return nil
+	}
+
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	selector, err := label.New().Instance(tc.GetInstanceName()).Selector()
+	if err != nil {
+		return err
+	}
+	pvcs, err := f.deps.PVCLister.PersistentVolumeClaims(ns).List(selector)
+	if err != nil {
+		return fmt.Errorf("tidbcluster finalizer: failed to list pvc for tc %s/%s, selector %s, error: %v", ns, tcName, selector, err)
+	}
+	for _, pvc := range pvcs {
+		if pvc.Spec.VolumeName == "" {
+			continue
+		}
+		pv, err := f.deps.PVLister.Get(pvc.Spec.VolumeName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("tidbcluster finalizer: failed to get pv %s of tc %s/%s, error: %v", pvc.Spec.VolumeName, ns, tcName, err)
+		}
+		if pv.Spec.PersistentVolumeReclaimPolicy == corev1.PersistentVolumeReclaimDelete {
+			continue
+		}
+		if err := f.deps.PVControl.PatchPVReclaimPolicy(tc, pv, corev1.PersistentVolumeReclaimDelete); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeEvictLeaderSchedulers removes the evict leader schedulers of the TiKV stores left by the upgrade
+// or the failover from the PD shared with other clusters, the PD of the cluster itself is deleted along
+// with the cluster. It's best effort, the deletion is not blocked if PD is unreachable.
+func (f *tidbClusterFinalizer) removeEvictLeaderSchedulers(tc *v1alpha1.TidbCluster) {
+	if len(tc.Status.TiKV.Stores) == 0 {
+		return
+	}
+	if tc.Spec.PD != nil && len(tc.Status.PD.PeerMembers) == 0 {
+		return
+	}
+
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	pdClient := controller.GetPDClient(f.deps.PDControl, tc)
+	schedulers, err := pdClient.GetEvictLeaderSchedulers()
+	if err != nil {
+		klog.Warningf("tidbcluster finalizer: failed to get evict leader schedulers of tc %s/%s, skip removing them, error: %v", ns, tcName, err)
+		return
+	}
+	// the name of the scheduler is evict-leader-scheduler-<store id>
+	storeIDs := sets.NewString()
+	for _, s := range schedulers {
+		storeIDs.Insert(s[strings.LastIndex(s, "-")+1:])
+	}
+	for _, store := range tc.Status.TiKV.Stores {
+		if !storeIDs.Has(store.ID) {
+			continue
+		}
+		storeID, err := strconv.ParseUint(store.ID, 10, 64)
+		if err != nil {
+			continue
+		}
+		if err := pdClient.EndEvictLeader(storeID); err != nil {
+			klog.Warningf("tidbcluster finalizer: failed to remove the evict leader scheduler of store %d of tc %s/%s, error: %v", storeID, ns, tcName, err)
+			continue
+		}
+		klog.Infof("tidbcluster finalizer: removed the evict leader scheduler of store %d of tc %s/%s", storeID, ns, tcName)
+	}
+}
+
+func (f *tidbClusterFinalizer) removeFinalizer(tc *v1alpha1.TidbCluster) error {
+	newTC := tc.DeepCopy()
+	newTC.Finalizers = slice.RemoveString(newTC.Finalizers, label.TidbClusterCleanupFinalizer, nil)
+	return f.update(tc, newTC, "remove")
+}
+
+func (f *tidbClusterFinalizer) update(tc, newTC *v1alpha1.TidbCluster, verb string) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	updated, err := f.deps.Clientset.PingcapV1alpha1().TidbClusters(ns).Update(context.TODO(), newTC, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("tidbcluster finalizer: failed to %s finalizer %s of tc %s/%s, error: %v", verb, label.TidbClusterCleanupFinalizer, ns, tcName, err)
+	}
+	// keep the status changed in this round, it's updated later with the new resource version
+	tc.ObjectMeta = updated.ObjectMeta
+	return nil
+}
+
+var _ TidbClusterFinalizer = &tidbClusterFinalizer{}
+
+// FakeTidbClusterFinalizer is a fake TidbClusterFinalizer
+type FakeTidbClusterFinalizer struct {
+	err error
+}
+
+// NewFakeTidbClusterFinalizer returns a FakeTidbClusterFinalizer
+func NewFakeTidbClusterFinalizer() *FakeTidbClusterFinalizer {
+	return &FakeTidbClusterFinalizer{}
+}
+
+func (f *FakeTidbClusterFinalizer) SetFinalizeError(err error) {
+	f.err = err
+}
+
+func (f *FakeTidbClusterFinalizer) Finalize(tc *v1alpha1.TidbCluster) (bool, error) {
+	return tc.DeletionTimestamp != nil, f.err
+}
+
+var _ TidbClusterFinalizer = &FakeTidbClusterFinalizer{}
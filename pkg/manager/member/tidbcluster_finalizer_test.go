@@ -0,0 +1,257 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+func newFakeTidbClusterFinalizer(g *GomegaWithT, tc *v1alpha1.TidbCluster) (*tidbClusterFinalizer, cache.Indexer, cache.Indexer, *controller.FakePVControl) {
+	fakeDeps := controller.NewFakeDependencies()
+	_, err := fakeDeps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	pvIndexer := fakeDeps.KubeInformerFactory.Core().V1().PersistentVolumes().Informer().GetIndexer()
+	pvcIndexer := fakeDeps.KubeInformerFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer()
+	return &tidbClusterFinalizer{deps: fakeDeps}, pvIndexer, pvcIndexer, fakeDeps.PVControl.(*controller.FakePVControl)
+}
+
+func newDeletingTidbClusterForFinalizer(deletedAgo time.Duration) *v1alpha1.TidbCluster {
+	tc := newTidbClusterForPD()
+	tc.Finalizers = []string{label.TidbClusterCleanupFinalizer}
+	tc.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-deletedAgo)}
+	return tc
+}
+
+// addPVForFinalizer adds a bound PVC of the PD pod of the ordinal and its PV of the reclaim policy
+func addPVForFinalizer(g *GomegaWithT, pvIndexer, pvcIndexer cache.Indexer, tc *v1alpha1.TidbCluster, ordinal int32, policy corev1.PersistentVolumeReclaimPolicy) string {
+	pvc := newPVCForPDFailover(tc, v1alpha1.PDMemberType, ordinal)
+	pvName := fmt.Sprintf("pv-%d", ordinal)
+	pvc.Spec.VolumeName = pvName
+	g.Expect(pvcIndexer.Add(pvc)).To(Succeed())
+	g.Expect(pvIndexer.Add(&corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: pvName},
+		Spec:       corev1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: policy},
+	})).To(Succeed())
+	return pvName
+}
+
+func getFinalizedTidbCluster(g *GomegaWithT, f *tidbClusterFinalizer, tc *v1alpha1.TidbCluster) *v1alpha1.TidbCluster {
+	updated, err := f.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Get(context.TODO(), tc.Name, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	return updated
+}
+
+func TestTidbClusterFinalizerAddFinalizer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	f, _, _, _ := newFakeTidbClusterFinalizer(g, tc)
+
+	deleting, err := f.Finalize(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleting).To(BeFalse())
+	g.Expect(tc.Finalizers).To(ConsistOf(label.TidbClusterCleanupFinalizer))
+	g.Expect(getFinalizedTidbCluster(g, f, tc).Finalizers).To(ConsistOf(label.TidbClusterCleanupFinalizer))
+
+	// the finalizer is added once
+	deleting, err = f.Finalize(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleting).To(BeFalse())
+	g.Expect(tc.Finalizers).To(HaveLen(1))
+}
+
+func TestTidbClusterFinalizerTerminationPolicy(t *testing.T) {
+	tests := []struct {
+		name              string
+		terminationPolicy v1alpha1.TerminationPolicy
+		expectPolicy      corev1.PersistentVolumeReclaimPolicy
+	}{
+		{
+			name:         "default termination policy",
+			expectPolicy: corev1.PersistentVolumeReclaimRetain,
+		},
+		{
+			name:              "retain",
+			terminationPolicy: v1alpha1.TerminationPolicyRetain,
+			expectPolicy:      corev1.PersistentVolumeReclaimRetain,
+		},
+		{
+			name:              "delete",
+			terminationPolicy: v1alpha1.TerminationPolicyDelete,
+			expectPolicy:      corev1.PersistentVolumeReclaimDelete,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tc := newDeletingTidbClusterForFinalizer(time.Minute)
+			tc.Spec.TerminationPolicy = tt.terminationPolicy
+			f, pvIndexer, pvcIndexer, _ := newFakeTidbClusterFinalizer(g, tc)
+			// the PVs of both reclaim policies
+			retainPV := addPVForFinalizer(g, pvIndexer, pvcIndexer, tc, 0, corev1.PersistentVolumeReclaimRetain)
+			deletePV := addPVForFinalizer(g, pvIndexer, pvcIndexer, tc, 1, corev1.PersistentVolumeReclaimDelete)
+
+			deleting, err := f.Finalize(tc)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(deleting).To(BeTrue())
+			g.Expect(getFinalizedTidbCluster(g, f, tc).Finalizers).To(BeEmpty())
+
+			pv, err := f.deps.PVLister.Get(retainPV)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(tt.expectPolicy))
+			pv, err = f.deps.PVLister.Get(deletePV)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimDelete))
+		})
+	}
+}
+
+func TestTidbClusterFinalizerEvictLeaderSchedulers(t *testing.T) {
+	tests := []struct {
+		name        string
+		ownPD       bool
+		pdErr       error
+		expectEnded []uint64
+	}{
+		{
+			name:        "the pd is shared",
+			expectEnded: []uint64{2},
+		},
+		{
+			name:  "the pd is deleted along with the cluster",
+			ownPD: true,
+		},
+		{
+			name:  "the pd is unreachable",
+			pdErr: fmt.Errorf("pd is unreachable"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tc := newDeletingTidbClusterForFinalizer(time.Minute)
+			if !tt.ownPD {
+				tc.Spec.PD = nil
+				tc.Spec.Cluster = &v1alpha1.TidbClusterRef{Name: "shared", Namespace: tc.Namespace}
+			}
+			tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+				"1": {ID: "1", PodName: "test-tikv-0"},
+				"2": {ID: "2", PodName: "test-tikv-1"},
+			}
+			f, _, _, _ := newFakeTidbClusterFinalizer(g, tc)
+			pdClient := controller.NewFakePDClient(f.deps.PDControl.(*pdapi.FakePDControl), tc)
+			pdClient.AddReaction(pdapi.GetEvictLeaderSchedulersActionType, func(action *pdapi.Action) (interface{}, error) {
+				// the scheduler of store 3 belongs to another cluster
+				return []string{"evict-leader-scheduler-2", "evict-leader-scheduler-3"}, tt.pdErr
+			})
+			var ended []uint64
+			pdClient.AddReaction(pdapi.EndEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+				ended = append(ended, action.ID)
+				return nil, nil
+			})
+
+			deleting, err := f.Finalize(tc)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(deleting).To(BeTrue())
+			g.Expect(ended).To(Equal(tt.expectEnded))
+			g.Expect(getFinalizedTidbCluster(g, f, tc).Finalizers).To(BeEmpty())
+		})
+	}
+}
+
+func TestTidbClusterFinalizerStuck(t *testing.T) {
+	tests := []struct {
+		name            string
+		deletedAgo      time.Duration
+		forceRemove     bool
+		expectErr       bool
+		expectFinalizer bool
+		expectEvent     string
+	}{
+		{
+			name:            "cleanup fails",
+			deletedAgo:      time.Minute,
+			expectErr:       true,
+			expectFinalizer: true,
+		},
+		{
+			name:            "cleanup is stuck",
+			deletedAgo:      time.Hour,
+			expectErr:       true,
+			expectFinalizer: true,
+			expectEvent:     FinalizerStuck,
+		},
+		{
+			name:        "finalizer is removed by force",
+			deletedAgo:  time.Hour,
+			forceRemove: true,
+			expectEvent: FinalizerForceRemoved,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tc := newDeletingTidbClusterForFinalizer(tt.deletedAgo)
+			tc.Spec.TerminationPolicy = v1alpha1.TerminationPolicyDelete
+			if tt.forceRemove {
+				tc.Annotations = map[string]string{label.AnnForceRemoveFinalizerKey: "true"}
+			}
+			f, pvIndexer, pvcIndexer, pvControl := newFakeTidbClusterFinalizer(g, tc)
+			pvName := addPVForFinalizer(g, pvIndexer, pvcIndexer, tc, 0, corev1.PersistentVolumeReclaimRetain)
+			pvControl.SetUpdatePVError(fmt.Errorf("API server failed"), 0)
+			recorder := f.deps.Recorder.(*record.FakeRecorder)
+
+			deleting, err := f.Finalize(tc)
+			g.Expect(deleting).To(BeTrue())
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tt.expectFinalizer {
+				g.Expect(getFinalizedTidbCluster(g, f, tc).Finalizers).To(ConsistOf(label.TidbClusterCleanupFinalizer))
+			} else {
+				g.Expect(getFinalizedTidbCluster(g, f, tc).Finalizers).To(BeEmpty())
+			}
+			events := collectEvents(recorder.Events)
+			if tt.expectEvent != "" {
+				g.Expect(events).To(HaveLen(1))
+				g.Expect(events[0]).To(ContainSubstring(corev1.EventTypeWarning))
+				g.Expect(events[0]).To(ContainSubstring(tt.expectEvent))
+			} else {
+				g.Expect(events).To(BeEmpty())
+			}
+			// the PV is left as is
+			pv, err := f.deps.PVLister.Get(pvName)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+		})
+	}
+}
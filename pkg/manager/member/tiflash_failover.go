@@ -15,12 +15,15 @@ package member
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/util"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog"
 )
@@ -30,6 +33,8 @@ type tiflashFailover struct {
 	deps *controller.Dependencies
 }
 
+var _ Failover = &tiflashFailover{}
+
 // NewTiFlashFailover returns a tiflash Failover
 func NewTiFlashFailover(deps *controller.Dependencies) Failover {
 	return &tiflashFailover{deps: deps}
@@ -83,14 +88,105 @@ func (f *tiflashFailover) Failover(tc *v1alpha1.TidbCluster) error {
 					StoreID:   store.ID,
 					CreatedAt: metav1.Now(),
 				}
-				msg := fmt.Sprintf("store [%s] is Down", store.ID)
-				f.deps.Recorder.Event(tc, corev1.EventTypeWarning, unHealthEventReason, fmt.Sprintf(unHealthEventMsgPattern, "tiflash", podName, msg))
+				msg := fmt.Sprintf("store [%s] is %s", store.ID, store.State)
+				controller.RecordEventInWindow(f.deps.Recorder, tc, tc.FailoverEventInterval(v1alpha1.TiFlashMemberType), corev1.EventTypeWarning, unHealthEventReason, fmt.Sprintf(unHealthEventMsgPattern, "tiflash", podName, msg))
 			}
 		}
 	}
+	return f.tryToDeleteFailureStores(tc)
+}
+
+// tryToDeleteFailureStores deletes the failure stores still Down from PD, and recreates the pods of
+// the ones that become Tombstone with new PVCs to join the cluster as new stores. The failure stores
+// replaced in place are removed, so that no more pod is created for them.
+func (f *tiflashFailover) tryToDeleteFailureStores(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	storeIDs := make([]string, 0, len(tc.Status.TiFlash.FailureStores))
+	for storeID := range tc.Status.TiFlash.FailureStores {
+		storeIDs = append(storeIDs, storeID)
+	}
+	sort.Strings(storeIDs)
+
+	for _, storeID := range storeIDs {
+		failureStore := tc.Status.TiFlash.FailureStores[storeID]
+		if !f.isPodDesired(tc, failureStore.PodName) {
+			continue
+		}
+		if store, ok := tc.Status.TiFlash.Stores[storeID]; ok {
+			// the Offline store is being removed by PD, and the store back to Up is kept
+			if store.State != v1alpha1.TiKVStateDown {
+				continue
+			}
+			id, err := strconv.ParseUint(storeID, 10, 64)
+			if err != nil {
+				return err
+			}
+			if err := controller.GetPDClient(f.deps.PDControl, tc).DeleteStore(id); err != nil {
+				klog.Errorf("tiflash failover: failed to delete store %s/%s(%s), error: %v", ns, failureStore.PodName, storeID, err)
+				return err
+			}
+			klog.Infof("tiflash failover: delete store %s/%s(%s) successfully", ns, failureStore.PodName, storeID)
+			f.deps.Recorder.Eventf(tc, corev1.EventTypeWarning, "TiFlashStoreDeleted", "failure store %s/%s(%s) deleted from PD cluster", ns, failureStore.PodName, storeID)
+			continue
+		}
+
+		// the store is Tombstone or removed from PD, the pod and the PVCs created before the
+		// failure are deleted, otherwise the pod can't start with the data of a Tombstone store
+		if err := f.deleteFailureStorePodAndPVCs(tc, failureStore); err != nil {
+			return fmt.Errorf("tiflash failover: failed to delete pod and PVCs of failure store %s/%s(%s) of tc %s, error: %v", ns, failureStore.PodName, storeID, tcName, err)
+		}
+		delete(tc.Status.TiFlash.FailureStores, storeID)
+		klog.Infof("tiflash failover: failure store %s/%s(%s) is replaced in place, remove it from failure stores", ns, failureStore.PodName, storeID)
+	}
 	return nil
 }
 
+func (f *tiflashFailover) deleteFailureStorePodAndPVCs(tc *v1alpha1.TidbCluster, failureStore v1alpha1.TiKVFailureStore) error {
+	ns := tc.GetNamespace()
+	podName := failureStore.PodName
+
+	pod, err := f.deps.PodLister.Pods(ns).Get(podName)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if pod != nil && pod.DeletionTimestamp == nil && pod.CreationTimestamp.Before(&failureStore.CreatedAt) {
+		if err := f.deps.PodControl.DeletePod(tc, pod); err != nil {
+			return err
+		}
+	}
+
+	ordinal, err := util.GetOrdinalFromPodName(podName)
+	if err != nil {
+		return err
+	}
+	pvcSelector, err := GetPVCSelectorForPod(tc, v1alpha1.TiFlashMemberType, ordinal)
+	if err != nil {
+		return err
+	}
+	pvcs, err := f.deps.PVCLister.PersistentVolumeClaims(ns).List(pvcSelector)
+	if err != nil {
+		return err
+	}
+	for _, pvc := range pvcs {
+		// the PVCs of the new store are kept
+		if pvc.DeletionTimestamp != nil || !pvc.CreationTimestamp.Before(&failureStore.CreatedAt) {
+			continue
+		}
+		if err := f.deps.PVCControl.DeletePVC(tc, pvc); err != nil {
+			return err
+		}
+		klog.Infof("tiflash failover: delete PVC %s/%s successfully", ns, pvc.Name)
+	}
+	return nil
+}
+
+// isTiFlashStoreUnreachable returns whether the TiFlash store is Down or Disconnected
+func isTiFlashStoreUnreachable(state string) bool {
+	return state == v1alpha1.TiKVStateDown || state == v1alpha1.TiKVStateDisconnected
+}
+
 func (f *tiflashFailover) RemoveUndesiredFailures(tc *v1alpha1.TidbCluster) {
 	for key, failureStore := range tc.Status.TiFlash.FailureStores {
 		if !f.isPodDesired(tc, failureStore.PodName) {
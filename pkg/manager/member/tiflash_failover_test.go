@@ -0,0 +1,219 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestTiFlashFailoverFailover(t *testing.T) {
+	now := time.Now()
+	failedAt := metav1.NewTime(now.Add(-10 * time.Minute))
+	tiflash1 := ordinalPodName(v1alpha1.TiFlashMemberType, "test", 1)
+
+	newPod := func(created time.Time) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              tiflash1,
+				Namespace:         metav1.NamespaceDefault,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+		}
+	}
+	newPVC := func(name string, created time.Time) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         metav1.NamespaceDefault,
+				CreationTimestamp: metav1.NewTime(created),
+				Labels: map[string]string{
+					label.NameLabelKey:      "tidb-cluster",
+					label.ManagedByLabelKey: label.TiDBOperator,
+					label.InstanceLabelKey:  "test",
+					label.AnnPodNameKey:     tiflash1,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name                string
+		update              func(*v1alpha1.TidbCluster)
+		pod                 *corev1.Pod
+		pvcs                []*corev1.PersistentVolumeClaim
+		expectFailureStores int
+		expectDeletedStore  uint64
+		expectPodDeleted    bool
+		expectPVCs          []string
+	}{
+		{
+			name: "down within deadline",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiFlash.Stores = map[string]v1alpha1.TiKVStore{
+					"1": {ID: "1", State: v1alpha1.TiKVStateDown, PodName: tiflash1, LastTransitionTime: metav1.NewTime(now.Add(-30 * time.Minute))},
+				}
+			},
+			expectFailureStores: 0,
+		},
+		{
+			name: "disconnected after deadline",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiFlash.Stores = map[string]v1alpha1.TiKVStore{
+					"1": {ID: "1", State: v1alpha1.TiKVStateDisconnected, PodName: tiflash1, LastTransitionTime: metav1.NewTime(now.Add(-70 * time.Minute))},
+				}
+			},
+			expectFailureStores: 0,
+		},
+		{
+			name: "down after deadline",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiFlash.Stores = map[string]v1alpha1.TiKVStore{
+					"1": {ID: "1", State: v1alpha1.TiKVStateDown, PodName: tiflash1, LastTransitionTime: metav1.NewTime(now.Add(-70 * time.Minute))},
+				}
+			},
+			expectFailureStores: 1,
+			expectDeletedStore:  1,
+		},
+		{
+			name: "max failover count reached",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiFlash.MaxFailoverCount = pointer.Int32Ptr(1)
+				tc.Status.TiFlash.Stores = map[string]v1alpha1.TiKVStore{
+					"1": {ID: "1", State: v1alpha1.TiKVStateDown, PodName: tiflash1, LastTransitionTime: metav1.NewTime(now.Add(-70 * time.Minute))},
+				}
+				tc.Status.TiFlash.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+					"2": {PodName: ordinalPodName(v1alpha1.TiFlashMemberType, "test", 2), StoreID: "2", CreatedAt: failedAt},
+				}
+			},
+			expectFailureStores: 1,
+		},
+		{
+			name: "failure store back to Up",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiFlash.Stores = map[string]v1alpha1.TiKVStore{
+					"1": {ID: "1", State: v1alpha1.TiKVStateUp, PodName: tiflash1, LastTransitionTime: metav1.NewTime(now)},
+				}
+				tc.Status.TiFlash.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+					"1": {PodName: tiflash1, StoreID: "1", CreatedAt: failedAt},
+				}
+			},
+			expectFailureStores: 1,
+		},
+		{
+			name: "failure store is Offline",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiFlash.Stores = map[string]v1alpha1.TiKVStore{
+					"1": {ID: "1", State: v1alpha1.TiKVStateOffline, PodName: tiflash1, LastTransitionTime: metav1.NewTime(now)},
+				}
+				tc.Status.TiFlash.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+					"1": {PodName: tiflash1, StoreID: "1", CreatedAt: failedAt},
+				}
+			},
+			pod:                 newPod(now.Add(-time.Hour)),
+			pvcs:                []*corev1.PersistentVolumeClaim{newPVC("data0-"+tiflash1, now.Add(-time.Hour))},
+			expectFailureStores: 1,
+			expectPVCs:          []string{"data0-" + tiflash1},
+		},
+		{
+			name: "failure store is Tombstone",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiFlash.TombstoneStores = map[string]v1alpha1.TiKVStore{
+					"1": {ID: "1", State: v1alpha1.TiKVStateTombstone, PodName: tiflash1},
+				}
+				tc.Status.TiFlash.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+					"1": {PodName: tiflash1, StoreID: "1", CreatedAt: failedAt},
+				}
+			},
+			pod: newPod(now.Add(-time.Hour)),
+			pvcs: []*corev1.PersistentVolumeClaim{
+				newPVC("data0-"+tiflash1, now.Add(-time.Hour)),
+				newPVC("data1-"+tiflash1, now),
+			},
+			expectFailureStores: 0,
+			expectPodDeleted:    true,
+			expectPVCs:          []string{"data1-" + tiflash1},
+		},
+		{
+			name: "pod of Tombstone failure store is recreated",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiFlash.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+					"1": {PodName: tiflash1, StoreID: "1", CreatedAt: failedAt},
+				}
+			},
+			pod:                 newPod(now),
+			pvcs:                []*corev1.PersistentVolumeClaim{newPVC("data0-"+tiflash1, now)},
+			expectFailureStores: 0,
+			expectPVCs:          []string{"data0-" + tiflash1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			tc := newTidbClusterForPD()
+			tc.Spec.TiFlash = &v1alpha1.TiFlashSpec{
+				Replicas:         3,
+				MaxFailoverCount: pointer.Int32Ptr(3),
+			}
+			tt.update(tc)
+
+			fakeDeps := controller.NewFakeDependencies()
+			fakeDeps.CLIConfig.TiFlashFailoverPeriod = 1 * time.Hour
+			podIndexer := fakeDeps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+			pvcIndexer := fakeDeps.KubeInformerFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer()
+			if tt.pod != nil {
+				g.Expect(podIndexer.Add(tt.pod)).To(Succeed())
+			}
+			for _, pvc := range tt.pvcs {
+				g.Expect(pvcIndexer.Add(pvc)).To(Succeed())
+			}
+			var deletedStore uint64
+			pdClient := controller.NewFakePDClient(fakeDeps.PDControl.(*pdapi.FakePDControl), tc)
+			pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+				deletedStore = action.ID
+				return nil, nil
+			})
+			tiflashFailover := &tiflashFailover{deps: fakeDeps}
+
+			g.Expect(tiflashFailover.Failover(tc)).To(Succeed())
+			g.Expect(tc.Status.TiFlash.FailureStores).To(HaveLen(tt.expectFailureStores))
+			g.Expect(deletedStore).To(Equal(tt.expectDeletedStore))
+
+			if tt.pod != nil {
+				_, err := fakeDeps.PodLister.Pods(tc.Namespace).Get(tiflash1)
+				if tt.expectPodDeleted {
+					expectErrIsNotFound(g, err)
+				} else {
+					g.Expect(err).NotTo(HaveOccurred())
+				}
+			}
+			var pvcNames []string
+			for _, pvc := range tt.pvcs {
+				if _, err := fakeDeps.PVCLister.PersistentVolumeClaims(tc.Namespace).Get(pvc.Name); err == nil {
+					pvcNames = append(pvcNames, pvc.Name)
+				}
+			}
+			g.Expect(pvcNames).To(Equal(tt.expectPVCs))
+		})
+	}
+}
@@ -71,13 +71,15 @@ func (m *tiflashMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 		return nil
 	}
 
-	err := m.enablePlacementRules(tc)
-	if err != nil {
-		klog.Errorf("Enable placement rules failed, error: %v", err)
-		// No need to return err here, just continue to sync tiflash
+	// the placement rules are enabled in the pd config, which is a mutation skipped while paused
+	if !tc.ShouldPauseReconcile(v1alpha1.TiFlashMemberType) {
+		if err := m.enablePlacementRules(tc); err != nil {
+			klog.Errorf("Enable placement rules failed, error: %v", err)
+			// No need to return err here, just continue to sync tiflash
+		}
 	}
 	// Sync TiFlash Headless Service
-	if err = m.syncHeadlessService(tc); err != nil {
+	if err := m.syncHeadlessService(tc); err != nil {
 		return err
 	}
 
@@ -102,7 +104,7 @@ func (m *tiflashMemberManager) enablePlacementRules(tc *v1alpha1.TidbCluster) er
 }
 
 func (m *tiflashMemberManager) syncHeadlessService(tc *v1alpha1.TidbCluster) error {
-	if tc.Spec.Paused {
+	if tc.ShouldPauseReconcile(v1alpha1.TiFlashMemberType) {
 		klog.V(4).Infof("tiflash cluster %s/%s is paused, skip syncing for tiflash service", tc.GetNamespace(), tc.GetName())
 		return nil
 	}
@@ -111,6 +113,7 @@ func (m *tiflashMemberManager) syncHeadlessService(tc *v1alpha1.TidbCluster) err
 	tcName := tc.GetName()
 
 	newSvc := getNewHeadlessService(tc)
+	addStandardLabels(newSvc)
 	oldSvcTmp, err := m.deps.ServiceLister.Services(ns).Get(controller.TiFlashPeerMemberName(tcName))
 	if errors.IsNotFound(err) {
 		err = controller.SetServiceLastAppliedConfigAnnotation(newSvc)
@@ -129,8 +132,10 @@ func (m *tiflashMemberManager) syncHeadlessService(tc *v1alpha1.TidbCluster) err
 	if err != nil {
 		return err
 	}
-	if !equal {
+	svcLabels, labelsChanged := mergeStandardLabels(newSvc, oldSvc)
+	if !equal || labelsChanged {
 		svc := *oldSvc
+		svc.Labels = svcLabels
 		svc.Spec = newSvc.Spec
 		err = controller.SetServiceLastAppliedConfigAnnotation(&svc)
 		if err != nil {
@@ -159,7 +164,7 @@ func (m *tiflashMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 		return err
 	}
 
-	if tc.Spec.Paused {
+	if tc.ShouldPauseReconcile(v1alpha1.TiFlashMemberType) {
 		klog.V(4).Infof("tiflash cluster %s/%s is paused, skip syncing for tiflash statefulset", tc.GetNamespace(), tc.GetName())
 		return nil
 	}
@@ -183,6 +188,7 @@ func (m *tiflashMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 	if err != nil {
 		return err
 	}
+	addStandardLabelsToStatefulSet(newSet)
 	if setNotExist {
 		if !tc.PDIsAvailable() {
 			klog.Infof("TidbCluster: %s/%s, waiting for PD cluster running", ns, tcName)
@@ -200,6 +206,8 @@ func (m *tiflashMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 		return nil
 	}
 
+	syncPodManagementPolicy(tc, newSet, oldSet)
+
 	if _, err := m.setStoreLabelsForTiFlash(tc); err != nil {
 		return err
 	}
@@ -235,6 +243,7 @@ func (m *tiflashMemberManager) syncConfigMap(tc *v1alpha1.TidbCluster, set *apps
 	if err != nil {
 		return nil, err
 	}
+	addStandardLabels(newCm)
 
 	var inUseName string
 	if set != nil {
@@ -342,6 +351,10 @@ func getNewStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*apps.St
 			},
 		})
 	}
+	discoveryTLSMount, discoveryTLSVolume := discoveryTLSMountVolume(tc)
+	if tc.IsDiscoveryTLSEnabled() {
+		vols = append(vols, discoveryTLSVolume)
+	}
 
 	sysctls := "sysctl -w"
 	var initContainers []corev1.Container
@@ -387,6 +400,10 @@ func getNewStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*apps.St
 		{Name: "data0", MountPath: "/data0"},
 		{Name: "config", ReadOnly: true, MountPath: "/etc/tiflash"},
 	}
+	if tc.IsDiscoveryTLSEnabled() {
+		// the init container requests the discovery service
+		initVolMounts = append(initVolMounts, discoveryTLSMount)
+	}
 	initEnv := []corev1.EnvVar{
 		{
 			Name: "POD_NAME",
@@ -411,7 +428,7 @@ func getNewStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*apps.St
 set +e
 encoded_domain_url=$(echo $pd_url | base64 | tr "\n" " " | sed "s/ //g")
 discovery_url="%s-discovery.%s:10261"
-until result=$(wget -qO- -T 3 http://${discovery_url}/verify/${encoded_domain_url} 2>/dev/null | sed 's/http:\/\///g'); do
+until result=$(%s/verify/${encoded_domain_url} 2>/dev/null | sed 's/http:\/\///g'); do
 echo "waiting for the verification of PD endpoints ..."
 sleep 2
 done
@@ -421,7 +438,7 @@ sed -i s/PD_ADDR/${result}/g /data0/config.toml
 sed -i s/PD_ADDR/${result}/g /data0/proxy.toml
 `
 		script += "\n"
-		script += fmt.Sprintf(str, pdAddr, tc.GetName(), tc.GetNamespace())
+		script += fmt.Sprintf(str, pdAddr, tc.GetName(), tc.GetNamespace(), discoveryWget(tc.IsDiscoveryTLSEnabled()))
 	}
 
 	initContainers = append(initContainers, corev1.Container{
@@ -576,7 +593,7 @@ sed -i s/PD_ADDR/${result}/g /data0/proxy.toml
 			},
 			VolumeClaimTemplates: pvcs,
 			ServiceName:          headlessSvcName,
-			PodManagementPolicy:  apps.ParallelPodManagement,
+			PodManagementPolicy:  baseTiFlashSpec.PodManagementPolicy(),
 			UpdateStrategy:       updateStrategy,
 		},
 	}
@@ -688,7 +705,9 @@ func (m *tiflashMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, s
 		}
 
 		status.LastTransitionTime = metav1.Now()
-		if exist && status.State == oldStore.State {
+		// the store turns from Disconnected to Down when it's unreachable for a while,
+		// keep the transition time so that the failover deadline is not postponed
+		if exist && (status.State == oldStore.State || isTiFlashStoreUnreachable(status.State) && isTiFlashStoreUnreachable(oldStore.State)) {
 			status.LastTransitionTime = oldStore.LastTransitionTime
 		}
 
@@ -17,6 +17,7 @@ import (
 	"fmt"
 
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 
@@ -85,13 +86,14 @@ func (u *tiflashUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Statefu
 		return nil
 	}
 
-	setUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	oldPartition := *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition
+	setUpgradePartition(newSet, oldPartition)
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
 	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
 		i := podOrdinals[_i]
 		store := getTiFlashStoreByOrdinal(tc.GetName(), tc.Status.TiFlash, i)
 		if store == nil {
-			setUpgradePartition(newSet, i)
+			u.advancePartition(tc, newSet, oldPartition, i)
 			continue
 		}
 		podName := TiFlashPodName(tcName, i)
@@ -133,13 +135,27 @@ func (u *tiflashUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Statefu
 			continue
 		}
 
-		setUpgradePartition(newSet, i)
+		u.advancePartition(tc, newSet, oldPartition, i)
 		return nil
 	}
 
 	return nil
 }
 
+// advancePartition sets the partition of the StatefulSet to the ordinal, and records an event if the
+// partition advances to the ordinal from the partition of the existing StatefulSet. The partition
+// stays at the ordinal until the pod is upgraded, so the event is recorded once for each ordinal.
+func (u *tiflashUpgrader) advancePartition(tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, oldPartition int32, ordinal int32) {
+	setUpgradePartition(newSet, ordinal)
+	if ordinal >= oldPartition {
+		return
+	}
+	revision := tc.Status.TiFlash.StatefulSet.UpdateRevision
+	klog.Infof("tidbcluster: [%s/%s]'s TiFlash upgrade advances to ordinal %d, revision %s", tc.GetNamespace(), tc.GetName(), ordinal, revision)
+	u.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, controller.UpgradeProgress.For(v1alpha1.TiFlashMemberType),
+		"upgrading TiFlash pod %s (ordinal %d) to revision %s", TiFlashPodName(tc.GetName(), ordinal), ordinal, revision)
+}
+
 func getTiFlashStoreByOrdinal(name string, status v1alpha1.TiFlashStatus, ordinal int32) *v1alpha1.TiKVStore {
 	podName := TiFlashPodName(name, ordinal)
 	for _, store := range status.Stores {
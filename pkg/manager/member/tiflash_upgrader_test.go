@@ -24,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	podinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
@@ -514,6 +515,68 @@ func TestTiFlashUpgraderUpgrade(t *testing.T) {
 	}
 }
 
+func TestTiFlashUpgraderProgressEvents(t *testing.T) {
+	tests := []struct {
+		name            string
+		oldPartition    int32
+		upgradedPods    int32
+		expectPartition int32
+		expectEvent     string
+	}{
+		{
+			name:            "advance the partition to ordinal 2",
+			oldPartition:    3,
+			expectPartition: 2,
+			expectEvent:     "upgrading TiFlash pod upgrader-tiflash-2 (ordinal 2) to revision 2",
+		},
+		{
+			name:            "wait for the upgrade of ordinal 2",
+			oldPartition:    2,
+			expectPartition: 2,
+		},
+		{
+			name:            "advance the partition to ordinal 1",
+			oldPartition:    2,
+			upgradedPods:    1,
+			expectPartition: 1,
+			expectEvent:     "upgrading TiFlash pod upgrader-tiflash-1 (ordinal 1) to revision 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			upgrader, _, _, _, podInformer := newTiFlashUpgrader()
+			recorder := upgrader.(*tiflashUpgrader).deps.Recorder.(*record.FakeRecorder)
+			tc := newTidbClusterForTiFlashUpgrader()
+			tc.Status.PD.Phase = v1alpha1.NormalPhase
+
+			oldSet := oldStatefulSetForTiFlashUpgrader()
+			g.Expect(SetStatefulSetLastAppliedConfigAnnotation(oldSet)).To(Succeed())
+			oldSet.Spec.UpdateStrategy.RollingUpdate.Partition = pointer.Int32Ptr(tt.oldPartition)
+			oldSet.Status.CurrentReplicas -= tt.upgradedPods
+			oldSet.Status.UpdatedReplicas += tt.upgradedPods
+			newSet := newStatefulSetForTiFlashUpgrader()
+			for _, pod := range getTiFlashPods(oldSet) {
+				g.Expect(podInformer.Informer().GetIndexer().Add(pod)).To(Succeed())
+			}
+
+			g.Expect(upgrader.Upgrade(tc, oldSet, newSet)).To(Succeed())
+			g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(tt.expectPartition))
+			events := collectEvents(recorder.Events)
+			if tt.expectEvent == "" {
+				g.Expect(events).To(BeEmpty())
+				return
+			}
+			g.Expect(events).To(HaveLen(1))
+			g.Expect(events[0]).To(ContainSubstring(corev1.EventTypeNormal))
+			g.Expect(events[0]).To(ContainSubstring(controller.UpgradeProgress.For(v1alpha1.TiFlashMemberType)))
+			g.Expect(events[0]).To(ContainSubstring(tt.expectEvent))
+		})
+	}
+}
+
 func newTiFlashUpgrader() (Upgrader, *pdapi.FakePDControl, *tiflashapi.FakeTiFlashControl, *controller.FakePodControl, podinformers.PodInformer) {
 	fakeDeps := controller.NewFakeDependencies()
 	pdControl := fakeDeps.PDControl.(*pdapi.FakePDControl)
@@ -29,6 +29,8 @@ type tikvFailover struct {
 	deps *controller.Dependencies
 }
 
+var _ Failover = &tikvFailover{}
+
 // NewTiKVFailover returns a tikv Failover
 func NewTiKVFailover(deps *controller.Dependencies) Failover {
 	return &tikvFailover{deps: deps}
@@ -50,7 +52,8 @@ func (f *tikvFailover) Failover(tc *v1alpha1.TidbCluster) error {
 
 	for storeID, store := range tc.Status.TiKV.Stores {
 		podName := store.PodName
-		if store.LastTransitionTime.IsZero() {
+		downSince := tikvStoreDownTime(store)
+		if downSince.IsZero() {
 			continue
 		}
 		if !f.isPodDesired(tc, podName) {
@@ -59,7 +62,7 @@ func (f *tikvFailover) Failover(tc *v1alpha1.TidbCluster) error {
 			// (before it enters into Offline/Tombstone state)
 			continue
 		}
-		deadline := store.LastTransitionTime.Add(f.deps.CLIConfig.TiKVFailoverPeriod)
+		deadline := downSince.Add(f.deps.CLIConfig.TiKVFailoverPeriod)
 		exist := false
 		for _, failureStore := range tc.Status.TiKV.FailureStores {
 			if failureStore.PodName == podName {
@@ -68,6 +71,9 @@ func (f *tikvFailover) Failover(tc *v1alpha1.TidbCluster) error {
 			}
 		}
 		if store.State == v1alpha1.TiKVStateDown && time.Now().After(deadline) && !exist {
+			if err := checkClusterHealthFloor(f.deps, tc, v1alpha1.TiKVMemberType); err != nil {
+				return err
+			}
 			if tc.Status.TiKV.FailureStores == nil {
 				tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{}
 			}
@@ -83,14 +89,25 @@ func (f *tikvFailover) Failover(tc *v1alpha1.TidbCluster) error {
 					CreatedAt: metav1.Now(),
 				}
 				msg := fmt.Sprintf("store[%s] is Down", store.ID)
-				f.deps.Recorder.Event(tc, corev1.EventTypeWarning, unHealthEventReason, fmt.Sprintf(unHealthEventMsgPattern, "tikv", podName, msg))
+				controller.RecordEventInWindow(f.deps.Recorder, tc, tc.FailoverEventInterval(v1alpha1.TiKVMemberType), corev1.EventTypeWarning, unHealthEventReason, fmt.Sprintf(unHealthEventMsgPattern, "tikv", podName, msg))
 			}
 		}
 	}
 
+	newBlockingConditionReconciler(f.deps.Recorder, v1alpha1.FailoverBlocked, v1alpha1.TiKVMemberType).Resolve(tc)
 	return nil
 }
 
+// tikvStoreDownTime returns the time the store has been down since, the DownSince is preferred because
+// it isn't reset by the transitions between the states other than Up. The LastTransitionTime is used
+// for the status synced before the DownSince is tracked.
+func tikvStoreDownTime(store v1alpha1.TiKVStore) metav1.Time {
+	if store.DownSince != nil {
+		return *store.DownSince
+	}
+	return store.LastTransitionTime
+}
+
 func (f *tikvFailover) RemoveUndesiredFailures(tc *v1alpha1.TidbCluster) {
 	for key, failureStore := range tc.Status.TiKV.FailureStores {
 		if !f.isPodDesired(tc, failureStore.PodName) {
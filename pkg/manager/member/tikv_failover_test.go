@@ -20,7 +20,10 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 )
 
@@ -302,3 +305,106 @@ func TestTiKVFailoverFailover(t *testing.T) {
 		})
 	}
 }
+
+func TestTiKVFailoverClusterHealthFloor(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Spec.TiKV.Replicas = 3
+	tc.Spec.TiKV.MaxFailoverCount = pointer.Int32Ptr(3)
+	// pd is degraded but still in quorum
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+		"pd-0": {Name: "pd-0", ID: "0", Health: true},
+		"pd-1": {Name: "pd-1", ID: "1", Health: false},
+		"pd-2": {Name: "pd-2", ID: "2", Health: false},
+	}
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"1": {
+			ID:                 "1",
+			State:              v1alpha1.TiKVStateDown,
+			PodName:            "tikv-1",
+			LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
+		},
+		"2": {ID: "2", State: v1alpha1.TiKVStateUp, PodName: "tikv-2"},
+		"3": {ID: "3", State: v1alpha1.TiKVStateUp, PodName: "tikv-0"},
+	}
+
+	fakeDeps := controller.NewFakeDependencies()
+	fakeDeps.CLIConfig.TiKVFailoverPeriod = 1 * time.Hour
+	fakeDeps.CLIConfig.FailoverMinHealthyRatio = 0.6
+	recorder := fakeDeps.Recorder.(*record.FakeRecorder)
+	tikvFailover := &tikvFailover{deps: fakeDeps}
+
+	// 3 of 6 healthy members is below the floor
+	err := tikvFailover.Failover(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(tc.Status.TiKV.FailureStores).To(BeEmpty())
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, controller.ConditionTypeFor(v1alpha1.FailoverBlocked, v1alpha1.TiKVMemberType))
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(cond.Reason).To(Equal(utiltidbcluster.ClusterHealthBelowFloor))
+	events := collectEvents(recorder.Events)
+	g.Expect(events).To(HaveLen(1))
+	g.Expect(events[0]).To(ContainSubstring("Warning TiKVFailoverBlocked"))
+
+	// pd recovers
+	tc.Status.PD.Members["pd-1"] = v1alpha1.PDMember{Name: "pd-1", ID: "1", Health: true}
+	g.Expect(tikvFailover.Failover(tc)).To(Succeed())
+	g.Expect(tc.Status.TiKV.FailureStores).To(HaveKey("1"))
+	cond = utiltidbcluster.GetTidbClusterCondition(tc.Status, controller.ConditionTypeFor(v1alpha1.FailoverBlocked, v1alpha1.TiKVMemberType))
+	g.Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+}
+
+func TestTiKVFailoverDownSince(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Spec.TiKV.Replicas = 3
+	tc.Spec.TiKV.MaxFailoverCount = pointer.Int32Ptr(3)
+	fakeDeps := controller.NewFakeDependencies()
+	fakeDeps.CLIConfig.TiKVFailoverPeriod = 1 * time.Hour
+	tikvFailover := &tikvFailover{deps: fakeDeps}
+	ago := func(d time.Duration) metav1.Time {
+		return metav1.Time{Time: time.Now().Add(-d)}
+	}
+
+	// the store turns Down
+	store := v1alpha1.TiKVStore{ID: "1", State: v1alpha1.TiKVStateDown, PodName: "tikv-1"}
+	store.DownSince = tikvStoreDownSince(&store, nil)
+	g.Expect(store.DownSince).NotTo(BeNil())
+
+	// the store keeps Down before the deadline
+	downSince := ago(30 * time.Minute)
+	store.DownSince = &downSince
+	next := store
+	next.DownSince = tikvStoreDownSince(&next, &store)
+	g.Expect(next.DownSince).To(Equal(&downSince))
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{"1": next}
+	g.Expect(tikvFailover.Failover(tc)).To(Succeed())
+	g.Expect(tc.Status.TiKV.FailureStores).To(BeEmpty())
+
+	// the store recovers before the deadline
+	recovered := v1alpha1.TiKVStore{ID: "1", State: v1alpha1.TiKVStateUp, PodName: "tikv-1", LastTransitionTime: ago(0)}
+	recovered.DownSince = tikvStoreDownSince(&recovered, &next)
+	g.Expect(recovered.DownSince).To(BeNil())
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{"1": recovered}
+	g.Expect(tikvFailover.Failover(tc)).To(Succeed())
+	g.Expect(tc.Status.TiKV.FailureStores).To(BeEmpty())
+
+	// the store is Down past the deadline, though it transitioned recently, e.g. from Offline
+	downSince = ago(61 * time.Minute)
+	offline := v1alpha1.TiKVStore{ID: "1", State: v1alpha1.TiKVStateOffline, PodName: "tikv-1", DownSince: &downSince}
+	down := v1alpha1.TiKVStore{ID: "1", State: v1alpha1.TiKVStateDown, PodName: "tikv-1", LastTransitionTime: ago(time.Minute)}
+	down.DownSince = tikvStoreDownSince(&down, &offline)
+	g.Expect(down.DownSince).To(Equal(&downSince))
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{"1": down}
+	g.Expect(tikvFailover.Failover(tc)).To(Succeed())
+	g.Expect(tc.Status.TiKV.FailureStores).To(HaveKey("1"))
+
+	// the store has been Down before DownSince is tracked
+	lastTransitionTime := ago(2 * time.Hour)
+	tracked := v1alpha1.TiKVStore{ID: "1", State: v1alpha1.TiKVStateDown, PodName: "tikv-1", LastTransitionTime: lastTransitionTime}
+	untracked := tracked
+	tracked.DownSince = tikvStoreDownSince(&tracked, &untracked)
+	g.Expect(tracked.DownSince).To(Equal(&lastTransitionTime))
+}
@@ -21,6 +21,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/kvproto/pkg/metapb"
@@ -35,6 +36,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog"
@@ -114,7 +116,7 @@ func (m *tikvMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 }
 
 func (m *tikvMemberManager) syncServiceForTidbCluster(tc *v1alpha1.TidbCluster, svcConfig SvcConfig) error {
-	if tc.Spec.Paused {
+	if tc.ShouldPauseReconcile(v1alpha1.TiKVMemberType) {
 		klog.V(4).Infof("tikv cluster %s/%s is paused, skip syncing for tikv service", tc.GetNamespace(), tc.GetName())
 		return nil
 	}
@@ -123,6 +125,7 @@ func (m *tikvMemberManager) syncServiceForTidbCluster(tc *v1alpha1.TidbCluster,
 	tcName := tc.GetName()
 
 	newSvc := getNewServiceForTidbCluster(tc, svcConfig)
+	addStandardLabels(newSvc)
 	oldSvcTmp, err := m.deps.ServiceLister.Services(ns).Get(svcConfig.MemberName(tcName))
 	if errors.IsNotFound(err) {
 		err = controller.SetServiceLastAppliedConfigAnnotation(newSvc)
@@ -141,8 +144,10 @@ func (m *tikvMemberManager) syncServiceForTidbCluster(tc *v1alpha1.TidbCluster,
 	if err != nil {
 		return err
 	}
-	if !equal {
+	svcLabels, labelsChanged := mergeStandardLabels(newSvc, oldSvc)
+	if !equal || labelsChanged {
 		svc := *oldSvc
+		svc.Labels = svcLabels
 		svc.Spec = newSvc.Spec
 		// TODO add unit test
 		err = controller.SetServiceLastAppliedConfigAnnotation(&svc)
@@ -173,7 +178,7 @@ func (m *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 		return err
 	}
 
-	if tc.Spec.Paused {
+	if tc.ShouldPauseReconcile(v1alpha1.TiKVMemberType) {
 		klog.V(4).Infof("tikv cluster %s/%s is paused, skip syncing for tikv statefulset", tc.GetNamespace(), tc.GetName())
 		return nil
 	}
@@ -197,6 +202,7 @@ func (m *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 	if err != nil {
 		return err
 	}
+	addStandardLabelsToStatefulSet(newSet)
 	if setNotExist {
 		err = SetStatefulSetLastAppliedConfigAnnotation(newSet)
 		if err != nil {
@@ -210,10 +216,16 @@ func (m *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 		return nil
 	}
 
+	syncPodManagementPolicy(tc, newSet, oldSet)
+
 	if _, err := m.setStoreLabelsForTiKV(tc); err != nil {
 		return err
 	}
 
+	if err := m.removeAbandonedEvictLeaderSchedulers(tc); err != nil {
+		klog.Warningf("tikv: failed to remove the abandoned evict leader schedulers of %s/%s, %v", ns, tcName, err)
+	}
+
 	// Scaling takes precedence over upgrading because:
 	// - if a store fails in the upgrading, users may want to delete it or add
 	//   new replicas
@@ -248,10 +260,16 @@ func (m *tikvMemberManager) syncTiKVConfigMap(tc *v1alpha1.TidbCluster, set *app
 	if tc.Spec.TiKV.Config == nil {
 		return nil, nil
 	}
-	newCm, err := getTikVConfigMap(tc)
+	// the ConfigMap is only rendered again after what it's rendered from changes
+	cmKey := renderedConfigMapKey(tc.Namespace, controller.TiKVMemberName(tc.Name))
+	cmSpec := []interface{}{tc.UID, tc.Labels, tc.Spec}
+	newCm, err := m.deps.ConfigMapControl.RenderConfigMap(cmKey, cmSpec, func() (*corev1.ConfigMap, error) {
+		return getTikVConfigMap(tc)
+	})
 	if err != nil {
 		return nil, err
 	}
+	addStandardLabels(newCm)
 
 	var inUseName string
 	if set != nil {
@@ -339,6 +357,10 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 			})
 		}
 	}
+	discoveryTLSMount, discoveryTLSVolume := discoveryTLSMountVolume(tc)
+	if tc.IsDiscoveryTLSEnabled() {
+		volMounts = append(volMounts, discoveryTLSMount)
+	}
 
 	vols := []corev1.Volume{
 		annoVolume,
@@ -377,6 +399,9 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 			})
 		}
 	}
+	if tc.IsDiscoveryTLSEnabled() {
+		vols = append(vols, discoveryTLSVolume)
+	}
 	// handle StorageVolumes and AdditionalVolumeMounts in ComponentSpec
 	storageVolMounts, additionalPVCs := util.BuildStorageVolumeAndVolumeMount(tc.Spec.TiKV.StorageVolumes, tc.Spec.TiKV.StorageClassName, v1alpha1.TiKVMemberType)
 	volMounts = append(volMounts, storageVolMounts...)
@@ -583,7 +608,7 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 				util.VolumeClaimTemplate(storageRequest, v1alpha1.TiKVMemberType.String(), tc.Spec.TiKV.StorageClassName),
 			},
 			ServiceName:         headlessSvcName,
-			PodManagementPolicy: apps.ParallelPodManagement,
+			PodManagementPolicy: baseTiKVSpec.PodManagementPolicy(),
 			UpdateStrategy:      updateStrategy,
 		},
 	}
@@ -640,6 +665,7 @@ func getTikVConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 	}
 
 	scriptModel := &TiKVStartScriptModel{
+		DiscoveryStartScriptModel: DiscoveryStartScriptModel{DiscoveryTLSEnabled: tc.IsDiscoveryTLSEnabled()},
 		EnableAdvertiseStatusAddr: false,
 		DataDir:                   filepath.Join(tikvDataVolumeMountPath, tc.Spec.TiKV.DataSubDir),
 		ClusterDomain:             tc.Spec.ClusterDomain,
@@ -652,6 +678,8 @@ func getTikVConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 	if tc.HeterogeneousWithoutLocalPD() {
 		// TODO: for across k8s cluster, the start script do not support it now.
 		scriptModel.PDAddress = tc.Scheme() + "://" + controller.PDMemberName(tc.Spec.Cluster.Name) + ":2379"
+	} else if usesExternalPD(tc) {
+		scriptModel.PDAddress = strings.Join(tc.Spec.PDAddresses, ",")
 	} else {
 		scriptModel.PDAddress = tc.Scheme() + "://${CLUSTER_NAME}-pd:2379"
 	}
@@ -671,6 +699,27 @@ func getTikVConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 	return cm, nil
 }
 
+// tikvStoreDownSince returns the time the store first reported Down, it's set when the store turns Down
+// and cleared when it's Up again, the other states keep the one of the old store. It's seeded from the
+// LastTransitionTime of the store, e.g. for a store which has been Down before DownSince is tracked.
+func tikvStoreDownSince(store *v1alpha1.TiKVStore, oldStore *v1alpha1.TiKVStore) *metav1.Time {
+	if store.State == v1alpha1.TiKVStateUp {
+		return nil
+	}
+	if oldStore != nil && oldStore.DownSince != nil {
+		return oldStore.DownSince
+	}
+	if store.State == v1alpha1.TiKVStateDown {
+		if !store.LastTransitionTime.IsZero() {
+			downSince := store.LastTransitionTime
+			return &downSince
+		}
+		now := metav1.Now()
+		return &now
+	}
+	return nil
+}
+
 func labelTiKV(tc *v1alpha1.TidbCluster) label.Label {
 	instanceName := tc.GetInstanceName()
 	return label.New().Instance(instanceName).TiKV()
@@ -742,6 +791,11 @@ func (m *tikvMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 		if exist && status.State == oldStore.State {
 			status.LastTransitionTime = oldStore.LastTransitionTime
 		}
+		var previous *v1alpha1.TiKVStore
+		if exist {
+			previous = &oldStore
+		}
+		status.DownSince = tikvStoreDownSince(status, previous)
 
 		// In theory, the external tikv can join the cluster, and the operator would only manage the internal tikv.
 		// So we check the store owner to make sure it.
@@ -892,6 +946,98 @@ func (m *tikvMemberManager) storeLabelsEqualNodeLabels(storeLabels []*metapb.Sto
 	return reflect.DeepEqual(ls, nodeLabels)
 }
 
+// removeAbandonedEvictLeaderSchedulers ends the evict leader schedulers begun by the operator for the stores which
+// are no longer being evicted, e.g. the drain evicting the pod is aborted, or the eviction is refused or times out
+// without the pod being deleted, otherwise the store holds no region leader indefinitely. The eviction is abandoned
+// once the evict leader timeout of TiKV passes since the pod is annotated by the upgrader or the pod admission webhook,
+// the annotation is removed with the scheduler so that the next eviction of the pod evicts the region leaders again.
+// The schedulers of the pods without the annotation, e.g. the ones added with pd-ctl, are left as is.
+func (m *tikvMemberManager) removeAbandonedEvictLeaderSchedulers(tc *v1alpha1.TidbCluster) error {
+	if tc.Status.TiKV.Phase == v1alpha1.UpgradePhase {
+		return nil
+	}
+
+	ns := tc.GetNamespace()
+	now := time.Now()
+	timeout := tc.TiKVEvictLeaderTimeout()
+	abandoned := map[string]*corev1.Pod{}
+	for _, store := range tc.Status.TiKV.Stores {
+		pod, err := m.deps.PodLister.Pods(ns).Get(store.PodName)
+		if errors.IsNotFound(err) {
+			// the scheduler is ended by the pod admission webhook once the pod is recreated
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get pod %s, error: %v", store.PodName, err)
+		}
+		if pod.DeletionTimestamp == nil && isEvictLeaderExpired(pod, timeout, now) {
+			abandoned[store.ID] = pod
+		}
+	}
+	if len(abandoned) == 0 {
+		return nil
+	}
+
+	pdClient := controller.GetPDClient(m.deps.PDControl, tc)
+	schedulers, err := pdClient.GetEvictLeaderSchedulers()
+	if err != nil {
+		return fmt.Errorf("failed to get evict leader schedulers, error: %v", err)
+	}
+	// the name of the scheduler is evict-leader-scheduler-<store id>
+	storeIDs := sets.NewString()
+	for _, s := range schedulers {
+		storeIDs.Insert(s[strings.LastIndex(s, "-")+1:])
+	}
+
+	for id, pod := range abandoned {
+		if storeIDs.Has(id) {
+			storeID, err := strconv.ParseUint(id, 10, 64)
+			if err != nil {
+				continue
+			}
+			if err := pdClient.EndEvictLeader(storeID); err != nil {
+				return fmt.Errorf("failed to end evict leader for store %d, error: %v", storeID, err)
+			}
+			klog.Infof("tikv: ended the abandoned evict leader scheduler of store %d of pod %s/%s", storeID, ns, pod.Name)
+		}
+
+		newPod := pod.DeepCopy()
+		for _, key := range evictLeaderAnnotationKeys {
+			delete(newPod.Annotations, key)
+		}
+		if _, err := m.deps.PodControl.UpdatePod(tc, newPod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evictLeaderAnnotationKeys are the annotations of the begin time of evicting the region leaders of a TiKV pod,
+// set by the upgrader and the pod admission webhook respectively
+var evictLeaderAnnotationKeys = []string{EvictLeaderBeginTime, label.AnnEvictLeaderBeginTime}
+
+// isEvictLeaderExpired returns whether the TiKV pod is annotated with the begin time of evicting the region leaders,
+// and the timeout has passed since then
+func isEvictLeaderExpired(pod *corev1.Pod, timeout time.Duration, now time.Time) bool {
+	annotated := false
+	for _, key := range evictLeaderAnnotationKeys {
+		beginTimeStr, ok := pod.Annotations[key]
+		if !ok {
+			continue
+		}
+		annotated = true
+		beginTime, err := time.Parse(time.RFC3339, beginTimeStr)
+		if err != nil {
+			klog.Errorf("parse annotation:[%s] of pod %s/%s to time failed, %v", key, pod.Namespace, pod.Name, err)
+			continue
+		}
+		if now.Before(beginTime.Add(timeout)) {
+			return false
+		}
+	}
+	return annotated
+}
+
 func tikvStatefulSetIsUpgrading(podLister corelisters.PodLister, pdControl pdapi.PDControlInterface, set *apps.StatefulSet, tc *v1alpha1.TidbCluster) (bool, error) {
 	if statefulSetIsUpgrading(set) {
 		return true, nil
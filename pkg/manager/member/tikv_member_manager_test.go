@@ -388,6 +388,32 @@ func TestTiKVMemberManagerSyncUpdate(t *testing.T) {
 			},
 			expectTidbClusterFn: nil,
 		},
+		{
+			name: "tikv is paused",
+			modify: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV.Paused = true
+				tc.Spec.TiKV.Replicas = 5
+				tc.Spec.TiKV.SeparateRaftLog = pointer.BoolPtr(true)
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+			},
+			pdStores:                     &pdapi.StoresInfo{Count: 0, Stores: []*pdapi.StoreInfo{}},
+			tombstoneStores:              &pdapi.StoresInfo{Count: 0, Stores: []*pdapi.StoreInfo{}},
+			errWhenUpdateStatefulSet:     true,
+			errWhenUpdateTiKVPeerService: true,
+			errWhenGetStores:             false,
+			err:                          false,
+			expectTiKVPeerServiceFn:      nil,
+			expectStatefulSetFn: func(g *GomegaWithT, set *apps.StatefulSet, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(int(*set.Spec.Replicas)).To(Equal(3))
+				g.Expect(set.Spec.Template.Spec.Containers).To(HaveLen(1))
+			},
+			expectTidbClusterFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster) {
+				// the status is still synced
+				g.Expect(tc.Status.TiKV.StatefulSet.ObservedGeneration).To(Equal(int64(1)))
+				g.Expect(tc.Status.TiKV.Stores).To(Equal(map[string]v1alpha1.TiKVStore{}))
+			},
+		},
 	}
 
 	for i := range tests {
@@ -1766,6 +1792,46 @@ func TestGetNewTiKVSetForTidbCluster(t *testing.T) {
 			},
 			testSts: testAnnotations(t, map[string]string{"delete-slots": "[0,1]"}),
 		},
+		{
+			name: "tikv pod management policy is Parallel by default",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					TiDB: &v1alpha1.TiDBSpec{},
+					TiKV: &v1alpha1.TiKVSpec{},
+					PD:   &v1alpha1.PDSpec{},
+				},
+			},
+			testSts: func(sts *apps.StatefulSet) {
+				g := NewGomegaWithT(t)
+				g.Expect(sts.Spec.PodManagementPolicy).To(Equal(apps.ParallelPodManagement))
+			},
+		},
+		{
+			name: "tikv should respect pod management policy",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					TiDB: &v1alpha1.TiDBSpec{},
+					TiKV: &v1alpha1.TiKVSpec{
+						ComponentSpec: v1alpha1.ComponentSpec{
+							PodManagementPolicy: apps.OrderedReadyPodManagement,
+						},
+					},
+					PD: &v1alpha1.PDSpec{},
+				},
+			},
+			testSts: func(sts *apps.StatefulSet) {
+				g := NewGomegaWithT(t)
+				g.Expect(sts.Spec.PodManagementPolicy).To(Equal(apps.OrderedReadyPodManagement))
+			},
+		},
 		{
 			name: "tikv should respect resources config",
 			tc: v1alpha1.TidbCluster{
@@ -2471,6 +2537,121 @@ func TestTiKVBackupConfig(t *testing.T) {
 	}
 }
 
+func TestTiKVMemberManagerRemoveAbandonedEvictLeaderSchedulers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	newPod := func(name string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   corev1.NamespaceDefault,
+				Annotations: annotations,
+			},
+		}
+	}
+	evictingSince := func(key string, ago time.Duration) map[string]string {
+		return map[string]string{key: time.Now().Add(-ago).Format(time.RFC3339)}
+	}
+
+	tests := []struct {
+		name          string
+		phase         v1alpha1.MemberPhase
+		pods          []*corev1.Pod
+		expectEnded   []uint64
+		expectEvicted []string
+	}{
+		{
+			name:  "the evictions of a drain are abandoned",
+			phase: v1alpha1.NormalPhase,
+			pods: []*corev1.Pod{
+				// the eviction is refused or times out without the pod being deleted
+				newPod("test-tikv-0", evictingSince(label.AnnEvictLeaderBeginTime, 10*time.Minute)),
+				// the eviction is in progress
+				newPod("test-tikv-1", evictingSince(label.AnnEvictLeaderBeginTime, time.Minute)),
+				// the eviction is in progress by the upgrader
+				newPod("test-tikv-2", evictingSince(EvictLeaderBeginTime, time.Minute)),
+				// the scheduler is added with pd-ctl
+				newPod("test-tikv-3", nil),
+				// the eviction is abandoned after the scheduler is ended
+				newPod("test-tikv-5", evictingSince(label.AnnEvictLeaderBeginTime, 10*time.Minute)),
+			},
+			expectEnded:   []uint64{1},
+			expectEvicted: []string{"test-tikv-1", "test-tikv-2"},
+		},
+		{
+			name:  "the pod is being deleted",
+			phase: v1alpha1.NormalPhase,
+			pods: []*corev1.Pod{
+				func() *corev1.Pod {
+					pod := newPod("test-tikv-0", evictingSince(label.AnnEvictLeaderBeginTime, 10*time.Minute))
+					pod.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+					return pod
+				}(),
+			},
+			expectEvicted: []string{"test-tikv-0"},
+		},
+		{
+			name:  "the stores are being upgraded",
+			phase: v1alpha1.UpgradePhase,
+			pods: []*corev1.Pod{
+				newPod("test-tikv-0", evictingSince(EvictLeaderBeginTime, 10*time.Minute)),
+				newPod("test-tikv-3", nil),
+			},
+			expectEvicted: []string{"test-tikv-0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test: %s", test.name)
+		tc := newTidbClusterForTiKV()
+		tc.Spec.TiKV.EvictLeaderTimeout = pointer.StringPtr("5m")
+		tc.Status.TiKV.Phase = test.phase
+		tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+			"1": {ID: "1", PodName: "test-tikv-0"},
+			"2": {ID: "2", PodName: "test-tikv-1"},
+			"3": {ID: "3", PodName: "test-tikv-2"},
+			"4": {ID: "4", PodName: "test-tikv-3"},
+			// the pod is being recreated
+			"5": {ID: "5", PodName: "test-tikv-4"},
+			"6": {ID: "6", PodName: "test-tikv-5"},
+		}
+		tkmm, _, _, pdClient, podIndexer, _ := newFakeTiKVMemberManager(tc)
+		for _, pod := range test.pods {
+			g.Expect(podIndexer.Add(pod)).To(Succeed())
+		}
+		pdClient.AddReaction(pdapi.GetEvictLeaderSchedulersActionType, func(action *pdapi.Action) (interface{}, error) {
+			// the scheduler of store 9 belongs to another cluster
+			return []string{
+				"evict-leader-scheduler-1",
+				"evict-leader-scheduler-2",
+				"evict-leader-scheduler-3",
+				"evict-leader-scheduler-4",
+				"evict-leader-scheduler-5",
+				"evict-leader-scheduler-9",
+			}, nil
+		})
+		var ended []uint64
+		pdClient.AddReaction(pdapi.EndEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+			ended = append(ended, action.ID)
+			return nil, nil
+		})
+
+		g.Expect(tkmm.removeAbandonedEvictLeaderSchedulers(tc)).To(Succeed())
+		g.Expect(ended).To(ConsistOf(test.expectEnded))
+
+		// the annotations are removed with the schedulers, so that the next eviction evicts the leaders again
+		var evicted []string
+		for _, pod := range test.pods {
+			pod, err := tkmm.deps.PodLister.Pods(pod.Namespace).Get(pod.Name)
+			g.Expect(err).NotTo(HaveOccurred())
+			if len(pod.Annotations) > 0 {
+				evicted = append(evicted, pod.Name)
+			}
+		}
+		g.Expect(evicted).To(ConsistOf(test.expectEvicted))
+	}
+}
+
 func newTidbClusterForTiKV() *v1alpha1.TidbCluster {
 	return &v1alpha1.TidbCluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -14,6 +14,7 @@
 package member
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"time"
@@ -22,15 +23,29 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/util"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	apps "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 )
 
+const (
+	volumeSnapshotGroup   = "snapshot.storage.k8s.io"
+	volumeSnapshotVersion = "v1beta1"
+	volumeSnapshotKind    = "VolumeSnapshot"
+)
+
 type tikvScaler struct {
 	generalScaler
 }
@@ -60,14 +75,23 @@ func (s *tikvScaler) ScaleOut(meta metav1.Object, oldSet *apps.StatefulSet, newS
 	}
 	klog.Infof("scaling out tikv statefulset %s/%s, ordinal: %d (replicas: %d, delete slots: %v)", oldSet.Namespace, oldSet.Name, ordinal, replicas, deleteSlots.List())
 	var pvcName string
-	switch meta.(type) {
+	var dataSource *v1.TypedLocalObjectReference
+	switch meta := meta.(type) {
 	case *v1alpha1.TidbCluster:
-		pvcName = fmt.Sprintf("tikv-%s-tikv-%d", meta.GetName(), ordinal)
+		pvcName = ordinalPVCName(v1alpha1.TiKVMemberType, controller.TiKVMemberName(meta.GetName()), ordinal)
+		if meta.Spec.TiKV.ScalePolicy != nil {
+			dataSource = meta.Spec.TiKV.ScalePolicy.NewPVCDataSource
+		}
 	default:
 		return fmt.Errorf("tikv.ScaleOut, failed to convert cluster %s/%s", meta.GetNamespace(), meta.GetName())
 	}
-	_, err := s.deps.PVCLister.PersistentVolumeClaims(meta.GetNamespace()).Get(pvcName)
+	pvc, err := s.deps.PVCLister.PersistentVolumeClaims(meta.GetNamespace()).Get(pvcName)
 	if err == nil {
+		if isPreCreatedPVC(pvc, dataSource) {
+			// the PVC is pre-created in the previous round, it will be adopted by the StatefulSet
+			setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
+			return nil
+		}
 		_, err = s.deleteDeferDeletingPVC(obj, v1alpha1.TiKVMemberType, ordinal)
 		if err != nil {
 			return err
@@ -76,14 +100,129 @@ func (s *tikvScaler) ScaleOut(meta metav1.Object, oldSet *apps.StatefulSet, newS
 	} else if !errors.IsNotFound(err) {
 		return fmt.Errorf("tikv.ScaleOut, cluster %s/%s failed to fetch pvc informaiton, err:%v", meta.GetNamespace(), meta.GetName(), err)
 	}
+	if dataSource != nil {
+		if err := s.preCreatePVC(meta.(*v1alpha1.TidbCluster), oldSet, pvcName, ordinal, dataSource); err != nil {
+			return err
+		}
+	}
 	setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
 	return nil
 }
 
+// preCreatePVC creates the PVC of the tikv data volume for the new ordinal with the data source,
+// the StatefulSet controller adopts the PVC by its name instead of creating an empty one.
+// The PVC is not created and the StatefulSet controller creates an empty one if the data source
+// can't provide a volume of the requested size.
+func (s *tikvScaler) preCreatePVC(tc *v1alpha1.TidbCluster, set *apps.StatefulSet, pvcName string, ordinal int32, dataSource *v1.TypedLocalObjectReference) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	var template *v1.PersistentVolumeClaim
+	for i := range set.Spec.VolumeClaimTemplates {
+		if set.Spec.VolumeClaimTemplates[i].Name == v1alpha1.TiKVMemberType.String() {
+			template = &set.Spec.VolumeClaimTemplates[i]
+			break
+		}
+	}
+	if template == nil {
+		return fmt.Errorf("tikv.ScaleOut, volume claim template %s is not found in statefulset %s/%s", v1alpha1.TiKVMemberType, ns, set.Name)
+	}
+
+	request := template.Spec.Resources.Requests[v1.ResourceStorage]
+	restoreSize, err := s.getSnapshotRestoreSize(ns, dataSource)
+	if err != nil {
+		return fmt.Errorf("tikv.ScaleOut, cluster %s/%s failed to get data source %s %s, err: %v", ns, tcName, dataSource.Kind, dataSource.Name, err)
+	}
+	if restoreSize != nil && restoreSize.Cmp(request) > 0 {
+		msg := fmt.Sprintf("restore size %s of %s %s is larger than the requested storage %s, create pvc %s without the data source",
+			restoreSize.String(), dataSource.Kind, dataSource.Name, request.String(), pvcName)
+		klog.Warningf("tikv.ScaleOut, cluster %s/%s: %s", ns, tcName, msg)
+		s.deps.Recorder.Event(tc, v1.EventTypeWarning, "FailedPreCreatePVC", msg)
+		return nil
+	}
+
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        pvcName,
+			Namespace:   ns,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+	for k, v := range template.Labels {
+		pvc.Labels[k] = v
+	}
+	for k, v := range template.Annotations {
+		pvc.Annotations[k] = v
+	}
+	// the same labels as the ones set by the StatefulSet controller and tidb-operator
+	if set.Spec.Selector != nil {
+		for k, v := range set.Spec.Selector.MatchLabels {
+			pvc.Labels[k] = v
+		}
+	}
+	pvc.Annotations[label.AnnPodNameKey] = TikvPodName(tcName, ordinal)
+	pvc.Spec.DataSource = dataSource.DeepCopy()
+
+	err = s.deps.PVCControl.CreatePVC(tc, pvc)
+	if errors.IsAlreadyExists(err) {
+		// the PVC may be created by the StatefulSet controller or the previous round,
+		// check it again in the next round
+		return controller.RequeueErrorf("tikv.ScaleOut, pvc %s/%s already exists, wait for next round", ns, pvcName)
+	}
+	if err != nil {
+		return fmt.Errorf("tikv.ScaleOut, cluster %s/%s failed to pre-create pvc %s, err: %v", ns, tcName, pvcName, err)
+	}
+	klog.Infof("tikv.ScaleOut, cluster %s/%s pre-created pvc %s with data source %s %s", ns, tcName, pvcName, dataSource.Kind, dataSource.Name)
+	return nil
+}
+
+// getSnapshotRestoreSize returns the restore size of the data source if it's a VolumeSnapshot,
+// nil is returned if the restore size is unknown.
+func (s *tikvScaler) getSnapshotRestoreSize(ns string, dataSource *v1.TypedLocalObjectReference) (*resource.Quantity, error) {
+	if dataSource.Kind != volumeSnapshotKind || dataSource.APIGroup == nil || *dataSource.APIGroup != volumeSnapshotGroup {
+		return nil, nil
+	}
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(schema.GroupVersionKind{Group: volumeSnapshotGroup, Version: volumeSnapshotVersion, Kind: volumeSnapshotKind})
+	err := s.deps.GenericClient.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: dataSource.Name}, snapshot)
+	if errors.IsForbidden(err) || meta.IsNoMatchError(err) {
+		// the operator may be deployed without the permission of VolumeSnapshots or the CRD is not installed
+		klog.Warningf("tikv.ScaleOut, failed to get the restore size of %s %s/%s, err: %v", dataSource.Kind, ns, dataSource.Name, err)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	size, found, err := unstructured.NestedString(snapshot.Object, "status", "restoreSize")
+	if err != nil || !found {
+		return nil, err
+	}
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return nil, err
+	}
+	return &quantity, nil
+}
+
+// isPreCreatedPVC returns whether the PVC is pre-created by preCreatePVC
+func isPreCreatedPVC(pvc *v1.PersistentVolumeClaim, dataSource *v1.TypedLocalObjectReference) bool {
+	if dataSource == nil || pvc.Spec.DataSource == nil {
+		return false
+	}
+	if _, ok := pvc.Annotations[label.AnnPVCDeferDeleting]; ok {
+		return false
+	}
+	return apiequality.Semantic.DeepEqual(pvc.Spec.DataSource, dataSource)
+}
+
 func (s *tikvScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	ns := meta.GetNamespace()
 	tcName := meta.GetName()
-	// we can only remove one member at a time when scaling in
+	// we can only remove one member at a time when scaling in,
+	// the ordinal is the highest one of the ordinals leaving the StatefulSet,
+	// which may not be the highest ordinal of the StatefulSet if delete slots are used
 	_, ordinal, replicas, deleteSlots := scaleOne(oldSet, newSet)
 	resetReplicas(newSet, oldSet)
 
@@ -108,6 +247,16 @@ func (s *tikvScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSe
 		return err
 	}
 
+	// run the user-provided pre scale-in Job before the store is deleted
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.PodName == podName && store.State != v1alpha1.TiKVStateOffline {
+			if done, err := s.preScaleInJobDone(tc, podName, store.ID); !done {
+				return err
+			}
+			break
+		}
+	}
+
 	if s.deps.CLIConfig.PodWebhookEnabled {
 		setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
 		return nil
@@ -122,6 +271,9 @@ func (s *tikvScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSe
 				return err
 			}
 			if state != v1alpha1.TiKVStateOffline {
+				if pass, err := s.preCheckLastReplica(tc, podName, id); !pass {
+					return err
+				}
 				if err := controller.GetPDClient(s.deps.PDControl, tc).DeleteStore(id); err != nil {
 					klog.Errorf("tikvScaler.ScaleIn: failed to delete store %d, %v", id, err)
 					return err
@@ -253,6 +405,134 @@ func (s *tikvScaler) preCheckUpStores(tc *v1alpha1.TidbCluster, podName string)
 	return true, nil
 }
 
+// preCheckLastReplica checks whether the store holds the last replica of any region,
+// the data of such a region would be lost if the store is deleted.
+func (s *tikvScaler) preCheckLastReplica(tc *v1alpha1.TidbCluster, podName string, storeID uint64) (bool, error) {
+	regions, err := controller.GetPDClient(s.deps.PDControl, tc).GetRegionsByStore(storeID)
+	if err != nil {
+		return false, err
+	}
+	for _, region := range regions.Regions {
+		lastReplica := true
+		for _, peer := range region.Peers {
+			if peer.GetStoreId() != storeID {
+				lastReplica = false
+				break
+			}
+		}
+		if lastReplica {
+			errMsg := fmt.Sprintf("can't scale in TiKV of TidbCluster [%s/%s], cause the store %d in Pod %s holds the last replica of region %d", tc.GetNamespace(), tc.GetName(), storeID, podName, region.ID)
+			klog.Error(errMsg)
+			s.deps.Recorder.Event(tc, v1.EventTypeWarning, "FailedScaleIn", errMsg)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// preScaleInJobDone checks whether the pre scale-in Job of the store has completed,
+// and creates the Job if it does not exist yet.
+// A failed Job blocks the scale-in, a running Job blocks the scale-in until it times out.
+func (s *tikvScaler) preScaleInJobDone(tc *v1alpha1.TidbCluster, podName, storeID string) (bool, error) {
+	if tc.Spec.TiKV == nil || tc.Spec.TiKV.ScalePolicy == nil || tc.Spec.TiKV.ScalePolicy.PreScaleInJobTemplate == nil {
+		return true, nil
+	}
+	ns := tc.GetNamespace()
+	jobName := preScaleInJobName(podName, storeID)
+
+	job, err := s.deps.JobLister.Jobs(ns).Get(jobName)
+	if errors.IsNotFound(err) {
+		job = newPreScaleInJob(tc, podName, storeID)
+		if err := s.deps.JobControl.CreateJob(tc, job); err != nil {
+			return false, err
+		}
+		return false, controller.RequeueErrorf("tikvScaler.ScaleIn: pre scale-in job %s/%s for store %s is created, wait for it to complete", ns, jobName, storeID)
+	} else if err != nil {
+		return false, fmt.Errorf("tikvScaler.ScaleIn: failed to get pre scale-in job %s/%s, error: %v", ns, jobName, err)
+	}
+
+	if jobConditionIsTrue(job, batchv1.JobComplete) {
+		klog.Infof("tikvScaler.ScaleIn: pre scale-in job %s/%s for store %s completed", ns, jobName, storeID)
+		utiltidbcluster.SetTidbClusterCondition(&tc.Status, *utiltidbcluster.NewTidbClusterCondition(
+			v1alpha1.TiKVPreScaleInJobFailed, v1.ConditionFalse, utiltidbcluster.PreScaleInJobComplete,
+			fmt.Sprintf("pre scale-in job %s completed", jobName)))
+		return true, nil
+	}
+
+	if jobConditionIsTrue(job, batchv1.JobFailed) {
+		msg := fmt.Sprintf("pre scale-in job %s/%s for store %s of pod %s failed, scale-in is blocked", ns, jobName, storeID, podName)
+		klog.Error(msg)
+		s.deps.Recorder.Event(tc, v1.EventTypeWarning, "FailedPreScaleInJob", msg)
+		utiltidbcluster.SetTidbClusterCondition(&tc.Status, *utiltidbcluster.NewTidbClusterCondition(
+			v1alpha1.TiKVPreScaleInJobFailed, v1.ConditionTrue, utiltidbcluster.PreScaleInJobFailed, msg))
+		return false, fmt.Errorf("tikvScaler.ScaleIn: %s", msg)
+	}
+
+	timeout := tc.TiKVPreScaleInJobTimeout()
+	if timeout > 0 && time.Now().After(job.CreationTimestamp.Add(timeout)) {
+		msg := fmt.Sprintf("pre scale-in job %s/%s for store %s of pod %s does not complete in %v, proceed to scale in", ns, jobName, storeID, podName, timeout)
+		klog.Warning(msg)
+		s.deps.Recorder.Event(tc, v1.EventTypeWarning, "PreScaleInJobTimeout", msg)
+		return true, nil
+	}
+
+	return false, controller.RequeueErrorf("tikvScaler.ScaleIn: pre scale-in job %s/%s for store %s is running", ns, jobName, storeID)
+}
+
+func preScaleInJobName(podName, storeID string) string {
+	return fmt.Sprintf("%s-pre-scale-in-%s", podName, storeID)
+}
+
+// newPreScaleInJob builds the pre scale-in Job from the template in TiKV spec,
+// the Job is owned by the TidbCluster so that it will be garbage collected with it.
+func newPreScaleInJob(tc *v1alpha1.TidbCluster, podName, storeID string) *batchv1.Job {
+	tmpl := tc.Spec.TiKV.ScalePolicy.PreScaleInJobTemplate.DeepCopy()
+
+	jobLabels := label.New().Instance(tc.GetInstanceName()).Component(label.PreScaleInJobLabelVal)
+	jobLabels[label.StoreIDLabelKey] = storeID
+	envs := []v1.EnvVar{
+		{
+			Name:  "POD_NAME",
+			Value: podName,
+		},
+		{
+			Name:  "STORE_ID",
+			Value: storeID,
+		},
+		{
+			Name:  "PD_ADDRESS",
+			Value: fmt.Sprintf("%s://%s:2379", tc.Scheme(), controller.PDMemberName(tc.Name)),
+		},
+	}
+	podSpec := &tmpl.Spec.Template.Spec
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].Env = util.AppendOverwriteEnv(podSpec.Containers[i].Env, envs)
+	}
+	if podSpec.RestartPolicy == "" {
+		podSpec.RestartPolicy = v1.RestartPolicyNever
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            preScaleInJobName(podName, storeID),
+			Namespace:       tc.GetNamespace(),
+			Labels:          util.CombineStringMap(tmpl.Labels, jobLabels),
+			Annotations:     tmpl.Annotations,
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Spec: tmpl.Spec,
+	}
+}
+
+func jobConditionIsTrue(job *batchv1.Job, condType batchv1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == condType && c.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
 type fakeTiKVScaler struct{}
 
 // NewFakeTiKVScaler returns a fake tikv Scaler
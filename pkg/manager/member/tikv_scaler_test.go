@@ -14,20 +14,31 @@
 package member
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 )
 
@@ -144,6 +155,142 @@ func TestTiKVScalerScaleOut(t *testing.T) {
 	}
 }
 
+func TestTiKVScalerScaleOutWithNewPVCDataSource(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name         string
+		restoreSize  string
+		preCreated   bool
+		createPVCErr error
+		errExpectFn  func(*GomegaWithT, error)
+		changed      bool
+		expectFn     func(*GomegaWithT, *corev1.PersistentVolumeClaim, []string)
+	}
+
+	apiGroup := "snapshot.storage.k8s.io"
+	dataSource := &corev1.TypedLocalObjectReference{
+		APIGroup: &apiGroup,
+		Kind:     "VolumeSnapshot",
+		Name:     "tikv-snapshot",
+	}
+
+	testFn := func(test testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		tc.Spec.TiKV.ScalePolicy = &v1alpha1.TiKVScalePolicy{NewPVCDataSource: dataSource}
+		tc.Status.TiKV.BootStrapped = true
+
+		oldSet := newStatefulSetForPDScale()
+		oldSet.Name = fmt.Sprintf("%s-tikv", tc.Name)
+		oldSet.Spec.Selector = label.New().Instance(tc.Name).TiKV().LabelSelector()
+		oldSet.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
+			util.VolumeClaimTemplate(corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("100Gi"),
+				},
+			}, v1alpha1.TiKVMemberType.String(), pointer.StringPtr("my-storage-class")),
+		}
+		newSet := oldSet.DeepCopy()
+		newSet.Spec.Replicas = pointer.Int32Ptr(7)
+
+		scaler, _, pvcIndexer, _, pvcControl := newFakeTiKVScaler()
+		recorder := scaler.deps.Recorder.(*record.FakeRecorder)
+
+		if test.restoreSize != "" {
+			snapshot := &unstructured.Unstructured{}
+			snapshot.SetGroupVersionKind(schema.GroupVersionKind{Group: apiGroup, Version: "v1beta1", Kind: "VolumeSnapshot"})
+			snapshot.SetNamespace(tc.Namespace)
+			snapshot.SetName(dataSource.Name)
+			g.Expect(unstructured.SetNestedField(snapshot.Object, test.restoreSize, "status", "restoreSize")).To(Succeed())
+			g.Expect(scaler.deps.GenericClient.Create(context.TODO(), snapshot)).To(Succeed())
+		}
+		if test.preCreated {
+			pvc := _newPVCForStatefulSet(oldSet, v1alpha1.TiKVMemberType, tc.Name, 5)
+			pvc.Spec.DataSource = dataSource.DeepCopy()
+			pvcIndexer.Add(pvc)
+		}
+		if test.createPVCErr != nil {
+			pvcControl.SetCreatePVCError(test.createPVCErr, 0)
+		}
+
+		err := scaler.ScaleOut(tc, oldSet, newSet)
+		test.errExpectFn(g, err)
+		if test.changed {
+			g.Expect(int(*newSet.Spec.Replicas)).To(Equal(6))
+		} else {
+			g.Expect(int(*newSet.Spec.Replicas)).To(Equal(5))
+		}
+
+		pvc, err := scaler.deps.PVCLister.PersistentVolumeClaims(tc.Namespace).Get(ordinalPVCName(v1alpha1.TiKVMemberType, oldSet.Name, 5))
+		if err != nil {
+			g.Expect(errors.IsNotFound(err)).To(BeTrue())
+			pvc = nil
+		}
+		if test.expectFn != nil {
+			test.expectFn(g, pvc, collectEvents(recorder.Events))
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:        "pre-create pvc with the data source",
+			restoreSize: "50Gi",
+			errExpectFn: errExpectNil,
+			changed:     true,
+			expectFn: func(g *GomegaWithT, pvc *corev1.PersistentVolumeClaim, events []string) {
+				g.Expect(pvc).NotTo(BeNil())
+				g.Expect(pvc.Spec.DataSource).To(Equal(dataSource))
+				g.Expect(pvc.Spec.StorageClassName).To(Equal(pointer.StringPtr("my-storage-class")))
+				g.Expect(pvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("100Gi")))
+				g.Expect(pvc.Labels).To(HaveKeyWithValue(label.InstanceLabelKey, "test"))
+				g.Expect(pvc.Labels).To(HaveKeyWithValue(label.ComponentLabelKey, label.TiKVLabelVal))
+				g.Expect(pvc.Annotations).To(HaveKeyWithValue(label.AnnPodNameKey, "test-tikv-5"))
+				g.Expect(events).To(BeEmpty())
+			},
+		},
+		{
+			name:        "restore size of the snapshot is larger than the requested storage",
+			restoreSize: "200Gi",
+			errExpectFn: errExpectNil,
+			changed:     true,
+			expectFn: func(g *GomegaWithT, pvc *corev1.PersistentVolumeClaim, events []string) {
+				g.Expect(pvc).To(BeNil())
+				g.Expect(events).To(HaveLen(1))
+				g.Expect(events[0]).To(ContainSubstring("FailedPreCreatePVC"))
+			},
+		},
+		{
+			name:        "snapshot is not found",
+			errExpectFn: errExpectNotNil,
+			changed:     false,
+			expectFn: func(g *GomegaWithT, pvc *corev1.PersistentVolumeClaim, events []string) {
+				g.Expect(pvc).To(BeNil())
+			},
+		},
+		{
+			name:        "pvc is pre-created in the previous round",
+			preCreated:  true,
+			errExpectFn: errExpectNil,
+			changed:     true,
+			expectFn: func(g *GomegaWithT, pvc *corev1.PersistentVolumeClaim, events []string) {
+				g.Expect(pvc).NotTo(BeNil())
+				g.Expect(pvc.Spec.DataSource).To(Equal(dataSource))
+			},
+		},
+		{
+			name:         "pvc already exists when pre-creating",
+			restoreSize:  "50Gi",
+			createPVCErr: errors.NewAlreadyExists(corev1.Resource("persistentvolumeclaims"), "tikv-test-tikv-5"),
+			errExpectFn:  errExpectRequeue,
+			changed:      false,
+		},
+	}
+
+	for i := range tests {
+		testFn(tests[i], t)
+	}
+}
+
 func TestTiKVScalerScaleIn(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
@@ -543,6 +690,341 @@ func TestTiKVScalerScaleIn(t *testing.T) {
 	}
 }
 
+func TestTiKVScalerScaleInWithDeleteSlots(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name             string
+		storeFun         func(tc *v1alpha1.TidbCluster)
+		lastReplica      bool
+		errExpectFn      func(*GomegaWithT, error)
+		deletedStore     uint64
+		changed          bool
+		pvcDeferDeleting bool
+	}
+
+	testFn := func(test testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		test.storeFun(tc)
+		tc.Status.TiKV.BootStrapped = true
+
+		// scale in ordinal 1 out of 0..3
+		oldSet := newStatefulSetForPDScale()
+		oldSet.Spec.Replicas = pointer.Int32Ptr(4)
+		newSet := oldSet.DeepCopy()
+		newSet.Spec.Replicas = pointer.Int32Ptr(3)
+		newSet.Annotations = map[string]string{
+			helper.DeleteSlotsAnn: "[1]",
+		}
+
+		scaler, pdControl, pvcIndexer, podIndexer, _ := newFakeTiKVScaler()
+
+		for _, ordinal := range []int32{0, 1, 2, 3} {
+			pvc := _newPVCForStatefulSet(oldSet, v1alpha1.TiKVMemberType, tc.Name, ordinal)
+			pvcIndexer.Add(pvc)
+			pod := &corev1.Pod{
+				TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      TikvPodName(tc.GetName(), ordinal),
+					Namespace: corev1.NamespaceDefault,
+					Labels: map[string]string{
+						label.StoreIDLabelKey: fmt.Sprintf("1%d", ordinal),
+					},
+				},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: pvc.Name,
+								},
+							},
+						},
+					},
+				},
+			}
+			readyPodFunc(pod)
+			podIndexer.Add(pod)
+		}
+
+		pdClient := controller.NewFakePDClient(pdControl, tc)
+		pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+			var replicas uint64 = 3
+			return &pdapi.PDConfigFromAPI{
+				Replication: &pdapi.PDReplicationConfig{
+					MaxReplicas: &replicas,
+				},
+			}, nil
+		})
+		pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+			store := &pdapi.StoreInfo{
+				Store: &pdapi.MetaStore{
+					StateName: v1alpha1.TiKVStateUp,
+					Store: &metapb.Store{
+						Address: fmt.Sprintf("%s-tikv-0", "basic"),
+					},
+				},
+			}
+			return &pdapi.StoresInfo{
+				Count:  4,
+				Stores: []*pdapi.StoreInfo{store, store, store, store},
+			}, nil
+		})
+		pdClient.AddReaction(pdapi.GetRegionsByStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+			peers := []*metapb.Peer{{StoreId: action.ID}}
+			if !test.lastReplica {
+				peers = append(peers, &metapb.Peer{StoreId: 10}, &metapb.Peer{StoreId: 13})
+			}
+			return &pdapi.RegionsInfo{
+				Count:   1,
+				Regions: []*pdapi.RegionInfo{{ID: 100, Peers: peers}},
+			}, nil
+		})
+		var deletedStore uint64
+		pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+			deletedStore = action.ID
+			return nil, nil
+		})
+
+		err := scaler.ScaleIn(tc, oldSet, newSet)
+		test.errExpectFn(g, err)
+		g.Expect(deletedStore).To(Equal(test.deletedStore))
+		if test.changed {
+			g.Expect(int(*newSet.Spec.Replicas)).To(Equal(3))
+			g.Expect(helper.GetDeleteSlots(newSet).List()).To(Equal([]int32{1}))
+		} else {
+			g.Expect(int(*newSet.Spec.Replicas)).To(Equal(4))
+			g.Expect(helper.GetDeleteSlots(newSet).Len()).To(Equal(0))
+		}
+
+		for _, ordinal := range []int32{0, 1, 2, 3} {
+			pvc, err := scaler.deps.PVCLister.PersistentVolumeClaims(corev1.NamespaceDefault).Get(ordinalPVCName(v1alpha1.TiKVMemberType, oldSet.GetName(), ordinal))
+			g.Expect(err).NotTo(HaveOccurred())
+			_, deferDeleting := pvc.Annotations[label.AnnPVCDeferDeleting]
+			g.Expect(deferDeleting).To(Equal(test.pvcDeferDeleting && ordinal == 1))
+		}
+	}
+
+	features.DefaultFeatureGate.Set("AdvancedStatefulSet=true")
+	defer features.DefaultFeatureGate.Set("AdvancedStatefulSet=false")
+	tests := []testcase{
+		{
+			name:         "store of ordinal 1 is up, delete the store",
+			storeFun:     normalStoreFun,
+			errExpectFn:  errExpectRequeue,
+			deletedStore: 11,
+			changed:      false,
+		},
+		{
+			name:         "store of ordinal 1 holds the last replica of a region",
+			storeFun:     normalStoreFun,
+			lastReplica:  true,
+			errExpectFn:  errExpectNil,
+			deletedStore: 0,
+			changed:      false,
+		},
+		{
+			name: "store of ordinal 1 is offline",
+			storeFun: func(tc *v1alpha1.TidbCluster) {
+				normalStoreFun(tc)
+				store := tc.Status.TiKV.Stores["11"]
+				store.State = v1alpha1.TiKVStateOffline
+				tc.Status.TiKV.Stores["11"] = store
+			},
+			errExpectFn:  errExpectRequeue,
+			deletedStore: 0,
+			changed:      false,
+		},
+		{
+			name: "store of ordinal 1 is tombstone",
+			storeFun: func(tc *v1alpha1.TidbCluster) {
+				normalStoreFun(tc)
+				delete(tc.Status.TiKV.Stores, "11")
+				tc.Status.TiKV.TombstoneStores = map[string]v1alpha1.TiKVStore{
+					"11": {
+						ID:      "11",
+						PodName: ordinalPodName(v1alpha1.TiKVMemberType, tc.GetName(), 1),
+						State:   v1alpha1.TiKVStateTombstone,
+					},
+				}
+			},
+			errExpectFn:      errExpectNil,
+			deletedStore:     0,
+			changed:          true,
+			pvcDeferDeleting: true,
+		},
+	}
+
+	for i := range tests {
+		testFn(tests[i], t)
+	}
+}
+
+func TestTiKVScalerPreScaleInJob(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name         string
+		noTemplate   bool
+		jobCondition batchv1.JobConditionType
+		jobAge       time.Duration
+		timeout      *string
+		errExpectFn  func(*GomegaWithT, error)
+		storeDeleted bool
+		condition    corev1.ConditionStatus
+	}
+
+	testFn := func(test testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		normalStoreFun(tc)
+		tc.Status.TiKV.BootStrapped = true
+		if !test.noTemplate {
+			tc.Spec.TiKV.ScalePolicy = &v1alpha1.TiKVScalePolicy{
+				PreScaleInJobTemplate: &batchv1beta1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "verify", Image: "busybox"}},
+							},
+						},
+					},
+				},
+				PreScaleInJobTimeout: test.timeout,
+			}
+		}
+
+		oldSet := newStatefulSetForPDScale()
+		newSet := oldSet.DeepCopy()
+		newSet.Spec.Replicas = pointer.Int32Ptr(3)
+
+		pod := &corev1.Pod{
+			TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      TikvPodName(tc.GetName(), 4),
+				Namespace: corev1.NamespaceDefault,
+			},
+		}
+		readyPodFunc(pod)
+
+		scaler, pdControl, _, podIndexer, _ := newFakeTiKVScaler()
+		podIndexer.Add(pod)
+		jobIndexer := scaler.deps.KubeInformerFactory.Batch().V1().Jobs().Informer().GetIndexer()
+		if test.jobCondition != "" {
+			job := newPreScaleInJob(tc, pod.Name, "1")
+			job.CreationTimestamp = metav1.Time{Time: time.Now().Add(-test.jobAge)}
+			job.Status.Conditions = []batchv1.JobCondition{{Type: test.jobCondition, Status: corev1.ConditionTrue}}
+			if test.jobCondition == "Running" {
+				job.Status.Conditions = nil
+			}
+			jobIndexer.Add(job)
+		}
+
+		pdClient := controller.NewFakePDClient(pdControl, tc)
+		pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+			var replicas uint64 = 3
+			return &pdapi.PDConfigFromAPI{
+				Replication: &pdapi.PDReplicationConfig{
+					MaxReplicas: &replicas,
+				},
+			}, nil
+		})
+		pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+			store := &pdapi.StoreInfo{
+				Store: &pdapi.MetaStore{
+					StateName: v1alpha1.TiKVStateUp,
+					Store:     &metapb.Store{},
+				},
+			}
+			return &pdapi.StoresInfo{
+				Count:  5,
+				Stores: []*pdapi.StoreInfo{store, store, store, store, store},
+			}, nil
+		})
+		storeDeleted := false
+		pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+			storeDeleted = true
+			return nil, nil
+		})
+
+		err := scaler.ScaleIn(tc, oldSet, newSet)
+		test.errExpectFn(g, err)
+		g.Expect(storeDeleted).To(Equal(test.storeDeleted))
+		g.Expect(int(*newSet.Spec.Replicas)).To(Equal(5))
+
+		if !test.noTemplate {
+			job, err := scaler.deps.JobLister.Jobs(tc.Namespace).Get(preScaleInJobName(pod.Name, "1"))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(job.OwnerReferences).To(HaveLen(1))
+			g.Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "STORE_ID", Value: "1"}))
+			g.Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "POD_NAME", Value: pod.Name}))
+		}
+		cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TiKVPreScaleInJobFailed)
+		if test.condition == "" {
+			g.Expect(cond).To(BeNil())
+		} else {
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(test.condition))
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:         "no pre scale-in job template",
+			noTemplate:   true,
+			errExpectFn:  errExpectRequeue,
+			storeDeleted: true,
+		},
+		{
+			name:         "job not created",
+			errExpectFn:  errExpectRequeue,
+			storeDeleted: false,
+		},
+		{
+			name:         "job running",
+			jobCondition: "Running",
+			errExpectFn:  errExpectRequeue,
+			storeDeleted: false,
+		},
+		{
+			name:         "job complete",
+			jobCondition: batchv1.JobComplete,
+			errExpectFn:  errExpectRequeue,
+			storeDeleted: true,
+			condition:    corev1.ConditionFalse,
+		},
+		{
+			name:         "job failed",
+			jobCondition: batchv1.JobFailed,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(controller.IsRequeueError(err)).To(BeFalse())
+			},
+			storeDeleted: false,
+			condition:    corev1.ConditionTrue,
+		},
+		{
+			name:         "job running and timed out",
+			jobCondition: "Running",
+			jobAge:       time.Hour,
+			timeout:      pointer.StringPtr("10m"),
+			errExpectFn:  errExpectRequeue,
+			storeDeleted: true,
+		},
+		{
+			name:         "job running and not timed out",
+			jobCondition: "Running",
+			jobAge:       time.Minute,
+			timeout:      pointer.StringPtr("10m"),
+			errExpectFn:  errExpectRequeue,
+			storeDeleted: false,
+		},
+	}
+
+	for i := range tests {
+		testFn(tests[i], t)
+	}
+}
+
 func newFakeTiKVScaler(resyncDuration ...time.Duration) (*tikvScaler, *pdapi.FakePDControl, cache.Indexer, cache.Indexer, *controller.FakePVCControl) {
 	fakeDeps := controller.NewFakeDependencies()
 	if len(resyncDuration) > 0 {
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -93,7 +94,9 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 		return nil
 	}
 
+	blocking := newBlockingConditionReconciler(u.deps.Recorder, v1alpha1.UpgradeBlocked, v1alpha1.TiKVMemberType)
 	if status.StatefulSet.UpdateRevision == status.StatefulSet.CurrentRevision {
+		blocking.Resolve(tc)
 		return nil
 	}
 
@@ -129,9 +132,11 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 		if revision == status.StatefulSet.UpdateRevision {
 
 			if !podutil.IsPodReady(pod) {
+				blocking.Block(tc, utiltidbcluster.UpgradedPodNotReady, fmt.Sprintf("upgraded pod %s is not ready", podName))
 				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tikv pod: [%s] is not ready", ns, tcName, podName)
 			}
 			if store.State != v1alpha1.TiKVStateUp {
+				blocking.Block(tc, utiltidbcluster.UpgradedStoreNotUp, fmt.Sprintf("store %s of upgraded pod %s is %s", store.ID, podName, store.State))
 				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tikv pod: [%s] is not all ready", ns, tcName, podName)
 			}
 
@@ -149,6 +154,9 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 			continue
 		}
 
+		// all the upgraded pods are ready
+		blocking.Resolve(tc)
+
 		if u.deps.CLIConfig.PodWebhookEnabled {
 			setUpgradePartition(newSet, i)
 			return nil
@@ -157,6 +165,7 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 		return u.upgradeTiKVPod(tc, i, newSet)
 	}
 
+	blocking.Resolve(tc)
 	return nil
 }
 
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
@@ -26,6 +27,8 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/util/toml"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/util"
+	utildmcluster "github.com/pingcap/tidb-operator/pkg/util/dmcluster"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
@@ -46,8 +49,18 @@ const (
 	ImagePullBackOff = "ImagePullBackOff"
 	// ErrImagePull is the pod state of image pull failed
 	ErrImagePull = "ErrImagePull"
+	// ReadWriteOncePod is the access mode of the volume which can only be mounted by a single Pod,
+	// it's not defined by the vendored k8s.io/api yet.
+	ReadWriteOncePod corev1.PersistentVolumeAccessMode = "ReadWriteOncePod"
 )
 
+// componentPaused returns whether the reconciliation of the component of the object with the labels is paused,
+// only the components of a TidbCluster can be paused
+func componentPaused(meta metav1.Object, l label.Label) bool {
+	tc, ok := meta.(*v1alpha1.TidbCluster)
+	return ok && tc.ShouldPauseReconcile(v1alpha1.MemberType(l[label.ComponentLabelKey]))
+}
+
 func annotationsMountVolume() (corev1.VolumeMount, corev1.Volume) {
 	m := corev1.VolumeMount{Name: "annotations", ReadOnly: true, MountPath: "/etc/podinfo"}
 	v := corev1.Volume{
@@ -66,6 +79,21 @@ func annotationsMountVolume() (corev1.VolumeMount, corev1.Volume) {
 	return m, v
 }
 
+// discoveryTLSMountVolume returns the mount and volume of the CA to verify the certificate served by the discovery service
+func discoveryTLSMountVolume(tc *v1alpha1.TidbCluster) (corev1.VolumeMount, corev1.Volume) {
+	m := corev1.VolumeMount{Name: "discovery-tls", ReadOnly: true, MountPath: path.Dir(discoveryTLSCAPath)}
+	v := corev1.Volume{
+		Name: "discovery-tls",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: tc.Spec.Discovery.TLSSecret,
+				Items:      []corev1.KeyToPath{{Key: tlsSecretRootCAKey, Path: path.Base(discoveryTLSCAPath)}},
+			},
+		},
+	}
+	return m, v
+}
+
 // statefulSetIsUpgrading confirms whether the statefulSet is upgrading phase
 func statefulSetIsUpgrading(set *apps.StatefulSet) bool {
 	if set.Status.CurrentRevision != set.Status.UpdateRevision {
@@ -151,6 +179,17 @@ func tidbPodName(tcName string, ordinal int32) string {
 	return fmt.Sprintf("%s-%d", controller.TiDBMemberName(tcName), ordinal)
 }
 
+// renderedConfigMapKey returns the key of the ConfigMap of a component cached by RenderConfigMap
+func renderedConfigMapKey(ns, memberName string) string {
+	return fmt.Sprintf("%s/%s", ns, memberName)
+}
+
+// ForgetRenderedConfigMaps drops the cached ConfigMaps of the components of the deleted TidbCluster
+func ForgetRenderedConfigMaps(control controller.ConfigMapControlInterface, ns, tcName string) {
+	control.ForgetRenderedConfigMap(renderedConfigMapKey(ns, controller.PDMemberName(tcName)))
+	control.ForgetRenderedConfigMap(renderedConfigMapKey(ns, controller.TiKVMemberName(tcName)))
+}
+
 func ticdcPodName(tcName string, ordinal int32) string {
 	return fmt.Sprintf("%s-%d", controller.TiCDCMemberName(tcName), ordinal)
 }
@@ -159,6 +198,10 @@ func DMMasterPodName(dcName string, ordinal int32) string {
 	return fmt.Sprintf("%s-%d", controller.DMMasterMemberName(dcName), ordinal)
 }
 
+func DMWorkerPodName(dcName string, ordinal int32) string {
+	return fmt.Sprintf("%s-%d", controller.DMWorkerMemberName(dcName), ordinal)
+}
+
 func PdName(tcName string, ordinal int32, namespace string, clusterDomain string) string {
 	if len(clusterDomain) > 0 {
 		return fmt.Sprintf("%s.%s-pd-peer.%s.svc.%s", PdPodName(tcName, ordinal), tcName, namespace, clusterDomain)
@@ -266,10 +309,11 @@ func UpdateStatefulSet(setCtl controller.StatefulSetControlInterface, object run
 	if oldSet.Annotations == nil {
 		oldSet.Annotations = map[string]string{}
 	}
+	stageStandardPodLabels(newSet, oldSet)
 
 	// Check if an upgrade is needed.
 	// If not, early return.
-	if util.StatefulSetEqual(*newSet, *oldSet) && !isOrphan {
+	if util.StatefulSetEqual(*newSet, *oldSet) && util.IsSubMapOf(newSet.Labels, oldSet.Labels) && !isOrphan {
 		return nil
 	}
 
@@ -311,6 +355,68 @@ func UpdateStatefulSet(setCtl controller.StatefulSetControlInterface, object run
 	return err
 }
 
+// syncPodManagementPolicy keeps the podManagementPolicy of the existing StatefulSet because the field
+// is immutable, and reports the difference from the desired one by the PodManagementPolicyNotApplied
+// condition of the TidbCluster or DMCluster. The condition is cleared when the StatefulSet matches again.
+func syncPodManagementPolicy(obj runtime.Object, newSet, oldSet *apps.StatefulSet) {
+	desired := newSet.Spec.PodManagementPolicy
+	current := oldSet.Spec.PodManagementPolicy
+	if len(current) == 0 {
+		// defaulted by the api server
+		current = apps.OrderedReadyPodManagement
+	}
+	newSet.Spec.PodManagementPolicy = oldSet.Spec.PodManagementPolicy
+
+	msgPrefix := fmt.Sprintf("StatefulSet %s/%s", oldSet.Namespace, oldSet.Name)
+	if desired != current {
+		msg := fmt.Sprintf("%s: podManagementPolicy %s is not applied, the StatefulSet uses %s and the field is immutable, recreate the StatefulSet to apply it", msgPrefix, desired, current)
+		klog.Warning(msg)
+		setPodManagementPolicyCondition(obj, corev1.ConditionTrue, msg)
+		return
+	}
+
+	status, message := getPodManagementPolicyCondition(obj)
+	if status == corev1.ConditionTrue && strings.HasPrefix(message, msgPrefix+":") {
+		setPodManagementPolicyCondition(obj, corev1.ConditionFalse, fmt.Sprintf("%s: podManagementPolicy %s is applied", msgPrefix, current))
+	}
+}
+
+// getPodManagementPolicyCondition returns the status and message of the PodManagementPolicyNotApplied
+// condition of the TidbCluster or DMCluster, the status is empty if there is no such condition
+func getPodManagementPolicyCondition(obj runtime.Object) (corev1.ConditionStatus, string) {
+	switch o := obj.(type) {
+	case *v1alpha1.TidbCluster:
+		if cond := utiltidbcluster.GetTidbClusterCondition(o.Status, v1alpha1.PodManagementPolicyNotApplied); cond != nil {
+			return cond.Status, cond.Message
+		}
+	case *v1alpha1.DMCluster:
+		if cond := utildmcluster.GetDMClusterCondition(o.Status, v1alpha1.DMClusterPodManagementPolicyNotApplied); cond != nil {
+			return cond.Status, cond.Message
+		}
+	}
+	return "", ""
+}
+
+// setPodManagementPolicyCondition sets the PodManagementPolicyNotApplied condition of the TidbCluster or DMCluster
+func setPodManagementPolicyCondition(obj runtime.Object, status corev1.ConditionStatus, msg string) {
+	switch o := obj.(type) {
+	case *v1alpha1.TidbCluster:
+		reason := utiltidbcluster.PodManagementPolicyApplied
+		if status == corev1.ConditionTrue {
+			reason = utiltidbcluster.PodManagementPolicyChanged
+		}
+		cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.PodManagementPolicyNotApplied, status, reason, msg)
+		utiltidbcluster.SetTidbClusterCondition(&o.Status, *cond)
+	case *v1alpha1.DMCluster:
+		reason := utildmcluster.PodManagementPolicyApplied
+		if status == corev1.ConditionTrue {
+			reason = utildmcluster.PodManagementPolicyChanged
+		}
+		cond := utildmcluster.NewDMClusterCondition(v1alpha1.DMClusterPodManagementPolicyNotApplied, status, reason, msg)
+		utildmcluster.SetDMClusterCondition(&o.Status, *cond)
+	}
+}
+
 // findContainerByName finds targetContainer by containerName, If not find, then return nil
 func findContainerByName(sts *apps.StatefulSet, containerName string) *corev1.Container {
 	for _, c := range sts.Spec.Template.Spec.Containers {
@@ -532,3 +638,16 @@ func GetPVCSelectorForPod(controller runtime.Object, memberType v1alpha1.MemberT
 	}
 	return l.Selector()
 }
+
+// hasReadWriteOncePodPVC returns whether any of the PVCs has the ReadWriteOncePod access mode,
+// such a PVC can't be mounted by another Pod until the Pod using it is gone.
+func hasReadWriteOncePodPVC(pvcs []*corev1.PersistentVolumeClaim) bool {
+	for _, pvc := range pvcs {
+		for _, mode := range pvc.Spec.AccessModes {
+			if mode == ReadWriteOncePod {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -26,6 +26,8 @@ import (
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	utildmcluster "github.com/pingcap/tidb-operator/pkg/util/dmcluster"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
@@ -800,6 +802,62 @@ func TestCombineAnnotations(t *testing.T) {
 	}
 }
 
+func TestSyncPodManagementPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	newSts := func(name string, policy apps.PodManagementPolicyType) *apps.StatefulSet {
+		return &apps.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault},
+			Spec:       apps.StatefulSetSpec{PodManagementPolicy: policy},
+		}
+	}
+	getCond := func(tc *v1alpha1.TidbCluster) *v1alpha1.TidbClusterCondition {
+		return utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.PodManagementPolicyNotApplied)
+	}
+
+	tc := newTidbClusterForPD()
+
+	// the policy of an existing StatefulSet is kept and the drift is reported
+	newSet := newSts("test-tikv", apps.ParallelPodManagement)
+	syncPodManagementPolicy(tc, newSet, newSts("test-tikv", ""))
+	g.Expect(newSet.Spec.PodManagementPolicy).To(BeEmpty())
+	cond := getCond(tc)
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(cond.Reason).To(Equal(utiltidbcluster.PodManagementPolicyChanged))
+	g.Expect(cond.Message).To(ContainSubstring("StatefulSet default/test-tikv: podManagementPolicy Parallel is not applied"))
+
+	// another StatefulSet without drift does not clear the condition
+	newSet = newSts("test-tidb", apps.ParallelPodManagement)
+	syncPodManagementPolicy(tc, newSet, newSts("test-tidb", apps.ParallelPodManagement))
+	g.Expect(newSet.Spec.PodManagementPolicy).To(Equal(apps.ParallelPodManagement))
+	g.Expect(getCond(tc).Status).To(Equal(corev1.ConditionTrue))
+
+	// the condition is cleared when the StatefulSet matches again
+	newSet = newSts("test-tikv", apps.OrderedReadyPodManagement)
+	syncPodManagementPolicy(tc, newSet, newSts("test-tikv", ""))
+	g.Expect(newSet.Spec.PodManagementPolicy).To(BeEmpty())
+	cond = getCond(tc)
+	g.Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(cond.Reason).To(Equal(utiltidbcluster.PodManagementPolicyApplied))
+
+	// the drift of the StatefulSets of a DMCluster is reported by the DMCluster
+	dc := newDMClusterForMaster()
+	newSet = newSts("test-dm-worker", apps.OrderedReadyPodManagement)
+	syncPodManagementPolicy(dc, newSet, newSts("test-dm-worker", apps.ParallelPodManagement))
+	g.Expect(newSet.Spec.PodManagementPolicy).To(Equal(apps.ParallelPodManagement))
+	dcCond := utildmcluster.GetDMClusterCondition(dc.Status, v1alpha1.DMClusterPodManagementPolicyNotApplied)
+	g.Expect(dcCond).NotTo(BeNil())
+	g.Expect(dcCond.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(dcCond.Reason).To(Equal(utildmcluster.PodManagementPolicyChanged))
+
+	newSet = newSts("test-dm-worker", apps.ParallelPodManagement)
+	syncPodManagementPolicy(dc, newSet, newSts("test-dm-worker", apps.ParallelPodManagement))
+	dcCond = utildmcluster.GetDMClusterCondition(dc.Status, v1alpha1.DMClusterPodManagementPolicyNotApplied)
+	g.Expect(dcCond.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(dcCond.Reason).To(Equal(utildmcluster.PodManagementPolicyApplied))
+}
+
 func TestMemberPodName(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -50,6 +50,10 @@ func (m *metaManager) Sync(tc *v1alpha1.TidbCluster) error {
 	}
 
 	for _, pod := range pods {
+		if tc.ShouldPauseReconcile(v1alpha1.MemberType(pod.Labels[label.ComponentLabelKey])) {
+			klog.V(4).Infof("the component of pod %s/%s is paused, skip syncing meta info", ns, pod.GetName())
+			continue
+		}
 		// update meta info for pod
 		_, err := m.deps.PodControl.UpdateMetaInfo(tc, pod)
 		if err != nil {
@@ -46,11 +46,13 @@ func TestMetaManagerSync(t *testing.T) {
 		podChanged       bool
 		pvcChanged       bool
 		pvChanged        bool
+		paused           bool
 	}
 
 	testFn := func(test *testcase, t *testing.T) {
 		t.Log(test.name)
 		tc := newTidbClusterForMeta()
+		tc.Spec.Paused = test.paused
 		ns := tc.GetNamespace()
 		pv1 := newPV("1")
 		pvc1 := newPVC(tc, "1")
@@ -184,6 +186,23 @@ func TestMetaManagerSync(t *testing.T) {
 			pvcChanged:       true,
 			pvChanged:        true,
 		},
+		{
+			name:             "paused",
+			podHasLabels:     true,
+			pvcHasLabels:     true,
+			pvcHasVolumeName: true,
+			podRefPvc:        true,
+			podUpdateErr:     false,
+			getClusterErr:    false,
+			getMemberErr:     false,
+			getStoreErr:      false,
+			pvcUpdateErr:     false,
+			pvUpdateErr:      false,
+			podChanged:       false,
+			pvcChanged:       false,
+			pvChanged:        false,
+			paused:           true,
+		},
 		{
 			name:             "pod don't have labels",
 			podHasLabels:     false,
@@ -0,0 +1,195 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/manager"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+// ownerRefManager heals the owner references of the objects of a TidbCluster which is deleted and
+// recreated with the same name. The objects surviving the deletion, e.g. the PVCs, still reference the
+// UID of the deleted TidbCluster and would be deleted by the garbage collector of Kubernetes.
+//
+// The stale owner reference is replaced with the one of the live TidbCluster, except for PVCs and PVs
+// of a TidbCluster whose PV reclaim policy is Retain, which are orphaned instead so that the data
+// survive any later deletion of the TidbCluster as well.
+type ownerRefManager struct {
+	deps *controller.Dependencies
+}
+
+// NewOwnerRefManager returns a *ownerRefManager
+func NewOwnerRefManager(deps *controller.Dependencies) manager.Manager {
+	return &ownerRefManager{
+		deps: deps,
+	}
+}
+
+func (m *ownerRefManager) Sync(tc *v1alpha1.TidbCluster) error {
+	if tc.GetUID() == "" {
+		return nil
+	}
+	ns := tc.GetNamespace()
+	instanceName := tc.GetInstanceName()
+	selector, err := label.New().Instance(instanceName).Selector()
+	if err != nil {
+		return err
+	}
+
+	pvcs, err := m.deps.PVCLister.PersistentVolumeClaims(ns).List(selector)
+	if err != nil {
+		return fmt.Errorf("ownerRefManager.Sync: failed to list pvcs for cluster %s/%s, selector: %s, error: %v", ns, instanceName, selector, err)
+	}
+	retain := tc.Spec.PVReclaimPolicy != nil && *tc.Spec.PVReclaimPolicy == corev1.PersistentVolumeReclaimRetain
+	for _, pvc := range pvcs {
+		if componentPaused(pvc, tc) {
+			continue
+		}
+		if pvc.Spec.VolumeName != "" && m.deps.PVLister != nil {
+			pv, err := m.deps.PVLister.Get(pvc.Spec.VolumeName)
+			if errors.IsNotFound(err) {
+				// the PV may be deleted already, the owner reference of the PVC is still healed
+				klog.V(4).Infof("ownerRefManager.Sync: pv %s of cluster %s/%s is not found, skip", pvc.Spec.VolumeName, ns, instanceName)
+			} else if err != nil {
+				return fmt.Errorf("ownerRefManager.Sync: failed to get pv %s for cluster %s/%s, error: %v", pvc.Spec.VolumeName, ns, instanceName, err)
+			} else if refs, ok := healOwnerRefs(pv, tc, true); ok {
+				// a cluster-scoped PV can not be owned by a namespaced TidbCluster, so the stale reference is always removed
+				klog.Infof("ownerRefManager.Sync: remove the stale owner reference of pv %s of cluster %s/%s", pv.Name, ns, instanceName)
+				pv = pv.DeepCopy()
+				pv.OwnerReferences = refs
+				if _, err := m.deps.PVControl.UpdatePV(tc, pv); err != nil {
+					return err
+				}
+			}
+		}
+		if refs, ok := healOwnerRefs(pvc, tc, retain); ok {
+			klog.Infof("ownerRefManager.Sync: heal the stale owner reference of pvc %s/%s of cluster %s/%s, retain: %t", ns, pvc.Name, ns, instanceName, retain)
+			pvc = pvc.DeepCopy()
+			pvc.OwnerReferences = refs
+			if _, err := m.deps.PVCControl.UpdatePVC(tc, pvc); err != nil {
+				return err
+			}
+		}
+	}
+
+	sets, err := m.deps.StatefulSetLister.StatefulSets(ns).List(selector)
+	if err != nil {
+		return fmt.Errorf("ownerRefManager.Sync: failed to list statefulsets for cluster %s/%s, selector: %s, error: %v", ns, instanceName, selector, err)
+	}
+	for _, set := range sets {
+		if componentPaused(set, tc) {
+			continue
+		}
+		if refs, ok := healOwnerRefs(set, tc, false); ok {
+			klog.Infof("ownerRefManager.Sync: re-own statefulset %s/%s by cluster %s/%s", ns, set.Name, ns, instanceName)
+			set = set.DeepCopy()
+			set.OwnerReferences = refs
+			if _, err := m.deps.StatefulSetControl.UpdateStatefulSet(tc, set); err != nil {
+				return err
+			}
+		}
+	}
+
+	svcs, err := m.deps.ServiceLister.Services(ns).List(selector)
+	if err != nil {
+		return fmt.Errorf("ownerRefManager.Sync: failed to list services for cluster %s/%s, selector: %s, error: %v", ns, instanceName, selector, err)
+	}
+	for _, svc := range svcs {
+		if componentPaused(svc, tc) {
+			continue
+		}
+		if refs, ok := healOwnerRefs(svc, tc, false); ok {
+			klog.Infof("ownerRefManager.Sync: re-own service %s/%s by cluster %s/%s", ns, svc.Name, ns, instanceName)
+			svc = svc.DeepCopy()
+			svc.OwnerReferences = refs
+			if _, err := m.deps.ServiceControl.UpdateService(tc, svc); err != nil {
+				return err
+			}
+		}
+	}
+
+	cms, err := m.deps.ConfigMapLister.ConfigMaps(ns).List(selector)
+	if err != nil {
+		return fmt.Errorf("ownerRefManager.Sync: failed to list configmaps for cluster %s/%s, selector: %s, error: %v", ns, instanceName, selector, err)
+	}
+	for _, cm := range cms {
+		if componentPaused(cm, tc) {
+			continue
+		}
+		if refs, ok := healOwnerRefs(cm, tc, false); ok {
+			klog.Infof("ownerRefManager.Sync: re-own configmap %s/%s by cluster %s/%s", ns, cm.Name, ns, instanceName)
+			cm = cm.DeepCopy()
+			cm.OwnerReferences = refs
+			if _, err := m.deps.ConfigMapControl.UpdateConfigMap(tc, cm); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// componentPaused returns whether the reconciliation of the component of the object is paused, the objects
+// of the paused components are left untouched
+func componentPaused(obj metav1.Object, tc *v1alpha1.TidbCluster) bool {
+	return tc.ShouldPauseReconcile(v1alpha1.MemberType(obj.GetLabels()[label.ComponentLabelKey]))
+}
+
+// healOwnerRefs returns the healed owner references of obj and true if the controller of obj is a
+// TidbCluster with the name of tc but a different UID. The stale owner reference is removed if
+// orphan is true, or replaced with the owner reference of tc otherwise.
+func healOwnerRefs(obj metav1.Object, tc *v1alpha1.TidbCluster, orphan bool) ([]metav1.OwnerReference, bool) {
+	owned, ref := util.IsOwnedByTidbCluster(obj)
+	if !owned || ref.Name != tc.GetName() || ref.UID == tc.GetUID() {
+		return nil, false
+	}
+	refs := make([]metav1.OwnerReference, 0, len(obj.GetOwnerReferences()))
+	for _, r := range obj.GetOwnerReferences() {
+		if r.UID != ref.UID {
+			refs = append(refs, r)
+			continue
+		}
+		if !orphan {
+			refs = append(refs, controller.GetOwnerRef(tc))
+		}
+	}
+	return refs, true
+}
+
+var _ manager.Manager = &ownerRefManager{}
+
+type FakeOwnerRefManager struct {
+	err error
+}
+
+func NewFakeOwnerRefManager() *FakeOwnerRefManager {
+	return &FakeOwnerRefManager{}
+}
+
+func (m *FakeOwnerRefManager) SetSyncError(err error) {
+	m.err = err
+}
+
+func (m *FakeOwnerRefManager) Sync(_ *v1alpha1.TidbCluster) error {
+	return m.err
+}
@@ -0,0 +1,218 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestOwnerRefManagerSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name            string
+		reclaimPolicy   corev1.PersistentVolumeReclaimPolicy
+		paused          bool
+		pvDeleted       bool
+		ownerUID        types.UID
+		expectPVCOwners []types.UID
+		expectSetOwners []types.UID
+	}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+
+		// the TidbCluster is recreated with the same name
+		tc := newTidbClusterForMeta()
+		tc.UID = types.UID("new")
+		tc.Spec.PVReclaimPolicy = &test.reclaimPolicy
+		tc.Spec.Paused = test.paused
+		oldTC := tc.DeepCopy()
+		oldTC.UID = test.ownerUID
+
+		otherRef := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: types.UID("other")}
+		pvc := newPVC(tc, "1")
+		pvc.OwnerReferences = []metav1.OwnerReference{controller.GetOwnerRef(oldTC), otherRef}
+		pv := newPV("1")
+		pv.OwnerReferences = []metav1.OwnerReference{controller.GetOwnerRef(oldTC)}
+		set := &apps.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-pd",
+				Namespace:       tc.Namespace,
+				Labels:          label.New().Instance(tc.GetInstanceName()).PD(),
+				OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(oldTC)},
+			},
+		}
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-pd",
+				Namespace:       tc.Namespace,
+				Labels:          label.New().Instance(tc.GetInstanceName()).PD(),
+				OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(oldTC)},
+			},
+		}
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-pd",
+				Namespace:       tc.Namespace,
+				Labels:          label.New().Instance(tc.GetInstanceName()).PD(),
+				OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(oldTC)},
+			},
+		}
+
+		fakeDeps := controller.NewFakeDependencies()
+		informers := fakeDeps.KubeInformerFactory
+		g.Expect(informers.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc)).To(Succeed())
+		if !test.pvDeleted {
+			g.Expect(informers.Core().V1().PersistentVolumes().Informer().GetIndexer().Add(pv)).To(Succeed())
+		}
+		g.Expect(informers.Apps().V1().StatefulSets().Informer().GetIndexer().Add(set)).To(Succeed())
+		g.Expect(informers.Core().V1().Services().Informer().GetIndexer().Add(svc)).To(Succeed())
+		g.Expect(informers.Core().V1().ConfigMaps().Informer().GetIndexer().Add(cm)).To(Succeed())
+		g.Expect(fakeDeps.LabelFilterKubeInformerFactory.Core().V1().ConfigMaps().Informer().GetIndexer().Add(cm)).To(Succeed())
+
+		m := NewOwnerRefManager(fakeDeps)
+		g.Expect(m.Sync(tc)).To(Succeed())
+
+		ownerUIDs := func(obj metav1.Object) []types.UID {
+			var uids []types.UID
+			for _, ref := range obj.GetOwnerReferences() {
+				if ref.UID != otherRef.UID {
+					uids = append(uids, ref.UID)
+				}
+			}
+			return uids
+		}
+
+		gotPVC, err := fakeDeps.PVCLister.PersistentVolumeClaims(tc.Namespace).Get(pvc.Name)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(ownerUIDs(gotPVC)).To(Equal(test.expectPVCOwners))
+		// the owner references of other kinds are kept
+		g.Expect(gotPVC.OwnerReferences).To(ContainElement(otherRef))
+
+		gotPV, err := fakeDeps.PVLister.Get(pv.Name)
+		if test.pvDeleted {
+			g.Expect(errors.IsNotFound(err)).To(BeTrue())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+			if test.ownerUID == tc.UID || test.paused {
+				g.Expect(ownerUIDs(gotPV)).To(Equal([]types.UID{test.ownerUID}))
+			} else {
+				g.Expect(gotPV.OwnerReferences).To(BeEmpty())
+			}
+		}
+
+		gotSet, err := fakeDeps.StatefulSetLister.StatefulSets(tc.Namespace).Get(set.Name)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(ownerUIDs(gotSet)).To(Equal(test.expectSetOwners))
+		gotSvc, err := fakeDeps.ServiceLister.Services(tc.Namespace).Get(svc.Name)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(ownerUIDs(gotSvc)).To(Equal(test.expectSetOwners))
+		// the fake ConfigMapControl updates the ConfigMaps of KubeInformerFactory
+		gotCm, err := informers.Core().V1().ConfigMaps().Lister().ConfigMaps(tc.Namespace).Get(cm.Name)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(ownerUIDs(gotCm)).To(Equal(test.expectSetOwners))
+	}
+
+	tests := []testcase{
+		{
+			name:            "recreated with Retain reclaim policy",
+			reclaimPolicy:   corev1.PersistentVolumeReclaimRetain,
+			ownerUID:        types.UID("old"),
+			expectPVCOwners: nil,
+			expectSetOwners: []types.UID{"new"},
+		},
+		{
+			name:            "recreated with Delete reclaim policy",
+			reclaimPolicy:   corev1.PersistentVolumeReclaimDelete,
+			ownerUID:        types.UID("old"),
+			expectPVCOwners: []types.UID{"new"},
+			expectSetOwners: []types.UID{"new"},
+		},
+		{
+			name:            "owned by the live TidbCluster",
+			reclaimPolicy:   corev1.PersistentVolumeReclaimRetain,
+			ownerUID:        types.UID("new"),
+			expectPVCOwners: []types.UID{"new"},
+			expectSetOwners: []types.UID{"new"},
+		},
+		{
+			name:            "recreated with the pv deleted",
+			reclaimPolicy:   corev1.PersistentVolumeReclaimDelete,
+			pvDeleted:       true,
+			ownerUID:        types.UID("old"),
+			expectPVCOwners: []types.UID{"new"},
+			expectSetOwners: []types.UID{"new"},
+		},
+		{
+			name:            "recreated and paused",
+			reclaimPolicy:   corev1.PersistentVolumeReclaimDelete,
+			paused:          true,
+			ownerUID:        types.UID("old"),
+			expectPVCOwners: []types.UID{"old"},
+			expectSetOwners: []types.UID{"old"},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i])
+	}
+}
+
+func TestHealOwnerRefs(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForMeta()
+	oldTC := tc.DeepCopy()
+	oldTC.UID = types.UID("old")
+	otherTC := tc.DeepCopy()
+	otherTC.Name = "other"
+	otherTC.UID = types.UID("other")
+
+	obj := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+		OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(oldTC)},
+	}}
+	refs, ok := healOwnerRefs(obj, tc, false)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(refs).To(Equal([]metav1.OwnerReference{controller.GetOwnerRef(tc)}))
+	refs, ok = healOwnerRefs(obj, tc, true)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(refs).To(BeEmpty())
+
+	// owned by another TidbCluster
+	obj.OwnerReferences = []metav1.OwnerReference{controller.GetOwnerRef(otherTC)}
+	_, ok = healOwnerRefs(obj, tc, false)
+	g.Expect(ok).To(BeFalse())
+
+	// not owned by any TidbCluster
+	obj.OwnerReferences = nil
+	_, ok = healOwnerRefs(obj, tc, false)
+	g.Expect(ok).To(BeFalse())
+
+	// an owner reference which is not the controller is not healed
+	ref := controller.GetOwnerRef(oldTC)
+	ref.Controller = nil
+	obj.OwnerReferences = []metav1.OwnerReference{ref}
+	_, ok = healOwnerRefs(obj, tc, false)
+	g.Expect(ok).To(BeFalse())
+}
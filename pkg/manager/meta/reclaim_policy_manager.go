@@ -82,6 +82,7 @@ func (m *reclaimPolicyManager) sync(kind string, obj runtime.Object, isPVReclaim
 	if err != nil {
 		return fmt.Errorf("reclaimPolicyManager.sync: failed to list pvc for %s %s/%s, selector %s, error: %s", kind, ns, instanceName, selector, err)
 	}
+	var pvs []*corev1.PersistentVolume
 	for _, pvc := range pvcs {
 		if pvc.Spec.VolumeName == "" {
 			continue
@@ -93,12 +94,30 @@ func (m *reclaimPolicyManager) sync(kind string, obj runtime.Object, isPVReclaim
 		if l := label.Label(pvc.Labels); kind == v1alpha1.TiDBClusterKind && (!l.IsPD() && !l.IsTiDB() && !l.IsTiKV() && !l.IsTiFlash() && !l.IsPump()) {
 			continue
 		}
+		if tc, ok := obj.(*v1alpha1.TidbCluster); ok && tc.ShouldPauseReconcile(v1alpha1.MemberType(pvc.Labels[label.ComponentLabelKey])) {
+			// the PVs of the paused components are not patched
+			continue
+		}
 		pv, err := m.deps.PVLister.Get(pvc.Spec.VolumeName)
 		if err != nil {
 			return fmt.Errorf("reclaimPolicyManager.sync: failed to get pvc %s for %s %s/%s, error: %s", pvc.Spec.VolumeName, kind, ns, instanceName, err)
 		}
+		pvs = append(pvs, pv)
+	}
 
-		if pv.Spec.PersistentVolumeReclaimPolicy == policy {
+	retain := policy == corev1.PersistentVolumeReclaimRetain
+	if retain {
+		// the data volumes are ensured to be retained in case the provisioner defaults them to Delete
+		changed, err := m.deps.PVControl.EnsureRetainPolicy(obj, pvs)
+		if changed > 0 {
+			klog.Infof("reclaimPolicyManager.sync: patched reclaim policy of %d data PVs of %s %s/%s to Retain", changed, kind, ns, instanceName)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, pv := range pvs {
+		if pv.Spec.PersistentVolumeReclaimPolicy == policy || (retain && controller.IsDataPV(pv)) {
 			continue
 		}
 		err = m.deps.PVControl.PatchPVReclaimPolicy(obj, pv, policy)
@@ -20,11 +20,19 @@ import (
 // RegisterMetrics registers all metrics of tidb-operator.
 func RegisterMetrics() {
 	prometheus.MustRegister(ClusterSpecReplicas)
+	prometheus.MustRegister(ClusterUnhealthy)
+	prometheus.MustRegister(ReconcileTotal)
+	prometheus.MustRegister(ReconcileDuration)
+	prometheus.MustRegister(QueueDepth)
 }
 
 // Label constants.
 const (
-	LabelNamespace = "namespace"
-	LabelName      = "name"
-	LabelComponent = "component"
+	LabelNamespace  = "namespace"
+	LabelName       = "name"
+	LabelComponent  = "component"
+	LabelCluster    = "cluster"
+	LabelController = "controller"
+	LabelResult     = "result"
+	LabelPriority   = "priority"
 )
@@ -0,0 +1,61 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Results of a reconciliation.
+const (
+	ReconcileSuccess = "success"
+	ReconcileRequeue = "requeue"
+	ReconcileError   = "error"
+)
+
+var (
+	ReconcileTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "reconcile",
+			Name:      "total",
+			Help:      "Total number of the reconciliations of each component by the result",
+		}, []string{LabelController, LabelComponent, LabelResult})
+
+	ReconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "reconcile",
+			Name:      "duration_seconds",
+			Help:      "Duration in seconds of the reconciliations of each component",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		}, []string{LabelController, LabelComponent})
+
+	ClusterUnhealthy = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "cluster",
+			Name:      "unhealthy",
+			Help:      "Whether each component in TidbCluster is unhealthy according to its status, 1 if unhealthy and 0 otherwise",
+		}, []string{LabelNamespace, LabelName, LabelComponent})
+)
+
+// ObserveReconcile records the result and the duration of a reconciliation of the component
+// started at the start time.
+func ObserveReconcile(controller, component, result string, start time.Time) {
+	ReconcileTotal.WithLabelValues(controller, component, result).Inc()
+	ReconcileDuration.WithLabelValues(controller, component).Observe(time.Since(start).Seconds())
+}
@@ -14,10 +14,12 @@
 package monitor
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -37,23 +39,32 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/discovery"
 	discoverycachedmemory "k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/klog"
+	"k8s.io/kubernetes/pkg/util/slice"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type MonitorManager struct {
 	deps               *controller.Dependencies
 	pvManager          monitor.MonitorManager
 	discoveryInterface discovery.CachedDiscoveryInterface
+	// grafanaCredentialsOverridden records the keys of the monitors whose inline grafana credentials are
+	// overridden by the secrets, so the warning event is only emitted when the state changes
+	grafanaCredentialsOverridden sync.Map
 }
 
 const (
-	FailedSync          = "FailedSync"
-	SuccessSync         = "SuccessSync"
-	prometheusComponent = "prometheus"
-	grafanaComponent    = "grafana"
-	componentPrefix     = "/topology"
+	FailedSync             = "FailedSync"
+	SuccessSync            = "SuccessSync"
+	ExtraConfigMapNotFound = "ExtraConfigMapNotFound"
+	// GrafanaCredentialsOverridden is the event reason when both the inline and secret credentials of grafana are set
+	GrafanaCredentialsOverridden = "GrafanaCredentialsOverridden"
+	prometheusComponent          = "prometheus"
+	grafanaComponent             = "grafana"
+	componentPrefix              = "/topology"
 )
 
 func NewMonitorManager(deps *controller.Dependencies) *MonitorManager {
@@ -66,7 +77,8 @@ func NewMonitorManager(deps *controller.Dependencies) *MonitorManager {
 
 func (m *MonitorManager) SyncMonitor(monitor *v1alpha1.TidbMonitor) error {
 	if monitor.DeletionTimestamp != nil {
-		return nil
+		m.grafanaCredentialsOverridden.Delete(monitorKey(monitor))
+		return m.finalizeMonitor(monitor)
 	}
 	if monitor.Spec.Clusters == nil || len(monitor.Spec.Clusters) < 1 {
 		klog.Errorf("tm[%s/%s] does not configure the target tidbcluster", monitor.Namespace, monitor.Name)
@@ -129,6 +141,10 @@ func (m *MonitorManager) SyncMonitor(monitor *v1alpha1.TidbMonitor) error {
 		}
 	}
 
+	if err := addRemoteWriteAssets(monitor, assetStore); err != nil {
+		return err
+	}
+
 	// create or update tls asset secret
 	err := m.syncAssetSecret(monitor, assetStore)
 	if err != nil {
@@ -143,8 +159,11 @@ func (m *MonitorManager) SyncMonitor(monitor *v1alpha1.TidbMonitor) error {
 	}
 	klog.V(4).Infof("tm[%s/%s]'s service synced", monitor.Namespace, monitor.Name)
 
+	m.checkExtraConfigMaps(monitor)
+	m.checkGrafanaCredentials(monitor)
+
 	// Sync Statefulset
-	if err := m.syncTidbMonitorStatefulset(firstTc, firstDc, monitor); err != nil {
+	if err := m.syncTidbMonitorStatefulset(firstTc, firstDc, monitor, assetStore); err != nil {
 		message := fmt.Sprintf("Sync TidbMonitor[%s/%s] Statefulset failed, err:%v", monitor.Namespace, monitor.Name, err)
 		m.deps.Recorder.Event(monitor, corev1.EventTypeWarning, FailedSync, message)
 		return err
@@ -204,10 +223,10 @@ func (m *MonitorManager) syncTidbMonitorService(monitor *v1alpha1.TidbMonitor) e
 	return nil
 }
 
-func (m *MonitorManager) syncTidbMonitorStatefulset(tc *v1alpha1.TidbCluster, dc *v1alpha1.DMCluster, monitor *v1alpha1.TidbMonitor) error {
+func (m *MonitorManager) syncTidbMonitorStatefulset(tc *v1alpha1.TidbCluster, dc *v1alpha1.DMCluster, monitor *v1alpha1.TidbMonitor, store *Store) error {
 	ns := monitor.Namespace
 	name := monitor.Name
-	err := m.syncTidbMonitorConfig(monitor)
+	err := m.syncTidbMonitorConfig(monitor, store)
 	if err != nil {
 		klog.Errorf("tm[%s/%s]'s configmap failed to sync,err: %v", ns, name, err)
 		return err
@@ -241,6 +260,10 @@ func (m *MonitorManager) syncTidbMonitorStatefulset(tc *v1alpha1.TidbCluster, dc
 			klog.Errorf("Fail to generate statefulset for tm [%s/%s], err: %v", ns, name, err)
 			return err
 		}
+		if err := m.setGrafanaCredentialsHash(monitor, newMonitorSts); err != nil {
+			klog.Errorf("Fail to get grafana credentials for tm [%s/%s], err: %v", ns, name, err)
+			return err
+		}
 		stsName := newMonitorSts.Name
 		oldMonitorSetTmp, err := m.deps.StatefulSetLister.StatefulSets(ns).Get(stsName)
 		if err != nil && !errors.IsNotFound(err) {
@@ -271,6 +294,88 @@ func (m *MonitorManager) syncTidbMonitorStatefulset(tc *v1alpha1.TidbCluster, dc
 	}
 }
 
+// checkExtraConfigMaps emits a warning event for each missing ConfigMap of the extra rules and dashboards.
+// Their volumes are optional, so a missing ConfigMap is skipped and doesn't block the sync.
+func (m *MonitorManager) checkExtraConfigMaps(monitor *v1alpha1.TidbMonitor) {
+	names := append([]string{}, monitor.Spec.Prometheus.ExtraRulesConfigMaps...)
+	if monitor.Spec.Grafana != nil {
+		names = append(names, monitor.Spec.Grafana.ExtraDashboardsConfigMaps...)
+	}
+	for _, name := range names {
+		_, err := m.deps.ConfigMapControl.GetConfigMap(monitor, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: monitor.Namespace,
+			},
+		})
+		if err == nil {
+			continue
+		}
+		if errors.IsNotFound(err) {
+			message := fmt.Sprintf("ConfigMap %s/%s of the extra rules or dashboards is not found, skip it", monitor.Namespace, name)
+			m.deps.Recorder.Event(monitor, corev1.EventTypeWarning, ExtraConfigMapNotFound, message)
+			continue
+		}
+		klog.Errorf("tm[%s/%s] fails to get the extra configmap %s, err: %v", monitor.Namespace, monitor.Name, name, err)
+	}
+}
+
+// checkGrafanaCredentials emits a warning event if the inline credentials of grafana are set along with the secrets,
+// the secrets take precedence and the inline credentials are ignored. The event is emitted once the credentials
+// become overridden rather than in every sync.
+func (m *MonitorManager) checkGrafanaCredentials(monitor *v1alpha1.TidbMonitor) {
+	key := monitorKey(monitor)
+	grafana := monitor.Spec.Grafana
+	if grafana == nil || !((grafana.UsernameSecret != nil && grafana.Username != "") || (grafana.PasswordSecret != nil && grafana.Password != "")) {
+		m.grafanaCredentialsOverridden.Delete(key)
+		return
+	}
+	if _, overridden := m.grafanaCredentialsOverridden.LoadOrStore(key, struct{}{}); overridden {
+		return
+	}
+	message := fmt.Sprintf("both the inline and secret credentials of grafana are set for tm[%s/%s], the inline ones are ignored", monitor.Namespace, monitor.Name)
+	m.deps.Recorder.Event(monitor, corev1.EventTypeWarning, GrafanaCredentialsOverridden, message)
+}
+
+func monitorKey(monitor *v1alpha1.TidbMonitor) string {
+	return fmt.Sprintf("%s/%s", monitor.Namespace, monitor.Name)
+}
+
+// setGrafanaCredentialsHash sets the hash of the grafana credentials in the secrets to the Pod template,
+// so the rotation of the credentials rolls out the grafana.
+func (m *MonitorManager) setGrafanaCredentialsHash(monitor *v1alpha1.TidbMonitor, sts *appsv1.StatefulSet) error {
+	if monitor.Spec.Grafana == nil {
+		return nil
+	}
+	var credentials []string
+	for _, selector := range []*corev1.SecretKeySelector{monitor.Spec.Grafana.UsernameSecret, monitor.Spec.Grafana.PasswordSecret} {
+		if selector == nil {
+			continue
+		}
+		secret, err := m.deps.SecretLister.Secrets(monitor.Namespace).Get(selector.Name)
+		if errors.IsNotFound(err) {
+			credentials = append(credentials, "")
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		credentials = append(credentials, string(secret.Data[selector.Key]))
+	}
+	if len(credentials) == 0 {
+		return nil
+	}
+	hash, err := member.Sha256Sum(credentials)
+	if err != nil {
+		return err
+	}
+	if sts.Spec.Template.Annotations == nil {
+		sts.Spec.Template.Annotations = map[string]string{}
+	}
+	sts.Spec.Template.Annotations[GrafanaCredentialsHashAnnotation] = hash
+	return nil
+}
+
 func (m *MonitorManager) syncTidbMonitorSecret(monitor *v1alpha1.TidbMonitor) (*corev1.Secret, error) {
 	if monitor.Spec.Grafana == nil {
 		return nil, nil
@@ -279,7 +384,7 @@ func (m *MonitorManager) syncTidbMonitorSecret(monitor *v1alpha1.TidbMonitor) (*
 	return m.deps.TypedControl.CreateOrUpdateSecret(monitor, newSt)
 }
 
-func (m *MonitorManager) syncTidbMonitorConfig(monitor *v1alpha1.TidbMonitor) error {
+func (m *MonitorManager) syncTidbMonitorConfig(monitor *v1alpha1.TidbMonitor, store *Store) error {
 	if features.DefaultFeatureGate.Enabled(features.AutoScaling) {
 		// TODO: We need to update the status to tell users we are monitoring extra clusters
 		// Get all autoscaling clusters for TC, and add them to .Spec.Clusters to
@@ -362,7 +467,7 @@ func (m *MonitorManager) syncTidbMonitorConfig(monitor *v1alpha1.TidbMonitor) er
 	}
 
 	shards := monitor.GetShards()
-	promCM, err := getPromConfigMap(monitor, monitorClusterInfos, dmClusterInfos, shards)
+	promCM, err := getPromConfigMap(monitor, monitorClusterInfos, dmClusterInfos, shards, store)
 	if err != nil {
 		return err
 	}
@@ -461,9 +566,115 @@ func (m *MonitorManager) syncTidbMonitorRbac(monitor *v1alpha1.TidbMonitor) (*co
 		}
 	}
 
+	if err := m.syncTidbMonitorCrossNamespaceRbac(monitor, sa, policyRules); err != nil {
+		klog.Errorf("tm[%s/%s]'s cross namespace rbac failed to sync, err: %v", monitor.Namespace, monitor.Name, err)
+		return nil, err
+	}
+
 	return sa, nil
 }
 
+// syncTidbMonitorCrossNamespaceRbac grants the monitor to discover the pods of the target
+// clusters in the other namespaces if it's not cluster scoped, and revokes the grants of the
+// namespaces no longer targeted. The granted namespaces are recorded in the status because
+// the Roles and RoleBindings can't be owned by the monitor across namespaces.
+func (m *MonitorManager) syncTidbMonitorCrossNamespaceRbac(monitor *v1alpha1.TidbMonitor, sa *corev1.ServiceAccount, policyRules []rbac.PolicyRule) error {
+	var namespaces []string
+	if !monitor.Spec.ClusterScoped {
+		namespaces = getMonitorTargetNamespaces(monitor)
+	}
+	targets := sets.NewString(namespaces...)
+
+	// the Roles and RoleBindings in the other namespaces can't be owned by the monitor, they're deleted
+	// before the finalizer is removed when the monitor is deleted
+	if len(namespaces) > 0 && !slice.ContainsString(monitor.Finalizers, label.TidbMonitorCleanupFinalizer, nil) {
+		newMonitor := monitor.DeepCopy()
+		newMonitor.Finalizers = append(newMonitor.Finalizers, label.TidbMonitorCleanupFinalizer)
+		if err := m.updateFinalizers(monitor, newMonitor, "add"); err != nil {
+			return err
+		}
+	}
+
+	for _, ns := range namespaces {
+		role := getMonitorCrossNamespaceRole(monitor, ns, policyRules)
+		_, err := m.deps.GenericControl.CreateOrUpdate(monitor, role, func(existing, desired client.Object) error {
+			existingRole := existing.(*rbac.Role)
+			desiredRole := desired.(*rbac.Role)
+			existingRole.Labels = desiredRole.Labels
+			existingRole.Rules = desiredRole.Rules
+			return nil
+		}, false)
+		if err != nil {
+			return fmt.Errorf("sync role %s/%s failed, err: %v", ns, role.Name, err)
+		}
+
+		rb := getMonitorCrossNamespaceRoleBinding(sa, role, monitor)
+		_, err = m.deps.GenericControl.CreateOrUpdate(monitor, rb, func(existing, desired client.Object) error {
+			existingRB := existing.(*rbac.RoleBinding)
+			desiredRB := desired.(*rbac.RoleBinding)
+			existingRB.Labels = desiredRB.Labels
+			existingRB.RoleRef = desiredRB.RoleRef
+			existingRB.Subjects = desiredRB.Subjects
+			return nil
+		}, false)
+		if err != nil {
+			return fmt.Errorf("sync rolebinding %s/%s failed, err: %v", ns, rb.Name, err)
+		}
+	}
+
+	if err := m.revokeCrossNamespaceRbac(monitor, targets); err != nil {
+		return err
+	}
+	monitor.Status.RBACNamespaces = namespaces
+	return nil
+}
+
+// revokeCrossNamespaceRbac deletes the Roles and RoleBindings of the monitor in the granted namespaces
+// which are not in the targets
+func (m *MonitorManager) revokeCrossNamespaceRbac(monitor *v1alpha1.TidbMonitor, targets sets.String) error {
+	name := GetMonitorObjectNameCrossNamespace(monitor)
+	for _, ns := range monitor.Status.RBACNamespaces {
+		if targets.Has(ns) {
+			continue
+		}
+		objs := []client.Object{
+			&rbac.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}},
+			&rbac.Role{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}},
+		}
+		for _, obj := range objs {
+			if err := m.deps.TypedControl.Delete(monitor, obj); err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("delete %T %s/%s failed, err: %v", obj, ns, name, err)
+			}
+		}
+		klog.Infof("tm[%s/%s]'s rbac in namespace %s is cleaned up", monitor.Namespace, monitor.Name, ns)
+	}
+	return nil
+}
+
+// finalizeMonitor revokes the grants of the monitor being deleted in the namespaces of the target clusters,
+// then removes the tidb.pingcap.com/tidbmonitor-cleanup finalizer
+func (m *MonitorManager) finalizeMonitor(monitor *v1alpha1.TidbMonitor) error {
+	if !slice.ContainsString(monitor.Finalizers, label.TidbMonitorCleanupFinalizer, nil) {
+		return nil
+	}
+	if err := m.revokeCrossNamespaceRbac(monitor, sets.NewString()); err != nil {
+		return err
+	}
+	newMonitor := monitor.DeepCopy()
+	newMonitor.Finalizers = slice.RemoveString(newMonitor.Finalizers, label.TidbMonitorCleanupFinalizer, nil)
+	return m.updateFinalizers(monitor, newMonitor, "remove")
+}
+
+func (m *MonitorManager) updateFinalizers(monitor, newMonitor *v1alpha1.TidbMonitor, verb string) error {
+	updated, err := m.deps.Clientset.PingcapV1alpha1().TidbMonitors(monitor.Namespace).Update(context.TODO(), newMonitor, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to %s finalizer %s of tm[%s/%s], err: %v", verb, label.TidbMonitorCleanupFinalizer, monitor.Namespace, monitor.Name, err)
+	}
+	// keep the status changed in this round, it's updated later with the new resource version
+	monitor.ObjectMeta = updated.ObjectMeta
+	return nil
+}
+
 func (m *MonitorManager) syncIngress(monitor *v1alpha1.TidbMonitor) error {
 	if err := m.syncPrometheusIngress(monitor); err != nil {
 		return err
@@ -14,20 +14,28 @@
 package monitor
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/manager/meta"
+	"github.com/pingcap/tidb-operator/pkg/util"
 	"github.com/prometheus/common/model"
 	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	discoverycachedmemory "k8s.io/client-go/discovery/cached/memory"
 	discoveryfake "k8s.io/client-go/discovery/fake"
 	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 )
 
@@ -549,6 +557,180 @@ func TestTidbMonitorSyncUpdate(t *testing.T) {
 	}
 }
 
+func TestTidbMonitorSyncCrossNamespace(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tmm := newFakeTidbMonitorManager()
+	cli := tmm.deps.GenericControl.(*controller.FakeGenericControl).FakeCli
+
+	tm := newTidbMonitor(v1alpha1.TidbClusterRef{Name: "foo", Namespace: "ns"})
+	tm.Spec.Clusters = append(tm.Spec.Clusters, v1alpha1.TidbClusterRef{Name: "bar", Namespace: "ns2"})
+	name := GetMonitorObjectNameCrossNamespace(tm)
+	_, err := tmm.deps.Clientset.PingcapV1alpha1().TidbMonitors(tm.Namespace).Create(context.TODO(), tm, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the scrape jobs and the tls assets of the clusters are separated by namespaces
+	cm, err := getPromConfigMap(tm, []ClusterRegexInfo{
+		{Name: "foo", Namespace: "ns", enableTLS: true},
+		{Name: "bar", Namespace: "ns2", enableTLS: true},
+	}, nil, tm.GetShards(), nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	content := cm.Data["prometheus.yml"]
+	g.Expect(content).To(ContainSubstring("job_name: ns-foo-pd"))
+	g.Expect(content).To(ContainSubstring("job_name: ns2-bar-pd"))
+	g.Expect(content).To(ContainSubstring(TLSAssetKey{"secret", "ns", util.ClusterClientTLSSecretName("foo"), v1.TLSCertKey}.String()))
+	g.Expect(content).To(ContainSubstring(TLSAssetKey{"secret", "ns2", util.ClusterClientTLSSecretName("bar"), v1.TLSCertKey}.String()))
+
+	// the role and rolebinding are created in the namespace of the other cluster
+	sa, err := tmm.syncTidbMonitorRbac(tm)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tm.Status.RBACNamespaces).To(Equal([]string{"ns2"}))
+	g.Expect(tm.Finalizers).To(ContainElement(label.TidbMonitorCleanupFinalizer))
+	role := &rbacv1.Role{}
+	g.Expect(cli.Get(context.TODO(), types.NamespacedName{Namespace: "ns2", Name: name}, role)).To(Succeed())
+	g.Expect(role.OwnerReferences).To(BeEmpty())
+	rb := &rbacv1.RoleBinding{}
+	g.Expect(cli.Get(context.TODO(), types.NamespacedName{Namespace: "ns2", Name: name}, rb)).To(Succeed())
+	g.Expect(rb.RoleRef.Name).To(Equal(role.Name))
+	g.Expect(rb.Subjects).To(HaveLen(1))
+	g.Expect(rb.Subjects[0].Name).To(Equal(sa.Name))
+	g.Expect(rb.Subjects[0].Namespace).To(Equal("ns"))
+
+	// removing the cluster cleans up its rbac
+	tm.Spec.Clusters = tm.Spec.Clusters[:1]
+	_, err = tmm.syncTidbMonitorRbac(tm)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tm.Status.RBACNamespaces).To(BeEmpty())
+	err = cli.Get(context.TODO(), types.NamespacedName{Namespace: "ns2", Name: name}, &rbacv1.Role{})
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+	err = cli.Get(context.TODO(), types.NamespacedName{Namespace: "ns2", Name: name}, &rbacv1.RoleBinding{})
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+
+	// the cluster role covers all the namespaces
+	tm.Spec.Clusters = append(tm.Spec.Clusters, v1alpha1.TidbClusterRef{Name: "bar", Namespace: "ns2"})
+	_, err = tmm.syncTidbMonitorRbac(tm)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tm.Status.RBACNamespaces).To(Equal([]string{"ns2"}))
+	tm.Spec.ClusterScoped = true
+	_, err = tmm.syncTidbMonitorRbac(tm)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tm.Status.RBACNamespaces).To(BeEmpty())
+	err = cli.Get(context.TODO(), types.NamespacedName{Namespace: "ns2", Name: name}, &rbacv1.Role{})
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestTidbMonitorCleanupCrossNamespace(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tmm := newFakeTidbMonitorManager()
+	cli := tmm.deps.GenericControl.(*controller.FakeGenericControl).FakeCli
+
+	tm := newTidbMonitor(v1alpha1.TidbClusterRef{Name: "foo", Namespace: "ns"})
+	tm.Spec.Clusters = append(tm.Spec.Clusters, v1alpha1.TidbClusterRef{Name: "bar", Namespace: "ns2"})
+	name := GetMonitorObjectNameCrossNamespace(tm)
+	_, err := tmm.deps.Clientset.PingcapV1alpha1().TidbMonitors(tm.Namespace).Create(context.TODO(), tm, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = tmm.syncTidbMonitorRbac(tm)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tm.Status.RBACNamespaces).To(Equal([]string{"ns2"}))
+	g.Expect(tm.Finalizers).To(ContainElement(label.TidbMonitorCleanupFinalizer))
+
+	// the rbac is cleaned up before the finalizer is removed when the monitor is deleted
+	tm.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	g.Expect(tmm.SyncMonitor(tm)).To(Succeed())
+	g.Expect(tm.Finalizers).NotTo(ContainElement(label.TidbMonitorCleanupFinalizer))
+	err = cli.Get(context.TODO(), types.NamespacedName{Namespace: "ns2", Name: name}, &rbacv1.Role{})
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+	err = cli.Get(context.TODO(), types.NamespacedName{Namespace: "ns2", Name: name}, &rbacv1.RoleBinding{})
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestTidbMonitorCheckExtraConfigMaps(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tmm := newFakeTidbMonitorManager()
+	tmm.deps.ConfigMapControl = controller.NewRealConfigMapControl(tmm.deps.KubeClientset, tmm.deps.Recorder)
+	recorder := tmm.deps.Recorder.(*record.FakeRecorder)
+
+	_, err := tmm.deps.KubeClientset.CoreV1().ConfigMaps("ns").Create(context.TODO(), &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a-rules", Namespace: "ns"},
+	}, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	tm := newTidbMonitor(v1alpha1.TidbClusterRef{Name: "foo", Namespace: "ns"})
+	tm.Spec.Prometheus.ExtraRulesConfigMaps = []string{"team-a-rules", "team-b-rules"}
+	tm.Spec.Grafana = &v1alpha1.GrafanaSpec{ExtraDashboardsConfigMaps: []string{"team-a-dashboards"}}
+	tmm.checkExtraConfigMaps(tm)
+
+	var events []string
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+	g.Expect(events).To(HaveLen(2))
+	g.Expect(events[0]).To(ContainSubstring(ExtraConfigMapNotFound))
+	g.Expect(events[0]).To(ContainSubstring("ns/team-b-rules"))
+	g.Expect(events[1]).To(ContainSubstring("ns/team-a-dashboards"))
+}
+
+func TestTidbMonitorGrafanaCredentials(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tmm := newFakeTidbMonitorManager()
+	recorder := tmm.deps.Recorder.(*record.FakeRecorder)
+	secretIndexer := tmm.deps.KubeInformerFactory.Core().V1().Secrets().Informer().GetIndexer()
+
+	tm := newTidbMonitor(v1alpha1.TidbClusterRef{Name: "foo", Namespace: "ns"})
+	tm.Spec.Grafana = &v1alpha1.GrafanaSpec{
+		Password: "inline",
+		PasswordSecret: &v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "grafana-admin"},
+			Key:                  "password",
+		},
+	}
+	g.Expect(IsGrafanaCredentialsSecret(tm, "ns", "grafana-admin")).To(BeTrue())
+	g.Expect(IsGrafanaCredentialsSecret(tm, "ns2", "grafana-admin")).To(BeFalse())
+
+	// the secret takes precedence over the inline password
+	tmm.checkGrafanaCredentials(tm)
+	g.Expect(recorder.Events).To(HaveLen(1))
+	g.Expect(<-recorder.Events).To(ContainSubstring(GrafanaCredentialsOverridden))
+	// the event is not emitted again until the state changes
+	tmm.checkGrafanaCredentials(tm)
+	g.Expect(recorder.Events).To(BeEmpty())
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "grafana-admin", Namespace: "ns"},
+		Data:       map[string][]byte{"password": []byte("secret-1")},
+	}
+	g.Expect(secretIndexer.Add(secret)).To(Succeed())
+	hash := func() string {
+		sts, err := getMonitorStatefulSet(&v1.ServiceAccount{}, &v1.Secret{}, tm, &v1alpha1.TidbCluster{}, nil, 0)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(tmm.setGrafanaCredentialsHash(tm, sts)).To(Succeed())
+		return sts.Spec.Template.Annotations[GrafanaCredentialsHashAnnotation]
+	}
+	before := hash()
+	g.Expect(before).NotTo(BeEmpty())
+	g.Expect(hash()).To(Equal(before))
+
+	// rotate the password
+	secret = secret.DeepCopy()
+	secret.Data["password"] = []byte("secret-2")
+	g.Expect(secretIndexer.Update(secret)).To(Succeed())
+	g.Expect(hash()).NotTo(Equal(before))
+
+	// no secret is referred
+	tm.Spec.Grafana.PasswordSecret = nil
+	g.Expect(hash()).To(BeEmpty())
+	tmm.checkGrafanaCredentials(tm)
+	g.Expect(recorder.Events).To(BeEmpty())
+
+	// the secret is referred again
+	tm.Spec.Grafana.PasswordSecret = &v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: "grafana-admin"},
+		Key:                  "password",
+	}
+	tmm.checkGrafanaCredentials(tm)
+	g.Expect(recorder.Events).To(HaveLen(1))
+}
+
 func newTidbMonitor(cluster v1alpha1.TidbClusterRef) *v1alpha1.TidbMonitor {
 	return &v1alpha1.TidbMonitor{
 		ObjectMeta: metav1.ObjectMeta{
@@ -16,6 +16,7 @@ package monitor
 import (
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	corelisterv1 "k8s.io/client-go/listers/core/v1"
 )
 
@@ -52,6 +53,25 @@ func (s *Store) addTLSAssets(ns string, secretName string) error {
 	return nil
 }
 
+// addSecretKeyAsset adds the value of the key of the Secret referenced by the selector to the store.
+func (s *Store) addSecretKeyAsset(ns string, selector corev1.SecretKeySelector) error {
+	secret, err := s.secretLister.Secrets(ns).Get(selector.Name)
+	if err != nil {
+		return fmt.Errorf("get secret [%s/%s] failed, err: %v", ns, selector.Name, err)
+	}
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		return fmt.Errorf("key %s not found in secret [%s/%s]", selector.Key, ns, selector.Name)
+	}
+	s.TLSAssets[secretKeyAssetKey(ns, selector)] = TLSAsset(value)
+	return nil
+}
+
+// secretKeyAssetKey returns the key of the asset added by addSecretKeyAsset
+func secretKeyAssetKey(ns string, selector corev1.SecretKeySelector) TLSAssetKey {
+	return TLSAssetKey{"secret", ns, selector.Name, selector.Key}
+}
+
 // TLSAssetKey is a key for a TLS asset.
 type TLSAssetKey struct {
 	from string
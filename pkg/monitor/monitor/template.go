@@ -15,7 +15,9 @@ package monitor
 
 import (
 	"fmt"
+	"hash/fnv"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -71,6 +73,31 @@ var (
             "type": "file"
         }
     ]
+}`
+	extraDashBoardConfig = `{
+    "apiVersion": 1,
+    "providers": [
+        {
+            "folder": "",
+            "name": "0",
+            "options": {
+                "path": "/grafana-dashboard-definitions/tidb"
+            },
+			"allowUiUpdates":true,
+            "orgId": 1,
+            "type": "file"
+        },
+        {
+            "folder": "",
+            "name": "extra",
+            "options": {
+                "path": "/grafana-dashboard-definitions/extra"
+            },
+            "allowUiUpdates": true,
+            "orgId": 1,
+            "type": "file"
+        }
+    ]
 }`
 )
 
@@ -146,7 +173,9 @@ type MonitorConfigModel struct {
 	RemoteWriteConfigs        []*config.RemoteWriteConfig
 	EnableAlertRules          bool
 	EnableExternalRuleConfigs bool
+	ExtraRulesConfigMaps      []string
 	shards                    int32
+	shardByCluster            bool
 }
 
 // ClusterRegexInfo is the monitor cluster info
@@ -411,7 +440,7 @@ func scrapeJob(jobName string, componentPattern config.Regexp, cmodel *MonitorCo
 				},
 			},
 		}
-		scrapeconfig.RelabelConfigs = appendShardingRelabelConfigRules(scrapeconfig.RelabelConfigs, uint64(cmodel.shards))
+		scrapeconfig.RelabelConfigs = appendShardingRelabelConfigRules(scrapeconfig.RelabelConfigs, cmodel, cluster)
 		if cluster.enableTLS && !isDMJob(jobName) {
 			scrapeconfig.Scheme = "https"
 			// lightning does not need to authenticate the access of other components,
@@ -486,6 +515,9 @@ func RenderPrometheusConfig(model *MonitorConfigModel) (string, error) {
 			"/prometheus-external-rules/*.rules.yml",
 		}
 	}
+	for _, name := range model.ExtraRulesConfigMaps {
+		pc.RuleFiles = append(pc.RuleFiles, path.Join(extraRulesPath, name, "*.rules.yml"))
+	}
 
 	bs, err := yaml.Marshal(pc)
 	if err != nil {
@@ -494,21 +526,36 @@ func RenderPrometheusConfig(model *MonitorConfigModel) (string, error) {
 	return string(bs), nil
 }
 
-func appendShardingRelabelConfigRules(relabelConfigs []*config.RelabelConfig, shard uint64) []*config.RelabelConfig {
+// appendShardingRelabelConfigRules keeps the targets of the shard. The targets are distributed by the hash of
+// their identity instead of `__address__`, which changes with the Pod IP, so a target stays on its shard when
+// its Pod is recreated. If shardByCluster is set, all the targets of the cluster are kept by the shard
+// assigned by clusterShard.
+func appendShardingRelabelConfigRules(relabelConfigs []*config.RelabelConfig, cmodel *MonitorConfigModel, cluster ClusterRegexInfo) []*config.RelabelConfig {
 	shardsPattern, err := config.NewRegexp("$(SHARD)")
 	if err != nil {
-		klog.Errorf("Generate pattern for shard %d error: %v", shard, err)
+		klog.Errorf("Generate pattern for shard %d error: %v", cmodel.shards, err)
 		return relabelConfigs
 	}
-	return append(relabelConfigs, &config.RelabelConfig{
-
+	hashConfig := &config.RelabelConfig{
 		SourceLabels: model.LabelNames{
-			"__address__",
+			namespaceLabel,
+			instanceLabel,
+			componentLabel,
+			podNameLabel,
 		},
+		Separator:   "/",
 		Action:      config.RelabelHashMod,
 		TargetLabel: "__tmp_hash",
-		Modulus:     shard,
-	}, &config.RelabelConfig{
+		Modulus:     uint64(cmodel.shards),
+	}
+	if cmodel.shardByCluster {
+		hashConfig = &config.RelabelConfig{
+			Action:      config.RelabelReplace,
+			TargetLabel: "__tmp_hash",
+			Replacement: strconv.Itoa(int(clusterShard(cluster.Namespace, cluster.Name, cmodel.shards))),
+		}
+	}
+	return append(relabelConfigs, hashConfig, &config.RelabelConfig{
 
 		SourceLabels: model.LabelNames{
 			"__tmp_hash",
@@ -519,3 +566,14 @@ func appendShardingRelabelConfigRules(relabelConfigs []*config.RelabelConfig, sh
 	},
 	)
 }
+
+// clusterShard returns the shard the cluster is assigned to. It only depends on the identity of the cluster,
+// so adding or removing other clusters doesn't move the cluster to another shard.
+func clusterShard(namespace, name string, shards int32) int32 {
+	if shards <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(namespace + "/" + name))
+	return int32(h.Sum32() % uint32(shards))
+}
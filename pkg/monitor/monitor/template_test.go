@@ -15,7 +15,9 @@ package monitor
 
 import (
 	"bytes"
+	"fmt"
 	"path"
+	"strings"
 	"testing"
 	"text/template"
 	"time"
@@ -26,6 +28,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/util"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/relabel"
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -136,7 +139,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -191,7 +195,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -246,7 +251,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -301,7 +307,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -356,7 +363,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -411,7 +419,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -466,7 +475,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -521,7 +531,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -576,7 +587,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -631,7 +643,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -686,7 +699,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -741,7 +755,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -853,7 +868,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -908,7 +924,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -963,7 +980,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1018,7 +1036,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1073,7 +1092,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1128,7 +1148,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1183,7 +1204,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1238,7 +1260,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1293,7 +1316,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1348,7 +1372,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1403,7 +1428,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1458,7 +1484,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1572,7 +1599,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1630,7 +1658,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1688,7 +1717,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1746,7 +1776,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1804,7 +1835,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1862,7 +1894,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1920,7 +1953,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -1978,7 +2012,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -2036,7 +2071,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -2091,7 +2127,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -2149,7 +2186,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -2207,7 +2245,8 @@ scrape_configs:
     regex: (.+)
     target_label: __metrics_path__
     action: replace
-  - source_labels: [__address__]
+  - source_labels: [__meta_kubernetes_namespace, __meta_kubernetes_pod_label_app_kubernetes_io_instance, __meta_kubernetes_pod_label_app_kubernetes_io_component, __meta_kubernetes_pod_name]
+    separator: /
     target_label: __tmp_hash
     action: hashmod
   - source_labels: [__tmp_hash]
@@ -2332,3 +2371,81 @@ func TestScrapeJob(t *testing.T) {
 		KeyFile:  path.Join(util.ClusterAssetsTLSPath, TLSAssetKey{"secret", ns, tcTlsSecretName, corev1.TLSPrivateKeyKey}.String()),
 	}))
 }
+
+func TestShardingRelabelConfigRules(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	shards := int32(3)
+	var clusterInfos []ClusterRegexInfo
+	for i := 0; i < 4; i++ {
+		clusterInfos = append(clusterInfos, ClusterRegexInfo{Name: fmt.Sprintf("cluster-%d", i), Namespace: fmt.Sprintf("ns-%d", i%2)})
+	}
+	// targets discovered from the Pods, the IPs of the Pods are changed by ipOffset
+	targets := func(ipOffset int) []model.LabelSet {
+		var sets []model.LabelSet
+		ip := ipOffset
+		for _, cluster := range clusterInfos {
+			for _, component := range []string{"pd", "tikv"} {
+				for ordinal := 0; ordinal < 3; ordinal++ {
+					ip++
+					sets = append(sets, model.LabelSet{
+						model.AddressLabel: model.LabelValue(fmt.Sprintf("10.0.0.%d:2379", ip)),
+						namespaceLabel:     model.LabelValue(cluster.Namespace),
+						instanceLabel:      model.LabelValue(cluster.Name),
+						componentLabel:     model.LabelValue(component),
+						podNameLabel:       model.LabelValue(fmt.Sprintf("%s-%s-%d", cluster.Name, component, ordinal)),
+						scrapeLabel:        "true",
+						portLabel:          "2379",
+						metricsPathLabel:   "/metrics",
+					})
+				}
+			}
+		}
+		return sets
+	}
+	// assign returns the shard each target is kept by, keyed by the namespace and the Pod name
+	assign := func(shardByCluster bool, ipOffset int) map[string]int32 {
+		assignments := map[string]int32{}
+		for shard := int32(0); shard < shards; shard++ {
+			content, err := RenderPrometheusConfig(&MonitorConfigModel{
+				ClusterInfos:   clusterInfos,
+				shards:         shards,
+				shardByCluster: shardByCluster,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			// the same as what the Prometheus container does
+			pc, err := config.Load(strings.ReplaceAll(content, "$(SHARD)", fmt.Sprint(shard)))
+			g.Expect(err).NotTo(HaveOccurred())
+			for _, scrapeConfig := range pc.ScrapeConfigs {
+				for _, target := range targets(ipOffset) {
+					if relabel.Process(target.Clone(), scrapeConfig.RelabelConfigs...) == nil {
+						continue
+					}
+					key := fmt.Sprintf("%s/%s", target[namespaceLabel], target[podNameLabel])
+					_, exist := assignments[key]
+					g.Expect(exist).To(BeFalse(), "target %s is kept by more than one shard", key)
+					assignments[key] = shard
+				}
+			}
+		}
+		g.Expect(assignments).To(HaveLen(len(targets(ipOffset))))
+		return assignments
+	}
+
+	// the targets stay on their shards when the Pod IPs change
+	before := assign(false, 0)
+	g.Expect(assign(false, 100)).To(Equal(before))
+	shardsUsed := map[int32]bool{}
+	for _, shard := range before {
+		shardsUsed[shard] = true
+	}
+	g.Expect(len(shardsUsed)).To(BeNumerically(">", 1))
+
+	// all the targets of a cluster are on the shard it's assigned to
+	before = assign(true, 0)
+	g.Expect(assign(true, 100)).To(Equal(before))
+	for _, target := range targets(0) {
+		key := fmt.Sprintf("%s/%s", target[namespaceLabel], target[podNameLabel])
+		g.Expect(before[key]).To(Equal(clusterShard(string(target[namespaceLabel]), string(target[instanceLabel]), shards)))
+	}
+}
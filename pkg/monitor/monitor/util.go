@@ -37,10 +37,19 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/klog"
+	"k8s.io/utils/pointer"
 )
 
 const (
 	defaultReplicaExternalLabelName = "prometheus_replica"
+	// extraRulesPath is the dir the ConfigMaps of spec.prometheus.extraRulesConfigMaps are mounted to
+	extraRulesPath = "/prometheus-rules/extra"
+	// extraDashboardsPath is the dir the ConfigMaps of spec.grafana.extraDashboardsConfigMaps are mounted to
+	extraDashboardsPath = "/grafana-dashboard-definitions/extra"
+	// shardDatasourcesKey is the key of the Grafana datasources of the Prometheus shards in the Grafana ConfigMap
+	shardDatasourcesKey = "datasources-shards.yaml"
+	// GrafanaCredentialsHashAnnotation is the annotation of the hash of the grafana credentials in the secrets
+	GrafanaCredentialsHashAnnotation = "tidb.pingcap.com/grafana-credentials-hash"
 )
 
 func GetTLSAssetsSecretName(name string) string {
@@ -149,7 +158,7 @@ func getAlertManagerRulesVersion(tc *v1alpha1.TidbCluster, monitor *v1alpha1.Tid
 
 // getPromConfigMap generate the Prometheus config for TidbMonitor,
 // If the namespace in ClusterRef is empty, we would set the TidbMonitor's namespace in the default
-func getPromConfigMap(monitor *v1alpha1.TidbMonitor, monitorClusterInfos []ClusterRegexInfo, dmClusterInfos []ClusterRegexInfo, shard int32) (*core.ConfigMap, error) {
+func getPromConfigMap(monitor *v1alpha1.TidbMonitor, monitorClusterInfos []ClusterRegexInfo, dmClusterInfos []ClusterRegexInfo, shard int32, store *Store) (*core.ConfigMap, error) {
 	model := &MonitorConfigModel{
 		AlertmanagerURL:  "",
 		ClusterInfos:     monitorClusterInfos,
@@ -157,10 +166,12 @@ func getPromConfigMap(monitor *v1alpha1.TidbMonitor, monitorClusterInfos []Clust
 		ExternalLabels:   buildExternalLabels(monitor),
 		EnableAlertRules: monitor.Spec.EnableAlertRules,
 		shards:           shard,
+		shardByCluster:   monitor.GetShardingStrategy() == v1alpha1.ShardingStrategyCluster,
 	}
+	model.ExtraRulesConfigMaps = monitor.Spec.Prometheus.ExtraRulesConfigMaps
 
 	if len(monitor.Spec.Prometheus.RemoteWrite) > 0 {
-		model.RemoteWriteConfigs = generateRemoteWrite(monitor)
+		model.RemoteWriteConfigs = generateRemoteWrite(monitor, store)
 	}
 
 	if monitor.Spec.AlertmanagerURL != nil {
@@ -201,9 +212,94 @@ func getGrafanaConfigMap(monitor *v1alpha1.TidbMonitor) *core.ConfigMap {
 			"dashboards.yaml": dashBoardConfig,
 		},
 	}
+	if len(monitor.Spec.Grafana.ExtraDashboardsConfigMaps) > 0 {
+		cm.Data["dashboards.yaml"] = extraDashBoardConfig
+	}
+	if monitor.GetShards() > 1 {
+		cm.Data[shardDatasourcesKey] = getShardDatasources(monitor)
+	}
 	return cm
 }
 
+// IsGrafanaCredentialsSecret returns whether the secret holds the grafana credentials of the TidbMonitor
+func IsGrafanaCredentialsSecret(monitor *v1alpha1.TidbMonitor, ns, name string) bool {
+	if monitor.Spec.Grafana == nil || monitor.Namespace != ns {
+		return false
+	}
+	for _, selector := range []*core.SecretKeySelector{monitor.Spec.Grafana.UsernameSecret, monitor.Spec.Grafana.PasswordSecret} {
+		if selector != nil && selector.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// getShardDatasources generates the Grafana datasources of the Prometheus shards, so the Grafana of
+// any shard can query the shard a cluster is assigned to.
+func getShardDatasources(monitor *v1alpha1.TidbMonitor) string {
+	var b strings.Builder
+	b.WriteString("apiVersion: 1\ndatasources:\n")
+	for shard := int32(0); shard < monitor.GetShards(); shard++ {
+		name := PrometheusName(monitor.Name, shard)
+		fmt.Fprintf(&b, "- name: %s\n  type: prometheus\n  access: proxy\n  url: http://%s.%s:9090\n", name, name, monitor.Namespace)
+	}
+	return b.String()
+}
+
+// getExtraRulesVolumeMounts returns the mounts of the ConfigMaps of spec.prometheus.extraRulesConfigMaps
+func getExtraRulesVolumeMounts(monitor *v1alpha1.TidbMonitor) []core.VolumeMount {
+	var mounts []core.VolumeMount
+	for i, name := range monitor.Spec.Prometheus.ExtraRulesConfigMaps {
+		mounts = append(mounts, core.VolumeMount{
+			Name:      fmt.Sprintf("extra-rules-%d", i),
+			MountPath: path.Join(extraRulesPath, name),
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
+
+// getExtraDashboardsVolumeMounts returns the mounts of the ConfigMaps of spec.grafana.extraDashboardsConfigMaps
+func getExtraDashboardsVolumeMounts(monitor *v1alpha1.TidbMonitor) []core.VolumeMount {
+	var mounts []core.VolumeMount
+	for i, name := range monitor.Spec.Grafana.ExtraDashboardsConfigMaps {
+		mounts = append(mounts, core.VolumeMount{
+			Name:      fmt.Sprintf("extra-dashboards-%d", i),
+			MountPath: path.Join(extraDashboardsPath, name),
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
+
+// getExtraConfigMapVolumes returns the volumes of the extra rules and dashboards ConfigMaps. The volumes
+// are optional, a missing ConfigMap doesn't block the pods from starting.
+func getExtraConfigMapVolumes(monitor *v1alpha1.TidbMonitor) []core.Volume {
+	var volumes []core.Volume
+	newVolume := func(volumeName, cmName string) core.Volume {
+		return core.Volume{
+			Name: volumeName,
+			VolumeSource: core.VolumeSource{
+				ConfigMap: &core.ConfigMapVolumeSource{
+					LocalObjectReference: core.LocalObjectReference{
+						Name: cmName,
+					},
+					Optional: pointer.BoolPtr(true),
+				},
+			},
+		}
+	}
+	for i, name := range monitor.Spec.Prometheus.ExtraRulesConfigMaps {
+		volumes = append(volumes, newVolume(fmt.Sprintf("extra-rules-%d", i), name))
+	}
+	if monitor.Spec.Grafana != nil {
+		for i, name := range monitor.Spec.Grafana.ExtraDashboardsConfigMaps {
+			volumes = append(volumes, newVolume(fmt.Sprintf("extra-dashboards-%d", i), name))
+		}
+	}
+	return volumes
+}
+
 func getMonitorSecret(monitor *v1alpha1.TidbMonitor) *core.Secret {
 	return &core.Secret{
 		ObjectMeta: meta.ObjectMeta{
@@ -303,6 +399,86 @@ func getMonitorRoleBinding(sa *core.ServiceAccount, role *rbac.Role, monitor *v1
 	}
 }
 
+// getMonitorCrossNamespaceRole returns the Role granting the monitor to discover the pods
+// of the target clusters in namespace ns. Owner references can't cross namespaces, so it's
+// not owned by the monitor and must be deleted explicitly.
+func getMonitorCrossNamespaceRole(monitor *v1alpha1.TidbMonitor, ns string, policyRules []rbac.PolicyRule) *rbac.Role {
+	return &rbac.Role{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      GetMonitorObjectNameCrossNamespace(monitor),
+			Namespace: ns,
+			Labels:    buildTidbMonitorLabel(monitor.Name),
+		},
+		Rules: policyRules,
+	}
+}
+
+func getMonitorCrossNamespaceRoleBinding(sa *core.ServiceAccount, role *rbac.Role, monitor *v1alpha1.TidbMonitor) *rbac.RoleBinding {
+	return &rbac.RoleBinding{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      GetMonitorObjectNameCrossNamespace(monitor),
+			Namespace: role.Namespace,
+			Labels:    buildTidbMonitorLabel(monitor.Name),
+		},
+		Subjects: []rbac.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      sa.Name,
+				Namespace: sa.Namespace,
+				APIGroup:  "",
+			},
+		},
+		RoleRef: rbac.RoleRef{
+			Kind:     "Role",
+			Name:     role.Name,
+			APIGroup: "rbac.authorization.k8s.io",
+		},
+	}
+}
+
+// getMonitorTargetNamespaces returns the sorted namespaces of the target TidbClusters and
+// DMClusters other than the namespace of the monitor
+func getMonitorTargetNamespaces(monitor *v1alpha1.TidbMonitor) []string {
+	set := map[string]struct{}{}
+	for _, tcRef := range monitor.Spec.Clusters {
+		set[tcRef.Namespace] = struct{}{}
+	}
+	if monitor.Spec.DM != nil {
+		for _, dmRef := range monitor.Spec.DM.Clusters {
+			set[dmRef.Namespace] = struct{}{}
+		}
+	}
+	delete(set, monitor.Namespace)
+	delete(set, "")
+	namespaces := make([]string, 0, len(set))
+	for ns := range set {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// addRemoteWriteAssets adds the secrets referenced by the remote write configs of the monitor
+// to the store, they are synced to the tls assets secret mounted by prometheus.
+func addRemoteWriteAssets(monitor *v1alpha1.TidbMonitor, store *Store) error {
+	for _, remoteWrite := range monitor.Spec.Prometheus.RemoteWrite {
+		if remoteWrite.BasicAuth != nil {
+			if err := store.addSecretKeyAsset(monitor.Namespace, remoteWrite.BasicAuth.Username); err != nil {
+				return err
+			}
+			if err := store.addSecretKeyAsset(monitor.Namespace, remoteWrite.BasicAuth.Password); err != nil {
+				return err
+			}
+		}
+		if remoteWrite.BearerTokenSecret != nil {
+			if err := store.addSecretKeyAsset(monitor.Namespace, *remoteWrite.BearerTokenSecret); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func getMonitorInitContainer(monitor *v1alpha1.TidbMonitor, tc *v1alpha1.TidbCluster) core.Container {
 	command := getInitCommand(monitor)
 	container := core.Container{
@@ -571,6 +747,7 @@ func getMonitorPrometheusContainer(monitor *v1alpha1.TidbMonitor, tc *v1alpha1.T
 			ReadOnly:  true,
 		})
 	}
+	c.VolumeMounts = append(c.VolumeMounts, getExtraRulesVolumeMounts(monitor)...)
 	return c
 }
 
@@ -707,6 +884,15 @@ func getMonitorGrafanaContainer(secret *core.Secret, monitor *v1alpha1.TidbMonit
 	if monitor.Spec.Grafana.AdditionalVolumeMounts != nil {
 		c.VolumeMounts = append(c.VolumeMounts, monitor.Spec.Grafana.AdditionalVolumeMounts...)
 	}
+	c.VolumeMounts = append(c.VolumeMounts, getExtraDashboardsVolumeMounts(monitor)...)
+	if monitor.GetShards() > 1 {
+		c.VolumeMounts = append(c.VolumeMounts, core.VolumeMount{
+			Name:      "datasource-shards",
+			MountPath: path.Join("/etc/grafana/provisioning/datasources", shardDatasourcesKey),
+			SubPath:   shardDatasourcesKey,
+			ReadOnly:  true,
+		})
+	}
 	return c
 }
 
@@ -771,6 +957,10 @@ func getMonitorPrometheusReloaderContainer(monitor *v1alpha1.TidbMonitor, shard
 		})
 		c.Command = append(c.Command, "--watched-dir=/prometheus-external-rules")
 	}
+	for _, mount := range getExtraRulesVolumeMounts(monitor) {
+		c.VolumeMounts = append(c.VolumeMounts, mount)
+		c.Command = append(c.Command, fmt.Sprintf("--watched-dir=%s", mount.MountPath))
+	}
 	return c
 }
 
@@ -853,6 +1043,9 @@ func getMonitorVolumes(monitor *v1alpha1.TidbMonitor) []core.Volume {
 					LocalObjectReference: core.LocalObjectReference{
 						Name: GetGrafanaConfigMapName(monitor),
 					},
+					Items: []core.KeyToPath{
+						{Key: "dashboards.yaml", Path: "dashboards.yaml"},
+					},
 				},
 			},
 		}
@@ -863,6 +1056,21 @@ func getMonitorVolumes(monitor *v1alpha1.TidbMonitor) []core.Volume {
 			},
 		}
 		volumes = append(volumes, dataSource, dashboardsProvisioning, grafanaDashboard)
+		if monitor.GetShards() > 1 {
+			volumes = append(volumes, core.Volume{
+				Name: "datasource-shards",
+				VolumeSource: core.VolumeSource{
+					ConfigMap: &core.ConfigMapVolumeSource{
+						LocalObjectReference: core.LocalObjectReference{
+							Name: GetGrafanaConfigMapName(monitor),
+						},
+						Items: []core.KeyToPath{
+							{Key: shardDatasourcesKey, Path: shardDatasourcesKey},
+						},
+					},
+				},
+			})
+		}
 	}
 	prometheusRules := core.Volume{
 		Name: "prometheus-rules",
@@ -907,6 +1115,7 @@ func getMonitorVolumes(monitor *v1alpha1.TidbMonitor) []core.Volume {
 			},
 		})
 	}
+	volumes = append(volumes, getExtraConfigMapVolumes(monitor)...)
 
 	return volumes
 }
@@ -1405,7 +1614,10 @@ func buildExternalLabels(monitor *v1alpha1.TidbMonitor) model.LabelSet {
 	return m
 }
 
-func generateRemoteWrite(monitor *v1alpha1.TidbMonitor) []*config.RemoteWriteConfig {
+// generateRemoteWrite generates the remote write configs of the monitor. The secrets referenced by
+// the basic auth and the bearer token are read from the tls assets mounted at util.ClusterAssetsTLSPath,
+// so the credentials are not written into the config, store has the values added by addRemoteWriteAssets.
+func generateRemoteWrite(monitor *v1alpha1.TidbMonitor, store *Store) []*config.RemoteWriteConfig {
 	var remoteWriteConfigs []*config.RemoteWriteConfig
 	for _, remoteWrite := range monitor.Spec.Prometheus.RemoteWrite {
 		url, err := url.Parse(remoteWrite.URL)
@@ -1416,6 +1628,21 @@ func generateRemoteWrite(monitor *v1alpha1.TidbMonitor) []*config.RemoteWriteCon
 		httpClientConfig := config.HTTPClientConfig{
 			BearerTokenFile: remoteWrite.BearerTokenFile,
 		}
+		if remoteWrite.BearerTokenSecret != nil {
+			key := secretKeyAssetKey(monitor.Namespace, *remoteWrite.BearerTokenSecret)
+			httpClientConfig.BearerTokenFile = path.Join(util.ClusterAssetsTLSPath, key.String())
+		}
+		if remoteWrite.BasicAuth != nil && store != nil {
+			usernameKey := secretKeyAssetKey(monitor.Namespace, remoteWrite.BasicAuth.Username)
+			passwordKey := secretKeyAssetKey(monitor.Namespace, remoteWrite.BasicAuth.Password)
+			httpClientConfig.BasicAuth = &config.BasicAuth{
+				Username: string(store.TLSAssets[usernameKey]),
+				// the password is masked when marshaling, refer to the mounted file instead
+				XXX: map[string]interface{}{
+					"password_file": path.Join(util.ClusterAssetsTLSPath, passwordKey.String()),
+				},
+			}
+		}
 		if remoteWrite.TLSConfig != nil {
 			httpClientConfig.TLSConfig = config.TLSConfig{
 				CAFile:             remoteWrite.TLSConfig.CAFile,
@@ -14,14 +14,17 @@
 package monitor
 
 import (
+	"context"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/prometheus/common/model"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 )
@@ -37,7 +40,7 @@ func TestGenerateRemoteWrite(t *testing.T) {
 			},
 		},
 	}
-	remoteWriteConfig := generateRemoteWrite(&monitor)
+	remoteWriteConfig := generateRemoteWrite(&monitor, nil)
 	if remoteWriteConfig == nil || remoteWriteConfig[0] == nil {
 		t.Errorf("unexpected remoteWriteConfig %v", remoteWriteConfig)
 	}
@@ -46,6 +49,86 @@ func TestGenerateRemoteWrite(t *testing.T) {
 	}
 }
 
+func TestGenerateRemoteWriteWithSecrets(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tmm := newFakeTidbMonitorManager()
+	secretIndexer := tmm.deps.KubeInformerFactory.Core().V1().Secrets().Informer().GetIndexer()
+	g.Expect(secretIndexer.Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "thanos-auth", Namespace: "ns"},
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("secret-password"),
+		},
+	})).To(Succeed())
+	g.Expect(secretIndexer.Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "thanos-token", Namespace: "ns"},
+		Data:       map[string][]byte{"token": []byte("secret-token")},
+	})).To(Succeed())
+
+	selector := func(name, key string) corev1.SecretKeySelector {
+		return corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key}
+	}
+	tokenSelector := selector("thanos-token", "token")
+	monitor := &v1alpha1.TidbMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "ns"},
+		Spec: v1alpha1.TidbMonitorSpec{
+			Prometheus: v1alpha1.PrometheusSpec{
+				RemoteWrite: []*v1alpha1.RemoteWriteSpec{
+					{
+						URL: "http://thanos-receive-0:19291/api/v1/receive",
+						BasicAuth: &v1alpha1.BasicAuth{
+							Username: selector("thanos-auth", "username"),
+							Password: selector("thanos-auth", "password"),
+						},
+						WriteRelabelConfigs: []v1alpha1.RelabelConfig{
+							{SourceLabels: model.LabelNames{"__name__"}, Regex: "go_.*", Action: "drop"},
+						},
+					},
+					{
+						URL:               "http://thanos-receive-1:19291/api/v1/receive",
+						BearerTokenSecret: &tokenSelector,
+						QueueConfig:       &v1alpha1.QueueConfig{MaxShards: 10},
+					},
+				},
+			},
+		},
+	}
+
+	store := NewStore(tmm.deps.SecretLister)
+	g.Expect(addRemoteWriteAssets(monitor, store)).To(Succeed())
+	g.Expect(store.TLSAssets).To(HaveLen(3))
+
+	cm, err := getPromConfigMap(monitor, []ClusterRegexInfo{{Name: "basic", Namespace: "ns"}}, nil, 0, store)
+	g.Expect(err).NotTo(HaveOccurred())
+	content := cm.Data["prometheus.yml"]
+	g.Expect(content).To(ContainSubstring("url: http://thanos-receive-0:19291/api/v1/receive"))
+	g.Expect(content).To(ContainSubstring("url: http://thanos-receive-1:19291/api/v1/receive"))
+	g.Expect(content).To(ContainSubstring("username: admin"))
+	g.Expect(content).To(ContainSubstring("password_file: /var/lib/cluster-assets-tls/secret_ns_thanos-auth_password"))
+	g.Expect(content).To(ContainSubstring("bearer_token_file: /var/lib/cluster-assets-tls/secret_ns_thanos-token_token"))
+	g.Expect(content).To(ContainSubstring("regex: go_.*"))
+	g.Expect(content).To(ContainSubstring("max_shards: 10"))
+	// the credentials are not written into the config
+	g.Expect(content).NotTo(ContainSubstring("secret-password"))
+	g.Expect(content).NotTo(ContainSubstring("secret-token"))
+
+	// the credentials are synced to the tls assets secret mounted by prometheus
+	g.Expect(tmm.syncAssetSecret(monitor, store)).To(Succeed())
+	secret := &corev1.Secret{}
+	cli := tmm.deps.GenericControl.(*controller.FakeGenericControl).FakeCli
+	g.Expect(cli.Get(context.TODO(), types.NamespacedName{Namespace: "ns", Name: GetTLSAssetsSecretName("foo")}, secret)).To(Succeed())
+	g.Expect(secret.Data).To(HaveKeyWithValue("secret_ns_thanos-auth_password", []byte("secret-password")))
+	g.Expect(secret.Data).To(HaveKeyWithValue("secret_ns_thanos-token_token", []byte("secret-token")))
+
+	// the referenced secret must exist
+	monitor.Spec.Prometheus.RemoteWrite[1].BearerTokenSecret = &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "not-exist"},
+		Key:                  "token",
+	}
+	g.Expect(addRemoteWriteAssets(monitor, NewStore(tmm.deps.SecretLister))).NotTo(Succeed())
+}
+
 func TestGetMonitorConfigMap(t *testing.T) {
 	g := NewGomegaWithT(t)
 	varTrue := true
@@ -103,7 +186,7 @@ func TestGetMonitorConfigMap(t *testing.T) {
 
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
-			cm, err := getPromConfigMap(&tt.monitor, tt.monitorClusterInfos, nil, 0)
+			cm, err := getPromConfigMap(&tt.monitor, tt.monitorClusterInfos, nil, 0, nil)
 			g.Expect(err).NotTo(HaveOccurred())
 			if tt.expected == nil {
 				g.Expect(cm).To(BeNil())
@@ -976,6 +1059,118 @@ func TestGetMonitorVolumes(t *testing.T) {
 	}
 }
 
+func TestExtraRulesAndDashboards(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{}
+	monitor := &v1alpha1.TidbMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "ns"},
+		Spec: v1alpha1.TidbMonitorSpec{
+			Prometheus: v1alpha1.PrometheusSpec{
+				ExtraRulesConfigMaps: []string{"team-a-rules", "team-b-rules"},
+			},
+			Grafana: &v1alpha1.GrafanaSpec{
+				ExtraDashboardsConfigMaps: []string{"team-a-dashboards"},
+			},
+			PrometheusReloader: &v1alpha1.PrometheusReloaderSpec{
+				MonitorContainer: v1alpha1.MonitorContainer{
+					BaseImage: "quay.io/prometheus-operator/prometheus-config-reloaders",
+					Version:   "v0.49.0",
+				},
+			},
+		},
+	}
+
+	volumes := map[string]corev1.Volume{}
+	for _, v := range getMonitorVolumes(monitor) {
+		volumes[v.Name] = v
+	}
+	for name, cm := range map[string]string{
+		"extra-rules-0":      "team-a-rules",
+		"extra-rules-1":      "team-b-rules",
+		"extra-dashboards-0": "team-a-dashboards",
+	} {
+		g.Expect(volumes).To(HaveKey(name))
+		g.Expect(volumes[name].ConfigMap.Name).To(Equal(cm))
+		g.Expect(*volumes[name].ConfigMap.Optional).To(BeTrue())
+	}
+
+	rulesMounts := []corev1.VolumeMount{
+		{Name: "extra-rules-0", MountPath: "/prometheus-rules/extra/team-a-rules", ReadOnly: true},
+		{Name: "extra-rules-1", MountPath: "/prometheus-rules/extra/team-b-rules", ReadOnly: true},
+	}
+	prometheus := getMonitorPrometheusContainer(monitor, tc, 0)
+	g.Expect(prometheus.VolumeMounts).To(ContainElements(rulesMounts))
+	// the config reloader reloads prometheus on changes of the rules
+	reloader := getMonitorPrometheusReloaderContainer(monitor, 0)
+	g.Expect(reloader.VolumeMounts).To(ContainElements(rulesMounts))
+	g.Expect(reloader.Command).To(ContainElements(
+		"--watched-dir=/prometheus-rules/extra/team-a-rules",
+		"--watched-dir=/prometheus-rules/extra/team-b-rules",
+	))
+	grafana := getMonitorGrafanaContainer(&corev1.Secret{}, monitor, tc)
+	g.Expect(grafana.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+		Name: "extra-dashboards-0", MountPath: "/grafana-dashboard-definitions/extra/team-a-dashboards", ReadOnly: true,
+	}))
+	g.Expect(getGrafanaConfigMap(monitor).Data["dashboards.yaml"]).To(ContainSubstring(`"path": "/grafana-dashboard-definitions/extra"`))
+
+	cm, err := getPromConfigMap(monitor, []ClusterRegexInfo{{Name: "basic", Namespace: "ns"}}, nil, 0, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cm.Data["prometheus.yml"]).To(ContainSubstring(`rule_files:
+- /prometheus-rules/extra/team-a-rules/*.rules.yml
+- /prometheus-rules/extra/team-b-rules/*.rules.yml
+`))
+
+	// no extra dashboards
+	monitor.Spec.Grafana.ExtraDashboardsConfigMaps = nil
+	g.Expect(getGrafanaConfigMap(monitor).Data["dashboards.yaml"]).NotTo(ContainSubstring("extra"))
+}
+
+func TestShardDatasources(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{ObjectMeta: metav1.ObjectMeta{Name: "basic", Namespace: "ns"}}
+	monitor := &v1alpha1.TidbMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "basic", Namespace: "ns"},
+		Spec: v1alpha1.TidbMonitorSpec{
+			Grafana: &v1alpha1.GrafanaSpec{},
+		},
+	}
+	g.Expect(getGrafanaConfigMap(monitor).Data).NotTo(HaveKey(shardDatasourcesKey))
+	for _, v := range getMonitorVolumes(monitor) {
+		g.Expect(v.Name).NotTo(Equal("datasource-shards"))
+	}
+
+	monitor.Spec.Shards = pointer.Int32Ptr(2)
+	g.Expect(getGrafanaConfigMap(monitor).Data[shardDatasourcesKey]).To(Equal(`apiVersion: 1
+datasources:
+- name: basic-prometheus
+  type: prometheus
+  access: proxy
+  url: http://basic-prometheus.ns:9090
+- name: basic-prometheus-shard-1
+  type: prometheus
+  access: proxy
+  url: http://basic-prometheus-shard-1.ns:9090
+`))
+	g.Expect(getMonitorVolumes(monitor)).To(ContainElement(corev1.Volume{
+		Name: "datasource-shards",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "basic-monitor-grafana"},
+				Items:                []corev1.KeyToPath{{Key: shardDatasourcesKey, Path: shardDatasourcesKey}},
+			},
+		},
+	}))
+	grafana := getMonitorGrafanaContainer(&corev1.Secret{}, monitor, tc)
+	g.Expect(grafana.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+		Name:      "datasource-shards",
+		MountPath: "/etc/grafana/provisioning/datasources/datasources-shards.yaml",
+		SubPath:   shardDatasourcesKey,
+		ReadOnly:  true,
+	}))
+}
+
 func TestGetMonitorPrometheusContainer(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -30,6 +30,7 @@ const (
 	GetStoresActionType                ActionType = "GetStores"
 	GetTombStoneStoresActionType       ActionType = "GetTombStoneStores"
 	GetStoreActionType                 ActionType = "GetStore"
+	GetRegionsByStoreActionType        ActionType = "GetRegionsByStore"
 	DeleteStoreActionType              ActionType = "DeleteStore"
 	SetStoreStateActionType            ActionType = "SetStoreState"
 	DeleteMemberByIDActionType         ActionType = "DeleteMemberByID"
@@ -151,6 +152,18 @@ func (c *FakePDClient) GetStore(id uint64) (*StoreInfo, error) {
 	return result.(*StoreInfo), nil
 }
 
+func (c *FakePDClient) GetRegionsByStore(id uint64) (*RegionsInfo, error) {
+	if reaction, ok := c.reactions[GetRegionsByStoreActionType]; ok {
+		action := &Action{ID: id}
+		result, err := reaction(action)
+		if err != nil {
+			return nil, err
+		}
+		return result.(*RegionsInfo), nil
+	}
+	return &RegionsInfo{}, nil
+}
+
 func (c *FakePDClient) DeleteStore(id uint64) error {
 	if reaction, ok := c.reactions[DeleteStoreActionType]; ok {
 		action := &Action{ID: id}
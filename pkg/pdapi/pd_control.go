@@ -19,7 +19,6 @@ import (
 	"net/http"
 	"sync"
 
-	"github.com/pingcap/tidb-operator/pkg/util"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog"
 )
@@ -44,6 +43,9 @@ type PDControlInterface interface {
 // defaultPDControl is the default implementation of PDControlInterface.
 type defaultPDControl struct {
 	kubeCli kubernetes.Interface
+	// clientTLSSecretTemplate is the template of the name of the secret of the client certificate,
+	// see GetClientTLSConfig
+	clientTLSSecretTemplate string
 
 	mutex     sync.Mutex
 	pdClients map[string]PDClient
@@ -62,12 +64,18 @@ func (c *noOpClose) Close() error {
 
 // NewDefaultPDControl returns a defaultPDControl instance
 func NewDefaultPDControl(kubeCli kubernetes.Interface) PDControlInterface {
-	return &defaultPDControl{kubeCli: kubeCli, pdClients: map[string]PDClient{}, pdEtcdClients: map[string]PDEtcdClient{}}
+	return NewDefaultPDControlWithClientTLSSecret(kubeCli, "")
+}
+
+// NewDefaultPDControlWithClientTLSSecret returns a defaultPDControl instance which accesses the PD of the tidb clusters
+// with TLS enabled with the client certificate in the secret named by the template, see GetClientTLSConfig
+func NewDefaultPDControlWithClientTLSSecret(kubeCli kubernetes.Interface, clientTLSSecretTemplate string) PDControlInterface {
+	return &defaultPDControl{kubeCli: kubeCli, clientTLSSecretTemplate: clientTLSSecretTemplate, pdClients: map[string]PDClient{}, pdEtcdClients: map[string]PDEtcdClient{}}
 }
 
 func (c *defaultPDControl) GetEndpoints(namespace Namespace, tcName string, tlsEnabled bool) (endpoints []string, tlsConfig *tls.Config, err error) {
 	if tlsEnabled {
-		tlsConfig, err = GetTLSConfig(c.kubeCli, namespace, tcName, util.ClusterClientTLSSecretName(tcName))
+		tlsConfig, err = GetClientTLSConfig(c.kubeCli, namespace, tcName, c.clientTLSSecretTemplate)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -86,7 +94,7 @@ func (c *defaultPDControl) GetPDEtcdClient(namespace Namespace, tcName string, t
 	var err error
 
 	if tlsEnabled {
-		tlsConfig, err = GetTLSConfig(c.kubeCli, namespace, tcName, util.ClusterClientTLSSecretName(tcName))
+		tlsConfig, err = GetClientTLSConfig(c.kubeCli, namespace, tcName, c.clientTLSSecretTemplate)
 		if err != nil {
 			klog.Errorf("Unable to get tls config for tidb cluster %q, pd etcd client may not work: %v", tcName, err)
 			return nil, err
@@ -140,7 +148,7 @@ func (pdc *defaultPDControl) GetPeerPDClient(namespace Namespace, tcName string,
 	var err error
 
 	if tlsEnabled {
-		tlsConfig, err = GetTLSConfig(pdc.kubeCli, namespace, tcName, util.ClusterClientTLSSecretName(tcName))
+		tlsConfig, err = GetClientTLSConfig(pdc.kubeCli, namespace, tcName, pdc.clientTLSSecretTemplate)
 		if err != nil {
 			klog.Errorf("Unable to get tls config for tidb cluster %q in %s, pd client may not work: %v", tcName, namespace, err)
 			return &pdClient{url: clientURL, httpClient: &http.Client{Timeout: DefaultTimeout}}
@@ -26,9 +26,11 @@ import (
 
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/tidb-operator/pkg/util"
 	"github.com/pingcap/tidb-operator/pkg/util/crypto"
 	httputil "github.com/pingcap/tidb-operator/pkg/util/http"
 	"github.com/tikv/pd/pkg/typeutil"
+	"k8s.io/apimachinery/pkg/api/errors"
 	types "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog"
@@ -50,6 +52,38 @@ func GetTLSConfig(kubeCli kubernetes.Interface, namespace Namespace, tcName stri
 	return crypto.LoadTlsConfigFromSecret(secret)
 }
 
+// ClusterPlaceholder is replaced by the name of the tidb cluster in the template of the name of the client TLS secret
+const ClusterPlaceholder = "{cluster}"
+
+// GetClientTLSConfig returns *tls.Config of the client certificate the operator uses to access the given TiDB cluster,
+// it's loaded from the secret returned by clientTLSSecretName.
+func GetClientTLSConfig(kubeCli kubernetes.Interface, namespace Namespace, tcName string, secretTemplate string) (*tls.Config, error) {
+	secretName, err := clientTLSSecretName(kubeCli, namespace, tcName, secretTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return GetTLSConfig(kubeCli, namespace, tcName, secretName)
+}
+
+// clientTLSSecretName returns the name of the secret of the client certificate the operator uses to access the given
+// TiDB cluster. If the template is set and the secret named by it exists, it's used, so the operator has an identity
+// distinct from the tools, otherwise the <clusterName>-cluster-client-secret shared with the tools is used.
+func clientTLSSecretName(kubeCli kubernetes.Interface, namespace Namespace, tcName string, secretTemplate string) (string, error) {
+	defaultName := util.ClusterClientTLSSecretName(tcName)
+	if secretTemplate == "" {
+		return defaultName, nil
+	}
+	secretName := strings.ReplaceAll(secretTemplate, ClusterPlaceholder, tcName)
+	_, err := kubeCli.CoreV1().Secrets(string(namespace)).Get(context.Background(), secretName, types.GetOptions{})
+	if errors.IsNotFound(err) {
+		return defaultName, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to get secret %s/%s: %v", namespace, secretName, err)
+	}
+	return secretName, nil
+}
+
 // PDClient provides pd server's api
 type PDClient interface {
 	// GetHealth returns the PD's health info
@@ -66,6 +100,8 @@ type PDClient interface {
 	GetTombStoneStores() (*StoresInfo, error)
 	// GetStore gets a TiKV store for a specific store id from cluster
 	GetStore(storeID uint64) (*StoreInfo, error)
+	// GetRegionsByStore lists all regions which have a peer on a specific store
+	GetRegionsByStore(storeID uint64) (*RegionsInfo, error)
 	// storeLabelsEqualNodeLabels compares store labels with node labels
 	// for historic reasons, PD stores TiKV labels as []*StoreLabel which is a key-value pair slice
 	SetStoreLabels(storeID uint64, labels map[string]string) (bool, error)
@@ -99,6 +135,7 @@ var (
 	membersPrefix          = "pd/api/v1/members"
 	storesPrefix           = "pd/api/v1/stores"
 	storePrefix            = "pd/api/v1/store"
+	storeRegionsPrefix     = "pd/api/v1/regions/store"
 	configPrefix           = "pd/api/v1/config"
 	clusterIDPrefix        = "pd/api/v1/cluster"
 	schedulersPrefix       = "pd/api/v1/schedulers"
@@ -182,6 +219,18 @@ type StoresInfo struct {
 	Stores []*StoreInfo `json:"stores"`
 }
 
+// RegionInfo is a single region info returned from PD RESTful interface
+type RegionInfo struct {
+	ID    uint64         `json:"id"`
+	Peers []*metapb.Peer `json:"peers,omitempty"`
+}
+
+// RegionsInfo is regions info returned from PD RESTful interface
+type RegionsInfo struct {
+	Count   int           `json:"count"`
+	Regions []*RegionInfo `json:"regions"`
+}
+
 // MembersInfo is PD members info returned from PD RESTful interface
 //type Members map[string][]*pdpb.Member
 type MembersInfo struct {
@@ -345,6 +394,20 @@ func (c *pdClient) GetStore(storeID uint64) (*StoreInfo, error) {
 	return storeInfo, nil
 }
 
+func (c *pdClient) GetRegionsByStore(storeID uint64) (*RegionsInfo, error) {
+	apiURL := fmt.Sprintf("%s/%s/%d", c.url, storeRegionsPrefix, storeID)
+	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	regionsInfo := &RegionsInfo{}
+	err = json.Unmarshal(body, regionsInfo)
+	if err != nil {
+		return nil, err
+	}
+	return regionsInfo, nil
+}
+
 func (c *pdClient) DeleteStore(storeID uint64) error {
 	var exist bool
 	stores, err := c.GetStores()
@@ -14,6 +14,7 @@
 package pdapi
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -27,6 +28,9 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 )
 
 const (
@@ -306,6 +310,36 @@ func TestGetStore(t *testing.T) {
 	}
 }
 
+func TestGetRegionsByStore(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	id := uint64(1)
+	regions := &RegionsInfo{
+		Count: 2,
+		Regions: []*RegionInfo{
+			{ID: 2, Peers: []*metapb.Peer{{Id: 3, StoreId: id}}},
+			{ID: 4, Peers: []*metapb.Peer{{Id: 5, StoreId: id}, {Id: 6, StoreId: 7}}},
+		},
+	}
+
+	regionsBytes, err := json.Marshal(regions)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+		g.Expect(request.Method).To(Equal("GET"), "test method")
+		g.Expect(request.URL.Path).To(Equal(fmt.Sprintf("/%s/%d", storeRegionsPrefix, id)), "test url")
+
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		w.Write(regionsBytes)
+	})
+	defer svc.Close()
+
+	pdClient := NewPDClient(svc.URL, DefaultTimeout, &tls.Config{})
+	result, err := pdClient.GetRegionsByStore(id)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(regions))
+}
+
 func TestSetStoreLabels(t *testing.T) {
 	g := NewGomegaWithT(t)
 	id := uint64(1)
@@ -804,3 +838,61 @@ func TestGeneric(t *testing.T) {
 		})
 	}
 }
+
+func TestClientTLSSecretName(t *testing.T) {
+	tests := []struct {
+		name         string
+		template     string
+		secrets      []string
+		expectSecret string
+		// expectSecretCreated is the secret expected after the demo-operator-client-secret is created
+		expectSecretCreated string
+	}{
+		{
+			name:         "no template",
+			secrets:      []string{"demo-operator-client-secret"},
+			expectSecret: "demo-cluster-client-secret",
+		},
+		{
+			name:         "the secret of the template exists",
+			template:     "{cluster}-operator-client-secret",
+			secrets:      []string{"demo-operator-client-secret"},
+			expectSecret: "demo-operator-client-secret",
+		},
+		{
+			name:                "fall back to the cluster client secret",
+			template:            "{cluster}-operator-client-secret",
+			secrets:             []string{"other-operator-client-secret"},
+			expectSecret:        "demo-cluster-client-secret",
+			expectSecretCreated: "demo-operator-client-secret",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			kubeCli := kubefake.NewSimpleClientset()
+			for _, name := range tt.secrets {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"}}
+				_, err := kubeCli.CoreV1().Secrets("ns").Create(context.TODO(), secret, metav1.CreateOptions{})
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+
+			secretName, err := clientTLSSecretName(kubeCli, Namespace("ns"), "demo", tt.template)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(secretName).To(Equal(tt.expectSecret))
+
+			if tt.expectSecretCreated == "" {
+				return
+			}
+			// the secret is resolved on every call rather than cached, so the client certificate created later is picked up
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "demo-operator-client-secret", Namespace: "ns"}}
+			_, err = kubeCli.CoreV1().Secrets("ns").Create(context.TODO(), secret, metav1.CreateOptions{})
+			g.Expect(err).NotTo(HaveOccurred())
+			secretName, err = clientTLSSecretName(kubeCli, Namespace("ns"), "demo", tt.template)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(secretName).To(Equal(tt.expectSecretCreated))
+		})
+	}
+}
@@ -74,3 +74,10 @@ type CreateUpdateStrategy interface {
 	// ValidateUpdate validates an update request for existing resource
 	ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList
 }
+
+// WarningStrategy is optionally implemented by a CreateUpdateStrategy to return the warnings of a
+// new or updated resource, the warnings are returned to the client and never reject the request.
+type WarningStrategy interface {
+	// Warnings returns the warnings of a new or updated resource
+	Warnings(ctx context.Context, obj runtime.Object) []string
+}
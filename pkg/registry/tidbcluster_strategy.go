@@ -19,11 +19,15 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/defaulting"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
+	"github.com/pingcap/tidb-operator/pkg/configschema"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog"
 )
 
+// ImageDigestResolver pins the images of the TidbClusters to their digests if it's set
+var ImageDigestResolver defaulting.ImageDigestResolver
+
 // +k8s:deepcopy-gen=false
 type TidbClusterStrategy struct{}
 
@@ -34,11 +38,16 @@ func (TidbClusterStrategy) NewObject() runtime.Object {
 func (TidbClusterStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
 	if tc, ok := castTidbCluster(obj); ok {
 		defaulting.SetTidbClusterDefault(tc)
+		defaulting.SetTidbClusterImages(tc, ImageDigestResolver)
 	}
 }
 
 func (TidbClusterStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
-	// no op to not affect the cluster managed by old versions of the helm chart
+	// the other defaults are not set to not affect the cluster managed by old versions of the helm chart,
+	// the resolved images are only used by the operator
+	if tc, ok := castTidbCluster(obj); ok {
+		defaulting.SetTidbClusterImages(tc, ImageDigestResolver)
+	}
 }
 
 func (TidbClusterStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
@@ -57,6 +66,17 @@ func (TidbClusterStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.
 	return field.ErrorList{}
 }
 
+// Warnings returns the unknown and deprecated config keys of the components as warnings,
+// they are not validation errors because the schemas may be outdated for the versions.
+func (TidbClusterStrategy) Warnings(ctx context.Context, obj runtime.Object) []string {
+	if tc, ok := castTidbCluster(obj); ok {
+		return configschema.Warnings(configschema.ValidateTidbCluster(tc))
+	}
+	return nil
+}
+
+var _ WarningStrategy = TidbClusterStrategy{}
+
 func castTidbCluster(obj runtime.Object) (*v1alpha1.TidbCluster, bool) {
 	tc, ok := obj.(*v1alpha1.TidbCluster)
 	if !ok {
@@ -20,7 +20,6 @@ import (
 	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
-	"github.com/pingcap/tidb-operator/pkg/util"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog"
 )
@@ -39,11 +38,20 @@ type TiFlashControlInterface interface {
 type defaultTiFlashControl struct {
 	mutex   sync.Mutex
 	kubeCli kubernetes.Interface
+	// clientTLSSecretTemplate is the template of the name of the secret of the client certificate,
+	// see pdapi.GetClientTLSConfig
+	clientTLSSecretTemplate string
 }
 
 // NewDefaultTiFlashControl returns a defaultTiFlashControl instance
 func NewDefaultTiFlashControl(kubeCli kubernetes.Interface) TiFlashControlInterface {
-	return &defaultTiFlashControl{kubeCli: kubeCli}
+	return NewDefaultTiFlashControlWithClientTLSSecret(kubeCli, "")
+}
+
+// NewDefaultTiFlashControlWithClientTLSSecret returns a defaultTiFlashControl instance which accesses the TiFlash of the tidb
+// clusters with TLS enabled with the client certificate in the secret named by the template, see pdapi.GetClientTLSConfig
+func NewDefaultTiFlashControlWithClientTLSSecret(kubeCli kubernetes.Interface, clientTLSSecretTemplate string) TiFlashControlInterface {
+	return &defaultTiFlashControl{kubeCli: kubeCli, clientTLSSecretTemplate: clientTLSSecretTemplate}
 }
 
 func (tc *defaultTiFlashControl) GetTiFlashPodClient(namespace string, tcName string, podName string, tlsEnabled bool) TiFlashClient {
@@ -56,7 +64,7 @@ func (tc *defaultTiFlashControl) GetTiFlashPodClient(namespace string, tcName st
 
 	if tlsEnabled {
 		scheme = "https"
-		tlsConfig, err = pdapi.GetTLSConfig(tc.kubeCli, pdapi.Namespace(namespace), tcName, util.ClusterClientTLSSecretName(tcName))
+		tlsConfig, err = pdapi.GetClientTLSConfig(tc.kubeCli, pdapi.Namespace(namespace), tcName, tc.clientTLSSecretTemplate)
 		if err != nil {
 			klog.Errorf("Unable to get tls config for TiFlash cluster %q, tiflash client may not work: %v", tcName, err)
 			return NewTiFlashClient(TiFlashPodClientURL(namespace, tcName, podName, scheme), DefaultTimeout, tlsConfig, true)
@@ -19,7 +19,6 @@ import (
 	"sync"
 
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
-	"github.com/pingcap/tidb-operator/pkg/util"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog"
 )
@@ -35,11 +34,20 @@ type defaultTiKVControl struct {
 	mutex       sync.Mutex
 	kubeCli     kubernetes.Interface
 	tikvClients map[string]TiKVClient
+	// clientTLSSecretTemplate is the template of the name of the secret of the client certificate,
+	// see pdapi.GetClientTLSConfig
+	clientTLSSecretTemplate string
 }
 
 // NewDefaultTiKVControl returns a defaultTiKVControl instance
 func NewDefaultTiKVControl(kubeCli kubernetes.Interface) TiKVControlInterface {
-	return &defaultTiKVControl{kubeCli: kubeCli, tikvClients: map[string]TiKVClient{}}
+	return NewDefaultTiKVControlWithClientTLSSecret(kubeCli, "")
+}
+
+// NewDefaultTiKVControlWithClientTLSSecret returns a defaultTiKVControl instance which accesses the TiKV of the tidb clusters
+// with TLS enabled with the client certificate in the secret named by the template, see pdapi.GetClientTLSConfig
+func NewDefaultTiKVControlWithClientTLSSecret(kubeCli kubernetes.Interface, clientTLSSecretTemplate string) TiKVControlInterface {
+	return &defaultTiKVControl{kubeCli: kubeCli, tikvClients: map[string]TiKVClient{}, clientTLSSecretTemplate: clientTLSSecretTemplate}
 }
 
 func (tc *defaultTiKVControl) GetTiKVPodClient(namespace string, tcName string, podName string, tlsEnabled bool) TiKVClient {
@@ -52,7 +60,7 @@ func (tc *defaultTiKVControl) GetTiKVPodClient(namespace string, tcName string,
 
 	if tlsEnabled {
 		scheme = "https"
-		tlsConfig, err = pdapi.GetTLSConfig(tc.kubeCli, pdapi.Namespace(namespace), tcName, util.ClusterClientTLSSecretName(tcName))
+		tlsConfig, err = pdapi.GetClientTLSConfig(tc.kubeCli, pdapi.Namespace(namespace), tcName, tc.clientTLSSecretTemplate)
 		if err != nil {
 			klog.Errorf("Unable to get tls config for TiKV cluster %q, tikv client may not work: %v", tcName, err)
 			return NewTiKVClient(TiKVPodClientURL(namespace, tcName, podName, scheme), DefaultTimeout, tlsConfig, true)
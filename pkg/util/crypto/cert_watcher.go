@@ -0,0 +1,146 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+)
+
+// SecretCertWatcher watches a TLS Secret and always serves the latest
+// certificate in it, so the serving certificate of an HTTPS server can be
+// rotated without restarting the server.
+// The Secret is expected to contain `tls.crt`, `tls.key` and optionally `ca.crt`.
+type SecretCertWatcher struct {
+	namespace string
+	name      string
+	informer  cache.SharedIndexInformer
+
+	lock sync.RWMutex
+	cert *tls.Certificate
+	ca   []byte
+
+	caHandlers []func(ca []byte)
+}
+
+// NewSecretCertWatcher returns a SecretCertWatcher watching the Secret namespace/name
+func NewSecretCertWatcher(kubeCli kubernetes.Interface, namespace, name string, resyncDuration time.Duration) *SecretCertWatcher {
+	factory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeCli, resyncDuration,
+		kubeinformers.WithNamespace(namespace),
+		kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}))
+	w := &SecretCertWatcher{
+		namespace: namespace,
+		name:      name,
+		informer:  factory.Core().V1().Secrets().Informer(),
+	}
+	w.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: w.onSecret,
+		UpdateFunc: func(_, cur interface{}) {
+			w.onSecret(cur)
+		},
+	})
+	return w
+}
+
+// AddCAChangeHandler registers a handler which is called with the new CA
+// when the `ca.crt` in the Secret changes, including the first load.
+// It must be called before Run.
+func (w *SecretCertWatcher) AddCAChangeHandler(handler func(ca []byte)) {
+	w.caHandlers = append(w.caHandlers, handler)
+}
+
+// Run starts watching the Secret and waits until the certificate is loaded
+func (w *SecretCertWatcher) Run(stopCh <-chan struct{}) error {
+	go w.informer.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, w.informer.HasSynced) {
+		return fmt.Errorf("failed to sync the cache of secret %s/%s", w.namespace, w.name)
+	}
+	if w.getCertificate() == nil {
+		return fmt.Errorf("no valid certificate is loaded from secret %s/%s", w.namespace, w.name)
+	}
+	return nil
+}
+
+// GetCertificate returns the latest certificate, it can be used as
+// tls.Config.GetCertificate
+func (w *SecretCertWatcher) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert := w.getCertificate()
+	if cert == nil {
+		return nil, fmt.Errorf("no valid certificate is loaded from secret %s/%s", w.namespace, w.name)
+	}
+	return cert, nil
+}
+
+// TLSConfig returns a tls.Config serving the latest certificate in the Secret
+func (w *SecretCertWatcher) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: w.GetCertificate,
+	}
+}
+
+func (w *SecretCertWatcher) getCertificate() *tls.Certificate {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.cert
+}
+
+func (w *SecretCertWatcher) onSecret(obj interface{}) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+	if err := w.loadSecret(secret); err != nil {
+		klog.Errorf("failed to load certificate from secret %s/%s, keep serving the previous one, error: %v", w.namespace, w.name, err)
+	}
+}
+
+func (w *SecretCertWatcher) loadSecret(secret *corev1.Secret) error {
+	certPEM, certExists := secret.Data[corev1.TLSCertKey]
+	keyPEM, keyExists := secret.Data[corev1.TLSPrivateKeyKey]
+	if !certExists || !keyExists {
+		return fmt.Errorf("cert or key does not exist in secret %s/%s", secret.Namespace, secret.Name)
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+	ca := secret.Data[corev1.ServiceAccountRootCAKey]
+
+	w.lock.Lock()
+	w.cert = &cert
+	caChanged := !bytes.Equal(w.ca, ca)
+	w.ca = ca
+	w.lock.Unlock()
+
+	klog.Infof("certificate in secret %s/%s is loaded, resourceVersion: %s", secret.Namespace, secret.Name, secret.ResourceVersion)
+	if caChanged && len(ca) > 0 {
+		for _, handler := range w.caHandlers {
+			handler(ca)
+		}
+	}
+	return nil
+}
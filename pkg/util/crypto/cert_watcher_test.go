@@ -0,0 +1,133 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newSelfSignedCert(g *GomegaWithT, serial int64) (certPEM, keyPEM []byte) {
+	privKey, err := newPrivateKey(rsaKeySize)
+	g.Expect(err).Should(BeNil())
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &privKey.PublicKey, privKey)
+	g.Expect(err).Should(BeNil())
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return certPEM, convertKeyToPEM("RSA PRIVATE KEY", privKey)
+}
+
+func newTLSSecret(cert, key []byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "serving-cert",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:              cert,
+			corev1.TLSPrivateKeyKey:        key,
+			corev1.ServiceAccountRootCAKey: cert,
+		},
+	}
+}
+
+func TestSecretCertWatcher(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cert1, key1 := newSelfSignedCert(g, 1)
+	kubeCli := kubefake.NewSimpleClientset(newTLSSecret(cert1, key1))
+
+	var lock sync.Mutex
+	var cas [][]byte
+	w := NewSecretCertWatcher(kubeCli, "default", "serving-cert", 0)
+	w.AddCAChangeHandler(func(ca []byte) {
+		lock.Lock()
+		defer lock.Unlock()
+		cas = append(cas, ca)
+	})
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	g.Expect(w.Run(stopCh)).Should(Succeed())
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", w.TLSConfig())
+	g.Expect(err).Should(BeNil())
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.(*tls.Conn).Handshake()
+			}()
+		}
+	}()
+
+	servingSerial := func() int64 {
+		conn, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+		g.Expect(err).Should(BeNil())
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+	}
+	g.Expect(servingSerial()).Should(Equal(int64(1)))
+
+	// rotate the certificate in the secret
+	cert2, key2 := newSelfSignedCert(g, 2)
+	_, err = kubeCli.CoreV1().Secrets("default").Update(context.TODO(), newTLSSecret(cert2, key2), metav1.UpdateOptions{})
+	g.Expect(err).Should(BeNil())
+	g.Eventually(servingSerial, 5*time.Second, 100*time.Millisecond).Should(Equal(int64(2)))
+
+	g.Eventually(func() [][]byte {
+		lock.Lock()
+		defer lock.Unlock()
+		return cas
+	}, 5*time.Second, 100*time.Millisecond).Should(Equal([][]byte{cert1, cert2}))
+
+	// invalid content keeps serving the previous certificate
+	g.Expect(w.loadSecret(newTLSSecret(cert1, key2))).ShouldNot(Succeed())
+	cert, err := w.GetCertificate(nil)
+	g.Expect(err).Should(BeNil())
+	g.Expect(cert.Certificate[0]).Should(Equal(mustDecodePEM(cert2)))
+}
+
+func mustDecodePEM(data []byte) []byte {
+	block, _ := pem.Decode(data)
+	return block.Bytes
+}
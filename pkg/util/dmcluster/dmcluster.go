@@ -28,6 +28,10 @@ const (
 	StatfulSetNotUpToDate = "StatefulSetNotUpToDate"
 	// MasterUnhealthy is added when one of dm-master members is unhealthy.
 	MasterUnhealthy = "DMMasterUnhealthy"
+	// PodManagementPolicyChanged is added when the podManagementPolicy of a component differs from its StatefulSet.
+	PodManagementPolicyChanged = "PodManagementPolicyChanged"
+	// PodManagementPolicyApplied is added when the podManagementPolicy of a component matches its StatefulSet again.
+	PodManagementPolicyApplied = "PodManagementPolicyApplied"
 )
 
 // NewDMClusterCondition creates a new dmcluster condition.
@@ -34,6 +34,44 @@ const (
 	TiDBUnhealthy = "TiDBUnhealthy"
 	// TiFlashStoreNotUp is added when one of tiflash stores is not up.
 	TiFlashStoreNotUp = "TiFlashStoreNotUp"
+	// PreScaleInJobFailed is added when the pre scale-in job of a tikv store failed.
+	PreScaleInJobFailed = "PreScaleInJobFailed"
+	// PreScaleInJobComplete is added when the pre scale-in job of a tikv store completed.
+	PreScaleInJobComplete = "PreScaleInJobComplete"
+	// PodManagementPolicyChanged is added when the podManagementPolicy of a component differs from its StatefulSet.
+	PodManagementPolicyChanged = "PodManagementPolicyChanged"
+	// PodManagementPolicyApplied is added when the podManagementPolicy of a component matches its StatefulSet again.
+	PodManagementPolicyApplied = "PodManagementPolicyApplied"
+	// PDQuorumUnsafe is added when removing a pd member would break the quorum of the pd cluster.
+	PDQuorumUnsafe = "PDQuorumUnsafe"
+	// PDQuorumSafe is added when a pd member can be removed without breaking the quorum of the pd cluster.
+	PDQuorumSafe = "PDQuorumSafe"
+	// UpgradedPodNotReady is added when an upgraded pod is not ready and the upgrade can't continue.
+	UpgradedPodNotReady = "UpgradedPodNotReady"
+	// UpgradedStoreNotUp is added when the store of an upgraded tikv pod is not up and the upgrade can't continue.
+	UpgradedStoreNotUp = "UpgradedStoreNotUp"
+	// PDQuorumLost is added when the pd cluster lost its quorum and the failover can't continue.
+	PDQuorumLost = "PDQuorumLost"
+	// PDStatusSyncStale is added when the pd status has not been synced for longer than the threshold and the failover can't continue.
+	PDStatusSyncStale = "PDStatusSyncStale"
+	// PDMemberIDDuplicated is added when more than one pd member report the same member id and the failover can't continue.
+	PDMemberIDDuplicated = "PDMemberIDDuplicated"
+	// ExternalPDEndpointsUnreachable is added when none of the external PD endpoints is reachable.
+	ExternalPDEndpointsUnreachable = "ExternalPDEndpointsUnreachable"
+	// ExternalPDEndpointsReachable is added when any of the external PD endpoints is reachable again.
+	ExternalPDEndpointsReachable = "ExternalPDEndpointsReachable"
+	// ClusterHealthBelowFloor is added when the healthy ratio of the PD members and TiKV stores is below the floor and the failover can't continue.
+	ClusterHealthBelowFloor = "ClusterHealthBelowFloor"
+	// Resolved is added when the check blocking an upgrade or a failover passes again.
+	Resolved = "Resolved"
+	// UnknownConfigKeysFound is added when the config of a component has keys unknown to its config schema.
+	UnknownConfigKeysFound = "UnknownConfigKeysFound"
+	// UnknownConfigKeysRemoved is added when the unknown config keys are removed.
+	UnknownConfigKeysRemoved = "UnknownConfigKeysRemoved"
+	// ReconcilePaused is added when the reconciliation of the tidb cluster or any of its components is paused.
+	ReconcilePaused = "ReconcilePaused"
+	// ReconcileResumed is added when the reconciliation of the tidb cluster and all its components is resumed.
+	ReconcileResumed = "ReconcileResumed"
 )
 
 // NewTidbClusterCondition creates a new tidbcluster condition.
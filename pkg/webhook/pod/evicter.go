@@ -0,0 +1,201 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/webhook/util"
+	admission "k8s.io/api/admission/v1beta1"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+const (
+	// evictionSubResource is the subresource of the eviction requests of the pods
+	evictionSubResource = "eviction"
+	// evictionRetryAfterSeconds is the time the evicting clients are suggested to wait before retrying
+	evictionRetryAfterSeconds = 10
+)
+
+// EvictionConfig is the config of the admission of the pod evictions
+type EvictionConfig struct {
+	// FailOpen admits the eviction when PD is unreachable
+	FailOpen bool
+	// TiKVLeaderCountThreshold is the region leader count below which the eviction of a TiKV pod is admitted
+	TiKVLeaderCountThreshold int
+}
+
+// Webhook server receive request to evict pod, e.g. from node drains or the cluster autoscaler.
+// The eviction of the PD leader pod is refused until the PD leader is transferred to another healthy member,
+// and the eviction of a TiKV pod is refused until its region leaders are evicted or the evict leader timeout passes.
+func (pc *PodAdmissionControl) admitEvictPods(name, namespace string) *admission.AdmissionResponse {
+	klog.Infof("receive admission to %s pod[%s/%s]", "evict", namespace, name)
+
+	pod, err := pc.kubeCli.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		klog.Infof("failed to find pod[%s/%s] during evict it,admit to evict", namespace, name)
+		return util.ARSuccess()
+	}
+
+	l := label.Label(pod.Labels)
+	if !l.IsManagedByTiDBOperator() || !l.IsTidbClusterPod() || !(l.IsPD() || l.IsTiKV()) {
+		klog.Infof("pod[%s/%s] is not PD or TiKV of tidbcluster,admit to evict", namespace, name)
+		return util.ARSuccess()
+	}
+
+	tcName, exist := pod.Labels[label.InstanceLabelKey]
+	if !exist {
+		klog.Errorf("pod[%s/%s] has no label: %s", namespace, name, label.InstanceLabelKey)
+		return util.ARSuccess()
+	}
+	tc, err := pc.tcLister.TidbClusters(namespace).Get(tcName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			klog.Infof("tc[%s/%s] had been deleted,admit to evict pod[%s/%s]", namespace, tcName, namespace, name)
+			return util.ARSuccess()
+		}
+		klog.Errorf("failed get tc[%s/%s],refuse to evict pod[%s/%s]", namespace, tcName, namespace, name)
+		return util.ARFail(err)
+	}
+
+	var pdClient pdapi.PDClient
+	if tc.HeterogeneousWithoutLocalPD() {
+		pdClient = pc.pdControl.GetPDClient(pdapi.Namespace(tc.Spec.Cluster.Namespace), tc.Spec.Cluster.Name, tc.IsTLSClusterEnabled())
+	} else {
+		pdClient = pc.pdControl.GetPDClient(pdapi.Namespace(namespace), tcName, tc.IsTLSClusterEnabled())
+	}
+
+	if l.IsPD() {
+		return pc.admitEvictPDPod(tc, pod, pdClient)
+	}
+	return pc.admitEvictTiKVPod(tc, pod, pdClient)
+}
+
+func (pc *PodAdmissionControl) admitEvictPDPod(tc *v1alpha1.TidbCluster, pod *core.Pod, pdClient pdapi.PDClient) *admission.AdmissionResponse {
+	name := pod.Name
+	namespace := pod.Namespace
+
+	if tc.PDStsDesiredReplicas() < 2 {
+		klog.Infof("PD statefulset replicas are less than 2, admit to evict pod[%s/%s]", namespace, name)
+		return util.ARSuccess()
+	}
+
+	leader, err := pdClient.GetPDLeader()
+	if err != nil {
+		return pc.evictionPDUnreachable(pod, err)
+	}
+	if leader.Name != name {
+		klog.Infof("pod[%s/%s] is not pd leader,admit to evict", namespace, name)
+		return util.ARSuccess()
+	}
+
+	var targets []string
+	for memberName, member := range tc.Status.PD.Members {
+		if memberName != leader.Name && member.Health {
+			targets = append(targets, memberName)
+		}
+	}
+	if len(targets) == 0 {
+		klog.Infof("tc[%s/%s] has no healthy pd member to transfer pd leader to,refuse to evict pod[%s/%s]", namespace, tc.Name, namespace, name)
+		return rejectEviction("pd leader pod[%s/%s] has no healthy member to transfer the leader to", namespace, name)
+	}
+	sort.Strings(targets)
+
+	if err := pdClient.TransferPDLeader(targets[0]); err != nil {
+		return pc.evictionPDUnreachable(pod, err)
+	}
+	klog.Infof("tc[%s/%s] start to transfer pd leader to %s,refuse to evict pod[%s/%s]", namespace, tc.Name, targets[0], namespace, name)
+	return rejectEviction("pd leader is being transferred from pod[%s/%s] to %s", namespace, name, targets[0])
+}
+
+func (pc *PodAdmissionControl) admitEvictTiKVPod(tc *v1alpha1.TidbCluster, pod *core.Pod, pdClient pdapi.PDClient) *admission.AdmissionResponse {
+	name := pod.Name
+	namespace := pod.Namespace
+
+	if tc.TiKVStsDesiredReplicas() < 2 {
+		klog.Infof("TiKV statefulset replicas are less than 2, admit to evict pod[%s/%s]", namespace, name)
+		return util.ARSuccess()
+	}
+
+	storesInfo, err := pdClient.GetStores()
+	if err != nil {
+		return pc.evictionPDUnreachable(pod, err)
+	}
+	store, err := getStoreByPod(pod, storesInfo)
+	if err != nil {
+		klog.Infof("%v,admit to evict", err)
+		return util.ARSuccess()
+	}
+	if store.Store.StateName != v1alpha1.TiKVStateUp || store.Status.LeaderCount < pc.evictionConfig.TiKVLeaderCountThreshold {
+		klog.Infof("pod[%s/%s] store[%d] is %s with %d region leaders,admit to evict", namespace, name, store.Store.Id, store.Store.StateName, store.Status.LeaderCount)
+		return util.ARSuccess()
+	}
+
+	// the evict leader scheduler is ended once the pod is recreated, or by the TiKV member manager
+	// after the evict leader timeout if the eviction is abandoned without the pod being deleted
+	evictLeaderBeginTimeStr, evicting := pod.Annotations[EvictLeaderBeginTime]
+	if !evicting {
+		if err := beginEvictLeader(pc.kubeCli, store.Store.Id, pod, pdClient); err != nil {
+			return pc.evictionPDUnreachable(pod, err)
+		}
+		return rejectEviction("region leaders of pod[%s/%s] are being evicted", namespace, name)
+	}
+
+	evictLeaderBeginTime, err := time.Parse(time.RFC3339, evictLeaderBeginTimeStr)
+	if err != nil {
+		klog.Errorf("parse annotation:[%s] of pod[%s/%s] to time failed,admit to evict", EvictLeaderBeginTime, namespace, name)
+		return util.ARSuccess()
+	}
+	if time.Now().After(evictLeaderBeginTime.Add(tc.TiKVEvictLeaderTimeout())) {
+		klog.Infof("evicting region leaders of pod[%s/%s] timed out,admit to evict", namespace, name)
+		return util.ARSuccess()
+	}
+	return rejectEviction("region leaders of pod[%s/%s] are being evicted, %d left", namespace, name, store.Status.LeaderCount)
+}
+
+// evictionPDUnreachable admits the eviction if the eviction fails open, otherwise refuses it
+func (pc *PodAdmissionControl) evictionPDUnreachable(pod *core.Pod, err error) *admission.AdmissionResponse {
+	if pc.evictionConfig.FailOpen {
+		klog.Warningf("failed to access pd for pod[%s/%s],admit to evict: %v", pod.Namespace, pod.Name, err)
+		return util.ARSuccess()
+	}
+	klog.Errorf("failed to access pd for pod[%s/%s],refuse to evict: %v", pod.Namespace, pod.Name, err)
+	return util.ARFail(err)
+}
+
+// rejectEviction refuses the eviction and suggests the client to retry later
+func rejectEviction(format string, a ...interface{}) *admission.AdmissionResponse {
+	return &admission.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: fmt.Sprintf(format, a...) + ", retry the eviction later",
+			Reason:  metav1.StatusReasonTooManyRequests,
+			Code:    http.StatusTooManyRequests,
+			Details: &metav1.StatusDetails{
+				RetryAfterSeconds: evictionRetryAfterSeconds,
+			},
+		},
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/manager/member"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	admission "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAdmitEvictPods(t *testing.T) {
+	pdLeader := member.PdPodName(tcName, 0)
+	pdFollower := member.PdPodName(tcName, 2)
+	tikv := member.TikvPodName(tcName, 1)
+
+	newPod := func(name, component string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels: map[string]string{
+					label.NameLabelKey:      "tidb-cluster",
+					label.ManagedByLabelKey: label.TiDBOperator,
+					label.ComponentLabelKey: component,
+					label.InstanceLabelKey:  tcName,
+				},
+			},
+		}
+	}
+	leaderReaction := func(pdClient *pdapi.FakePDClient) {
+		pdClient.AddReaction(pdapi.GetPDLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+			return &pdpb.Member{Name: pdLeader}, nil
+		})
+	}
+	storesReaction := func(leaderCount int) func(pdClient *pdapi.FakePDClient) {
+		return func(pdClient *pdapi.FakePDClient) {
+			pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+				return &pdapi.StoresInfo{
+					Stores: []*pdapi.StoreInfo{
+						{
+							Store: &pdapi.MetaStore{
+								Store:     &metapb.Store{Id: 1, Address: tikv + "." + tcName + "-tikv-peer." + namespace + ".svc:20160"},
+								StateName: v1alpha1.TiKVStateUp,
+							},
+							Status: &pdapi.StoreStatus{LeaderCount: leaderCount},
+						},
+					},
+				}, nil
+			})
+		}
+	}
+
+	tests := []struct {
+		name              string
+		pod               *corev1.Pod
+		failClosed        bool
+		setup             func(pdClient *pdapi.FakePDClient)
+		wantAllowed       bool
+		wantTransferredTo string
+		wantEvictedStore  uint64
+	}{
+		{
+			name:        "pod is not managed by tidb-operator",
+			pod:         &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "foo"}},
+			wantAllowed: true,
+		},
+		{
+			name:              "pd leader",
+			pod:               newPod(pdLeader, label.PDLabelVal),
+			setup:             leaderReaction,
+			wantAllowed:       false,
+			wantTransferredTo: member.PdPodName(tcName, 1),
+		},
+		{
+			name:        "pd follower",
+			pod:         newPod(pdFollower, label.PDLabelVal),
+			setup:       leaderReaction,
+			wantAllowed: true,
+		},
+		{
+			name:        "pd is unreachable and fail open",
+			pod:         newPod(pdLeader, label.PDLabelVal),
+			wantAllowed: true,
+		},
+		{
+			name:        "pd is unreachable and fail closed",
+			pod:         newPod(pdLeader, label.PDLabelVal),
+			failClosed:  true,
+			wantAllowed: false,
+		},
+		{
+			name:             "tikv with region leaders",
+			pod:              newPod(tikv, label.TiKVLabelVal),
+			setup:            storesReaction(10),
+			wantAllowed:      false,
+			wantEvictedStore: 1,
+		},
+		{
+			name:        "tikv without region leaders",
+			pod:         newPod(tikv, label.TiKVLabelVal),
+			setup:       storesReaction(0),
+			wantAllowed: true,
+		},
+		{
+			name:        "tikv and pd is unreachable",
+			pod:         newPod(tikv, label.TiKVLabelVal),
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tc := newTidbClusterForPodAdmissionControl(pdReplicas, tikvReplicas)
+			cli := fake.NewSimpleClientset()
+			cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+			kubeCli := kubefake.NewSimpleClientset()
+			kubeCli.CoreV1().Pods(tt.pod.Namespace).Create(context.TODO(), tt.pod, metav1.CreateOptions{})
+
+			podAdmissionControl := newPodAdmissionControl(nil, kubeCli, cli)
+			podAdmissionControl.evictionConfig.FailOpen = !tt.failClosed
+			pdClient := controller.NewFakePDClient(podAdmissionControl.pdControl.(*pdapi.FakePDControl), tc)
+			var transferredTo string
+			pdClient.AddReaction(pdapi.TransferPDLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+				transferredTo = action.Name
+				return nil, nil
+			})
+			var evictedStore uint64
+			pdClient.AddReaction(pdapi.BeginEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+				evictedStore = action.ID
+				return nil, nil
+			})
+			if tt.setup != nil {
+				tt.setup(pdClient)
+			}
+
+			resp := podAdmissionControl.Validate(&admission.AdmissionRequest{
+				Name:        tt.pod.Name,
+				Namespace:   tt.pod.Namespace,
+				Operation:   admission.Create,
+				SubResource: evictionSubResource,
+				UserInfo: authenticationv1.UserInfo{
+					Username: "system:serviceaccount:kube-system:cluster-autoscaler",
+				},
+			})
+			g.Expect(resp.Allowed).To(Equal(tt.wantAllowed))
+			g.Expect(transferredTo).To(Equal(tt.wantTransferredTo))
+			g.Expect(evictedStore).To(Equal(tt.wantEvictedStore))
+			if tt.wantTransferredTo != "" || tt.wantEvictedStore != 0 {
+				g.Expect(resp.Result.Code).To(Equal(int32(http.StatusTooManyRequests)))
+				g.Expect(resp.Result.Details.RetryAfterSeconds).To(BeNumerically(">", 0))
+			}
+			if tt.wantEvictedStore != 0 {
+				pod, err := kubeCli.CoreV1().Pods(tt.pod.Namespace).Get(context.TODO(), tt.pod.Name, metav1.GetOptions{})
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(pod.Annotations).To(HaveKey(EvictLeaderBeginTime))
+			}
+		})
+	}
+}
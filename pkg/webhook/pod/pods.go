@@ -64,6 +64,8 @@ type PodAdmissionControl struct {
 	tcLister v1alpha1listers.TidbClusterLister
 	// recorder to send event
 	recorder record.EventRecorder
+	// the config of the admission of the pod evictions
+	evictionConfig EvictionConfig
 }
 
 var _ apiserver.ValidatingAdmissionHook = &PodAdmissionControl{}
@@ -82,7 +84,7 @@ var (
 	AstsControllerServiceAccounts string
 )
 
-func NewPodAdmissionControl(extraServiceAccounts []string, resyncDuration time.Duration) *PodAdmissionControl {
+func NewPodAdmissionControl(extraServiceAccounts []string, resyncDuration time.Duration, evictionConfig EvictionConfig) *PodAdmissionControl {
 	serviceAccounts := sets.NewString(stsControllerServiceAccounts)
 	for _, sa := range extraServiceAccounts {
 		serviceAccounts.Insert(sa)
@@ -93,6 +95,7 @@ func NewPodAdmissionControl(extraServiceAccounts []string, resyncDuration time.D
 	return &PodAdmissionControl{
 		serviceAccounts: serviceAccounts,
 		resyncDuration:  resyncDuration,
+		evictionConfig:  evictionConfig,
 	}
 }
 
@@ -158,6 +161,11 @@ func (pc *PodAdmissionControl) Validate(ar *admission.AdmissionRequest) *admissi
 	serviceAccount := ar.UserInfo.Username
 	klog.Infof("receive %s pod[%s/%s] by sa[%s]", operation, namespace, name, serviceAccount)
 
+	// the evictions are checked whoever sends them, e.g. node drains and the cluster autoscaler
+	if operation == admission.Create && ar.SubResource == evictionSubResource {
+		return pc.admitEvictPods(name, namespace)
+	}
+
 	if !pc.serviceAccounts.Has(serviceAccount) {
 		klog.Infof("Request was not sent by known controlled ServiceAccounts, admit to %s pod [%s/%s]", operation, namespace, name)
 		return util.ARSuccess()
@@ -21,20 +21,31 @@ import (
 
 	"github.com/openshift/generic-admission-server/pkg/apiserver"
 	asapps "github.com/pingcap/advanced-statefulset/client/apis/apps/v1"
+	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
+	asclientset "github.com/pingcap/advanced-statefulset/client/client/clientset/versioned"
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/webhook/util"
 	admission "k8s.io/api/admission/v1beta1"
 	apps "k8s.io/api/apps/v1"
+	autoscaling "k8s.io/api/autoscaling/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog"
 )
 
+const (
+	scaleSubResource = "scale"
+)
+
 var (
 	deserializer runtime.Decoder = util.Codecs.UniversalDeserializer()
 )
@@ -44,6 +55,12 @@ type StatefulSetAdmissionControl struct {
 	initialized bool
 	// operator client interface
 	operatorCli versioned.Interface
+	// kubernetes client interface, the statefulsets are of AdvancedStatefulSet if it's enabled
+	kubeCli kubernetes.Interface
+	// the clients of the builtin and the advanced statefulsets, the statefulset of the scale subresource
+	// is got from the group of the request
+	builtinKubeCli kubernetes.Interface
+	asCli          asclientset.Interface
 }
 
 var _ apiserver.ValidatingAdmissionHook = &StatefulSetAdmissionControl{}
@@ -81,26 +98,125 @@ func (sc *StatefulSetAdmissionControl) Validate(ar *admission.AdmissionRequest)
 
 	klog.Infof("admit %s [%s/%s]", setResource, namespace, name)
 
-	stsObjectMeta, stsPartition, err := getStsAttributes(ar.Object.Raw)
+	if ar.Operation != admission.Update {
+		return util.ARSuccess()
+	}
+
+	if ar.SubResource == scaleSubResource {
+		return sc.admitScale(ar)
+	}
+
+	set, err := decodeStatefulSet(ar.Object.Raw)
 	if err != nil {
 		err = fmt.Errorf("statefulset %s/%s, decode request failed, err: %v", namespace, name, err)
 		klog.Error(err)
 		return util.ARFail(err)
 	}
 
-	l := label.Label(stsObjectMeta.Labels)
+	tc, l, resp := sc.getOwnerTidbCluster(namespace, name, set)
+	if resp != nil {
+		return resp
+	}
+
+	if resp := admitPartitionAnnotation(namespace, name, tc, l, set); resp != nil {
+		return resp
+	}
+
+	if len(ar.OldObject.Raw) == 0 {
+		return util.ARSuccess()
+	}
+	oldSet, err := decodeStatefulSet(ar.OldObject.Raw)
+	if err != nil {
+		err = fmt.Errorf("statefulset %s/%s, decode old object failed, err: %v", namespace, name, err)
+		klog.Error(err)
+		return util.ARFail(err)
+	}
+
+	if err := checkReplicas(namespace, name, tc, l, getReplicas(oldSet), getReplicas(set)); err != nil {
+		klog.Infof("deny statefulset %s/%s: %v", namespace, name, err)
+		return util.ARFail(err)
+	}
+	if err := sc.checkPartition(namespace, name, tc, l, oldSet, set); err != nil {
+		klog.Infof("deny statefulset %s/%s: %v", namespace, name, err)
+		return util.ARFail(err)
+	}
+	return util.ARSuccess()
+}
+
+// admitScale admits the update of the scale subresource of the statefulset, e.g. `kubectl scale sts`. As the
+// scale subresource can't be selected by the labels, the statefulsets not found or not of tidb and tikv are
+// admitted before any other check.
+func (sc *StatefulSetAdmissionControl) admitScale(ar *admission.AdmissionRequest) *admission.AdmissionResponse {
+	name := ar.Name
+	namespace := ar.Namespace
+
+	set, err := sc.getStatefulSet(ar.Resource.Group, namespace, name)
+	if errors.IsNotFound(err) {
+		return util.ARSuccess()
+	}
+	if err != nil {
+		err = fmt.Errorf("get statefulset %s/%s failed, err: %v", namespace, name, err)
+		klog.Error(err)
+		return util.ARFail(err)
+	}
+
+	tc, l, resp := sc.getOwnerTidbCluster(namespace, name, set)
+	if resp != nil {
+		return resp
+	}
+
+	scale := autoscaling.Scale{}
+	if _, _, err := deserializer.Decode(ar.Object.Raw, nil, &scale); err != nil {
+		err = fmt.Errorf("statefulset %s/%s, decode scale failed, err: %v", namespace, name, err)
+		klog.Error(err)
+		return util.ARFail(err)
+	}
+	oldScale := autoscaling.Scale{}
+	if _, _, err := deserializer.Decode(ar.OldObject.Raw, nil, &oldScale); err != nil {
+		err = fmt.Errorf("statefulset %s/%s, decode old scale failed, err: %v", namespace, name, err)
+		klog.Error(err)
+		return util.ARFail(err)
+	}
+	if err := checkReplicas(namespace, name, tc, l, oldScale.Spec.Replicas, scale.Spec.Replicas); err != nil {
+		klog.Infof("deny scaling statefulset %s/%s: %v", namespace, name, err)
+		return util.ARFail(err)
+	}
+	return util.ARSuccess()
+}
+
+// getStatefulSet gets the statefulset of the group, the advanced statefulset is converted to the builtin one
+func (sc *StatefulSetAdmissionControl) getStatefulSet(group, namespace, name string) (*apps.StatefulSet, error) {
+	if group != asapps.GroupName {
+		return sc.builtinKubeCli.AppsV1().StatefulSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	}
+	set, err := sc.asCli.AppsV1().StatefulSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToBuiltinStatefulSet(set)
+}
+
+// getOwnerTidbCluster returns the TidbCluster owning the tidb or tikv statefulset, a non-nil response is returned
+// if the statefulset should not be checked further.
+func (sc *StatefulSetAdmissionControl) getOwnerTidbCluster(namespace, name string, set *apps.StatefulSet) (*v1alpha1.TidbCluster, label.Label, *admission.AdmissionResponse) {
+	l := label.Label(set.Labels)
 
 	if !(l.IsTiDB() || l.IsTiKV()) {
 		// If it is not statefulset of tikv and tidb, return quickly.
-		return util.ARSuccess()
+		return nil, l, util.ARSuccess()
 	}
 
-	controllerRef := metav1.GetControllerOf(stsObjectMeta)
+	controllerRef := metav1.GetControllerOf(set)
 	if controllerRef == nil || controllerRef.Kind != controller.ControllerKind.Kind {
 		// In this case, we can't tell if this statefulset is controlled by tidb-operator,
 		// so we don't block this statefulset upgrade, return directly.
 		klog.Warningf("statefulset %s/%s has tidb or tikv component label but doesn't have owner reference or the owner reference is not TidbCluster", namespace, name)
-		return util.ARSuccess()
+		return nil, l, util.ARSuccess()
+	}
+
+	if set.Annotations[label.AnnBypassStsAdmissionKey] == label.AnnBypassStsAdmissionVal {
+		klog.Warningf("statefulset %s/%s has annotation %s, skip the admission checks", namespace, name, label.AnnBypassStsAdmissionKey)
+		return nil, l, util.ARSuccess()
 	}
 
 	tcName := controllerRef.Name
@@ -108,9 +224,13 @@ func (sc *StatefulSetAdmissionControl) Validate(ar *admission.AdmissionRequest)
 	if err != nil {
 		err := fmt.Errorf("get tidbcluster %s/%s failed, statefulset %s, err %v", namespace, tcName, name, err)
 		klog.Error(err.Error())
-		return util.ARFail(err)
+		return nil, l, util.ARFail(err)
 	}
+	return tc, l, nil
+}
 
+// admitPartitionAnnotation denies the partition lower than the one protected by the partition annotation of the TidbCluster
+func admitPartitionAnnotation(namespace, name string, tc *v1alpha1.TidbCluster, l label.Label, set *apps.StatefulSet) *admission.AdmissionResponse {
 	annKey := label.AnnTiDBPartition
 	if l.IsTiKV() {
 		annKey = label.AnnTiKVPartition
@@ -118,7 +238,7 @@ func (sc *StatefulSetAdmissionControl) Validate(ar *admission.AdmissionRequest)
 	partitionStr := tc.Annotations[annKey]
 
 	if len(partitionStr) == 0 {
-		return util.ARSuccess()
+		return nil
 	}
 
 	partition, err := strconv.ParseInt(partitionStr, 10, 32)
@@ -128,15 +248,97 @@ func (sc *StatefulSetAdmissionControl) Validate(ar *admission.AdmissionRequest)
 		return util.ARFail(err)
 	}
 
+	stsPartition := getPartition(set)
 	if stsPartition != nil {
 		// only [partition from tc, INT32_MAX] are allowed
 		if *stsPartition < int32(partition) {
 			klog.Infof("statefulset %s/%s has been protected by partition annotation %q", namespace, name, partitionStr)
-			return util.ARFail(fmt.Errorf("protected by partition annotation (%s = %s) on the tidb cluster %s/%s", annKey, partitionStr, namespace, tcName))
+			return util.ARFail(fmt.Errorf("protected by partition annotation (%s = %s) on the tidb cluster %s/%s", annKey, partitionStr, namespace, tc.Name))
 		}
 		klog.Infof("admit statefulset %s/%s update partition to %d, protect partition is %d", namespace, name, *stsPartition, partition)
 	}
-	return util.ARSuccess()
+	return nil
+}
+
+// checkReplicas denies the replicas decrease which is not initiated by the operator, the operator only decreases
+// the replicas of the statefulset after the replicas in the spec of the TidbCluster is decreased, the store offline
+// flow of tikv is bypassed otherwise.
+func checkReplicas(namespace, name string, tc *v1alpha1.TidbCluster, l label.Label, oldReplicas, replicas int32) error {
+	if replicas >= oldReplicas {
+		return nil
+	}
+
+	component := v1alpha1.TiDBMemberType
+	desiredReplicas := tc.TiDBStsDesiredReplicas()
+	if l.IsTiKV() {
+		component = v1alpha1.TiKVMemberType
+		desiredReplicas = tc.TiKVStsDesiredReplicas()
+	}
+	if replicas < desiredReplicas {
+		return fmt.Errorf("replicas of statefulset %s/%s can't be decreased to %d, it's less than the desired replicas %d of %s in the tidb cluster %s/%s, please scale in by changing spec.%s.replicas of the tidb cluster, or set annotation %s=%s on the statefulset in emergencies",
+			namespace, name, replicas, desiredReplicas, component, tc.Namespace, tc.Name, component, label.AnnBypassStsAdmissionKey, label.AnnBypassStsAdmissionVal)
+	}
+	return nil
+}
+
+// checkPartition denies the partition decrease which skips ahead of the ordinal the upgrader intends to upgrade next.
+func (sc *StatefulSetAdmissionControl) checkPartition(namespace, name string, tc *v1alpha1.TidbCluster, l label.Label, oldSet, set *apps.StatefulSet) error {
+	oldPartition := getPartition(oldSet)
+	partition := getPartition(set)
+	if oldPartition == nil || partition == nil || *partition >= *oldPartition {
+		return nil
+	}
+
+	ordinal, err := sc.nextUpgradeOrdinal(namespace, name, tc, l, oldSet, !apiequality.Semantic.DeepEqual(oldSet.Spec.Template, set.Spec.Template))
+	if err != nil {
+		return err
+	}
+	if *partition < ordinal {
+		return fmt.Errorf("partition of statefulset %s/%s can't be decreased to %d, the next ordinal to upgrade is %d, please let the operator upgrade the pods one by one, or set annotation %s=%s on the statefulset in emergencies",
+			namespace, name, *partition, ordinal, label.AnnBypassStsAdmissionKey, label.AnnBypassStsAdmissionVal)
+	}
+	return nil
+}
+
+// nextUpgradeOrdinal returns the ordinal the upgrader intends to upgrade next. Like the upgrader, it walks
+// the ordinals below the current partition in descending order and skips the pods upgraded already, and the
+// tikv pods without store. If the template is changed, no pod is upgraded to the new revision yet.
+func (sc *StatefulSetAdmissionControl) nextUpgradeOrdinal(namespace, name string, tc *v1alpha1.TidbCluster, l label.Label, set *apps.StatefulSet, templateChanged bool) (int32, error) {
+	partition := *getPartition(set)
+	podOrdinals := helper.GetPodOrdinals(getReplicas(set), set).List()
+	for i := len(podOrdinals) - 1; i >= 0; i-- {
+		ordinal := podOrdinals[i]
+		if ordinal >= partition {
+			continue
+		}
+		podName := fmt.Sprintf("%s-%d", name, ordinal)
+		if l.IsTiKV() && !hasTiKVStore(tc, podName) {
+			continue
+		}
+		if templateChanged {
+			return ordinal, nil
+		}
+		pod, err := sc.kubeCli.CoreV1().Pods(namespace).Get(context.TODO(), podName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return ordinal, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("get pod %s/%s failed, err: %v", namespace, podName, err)
+		}
+		if pod.Labels[apps.ControllerRevisionHashLabelKey] != set.Status.UpdateRevision {
+			return ordinal, nil
+		}
+	}
+	return 0, nil
+}
+
+func hasTiKVStore(tc *v1alpha1.TidbCluster, podName string) bool {
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.PodName == podName {
+			return true
+		}
+	}
+	return false
 }
 
 // Initialize implements AdmissionHook.Initialize interface. It's is called as
@@ -150,29 +352,57 @@ func (a *StatefulSetAdmissionControl) Initialize(cfg *rest.Config, stopCh <-chan
 		return err
 	}
 
+	var kubeCli kubernetes.Interface
+	kubeCli, err = kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	asCli, err := asclientset.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	a.builtinKubeCli = kubeCli
+	a.asCli = asCli
+	if features.DefaultFeatureGate.Enabled(features.AdvancedStatefulSet) {
+		// If AdvancedStatefulSet is enabled, we hijack the Kubernetes client to use
+		// AdvancedStatefulSet.
+		kubeCli = helper.NewHijackClient(kubeCli, asCli)
+	}
+
 	a.operatorCli = cli
+	a.kubeCli = kubeCli
 
 	a.initialized = true
 	return nil
 }
 
-func getStsAttributes(data []byte) (*metav1.ObjectMeta, *int32, error) {
+// decodeStatefulSet decodes the statefulset, the advanced statefulset is converted to the builtin one
+func decodeStatefulSet(data []byte) (*apps.StatefulSet, error) {
 	if !features.DefaultFeatureGate.Enabled(features.AdvancedStatefulSet) {
 		set := apps.StatefulSet{}
 		if _, _, err := deserializer.Decode(data, nil, &set); err != nil {
-			return nil, nil, err
-		}
-		if set.Spec.UpdateStrategy.RollingUpdate != nil {
-			return &(set.ObjectMeta), set.Spec.UpdateStrategy.RollingUpdate.Partition, nil
+			return nil, err
 		}
-		return &(set.ObjectMeta), nil, nil
+		return &set, nil
 	}
 	set := asapps.StatefulSet{}
 	if _, _, err := deserializer.Decode(data, nil, &set); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	return helper.ToBuiltinStatefulSet(&set)
+}
+
+// getReplicas returns the replicas of the statefulset, it's defaulted to 1 if not set
+func getReplicas(set *apps.StatefulSet) int32 {
+	if set.Spec.Replicas != nil {
+		return *set.Spec.Replicas
+	}
+	return 1
+}
+
+func getPartition(set *apps.StatefulSet) *int32 {
 	if set.Spec.UpdateStrategy.RollingUpdate != nil {
-		return &(set.ObjectMeta), set.Spec.UpdateStrategy.RollingUpdate.Partition, nil
+		return set.Spec.UpdateStrategy.RollingUpdate.Partition
 	}
-	return &(set.ObjectMeta), nil, nil
+	return nil
 }
@@ -16,32 +16,120 @@ package statefulset
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"reflect"
 	"testing"
 
 	asapps "github.com/pingcap/advanced-statefulset/client/apis/apps/v1"
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
+	asfake "github.com/pingcap/advanced-statefulset/client/client/clientset/versioned/fake"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
 	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/webhook/util"
 	admission "k8s.io/api/admission/v1beta1"
 	apps "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/utils/pointer"
 )
 
 type testcase struct {
 	name        string
 	sts         *apps.StatefulSet
+	oldSts      *apps.StatefulSet
+	scale       *autoscalingv1.Scale
+	oldScale    *autoscalingv1.Scale
 	tc          *v1alpha1.TidbCluster
+	pods        []*v1.Pod
 	operation   admission.Operation
 	wantAllowed bool
 }
 
+func newTiKVStatefulSet(replicas, partition int32, annotations map[string]string) *apps.StatefulSet {
+	return &apps.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "StatefulSet",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: v1.NamespaceDefault,
+			Labels: map[string]string{
+				"app.kubernetes.io/component": "tikv",
+			},
+			Annotations:     annotations,
+			OwnerReferences: validOwnerRefs,
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas: pointer.Int32Ptr(replicas),
+			UpdateStrategy: apps.StatefulSetUpdateStrategy{
+				Type: apps.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{
+					Partition: pointer.Int32Ptr(partition),
+				},
+			},
+		},
+		Status: apps.StatefulSetStatus{
+			CurrentRevision: "foo-1",
+			UpdateRevision:  "foo-2",
+		},
+	}
+}
+
+func newTiKVTidbCluster(replicas int32) *v1alpha1.TidbCluster {
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ownerTCName,
+			Namespace: v1.NamespaceDefault,
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			TiKV: &v1alpha1.TiKVSpec{
+				Replicas: replicas,
+			},
+		},
+	}
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
+	for i := int32(0); i < 3; i++ {
+		id := fmt.Sprintf("%d", i+1)
+		tc.Status.TiKV.Stores[id] = v1alpha1.TiKVStore{ID: id, PodName: fmt.Sprintf("foo-%d", i)}
+	}
+	return tc
+}
+
+func newPod(ordinal int32, revision string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("foo-%d", ordinal),
+			Namespace: v1.NamespaceDefault,
+			Labels: map[string]string{
+				apps.ControllerRevisionHashLabelKey: revision,
+			},
+		},
+	}
+}
+
+func newScale(replicas int32) *autoscalingv1.Scale {
+	return &autoscalingv1.Scale{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Scale",
+			APIVersion: "autoscaling/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: v1.NamespaceDefault,
+		},
+		Spec: autoscalingv1.ScaleSpec{
+			Replicas: replicas,
+		},
+	}
+}
+
 var (
 	ownerTCName    = "foo"
 	validOwnerRefs = []metav1.OwnerReference{
@@ -221,6 +309,133 @@ var (
 			},
 			wantAllowed: false,
 		},
+		{
+			name:        "replicas decrease initiated by the operator",
+			operation:   admission.Update,
+			oldSts:      newTiKVStatefulSet(3, 3, nil),
+			sts:         newTiKVStatefulSet(2, 3, nil),
+			tc:          newTiKVTidbCluster(2),
+			wantAllowed: true,
+		},
+		{
+			name:        "replicas decrease not initiated by the operator",
+			operation:   admission.Update,
+			oldSts:      newTiKVStatefulSet(3, 3, nil),
+			sts:         newTiKVStatefulSet(2, 3, nil),
+			tc:          newTiKVTidbCluster(3),
+			wantAllowed: false,
+		},
+		{
+			name:      "replicas decrease not initiated by the operator with bypass annotation",
+			operation: admission.Update,
+			oldSts:    newTiKVStatefulSet(3, 3, nil),
+			sts: newTiKVStatefulSet(2, 3, map[string]string{
+				"tidb.pingcap.com/bypass-sts-admission": "true",
+			}),
+			tc:          newTiKVTidbCluster(3),
+			wantAllowed: true,
+		},
+		{
+			name:        "replicas increase",
+			operation:   admission.Update,
+			oldSts:      newTiKVStatefulSet(3, 3, nil),
+			sts:         newTiKVStatefulSet(4, 3, nil),
+			tc:          newTiKVTidbCluster(3),
+			wantAllowed: true,
+		},
+		{
+			name:        "partition decrease to the next ordinal",
+			operation:   admission.Update,
+			oldSts:      newTiKVStatefulSet(3, 3, nil),
+			sts:         newTiKVStatefulSet(3, 2, nil),
+			tc:          newTiKVTidbCluster(3),
+			pods:        []*v1.Pod{newPod(0, "foo-1"), newPod(1, "foo-1"), newPod(2, "foo-1")},
+			wantAllowed: true,
+		},
+		{
+			name:        "partition decrease skipping ahead of the next ordinal",
+			operation:   admission.Update,
+			oldSts:      newTiKVStatefulSet(3, 3, nil),
+			sts:         newTiKVStatefulSet(3, 0, nil),
+			tc:          newTiKVTidbCluster(3),
+			pods:        []*v1.Pod{newPod(0, "foo-1"), newPod(1, "foo-1"), newPod(2, "foo-1")},
+			wantAllowed: false,
+		},
+		{
+			name:        "partition decrease skipping the upgraded pods",
+			operation:   admission.Update,
+			oldSts:      newTiKVStatefulSet(3, 3, nil),
+			sts:         newTiKVStatefulSet(3, 1, nil),
+			tc:          newTiKVTidbCluster(3),
+			pods:        []*v1.Pod{newPod(0, "foo-1"), newPod(1, "foo-1"), newPod(2, "foo-2")},
+			wantAllowed: true,
+		},
+		{
+			name:      "partition decrease skipping ahead of the next ordinal with bypass annotation",
+			operation: admission.Update,
+			oldSts:    newTiKVStatefulSet(3, 3, nil),
+			sts: newTiKVStatefulSet(3, 0, map[string]string{
+				"tidb.pingcap.com/bypass-sts-admission": "true",
+			}),
+			tc:          newTiKVTidbCluster(3),
+			pods:        []*v1.Pod{newPod(0, "foo-1"), newPod(1, "foo-1"), newPod(2, "foo-1")},
+			wantAllowed: true,
+		},
+		{
+			name:        "scale subresource decrease initiated by the operator",
+			operation:   admission.Update,
+			oldSts:      newTiKVStatefulSet(3, 3, nil),
+			oldScale:    newScale(3),
+			scale:       newScale(2),
+			tc:          newTiKVTidbCluster(2),
+			wantAllowed: true,
+		},
+		{
+			name:        "scale subresource decrease not initiated by the operator",
+			operation:   admission.Update,
+			oldSts:      newTiKVStatefulSet(3, 3, nil),
+			oldScale:    newScale(3),
+			scale:       newScale(2),
+			tc:          newTiKVTidbCluster(3),
+			wantAllowed: false,
+		},
+		{
+			name:      "scale subresource decrease not initiated by the operator with bypass annotation",
+			operation: admission.Update,
+			oldSts: newTiKVStatefulSet(3, 3, map[string]string{
+				"tidb.pingcap.com/bypass-sts-admission": "true",
+			}),
+			oldScale:    newScale(3),
+			scale:       newScale(2),
+			tc:          newTiKVTidbCluster(3),
+			wantAllowed: true,
+		},
+		{
+			name:      "scale subresource decrease of statefulset not found",
+			operation: admission.Update,
+			oldSts: func() *apps.StatefulSet {
+				set := newTiKVStatefulSet(3, 3, nil)
+				set.Name = "bar"
+				return set
+			}(),
+			oldScale:    newScale(3),
+			scale:       newScale(2),
+			tc:          newTiKVTidbCluster(3),
+			wantAllowed: true,
+		},
+		{
+			name:      "scale subresource decrease of statefulset not of tidb and tikv",
+			operation: admission.Update,
+			oldSts: func() *apps.StatefulSet {
+				set := newTiKVStatefulSet(3, 3, nil)
+				set.Labels = nil
+				return set
+			}(),
+			oldScale:    newScale(3),
+			scale:       newScale(2),
+			tc:          newTiKVTidbCluster(3),
+			wantAllowed: true,
+		},
 	}
 )
 
@@ -229,40 +444,75 @@ func runTest(t *testing.T, tt testcase, asts bool) {
 	if !ok {
 		t.Fatalf("unable to locate encoder -- %q is not a supported media type", runtime.ContentTypeJSON)
 	}
+	encode := func(obj runtime.Object, gv schema.GroupVersion) runtime.RawExtension {
+		buf := bytes.Buffer{}
+		encoder := util.Codecs.EncoderForVersion(jsonInfo.Serializer, gv)
+		if err := encoder.Encode(obj, &buf); err != nil {
+			t.Fatal(err)
+		}
+		return runtime.RawExtension{
+			Raw: buf.Bytes(),
+		}
+	}
+	encodeSts := func(set *apps.StatefulSet) runtime.RawExtension {
+		if asts {
+			sts, err := helper.FromBuiltinStatefulSet(set)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return encode(sts, asapps.SchemeGroupVersion)
+		}
+		return encode(set, apps.SchemeGroupVersion)
+	}
 
 	cli := fake.NewSimpleClientset()
+	builtinKubeCli := kubefake.NewSimpleClientset()
+	asCli := asfake.NewSimpleClientset()
+	var kubeCli kubernetes.Interface = builtinKubeCli
+	if asts {
+		kubeCli = helper.NewHijackClient(kubeCli, asCli)
+	}
 	ac := NewStatefulSetAdmissionControl()
 	ac.initialized = true
 	ac.operatorCli = cli
+	ac.kubeCli = kubeCli
+	ac.builtinKubeCli = builtinKubeCli
+	ac.asCli = asCli
 	ar := &admission.AdmissionRequest{
 		Name:      "foo",
 		Namespace: v1.NamespaceDefault,
 		Operation: tt.operation,
+		Resource:  metav1.GroupVersionResource{Group: apps.GroupName, Version: "v1", Resource: "statefulsets"},
+	}
+	if asts {
+		ar.Resource.Group = asapps.GroupName
 	}
 	if tt.sts != nil {
-		buf := bytes.Buffer{}
-		if asts {
-			encoder := util.Codecs.EncoderForVersion(jsonInfo.Serializer, asapps.SchemeGroupVersion)
-			sts, err := helper.FromBuiltinStatefulSet(tt.sts)
-			if err != nil {
-				t.Fatal(err)
-			}
-			if err := encoder.Encode(sts, &buf); err != nil {
+		ar.Object = encodeSts(tt.sts)
+	}
+	if tt.oldSts != nil {
+		if tt.scale != nil {
+			// the statefulset is got from the api server when its scale subresource is updated
+			if _, err := kubeCli.AppsV1().StatefulSets(tt.oldSts.Namespace).Create(context.TODO(), tt.oldSts, metav1.CreateOptions{}); err != nil {
 				t.Fatal(err)
 			}
 		} else {
-			encoder := util.Codecs.EncoderForVersion(jsonInfo.Serializer, apps.SchemeGroupVersion)
-			if err := encoder.Encode(tt.sts, &buf); err != nil {
-				t.Fatal(err)
-			}
-		}
-		ar.Object = runtime.RawExtension{
-			Raw: buf.Bytes(),
+			ar.OldObject = encodeSts(tt.oldSts)
 		}
 	}
+	if tt.scale != nil {
+		ar.SubResource = "scale"
+		ar.Object = encode(tt.scale, autoscalingv1.SchemeGroupVersion)
+		ar.OldObject = encode(tt.oldScale, autoscalingv1.SchemeGroupVersion)
+	}
 	if tt.tc != nil {
 		cli.PingcapV1alpha1().TidbClusters(tt.tc.Namespace).Create(context.TODO(), tt.tc, metav1.CreateOptions{})
 	}
+	for _, pod := range tt.pods {
+		if _, err := kubeCli.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
 	resp := ac.Validate(ar)
 	if resp.Allowed != tt.wantAllowed {
 		t.Errorf("want allowed %v, got %v", tt.wantAllowed, resp.Allowed)
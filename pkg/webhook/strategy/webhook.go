@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 
 	"github.com/openshift/generic-admission-server/pkg/apiserver"
+	"github.com/pingcap/tidb-operator/pkg/registry"
 	"github.com/pingcap/tidb-operator/pkg/webhook/util"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -84,7 +85,11 @@ func (w *StrategyAdmissionHook) Validate(ar *admissionv1beta1.AdmissionRequest)
 	if len(allErr) > 0 {
 		return util.ARFail(allErr.ToAggregate())
 	}
-	return util.ARSuccess()
+	resp := util.ARSuccess()
+	if ws, ok := s.(registry.WarningStrategy); ok {
+		resp.Warnings = ws.Warnings(context.TODO(), obj)
+	}
+	return resp
 }
 
 func (w *StrategyAdmissionHook) Admit(ar *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
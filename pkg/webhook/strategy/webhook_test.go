@@ -116,6 +116,7 @@ func TestStrategyAdmissionHook_Validate(t *testing.T) {
 
 		validateError       error
 		validateUpdateError error
+		warnings            []string
 
 		expectedValidateTimes          int
 		expectedValidateForUpdateTimes int
@@ -160,6 +161,13 @@ func TestStrategyAdmissionHook_Validate(t *testing.T) {
 			validateUpdateError:            fmt.Errorf("invalid object"),
 			expectedValidateTimes:          0,
 			expectedValidateForUpdateTimes: 1,
+		}, {
+			name:                           "Validate creating with warnings",
+			operation:                      admissionv1beta1.Create,
+			apiObj:                         &v1alpha1.TidbCluster{},
+			warnings:                       []string{"tikv: unknown config key \"foo\""},
+			expectedValidateTimes:          1,
+			expectedValidateForUpdateTimes: 0,
 		},
 	}
 
@@ -197,6 +205,7 @@ func TestStrategyAdmissionHook_Validate(t *testing.T) {
 		if tt.validateUpdateError != nil {
 			s.validateUpdateTracker.SetError(tt.validateUpdateError)
 		}
+		s.warnings = tt.warnings
 
 		resp := w.Validate(&ar)
 		if tt.validateError != nil && tt.operation == admissionv1beta1.Create {
@@ -205,6 +214,7 @@ func TestStrategyAdmissionHook_Validate(t *testing.T) {
 			g.Expect(resp.Allowed).To(BeFalse())
 		} else {
 			g.Expect(resp.Allowed).To(BeTrue())
+			g.Expect(resp.Warnings).To(Equal(tt.warnings))
 		}
 		g.Expect(s.validateTracker.GetRequests()).To(Equal(tt.expectedValidateTimes))
 		g.Expect(s.validateUpdateTracker.GetRequests()).To(Equal(tt.expectedValidateForUpdateTimes))
@@ -220,6 +230,7 @@ type FakeStrategy struct {
 	prepareForUpdateTracker controller.RequestTracker
 	validateTracker         controller.RequestTracker
 	validateUpdateTracker   controller.RequestTracker
+	warnings                []string
 }
 
 func (s *FakeStrategy) NewObject() runtime.Object {
@@ -252,6 +263,10 @@ func (s *FakeStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Obje
 	return allErrs
 }
 
+func (s *FakeStrategy) Warnings(ctx context.Context, obj runtime.Object) []string {
+	return s.warnings
+}
+
 func TestValidatingResource(t *testing.T) {
 	r := NewRegistry()
 	w := NewStrategyAdmissionHook(&r)
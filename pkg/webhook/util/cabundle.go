@@ -0,0 +1,45 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
+	aggregatorclientset "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
+)
+
+// UpdateAPIServiceCABundle sets the caBundle of the APIService through which
+// the kube-apiserver reaches the admission webhooks.
+func UpdateAPIServiceCABundle(cli aggregatorclientset.Interface, name string, ca []byte) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		apiService, err := cli.ApiregistrationV1().APIServices().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(apiService.Spec.CABundle, ca) && !apiService.Spec.InsecureSkipTLSVerify {
+			return nil
+		}
+		apiService.Spec.CABundle = ca
+		apiService.Spec.InsecureSkipTLSVerify = false
+		if _, err := cli.ApiregistrationV1().APIServices().Update(context.TODO(), apiService, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		klog.Infof("caBundle of APIService %s is updated", name)
+		return nil
+	})
+}